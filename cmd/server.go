@@ -18,8 +18,10 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
+	version "github.com/hashicorp/go-version"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/moby/patternmatcher"
 	"github.com/pkg/errors"
@@ -27,7 +29,11 @@ import (
 	"github.com/spf13/viper"
 
 	"github.com/runatlantis/atlantis/server"
+	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketcloud"
+	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketserver"
 	"github.com/runatlantis/atlantis/server/logging"
 )
 
@@ -54,8 +60,10 @@ const (
 	ADTokenFlag                      = "azuredevops-token" // nolint: gosec
 	ADUserFlag                       = "azuredevops-user"
 	ADHostnameFlag                   = "azuredevops-hostname"
+	ADThreadCommentsFlag             = "azuredevops-thread-comments"
 	AllowCommandsFlag                = "allow-commands"
 	AllowForkPRsFlag                 = "allow-fork-prs"
+	AsyncApplyFlag                   = "async-apply"
 	AtlantisURLFlag                  = "atlantis-url"
 	AutoDiscoverModeFlag             = "autodiscover-mode"
 	AutomergeFlag                    = "automerge"
@@ -66,6 +74,8 @@ const (
 	AutoplanFileListFlag             = "autoplan-file-list"
 	BitbucketApiUserFlag             = "bitbucket-api-user"
 	BitbucketBaseURLFlag             = "bitbucket-base-url"
+	BitbucketDraftDetectionFlag      = "bitbucket-draft-detection"
+	BitbucketDraftTitlePrefixesFlag  = "bitbucket-draft-title-prefixes"
 	BitbucketTokenFlag               = "bitbucket-token"
 	BitbucketUserFlag                = "bitbucket-user"
 	BitbucketWebhookSecretFlag       = "bitbucket-webhook-secret"
@@ -75,10 +85,13 @@ const (
 	DataDirFlag                      = "data-dir"
 	DefaultTFDistributionFlag        = "default-tf-distribution"
 	DefaultTFVersionFlag             = "default-tf-version"
+	DefaultTFVersionConstraintFlag   = "default-tf-version-constraint"
 	DisableApplyAllFlag              = "disable-apply-all"
 	DisableAutoplanFlag              = "disable-autoplan"
 	DisableAutoplanLabelFlag         = "disable-autoplan-label"
 	DisableMarkdownFoldingFlag       = "disable-markdown-folding"
+	DisablePolicyCheckCacheFlag      = "disable-policy-check-cache"
+	DisableProjectStatusesFlag       = "disable-project-statuses"
 	DisableRepoLockingFlag           = "disable-repo-locking"
 	DisableGlobalApplyLockFlag       = "disable-global-apply-lock"
 	DisableUnlockLabelFlag           = "disable-unlock-label"
@@ -91,6 +104,7 @@ const (
 	ExecutableName                   = "executable-name"
 	FailOnPreWorkflowHookError       = "fail-on-pre-workflow-hook-error"
 	HideUnchangedPlanComments        = "hide-unchanged-plan-comments"
+	GHCheckRunsFlag                  = "gh-check-runs"
 	GHHostnameFlag                   = "gh-hostname"
 	GHTeamAllowlistFlag              = "gh-team-allowlist"
 	GHTokenFlag                      = "gh-token"
@@ -100,8 +114,10 @@ const (
 	GHAppKeyFlag                     = "gh-app-key"
 	GHAppKeyFileFlag                 = "gh-app-key-file"
 	GHAppSlugFlag                    = "gh-app-slug"
+	GHAppTokenRefreshLeadTimeFlag    = "gh-app-token-refresh-lead-time" // nolint: gosec
 	GHAppInstallationIDFlag          = "gh-app-installation-id"
 	GHOrganizationFlag               = "gh-org"
+	GHRateLimitMaxWaitFlag           = "gh-rate-limit-max-wait"
 	GHWebhookSecretFlag              = "gh-webhook-secret"               // nolint: gosec
 	GHAllowMergeableBypassApply      = "gh-allow-mergeable-bypass-apply" // nolint: gosec
 	GiteaBaseURLFlag                 = "gitea-base-url"
@@ -115,16 +131,28 @@ const (
 	GitlabUserFlag                   = "gitlab-user"
 	GitlabWebhookSecretFlag          = "gitlab-webhook-secret" // nolint: gosec
 	GitlabStatusRetryEnabledFlag     = "gitlab-status-retry-enabled"
+	GitlabExternalStatusChecksFlag   = "gitlab-external-status-checks"
 	IncludeGitUntrackedFiles         = "include-git-untracked-files"
 	APISecretFlag                    = "api-secret"
 	HidePrevPlanComments             = "hide-prev-plan-comments"
 	QuietPolicyChecks                = "quiet-policy-checks"
+	LockGranularityFlag              = "lock-granularity"
 	LockingDBType                    = "locking-db-type"
+	LockTTLFlag                      = "lock-ttl"
+	LogFormatFlag                    = "log-format"
 	LogLevelFlag                     = "log-level"
 	MarkdownTemplateOverridesDirFlag = "markdown-template-overrides-dir"
+	MaxCommentOutputSizeFlag         = "max-comment-output-size"
 	MaxCommentsPerCommand            = "max-comments-per-command"
+	NoOpApplyFlag                    = "no-op-apply"
 	ParallelPoolSize                 = "parallel-pool-size"
+	ParallelPlanLimitFlag            = "parallel-plan-limit"
 	PendingApplyStatusFlag           = "pending-apply-status"
+	PlanJSONStatsFlag                = "plan-json-stats"
+	PlanReviewCommentsFlag           = "plan-review-comments"
+	PlanSummaryCommentFlag           = "plan-summary-comment"
+	ProjectOrderFlag                 = "project-order"
+	PullDescriptionPlanLinksFlag     = "pull-description-plan-links"
 	StatsNamespace                   = "stats-namespace"
 	AllowDraftPRs                    = "allow-draft-prs"
 	PortFlag                         = "port"
@@ -136,6 +164,7 @@ const (
 	RedisInsecureSkipVerify          = "redis-insecure-skip-verify"
 	RepoConfigFlag                   = "repo-config"
 	RepoConfigJSONFlag               = "repo-config-json"
+	RepoConfigEnvAllowlistFlag       = "repo-config-env-allowlist"
 	RepoAllowlistFlag                = "repo-allowlist"
 	SilenceNoProjectsFlag            = "silence-no-projects"
 	SilenceForkPRErrorsFlag          = "silence-fork-pr-errors"
@@ -144,9 +173,12 @@ const (
 	SilenceAllowlistErrorsFlag       = "silence-allowlist-errors"
 	SkipCloneNoChanges               = "skip-clone-no-changes"
 	SlackTokenFlag                   = "slack-token"
+	SlackWebhookURLFlag              = "slack-webhook-url"
+	SplitLargeCommentsFlag           = "split-large-comments"
 	SSLCertFileFlag                  = "ssl-cert-file"
 	SSLKeyFileFlag                   = "ssl-key-file"
 	RestrictFileList                 = "restrict-file-list"
+	TeamsWebhookURLFlag              = "teams-webhook-url"
 	TFDistributionFlag               = "tf-distribution" // deprecated for DefaultTFDistributionFlag
 	TFDownloadFlag                   = "tf-download"
 	TFDownloadURLFlag                = "tf-download-url"
@@ -174,19 +206,28 @@ const (
 	DefaultCheckoutStrategy             = CheckoutStrategyBranch
 	DefaultCheckoutDepth                = 0
 	DefaultBitbucketBaseURL             = bitbucketcloud.BaseURL
+	DefaultBitbucketDraftDetection      = bitbucketserver.DraftDetectionField
+	DefaultBitbucketDraftTitlePrefixes  = "WIP:,[WIP],Draft:"
 	DefaultDataDir                      = "~/.atlantis"
 	DefaultEmojiReaction                = ""
 	DefaultExecutableName               = "atlantis"
 	DefaultMarkdownTemplateOverridesDir = "~/.markdown_templates"
+	DefaultGHAppTokenRefreshLeadTime    = "5m"
 	DefaultGHHostname                   = "github.com"
+	DefaultGHRateLimitMaxWait           = "5m"
 	DefaultGiteaBaseURL                 = "https://gitea.com"
 	DefaultGiteaPageSize                = 30
 	DefaultGitlabHostname               = "gitlab.com"
+	DefaultLockGranularity              = locking.WorkspaceLockGranularity
 	DefaultLockingDBType                = "boltdb"
+	DefaultLockTTL                      = "0"
+	DefaultLogFormat                    = logging.DefaultFormat
 	DefaultLogLevel                     = "info"
 	DefaultIgnoreVCSStatusNames         = ""
 	DefaultMaxCommentsPerCommand        = 100
+	DefaultNoOpApply                    = events.NoOpApplyRun
 	DefaultParallelPoolSize             = 15
+	DefaultProjectOrder                 = events.ProjectOrderDiscovery
 	DefaultStatsNamespace               = "atlantis"
 	DefaultPort                         = 4141
 	DefaultRedisDB                      = 0
@@ -268,6 +309,18 @@ var stringFlags = map[string]stringFlag{
 			" If using Bitbucket Cloud (bitbucket.org), do not set.",
 		defaultValue: DefaultBitbucketBaseURL,
 	},
+	BitbucketDraftDetectionFlag: {
+		description: "How Atlantis detects draft pull requests on Bitbucket Server so that they're not autoplanned unless --" + AllowDraftPRs + " is set." +
+			" Accepts 'field' (default), which uses the pull request's draft field set by Bitbucket Data Center 8.18+," +
+			" 'title', which uses the pull request's title prefix (see --" + BitbucketDraftTitlePrefixesFlag + ")," +
+			" or 'any', which uses both.",
+		defaultValue: DefaultBitbucketDraftDetection,
+	},
+	BitbucketDraftTitlePrefixesFlag: {
+		description: "Comma separated list of case insensitive title prefixes that mark a Bitbucket Server pull request as a draft." +
+			" Only used if --" + BitbucketDraftDetectionFlag + " is 'title' or 'any'.",
+		defaultValue: DefaultBitbucketDraftTitlePrefixes,
+	},
 	BitbucketWebhookSecretFlag: {
 		description: "Secret used to validate Bitbucket webhooks." +
 			" SECURITY WARNING: If not specified, Atlantis won't be able to validate that the incoming webhook call came from Bitbucket. " +
@@ -342,10 +395,18 @@ var stringFlags = map[string]stringFlag{
 	GHAppSlugFlag: {
 		description: "The Github app slug (ie. the URL-friendly name of your GitHub App)",
 	},
+	GHAppTokenRefreshLeadTimeFlag: {
+		description:  "Refresh the GitHub App installation token when it expires in less than this duration, ex. 10m. Must be less than 1h, the validity of installation tokens.",
+		defaultValue: DefaultGHAppTokenRefreshLeadTime,
+	},
 	GHOrganizationFlag: {
 		description:  "The name of the GitHub organization to use during the creation of a Github App for Atlantis",
 		defaultValue: "",
 	},
+	GHRateLimitMaxWaitFlag: {
+		description:  "How long a GitHub API request rejected by rate limits waits in total for the limit to reset before it fails, ex. 10m. 0 disables retrying rate limited requests.",
+		defaultValue: DefaultGHRateLimitMaxWait,
+	},
 	GHWebhookSecretFlag: {
 		description: "Secret used to validate GitHub webhooks (see https://developer.github.com/webhooks/securing/)." +
 			" SECURITY WARNING: If not specified, Atlantis won't be able to validate that the incoming webhook call came from GitHub. " +
@@ -398,10 +459,23 @@ var stringFlags = map[string]stringFlag{
 	APISecretFlag: {
 		description: "Secret used to validate requests made to the /api/* endpoints",
 	},
+	LockGranularityFlag: {
+		description: "What a project lock covers. Either workspace to lock each workspace of a project's dir separately, " +
+			"or project to lock all workspaces of the dir together.",
+		defaultValue: DefaultLockGranularity,
+	},
 	LockingDBType: {
 		description:  "The locking database type to use for storing plan and apply locks.",
 		defaultValue: DefaultLockingDBType,
 	},
+	LockTTLFlag: {
+		description:  "Release project locks that have been held for longer than this duration, ex. 24h. Locks in use by a running command are never released. 0 disables expiry.",
+		defaultValue: DefaultLockTTL,
+	},
+	LogFormatFlag: {
+		description:  "Log format. Either default, or json to write the fields of each log entry, ex. repo and pull, next to level and msg instead of nested under json.",
+		defaultValue: DefaultLogFormat,
+	},
 	LogLevelFlag: {
 		description:  "Log level. Either debug, info, warn, or error.",
 		defaultValue: DefaultLogLevel,
@@ -410,6 +484,16 @@ var stringFlags = map[string]stringFlag{
 		description:  "Directory for custom overrides to the markdown templates used for comments.",
 		defaultValue: DefaultMarkdownTemplateOverridesDir,
 	},
+	NoOpApplyFlag: {
+		description: "What apply does for projects whose plan has no changes. Either apply to apply them anyway, " +
+			"warn to apply them and warn that nothing changed, or skip to discard their plan without running apply.",
+		defaultValue: DefaultNoOpApply,
+	},
+	ProjectOrderFlag: {
+		description: "The order projects are planned and applied in and listed in comments within their execution order group. " +
+			"Either discovery to keep the order they're found in, config to order them like the repo config or name to order them by project name.",
+		defaultValue: DefaultProjectOrder,
+	},
 	StatsNamespace: {
 		description:  "Namespace for aggregating stats.",
 		defaultValue: DefaultStatsNamespace,
@@ -426,6 +510,10 @@ var stringFlags = map[string]stringFlag{
 	RepoConfigJSONFlag: {
 		description: "Specify repo config as a JSON string. Useful if you don't want to write a config file to disk.",
 	},
+	RepoConfigEnvAllowlistFlag: {
+		description: "Comma separated list of environment variables that repo config files (atlantis.yaml) can reference as ${VAR}." +
+			" If it's set, ${VAR} in the string values of repo configs is replaced by the variable's value and $$ by a literal $.",
+	},
 	RepoAllowlistFlag: {
 		description: "Comma separated list of repositories that Atlantis will operate on. " +
 			"The format is {hostname}/{owner}/{repo}, ex. github.com/runatlantis/atlantis. '*' matches any characters until the next comma. Examples: " +
@@ -435,12 +523,18 @@ var stringFlags = map[string]stringFlag{
 	SlackTokenFlag: {
 		description: "API token for Slack notifications.",
 	},
+	SlackWebhookURLFlag: {
+		description: "URL of a Slack incoming webhook to send a summary of the results of plan and apply commands to.",
+	},
 	SSLCertFileFlag: {
 		description: "File containing x509 Certificate used for serving HTTPS. If the cert is signed by a CA, the file should be the concatenation of the server's certificate, any intermediates, and the CA's certificate.",
 	},
 	SSLKeyFileFlag: {
 		description: fmt.Sprintf("File containing x509 private key matching --%s.", SSLCertFileFlag),
 	},
+	TeamsWebhookURLFlag: {
+		description: "URL of a Microsoft Teams webhook to send a summary of the results of plan and apply commands to as an adaptive card.",
+	},
 	TFDistributionFlag: {
 		description: "[Deprecated for --default-tf-distribution].",
 		hidden:      true,
@@ -466,6 +560,10 @@ var stringFlags = map[string]stringFlag{
 		description: "Terraform version to default to (ex. v0.12.0). Will download if not yet on disk." +
 			" If not set, Atlantis uses the terraform binary in its PATH.",
 	},
+	DefaultTFVersionConstraintFlag: {
+		description: "Version constraint, ex. '>= 1.5, < 1.7', that picks the newest installed Terraform version it matches as the default." +
+			" Looks at the terraform binary in PATH and terraform<version> binaries in PATH and <data-dir>/bin. Cannot be used with --" + DefaultTFVersionFlag + ".",
+	},
 	VarFileAllowlistFlag: {
 		description: "Comma-separated list of additional paths where variable definition files can be read from." +
 			" If this argument is not provided, it defaults to Atlantis' data directory, determined by the --data-dir argument.",
@@ -497,10 +595,19 @@ var stringFlags = map[string]stringFlag{
 }
 
 var boolFlags = map[string]boolFlag{
+	ADThreadCommentsFlag: {
+		description:  "Post Azure DevOps plan and apply comments as replies in the thread of the pull request's first plan comment instead of starting a new thread for each.",
+		defaultValue: false,
+	},
 	AllowForkPRsFlag: {
 		description:  "Allow Atlantis to run on pull requests from forks. A security issue for public repos.",
 		defaultValue: false,
 	},
+	AsyncApplyFlag: {
+		description: "Acknowledge apply commands immediately and run them in the background." +
+			" The results are commented on the pull request once the apply completes.",
+		defaultValue: false,
+	},
 	AutoplanModules: {
 		description:  "Automatically plan projects that have a changed module from the local repository.",
 		defaultValue: false,
@@ -552,10 +659,18 @@ var boolFlags = map[string]boolFlag{
 		description:  "Feature flag to enable functionality to allow mergeable check to ignore apply required check",
 		defaultValue: false,
 	},
+	GHCheckRunsFlag: {
+		description:  "Publish the plan and apply statuses as GitHub check runs instead of commit statuses. Requires a GitHub App.",
+		defaultValue: false,
+	},
 	GitlabStatusRetryEnabledFlag: {
 		description:  "Enable enhanced retry logic for GitLab pipeline status updates with exponential backoff.",
 		defaultValue: false,
 	},
+	GitlabExternalStatusChecksFlag: {
+		description:  "Also report the plan and apply statuses to the merge request's external status checks with the same names, ex. 'atlantis/apply: project1'.",
+		defaultValue: false,
+	},
 	AllowDraftPRs: {
 		description:  "Enable autoplan for Github Draft Pull Requests",
 		defaultValue: false,
@@ -569,6 +684,27 @@ var boolFlags = map[string]boolFlag{
 		description:  "Include git untracked files in the Atlantis modified file scope.",
 		defaultValue: false,
 	},
+	PlanJSONStatsFlag: {
+		description: "Read the number of resources each plan adds, changes and destroys with terraform show -json instead of parsing the plan output. " +
+			"Falls back to parsing the plan output if the plan can't be shown as JSON.",
+		defaultValue: false,
+	},
+	PlanReviewCommentsFlag: {
+		description: "Post each project's plan as a review comment on a file it modified instead of in the pull request comment. " +
+			"The pull request comment still summarizes every project. " +
+			"VCS support is limited to: GitHub. Other VCS hosts post plans in the pull request comment.",
+		defaultValue: false,
+	},
+	PlanSummaryCommentFlag: {
+		description: "Post a comment with a table of the resources each project's latest plan adds, changes and destroys after every plan. " +
+			"Previous summary comments are hidden on VCS hosts that support hiding comments.",
+		defaultValue: false,
+	},
+	PullDescriptionPlanLinksFlag: {
+		description: "Maintain a section in the pull request description that links to each project's plan output. " +
+			"VCS support is limited to: GitHub, GitLab.",
+		defaultValue: false,
+	},
 	ParallelPlanFlag: {
 		description:  "Run plan operations in parallel.",
 		defaultValue: false,
@@ -617,6 +753,14 @@ var boolFlags = map[string]boolFlag{
 		description:  "Toggle off folding in markdown output.",
 		defaultValue: false,
 	},
+	DisablePolicyCheckCacheFlag: {
+		description:  "Run conftest for every policy check instead of reusing the result of a policy set while the plan and the policy set are unchanged.",
+		defaultValue: false,
+	},
+	DisableProjectStatusesFlag: {
+		description:  "Only set the statuses rolled up for all projects, ex. atlantis/plan, instead of also setting a status per project, ex. atlantis/plan: project1.",
+		defaultValue: false,
+	},
 	WriteGitCredsFlag: {
 		description: "Write out a .git-credentials file with the provider user and token to allow cloning private modules over HTTPS or SSH." +
 			" This writes secrets to disk and should only be enabled in a secure environment.",
@@ -626,6 +770,11 @@ var boolFlags = map[string]boolFlag{
 		description:  "Skips cloning the PR repo if there are no projects were changed in the PR.",
 		defaultValue: false,
 	},
+	SplitLargeCommentsFlag: {
+		description: "Split GitHub comments that are too long into numbered parts at line and code block boundaries." +
+			" Unless there would be more parts than --" + MaxCommentsPerCommand + ", the output isn't truncated.",
+		defaultValue: false,
+	},
 	TFDownloadFlag: {
 		description:  "Allow Atlantis to list & download Terraform versions. Setting this to false can be helpful in air-gapped environments.",
 		defaultValue: DefaultTFDownload,
@@ -662,6 +811,11 @@ var intFlags = map[string]intFlag{
 			" If merge base is further behind than this number of commits from any of branches heads, full fetch will be performed.",
 		defaultValue: DefaultCheckoutDepth,
 	},
+	MaxCommentOutputSizeFlag: {
+		description: "If non-zero, the maximum number of bytes of each project's plan or apply output to comment." +
+			" Longer output is cut at a line boundary and links to the project's job with the full output.",
+		defaultValue: 0,
+	},
 	MaxCommentsPerCommand: {
 		description:  "If non-zero, the maximum number of comments to split command output into before truncating.",
 		defaultValue: DefaultMaxCommentsPerCommand,
@@ -674,6 +828,10 @@ var intFlags = map[string]intFlag{
 		description:  "Max size of the wait group that runs parallel plans and applies (if enabled).",
 		defaultValue: DefaultParallelPoolSize,
 	},
+	ParallelPlanLimitFlag: {
+		description:  "Max number of projects planned at the same time when parallel plans are enabled, overriding --" + ParallelPoolSize + " for plans. 1 plans one project at a time. 0 uses --" + ParallelPoolSize + ".",
+		defaultValue: 0,
+	},
 	PortFlag: {
 		description:  "Port to bind to.",
 		defaultValue: DefaultPort,
@@ -932,15 +1090,36 @@ func (s *ServerCmd) setDefaults(c *server.UserConfig, v *viper.Viper) {
 	if c.BitbucketBaseURL == "" {
 		c.BitbucketBaseURL = DefaultBitbucketBaseURL
 	}
+	if c.BitbucketDraftDetection == "" {
+		c.BitbucketDraftDetection = DefaultBitbucketDraftDetection
+	}
+	if c.BitbucketDraftTitlePrefixes == "" {
+		c.BitbucketDraftTitlePrefixes = DefaultBitbucketDraftTitlePrefixes
+	}
 	if c.EmojiReaction == "" {
 		c.EmojiReaction = DefaultEmojiReaction
 	}
 	if c.ExecutableName == "" {
 		c.ExecutableName = DefaultExecutableName
 	}
+	if c.GithubAppTokenRefreshLeadTime == "" {
+		c.GithubAppTokenRefreshLeadTime = DefaultGHAppTokenRefreshLeadTime
+	}
+	if c.GithubRateLimitMaxWait == "" {
+		c.GithubRateLimitMaxWait = DefaultGHRateLimitMaxWait
+	}
+	if c.LockGranularity == "" {
+		c.LockGranularity = DefaultLockGranularity
+	}
 	if c.LockingDBType == "" {
 		c.LockingDBType = DefaultLockingDBType
 	}
+	if c.LockTTL == "" {
+		c.LockTTL = DefaultLockTTL
+	}
+	if c.LogFormat == "" {
+		c.LogFormat = DefaultLogFormat
+	}
 	if c.LogLevel == "" {
 		c.LogLevel = DefaultLogLevel
 	}
@@ -950,6 +1129,12 @@ func (s *ServerCmd) setDefaults(c *server.UserConfig, v *viper.Viper) {
 	if !v.IsSet("max-comments-per-command") {
 		c.MaxCommentsPerCommand = DefaultMaxCommentsPerCommand
 	}
+	if c.NoOpApply == "" {
+		c.NoOpApply = DefaultNoOpApply
+	}
+	if c.ProjectOrder == "" {
+		c.ProjectOrder = DefaultProjectOrder
+	}
 	if c.ParallelPoolSize == 0 {
 		c.ParallelPoolSize = DefaultParallelPoolSize
 	}
@@ -999,12 +1184,38 @@ func (s *ServerCmd) validate(userConfig server.UserConfig) error {
 	if !isValidLogLevel(userConfig.LogLevel) {
 		return fmt.Errorf("invalid log level: must be one of %v", ValidLogLevels)
 	}
+	if !slices.Contains(logging.ValidFormats, userConfig.LogFormat) {
+		return fmt.Errorf("invalid --%s: must be one of %v", LogFormatFlag, logging.ValidFormats)
+	}
+	if !slices.Contains(locking.ValidLockGranularities, userConfig.LockGranularity) {
+		return fmt.Errorf("invalid --%s: must be one of %v", LockGranularityFlag, locking.ValidLockGranularities)
+	}
+	if !slices.Contains(events.ValidNoOpApplyModes, userConfig.NoOpApply) {
+		return fmt.Errorf("invalid --%s: must be one of %v", NoOpApplyFlag, events.ValidNoOpApplyModes)
+	}
+	if !slices.Contains(events.ValidProjectOrders, userConfig.ProjectOrder) {
+		return fmt.Errorf("invalid --%s: must be one of %v", ProjectOrderFlag, events.ValidProjectOrders)
+	}
+
+	if userConfig.MaxCommentOutputSize < 0 {
+		return fmt.Errorf("--%s cannot be negative", MaxCommentOutputSizeFlag)
+	}
+	if userConfig.ParallelPlanLimit < 0 {
+		return fmt.Errorf("--%s cannot be negative", ParallelPlanLimitFlag)
+	}
 
 	if userConfig.DefaultTFDistribution != TFDistributionTerraform && userConfig.DefaultTFDistribution != TFDistributionOpenTofu {
 		return fmt.Errorf("invalid tf distribution: expected one of %s or %s",
 			TFDistributionTerraform, TFDistributionOpenTofu)
 	}
 
+	switch userConfig.BitbucketDraftDetection {
+	case bitbucketserver.DraftDetectionField, bitbucketserver.DraftDetectionTitle, bitbucketserver.DraftDetectionAny:
+	default:
+		return fmt.Errorf("invalid --%s: not one of %s, %s or %s", BitbucketDraftDetectionFlag,
+			bitbucketserver.DraftDetectionField, bitbucketserver.DraftDetectionTitle, bitbucketserver.DraftDetectionAny)
+	}
+
 	checkoutStrategy := userConfig.CheckoutStrategy
 	if checkoutStrategy != CheckoutStrategyBranch && checkoutStrategy != CheckoutStrategyMerge {
 		return fmt.Errorf("invalid checkout strategy: not one of %s or %s",
@@ -1069,6 +1280,15 @@ func (s *ServerCmd) validate(userConfig server.UserConfig) error {
 		return fmt.Errorf("--%s must have http:// or https://, got %q", GiteaBaseURLFlag, userConfig.GiteaBaseURL)
 	}
 
+	if userConfig.DefaultTFVersionConstraint != "" {
+		if userConfig.DefaultTFVersion != "" {
+			return fmt.Errorf("cannot use --%s and --%s at the same time", DefaultTFVersionFlag, DefaultTFVersionConstraintFlag)
+		}
+		if _, err := version.NewConstraint(userConfig.DefaultTFVersionConstraint); err != nil {
+			return fmt.Errorf("invalid --%s: %s", DefaultTFVersionConstraintFlag, err)
+		}
+	}
+
 	if userConfig.RepoConfig != "" && userConfig.RepoConfigJSON != "" {
 		return fmt.Errorf("cannot use --%s and --%s at the same time", RepoConfigFlag, RepoConfigJSONFlag)
 	}
@@ -1099,9 +1319,37 @@ func (s *ServerCmd) validate(userConfig server.UserConfig) error {
 		return errors.Wrapf(patternErr, "invalid pattern in --%s, %s", AutoplanFileListFlag, userConfig.AutoplanFileList)
 	}
 
-	if _, err := userConfig.ToAllowCommandNames(); err != nil {
+	allowCommands, err := userConfig.ToAllowCommandNames()
+	if err != nil {
 		return errors.Wrapf(err, "invalid --%s", AllowCommandsFlag)
 	}
+	if userConfig.AsyncApply && !slices.Contains(allowCommands, command.Apply) {
+		return fmt.Errorf("--%s requires apply to be included in --%s", AsyncApplyFlag, AllowCommandsFlag)
+	}
+
+	if _, err := userConfig.ToLockTTL(); err != nil {
+		return errors.Wrapf(err, "invalid --%s", LockTTLFlag)
+	}
+
+	if _, err := userConfig.ToGithubAppTokenRefreshLeadTime(); err != nil {
+		return errors.Wrapf(err, "invalid --%s", GHAppTokenRefreshLeadTimeFlag)
+	}
+
+	if _, err := userConfig.ToGithubRateLimitMaxWait(); err != nil {
+		return errors.Wrapf(err, "invalid --%s", GHRateLimitMaxWaitFlag)
+	}
+
+	if _, err := userConfig.ToRepoConfigEnvAllowlist(); err != nil {
+		return errors.Wrapf(err, "invalid --%s", RepoConfigEnvAllowlistFlag)
+	}
+
+	if userConfig.GithubCheckRuns && userConfig.GithubAppID == 0 {
+		return fmt.Errorf("--%s is only supported with a GitHub App, check runs can't be created with --%s", GHCheckRunsFlag, GHUserFlag)
+	}
+
+	if userConfig.PullDescriptionPlanLinks && userConfig.GithubUser == "" && userConfig.GithubAppID == 0 && userConfig.GitlabUser == "" {
+		return fmt.Errorf("--%s is only supported with GitHub or GitLab", PullDescriptionPlanLinksFlag)
+	}
 
 	if _, err := userConfig.ToWebhookHttpHeaders(); err != nil {
 		return errors.Wrapf(err, "invalid --%s", WebhookHttpHeaders)
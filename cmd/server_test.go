@@ -58,6 +58,7 @@ var testFlags = map[string]interface{}{
 	ADHostnameFlag:                   "dev.azure.com",
 	ADTokenFlag:                      "ad-token",
 	ADUserFlag:                       "ad-user",
+	ADThreadCommentsFlag:             true,
 	ADWebhookPasswordFlag:            "ad-wh-pass",
 	ADWebhookUserFlag:                "ad-wh-user",
 	AtlantisURLFlag:                  "url",
@@ -65,12 +66,15 @@ var testFlags = map[string]interface{}{
 	AutoplanModulesFromProjects:      "",
 	AllowCommandsFlag:                "version,plan,apply,unlock,import,approve_policies",
 	AllowForkPRsFlag:                 true,
+	AsyncApplyFlag:                   false,
 	APISecretFlag:                    "",
 	AutoDiscoverModeFlag:             "auto",
 	AutomergeFlag:                    true,
 	AutoplanFileListFlag:             "**/*.tf,**/*.yml",
 	BitbucketApiUserFlag:             "bitbucket-api-user",
 	BitbucketBaseURLFlag:             "https://bitbucket-base-url.com",
+	BitbucketDraftDetectionFlag:      "any",
+	BitbucketDraftTitlePrefixesFlag:  "WIP:,Draft:",
 	BitbucketTokenFlag:               "bitbucket-token",
 	BitbucketUserFlag:                "bitbucket-user",
 	BitbucketWebhookSecretFlag:       "bitbucket-secret",
@@ -79,8 +83,11 @@ var testFlags = map[string]interface{}{
 	DataDirFlag:                      "/path",
 	DefaultTFDistributionFlag:        "terraform",
 	DefaultTFVersionFlag:             "v0.11.0",
+	DefaultTFVersionConstraintFlag:   "",
 	DisableApplyAllFlag:              true,
 	DisableMarkdownFoldingFlag:       true,
+	DisablePolicyCheckCacheFlag:      true,
+	DisableProjectStatusesFlag:       true,
 	DisableRepoLockingFlag:           true,
 	DisableGlobalApplyLockFlag:       false,
 	DiscardApprovalOnPlanFlag:        true,
@@ -88,6 +95,7 @@ var testFlags = map[string]interface{}{
 	ExecutableName:                   "atlantis",
 	FailOnPreWorkflowHookError:       false,
 	GHAllowMergeableBypassApply:      false,
+	GHCheckRunsFlag:                  false,
 	GHHostnameFlag:                   "ghhostname",
 	GHTeamAllowlistFlag:              "",
 	GHTokenFlag:                      "token",
@@ -97,6 +105,8 @@ var testFlags = map[string]interface{}{
 	GHAppKeyFlag:                     "",
 	GHAppKeyFileFlag:                 "",
 	GHAppSlugFlag:                    "atlantis",
+	GHAppTokenRefreshLeadTimeFlag:    "10m",
+	GHRateLimitMaxWaitFlag:           "10m",
 	GHAppInstallationIDFlag:          int64(0),
 	GHOrganizationFlag:               "",
 	GHWebhookSecretFlag:              "secret",
@@ -111,17 +121,29 @@ var testFlags = map[string]interface{}{
 	GitlabUserFlag:                   "gitlab-user",
 	GitlabWebhookSecretFlag:          "gitlab-secret",
 	GitlabStatusRetryEnabledFlag:     false,
+	GitlabExternalStatusChecksFlag:   false,
 	HideUnchangedPlanComments:        false,
 	HidePrevPlanComments:             false,
 	IncludeGitUntrackedFiles:         false,
+	PlanJSONStatsFlag:                false,
+	PlanReviewCommentsFlag:           false,
+	PlanSummaryCommentFlag:           false,
+	PullDescriptionPlanLinksFlag:     false,
+	LockGranularityFlag:              "project",
 	LockingDBType:                    "boltdb",
+	LockTTLFlag:                      "24h",
+	LogFormatFlag:                    "json",
 	LogLevelFlag:                     "debug",
 	MarkdownTemplateOverridesDirFlag: "/path2",
+	MaxCommentOutputSizeFlag:         50000,
 	MaxCommentsPerCommand:            10,
+	NoOpApplyFlag:                    "skip",
+	ProjectOrderFlag:                 "name",
 	StatsNamespace:                   "atlantis",
 	AllowDraftPRs:                    true,
 	PortFlag:                         8181,
 	ParallelPoolSize:                 100,
+	ParallelPlanLimitFlag:            4,
 	ParallelPlanFlag:                 true,
 	ParallelApplyFlag:                true,
 	PendingApplyStatusFlag:           false,
@@ -135,15 +157,19 @@ var testFlags = map[string]interface{}{
 	RepoAllowlistFlag:                "github.com/runatlantis/atlantis",
 	RepoConfigFlag:                   "",
 	RepoConfigJSONFlag:               "",
+	RepoConfigEnvAllowlistFlag:       "",
 	SilenceNoProjectsFlag:            false,
 	SilenceVCSStatusNoProjectsFlag:   false,
 	SilenceForkPRErrorsFlag:          true,
 	SilenceAllowlistErrorsFlag:       true,
 	SilenceVCSStatusNoPlans:          true,
 	SkipCloneNoChanges:               true,
+	SplitLargeCommentsFlag:           true,
 	SlackTokenFlag:                   "slack-token",
+	SlackWebhookURLFlag:              "https://hooks.slack.com/services/T/B/X",
 	SSLCertFileFlag:                  "cert-file",
 	SSLKeyFileFlag:                   "key-file",
+	TeamsWebhookURLFlag:              "https://example.webhook.office.com/webhook",
 	RestrictFileList:                 false,
 	TFDistributionFlag:               "terraform",
 	TFDownloadFlag:                   true,
@@ -453,6 +479,38 @@ func TestExecute_RepoAllowlistScheme(t *testing.T) {
 	Equals(t, "--repo-allowlist cannot contain ://, should be hostnames only", err.Error())
 }
 
+func TestExecute_ValidateLogFormat(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		LogFormatFlag: "text",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "invalid --log-format: must be one of [default json]", err)
+}
+
+func TestExecute_ValidateLockGranularity(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		LockGranularityFlag: "repo",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "invalid --lock-granularity: must be one of [workspace project]", err)
+}
+
+func TestExecute_ValidateNoOpApply(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		NoOpApplyFlag: "ignore",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "invalid --no-op-apply: must be one of [apply warn skip]", err)
+}
+
+func TestExecute_ValidateProjectOrder(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		ProjectOrderFlag: "alphabetical",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "invalid --project-order: must be one of [discovery config name]", err)
+}
+
 func TestExecute_ValidateLogLevel(t *testing.T) {
 	cases := []struct {
 		description string
@@ -820,6 +878,107 @@ func TestExecute_ValidateAllowCommands(t *testing.T) {
 	}
 }
 
+func TestExecute_ValidateAsyncApply(t *testing.T) {
+	cases := []struct {
+		name              string
+		allowCommandsFlag string
+		expErr            string
+	}{
+		{
+			name:              "apply allowed",
+			allowCommandsFlag: "plan,apply",
+			expErr:            "",
+		},
+		{
+			name:              "apply not allowed",
+			allowCommandsFlag: "plan,unlock",
+			expErr:            "--async-apply requires apply to be included in --allow-commands",
+		},
+	}
+	for _, testCase := range cases {
+		c := setupWithDefaults(map[string]interface{}{
+			AllowCommandsFlag: testCase.allowCommandsFlag,
+			AsyncApplyFlag:    true,
+		}, t)
+		err := c.Execute()
+		if testCase.expErr != "" {
+			ErrEquals(t, testCase.expErr, err)
+		} else {
+			Ok(t, err)
+		}
+	}
+}
+
+func TestExecute_ValidatePullDescriptionPlanLinks(t *testing.T) {
+	c := setup(map[string]interface{}{
+		BitbucketUserFlag:            "user",
+		BitbucketTokenFlag:           "token",
+		RepoAllowlistFlag:            "*",
+		PullDescriptionPlanLinksFlag: true,
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--pull-description-plan-links is only supported with GitHub or GitLab", err)
+}
+
+func TestExecute_ValidateGHCheckRuns(t *testing.T) {
+	c := setup(map[string]interface{}{
+		GHUserFlag:        "user",
+		GHTokenFlag:       "token",
+		RepoAllowlistFlag: "*",
+		GHCheckRunsFlag:   true,
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--gh-check-runs is only supported with a GitHub App, check runs can't be created with --gh-user", err)
+}
+
+func TestExecute_ValidateLockTTL(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		LockTTLFlag: "-1h",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "invalid --lock-ttl: must not be negative", err)
+}
+
+func TestExecute_ValidateGHAppTokenRefreshLeadTime(t *testing.T) {
+	cases := map[string]string{
+		"-1m": "invalid --gh-app-token-refresh-lead-time: must not be negative",
+		"1h":  "invalid --gh-app-token-refresh-lead-time: must be less than 1h0m0s",
+	}
+	for leadTime, expErr := range cases {
+		t.Run(leadTime, func(t *testing.T) {
+			c := setupWithDefaults(map[string]interface{}{
+				GHAppTokenRefreshLeadTimeFlag: leadTime,
+			}, t)
+			err := c.Execute()
+			ErrEquals(t, expErr, err)
+		})
+	}
+}
+
+func TestExecute_ValidateGHRateLimitMaxWait(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		GHRateLimitMaxWaitFlag: "-1m",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "invalid --gh-rate-limit-max-wait: must not be negative", err)
+}
+
+func TestExecute_ValidateRepoConfigEnvAllowlist(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		RepoConfigEnvAllowlistFlag: "ENV, 1BAD",
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, `invalid --repo-config-env-allowlist: "1BAD" is not a valid environment variable name`, err)
+}
+
+func TestExecute_ValidateParallelPlanLimit(t *testing.T) {
+	c := setupWithDefaults(map[string]interface{}{
+		ParallelPlanLimitFlag: -1,
+	}, t)
+	err := c.Execute()
+	ErrEquals(t, "--parallel-plan-limit cannot be negative", err)
+}
+
 func TestExecute_ExpandHomeInDataDir(t *testing.T) {
 	t.Log("If ~ is used as a data-dir path, should expand to absolute home path")
 	c := setup(map[string]interface{}{
@@ -988,6 +1147,48 @@ func TestExecute_RepoCfgFlags(t *testing.T) {
 	ErrEquals(t, "cannot use --repo-config and --repo-config-json at the same time", err)
 }
 
+func TestExecute_DefaultTFVersionConstraint(t *testing.T) {
+	cases := []struct {
+		description string
+		flags       map[string]interface{}
+		expErr      string
+	}{
+		{
+			description: "constraint",
+			flags:       map[string]interface{}{DefaultTFVersionConstraintFlag: ">= 1.5, < 1.7"},
+		},
+		{
+			description: "with an exact version",
+			flags:       map[string]interface{}{DefaultTFVersionConstraintFlag: ">= 1.5", DefaultTFVersionFlag: "1.5.7"},
+			expErr:      "cannot use --default-tf-version and --default-tf-version-constraint at the same time",
+		},
+		{
+			description: "invalid constraint",
+			flags:       map[string]interface{}{DefaultTFVersionConstraintFlag: "newest"},
+			expErr:      "invalid --default-tf-version-constraint: Malformed constraint: newest",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			flags := map[string]interface{}{
+				GHUserFlag:        "user",
+				GHTokenFlag:       "token",
+				RepoAllowlistFlag: "github.com",
+			}
+			for k, v := range c.flags {
+				flags[k] = v
+			}
+			err := setup(flags, t).Execute()
+			if c.expErr == "" {
+				Ok(t, err)
+				Equals(t, ">= 1.5, < 1.7", passedConfig.DefaultTFVersionConstraint)
+			} else {
+				ErrEquals(t, c.expErr, err)
+			}
+		})
+	}
+}
+
 // Can't use both --tfe-hostname flag without --tfe-token.
 func TestExecute_TFEHostnameOnly(t *testing.T) {
 	c := setup(map[string]interface{}{
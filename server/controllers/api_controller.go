@@ -5,14 +5,20 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/runatlantis/atlantis/server/core/db"
 	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/core/runtime"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
@@ -31,6 +37,7 @@ type APIController struct {
 	ProjectCommandBuilder          events.ProjectCommandBuilder     `validate:"required"`
 	ProjectPlanCommandRunner       events.ProjectPlanCommandRunner  `validate:"required"`
 	ProjectApplyCommandRunner      events.ProjectApplyCommandRunner `validate:"required"`
+	ProjectDriftCommandRunner      events.ProjectDriftCommandRunner `validate:"required"`
 	FailOnPreWorkflowHookError     bool
 	PreWorkflowHooksCommandRunner  events.PreWorkflowHooksCommandRunner  `validate:"required"`
 	PostWorkflowHooksCommandRunner events.PostWorkflowHooksCommandRunner `validate:"required"`
@@ -42,6 +49,10 @@ type APIController struct {
 	CommitStatusUpdater            events.CommitStatusUpdater            `validate:"required"`
 	// SilenceVCSStatusNoProjects is whether API should set commit status if no projects are found
 	SilenceVCSStatusNoProjects bool
+	// Approvals receives the decisions for await_approval steps.
+	Approvals *runtime.ApprovalRegistry
+	// Database stores the external approvals of pull requests.
+	Database db.Database
 }
 
 type APIRequest struct {
@@ -56,31 +67,50 @@ type APIRequest struct {
 	}
 }
 
-func (a *APIRequest) getCommands(ctx *command.Context, cmdBuilder func(*command.Context, *events.CommentCommand) ([]command.ProjectContext, error)) ([]command.ProjectContext, []*events.CommentCommand, error) {
+// APIResult is the response of the plan and apply endpoints.
+type APIResult struct {
+	// RunID identifies the run in the logs of Atlantis.
+	RunID string
+	*command.Result
+}
+
+func (a *APIRequest) getCommands(ctx *command.Context, cmdName command.Name, cmdBuilder func(*command.Context, *events.CommentCommand) ([]command.ProjectContext, error)) ([]command.ProjectContext, []*events.CommentCommand, error) {
 	cc := make([]*events.CommentCommand, 0)
 
 	for _, project := range a.Projects {
 		cc = append(cc, &events.CommentCommand{
+			Name:        cmdName,
 			ProjectName: project,
 		})
 	}
 	for _, path := range a.Paths {
 		cc = append(cc, &events.CommentCommand{
+			Name:       cmdName,
 			RepoRelDir: strings.TrimRight(path.Directory, "/"),
 			Workspace:  path.Workspace,
 		})
 	}
+	// Like a comment without flags, a request for a pull request without
+	// projects or paths runs every project the pull request modified.
+	if len(cc) == 0 && a.PR != 0 {
+		cc = append(cc, &events.CommentCommand{Name: cmdName})
+	}
 
+	// projectCC is the comment command each project command was built from.
 	cmds := make([]command.ProjectContext, 0)
+	projectCC := make([]*events.CommentCommand, 0)
 	for _, commentCommand := range cc {
 		projectCmds, err := cmdBuilder(ctx, commentCommand)
 		if err != nil {
 			return nil, nil, fmt.Errorf("failed to build command: %v", err)
 		}
 		cmds = append(cmds, projectCmds...)
+		for range projectCmds {
+			projectCC = append(projectCC, commentCommand)
+		}
 	}
 
-	return cmds, cc, nil
+	return cmds, projectCC, nil
 }
 
 func (a *APIController) apiReportError(w http.ResponseWriter, code int, err error) {
@@ -110,18 +140,11 @@ func (a *APIController) Plan(w http.ResponseWriter, r *http.Request) {
 		a.apiReportError(w, http.StatusInternalServerError, err)
 		return
 	}
-	defer a.Locker.UnlockByPull(ctx.HeadRepo.FullName, ctx.Pull.Num) // nolint: errcheck
+	defer a.apiUnlock(request, ctx)
 	if result.HasErrors() {
 		code = http.StatusInternalServerError
 	}
-
-	// TODO: make a better response
-	response, err := json.Marshal(result)
-	if err != nil {
-		a.apiReportError(w, http.StatusInternalServerError, err)
-		return
-	}
-	a.respond(w, logging.Warn, code, "%s", string(response))
+	a.apiRespondResult(w, code, ctx, result)
 }
 
 func (a *APIController) Apply(w http.ResponseWriter, r *http.Request) {
@@ -145,7 +168,7 @@ func (a *APIController) Apply(w http.ResponseWriter, r *http.Request) {
 		a.apiReportError(w, http.StatusInternalServerError, err)
 		return
 	}
-	defer a.Locker.UnlockByPull(ctx.HeadRepo.FullName, ctx.Pull.Num) // nolint: errcheck
+	defer a.apiUnlock(request, ctx)
 
 	// We can now prepare and run the apply step
 	result, err := a.apiApply(request, ctx)
@@ -156,13 +179,36 @@ func (a *APIController) Apply(w http.ResponseWriter, r *http.Request) {
 	if result.HasErrors() {
 		code = http.StatusInternalServerError
 	}
+	a.apiRespondResult(w, code, ctx, result)
+}
 
-	response, err := json.Marshal(result)
+// Drift runs plan for the projects of the request to report if their
+// infrastructure drifted. Like the drift command, it doesn't lock projects or
+// set commit statuses.
+func (a *APIController) Drift(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	request, ctx, code, err := a.apiParseAndValidate(r)
+	if err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	err = a.apiSetup(ctx, command.Drift)
 	if err != nil {
 		a.apiReportError(w, http.StatusInternalServerError, err)
 		return
 	}
-	a.respond(w, logging.Warn, code, "%s", string(response))
+
+	result, err := a.apiDrift(request, ctx)
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if result.HasErrors() {
+		code = http.StatusInternalServerError
+	}
+	a.apiRespondResult(w, code, ctx, result)
 }
 
 type LockDetail struct {
@@ -179,19 +225,68 @@ type LockDetail struct {
 
 type ListLocksResult struct {
 	Locks []LockDetail
+	// Total is the number of locks before limit and offset are applied.
+	Total int
 }
 
+type APIApprovalRequest struct {
+	// Token identifies the await_approval step to resolve.
+	Token    string `validate:"required_without=Repository"`
+	Approved bool
+	Reason   string
+	// Repository, Type and PR identify the pull request to record an
+	// external approval for when Token isn't set.
+	Repository string `validate:"required_without=Token"`
+	Type       string `validate:"required_with=Repository"`
+	PR         int    `validate:"required_with=Repository"`
+	// Commit is the approved commit. If it's set it must be the pull
+	// request's latest planned commit.
+	Commit string
+	// Projects are the names of the approved projects, all projects are
+	// approved if it's empty.
+	Projects []string
+	// Approver identifies who approved, ex. a change request number.
+	Approver string `validate:"required_with=Repository"`
+}
+
+// ListLocks returns the current project locks sorted by name. The optional
+// limit and offset query parameters page through them.
 func (a *APIController) ListLocks(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 
+	if code, err := a.apiCheckSecret(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+	limit, err := apiQueryInt(r, "limit")
+	if err != nil {
+		a.apiReportError(w, http.StatusBadRequest, err)
+		return
+	}
+	offset, err := apiQueryInt(r, "offset")
+	if err != nil {
+		a.apiReportError(w, http.StatusBadRequest, err)
+		return
+	}
+
 	locks, err := a.Locker.List()
 	if err != nil {
 		a.apiReportError(w, http.StatusInternalServerError, err)
 		return
 	}
 
-	result := ListLocksResult{}
-	for name, lock := range locks {
+	names := make([]string, 0, len(locks))
+	for name := range locks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	result := ListLocksResult{Total: len(names)}
+	names = names[min(offset, len(names)):]
+	if limit > 0 && limit < len(names) {
+		names = names[:limit]
+	}
+	for _, name := range names {
+		lock := locks[name]
 		lockDetail := LockDetail{
 			name,
 			lock.Project.ProjectName,
@@ -214,6 +309,123 @@ func (a *APIController) ListLocks(w http.ResponseWriter, r *http.Request) {
 	a.respond(w, logging.Warn, http.StatusOK, "%s", string(response))
 }
 
+// Approve resolves the await_approval step waiting on the request's token,
+// or records an external approval of the request's pull request if it has
+// no token.
+func (a *APIController) Approve(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	if code, err := a.apiCheckSecret(r); err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	bytes, err := io.ReadAll(r.Body)
+	if err != nil {
+		a.apiReportError(w, http.StatusBadRequest, fmt.Errorf("failed to read request"))
+		return
+	}
+	var request APIApprovalRequest
+	if err = json.Unmarshal(bytes, &request); err != nil {
+		a.apiReportError(w, http.StatusBadRequest, fmt.Errorf("failed to parse request: %v", err.Error()))
+		return
+	}
+	if err = validator.New().Struct(request); err != nil {
+		a.apiReportError(w, http.StatusBadRequest, fmt.Errorf("request is missing fields"))
+		return
+	}
+	if request.Token == "" {
+		a.apiApproveExternal(w, request)
+		return
+	}
+	if a.Approvals == nil {
+		a.apiReportError(w, http.StatusBadRequest, fmt.Errorf("approvals are not enabled"))
+		return
+	}
+
+	err = a.Approvals.Resolve(request.Token, runtime.ApprovalDecision{
+		Approved: request.Approved,
+		Reason:   request.Reason,
+	})
+	if err != nil {
+		a.apiReportError(w, http.StatusNotFound, err)
+		return
+	}
+	a.respond(w, logging.Info, http.StatusOK, "{}")
+}
+
+// apiApproveExternal records an external approval of the request's pull
+// request for the external_approval requirement. The approval is for the
+// pull request's latest planned commit so it has to be planned first.
+func (a *APIController) apiApproveExternal(w http.ResponseWriter, request APIApprovalRequest) {
+	if a.Database == nil {
+		a.apiReportError(w, http.StatusBadRequest, fmt.Errorf("external approvals are not enabled"))
+		return
+	}
+	// A rejection must not be recorded as an approval, the apply just
+	// stays blocked.
+	if !request.Approved {
+		a.apiReportError(w, http.StatusBadRequest, fmt.Errorf("only approvals can be recorded, Approved must be true"))
+		return
+	}
+	baseRepo, code, err := a.apiParseRepo(request.Type, request.Repository)
+	if err != nil {
+		a.apiReportError(w, code, err)
+		return
+	}
+
+	pull := models.PullRequest{Num: request.PR, BaseRepo: baseRepo}
+	status, err := a.Database.GetPullStatus(pull)
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, err)
+		return
+	}
+	if status == nil {
+		a.apiReportError(w, http.StatusNotFound, fmt.Errorf("pull request %s#%d has not been planned", baseRepo.FullName, request.PR))
+		return
+	}
+	headCommit := status.Pull.HeadCommit
+	if request.Commit != "" && request.Commit != headCommit {
+		a.apiReportError(w, http.StatusConflict, fmt.Errorf("commit %s is not the latest planned commit %s of pull request %s#%d", request.Commit, headCommit, baseRepo.FullName, request.PR))
+		return
+	}
+
+	err = a.Database.AddExternalApproval(pull, models.ExternalApproval{
+		HeadCommit: headCommit,
+		Projects:   request.Projects,
+		Approver:   request.Approver,
+		Reason:     request.Reason,
+		Time:       time.Now(),
+	})
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, err)
+		return
+	}
+	a.Logger.Info("recorded external approval of %s#%d at %s by %q", baseRepo.FullName, request.PR, headCommit, request.Approver)
+	a.respond(w, logging.Info, http.StatusOK, "{}")
+}
+
+// apiUnlock releases the locks taken by a request without a pull request
+// since nothing would release them later. The locks of a pull request are
+// kept like for comments until it's closed or unlocked.
+func (a *APIController) apiUnlock(request *APIRequest, ctx *command.Context) {
+	if request.PR != 0 {
+		return
+	}
+	if _, err := a.Locker.UnlockByPull(ctx.HeadRepo.FullName, ctx.Pull.Num); err != nil {
+		ctx.Log.Warn("unable to release locks: %s", err)
+	}
+}
+
+func (a *APIController) apiRespondResult(w http.ResponseWriter, code int, ctx *command.Context, result *command.Result) {
+	response, err := json.Marshal(APIResult{RunID: ctx.RunID, Result: result})
+	if err != nil {
+		a.apiReportError(w, http.StatusInternalServerError, err)
+		return
+	}
+	a.respond(w, logging.Warn, code, "%s", string(response))
+}
+
 func (a *APIController) apiSetup(ctx *command.Context, cmdName command.Name) error {
 	pull := ctx.Pull
 	baseRepo := ctx.Pull.BaseRepo
@@ -236,7 +448,7 @@ func (a *APIController) apiSetup(ctx *command.Context, cmdName command.Name) err
 }
 
 func (a *APIController) apiPlan(request *APIRequest, ctx *command.Context) (*command.Result, error) {
-	cmds, cc, err := request.getCommands(ctx, a.ProjectCommandBuilder.BuildPlanCommands)
+	cmds, cc, err := request.getCommands(ctx, command.Plan, a.ProjectCommandBuilder.BuildPlanCommands)
 	if err != nil {
 		return nil, err
 	}
@@ -270,7 +482,8 @@ func (a *APIController) apiPlan(request *APIRequest, ctx *command.Context) (*com
 	for i, cmd := range cmds {
 		err = a.PreWorkflowHooksCommandRunner.RunPreHooks(ctx, cc[i])
 		if err != nil {
-			if a.FailOnPreWorkflowHookError {
+			var abortErr *runtime.PreWorkflowHookAbortError
+			if a.FailOnPreWorkflowHookError || errors.As(err, &abortErr) {
 				return nil, err
 			}
 		}
@@ -283,8 +496,32 @@ func (a *APIController) apiPlan(request *APIRequest, ctx *command.Context) (*com
 	return &command.Result{ProjectResults: projectResults}, nil
 }
 
+func (a *APIController) apiDrift(request *APIRequest, ctx *command.Context) (*command.Result, error) {
+	cmds, cc, err := request.getCommands(ctx, command.Drift, a.ProjectCommandBuilder.BuildDriftCommands)
+	if err != nil {
+		return nil, err
+	}
+
+	var projectResults []command.ProjectResult
+	for i, cmd := range cmds {
+		err = a.PreWorkflowHooksCommandRunner.RunPreHooks(ctx, cc[i])
+		if err != nil {
+			var abortErr *runtime.PreWorkflowHookAbortError
+			if a.FailOnPreWorkflowHookError || errors.As(err, &abortErr) {
+				return nil, err
+			}
+		}
+
+		res := a.ProjectDriftCommandRunner.Drift(cmd)
+		projectResults = append(projectResults, res)
+
+		a.PostWorkflowHooksCommandRunner.RunPostHooks(ctx, cc[i]) // nolint: errcheck
+	}
+	return &command.Result{ProjectResults: projectResults}, nil
+}
+
 func (a *APIController) apiApply(request *APIRequest, ctx *command.Context) (*command.Result, error) {
-	cmds, cc, err := request.getCommands(ctx, a.ProjectCommandBuilder.BuildApplyCommands)
+	cmds, cc, err := request.getCommands(ctx, command.Apply, a.ProjectCommandBuilder.BuildApplyCommands)
 	if err != nil {
 		return nil, err
 	}
@@ -318,7 +555,8 @@ func (a *APIController) apiApply(request *APIRequest, ctx *command.Context) (*co
 	for i, cmd := range cmds {
 		err = a.PreWorkflowHooksCommandRunner.RunPreHooks(ctx, cc[i])
 		if err != nil {
-			if a.FailOnPreWorkflowHookError {
+			var abortErr *runtime.PreWorkflowHookAbortError
+			if a.FailOnPreWorkflowHookError || errors.As(err, &abortErr) {
 				return nil, err
 			}
 		}
@@ -331,15 +569,36 @@ func (a *APIController) apiApply(request *APIRequest, ctx *command.Context) (*co
 	return &command.Result{ProjectResults: projectResults}, nil
 }
 
-func (a *APIController) apiParseAndValidate(r *http.Request) (*APIRequest, *command.Context, int, error) {
+func (a *APIController) apiCheckSecret(r *http.Request) (int, error) {
 	if len(a.APISecret) == 0 {
-		return nil, nil, http.StatusBadRequest, fmt.Errorf("ignoring request since API is disabled")
+		return http.StatusBadRequest, fmt.Errorf("ignoring request since API is disabled")
 	}
 
 	// Validate the secret token
 	secret := r.Header.Get(atlantisTokenHeader)
 	if secret != string(a.APISecret) {
-		return nil, nil, http.StatusUnauthorized, fmt.Errorf("header %s did not match expected secret", atlantisTokenHeader)
+		return http.StatusUnauthorized, fmt.Errorf("header %s did not match expected secret", atlantisTokenHeader)
+	}
+	return http.StatusOK, nil
+}
+
+// apiQueryInt returns the non-negative integer query parameter key, or 0 if
+// it isn't set.
+func apiQueryInt(r *http.Request, key string) (int, error) {
+	value := r.URL.Query().Get(key)
+	if value == "" {
+		return 0, nil
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil || i < 0 {
+		return 0, fmt.Errorf("%s must be a non-negative integer", key)
+	}
+	return i, nil
+}
+
+func (a *APIController) apiParseAndValidate(r *http.Request) (*APIRequest, *command.Context, int, error) {
+	if code, err := a.apiCheckSecret(r); err != nil {
+		return nil, nil, code, err
 	}
 
 	// Parse the JSON payload
@@ -355,25 +614,12 @@ func (a *APIController) apiParseAndValidate(r *http.Request) (*APIRequest, *comm
 		return nil, nil, http.StatusBadRequest, fmt.Errorf("request %q is missing fields", string(bytes))
 	}
 
-	VCSHostType, err := models.NewVCSHostType(request.Type)
+	baseRepo, code, err := a.apiParseRepo(request.Type, request.Repository)
 	if err != nil {
-		return nil, nil, http.StatusBadRequest, err
-	}
-	cloneURL, err := a.VCSClient.GetCloneURL(a.Logger, VCSHostType, request.Repository)
-	if err != nil {
-		return nil, nil, http.StatusInternalServerError, err
-	}
-
-	baseRepo, err := a.Parser.ParseAPIPlanRequest(VCSHostType, request.Repository, cloneURL)
-	if err != nil {
-		return nil, nil, http.StatusBadRequest, fmt.Errorf("failed to parse request: %v", err)
-	}
-
-	// Check if the repo is allowlisted
-	if !a.RepoAllowlistChecker.IsAllowlisted(baseRepo.FullName, baseRepo.VCSHost.Hostname) {
-		return nil, nil, http.StatusForbidden, fmt.Errorf("repo not allowlisted")
+		return nil, nil, code, err
 	}
 
+	runID := uuid.NewString()
 	return &request, &command.Context{
 		HeadRepo: baseRepo,
 		Pull: models.PullRequest{
@@ -384,11 +630,36 @@ func (a *APIController) apiParseAndValidate(r *http.Request) (*APIRequest, *comm
 			BaseRepo:   baseRepo,
 		},
 		Scope: a.Scope,
-		Log:   a.Logger,
+		Log:   a.Logger.With("run-id", runID),
 		API:   true,
+		RunID: runID,
 	}, http.StatusOK, nil
 }
 
+// apiParseRepo returns the allowlisted repo repository of VCS host type
+// vcsType.
+func (a *APIController) apiParseRepo(vcsType string, repository string) (models.Repo, int, error) {
+	VCSHostType, err := models.NewVCSHostType(vcsType)
+	if err != nil {
+		return models.Repo{}, http.StatusBadRequest, err
+	}
+	cloneURL, err := a.VCSClient.GetCloneURL(a.Logger, VCSHostType, repository)
+	if err != nil {
+		return models.Repo{}, http.StatusInternalServerError, err
+	}
+
+	baseRepo, err := a.Parser.ParseAPIPlanRequest(VCSHostType, repository, cloneURL)
+	if err != nil {
+		return models.Repo{}, http.StatusBadRequest, fmt.Errorf("failed to parse request: %v", err)
+	}
+
+	// Check if the repo is allowlisted
+	if !a.RepoAllowlistChecker.IsAllowlisted(baseRepo.FullName, baseRepo.VCSHost.Hostname) {
+		return models.Repo{}, http.StatusForbidden, fmt.Errorf("repo not allowlisted")
+	}
+	return baseRepo, http.StatusOK, nil
+}
+
 func (a *APIController) respond(w http.ResponseWriter, lvl logging.LogLevel, responseCode int, format string, args ...interface{}) {
 	response := fmt.Sprintf(format, args...)
 	a.Logger.Log(lvl, response)
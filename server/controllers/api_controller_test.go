@@ -14,7 +14,10 @@ import (
 
 	. "github.com/petergtz/pegomock/v4"
 	"github.com/runatlantis/atlantis/server/controllers"
+	dbmocks "github.com/runatlantis/atlantis/server/core/db/mocks"
 	. "github.com/runatlantis/atlantis/server/core/locking/mocks"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	runtime_mocks "github.com/runatlantis/atlantis/server/core/runtime/models/mocks"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/command"
 	. "github.com/runatlantis/atlantis/server/events/mocks"
@@ -109,6 +112,10 @@ func TestAPIController_Plan(t *testing.T) {
 
 		expectedCalls += len(c.projects)
 		expectedCalls += len(c.paths)
+		// A pull request without projects or paths runs all its projects.
+		if c.pr != 0 && len(c.projects) == 0 && len(c.paths) == 0 {
+			expectedCalls++
+		}
 	}
 
 	projectCommandBuilder.VerifyWasCalled(Times(expectedCalls)).BuildPlanCommands(Any[*command.Context](), Any[*events.CommentCommand]())
@@ -196,6 +203,10 @@ func TestAPIController_Apply(t *testing.T) {
 
 		expectedCalls += len(c.projects)
 		expectedCalls += len(c.paths)
+		// A pull request without projects or paths runs all its projects.
+		if c.pr != 0 && len(c.projects) == 0 && len(c.paths) == 0 {
+			expectedCalls++
+		}
 	}
 
 	projectCommandBuilder.VerifyWasCalled(Times(expectedCalls)).BuildApplyCommands(Any[*command.Context](), Any[*events.CommentCommand]())
@@ -203,6 +214,74 @@ func TestAPIController_Apply(t *testing.T) {
 	projectCommandRunner.VerifyWasCalled(Times(expectedCalls)).Apply(Any[command.ProjectContext]())
 }
 
+func TestAPIController_Drift(t *testing.T) {
+	ac, projectCommandBuilder, projectCommandRunner := setup(t)
+
+	body, _ := json.Marshal(controllers.APIRequest{
+		Repository: "Repo",
+		Ref:        "main",
+		Type:       "Gitlab",
+		Projects:   []string{"default"},
+	})
+	req, _ := http.NewRequest("POST", "", bytes.NewBuffer(body))
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	w := httptest.NewRecorder()
+	ac.Drift(w, req)
+	ResponseContains(t, w, http.StatusOK, `"Drifted":true`)
+
+	projectCommandBuilder.VerifyWasCalledOnce().BuildDriftCommands(Any[*command.Context](), Any[*events.CommentCommand]())
+	projectCommandRunner.VerifyWasCalledOnce().Drift(Any[command.ProjectContext]())
+	projectCommandRunner.VerifyWasCalled(Never()).Plan(Any[command.ProjectContext]())
+}
+
+func TestAPIController_Plan_Locks(t *testing.T) {
+	cases := []struct {
+		description string
+		pr          int
+		expUnlocked bool
+	}{
+		{
+			description: "without pull request",
+			expUnlocked: true,
+		},
+		{
+			description: "pull request keeps its locks",
+			pr:          2,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			ac, projectCommandBuilder, _ := setup(t)
+			body, _ := json.Marshal(controllers.APIRequest{
+				Repository: "Repo",
+				Ref:        "main",
+				Type:       "Gitlab",
+				PR:         c.pr,
+				Projects:   []string{"default"},
+			})
+			req, _ := http.NewRequest("POST", "", bytes.NewBuffer(body))
+			req.Header.Set(atlantisTokenHeader, atlantisToken)
+			w := httptest.NewRecorder()
+			ac.Plan(w, req)
+			ResponseContains(t, w, http.StatusOK, "")
+
+			var result controllers.APIResult
+			Ok(t, json.Unmarshal(w.Body.Bytes(), &result))
+			Assert(t, result.RunID != "", "expected a run ID")
+			Equals(t, 1, len(result.ProjectResults))
+
+			ctx, _ := projectCommandBuilder.VerifyWasCalledOnce().BuildPlanCommands(Any[*command.Context](), Any[*events.CommentCommand]()).GetCapturedArguments()
+			Equals(t, result.RunID, ctx.RunID)
+			locker := ac.Locker.(*MockLocker)
+			if c.expUnlocked {
+				locker.VerifyWasCalledOnce().UnlockByPull(Any[string](), Any[int]())
+			} else {
+				locker.VerifyWasCalled(Never()).UnlockByPull(Any[string](), Any[int]())
+			}
+		})
+	}
+}
+
 func TestAPIController_ListLocks(t *testing.T) {
 	ac, _, _ := setup(t)
 	time := time.Now()
@@ -218,7 +297,7 @@ func TestAPIController_ListLocks(t *testing.T) {
 			Workspace:       "default",
 			Time:            time,
 		},
-	},
+	}, 1,
 	}
 	mockLock := models.ProjectLock{
 		Project:   models.Project{ProjectName: "terraform", RepoFullName: "owner/repo", Path: "/path"},
@@ -233,6 +312,7 @@ func TestAPIController_ListLocks(t *testing.T) {
 	When(ac.Locker.List()).ThenReturn(mockLocks, nil)
 
 	req, _ := http.NewRequest("GET", "", nil)
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
 	w := httptest.NewRecorder()
 	ac.ListLocks(w, req)
 	response, _ := io.ReadAll(w.Result().Body)
@@ -250,6 +330,7 @@ func TestAPIController_ListLocksEmpty(t *testing.T) {
 	When(ac.Locker.List()).ThenReturn(mockLocks, nil)
 
 	req, _ := http.NewRequest("GET", "", nil)
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
 	w := httptest.NewRecorder()
 	ac.ListLocks(w, req)
 	response, _ := io.ReadAll(w.Result().Body)
@@ -259,6 +340,193 @@ func TestAPIController_ListLocksEmpty(t *testing.T) {
 	Equals(t, expected, result)
 }
 
+func TestAPIController_ListLocksPaged(t *testing.T) {
+	ac, _, _ := setup(t)
+	mockLocks := map[string]models.ProjectLock{
+		"owner/repo/c/default": {Workspace: "c"},
+		"owner/repo/a/default": {Workspace: "a"},
+		"owner/repo/d/default": {Workspace: "d"},
+		"owner/repo/b/default": {Workspace: "b"},
+	}
+	When(ac.Locker.List()).ThenReturn(mockLocks, nil)
+
+	cases := []struct {
+		query   string
+		expCode int
+		expLock []string
+	}{
+		{query: "", expCode: http.StatusOK, expLock: []string{"a", "b", "c", "d"}},
+		{query: "?limit=2", expCode: http.StatusOK, expLock: []string{"a", "b"}},
+		{query: "?limit=2&offset=3", expCode: http.StatusOK, expLock: []string{"d"}},
+		{query: "?offset=5", expCode: http.StatusOK, expLock: nil},
+		{query: "?limit=-1", expCode: http.StatusBadRequest},
+		{query: "?offset=abc", expCode: http.StatusBadRequest},
+	}
+	for _, c := range cases {
+		t.Run(c.query, func(t *testing.T) {
+			req, _ := http.NewRequest("GET", "/api/locks"+c.query, nil)
+			req.Header.Set(atlantisTokenHeader, atlantisToken)
+			w := httptest.NewRecorder()
+			ac.ListLocks(w, req)
+			Equals(t, c.expCode, w.Result().StatusCode)
+			if c.expCode != http.StatusOK {
+				return
+			}
+			response, _ := io.ReadAll(w.Result().Body)
+			var result controllers.ListLocksResult
+			Ok(t, json.Unmarshal(response, &result))
+			Equals(t, 4, result.Total)
+			var workspaces []string
+			for _, lock := range result.Locks {
+				workspaces = append(workspaces, lock.Workspace)
+			}
+			Equals(t, c.expLock, workspaces)
+		})
+	}
+}
+
+func TestAPIController_ListLocksUnauthorized(t *testing.T) {
+	ac, _, _ := setup(t)
+	req, _ := http.NewRequest("GET", "/api/locks", nil)
+	req.Header.Set(atlantisTokenHeader, "wrong")
+	w := httptest.NewRecorder()
+	ac.ListLocks(w, req)
+	Equals(t, http.StatusUnauthorized, w.Result().StatusCode)
+	ac.Locker.(*MockLocker).VerifyWasCalled(Never()).List()
+}
+
+func TestAPIController_Approve(t *testing.T) {
+	ac, _, _ := setup(t)
+	ac.Approvals = runtime.NewApprovalRegistry()
+
+	// The notify command hands the token to the external system.
+	tokens := make(chan string, 1)
+	mockExec := runtime_mocks.NewMockExec()
+	When(mockExec.CombinedOutput(Any[[]string](), Any[map[string]string](), Any[string]())).Then(func(params []Param) ReturnValues {
+		tokens <- params[1].(map[string]string)["ATLANTIS_APPROVAL_TOKEN"]
+		return ReturnValues{"", nil}
+	})
+	stepRunner := runtime.AwaitApprovalStepRunner{Registry: ac.Approvals, Exec: mockExec}
+	type stepResult struct {
+		out string
+		err error
+	}
+	results := make(chan stepResult, 1)
+	go func() {
+		out, err := stepRunner.Run(command.ProjectContext{Log: logging.NewNoopLogger(t)}, "./notify.sh", time.Minute, t.TempDir(), map[string]string{})
+		results <- stepResult{out, err}
+	}()
+
+	body, _ := json.Marshal(controllers.APIApprovalRequest{
+		Token:    <-tokens,
+		Approved: true,
+		Reason:   "CHG-123",
+	})
+	req, _ := http.NewRequest("POST", "", bytes.NewBuffer(body))
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	w := httptest.NewRecorder()
+	ac.Approve(w, req)
+	ResponseContains(t, w, http.StatusOK, "")
+
+	res := <-results
+	Ok(t, res.err)
+	Equals(t, "Approved: CHG-123", res.out)
+
+	// Tokens can only be used once.
+	req, _ = http.NewRequest("POST", "", bytes.NewBuffer(body))
+	req.Header.Set(atlantisTokenHeader, atlantisToken)
+	w = httptest.NewRecorder()
+	ac.Approve(w, req)
+	ResponseContains(t, w, http.StatusNotFound, "no step is waiting for approval with this token")
+}
+
+func TestAPIController_ApproveUnauthorized(t *testing.T) {
+	ac, _, _ := setup(t)
+	ac.Approvals = runtime.NewApprovalRegistry()
+	body, _ := json.Marshal(controllers.APIApprovalRequest{Token: "token", Approved: true})
+	req, _ := http.NewRequest("POST", "", bytes.NewBuffer(body))
+	req.Header.Set(atlantisTokenHeader, "wrong")
+	w := httptest.NewRecorder()
+	ac.Approve(w, req)
+	ResponseContains(t, w, http.StatusUnauthorized, "did not match expected secret")
+}
+
+func TestAPIController_ApproveExternal(t *testing.T) {
+	repo := models.Repo{FullName: "runatlantis/atlantis"}
+	pull := models.PullRequest{Num: 1, BaseRepo: repo}
+	cases := []struct {
+		description string
+		request     controllers.APIApprovalRequest
+		status      *models.PullStatus
+		expCode     int
+		expResponse string
+	}{
+		{
+			description: "approved",
+			request:     controllers.APIApprovalRequest{Repository: repo.FullName, Type: "Github", PR: 1, Approved: true, Approver: "CHG-123", Projects: []string{"prod"}},
+			status:      &models.PullStatus{Pull: models.PullRequest{HeadCommit: "sha"}},
+			expCode:     http.StatusOK,
+		},
+		{
+			description: "approved commit",
+			request:     controllers.APIApprovalRequest{Repository: repo.FullName, Type: "Github", PR: 1, Commit: "sha", Approved: true, Approver: "CHG-123", Projects: []string{"prod"}},
+			status:      &models.PullStatus{Pull: models.PullRequest{HeadCommit: "sha"}},
+			expCode:     http.StatusOK,
+		},
+		{
+			description: "outdated commit",
+			request:     controllers.APIApprovalRequest{Repository: repo.FullName, Type: "Github", PR: 1, Commit: "oldsha", Approved: true, Approver: "CHG-123"},
+			status:      &models.PullStatus{Pull: models.PullRequest{HeadCommit: "sha"}},
+			expCode:     http.StatusConflict,
+			expResponse: "commit oldsha is not the latest planned commit sha of pull request runatlantis/atlantis#1",
+		},
+		{
+			description: "not planned",
+			request:     controllers.APIApprovalRequest{Repository: repo.FullName, Type: "Github", PR: 1, Approved: true, Approver: "CHG-123"},
+			expCode:     http.StatusNotFound,
+			expResponse: "pull request runatlantis/atlantis#1 has not been planned",
+		},
+		{
+			description: "rejected",
+			request:     controllers.APIApprovalRequest{Repository: repo.FullName, Type: "Github", PR: 1, Approver: "CHG-123"},
+			expCode:     http.StatusBadRequest,
+			expResponse: "only approvals can be recorded",
+		},
+		{
+			description: "missing approver",
+			request:     controllers.APIApprovalRequest{Repository: repo.FullName, Type: "Github", PR: 1, Approved: true},
+			expCode:     http.StatusBadRequest,
+			expResponse: "request is missing fields",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			ac, _, _ := setup(t)
+			database := dbmocks.NewMockDatabase()
+			ac.Database = database
+			When(ac.Parser.(*MockEventParsing).ParseAPIPlanRequest(Any[models.VCSHostType](), Any[string](), Any[string]())).ThenReturn(repo, nil)
+			When(database.GetPullStatus(Eq(pull))).ThenReturn(c.status, nil)
+
+			body, _ := json.Marshal(c.request)
+			req, _ := http.NewRequest("POST", "", bytes.NewBuffer(body))
+			req.Header.Set(atlantisTokenHeader, atlantisToken)
+			w := httptest.NewRecorder()
+			ac.Approve(w, req)
+			ResponseContains(t, w, c.expCode, c.expResponse)
+
+			if c.expCode != http.StatusOK {
+				database.VerifyWasCalled(Never()).AddExternalApproval(Any[models.PullRequest](), Any[models.ExternalApproval]())
+				return
+			}
+			gotPull, approval := database.VerifyWasCalledOnce().AddExternalApproval(Any[models.PullRequest](), Any[models.ExternalApproval]()).GetCapturedArguments()
+			Equals(t, pull, gotPull)
+			Equals(t, "sha", approval.HeadCommit)
+			Equals(t, []string{"prod"}, approval.Projects)
+			Equals(t, "CHG-123", approval.Approver)
+		})
+	}
+}
+
 func setup(t *testing.T) (controllers.APIController, *MockProjectCommandBuilder, *MockProjectCommandRunner) {
 	RegisterMockTestingT(t)
 	locker := NewMockLocker()
@@ -283,6 +551,10 @@ func setup(t *testing.T) (controllers.APIController, *MockProjectCommandBuilder,
 		ThenReturn([]command.ProjectContext{{
 			CommandName: command.Apply,
 		}}, nil)
+	When(projectCommandBuilder.BuildDriftCommands(Any[*command.Context](), Any[*events.CommentCommand]())).
+		ThenReturn([]command.ProjectContext{{
+			CommandName: command.Drift,
+		}}, nil)
 
 	projectCommandRunner := NewMockProjectCommandRunner()
 	When(projectCommandRunner.Plan(Any[command.ProjectContext]())).ThenReturn(command.ProjectResult{
@@ -291,6 +563,9 @@ func setup(t *testing.T) (controllers.APIController, *MockProjectCommandBuilder,
 	When(projectCommandRunner.Apply(Any[command.ProjectContext]())).ThenReturn(command.ProjectResult{
 		ApplySuccess: "success",
 	})
+	When(projectCommandRunner.Drift(Any[command.ProjectContext]())).ThenReturn(command.ProjectResult{
+		DriftSuccess: &models.DriftSuccess{Drifted: true},
+	})
 
 	preWorkflowHooksCommandRunner := NewMockPreWorkflowHooksCommandRunner()
 
@@ -313,6 +588,7 @@ func setup(t *testing.T) (controllers.APIController, *MockProjectCommandBuilder,
 		ProjectCommandBuilder:          projectCommandBuilder,
 		ProjectPlanCommandRunner:       projectCommandRunner,
 		ProjectApplyCommandRunner:      projectCommandRunner,
+		ProjectDriftCommandRunner:      projectCommandRunner,
 		PreWorkflowHooksCommandRunner:  preWorkflowHooksCommandRunner,
 		PostWorkflowHooksCommandRunner: postWorkflowHooksCommandRunner,
 		VCSClient:                      vcsClient,
@@ -27,6 +27,7 @@ import (
 	"github.com/microcosm-cc/bluemonday"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketcloud"
@@ -85,7 +86,10 @@ type VCSEventsController struct {
 	// startup to support.
 	SupportedVCSHosts []models.VCSHostType `validate:"required"`
 	VCSClient         vcs.Client           `validate:"required"`
-	TestingMode       bool
+	// CommitStatusUpdater sets the statuses of GitHub merge queue commits. If
+	// nil, merge group events are ignored.
+	CommitStatusUpdater events.CommitStatusUpdater
+	TestingMode         bool
 	// BitbucketWebhookSecret is the secret added to this webhook via the Bitbucket
 	// UI that identifies this call as coming from Bitbucket. If empty, no
 	// request validation is done.
@@ -199,6 +203,9 @@ func (e *VCSEventsController) handleGithubPost(w http.ResponseWriter, r *http.Re
 		resp = e.HandleGithubPullRequestEvent(logger, event, githubReqID)
 		scope = scope.SubScope(fmt.Sprintf("pr_%s", *event.Action))
 		scope = vcs.SetGitScopeTags(scope, event.GetRepo().GetFullName(), event.GetNumber())
+	case *github.MergeGroupEvent:
+		resp = e.HandleGithubMergeGroupEvent(logger, event, githubReqID)
+		scope = scope.SubScope(fmt.Sprintf("merge_group_%s", event.GetAction()))
 	default:
 		resp = HTTPResponse{
 			body: fmt.Sprintf("Ignoring unsupported event %s", githubReqID),
@@ -273,7 +280,7 @@ func (e *VCSEventsController) handleBitbucketServerPost(w http.ResponseWriter, r
 		}
 	}
 	switch eventType {
-	case bitbucketserver.PullCreatedHeader, bitbucketserver.PullFromRefUpdatedHeader, bitbucketserver.PullMergedHeader, bitbucketserver.PullDeclinedHeader, bitbucketserver.PullDeletedHeader:
+	case bitbucketserver.PullCreatedHeader, bitbucketserver.PullFromRefUpdatedHeader, bitbucketserver.PullModifiedHeader, bitbucketserver.PullMergedHeader, bitbucketserver.PullDeclinedHeader, bitbucketserver.PullDeletedHeader:
 		e.Logger.Debug("handling as pull request state changed event")
 		e.handleBitbucketServerPullRequestEvent(e.Logger, w, eventType, body, reqID)
 		return
@@ -505,12 +512,11 @@ func (e *VCSEventsController) handleBitbucketCloudPullRequestEvent(logger loggin
 }
 
 func (e *VCSEventsController) handleBitbucketServerPullRequestEvent(logger logging.SimpleLogging, w http.ResponseWriter, eventType string, body []byte, reqID string) {
-	pull, baseRepo, headRepo, user, err := e.Parser.ParseBitbucketServerPullEvent(body)
+	pull, pullEventType, baseRepo, headRepo, user, err := e.Parser.ParseBitbucketServerPullEvent(eventType, body)
 	if err != nil {
 		e.respond(w, logging.Error, http.StatusBadRequest, "Error parsing pull data: %s %s=%s", err, bitbucketServerRequestIDHeader, reqID)
 		return
 	}
-	pullEventType := e.Parser.GetBitbucketServerPullEventType(eventType)
 
 	// Annotate logger with repo and pull/merge request number.
 	logger = logger.With(
@@ -560,6 +566,78 @@ func (e *VCSEventsController) HandleGithubPullRequestEvent(logger logging.Simple
 	return e.handlePullRequestEvent(logger, baseRepo, headRepo, pull, user, pullEventType)
 }
 
+// HandleGithubMergeGroupEvent handles events from GitHub's merge queue. The
+// queue tests a temporary branch that merges the pull request into the base
+// branch. Since the pull request could only be queued once its required checks
+// passed, instead of planning that branch again we mark the plan and apply
+// statuses of its commit as successful so they don't block the queue.
+// It's exported to make testing easier.
+func (e *VCSEventsController) HandleGithubMergeGroupEvent(logger logging.SimpleLogging, event *github.MergeGroupEvent, githubReqID string) HTTPResponse {
+	if event.GetAction() != "checks_requested" || e.CommitStatusUpdater == nil {
+		return HTTPResponse{
+			body: fmt.Sprintf("Ignoring merge group %q event %s", event.GetAction(), githubReqID),
+		}
+	}
+	headRef := event.GetMergeGroup().GetHeadRef()
+	pullNum, ok := events.ParseGithubMergeQueueBranch(headRef)
+	if !ok {
+		return HTTPResponse{
+			body: fmt.Sprintf("Ignoring merge group event for unknown branch %q %s", headRef, githubReqID),
+		}
+	}
+	baseRepo, err := e.Parser.ParseGithubRepo(event.GetRepo())
+	if err != nil {
+		wrapped := errors.Wrapf(err, "Error parsing repo data: %s", githubReqID)
+		return HTTPResponse{
+			body: wrapped.Error(),
+			err: HTTPError{
+				code: http.StatusBadRequest,
+				err:  wrapped,
+			},
+		}
+	}
+	if !e.RepoAllowlistChecker.IsAllowlisted(baseRepo.FullName, baseRepo.VCSHost.Hostname) {
+		err := errors.Errorf("Merge group event from non-allowlisted repo '%s/%s'", baseRepo.VCSHost.Hostname, baseRepo.FullName)
+		return HTTPResponse{
+			body: err.Error(),
+			err: HTTPError{
+				code:       http.StatusForbidden,
+				err:        err,
+				isSilenced: e.SilenceAllowlistErrors,
+			},
+		}
+	}
+
+	logger = logger.With(
+		"repo", baseRepo.FullName,
+		"pull", strconv.Itoa(pullNum),
+	)
+	logger.Info("Handling GitHub merge group event for branch %q", headRef)
+	pull := models.PullRequest{
+		Num:        pullNum,
+		HeadBranch: strings.TrimPrefix(headRef, "refs/heads/"),
+		HeadCommit: event.GetMergeGroup().GetHeadSHA(),
+		BaseRepo:   baseRepo,
+		BaseBranch: strings.TrimPrefix(event.GetMergeGroup().GetBaseRef(), "refs/heads/"),
+		State:      models.OpenPullState,
+	}
+	for _, cmdName := range []command.Name{command.Plan, command.Apply} {
+		if err := e.CommitStatusUpdater.UpdateCombined(logger, baseRepo, pull, models.SuccessCommitStatus, cmdName); err != nil {
+			wrapped := errors.Wrapf(err, "updating %s status of merge group commit %s", cmdName.String(), pull.HeadCommit)
+			return HTTPResponse{
+				body: wrapped.Error(),
+				err: HTTPError{
+					code: http.StatusInternalServerError,
+					err:  wrapped,
+				},
+			}
+		}
+	}
+	return HTTPResponse{
+		body: fmt.Sprintf("Updated merge group statuses for pull request #%d", pullNum),
+	}
+}
+
 func (e *VCSEventsController) handlePullRequestEvent(logger logging.SimpleLogging, baseRepo models.Repo, headRepo models.Repo, pull models.PullRequest, user models.User, eventType models.PullRequestEventType) HTTPResponse {
 	if !e.RepoAllowlistChecker.IsAllowlisted(baseRepo.FullName, baseRepo.VCSHost.Hostname) {
 		// If the repo isn't allowlisted and we receive an opened pull request
@@ -673,7 +751,7 @@ func (e *VCSEventsController) HandleGitlabCommentEvent(w http.ResponseWriter, ev
 func (e *VCSEventsController) handleCommentEvent(logger logging.SimpleLogging, baseRepo models.Repo, maybeHeadRepo *models.Repo, maybePull *models.PullRequest, user models.User, pullNum int, comment string, commentID int64, vcsHost models.VCSHostType) HTTPResponse {
 	logger = logger.WithHistory(
 		"repo", baseRepo.FullName,
-		"pull", pullNum,
+		"pull", strconv.Itoa(pullNum),
 	)
 
 	parseResult := e.CommentParser.Parse(comment, vcsHost)
@@ -722,7 +800,7 @@ func (e *VCSEventsController) handleCommentEvent(logger logging.SimpleLogging, b
 	// We do this here rather than earlier because we need access to the pull
 	// variable to comment back on the pull request.
 	if parseResult.CommentResponse != "" {
-		if err := e.VCSClient.CreateComment(logger, baseRepo, pullNum, parseResult.CommentResponse, ""); err != nil {
+		if _, err := e.VCSClient.CreateComment(logger, baseRepo, pullNum, parseResult.CommentResponse, ""); err != nil {
 			logger.Err("Unable to comment on pull request: %s", err)
 		}
 		return HTTPResponse{
@@ -910,7 +988,7 @@ func (e *VCSEventsController) commentNotAllowlisted(baseRepo models.Repo, pullNu
 	}
 
 	errMsg := "```\nError: This repo is not allowlisted for Atlantis.\n```"
-	if err := e.VCSClient.CreateComment(e.Logger, baseRepo, pullNum, errMsg, ""); err != nil {
+	if _, err := e.VCSClient.CreateComment(e.Logger, baseRepo, pullNum, errMsg, ""); err != nil {
 		e.Logger.Err("unable to comment on pull request: %s", err)
 	}
 }
@@ -1453,6 +1453,7 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 		false,
 		false,
 		"auto",
+		"",
 		statsScope,
 		terraformClient,
 	)
@@ -1461,7 +1462,7 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 
 	Ok(t, err)
 
-	conftextExec := policy.NewConfTestExecutorWorkflow(logger, binDir, mock_policy.NewMockDownloader())
+	conftextExec := policy.NewConfTestExecutorWorkflow(logger, binDir, mock_policy.NewMockDownloader(), "")
 
 	// swapping out version cache to something that always returns local conftest
 	// binary
@@ -1490,6 +1491,7 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 			defaultTFVersion,
 			statusUpdater,
 			asyncTfExec,
+			false,
 		),
 		ShowStepRunner:        showStepRunner,
 		PolicyCheckStepRunner: policyCheckRunner,
@@ -1530,6 +1532,7 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 			"atlantis",                       // executableName
 			false,                            // hideUnchangedPlanComments
 			opt.userConfig.QuietPolicyChecks, // quietPolicyChecks
+			0,                                // maxCommentOutputSize
 		),
 	}
 
@@ -1570,6 +1573,7 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 		discardApprovalOnPlan,
 		e2ePullReqStatusFetcher,
 		false,
+		0,
 	)
 
 	applyCommandRunner := events.NewApplyCommandRunner(
@@ -1587,6 +1591,8 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 		silenceNoProjects,
 		false,
 		e2ePullReqStatusFetcher,
+		false,
+		drainer,
 	)
 
 	approvePoliciesCommandRunner := events.NewApprovePoliciesCommandRunner(
@@ -1671,6 +1677,8 @@ func setupE2E(t *testing.T, repoDir string, opt setupOption) (events_controllers
 			Database:                 database,
 			PullClosedTemplate:       &events.PullClosedEventTemplate{},
 			LogStreamResourceCleaner: projectCmdOutputHandler,
+			TerraformClient:          terraformClient,
+			TerraformDistribution:    defaultTFDistribution,
 		},
 		Logger:                       logger,
 		Scope:                        statsScope,
@@ -536,6 +536,50 @@ func TestPost_GithubPullRequestUnsupportedAction(t *testing.T) {
 	ResponseContains(t, w, http.StatusOK, "Ignoring non-actionable pull request event")
 }
 
+func TestPost_GithubMergeGroup(t *testing.T) {
+	t.Log("when the event is a github merge group we mark its commit's statuses as successful instead of planning")
+	e, v, _, _, p, cr, _, _, _ := setup(t)
+	statusUpdater := emocks.NewMockCommitStatusUpdater()
+	e.CommitStatusUpdater = statusUpdater
+	req, _ := http.NewRequest("GET", "", bytes.NewBuffer(nil))
+	req.Header.Set(githubHeader, "merge_group")
+
+	event := `{"action": "checks_requested", "merge_group": {"head_sha": "0123abcd", "head_ref": "refs/heads/gh-readonly-queue/main/pr-1-4567cdef", "base_ref": "refs/heads/main"}}`
+	When(v.Validate(req, secret)).ThenReturn([]byte(event), nil)
+	repo := models.Repo{FullName: "owner/repo", VCSHost: models.VCSHost{Hostname: "github.com", Type: models.Github}}
+	When(p.ParseGithubRepo(Any[*github.Repository]())).ThenReturn(repo, nil)
+	w := httptest.NewRecorder()
+	e.Post(w, req)
+	ResponseContains(t, w, http.StatusOK, "Updated merge group statuses for pull request #1")
+
+	expPull := models.PullRequest{
+		Num:        1,
+		HeadBranch: "gh-readonly-queue/main/pr-1-4567cdef",
+		HeadCommit: "0123abcd",
+		BaseRepo:   repo,
+		BaseBranch: "main",
+		State:      models.OpenPullState,
+	}
+	statusUpdater.VerifyWasCalledOnce().UpdateCombined(Any[logging.SimpleLogging](), Eq(repo), Eq(expPull), Eq(models.SuccessCommitStatus), Eq(command.Plan))
+	statusUpdater.VerifyWasCalledOnce().UpdateCombined(Any[logging.SimpleLogging](), Eq(repo), Eq(expPull), Eq(models.SuccessCommitStatus), Eq(command.Apply))
+	cr.VerifyWasCalled(Never()).RunAutoplanCommand(Any[models.Repo](), Any[models.Repo](), Any[models.PullRequest](), Any[models.User]())
+}
+
+func TestPost_GithubMergeGroupDestroyed(t *testing.T) {
+	e, v, _, _, _, _, _, _, _ := setup(t)
+	statusUpdater := emocks.NewMockCommitStatusUpdater()
+	e.CommitStatusUpdater = statusUpdater
+	req, _ := http.NewRequest("GET", "", bytes.NewBuffer(nil))
+	req.Header.Set(githubHeader, "merge_group")
+
+	event := `{"action": "destroyed", "reason": "merged", "merge_group": {"head_sha": "0123abcd", "head_ref": "refs/heads/gh-readonly-queue/main/pr-1-4567cdef"}}`
+	When(v.Validate(req, secret)).ThenReturn([]byte(event), nil)
+	w := httptest.NewRecorder()
+	e.Post(w, req)
+	ResponseContains(t, w, http.StatusOK, "Ignoring merge group \"destroyed\" event")
+	statusUpdater.VerifyWasCalled(Never()).UpdateCombined(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](), Any[models.CommitStatus](), Any[command.Name]())
+}
+
 func TestPost_GitlabMergeRequestUnsupportedAction(t *testing.T) {
 	t.Skip("relies too much on mocks, should use real event parser")
 	t.Log("when the event is a gitlab merge request to a non-allowlisted repo we return a 400")
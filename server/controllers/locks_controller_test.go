@@ -334,6 +334,7 @@ func TestDeleteLock_UpdateProjectStatus(t *testing.T) {
 			Workspace:  workspaceName,
 			RepoRelDir: projectPath,
 			Status:     models.DiscardedPlanStatus,
+			PlanStats:  &models.PlanSuccessStats{},
 		},
 	}, status.Projects)
 }
@@ -354,7 +355,7 @@ func TestDeleteLock_CommentFailed(t *testing.T) {
 	tmp := t.TempDir()
 	database, err := boltdb.New(tmp)
 	Ok(t, err)
-	When(cp.CreateComment(Any[logging.SimpleLogging](), Any[models.Repo](), Any[int](), Any[string](), Any[string]())).ThenReturn(errors.New("err"))
+	When(cp.CreateComment(Any[logging.SimpleLogging](), Any[models.Repo](), Any[int](), Any[string](), Any[string]())).ThenReturn("", errors.New("err"))
 	lc := controllers.LocksController{
 		DeleteLockCommand: dlc,
 		Logger:            logging.NewNoopLogger(t),
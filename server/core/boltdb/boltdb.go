@@ -262,13 +262,15 @@ func (b *BoltDB) UnlockByPull(repoFullName string, pullNum int) ([]models.Projec
 	err := b.db.View(func(tx *bolt.Tx) error {
 		c := tx.Bucket(b.locksBucketName).Cursor()
 
-		// we can use the repoFullName as a prefix search since that's the first part of the key
-		for k, v := c.Seek([]byte(repoFullName)); k != nil && bytes.HasPrefix(k, []byte(repoFullName)); k, v = c.Next() {
+		// we can use the repoFullName as a prefix search since that's the first part of the key,
+		// the prefix also matches other repos whose names start with it, ex. GitLab subgroups
+		prefix := []byte(repoFullName + "/")
+		for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
 			var lock models.ProjectLock
 			if err := json.Unmarshal(v, &lock); err != nil {
 				return errors.Wrapf(err, "deserializing lock at key %q", string(k))
 			}
-			if lock.Pull.Num == pullNum {
+			if lock.Project.RepoFullName == repoFullName && lock.Pull.Num == pullNum {
 				locks = append(locks, lock)
 			}
 		}
@@ -342,6 +344,9 @@ func (b *BoltDB) UpdatePullWithResults(pull models.PullRequest, newResults []com
 				Pull:     pull,
 				Projects: statuses,
 			}
+			if currStatus != nil {
+				newStatus.CreatedWorkspaces = currStatus.CreatedWorkspaces
+			}
 		} else {
 			// If there's an existing pull at the right commit then we have to
 			// merge our project results with the existing ones. We do a merge
@@ -361,6 +366,9 @@ func (b *BoltDB) UpdatePullWithResults(pull models.PullRequest, newResults []com
 						res.ProjectName == proj.ProjectName {
 
 						proj.Status = res.PlanStatus()
+						if res.Command == command.Plan {
+							proj.PlanStats = res.PlanStats()
+						}
 
 						// Updating only policy sets which are included in results; keeping the rest.
 						if len(proj.PolicyStatus) > 0 {
@@ -388,6 +396,12 @@ func (b *BoltDB) UpdatePullWithResults(pull models.PullRequest, newResults []com
 			}
 		}
 
+		for _, res := range newResults {
+			if ws := res.CreatedWorkspace(); ws != nil {
+				newStatus.AddCreatedWorkspace(*ws)
+			}
+		}
+
 		// Now, we overwrite the key with our new status.
 		return b.writePullToBucket(bucket, key, newStatus)
 	})
@@ -468,6 +482,31 @@ func (b *BoltDB) UpdateProjectStatus(pull models.PullRequest, workspace string,
 	return nil
 }
 
+// AddExternalApproval adds approval to pull's status. It does nothing if
+// there's no status for pull.
+func (b *BoltDB) AddExternalApproval(pull models.PullRequest, approval models.ExternalApproval) error {
+	key, err := b.pullKey(pull)
+	if err != nil {
+		return err
+	}
+	err = b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(b.pullsBucketName)
+		currStatus, err := b.getPullFromBucket(bucket, key)
+		if err != nil {
+			return err
+		}
+		if currStatus == nil {
+			return nil
+		}
+		currStatus.ExternalApprovals = append(currStatus.ExternalApprovals, approval)
+		return b.writePullToBucket(bucket, key, *currStatus)
+	})
+	if err != nil {
+		return errors.Wrap(err, "DB transaction failed")
+	}
+	return nil
+}
+
 func (b *BoltDB) pullKey(pull models.PullRequest) ([]byte, error) {
 	hostname := pull.BaseRepo.VCSHost.Hostname
 	if strings.Contains(hostname, pullKeySeparator) {
@@ -518,6 +557,7 @@ func (b *BoltDB) projectResultToProject(p command.ProjectResult) models.ProjectS
 		ProjectName:  p.ProjectName,
 		PolicyStatus: p.PolicyStatus(),
 		Status:       p.PlanStatus(),
+		PlanStats:    p.PlanStats(),
 	}
 }
 
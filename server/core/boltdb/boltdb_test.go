@@ -361,6 +361,14 @@ func TestUnlockByPullOne(t *testing.T) {
 		Ok(t, err)
 		Equals(t, 1, len(ls))
 	}
+	t.Log("...delete nothing when its the same pull but a repo whose name is a prefix of the repo's")
+	{
+		_, err := b.UnlockByPull("owner/re", pullNum)
+		Ok(t, err)
+		ls, err := b.List()
+		Ok(t, err)
+		Equals(t, 1, len(ls))
+	}
 	t.Log("...delete the lock when its the same repo and pull")
 	{
 		_, err := b.UnlockByPull(project.RepoFullName, pullNum)
@@ -665,6 +673,131 @@ func TestPullStatus_UpdateNewCommit(t *testing.T) {
 	b.Close()
 }
 
+func TestPullStatus_AddExternalApproval(t *testing.T) {
+	b := newTestDB2(t)
+
+	pull := models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		URL:        "url",
+		HeadBranch: "head",
+		BaseBranch: "base",
+		Author:     "lkysow",
+		State:      models.OpenPullState,
+		BaseRepo: models.Repo{
+			FullName:          "runatlantis/atlantis",
+			Owner:             "runatlantis",
+			Name:              "atlantis",
+			CloneURL:          "clone-url",
+			SanitizedCloneURL: "clone-url",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+
+	// There's nothing to approve without a status.
+	approval := models.ExternalApproval{
+		HeadCommit: "sha",
+		Approver:   "CHG-123",
+		Time:       time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	}
+	Ok(t, b.AddExternalApproval(pull, approval))
+	maybeStatus, err := b.GetPullStatus(pull)
+	Ok(t, err)
+	Assert(t, maybeStatus == nil, "exp no status")
+
+	_, err = b.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			RepoRelDir: ".",
+			Workspace:  "default",
+			Failure:    "failure",
+		},
+	})
+	Ok(t, err)
+	Ok(t, b.AddExternalApproval(pull, approval))
+
+	// The approval is kept when the same commit is updated.
+	_, err = b.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			RepoRelDir:   ".",
+			Workspace:    "default",
+			ApplySuccess: "success!",
+		},
+	})
+	Ok(t, err)
+	maybeStatus, err = b.GetPullStatus(pull)
+	Ok(t, err)
+	Equals(t, []models.ExternalApproval{approval}, maybeStatus.ExternalApprovals)
+
+	// A new commit needs a new approval.
+	pull.HeadCommit = "newsha"
+	status, err := b.UpdatePullWithResults(pull, []command.ProjectResult{
+		{
+			RepoRelDir: ".",
+			Workspace:  "default",
+			Failure:    "failure",
+		},
+	})
+	Ok(t, err)
+	Equals(t, 0, len(status.ExternalApprovals))
+	b.Close()
+}
+
+func TestPullStatus_CreatedWorkspaces(t *testing.T) {
+	b := newTestDB2(t)
+
+	pull := models.PullRequest{
+		Num:        1,
+		HeadCommit: "sha",
+		URL:        "url",
+		HeadBranch: "head",
+		BaseBranch: "base",
+		Author:     "lkysow",
+		State:      models.OpenPullState,
+		BaseRepo: models.Repo{
+			FullName:          "runatlantis/atlantis",
+			Owner:             "runatlantis",
+			Name:              "atlantis",
+			CloneURL:          "clone-url",
+			SanitizedCloneURL: "clone-url",
+			VCSHost: models.VCSHost{
+				Hostname: "github.com",
+				Type:     models.Github,
+			},
+		},
+	}
+
+	created := command.ProjectResult{
+		Command:     command.Plan,
+		RepoRelDir:  ".",
+		Workspace:   "pr-1",
+		PlanSuccess: &models.PlanSuccess{CreatedWorkspace: "pr-1", TerraformVersion: "1.5.7"},
+	}
+	_, err := b.UpdatePullWithResults(pull, []command.ProjectResult{created})
+	Ok(t, err)
+
+	// The workspace is only recorded once and kept for new commits.
+	pull.HeadCommit = "newsha"
+	status, err := b.UpdatePullWithResults(pull, []command.ProjectResult{
+		created,
+		{
+			Command:     command.Plan,
+			RepoRelDir:  ".",
+			Workspace:   "default",
+			PlanSuccess: &models.PlanSuccess{},
+		},
+	})
+	Ok(t, err)
+	exp := []models.CreatedWorkspace{{RepoRelDir: ".", Workspace: "pr-1", TerraformVersion: "1.5.7"}}
+	Equals(t, exp, status.CreatedWorkspaces)
+	maybeStatus, err := b.GetPullStatus(pull)
+	Ok(t, err)
+	Equals(t, exp, maybeStatus.CreatedWorkspaces)
+	b.Close()
+}
+
 // Test that if we update an existing pull status via Apply and our new status is for a
 // the same commit, that we merge the statuses.
 func TestPullStatus_UpdateMerge_Apply(t *testing.T) {
@@ -767,6 +900,7 @@ func TestPullStatus_UpdateMerge_Apply(t *testing.T) {
 				RepoRelDir: "staythesame",
 				Workspace:  "default",
 				Status:     models.PlannedPlanStatus,
+				PlanStats:  &models.PlanSuccessStats{},
 			},
 			{
 				RepoRelDir: "newresult",
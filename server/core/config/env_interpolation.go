@@ -0,0 +1,127 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+
+	yaml "gopkg.in/yaml.v3"
+)
+
+// envVarNameRegex matches valid environment variable names.
+var envVarNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ValidEnvVarName returns true if name can be referenced as ${name} in repo
+// configs.
+func ValidEnvVarName(name string) bool {
+	return envVarNameRegex.MatchString(name)
+}
+
+// interpolateEnv returns the repo config repoCfgData with ${VAR} in its string
+// values replaced by the value of the environment variable VAR, which must be
+// in allowed and set. $$ is an escaped literal $. Workflows aren't
+// interpolated since their run steps are commands where ${VAR} is expanded
+// by the shell.
+func interpolateEnv(repoCfgData []byte, allowed []string) ([]byte, error) {
+	if !bytes.Contains(repoCfgData, []byte("$")) {
+		return repoCfgData, nil
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(repoCfgData, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 {
+		return repoCfgData, nil
+	}
+
+	root := doc.Content[0]
+	if root.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(root.Content); i += 2 {
+			if root.Content[i].Value == "workflows" {
+				continue
+			}
+			if err := interpolateEnvNode(root.Content[i+1], allowed); err != nil {
+				return nil, err
+			}
+		}
+	} else if err := interpolateEnvNode(root, allowed); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(&doc)
+}
+
+// interpolateEnvNode interpolates the string values of n and its children.
+// Mapping keys aren't interpolated.
+func interpolateEnvNode(n *yaml.Node, allowed []string) error {
+	switch n.Kind {
+	case yaml.ScalarNode:
+		if n.ShortTag() != "!!str" {
+			return nil
+		}
+		value, err := expandEnv(n.Value, allowed)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		n.Value = value
+	case yaml.MappingNode:
+		for i := 1; i < len(n.Content); i += 2 {
+			if err := interpolateEnvNode(n.Content[i], allowed); err != nil {
+				return err
+			}
+		}
+	case yaml.SequenceNode:
+		for _, c := range n.Content {
+			if err := interpolateEnvNode(c, allowed); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// expandEnv replaces ${VAR} in s with the value of the environment variable
+// VAR and $$ with $. Any other $ is kept as is.
+func expandEnv(s string, allowed []string) (string, error) {
+	if !strings.Contains(s, "$") {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '$' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case '$':
+			b.WriteByte('$')
+			i++
+		case '{':
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated environment variable reference in %q, use $$ for a literal $", s)
+			}
+			name := s[i+2 : i+2+end]
+			if !ValidEnvVarName(name) {
+				return "", fmt.Errorf("invalid environment variable name %q", name)
+			}
+			if !slices.Contains(allowed, name) {
+				return "", fmt.Errorf("environment variable %q is not allowed to be referenced in repo config, allowed are: %s", name, strings.Join(allowed, ", "))
+			}
+			value, ok := os.LookupEnv(name)
+			if !ok {
+				return "", fmt.Errorf("environment variable %q is not defined", name)
+			}
+			b.WriteString(value)
+			i += 2 + end
+		default:
+			b.WriteByte('$')
+		}
+	}
+	return b.String(), nil
+}
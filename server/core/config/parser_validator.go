@@ -11,6 +11,7 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	validation "github.com/go-ozzo/ozzo-validation"
@@ -18,12 +19,19 @@ import (
 
 	"github.com/runatlantis/atlantis/server/core/config/raw"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/logging"
 	yaml "gopkg.in/yaml.v3"
 )
 
 // ParserValidator parses and validates server-side repo config files and
 // repo-level atlantis.yaml files.
-type ParserValidator struct{}
+type ParserValidator struct {
+	// RemoteRepoCfg fetches the repo configs of repos with a repo_config_url.
+	RemoteRepoCfg RemoteRepoCfgFetcher
+	// AllowedEnvVars are the environment variables that repo configs can
+	// reference as ${VAR}. If it's empty, repo configs aren't interpolated.
+	AllowedEnvVars []string
+}
 
 // HasRepoCfg returns true if there is a repo config (atlantis.yaml) file
 // for the repo at absRepoDir.
@@ -57,9 +65,32 @@ func (p *ParserValidator) ParseRepoCfg(absRepoDir string, globalCfg valid.Global
 	return p.ParseRepoCfgData(configData, globalCfg, repoID, branch)
 }
 
+// ParseRemoteRepoCfg returns the parsed and validated repo config at the
+// repo_config_url of the repo. It returns false if the repo doesn't have one.
+func (p *ParserValidator) ParseRemoteRepoCfg(log logging.SimpleLogging, globalCfg valid.GlobalCfg, repoID string, branch string) (bool, valid.RepoCfg, error) {
+	repoConfigURL := globalCfg.RepoConfigURL(repoID)
+	if repoConfigURL == "" {
+		return false, valid.RepoCfg{}, nil
+	}
+	configData, err := p.RemoteRepoCfg.Fetch(log, repoConfigURL)
+	if err != nil {
+		return false, valid.RepoCfg{}, err
+	}
+	repoCfg, err := p.ParseRepoCfgData(configData, globalCfg, repoID, branch)
+	return true, repoCfg, err
+}
+
 func (p *ParserValidator) ParseRepoCfgData(repoCfgData []byte, globalCfg valid.GlobalCfg, repoID string, branch string) (valid.RepoCfg, error) {
 	var rawConfig raw.RepoCfg
 
+	if len(p.AllowedEnvVars) > 0 {
+		var err error
+		repoCfgData, err = interpolateEnv(repoCfgData, p.AllowedEnvVars)
+		if err != nil {
+			return valid.RepoCfg{}, fmt.Errorf("interpolating environment variables: %w", err)
+		}
+	}
+
 	decoder := yaml.NewDecoder(bytes.NewReader(repoCfgData))
 	decoder.KnownFields(true)
 
@@ -76,17 +107,23 @@ func (p *ParserValidator) ParseRepoCfgData(repoCfgData []byte, globalCfg valid.G
 
 	validConfig := rawConfig.ToValid()
 
+	if err := p.validateProjectDependencies(validConfig); err != nil {
+		return valid.RepoCfg{}, err
+	}
+
 	// Filter the repo config's projects based on pull request's branch. Only
 	// keep projects that either:
 	//
-	//   - Have no branch regex defined at all (i.e. match all branches), or
-	//   - Those that have branch regex matching the PR's base branch.
+	//   - Have no branch defined at all (i.e. match all branches), or
+	//   - Those that have a branch regex or glob matching the PR's base branch.
 	//
 	i := 0
 	for _, p := range validConfig.Projects {
-		if branch == "" || p.BranchRegex == nil || p.BranchRegex.MatchString(branch) {
+		if branch == "" || p.MatchesBranch(branch) {
 			validConfig.Projects[i] = p
 			i++
+		} else {
+			validConfig.BranchSkippedProjects = append(validConfig.BranchSkippedProjects, p)
 		}
 	}
 	validConfig.Projects = validConfig.Projects[:i]
@@ -104,8 +141,11 @@ func (p *ParserValidator) ParseRepoCfgData(repoCfgData []byte, globalCfg valid.G
 		}
 	}
 
-	err = globalCfg.ValidateRepoCfg(validConfig, repoID)
-	return validConfig, err
+	if err := globalCfg.ValidateRepoCfg(validConfig, repoID); err != nil {
+		return validConfig, err
+	}
+	validConfig.Warnings = globalCfg.RepoCfgWarnings(validConfig, repoID)
+	return validConfig, nil
 }
 
 // ParseGlobalCfg returns the parsed and validated global repo config file at
@@ -152,6 +192,9 @@ func (p *ParserValidator) validateRawGlobalCfg(rawCfg raw.GlobalCfg, defaultCfg
 	}
 
 	validCfg := rawCfg.ToValid(defaultCfg)
+	if err := validCfg.ValidateWorkflowShells(validCfg.Workflows); err != nil {
+		return valid.GlobalCfg{}, err
+	}
 	return validCfg, nil
 }
 
@@ -173,26 +216,99 @@ func (p *ParserValidator) validateProjectNames(config valid.RepoCfg) error {
 		}
 	}
 
-	// Next, validate that all dir/workspace combos are named.
-	// This map's keys will be 'dir/workspace' and the values are the names for
-	// that project.
-	dirWorkspaceToNames := make(map[string][]string)
+	// Next, validate that projects sharing a dir/workspace combo are all
+	// named. A project's allowed workspaces count as its workspaces too.
+	type dirWorkspace struct {
+		dir       string
+		workspace string
+	}
+	var combos []dirWorkspace
+	comboToProjects := make(map[dirWorkspace][]valid.Project)
 	for _, project := range config.Projects {
-		key := fmt.Sprintf("%s/%s", project.Dir, project.Workspace)
-		names := dirWorkspaceToNames[key]
-
-		// If there is already a project with this dir/workspace then this
-		// project must have a name.
-		if len(names) > 0 && project.Name == nil {
-			return fmt.Errorf("there are two or more projects with dir: %q workspace: %q that are not all named; they must have a 'name' key so they can be targeted for apply's separately", project.Dir, project.Workspace)
+		for _, workspace := range append([]string{project.Workspace}, project.AllowedWorkspaces...) {
+			combo := dirWorkspace{project.Dir, workspace}
+			if _, ok := comboToProjects[combo]; !ok {
+				combos = append(combos, combo)
+			}
+			comboToProjects[combo] = append(comboToProjects[combo], project)
 		}
-		var name string
+	}
+	for _, combo := range combos {
+		projects := comboToProjects[combo]
+		if len(projects) < 2 {
+			continue
+		}
+		for _, project := range projects {
+			if project.Name == nil {
+				return fmt.Errorf("there are two or more projects with dir: %q workspace: %q that are not all named; they must have a 'name' key so they can be targeted for apply's separately", combo.dir, combo.workspace)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validateProjectDependencies validates that every project referenced in
+// depends_on exists and that the dependencies don't form a cycle.
+func (p *ParserValidator) validateProjectDependencies(config valid.RepoCfg) error {
+	dependsOn := make(map[string][]string)
+	for _, project := range config.Projects {
 		if project.Name != nil {
-			name = *project.Name
+			dependsOn[*project.Name] = project.DependsOn
+		}
+	}
+	for _, project := range config.Projects {
+		name := project.GetName()
+		label := name
+		if label == "" {
+			label = project.Dir
+		}
+		for _, dep := range project.DependsOn {
+			if dep == name {
+				return fmt.Errorf("project %q cannot depend on itself", label)
+			}
+			if _, ok := dependsOn[dep]; !ok {
+				return fmt.Errorf("project %q depends on %q but no project with that name is defined", label, dep)
+			}
 		}
-		dirWorkspaceToNames[key] = append(dirWorkspaceToNames[key], name)
 	}
 
+	// Walk the dependencies depth first, tracking the current path so a
+	// cycle can be reported in full.
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[string]int)
+	var path []string
+	var visit func(name string) error
+	visit = func(name string) error {
+		switch state[name] {
+		case done:
+			return nil
+		case visiting:
+			start := slices.Index(path, name)
+			cycle := append(slices.Clone(path[start:]), name)
+			return fmt.Errorf("project dependencies form a cycle: %s", strings.Join(cycle, " -> "))
+		}
+		state[name] = visiting
+		path = append(path, name)
+		for _, dep := range dependsOn[name] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		path = path[:len(path)-1]
+		state[name] = done
+		return nil
+	}
+	for _, project := range config.Projects {
+		if project.Name != nil {
+			if err := visit(*project.Name); err != nil {
+				return err
+			}
+		}
+	}
 	return nil
 }
 
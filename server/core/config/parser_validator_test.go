@@ -648,6 +648,36 @@ projects:
 - dir: ..`,
 			expErr: "projects: (0: (dir: cannot contain '..'.).).",
 		},
+		{
+			description: "project with invalid when_modified exclusion",
+			input: `
+version: 3
+projects:
+- dir: .
+  autoplan:
+    when_modified: ["**/*.tf", "!"]`,
+			expErr: "projects: (0: (autoplan: when_modified: illegal exclusion pattern: \"!\".).).",
+		},
+		{
+			description: "project with custom apply requirement",
+			input: `
+version: 3
+projects:
+- dir: .
+  apply_requirements:
+  - custom:
+      command: ./check-ticket.sh`,
+			expErr: "projects: (0: (apply_requirements: custom apply_requirements can only be set in the server-side repo config.).).",
+		},
+		{
+			description: "project with custom apply requirement as a string",
+			input: `
+version: 3
+projects:
+- dir: .
+  apply_requirements: ["custom:./check-ticket.sh"]`,
+			expErr: "projects: (0: (apply_requirements: \"custom:./check-ticket.sh\" is not a valid apply_requirement, only \"approved\", \"mergeable\", \"undiverged\", \"pipeline_success\", \"external_approval\" and \"custom\" are supported.).).",
+		},
 
 		// Project must have dir set.
 		{
@@ -707,6 +737,50 @@ projects:
   workspace: workspace`,
 			expErr: "there are two or more projects with dir: \".\" workspace: \"workspace\" that are not all named; they must have a 'name' key so they can be targeted for apply's separately",
 		},
+		{
+			description: "no_changes_message is not a string",
+			input: `
+version: 3
+projects:
+- dir: .
+  no_changes_message:
+    text: nothing to see`,
+			expErr: "yaml: unmarshal errors:\n  line 6: cannot unmarshal !!map into string",
+		},
+		{
+			description: "label value is not a string",
+			input: `
+version: 3
+projects:
+- dir: .
+  labels:
+    team:
+      name: platform`,
+			expErr: "yaml: unmarshal errors:\n  line 7: cannot unmarshal !!map into string",
+		},
+		{
+			description: "two projects with same dir/workspace only the second with name",
+			input: `
+version: 3
+projects:
+- dir: .
+  workspace: workspace
+- name: myname
+  dir: .
+  workspace: workspace`,
+			expErr: "there are two or more projects with dir: \".\" workspace: \"workspace\" that are not all named; they must have a 'name' key so they can be targeted for apply's separately",
+		},
+		{
+			description: "project allowed workspace overlaps another project's workspace",
+			input: `
+version: 3
+projects:
+- dir: .
+  allowed_workspaces: [staging]
+- dir: .
+  workspace: staging`,
+			expErr: "there are two or more projects with dir: \".\" workspace: \"staging\" that are not all named; they must have a 'name' key so they can be targeted for apply's separately",
+		},
 		{
 			description: "two projects with same dir/workspace both with same name",
 			input: `
@@ -720,6 +794,42 @@ projects:
   workspace: workspace`,
 			expErr: "found two or more projects with name \"myname\"; project names must be unique",
 		},
+		{
+			description: "project depends on undefined project",
+			input: `
+version: 3
+projects:
+- name: app
+  dir: app
+  depends_on: [network]`,
+			expErr: "project \"app\" depends on \"network\" but no project with that name is defined",
+		},
+		{
+			description: "project depends on itself",
+			input: `
+version: 3
+projects:
+- name: app
+  dir: app
+  depends_on: [app]`,
+			expErr: "project \"app\" cannot depend on itself",
+		},
+		{
+			description: "project dependencies form a cycle",
+			input: `
+version: 3
+projects:
+- name: app
+  dir: app
+  depends_on: [network]
+- name: network
+  dir: network
+  depends_on: [dns]
+- name: dns
+  dir: dns
+  depends_on: [app]`,
+			expErr: "project dependencies form a cycle: app -> network -> dns -> app",
+		},
 		{
 			description: "two projects with same dir/workspace with different names",
 			input: `
@@ -1173,6 +1283,330 @@ workflows:
 	ErrEquals(t, "repo config not allowed to set 'workflow' key: server-side config needs 'allowed_overrides: [workflow]'", err)
 }
 
+func TestParseRepoCfgData_EnvInterpolation(t *testing.T) {
+	t.Setenv("ATLANTIS_TEST_ENV", "staging")
+	t.Setenv("ATLANTIS_TEST_SECRET", "secret")
+
+	cases := []struct {
+		description string
+		allowed     []string
+		input       string
+		expDir      string
+		expStep     string
+		expErr      string
+	}{
+		{
+			description: "interpolates allowed variables",
+			allowed:     []string{"ATLANTIS_TEST_ENV"},
+			input: `
+version: 3
+projects:
+- dir: envs/${ATLANTIS_TEST_ENV}`,
+			expDir: "envs/staging",
+		},
+		{
+			description: "escaped dollar",
+			allowed:     []string{"ATLANTIS_TEST_ENV"},
+			input: `
+version: 3
+projects:
+- dir: envs/$${ATLANTIS_TEST_ENV}`,
+			expDir: "envs/${ATLANTIS_TEST_ENV}",
+		},
+		{
+			description: "no allowlist",
+			input: `
+version: 3
+projects:
+- dir: envs/${ATLANTIS_TEST_ENV}`,
+			expDir: "envs/${ATLANTIS_TEST_ENV}",
+		},
+		{
+			description: "workflows aren't interpolated",
+			allowed:     []string{"ATLANTIS_TEST_ENV"},
+			input: `
+version: 3
+projects:
+- dir: ${ATLANTIS_TEST_ENV}
+workflows:
+  custom:
+    plan:
+      steps:
+      - run: echo ${ATLANTIS_TEST_SECRET}`,
+			expDir:  "staging",
+			expStep: "echo ${ATLANTIS_TEST_SECRET}",
+		},
+		{
+			description: "variable not allowed",
+			allowed:     []string{"ATLANTIS_TEST_ENV"},
+			input: `
+version: 3
+projects:
+- dir: ${ATLANTIS_TEST_SECRET}`,
+			expErr: `interpolating environment variables: line 4: environment variable "ATLANTIS_TEST_SECRET" is not allowed to be referenced in repo config, allowed are: ATLANTIS_TEST_ENV`,
+		},
+		{
+			description: "variable not defined",
+			allowed:     []string{"ATLANTIS_TEST_UNDEFINED"},
+			input: `
+version: 3
+projects:
+- dir: ${ATLANTIS_TEST_UNDEFINED}`,
+			expErr: `interpolating environment variables: line 4: environment variable "ATLANTIS_TEST_UNDEFINED" is not defined`,
+		},
+		{
+			description: "unterminated reference",
+			allowed:     []string{"ATLANTIS_TEST_ENV"},
+			input: `
+version: 3
+projects:
+- dir: ${ATLANTIS_TEST_ENV`,
+			expErr: `interpolating environment variables: line 4: unterminated environment variable reference in "${ATLANTIS_TEST_ENV", use $$ for a literal $`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			r := config.ParserValidator{AllowedEnvVars: c.allowed}
+			globalCfg := valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{AllowAllRepoSettings: true})
+			repoCfg, err := r.ParseRepoCfgData([]byte(c.input), globalCfg, "repo_id", "branch")
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, c.expDir, repoCfg.Projects[0].Dir)
+			if c.expStep != "" {
+				Equals(t, c.expStep, repoCfg.Workflows["custom"].Plan.Steps[0].RunCommand)
+			}
+		})
+	}
+}
+
+func TestParseGlobalCfg_AllowedShells(t *testing.T) {
+	cases := map[string]struct {
+		input  string
+		expErr string
+	}{
+		"shell on default allowlist": {
+			input: `
+workflows:
+  custom:
+    plan:
+      steps:
+      - run:
+          command: echo hi
+          shell: bash
+`,
+		},
+		"shell not on default allowlist": {
+			input: `
+workflows:
+  custom:
+    plan:
+      steps:
+      - run:
+          command: echo hi
+          shell: bsah
+`,
+			expErr: "workflow \"custom\": \"run\" step shell \"bsah\" is not allowed, allowed shells are [sh, bash, zsh, pwsh]",
+		},
+		"shell on configured allowlist": {
+			input: `
+allowed_shells: [fish]
+workflows:
+  custom:
+    plan:
+      steps:
+      - run:
+          command: echo hi
+          shell: fish
+`,
+		},
+		"shell not on configured allowlist": {
+			input: `
+allowed_shells: [sh]
+workflows:
+  custom:
+    plan:
+      steps:
+      - run:
+          command: echo hi
+          shell: bash
+`,
+			expErr: "workflow \"custom\": \"run\" step shell \"bash\" is not allowed, allowed shells are [sh]",
+		},
+		"empty allowed shell": {
+			input: `
+allowed_shells: [""]
+`,
+			expErr: "allowed_shells: cannot contain an empty shell name",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &config.ParserValidator{}
+			tmp := t.TempDir()
+			path := filepath.Join(tmp, "conf.yaml")
+			Ok(t, os.WriteFile(path, []byte(c.input), 0600))
+
+			_, err := r.ParseGlobalCfg(path, valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}))
+			if c.expErr == "" {
+				Ok(t, err)
+			} else {
+				ErrEquals(t, c.expErr, err)
+			}
+		})
+	}
+}
+
+func TestParseGlobalCfg_AllowedRunCommands(t *testing.T) {
+	cases := map[string]struct {
+		input  string
+		expErr string
+	}{
+		"valid regexes": {
+			input: `
+repos:
+- id: /.*/
+  allowed_run_commands: ["terraform .*", "make (plan|apply)"]
+`,
+		},
+		"empty pattern": {
+			input: `
+repos:
+- id: /.*/
+  allowed_run_commands: [""]
+`,
+			expErr: "repos: (0: (allowed_run_commands: cannot contain an empty pattern.).).",
+		},
+		"invalid regex": {
+			input: `
+repos:
+- id: /.*/
+  allowed_run_commands: ["make ("]
+`,
+			expErr: "repos: (0: (allowed_run_commands: invalid regex \"make (\": error parsing regexp: missing closing ): `make (`.).).",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &config.ParserValidator{}
+			tmp := t.TempDir()
+			path := filepath.Join(tmp, "conf.yaml")
+			Ok(t, os.WriteFile(path, []byte(c.input), 0600))
+
+			_, err := r.ParseGlobalCfg(path, valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}))
+			if c.expErr == "" {
+				Ok(t, err)
+			} else {
+				ErrEquals(t, c.expErr, err)
+			}
+		})
+	}
+}
+
+func TestParseGlobalCfg_StepOutputDenylist(t *testing.T) {
+	cases := map[string]struct {
+		input  string
+		exp    []string
+		expErr string
+	}{
+		"not set": {
+			input: `repos: []`,
+		},
+		"patterns": {
+			input: `
+step_output_denylist:
+- AKIA[0-9A-Z]{16}
+- "-----BEGIN (RSA )?PRIVATE KEY-----"
+`,
+			exp: []string{"AKIA[0-9A-Z]{16}", "-----BEGIN (RSA )?PRIVATE KEY-----"},
+		},
+		"invalid pattern": {
+			input: `
+step_output_denylist: ["[a-z"]
+`,
+			expErr: "step_output_denylist: invalid regex \"[a-z\": error parsing regexp: missing closing ]: `[a-z`",
+		},
+		"empty pattern": {
+			input: `
+step_output_denylist: [""]
+`,
+			expErr: "step_output_denylist: cannot contain an empty pattern",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &config.ParserValidator{}
+			tmp := t.TempDir()
+			path := filepath.Join(tmp, "conf.yaml")
+			Ok(t, os.WriteFile(path, []byte(c.input), 0600))
+
+			cfg, err := r.ParseGlobalCfg(path, valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}))
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			var patterns []string
+			for _, pattern := range cfg.StepOutputDenylist {
+				patterns = append(patterns, pattern.String())
+			}
+			Equals(t, c.exp, patterns)
+		})
+	}
+}
+
+func TestParseGlobalCfg_StepOutputMasks(t *testing.T) {
+	cases := map[string]struct {
+		input  string
+		exp    []string
+		expErr string
+	}{
+		"patterns": {
+			input: `
+step_output_masks:
+- ghp_[A-Za-z0-9]{36}
+- "(?i)password=\\S+"
+`,
+			exp: []string{"ghp_[A-Za-z0-9]{36}", "(?i)password=\\S+"},
+		},
+		"invalid pattern": {
+			input: `
+step_output_masks: ["(ghp_"]
+`,
+			expErr: "step_output_masks: invalid regex \"(ghp_\": error parsing regexp: missing closing ): `(ghp_`",
+		},
+		"empty pattern": {
+			input: `
+step_output_masks: [""]
+`,
+			expErr: "step_output_masks: cannot contain an empty pattern",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &config.ParserValidator{}
+			tmp := t.TempDir()
+			path := filepath.Join(tmp, "conf.yaml")
+			Ok(t, os.WriteFile(path, []byte(c.input), 0600))
+
+			cfg, err := r.ParseGlobalCfg(path, valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}))
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			var patterns []string
+			for _, pattern := range cfg.StepOutputMasks {
+				patterns = append(patterns, pattern.String())
+			}
+			Equals(t, c.exp, patterns)
+		})
+	}
+}
+
 func TestParseGlobalCfg_NotExist(t *testing.T) {
 	r := config.ParserValidator{}
 	globalCfgArgs := valid.GlobalCfgArgs{}
@@ -1318,6 +1752,26 @@ func TestParseGlobalCfg(t *testing.T) {
   allowed_overrides: [invalid]`,
 			expErr: "repos: (0: (allowed_overrides: \"invalid\" is not a valid override, only \"plan_requirements\", \"apply_requirements\", \"import_requirements\", \"workflow\", \"delete_source_branch_on_merge\", \"repo_locking\", \"repo_locks\", \"policy_check\", \"custom_policy_check\", and \"silence_pr_comments\" are supported.).).",
 		},
+		"invalid locked_override": {
+			input: `repos:
+- id: /.*/
+  locked_overrides: [invalid]`,
+			expErr: "repos: (0: (locked_overrides: \"invalid\" is not a valid override, only \"plan_requirements\", \"apply_requirements\", \"import_requirements\", \"workflow\", \"delete_source_branch_on_merge\", \"repo_locking\", \"repo_locks\", \"policy_check\", \"custom_policy_check\", and \"silence_pr_comments\" are supported.).).",
+		},
+		"locked_override also allowed": {
+			input: `repos:
+- id: /.*/
+  allowed_overrides: [workflow, apply_requirements]
+  locked_overrides: [apply_requirements]`,
+			expErr: "repos: (0: (locked_overrides: \"apply_requirements\" can't be in both allowed_overrides and locked_overrides.).).",
+		},
+		"invalid locked_overrides_action": {
+			input: `repos:
+- id: /.*/
+  locked_overrides: [apply_requirements]
+  locked_overrides_action: warn`,
+			expErr: "repos: (0: (locked_overrides_action: must be a valid value.).).",
+		},
 		"invalid plan_requirement": {
 			input: `repos:
 - id: /.*/
@@ -1328,7 +1782,37 @@ func TestParseGlobalCfg(t *testing.T) {
 			input: `repos:
 - id: /.*/
   apply_requirements: [invalid]`,
-			expErr: "repos: (0: (apply_requirements: \"invalid\" is not a valid apply_requirement, only \"approved\", \"mergeable\" and \"undiverged\" are supported.).).",
+			expErr: "repos: (0: (apply_requirements: \"invalid\" is not a valid apply_requirement, only \"approved\", \"mergeable\", \"undiverged\", \"pipeline_success\", \"external_approval\" and \"custom\" are supported.).).",
+		},
+		"empty custom apply_requirement": {
+			input: `repos:
+- id: /.*/
+  apply_requirements:
+  - custom:
+      command: ""`,
+			expErr: "repos: (0: (apply_requirements: custom apply_requirement must have a non-empty \"command\".).).",
+		},
+		"custom apply_requirement": {
+			input: `repos:
+- id: /.*/
+  apply_requirements:
+  - approved
+  - custom:
+      command: ./check-ticket.sh`,
+			exp: valid.GlobalCfg{
+				Repos: []valid.Repo{
+					defaultCfg.Repos[0],
+					{
+						IDRegex:                 regexp.MustCompile(".*"),
+						ApplyRequirements:       []string{"approved"},
+						CustomApplyRequirements: []valid.CustomRequirement{{Command: "./check-ticket.sh"}},
+					},
+				},
+				Workflows: defaultCfg.Workflows,
+				TeamAuthz: valid.TeamAuthz{
+					Args: make([]string, 0),
+				},
+			},
 		},
 		"invalid import_requirement": {
 			input: `repos:
@@ -1455,6 +1939,7 @@ workflows:
 repos:
 - id: github.com/owner/repo
   repo_config_file: "path/to/atlantis.yaml"
+  repo_config_url: "https://example.com/atlantis.yaml"
   apply_requirements: [approved, mergeable]
   pre_workflow_hooks:
     - run: custom workflow command
@@ -1518,6 +2003,7 @@ policies:
 					{
 						ID:                   "github.com/owner/repo",
 						RepoConfigFile:       "path/to/atlantis.yaml",
+						RepoConfigURL:        "https://example.com/atlantis.yaml",
 						ApplyRequirements:    []string{"approved", "mergeable"},
 						PreWorkflowHooks:     preWorkflowHooks,
 						Workflow:             &customWorkflow1,
@@ -0,0 +1,149 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package raw
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+)
+
+const (
+	CustomRequirementKey        = "custom"
+	CustomRequirementCommandKey = "command"
+)
+
+// ApplyRequirements is a list of apply requirements. Each element is either
+// the name of a built-in requirement or a custom requirement, ex.
+//
+//	apply_requirements:
+//	- approved
+//	- custom:
+//	    command: ./check-ticket.sh
+//
+// Custom requirements run commands on the Atlantis server so they can only be
+// set in the server-side repo config.
+type ApplyRequirements []ApplyRequirement
+
+// ApplyRequirement is the built-in requirement Name, or a custom requirement
+// if Custom is set.
+type ApplyRequirement struct {
+	Name   string
+	Custom *CustomRequirement
+}
+
+// CustomRequirement is a requirement that passes when Command exits zero.
+type CustomRequirement struct {
+	Command string
+}
+
+// Names returns the names of the built-in requirements. It returns nil if a
+// is nil so unset requirements are told apart from empty ones.
+func (a ApplyRequirements) Names() []string {
+	if a == nil {
+		return nil
+	}
+	names := []string{}
+	for _, req := range a {
+		if req.Custom == nil {
+			names = append(names, req.Name)
+		}
+	}
+	return names
+}
+
+// Custom returns the custom requirements.
+func (a ApplyRequirements) Custom() []valid.CustomRequirement {
+	var reqs []valid.CustomRequirement
+	for _, req := range a {
+		if req.Custom != nil {
+			reqs = append(reqs, valid.CustomRequirement{Command: req.Custom.Command})
+		}
+	}
+	return reqs
+}
+
+func (a *ApplyRequirements) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	return a.unmarshalGeneric(unmarshal)
+}
+
+func (a ApplyRequirements) MarshalYAML() (interface{}, error) {
+	return a.marshalGeneric(), nil
+}
+
+func (a *ApplyRequirements) UnmarshalJSON(data []byte) error {
+	return a.unmarshalGeneric(func(i interface{}) error {
+		return json.Unmarshal(data, i)
+	})
+}
+
+func (a ApplyRequirements) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.marshalGeneric())
+}
+
+func (a *ApplyRequirements) unmarshalGeneric(unmarshal func(interface{}) error) error {
+	var elems []interface{}
+	if err := unmarshal(&elems); err != nil {
+		return err
+	}
+	if elems == nil {
+		*a = nil
+		return nil
+	}
+	reqs := ApplyRequirements{}
+	for _, elem := range elems {
+		if s, ok := elem.(string); ok {
+			reqs = append(reqs, ApplyRequirement{Name: s})
+			continue
+		}
+		command, err := customRequirementCommand(elem)
+		if err != nil {
+			return err
+		}
+		reqs = append(reqs, ApplyRequirement{Custom: &CustomRequirement{Command: command}})
+	}
+	*a = reqs
+	return nil
+}
+
+func (a ApplyRequirements) marshalGeneric() []interface{} {
+	if a == nil {
+		return nil
+	}
+	out := []interface{}{}
+	for _, req := range a {
+		if req.Custom != nil {
+			out = append(out, map[string]map[string]string{
+				CustomRequirementKey: {CustomRequirementCommandKey: req.Custom.Command},
+			})
+			continue
+		}
+		out = append(out, req.Name)
+	}
+	return out
+}
+
+// customRequirementCommand returns the command of a requirement in the
+// {custom: {command: X}} form.
+func customRequirementCommand(elem interface{}) (string, error) {
+	m, ok := elem.(map[string]interface{})
+	if !ok || len(m) != 1 {
+		return "", fmt.Errorf("requirements must be a string or a map with a single %q key", CustomRequirementKey)
+	}
+	custom, ok := m[CustomRequirementKey].(map[string]interface{})
+	if !ok {
+		return "", fmt.Errorf("requirements must be a string or a map with a single %q key", CustomRequirementKey)
+	}
+	for k := range custom {
+		if k != CustomRequirementCommandKey {
+			return "", fmt.Errorf("%q is not a valid key for a %s requirement, only %q is supported", k, CustomRequirementKey, CustomRequirementCommandKey)
+		}
+	}
+	command, ok := custom[CustomRequirementCommandKey].(string)
+	if !ok && custom[CustomRequirementCommandKey] != nil {
+		return "", fmt.Errorf("%s requirement %q must be a string", CustomRequirementKey, CustomRequirementCommandKey)
+	}
+	return command, nil
+}
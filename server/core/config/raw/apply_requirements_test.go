@@ -0,0 +1,97 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package raw_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/config/raw"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	. "github.com/runatlantis/atlantis/testing"
+	yaml "gopkg.in/yaml.v3"
+)
+
+func TestApplyRequirements_YAMLMarshalling(t *testing.T) {
+	cases := []struct {
+		description string
+		input       string
+		exp         raw.ApplyRequirements
+		expErr      string
+	}{
+		{
+			description: "built-in requirements",
+			input:       `[approved, mergeable]`,
+			exp:         raw.ApplyRequirements{{Name: "approved"}, {Name: "mergeable"}},
+		},
+		{
+			description: "custom requirement",
+			input: `
+- undiverged
+- custom:
+    command: ./check-ticket.sh`,
+			exp: raw.ApplyRequirements{{Name: "undiverged"}, {Custom: &raw.CustomRequirement{Command: "./check-ticket.sh"}}},
+		},
+		{
+			description: "custom requirement without command",
+			input:       `[custom: {}]`,
+			exp:         raw.ApplyRequirements{{Custom: &raw.CustomRequirement{}}},
+		},
+		{
+			description: "unknown map key",
+			input:       `[other: {command: ./check-ticket.sh}]`,
+			expErr:      `requirements must be a string or a map with a single "custom" key`,
+		},
+		{
+			description: "unknown custom key",
+			input:       `[custom: {command: ./check-ticket.sh, shell: bash}]`,
+			expErr:      `"shell" is not a valid key for a custom requirement, only "command" is supported`,
+		},
+		{
+			description: "non-string command",
+			input:       `[custom: {command: [a, b]}]`,
+			expErr:      `custom requirement "command" must be a string`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			var got raw.ApplyRequirements
+			err := yaml.Unmarshal([]byte(c.input), &got)
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, c.exp, got)
+
+			// Marshalling should round trip.
+			out, err := yaml.Marshal(got)
+			Ok(t, err)
+			var roundTripped raw.ApplyRequirements
+			Ok(t, yaml.Unmarshal(out, &roundTripped))
+			Equals(t, c.exp, roundTripped)
+		})
+	}
+}
+
+func TestApplyRequirements_JSONMarshalling(t *testing.T) {
+	var got raw.ApplyRequirements
+	Ok(t, json.Unmarshal([]byte(`["approved", {"custom": {"command": "./check-ticket.sh"}}]`), &got))
+	Equals(t, raw.ApplyRequirements{{Name: "approved"}, {Custom: &raw.CustomRequirement{Command: "./check-ticket.sh"}}}, got)
+
+	out, err := json.Marshal(got)
+	Ok(t, err)
+	Equals(t, `["approved",{"custom":{"command":"./check-ticket.sh"}}]`, string(out))
+}
+
+func TestApplyRequirements_NamesAndCustom(t *testing.T) {
+	var unset raw.ApplyRequirements
+	Assert(t, unset.Names() == nil, "exp nil names if unset")
+	Assert(t, unset.Custom() == nil, "exp nil custom requirements if unset")
+
+	reqs := raw.ApplyRequirements{{Name: "approved"}, {Custom: &raw.CustomRequirement{Command: "./check-ticket.sh"}}}
+	Equals(t, []string{"approved"}, reqs.Names())
+	Equals(t, []valid.CustomRequirement{{Command: "./check-ticket.sh"}}, reqs.Custom())
+	Equals(t, []string{}, raw.ApplyRequirements{{Custom: &raw.CustomRequirement{Command: "./check-ticket.sh"}}}.Names())
+}
@@ -4,6 +4,10 @@
 package raw
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/moby/patternmatcher"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 )
 
@@ -37,7 +41,19 @@ func (a Autoplan) ToValid() valid.Autoplan {
 	return v
 }
 
+// Validate checks that the when_modified patterns are valid. Patterns
+// prefixed with '!' are exclusions. Patterns are evaluated in order and the
+// last pattern that matches a file wins, so an exclusion only excludes files
+// matched by an earlier pattern and a list that only has exclusions never
+// matches.
 func (a Autoplan) Validate() error {
+	var patterns []string
+	for _, wm := range a.WhenModified {
+		patterns = append(patterns, strings.TrimSpace(wm))
+	}
+	if _, err := patternmatcher.New(patterns); err != nil {
+		return fmt.Errorf("when_modified: %w", err)
+	}
 	return nil
 }
 
@@ -48,3 +64,30 @@ func DefaultAutoPlan() valid.Autoplan {
 		Enabled:      valid.DefaultAutoPlanEnabled,
 	}
 }
+
+// RepoAutoplan is the repo-level autoplan config. It applies to all projects
+// in the repo.
+type RepoAutoplan struct {
+	// Ignore are patterns, relative to the repo root, for modified files
+	// that are ignored when determining which projects to plan.
+	Ignore []string `yaml:"ignore,omitempty"`
+}
+
+func (a RepoAutoplan) ToValid() *valid.RepoAutoplan {
+	return &valid.RepoAutoplan{Ignore: a.Ignore}
+}
+
+// Validate checks that the ignore patterns are valid. They use the same
+// syntax as when_modified so '!' re-includes files ignored by an earlier
+// pattern.
+func (a RepoAutoplan) Validate() error {
+	for _, ignore := range a.Ignore {
+		if strings.HasPrefix(strings.TrimSpace(ignore), "/") {
+			return fmt.Errorf("ignore: pattern must not begin with a slash '/', found %q", ignore)
+		}
+	}
+	if _, err := patternmatcher.New(a.Ignore); err != nil {
+		return fmt.Errorf("ignore: %w", err)
+	}
+	return nil
+}
@@ -92,6 +92,12 @@ func TestAutoplan_Validate(t *testing.T) {
 				Enabled: Bool(false),
 			},
 		},
+		{
+			description: "when_modified with exclusions",
+			input: raw.Autoplan{
+				WhenModified: []string{"**/*.tf", "!examples/**", " !docs/** "},
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.description, func(t *testing.T) {
@@ -100,6 +106,34 @@ func TestAutoplan_Validate(t *testing.T) {
 	}
 }
 
+func TestAutoplan_ValidateError(t *testing.T) {
+	cases := []struct {
+		description string
+		input       raw.Autoplan
+		expErr      string
+	}{
+		{
+			description: "empty exclusion",
+			input: raw.Autoplan{
+				WhenModified: []string{"**/*.tf", "!"},
+			},
+			expErr: "when_modified: illegal exclusion pattern: \"!\"",
+		},
+		{
+			description: "invalid pattern",
+			input: raw.Autoplan{
+				WhenModified: []string{"[*.tf"},
+			},
+			expErr: "when_modified: syntax error in pattern",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			ErrEquals(t, c.expErr, c.input.Validate())
+		})
+	}
+}
+
 func TestAutoplan_ToValid(t *testing.T) {
 	cases := []struct {
 		description string
@@ -151,3 +185,46 @@ func TestAutoplan_ToValid(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoAutoplan_Validate(t *testing.T) {
+	cases := []struct {
+		description string
+		input       raw.RepoAutoplan
+		expErr      string
+	}{
+		{
+			description: "nothing set",
+			input:       raw.RepoAutoplan{},
+		},
+		{
+			description: "ignore with exceptions",
+			input: raw.RepoAutoplan{
+				Ignore: []string{"**/generated/**", "!**/generated/keep.tf"},
+			},
+		},
+		{
+			description: "leading slash",
+			input: raw.RepoAutoplan{
+				Ignore: []string{"/generated/**"},
+			},
+			expErr: "ignore: pattern must not begin with a slash '/', found \"/generated/**\"",
+		},
+		{
+			description: "invalid pattern",
+			input: raw.RepoAutoplan{
+				Ignore: []string{"[*.tf"},
+			},
+			expErr: "ignore: syntax error in pattern",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			err := c.input.Validate()
+			if c.expErr == "" {
+				Ok(t, err)
+			} else {
+				ErrEquals(t, c.expErr, err)
+			}
+		})
+	}
+}
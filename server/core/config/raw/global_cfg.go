@@ -16,34 +16,42 @@ import (
 
 // GlobalCfg is the raw schema for server-side repo config.
 type GlobalCfg struct {
-	Repos      []Repo              `yaml:"repos" json:"repos"`
-	Workflows  map[string]Workflow `yaml:"workflows" json:"workflows"`
-	PolicySets PolicySets          `yaml:"policies" json:"policies"`
-	Metrics    Metrics             `yaml:"metrics" json:"metrics"`
-	TeamAuthz  TeamAuthz           `yaml:"team_authz" json:"team_authz"`
+	Repos              []Repo              `yaml:"repos" json:"repos"`
+	Workflows          map[string]Workflow `yaml:"workflows" json:"workflows"`
+	PolicySets         PolicySets          `yaml:"policies" json:"policies"`
+	Metrics            Metrics             `yaml:"metrics" json:"metrics"`
+	TeamAuthz          TeamAuthz           `yaml:"team_authz" json:"team_authz"`
+	AllowedShells      []string            `yaml:"allowed_shells,omitempty" json:"allowed_shells,omitempty"`
+	StepOutputDenylist []string            `yaml:"step_output_denylist,omitempty" json:"step_output_denylist,omitempty"`
+	StepOutputMasks    []string            `yaml:"step_output_masks,omitempty" json:"step_output_masks,omitempty"`
 }
 
 // Repo is the raw schema for repos in the server-side repo config.
 type Repo struct {
-	ID                        string         `yaml:"id" json:"id"`
-	Branch                    string         `yaml:"branch" json:"branch"`
-	RepoConfigFile            string         `yaml:"repo_config_file" json:"repo_config_file"`
-	PlanRequirements          []string       `yaml:"plan_requirements" json:"plan_requirements"`
-	ApplyRequirements         []string       `yaml:"apply_requirements" json:"apply_requirements"`
-	ImportRequirements        []string       `yaml:"import_requirements" json:"import_requirements"`
-	PreWorkflowHooks          []WorkflowHook `yaml:"pre_workflow_hooks" json:"pre_workflow_hooks"`
-	Workflow                  *string        `yaml:"workflow,omitempty" json:"workflow,omitempty"`
-	PostWorkflowHooks         []WorkflowHook `yaml:"post_workflow_hooks" json:"post_workflow_hooks"`
-	AllowedWorkflows          []string       `yaml:"allowed_workflows,omitempty" json:"allowed_workflows,omitempty"`
-	AllowedOverrides          []string       `yaml:"allowed_overrides" json:"allowed_overrides"`
-	AllowCustomWorkflows      *bool          `yaml:"allow_custom_workflows,omitempty" json:"allow_custom_workflows,omitempty"`
-	DeleteSourceBranchOnMerge *bool          `yaml:"delete_source_branch_on_merge,omitempty" json:"delete_source_branch_on_merge,omitempty"`
-	RepoLocking               *bool          `yaml:"repo_locking,omitempty" json:"repo_locking,omitempty"`
-	RepoLocks                 *RepoLocks     `yaml:"repo_locks,omitempty" json:"repo_locks,omitempty"`
-	PolicyCheck               *bool          `yaml:"policy_check,omitempty" json:"policy_check,omitempty"`
-	CustomPolicyCheck         *bool          `yaml:"custom_policy_check,omitempty" json:"custom_policy_check,omitempty"`
-	AutoDiscover              *AutoDiscover  `yaml:"autodiscover,omitempty" json:"autodiscover,omitempty"`
-	SilencePRComments         []string       `yaml:"silence_pr_comments,omitempty" json:"silence_pr_comments,omitempty"`
+	ID                        string            `yaml:"id" json:"id"`
+	Branch                    string            `yaml:"branch" json:"branch"`
+	RepoConfigFile            string            `yaml:"repo_config_file" json:"repo_config_file"`
+	RepoConfigURL             string            `yaml:"repo_config_url,omitempty" json:"repo_config_url,omitempty"`
+	PlanRequirements          []string          `yaml:"plan_requirements" json:"plan_requirements"`
+	ApplyRequirements         ApplyRequirements `yaml:"apply_requirements" json:"apply_requirements"`
+	ImportRequirements        []string          `yaml:"import_requirements" json:"import_requirements"`
+	PreWorkflowHooks          []WorkflowHook    `yaml:"pre_workflow_hooks" json:"pre_workflow_hooks"`
+	Workflow                  *string           `yaml:"workflow,omitempty" json:"workflow,omitempty"`
+	PostWorkflowHooks         []WorkflowHook    `yaml:"post_workflow_hooks" json:"post_workflow_hooks"`
+	AllowedWorkflows          []string          `yaml:"allowed_workflows,omitempty" json:"allowed_workflows,omitempty"`
+	AllowedOverrides          []string          `yaml:"allowed_overrides" json:"allowed_overrides"`
+	LockedOverrides           []string          `yaml:"locked_overrides,omitempty" json:"locked_overrides,omitempty"`
+	LockedOverridesAction     string            `yaml:"locked_overrides_action,omitempty" json:"locked_overrides_action,omitempty"`
+	AllowCustomWorkflows      *bool             `yaml:"allow_custom_workflows,omitempty" json:"allow_custom_workflows,omitempty"`
+	DeleteSourceBranchOnMerge *bool             `yaml:"delete_source_branch_on_merge,omitempty" json:"delete_source_branch_on_merge,omitempty"`
+	RepoLocking               *bool             `yaml:"repo_locking,omitempty" json:"repo_locking,omitempty"`
+	RepoLocks                 *RepoLocks        `yaml:"repo_locks,omitempty" json:"repo_locks,omitempty"`
+	PolicyCheck               *bool             `yaml:"policy_check,omitempty" json:"policy_check,omitempty"`
+	CustomPolicyCheck         *bool             `yaml:"custom_policy_check,omitempty" json:"custom_policy_check,omitempty"`
+	AutoDiscover              *AutoDiscover     `yaml:"autodiscover,omitempty" json:"autodiscover,omitempty"`
+	SilencePRComments         []string          `yaml:"silence_pr_comments,omitempty" json:"silence_pr_comments,omitempty"`
+	QuietPolicyChecks         *bool             `yaml:"quiet_policy_checks,omitempty" json:"quiet_policy_checks,omitempty"`
+	AllowedRunCommands        []string          `yaml:"allowed_run_commands,omitempty" json:"allowed_run_commands,omitempty"`
 }
 
 func (g GlobalCfg) Validate() error {
@@ -101,6 +109,30 @@ func (g GlobalCfg) Validate() error {
 		}
 	}
 
+	for _, shell := range g.AllowedShells {
+		if shell == "" {
+			return errors.New("allowed_shells: cannot contain an empty shell name")
+		}
+	}
+
+	for _, pattern := range g.StepOutputDenylist {
+		if pattern == "" {
+			return errors.New("step_output_denylist: cannot contain an empty pattern")
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("step_output_denylist: invalid regex %q: %w", pattern, err)
+		}
+	}
+
+	for _, pattern := range g.StepOutputMasks {
+		if pattern == "" {
+			return errors.New("step_output_masks: cannot contain an empty pattern")
+		}
+		if _, err := regexp.Compile(pattern); err != nil {
+			return fmt.Errorf("step_output_masks: invalid regex %q: %w", pattern, err)
+		}
+	}
+
 	// Validate supported SilencePRComments values.
 	for _, repo := range g.Repos {
 		if repo.SilencePRComments == nil {
@@ -160,12 +192,26 @@ func (g GlobalCfg) ToValid(defaultCfg valid.GlobalCfg) valid.GlobalCfg {
 	}
 	repos = append(defaultCfg.Repos, repos...)
 
+	var stepOutputDenylist []*regexp.Regexp
+	for _, pattern := range g.StepOutputDenylist {
+		// Safe to use MustCompile because we test it in Validate().
+		stepOutputDenylist = append(stepOutputDenylist, regexp.MustCompile(pattern))
+	}
+	var stepOutputMasks []*regexp.Regexp
+	for _, pattern := range g.StepOutputMasks {
+		// Safe to use MustCompile because we test it in Validate().
+		stepOutputMasks = append(stepOutputMasks, regexp.MustCompile(pattern))
+	}
+
 	return valid.GlobalCfg{
-		Repos:      repos,
-		Workflows:  workflows,
-		PolicySets: g.PolicySets.ToValid(),
-		Metrics:    g.Metrics.ToValid(),
-		TeamAuthz:  g.TeamAuthz.ToValid(),
+		Repos:              repos,
+		Workflows:          workflows,
+		PolicySets:         g.PolicySets.ToValid(),
+		Metrics:            g.Metrics.ToValid(),
+		TeamAuthz:          g.TeamAuthz.ToValid(),
+		AllowedShells:      g.AllowedShells,
+		StepOutputDenylist: stepOutputDenylist,
+		StepOutputMasks:    stepOutputMasks,
 	}
 }
 
@@ -233,6 +279,18 @@ func (r Repo) Validate() error {
 		return nil
 	}
 
+	lockedOverridesValid := func(value interface{}) error {
+		if err := overridesValid(value); err != nil {
+			return err
+		}
+		for _, o := range value.([]string) {
+			if utils.SlicesContains(r.AllowedOverrides, o) {
+				return fmt.Errorf("%q can't be in both %s and %s", o, valid.AllowedOverridesKey, valid.LockedOverridesKey)
+			}
+		}
+		return nil
+	}
+
 	workflowExists := func(value interface{}) error {
 		// We validate workflows in ParserValidator.validateRepoWorkflows
 		// because we need the list of workflows to validate.
@@ -260,11 +318,25 @@ func (r Repo) Validate() error {
 		return nil
 	}
 
+	allowedRunCommandsValid := func(value interface{}) error {
+		for _, pattern := range value.([]string) {
+			if pattern == "" {
+				return errors.New("cannot contain an empty pattern")
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid regex %q: %w", pattern, err)
+			}
+		}
+		return nil
+	}
+
 	return validation.ValidateStruct(&r,
 		validation.Field(&r.ID, validation.Required, validation.By(idValid)),
 		validation.Field(&r.Branch, validation.By(branchValid)),
 		validation.Field(&r.RepoConfigFile, validation.By(repoConfigFileValid)),
 		validation.Field(&r.AllowedOverrides, validation.By(overridesValid)),
+		validation.Field(&r.LockedOverrides, validation.By(lockedOverridesValid)),
+		validation.Field(&r.LockedOverridesAction, validation.In(valid.LockedOverridesRejectAction, valid.LockedOverridesIgnoreAction)),
 		validation.Field(&r.PlanRequirements, validation.By(validPlanReq)),
 		validation.Field(&r.ApplyRequirements, validation.By(validApplyReq)),
 		validation.Field(&r.ImportRequirements, validation.By(validImportReq)),
@@ -272,6 +344,7 @@ func (r Repo) Validate() error {
 		validation.Field(&r.DeleteSourceBranchOnMerge, validation.By(deleteSourceBranchOnMergeValid)),
 		validation.Field(&r.AutoDiscover, validation.By(autoDiscoverValid)),
 		validation.Field(&r.RepoLocks, validation.By(repoLocksValid)),
+		validation.Field(&r.AllowedRunCommands, validation.By(allowedRunCommandsValid)),
 	)
 }
 
@@ -318,7 +391,7 @@ func (r Repo) ToValid(workflows map[string]valid.Workflow, globalPlanReqs []stri
 	var mergedPlanReqs []string
 	mergedPlanReqs = append(mergedPlanReqs, r.PlanRequirements...)
 	var mergedApplyReqs []string
-	mergedApplyReqs = append(mergedApplyReqs, r.ApplyRequirements...)
+	mergedApplyReqs = append(mergedApplyReqs, r.ApplyRequirements.Names()...)
 	var mergedImportReqs []string
 	mergedImportReqs = append(mergedImportReqs, r.ImportRequirements...)
 
@@ -339,7 +412,7 @@ OuterGlobalPlanReqs:
 	}
 OuterGlobalApplyReqs:
 	for _, globalReq := range globalApplyReqs {
-		for _, currReq := range r.ApplyRequirements {
+		for _, currReq := range r.ApplyRequirements.Names() {
 			if globalReq == currReq {
 				continue OuterGlobalApplyReqs
 			}
@@ -376,19 +449,31 @@ OuterGlobalImportReqs:
 		repoLocks = r.RepoLocks.ToValid()
 	}
 
+	var allowedRunCommands []*regexp.Regexp
+	for _, pattern := range r.AllowedRunCommands {
+		// Commands must match the whole pattern so an allowed command can't
+		// be chained with another one. Safe to use MustCompile because we
+		// test it in Validate().
+		allowedRunCommands = append(allowedRunCommands, regexp.MustCompile("^(?:"+pattern+")$"))
+	}
+
 	return valid.Repo{
 		ID:                        id,
 		IDRegex:                   idRegex,
 		BranchRegex:               branchRegex,
 		RepoConfigFile:            r.RepoConfigFile,
+		RepoConfigURL:             r.RepoConfigURL,
 		PlanRequirements:          mergedPlanReqs,
 		ApplyRequirements:         mergedApplyReqs,
+		CustomApplyRequirements:   r.ApplyRequirements.Custom(),
 		ImportRequirements:        mergedImportReqs,
 		PreWorkflowHooks:          preWorkflowHooks,
 		Workflow:                  workflow,
 		PostWorkflowHooks:         postWorkflowHooks,
 		AllowedWorkflows:          r.AllowedWorkflows,
 		AllowedOverrides:          r.AllowedOverrides,
+		LockedOverrides:           r.LockedOverrides,
+		LockedOverridesAction:     r.LockedOverridesAction,
 		AllowCustomWorkflows:      r.AllowCustomWorkflows,
 		DeleteSourceBranchOnMerge: r.DeleteSourceBranchOnMerge,
 		RepoLocking:               r.RepoLocking,
@@ -397,5 +482,7 @@ OuterGlobalImportReqs:
 		CustomPolicyCheck:         r.CustomPolicyCheck,
 		AutoDiscover:              autoDiscover,
 		SilencePRComments:         r.SilencePRComments,
+		QuietPolicyChecks:         r.QuietPolicyChecks,
+		AllowedRunCommands:        allowedRunCommands,
 	}
 }
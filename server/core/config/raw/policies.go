@@ -79,6 +79,8 @@ type PolicySet struct {
 	Owners             PolicyOwners `yaml:"owners,omitempty" json:"owners,omitempty"`
 	ApproveCount       int          `yaml:"approve_count,omitempty" json:"approve_count,omitempty"`
 	PreventSelfApprove bool         `yaml:"prevent_self_approve,omitempty" json:"prevent_self_approve,omitempty"`
+	SoftFail           bool         `yaml:"soft_fail,omitempty" json:"soft_fail,omitempty"`
+	Approvers          []string     `yaml:"approvers,omitempty" json:"approvers,omitempty"`
 }
 
 func (p PolicySet) Validate() error {
@@ -99,6 +101,10 @@ func (p PolicySet) ToValid() valid.PolicySet {
 	policySet.Source = p.Source
 	policySet.ApproveCount = p.ApproveCount
 	policySet.PreventSelfApprove = p.PreventSelfApprove
+	policySet.SoftFail = p.SoftFail
+	if len(p.Approvers) > 0 {
+		policySet.Approvers = p.Approvers
+	}
 	policySet.Owners = p.Owners.ToValid()
 
 	return policySet
@@ -40,6 +40,48 @@ policy_sets:
 				},
 			},
 		},
+		{
+			description: "soft fail",
+			input: `
+conftest_version: v1.0.0
+policy_sets:
+- name: policy-name
+  source: "local"
+  path: "rel/path/to/policy-set"
+  soft_fail: true
+`,
+			exp: raw.PolicySets{
+				Version: String("v1.0.0"),
+				PolicySets: []raw.PolicySet{
+					{
+						Name:     "policy-name",
+						Source:   valid.LocalPolicySet,
+						Path:     "rel/path/to/policy-set",
+						SoftFail: true,
+					},
+				},
+			},
+		},
+		{
+			description: "approvers",
+			input: `
+policy_sets:
+- name: policy-name
+  source: "local"
+  path: "rel/path/to/policy-set"
+  approvers: [security]
+`,
+			exp: raw.PolicySets{
+				PolicySets: []raw.PolicySet{
+					{
+						Name:      "policy-name",
+						Source:    valid.LocalPolicySet,
+						Path:      "rel/path/to/policy-set",
+						Approvers: []string{"security"},
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -261,6 +303,33 @@ func TestPolicySets_ToValid(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "soft fail policy set",
+			input: raw.PolicySets{
+				Version: String("v1.0.0"),
+				PolicySets: []raw.PolicySet{
+					{
+						Name:     "warn-policy",
+						Path:     "rel/path/to/source",
+						Source:   valid.LocalPolicySet,
+						SoftFail: true,
+					},
+				},
+			},
+			exp: valid.PolicySets{
+				Version:      version,
+				ApproveCount: 1,
+				PolicySets: []valid.PolicySet{
+					{
+						Name:         "warn-policy",
+						Path:         "rel/path/to/source",
+						Source:       "local",
+						ApproveCount: 1,
+						SoftFail:     true,
+					},
+				},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -7,9 +7,11 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
+	"text/template"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	version "github.com/hashicorp/go-version"
@@ -21,28 +23,45 @@ const (
 	ApprovedRequirement   = "approved"
 	MergeableRequirement  = "mergeable"
 	UnDivergedRequirement = "undiverged"
+	// PipelineSuccessRequirement requires the merge request's latest GitLab
+	// pipeline to succeed. It's ignored for other VCS hosts.
+	PipelineSuccessRequirement = "pipeline_success"
+	// ExternalApprovalRequirement requires the pull request's latest commit
+	// to be approved through the approvals API.
+	ExternalApprovalRequirement = "external_approval"
 )
 
 type Project struct {
-	Name                      *string    `yaml:"name,omitempty"`
-	Branch                    *string    `yaml:"branch,omitempty"`
-	Dir                       *string    `yaml:"dir,omitempty"`
-	Workspace                 *string    `yaml:"workspace,omitempty"`
-	Workflow                  *string    `yaml:"workflow,omitempty"`
-	TerraformDistribution     *string    `yaml:"terraform_distribution,omitempty"`
-	TerraformVersion          *string    `yaml:"terraform_version,omitempty"`
-	Autoplan                  *Autoplan  `yaml:"autoplan,omitempty"`
-	PlanRequirements          []string   `yaml:"plan_requirements,omitempty"`
-	ApplyRequirements         []string   `yaml:"apply_requirements,omitempty"`
-	ImportRequirements        []string   `yaml:"import_requirements,omitempty"`
-	DependsOn                 []string   `yaml:"depends_on,omitempty"`
-	DeleteSourceBranchOnMerge *bool      `yaml:"delete_source_branch_on_merge,omitempty"`
-	RepoLocking               *bool      `yaml:"repo_locking,omitempty"`
-	RepoLocks                 *RepoLocks `yaml:"repo_locks,omitempty"`
-	ExecutionOrderGroup       *int       `yaml:"execution_order_group,omitempty"`
-	PolicyCheck               *bool      `yaml:"policy_check,omitempty"`
-	CustomPolicyCheck         *bool      `yaml:"custom_policy_check,omitempty"`
-	SilencePRComments         []string   `yaml:"silence_pr_comments,omitempty"`
+	Name                      *string           `yaml:"name,omitempty"`
+	Branch                    *string           `yaml:"branch,omitempty"`
+	Dir                       *string           `yaml:"dir,omitempty"`
+	Workspace                 *string           `yaml:"workspace,omitempty"`
+	AllowedWorkspaces         []string          `yaml:"allowed_workspaces,omitempty"`
+	Workflow                  *string           `yaml:"workflow,omitempty"`
+	TerraformDistribution     *string           `yaml:"terraform_distribution,omitempty"`
+	TerraformVersion          *string           `yaml:"terraform_version,omitempty"`
+	Autoplan                  *Autoplan         `yaml:"autoplan,omitempty"`
+	PlanRequirements          []string          `yaml:"plan_requirements,omitempty"`
+	ApplyRequirements         ApplyRequirements `yaml:"apply_requirements,omitempty"`
+	ImportRequirements        []string          `yaml:"import_requirements,omitempty"`
+	DependsOn                 []string          `yaml:"depends_on,omitempty"`
+	DeleteSourceBranchOnMerge *bool             `yaml:"delete_source_branch_on_merge,omitempty"`
+	RepoLocking               *bool             `yaml:"repo_locking,omitempty"`
+	RepoLocks                 *RepoLocks        `yaml:"repo_locks,omitempty"`
+	ExecutionOrderGroup       *int              `yaml:"execution_order_group,omitempty"`
+	PolicyCheck               *bool             `yaml:"policy_check,omitempty"`
+	CustomPolicyCheck         *bool             `yaml:"custom_policy_check,omitempty"`
+	SilencePRComments         []string          `yaml:"silence_pr_comments,omitempty"`
+	NoChangesMessage          *string           `yaml:"no_changes_message,omitempty"`
+	HidePrevPlanComments      *bool             `yaml:"hide_prev_plan_comments,omitempty"`
+	PlanFilePath              *string           `yaml:"plan_file_path,omitempty"`
+	InitUpgrade               *bool             `yaml:"init_upgrade,omitempty"`
+	PushLockFile              *bool             `yaml:"push_lock_file,omitempty"`
+	Automerge                 *bool             `yaml:"automerge,omitempty"`
+	DestroyThreshold          *int              `yaml:"destroy_threshold,omitempty"`
+	DestroyThresholdApproval  *bool             `yaml:"destroy_threshold_approval,omitempty"`
+	WorkspaceTfvars           *bool             `yaml:"workspace_tfvars,omitempty"`
+	Labels                    map[string]string `yaml:"labels,omitempty"`
 }
 
 func (p Project) Validate() error {
@@ -73,8 +92,11 @@ func (p Project) Validate() error {
 			return nil
 		}
 		branch := *strPtr
-		if !strings.HasPrefix(branch, "/") || !strings.HasSuffix(branch, "/") {
-			return errors.New("regex must begin and end with a slash '/'")
+		if !isBranchRegex(branch) {
+			if _, err := path.Match(branch, ""); err != nil {
+				return fmt.Errorf("parsing glob: %s: %w", branch, err)
+			}
+			return nil
 		}
 		withoutSlashes := branch[1 : len(branch)-1]
 		_, err := regexp.Compile(withoutSlashes)
@@ -88,16 +110,38 @@ func (p Project) Validate() error {
 		return nil
 	}
 
+	validAllowedWorkspaces := func(value interface{}) error {
+		seen := make(map[string]bool)
+		for _, w := range value.([]string) {
+			if strings.TrimSpace(w) == "" {
+				return errors.New("workspace names cannot be empty")
+			}
+			if seen[w] {
+				return fmt.Errorf("workspace %q is listed more than once", w)
+			}
+			seen[w] = true
+		}
+		return nil
+	}
+
 	return validation.ValidateStruct(&p,
 		validation.Field(&p.Dir, validation.Required, validation.By(hasDotDot)),
 		validation.Field(&p.PlanRequirements, validation.By(validPlanReq)),
-		validation.Field(&p.ApplyRequirements, validation.By(validApplyReq)),
+		validation.Field(&p.ApplyRequirements, validation.By(validRepoApplyReq)),
 		validation.Field(&p.ImportRequirements, validation.By(validImportReq)),
 		validation.Field(&p.TerraformDistribution, validation.By(validDistribution)),
 		validation.Field(&p.TerraformVersion, validation.By(VersionValidator)),
 		validation.Field(&p.DependsOn, validation.By(DependsOn)),
 		validation.Field(&p.Name, validation.By(validName)),
 		validation.Field(&p.Branch, validation.By(branchValid)),
+		validation.Field(&p.AllowedWorkspaces, validation.By(validAllowedWorkspaces)),
+		validation.Field(&p.Autoplan),
+		validation.Field(&p.NoChangesMessage, validation.By(validNoChangesMessage)),
+		validation.Field(&p.Labels, validation.By(validLabels)),
+		validation.Field(&p.PlanFilePath, validation.By(validPlanFilePath)),
+		validation.Field(&p.ExecutionOrderGroup, validation.By(validExecutionOrderGroup)),
+		validation.Field(&p.DestroyThreshold, validation.By(validDestroyThreshold)),
+		validation.Field(&p.DestroyThresholdApproval, validation.By(p.validDestroyThresholdApproval)),
 	)
 }
 
@@ -109,11 +153,13 @@ func (p Project) ToValid() valid.Project {
 	cleanedDir := filepath.Clean("./" + *p.Dir)
 	v.Dir = cleanedDir
 
-	if p.Branch != nil {
+	if p.Branch != nil && isBranchRegex(*p.Branch) {
 		branch := *p.Branch
 		withoutSlashes := branch[1 : len(branch)-1]
 		// Safe to use MustCompile because we test it in Validate().
 		v.BranchRegex = regexp.MustCompile(withoutSlashes)
+	} else if p.Branch != nil {
+		v.BranchGlob = *p.Branch
 	}
 
 	if p.Workspace == nil || *p.Workspace == "" {
@@ -121,6 +167,7 @@ func (p Project) ToValid() valid.Project {
 	} else {
 		v.Workspace = *p.Workspace
 	}
+	v.AllowedWorkspaces = p.AllowedWorkspaces
 
 	v.WorkflowName = p.Workflow
 	if p.TerraformVersion != nil {
@@ -137,7 +184,7 @@ func (p Project) ToValid() valid.Project {
 
 	// There are no default apply/import requirements.
 	v.PlanRequirements = p.PlanRequirements
-	v.ApplyRequirements = p.ApplyRequirements
+	v.ApplyRequirements = p.ApplyRequirements.Names()
 	v.ImportRequirements = p.ImportRequirements
 
 	v.Name = p.Name
@@ -172,6 +219,46 @@ func (p Project) ToValid() valid.Project {
 		v.SilencePRComments = p.SilencePRComments
 	}
 
+	if p.NoChangesMessage != nil {
+		v.NoChangesMessage = *p.NoChangesMessage
+	}
+
+	if p.Labels != nil {
+		v.Labels = p.Labels
+	}
+
+	if p.HidePrevPlanComments != nil {
+		v.HidePrevPlanComments = p.HidePrevPlanComments
+	}
+
+	if p.Automerge != nil {
+		v.Automerge = p.Automerge
+	}
+
+	if p.PlanFilePath != nil {
+		v.PlanFilePath = *p.PlanFilePath
+	}
+
+	if p.InitUpgrade != nil {
+		v.InitUpgrade = *p.InitUpgrade
+	}
+
+	if p.PushLockFile != nil {
+		v.PushLockFile = *p.PushLockFile
+	}
+
+	if p.DestroyThreshold != nil {
+		v.DestroyThreshold = *p.DestroyThreshold
+	}
+
+	if p.DestroyThresholdApproval != nil {
+		v.DestroyThresholdApproval = *p.DestroyThresholdApproval
+	}
+
+	if p.WorkspaceTfvars != nil {
+		v.WorkspaceTfvars = *p.WorkspaceTfvars
+	}
+
 	return v
 }
 
@@ -194,11 +281,29 @@ func validPlanReq(value interface{}) error {
 	return nil
 }
 
+// validRepoApplyReq validates the apply requirements of a repo config, which
+// can't set custom requirements since their commands run on the server.
+func validRepoApplyReq(value interface{}) error {
+	for _, r := range value.(ApplyRequirements) {
+		if r.Custom != nil {
+			return fmt.Errorf("%s apply_requirements can only be set in the server-side repo config", CustomRequirementKey)
+		}
+	}
+	return validApplyReq(value)
+}
+
 func validApplyReq(value interface{}) error {
-	reqs := value.([]string)
-	for _, r := range reqs {
-		if r != ApprovedRequirement && r != MergeableRequirement && r != UnDivergedRequirement {
-			return fmt.Errorf("%q is not a valid apply_requirement, only %q, %q and %q are supported", r, ApprovedRequirement, MergeableRequirement, UnDivergedRequirement)
+	reqs := value.(ApplyRequirements)
+	for _, req := range reqs {
+		if req.Custom != nil {
+			if strings.TrimSpace(req.Custom.Command) == "" {
+				return fmt.Errorf("%s apply_requirement must have a non-empty %q", CustomRequirementKey, CustomRequirementCommandKey)
+			}
+			continue
+		}
+		r := req.Name
+		if r != ApprovedRequirement && r != MergeableRequirement && r != UnDivergedRequirement && r != PipelineSuccessRequirement && r != ExternalApprovalRequirement {
+			return fmt.Errorf("%q is not a valid apply_requirement, only %q, %q, %q, %q, %q and %q are supported", r, ApprovedRequirement, MergeableRequirement, UnDivergedRequirement, PipelineSuccessRequirement, ExternalApprovalRequirement, CustomRequirementKey)
 		}
 	}
 	return nil
@@ -214,6 +319,71 @@ func validImportReq(value interface{}) error {
 	return nil
 }
 
+func validNoChangesMessage(value interface{}) error {
+	strPtr := value.(*string)
+	if strPtr != nil && strings.TrimSpace(*strPtr) == "" {
+		return errors.New("if set cannot be empty")
+	}
+	return nil
+}
+
+// labelKeyRegex matches the label keys that can be rendered in comment
+// templates as fields, ex. {{ .Project.Labels.team }}.
+var labelKeyRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+func validLabels(value interface{}) error {
+	for k := range value.(map[string]string) {
+		if !labelKeyRegex.MatchString(k) {
+			return fmt.Errorf("key %q must only contain letters, digits and underscores and not start with a digit", k)
+		}
+	}
+	return nil
+}
+
+func validPlanFilePath(value interface{}) error {
+	strPtr := value.(*string)
+	if strPtr == nil {
+		return nil
+	}
+	if strings.TrimSpace(*strPtr) == "" {
+		return errors.New("if set cannot be empty")
+	}
+	if filepath.IsAbs(*strPtr) {
+		return errors.New("must be relative to the project's dir")
+	}
+	if _, err := template.New("plan_file_path").Parse(*strPtr); err != nil {
+		return fmt.Errorf("is not a valid template: %w", err)
+	}
+	return nil
+}
+
+func validExecutionOrderGroup(value interface{}) error {
+	group := value.(*int)
+	if group != nil && *group < 0 {
+		return fmt.Errorf("%d is not allowed: must be greater than or equal to 0", *group)
+	}
+	return nil
+}
+
+func validDestroyThreshold(value interface{}) error {
+	threshold := value.(*int)
+	if threshold != nil && *threshold < 0 {
+		return fmt.Errorf("%d is not allowed: must be greater than or equal to 0", *threshold)
+	}
+	return nil
+}
+
+// validDestroyThresholdApproval checks that destroy_threshold_approval is
+// only enabled with a destroy_threshold since there's nothing to approve
+// otherwise.
+func (p Project) validDestroyThresholdApproval(value interface{}) error {
+	approval := value.(*bool)
+	if approval != nil && *approval && (p.DestroyThreshold == nil || *p.DestroyThreshold == 0) {
+		return errors.New("requires destroy_threshold to be set")
+	}
+	return nil
+}
+
 func validDistribution(value interface{}) error {
 	distribution := value.(*string)
 	if distribution != nil && *distribution != "terraform" && *distribution != "opentofu" {
@@ -221,3 +391,9 @@ func validDistribution(value interface{}) error {
 	}
 	return nil
 }
+
+// isBranchRegex returns true if the project's branch is a regex, i.e. it's
+// wrapped in slashes, and not a glob.
+func isBranchRegex(branch string) bool {
+	return len(branch) >= 2 && strings.HasPrefix(branch, "/") && strings.HasSuffix(branch, "/")
+}
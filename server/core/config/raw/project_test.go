@@ -66,11 +66,22 @@ execution_order_group: 10`,
 					Enabled:      Bool(false),
 				},
 				PlanRequirements:    []string{"mergeable"},
-				ApplyRequirements:   []string{"mergeable"},
+				ApplyRequirements:   raw.ApplyRequirements{{Name: "mergeable"}},
 				ImportRequirements:  []string{"mergeable"},
 				ExecutionOrderGroup: Int(10),
 			},
 		},
+		{
+			description: "custom apply requirement",
+			input: `
+apply_requirements:
+- approved
+- custom:
+    command: ./check-ticket.sh`,
+			exp: raw.Project{
+				ApplyRequirements: raw.ApplyRequirements{{Name: "approved"}, {Custom: &raw.CustomRequirement{Command: "./check-ticket.sh"}}},
+			},
+		},
 	}
 
 	for _, c := range cases {
@@ -111,12 +122,86 @@ func TestProject_Validate(t *testing.T) {
 			expErr: "dir: cannot contain '..'.",
 		},
 		{
-			description: "not a regexp for branch",
+			description: "allowed workspaces",
+			input: raw.Project{
+				Dir:               String("."),
+				AllowedWorkspaces: []string{"staging", "production"},
+			},
+			expErr: "",
+		},
+		{
+			description: "empty allowed workspace",
+			input: raw.Project{
+				Dir:               String("."),
+				AllowedWorkspaces: []string{"staging", " "},
+			},
+			expErr: "allowed_workspaces: workspace names cannot be empty.",
+		},
+		{
+			description: "duplicate allowed workspace",
+			input: raw.Project{
+				Dir:               String("."),
+				AllowedWorkspaces: []string{"staging", "staging"},
+			},
+			expErr: "allowed_workspaces: workspace \"staging\" is listed more than once.",
+		},
+		{
+			description: "execution order group zero",
+			input: raw.Project{
+				Dir:                 String("."),
+				ExecutionOrderGroup: Int(0),
+			},
+			expErr: "",
+		},
+		{
+			description: "negative execution order group",
+			input: raw.Project{
+				Dir:                 String("."),
+				ExecutionOrderGroup: Int(-1),
+			},
+			expErr: "execution_order_group: -1 is not allowed: must be greater than or equal to 0.",
+		},
+		{
+			description: "destroy threshold with approval",
+			input: raw.Project{
+				Dir:                      String("."),
+				DestroyThreshold:         Int(5),
+				DestroyThresholdApproval: Bool(true),
+			},
+			expErr: "",
+		},
+		{
+			description: "negative destroy threshold",
+			input: raw.Project{
+				Dir:              String("."),
+				DestroyThreshold: Int(-1),
+			},
+			expErr: "destroy_threshold: -1 is not allowed: must be greater than or equal to 0.",
+		},
+		{
+			description: "destroy threshold approval without threshold",
+			input: raw.Project{
+				Dir:                      String("."),
+				DestroyThreshold:         Int(0),
+				DestroyThresholdApproval: Bool(true),
+			},
+			expErr: "destroy_threshold_approval: requires destroy_threshold to be set.",
+		},
+		{
+			description: "glob for branch",
+			input: raw.Project{
+				Branch: String("release/*"),
+				Dir:    String("."),
+			},
+			expErr: "",
+		},
+		{
+			description: "invalid glob for branch",
 			input: raw.Project{
-				Branch: String("text"),
+				Branch: String("release/[0-9"),
 				Dir:    String("."),
 			},
-			expErr: "branch: regex must begin and end with a slash '/'.",
+			expErr: "branch: parsing glob: release/[0-9: syntax error in pattern.",
 		},
 		{
 			description: "invalid regexp for branch",
@@ -170,15 +255,15 @@ func TestProject_Validate(t *testing.T) {
 			description: "apply reqs with unsupported",
 			input: raw.Project{
 				Dir:               String("."),
-				ApplyRequirements: []string{"unsupported"},
+				ApplyRequirements: raw.ApplyRequirements{{Name: "unsupported"}},
 			},
-			expErr: "apply_requirements: \"unsupported\" is not a valid apply_requirement, only \"approved\", \"mergeable\" and \"undiverged\" are supported.",
+			expErr: "apply_requirements: \"unsupported\" is not a valid apply_requirement, only \"approved\", \"mergeable\", \"undiverged\", \"pipeline_success\", \"external_approval\" and \"custom\" are supported.",
 		},
 		{
 			description: "apply reqs with approved requirement",
 			input: raw.Project{
 				Dir:               String("."),
-				ApplyRequirements: []string{"approved"},
+				ApplyRequirements: raw.ApplyRequirements{{Name: "approved"}},
 			},
 			expErr: "",
 		},
@@ -186,7 +271,7 @@ func TestProject_Validate(t *testing.T) {
 			description: "apply reqs with mergeable requirement",
 			input: raw.Project{
 				Dir:               String("."),
-				ApplyRequirements: []string{"mergeable"},
+				ApplyRequirements: raw.ApplyRequirements{{Name: "mergeable"}},
 			},
 			expErr: "",
 		},
@@ -194,7 +279,7 @@ func TestProject_Validate(t *testing.T) {
 			description: "apply reqs with undiverged requirement",
 			input: raw.Project{
 				Dir:               String("."),
-				ApplyRequirements: []string{"undiverged"},
+				ApplyRequirements: raw.ApplyRequirements{{Name: "undiverged"}},
 			},
 			expErr: "",
 		},
@@ -202,7 +287,7 @@ func TestProject_Validate(t *testing.T) {
 			description: "apply reqs with mergeable and approved requirements",
 			input: raw.Project{
 				Dir:               String("."),
-				ApplyRequirements: []string{"mergeable", "approved"},
+				ApplyRequirements: raw.ApplyRequirements{{Name: "mergeable"}, {Name: "approved"}},
 			},
 			expErr: "",
 		},
@@ -210,7 +295,7 @@ func TestProject_Validate(t *testing.T) {
 			description: "apply reqs with undiverged and approved requirements",
 			input: raw.Project{
 				Dir:               String("."),
-				ApplyRequirements: []string{"undiverged", "approved"},
+				ApplyRequirements: raw.ApplyRequirements{{Name: "undiverged"}, {Name: "approved"}},
 			},
 			expErr: "",
 		},
@@ -218,7 +303,7 @@ func TestProject_Validate(t *testing.T) {
 			description: "apply reqs with undiverged and mergeable requirements",
 			input: raw.Project{
 				Dir:               String("."),
-				ApplyRequirements: []string{"undiverged", "mergeable"},
+				ApplyRequirements: raw.ApplyRequirements{{Name: "undiverged"}, {Name: "mergeable"}},
 			},
 			expErr: "",
 		},
@@ -226,10 +311,58 @@ func TestProject_Validate(t *testing.T) {
 			description: "apply reqs with undiverged, mergeable and approved requirements",
 			input: raw.Project{
 				Dir:               String("."),
-				ApplyRequirements: []string{"undiverged", "mergeable", "approved"},
+				ApplyRequirements: raw.ApplyRequirements{{Name: "undiverged"}, {Name: "mergeable"}, {Name: "approved"}},
+			},
+			expErr: "",
+		},
+		{
+			description: "empty no_changes_message",
+			input: raw.Project{
+				Dir:              String("."),
+				NoChangesMessage: String(" "),
+			},
+			expErr: "no_changes_message: if set cannot be empty.",
+		},
+		{
+			description: "labels",
+			input: raw.Project{
+				Dir:    String("."),
+				Labels: map[string]string{"team": "platform", "cost_center": "42"},
 			},
 			expErr: "",
 		},
+		{
+			description: "invalid label key",
+			input: raw.Project{
+				Dir:    String("."),
+				Labels: map[string]string{"cost-center": "42"},
+			},
+			expErr: "labels: key \"cost-center\" must only contain letters, digits and underscores and not start with a digit.",
+		},
+		{
+			description: "absolute plan_file_path",
+			input: raw.Project{
+				Dir:          String("."),
+				PlanFilePath: String("/plans/{{ .Workspace }}.tfplan"),
+			},
+			expErr: "plan_file_path: must be relative to the project's dir.",
+		},
+		{
+			description: "invalid plan_file_path template",
+			input: raw.Project{
+				Dir:          String("."),
+				PlanFilePath: String("plans/{{ .Workspace }.tfplan"),
+			},
+			expErr: "plan_file_path: is not a valid template: template: plan_file_path:1: unexpected \"}\" in operand.",
+		},
+		{
+			description: "apply reqs with custom requirement",
+			input: raw.Project{
+				Dir:               String("."),
+				ApplyRequirements: raw.ApplyRequirements{{Name: "approved"}, {Custom: &raw.CustomRequirement{Command: "./check-ticket.sh"}}},
+			},
+			expErr: "apply_requirements: custom apply_requirements can only be set in the server-side repo config.",
+		},
 		{
 			description: "import reqs with unsupported",
 			input: raw.Project{
@@ -362,10 +495,20 @@ func TestProject_ToValid(t *testing.T) {
 		{
 			description: "all set",
 			input: raw.Project{
-				Dir:              String("."),
-				Workspace:        String("myworkspace"),
-				Workflow:         String("myworkflow"),
-				TerraformVersion: String("v0.11.0"),
+				Dir:                  String("."),
+				Workspace:            String("myworkspace"),
+				AllowedWorkspaces:    []string{"staging"},
+				NoChangesMessage:     String("Nothing to see here."),
+				Labels:               map[string]string{"team": "platform"},
+				HidePrevPlanComments: Bool(true),
+				PlanFilePath:         String("plans/{{ .Workspace }}.tfplan"),
+				InitUpgrade:          Bool(true),
+				PushLockFile:         Bool(true),
+				Automerge:            Bool(false),
+				DestroyThreshold:     Int(5),
+				WorkspaceTfvars:      Bool(true),
+				Workflow:             String("myworkflow"),
+				TerraformVersion:     String("v0.11.0"),
 				Autoplan: &raw.Autoplan{
 					WhenModified: []string{"hi"},
 					Enabled:      Bool(false),
@@ -373,15 +516,25 @@ func TestProject_ToValid(t *testing.T) {
 				RepoLocks: &raw.RepoLocks{
 					Mode: &repoLocksOnApply,
 				},
-				ApplyRequirements:   []string{"approved"},
+				ApplyRequirements:   raw.ApplyRequirements{{Name: "approved"}},
 				Name:                String("myname"),
 				ExecutionOrderGroup: Int(10),
 			},
 			exp: valid.Project{
-				Dir:              ".",
-				Workspace:        "myworkspace",
-				WorkflowName:     String("myworkflow"),
-				TerraformVersion: tfVersionPointEleven,
+				Dir:                  ".",
+				Workspace:            "myworkspace",
+				AllowedWorkspaces:    []string{"staging"},
+				NoChangesMessage:     "Nothing to see here.",
+				Labels:               map[string]string{"team": "platform"},
+				HidePrevPlanComments: Bool(true),
+				PlanFilePath:         "plans/{{ .Workspace }}.tfplan",
+				InitUpgrade:          true,
+				PushLockFile:         true,
+				Automerge:            Bool(false),
+				DestroyThreshold:     5,
+				WorkspaceTfvars:      true,
+				WorkflowName:         String("myworkflow"),
+				TerraformVersion:     tfVersionPointEleven,
 				Autoplan: valid.Autoplan{
 					WhenModified: []string{"hi"},
 					Enabled:      false,
@@ -511,6 +664,22 @@ func TestProject_ToValid(t *testing.T) {
 			},
 		},
 
+		{
+			description: "branch glob",
+			input: raw.Project{
+				Dir:    String("."),
+				Branch: String("release/*"),
+			},
+			exp: valid.Project{
+				Dir:        ".",
+				BranchGlob: "release/*",
+				Workspace:  "default",
+				Autoplan: valid.Autoplan{
+					WhenModified: raw.DefaultAutoPlanWhenModified,
+					Enabled:      true,
+				},
+			},
+		},
 		{
 			description: "workspace set to empty string",
 			input: raw.Project{
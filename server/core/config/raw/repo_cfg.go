@@ -5,6 +5,7 @@ package raw
 
 import (
 	"errors"
+	"fmt"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -23,7 +24,9 @@ type RepoCfg struct {
 	Workflows                 map[string]Workflow `yaml:"workflows,omitempty"`
 	PolicySets                PolicySets          `yaml:"policies,omitempty"`
 	AutoDiscover              *AutoDiscover       `yaml:"autodiscover,omitempty"`
+	Autoplan                  *RepoAutoplan       `yaml:"autoplan,omitempty"`
 	Automerge                 *bool               `yaml:"automerge,omitempty"`
+	AutomergeMethod           *string             `yaml:"automerge_method,omitempty"`
 	ParallelApply             *bool               `yaml:"parallel_apply,omitempty"`
 	ParallelPlan              *bool               `yaml:"parallel_plan,omitempty"`
 	DeleteSourceBranchOnMerge *bool               `yaml:"delete_source_branch_on_merge,omitempty"`
@@ -49,6 +52,9 @@ func (r RepoCfg) Validate() error {
 		validation.Field(&r.Version, validation.By(equals2)),
 		validation.Field(&r.Projects),
 		validation.Field(&r.Workflows),
+		validation.Field(&r.Autoplan),
+		validation.Field(&r.AutomergeMethod, validation.In(valid.MergeAutomergeMethod, valid.RebaseAutomergeMethod, valid.SquashAutomergeMethod).Error(
+			fmt.Sprintf("must be one of %s, %s or %s", valid.MergeAutomergeMethod, valid.RebaseAutomergeMethod, valid.SquashAutomergeMethod))),
 	)
 }
 
@@ -64,6 +70,10 @@ func (r RepoCfg) ToValid() valid.RepoCfg {
 	}
 
 	automerge := r.Automerge
+	var automergeMethod string
+	if r.AutomergeMethod != nil {
+		automergeMethod = *r.AutomergeMethod
+	}
 	parallelApply := r.ParallelApply
 	parallelPlan := r.ParallelPlan
 
@@ -82,6 +92,11 @@ func (r RepoCfg) ToValid() valid.RepoCfg {
 		autoDiscover = r.AutoDiscover.ToValid()
 	}
 
+	var autoplan *valid.RepoAutoplan
+	if r.Autoplan != nil {
+		autoplan = r.Autoplan.ToValid()
+	}
+
 	var repoLocks *valid.RepoLocks
 	if r.RepoLocks != nil {
 		repoLocks = r.RepoLocks.ToValid()
@@ -91,7 +106,9 @@ func (r RepoCfg) ToValid() valid.RepoCfg {
 		Projects:                  validProjects,
 		Workflows:                 validWorkflows,
 		AutoDiscover:              autoDiscover,
+		Autoplan:                  autoplan,
 		Automerge:                 automerge,
+		AutomergeMethod:           automergeMethod,
 		ParallelApply:             parallelApply,
 		ParallelPlan:              parallelPlan,
 		ParallelPolicyCheck:       parallelPlan,
@@ -181,7 +181,7 @@ allowed_regexp_prefixes:
 							WhenModified: []string{},
 							Enabled:      Bool(false),
 						},
-						ApplyRequirements: []string{"mergeable"},
+						ApplyRequirements: raw.ApplyRequirements{{Name: "mergeable"}},
 						RepoLocks:         &raw.RepoLocks{Mode: &repoLocksDisabled},
 					},
 				},
@@ -236,6 +236,21 @@ func TestConfig_Validate(t *testing.T) {
 			},
 			expErr: "version: only versions 2 and 3 are supported.",
 		},
+		{
+			description: "automerge_method valid",
+			input: raw.RepoCfg{
+				Version:         Int(3),
+				AutomergeMethod: String("squash"),
+			},
+		},
+		{
+			description: "automerge_method invalid",
+			input: raw.RepoCfg{
+				Version:         Int(3),
+				AutomergeMethod: String("fast-forward"),
+			},
+			expErr: "automerge_method: must be one of merge, rebase or squash.",
+		},
 	}
 	validation.ErrorTag = "yaml"
 	for _, c := range cases {
@@ -312,6 +327,18 @@ func TestConfig_ToValid(t *testing.T) {
 				Workflows:                 map[string]valid.Workflow{},
 			},
 		},
+		{
+			description: "automerge_method set",
+			input: raw.RepoCfg{
+				Version:         Int(2),
+				AutomergeMethod: String("rebase"),
+			},
+			exp: valid.RepoCfg{
+				Version:         2,
+				AutomergeMethod: "rebase",
+				Workflows:       map[string]valid.Workflow{},
+			},
+		},
 		{
 			description: "automerge, parallel_apply, abort_on_execution_order_fail false",
 			input: raw.RepoCfg{
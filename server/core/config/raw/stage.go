@@ -4,17 +4,76 @@
 package raw
 
 import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 )
 
+// MaxStageRetryAttempts is the maximum number of times a stage can be run
+// when retries are configured.
+const MaxStageRetryAttempts = 10
+
 type Stage struct {
-	Steps []Step `yaml:"steps,omitempty" json:"steps,omitempty"`
+	Steps []Step      `yaml:"steps,omitempty" json:"steps,omitempty"`
+	Retry *StageRetry `yaml:"retry,omitempty" json:"retry,omitempty"`
+}
+
+// StageRetry configures re-running the whole stage when it fails with output
+// matching one of the patterns, ex. for transient rate limit errors.
+type StageRetry struct {
+	MaxAttempts int      `yaml:"max_attempts,omitempty" json:"max_attempts,omitempty"`
+	Backoff     string   `yaml:"backoff,omitempty" json:"backoff,omitempty"`
+	Patterns    []string `yaml:"patterns,omitempty" json:"patterns,omitempty"`
 }
 
 func (s Stage) Validate() error {
 	return validation.ValidateStruct(&s,
 		validation.Field(&s.Steps),
+		validation.Field(&s.Retry),
+	)
+}
+
+func (r StageRetry) Validate() error {
+	validPatterns := func(value interface{}) error {
+		patterns := value.([]string)
+		if len(patterns) == 0 {
+			return errors.New("at least one pattern is required")
+		}
+		for _, p := range patterns {
+			if p == "" {
+				return errors.New("patterns cannot be empty")
+			}
+			if _, err := regexp.Compile(p); err != nil {
+				return fmt.Errorf("parsing: %s: %w", p, err)
+			}
+		}
+		return nil
+	}
+	validBackoff := func(value interface{}) error {
+		backoff := value.(string)
+		if backoff == "" {
+			return nil
+		}
+		d, err := time.ParseDuration(backoff)
+		if err != nil {
+			return err
+		}
+		if d < 0 {
+			return errors.New("must not be negative")
+		}
+		if d > valid.MaxStageRetryBackoff {
+			return fmt.Errorf("must not be more than %s", valid.MaxStageRetryBackoff)
+		}
+		return nil
+	}
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.MaxAttempts, validation.Required, validation.Min(2), validation.Max(MaxStageRetryAttempts)),
+		validation.Field(&r.Backoff, validation.By(validBackoff)),
+		validation.Field(&r.Patterns, validation.By(validPatterns)),
 	)
 }
 
@@ -25,5 +84,23 @@ func (s Stage) ToValid() valid.Stage {
 	}
 	return valid.Stage{
 		Steps: validSteps,
+		Retry: s.Retry.ToValid(),
+	}
+}
+
+func (r *StageRetry) ToValid() *valid.StageRetry {
+	if r == nil {
+		return nil
+	}
+	// Backoff and patterns were checked in Validate.
+	backoff, _ := time.ParseDuration(r.Backoff)
+	var patterns []*regexp.Regexp
+	for _, p := range r.Patterns {
+		patterns = append(patterns, regexp.MustCompile(p))
+	}
+	return &valid.StageRetry{
+		MaxAttempts: r.MaxAttempts,
+		Backoff:     backoff,
+		Patterns:    patterns,
 	}
 }
@@ -4,7 +4,9 @@
 package raw_test
 
 import (
+	"regexp"
 	"testing"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/runatlantis/atlantis/server/core/config/raw"
@@ -66,6 +68,83 @@ func TestStage_Validate(t *testing.T) {
 	Ok(t, (raw.Stage{}).Validate())
 }
 
+func TestStage_ValidateRetry(t *testing.T) {
+	cases := []struct {
+		description string
+		retry       raw.StageRetry
+		expErr      string
+	}{
+		{
+			description: "valid",
+			retry: raw.StageRetry{
+				MaxAttempts: 3,
+				Backoff:     "10s",
+				Patterns:    []string{"(?i)rate limit"},
+			},
+		},
+		{
+			description: "missing max_attempts",
+			retry: raw.StageRetry{
+				Patterns: []string{"rate limit"},
+			},
+			expErr: "retry: (max_attempts: cannot be blank.).",
+		},
+		{
+			description: "max_attempts too large",
+			retry: raw.StageRetry{
+				MaxAttempts: 11,
+				Patterns:    []string{"rate limit"},
+			},
+			expErr: "retry: (max_attempts: must be no greater than 10.).",
+		},
+		{
+			description: "invalid backoff",
+			retry: raw.StageRetry{
+				MaxAttempts: 2,
+				Backoff:     "soon",
+				Patterns:    []string{"rate limit"},
+			},
+			expErr: "retry: (backoff: time: invalid duration \"soon\".).",
+		},
+		{
+			description: "backoff too long",
+			retry: raw.StageRetry{
+				MaxAttempts: 2,
+				Backoff:     "1h",
+				Patterns:    []string{"rate limit"},
+			},
+			expErr: "retry: (backoff: must not be more than 5m0s.).",
+		},
+		{
+			description: "no patterns",
+			retry: raw.StageRetry{
+				MaxAttempts: 2,
+			},
+			expErr: "retry: (patterns: at least one pattern is required.).",
+		},
+		{
+			description: "invalid pattern",
+			retry: raw.StageRetry{
+				MaxAttempts: 2,
+				Patterns:    []string{"("},
+			},
+			expErr: "retry: (patterns: parsing: (: error parsing regexp: missing closing ): `(`.).",
+		},
+	}
+	validation.ErrorTag = "yaml"
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			retry := c.retry
+			err := raw.Stage{Retry: &retry}.Validate()
+			if c.expErr == "" {
+				Ok(t, err)
+				return
+			}
+			ErrEquals(t, c.expErr, err)
+		})
+	}
+}
+
 func TestStage_ToValid(t *testing.T) {
 	cases := []struct {
 		description string
@@ -96,6 +175,23 @@ func TestStage_ToValid(t *testing.T) {
 				},
 			},
 		},
+		{
+			description: "retry set",
+			input: raw.Stage{
+				Retry: &raw.StageRetry{
+					MaxAttempts: 3,
+					Backoff:     "5s",
+					Patterns:    []string{"rate limit"},
+				},
+			},
+			exp: valid.Stage{
+				Retry: &valid.StageRetry{
+					MaxAttempts: 3,
+					Backoff:     5 * time.Second,
+					Patterns:    []*regexp.Regexp{regexp.MustCompile("rate limit")},
+				},
+			},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.description, func(t *testing.T) {
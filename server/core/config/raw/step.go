@@ -11,6 +11,8 @@ import (
 	"slices"
 	"sort"
 	"strings"
+	"text/template"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -18,29 +20,68 @@ import (
 )
 
 const (
-	ExtraArgsKey        = "extra_args"
-	NameArgKey          = "name"
-	CommandArgKey       = "command"
-	ValueArgKey         = "value"
-	OutputArgKey        = "output"
-	RunStepName         = "run"
-	PlanStepName        = "plan"
-	ShowStepName        = "show"
-	PolicyCheckStepName = "policy_check"
-	ApplyStepName       = "apply"
-	InitStepName        = "init"
-	EnvStepName         = "env"
-	MultiEnvStepName    = "multienv"
-	ImportStepName      = "import"
-	StateRmStepName     = "state_rm"
-	ShellArgKey         = "shell"
-	ShellArgsArgKey     = "shellArgs"
+	ExtraArgsKey           = "extra_args"
+	NameArgKey             = "name"
+	CommandArgKey          = "command"
+	ValueArgKey            = "value"
+	OutputArgKey           = "output"
+	StoreAsArgKey          = "store_as"
+	RunStepName            = "run"
+	PlanStepName           = "plan"
+	ShowStepName           = "show"
+	PolicyCheckStepName    = "policy_check"
+	ApplyStepName          = "apply"
+	InitStepName           = "init"
+	EnvStepName            = "env"
+	MultiEnvStepName       = "multienv"
+	ImportStepName         = "import"
+	StateRmStepName        = "state_rm"
+	ValidateStepName       = "validate"
+	FmtCheckStepName       = "fmt_check"
+	CostEstimateStepName   = "cost_estimate"
+	ShellArgKey            = "shell"
+	ShellArgsArgKey        = "shellArgs"
+	ArchiveStepName        = "archive"
+	BackendArgKey          = "backend"
+	BucketArgKey           = "bucket"
+	KeyArgKey              = "key"
+	ModulePinCheckStepName = "module_pin_check"
+	AllowArgKey            = "allow"
+	AwaitApprovalStepName  = "await_approval"
+	TimeoutArgKey          = "timeout"
+	LockProvidersStepName  = "lock_providers"
+	PlatformsArgKey        = "platforms"
+	PushArgKey             = "push"
+	TargetsArgKey          = "targets"
+	RefreshArgKey          = "refresh"
+	VaultArgKey            = "vault"
+	VaultPathArgKey        = "path"
+	VaultKeyArgKey         = "key"
 )
 
+// validArchiveBackends are the object storage backends supported by the
+// archive step.
+var validArchiveBackends = []string{"gcs", "s3"}
+
+// lockProvidersPlatformRegex matches the platforms terraform providers lock
+// accepts, ex. "linux_amd64".
+var lockProvidersPlatformRegex = regexp.MustCompile(`^[a-z0-9]+_[a-z0-9]+$`)
+
+// storeAsRegex matches the names run steps can store their output as so
+// they can be used as template fields, ex. {{ .Artifacts.ami_id }}.
+var storeAsRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// planTargetRegex matches the resource addresses a plan step can target, ex.
+// "module.a" or `aws_instance.web["blue"]`.
+var planTargetRegex = regexp.MustCompile(`^[A-Za-z_][^\s=]*$`)
+
 /*
 Step represents a single action/command to perform. In YAML, it can be set as
 1. A single string for a built-in command:
   - init
+  - validate
+  - fmt_check
+  - cost_estimate
   - plan
   - policy_check
 
@@ -51,6 +92,9 @@ Step represents a single action/command to perform. In YAML, it can be set as
   - env:
     name: test_value
     value: value
+  - env:
+    name: test_secret
+    vault: {path: secret/data/x, key: token}
   - env:
     name: test_bash_command
     command: echo ${test_value::7}
@@ -67,10 +111,41 @@ Step represents a single action/command to perform. In YAML, it can be set as
   - run:
     command: my custom command
     output: ["strip_refreshing", {"filter_regex": "((?i)secret:\\s\")[^\"]*"}]
+  - run:
+    command: ./build-ami.sh
+    output: hide
+    store_as: ami_id
+  - archive:
+    backend: s3
+    bucket: my-plans
+    key: "{{ .Repo.FullName }}/{{ .Pull.Num }}.tfplan"
+  - await_approval:
+    command: ./request-approval.sh
+    timeout: 30m
+  - lock_providers:
+    platforms: [linux_amd64, darwin_arm64]
+    push: true
+  - plan:
+    extra_args: [-var-file=staging.tfvars]
+    refresh: false
 
 3. A map for a built-in command and extra_args:
   - plan:
     extra_args: [-var-file=staging.tfvars]
+  - validate:
+    extra_args: [-json]
+  - fmt_check:
+    extra_args: [-recursive]
+  - cost_estimate:
+    extra_args: [--show-skipped]
+  - module_pin_check:
+    allow: [git::https://github.com/acme/internal-modules]
+  - lock_providers:
+    platforms: [linux_amd64, darwin_arm64]
+  - plan:
+    targets: [module.a, module.b]
+  - plan:
+    output: [collapse_unchanged]
 
 4. A map for a custom run command:
   - run: my custom command
@@ -121,12 +196,19 @@ func (s Step) validStepName(stepName string) bool {
 		stepName == ShowStepName ||
 		stepName == PolicyCheckStepName ||
 		stepName == ImportStepName ||
-		stepName == StateRmStepName
+		stepName == StateRmStepName ||
+		stepName == ValidateStepName ||
+		stepName == FmtCheckStepName ||
+		stepName == CostEstimateStepName ||
+		stepName == ModulePinCheckStepName
 }
 
 func (s Step) Validate() error {
 	validStep := func(value interface{}) error {
 		str := *value.(*string)
+		if str == LockProvidersStepName {
+			return fmt.Errorf("%q step must have a %q key set", LockProvidersStepName, PlatformsArgKey)
+		}
 		if !s.validStepName(str) {
 			return fmt.Errorf("%q is not a valid step type, maybe you omitted the 'run' key", str)
 		}
@@ -147,9 +229,6 @@ func (s Step) Validate() error {
 				len(keys), strings.Join(keys, ","))
 		}
 		for stepName, args := range elem {
-			if !s.validStepName(stepName) {
-				return fmt.Errorf("%q is not a valid step type", stepName)
-			}
 			var argKeys []string
 			for k := range args {
 				argKeys = append(argKeys, k)
@@ -157,6 +236,63 @@ func (s Step) Validate() error {
 			// Sort so tests can be deterministic.
 			sort.Strings(argKeys)
 
+			// lock_providers without push is parsed here since all its
+			// values are lists.
+			if stepName == LockProvidersStepName {
+				for _, k := range argKeys {
+					if k != PlatformsArgKey {
+						return fmt.Errorf("%q steps only support keys %q and %q, found extra keys %q",
+							stepName, PlatformsArgKey, PushArgKey, k)
+					}
+				}
+				if err := validLockProvidersPlatforms(args[PlatformsArgKey]); err != nil {
+					return err
+				}
+				continue
+			}
+
+			if !s.validStepName(stepName) {
+				return fmt.Errorf("%q is not a valid step type", stepName)
+			}
+
+			// module_pin_check doesn't run terraform so it takes an allowlist
+			// of module sources instead of extra_args.
+			if stepName == ModulePinCheckStepName {
+				for _, k := range argKeys {
+					if k != AllowArgKey {
+						return fmt.Errorf("%s steps only support a single %s key, found %q",
+							ModulePinCheckStepName, AllowArgKey, k)
+					}
+				}
+				for _, prefix := range args[AllowArgKey] {
+					if strings.TrimSpace(prefix) == "" {
+						return fmt.Errorf("%s step %s entries cannot be empty", ModulePinCheckStepName, AllowArgKey)
+					}
+				}
+				continue
+			}
+
+			// plan can also target resources, which are expanded into
+			// -target flags, and post-process its output.
+			if stepName == PlanStepName {
+				for _, k := range argKeys {
+					if k == RefreshArgKey {
+						return fmt.Errorf("%q step %q option must be a boolean, found %v", stepName, RefreshArgKey, args[k])
+					}
+					if k != ExtraArgsKey && k != TargetsArgKey && k != OutputArgKey {
+						return fmt.Errorf("%s steps only support keys %q, %q, %q and %q, found extra keys %q",
+							PlanStepName, ExtraArgsKey, TargetsArgKey, RefreshArgKey, OutputArgKey, k)
+					}
+				}
+				if err := validPlanTargets(args[TargetsArgKey]); err != nil {
+					return err
+				}
+				if err := validPlanOutput(args[OutputArgKey]); err != nil {
+					return err
+				}
+				continue
+			}
+
 			// args should contain a single 'extra_args' key.
 			if len(argKeys) > 1 {
 				return fmt.Errorf("built-in steps only support a single %s key, found %d: %s",
@@ -238,13 +374,15 @@ func (s Step) Validate() error {
 				if k != NameArgKey &&
 					k != CommandArgKey &&
 					k != ValueArgKey &&
+					k != VaultArgKey &&
 					k != ShellArgKey &&
 					k != ShellArgsArgKey {
 					return fmt.Errorf(
-						"env steps only support keys %q, %q, %q, %q and %q, found key %q",
+						"env steps only support keys %q, %q, %q, %q, %q and %q, found key %q",
 						NameArgKey,
 						ValueArgKey,
 						CommandArgKey,
+						VaultArgKey,
 						ShellArgKey,
 						ShellArgsArgKey,
 						k,
@@ -264,6 +402,16 @@ func (s Step) Validate() error {
 					ValueArgKey, CommandArgKey)
 			}
 			delete(argMap, ValueArgKey)
+			if vault, ok := argMap[VaultArgKey]; ok {
+				if utils.SlicesContains(argKeys, ValueArgKey) || utils.SlicesContains(argKeys, CommandArgKey) {
+					return fmt.Errorf("env steps don't support the %q key with the %q or %q keys",
+						VaultArgKey, ValueArgKey, CommandArgKey)
+				}
+				if _, _, err := envVaultSecret(vault); err != nil {
+					return err
+				}
+			}
+			delete(argMap, VaultArgKey)
 		case MultiEnvStepName:
 			if _, ok := argMap[CommandArgKey].(string); !ok {
 				return fmt.Errorf("%q step must have a %q key set", stepName, CommandArgKey)
@@ -289,19 +437,28 @@ func (s Step) Validate() error {
 				return fmt.Errorf("%q step must have a %q key set", stepName, CommandArgKey)
 			}
 			delete(argMap, CommandArgKey)
+			if storeAs, ok := argMap[StoreAsArgKey]; ok {
+				if name, _ := storeAs.(string); !storeAsRegex.MatchString(name) {
+					return fmt.Errorf("%q step %q option must only contain letters, digits and underscores and not start with a digit, found %v",
+						stepName, StoreAsArgKey, storeAs)
+				}
+			}
+			delete(argMap, StoreAsArgKey)
 			if v, ok := argMap[OutputArgKey].(string); ok {
 				switch v {
 				case valid.PostProcessRunOutputShow,
 					valid.PostProcessRunOutputHide,
-					valid.PostProcessRunOutputStripRefreshing:
+					valid.PostProcessRunOutputStripRefreshing,
+					valid.PostProcessRunOutputCollapseUnchanged:
 					// All good; do nothing
 				default:
 					return fmt.Errorf(
-						"run step %q option must be one of %q, %q, %q, or %q",
+						"run step %q option must be one of %q, %q, %q, %q, or %q",
 						OutputArgKey,
 						valid.PostProcessRunOutputShow,
 						valid.PostProcessRunOutputHide,
 						valid.PostProcessRunOutputStripRefreshing,
+						valid.PostProcessRunOutputCollapseUnchanged,
 						valid.PostProcessRunOutputFilterRegexKey,
 					)
 				}
@@ -323,11 +480,12 @@ func (s Step) Validate() error {
 							}
 						default:
 							return fmt.Errorf(
-								"run step %q option must be one of %q, %q, %q, or %q",
+								"run step %q option must be one of %q, %q, %q, %q, or %q",
 								OutputArgKey,
 								valid.PostProcessRunOutputShow,
 								valid.PostProcessRunOutputHide,
 								valid.PostProcessRunOutputStripRefreshing,
+								valid.PostProcessRunOutputCollapseUnchanged,
 								valid.PostProcessRunOutputFilterRegexKey,
 							)
 						}
@@ -345,6 +503,137 @@ func (s Step) Validate() error {
 				}
 			}
 			delete(argMap, OutputArgKey)
+		case ArchiveStepName:
+			if utils.SlicesContains(argKeys, ShellArgKey) {
+				return fmt.Errorf("%q steps do not support the %q key", stepName, ShellArgKey)
+			}
+			backend, _ := argMap[BackendArgKey].(string)
+			if !utils.SlicesContains(validArchiveBackends, backend) {
+				return fmt.Errorf("%q step %q option must be one of %q, found %q",
+					stepName, BackendArgKey, validArchiveBackends, backend)
+			}
+			delete(argMap, BackendArgKey)
+			if bucket, _ := argMap[BucketArgKey].(string); bucket == "" {
+				return fmt.Errorf("%q step must have a %q key set", stepName, BucketArgKey)
+			} else if strings.ContainsAny(bucket, "/ ") {
+				return fmt.Errorf("%q step %q option must be a bucket name, found %q", stepName, BucketArgKey, bucket)
+			}
+			delete(argMap, BucketArgKey)
+			key, _ := argMap[KeyArgKey].(string)
+			if key == "" {
+				return fmt.Errorf("%q step must have a %q key set", stepName, KeyArgKey)
+			}
+			if _, err := template.New(KeyArgKey).Parse(key); err != nil {
+				return fmt.Errorf("%q step %q option is not a valid template: %w", stepName, KeyArgKey, err)
+			}
+			delete(argMap, KeyArgKey)
+			if len(argMap) > 0 {
+				var extraKeys []string
+				for k := range argMap {
+					extraKeys = append(extraKeys, k)
+				}
+				// Sort so tests can be deterministic.
+				sort.Strings(extraKeys)
+				return fmt.Errorf("%q steps only support keys %q, %q and %q, found extra keys %q",
+					stepName, BackendArgKey, BucketArgKey, KeyArgKey, strings.Join(extraKeys, ","))
+			}
+		case AwaitApprovalStepName:
+			if utils.SlicesContains(argKeys, ShellArgKey) {
+				return fmt.Errorf("%q steps do not support the %q key", stepName, ShellArgKey)
+			}
+			if cmd, ok := argMap[CommandArgKey]; ok {
+				if str, _ := cmd.(string); strings.TrimSpace(str) == "" {
+					return fmt.Errorf("%q step %q option must be a non-empty string", stepName, CommandArgKey)
+				}
+			}
+			delete(argMap, CommandArgKey)
+			timeout, _ := argMap[TimeoutArgKey].(string)
+			if timeout == "" {
+				return fmt.Errorf("%q step must have a %q key set", stepName, TimeoutArgKey)
+			}
+			if d, err := time.ParseDuration(timeout); err != nil || d <= 0 {
+				return fmt.Errorf("%q step %q option must be a positive duration, ex. \"30m\", found %q",
+					stepName, TimeoutArgKey, timeout)
+			}
+			delete(argMap, TimeoutArgKey)
+			if len(argMap) > 0 {
+				var extraKeys []string
+				for k := range argMap {
+					extraKeys = append(extraKeys, k)
+				}
+				// Sort so tests can be deterministic.
+				sort.Strings(extraKeys)
+				return fmt.Errorf("%q steps only support keys %q and %q, found extra keys %q",
+					stepName, CommandArgKey, TimeoutArgKey, strings.Join(extraKeys, ","))
+			}
+		case PlanStepName:
+			if utils.SlicesContains(argKeys, ShellArgKey) {
+				return fmt.Errorf("%q steps do not support the %q key", stepName, ShellArgKey)
+			}
+			if _, err := stepStringList(stepName, ExtraArgsKey, argMap[ExtraArgsKey]); err != nil {
+				return err
+			}
+			delete(argMap, ExtraArgsKey)
+			targets, err := stepStringList(stepName, TargetsArgKey, argMap[TargetsArgKey])
+			if err != nil {
+				return err
+			}
+			if err := validPlanTargets(targets); err != nil {
+				return err
+			}
+			delete(argMap, TargetsArgKey)
+			if refresh, ok := argMap[RefreshArgKey]; ok {
+				if _, ok := refresh.(bool); !ok {
+					return fmt.Errorf("%q step %q option must be a boolean, found %v", stepName, RefreshArgKey, refresh)
+				}
+			}
+			delete(argMap, RefreshArgKey)
+			output, err := planOutput(argMap[OutputArgKey])
+			if err != nil {
+				return err
+			}
+			if err := validPlanOutput(output); err != nil {
+				return err
+			}
+			delete(argMap, OutputArgKey)
+			if len(argMap) > 0 {
+				var extraKeys []string
+				for k := range argMap {
+					extraKeys = append(extraKeys, k)
+				}
+				// Sort so tests can be deterministic.
+				sort.Strings(extraKeys)
+				return fmt.Errorf("%q steps only support keys %q, %q, %q and %q, found extra keys %q",
+					stepName, ExtraArgsKey, TargetsArgKey, RefreshArgKey, OutputArgKey, strings.Join(extraKeys, ","))
+			}
+		case LockProvidersStepName:
+			if utils.SlicesContains(argKeys, ShellArgKey) {
+				return fmt.Errorf("%q steps do not support the %q key", stepName, ShellArgKey)
+			}
+			platforms, err := lockProvidersPlatforms(argMap[PlatformsArgKey])
+			if err != nil {
+				return err
+			}
+			if err := validLockProvidersPlatforms(platforms); err != nil {
+				return err
+			}
+			delete(argMap, PlatformsArgKey)
+			if push, ok := argMap[PushArgKey]; ok {
+				if _, ok := push.(bool); !ok {
+					return fmt.Errorf("%q step %q option must be a boolean, found %v", stepName, PushArgKey, push)
+				}
+			}
+			delete(argMap, PushArgKey)
+			if len(argMap) > 0 {
+				var extraKeys []string
+				for k := range argMap {
+					extraKeys = append(extraKeys, k)
+				}
+				// Sort so tests can be deterministic.
+				sort.Strings(extraKeys)
+				return fmt.Errorf("%q steps only support keys %q and %q, found extra keys %q",
+					stepName, PlatformsArgKey, PushArgKey, strings.Join(extraKeys, ","))
+			}
 		default:
 			return fmt.Errorf("%q is not a valid step type", stepName)
 		}
@@ -422,6 +711,37 @@ func (s Step) ToValid() valid.Step {
 			if value, ok := stepArgs[ValueArgKey].(string); ok {
 				step.EnvVarValue = value
 			}
+			if vault, ok := stepArgs[VaultArgKey]; ok && step.StepName == EnvStepName {
+				// Safe to ignore the error because we test it in Validate().
+				secretPath, key, _ := envVaultSecret(vault)
+				step.EnvVarVault = &valid.VaultSecret{Path: secretPath, Key: key}
+			}
+			if step.StepName == RunStepName {
+				step.StoreAs, _ = stepArgs[StoreAsArgKey].(string)
+			}
+			if step.StepName == ArchiveStepName {
+				step.ArchiveBackend, _ = stepArgs[BackendArgKey].(string)
+				step.ArchiveBucket, _ = stepArgs[BucketArgKey].(string)
+				step.ArchiveKey, _ = stepArgs[KeyArgKey].(string)
+			}
+			if step.StepName == AwaitApprovalStepName {
+				// Safe to ignore the error because we test it in Validate().
+				timeout, _ := stepArgs[TimeoutArgKey].(string)
+				step.ApprovalTimeout, _ = time.ParseDuration(timeout)
+			}
+			if step.StepName == PlanStepName {
+				// Safe to ignore the errors because we test them in Validate().
+				step.ExtraArgs, _ = stepStringList(PlanStepName, ExtraArgsKey, stepArgs[ExtraArgsKey])
+				step.Targets, _ = stepStringList(PlanStepName, TargetsArgKey, stepArgs[TargetsArgKey])
+				if refresh, ok := stepArgs[RefreshArgKey].(bool); ok {
+					step.SkipRefresh = !refresh
+				}
+			}
+			if step.StepName == LockProvidersStepName {
+				// Safe to ignore the error because we test it in Validate().
+				step.LockProvidersPlatforms, _ = lockProvidersPlatforms(stepArgs[PlatformsArgKey])
+				step.LockProvidersPush, _ = stepArgs[PushArgKey].(bool)
+			}
 			if shell, ok := stepArgs[ShellArgKey].(string); ok {
 				step.RunShell = &valid.CommandShell{
 					Shell:     shell,
@@ -489,10 +809,27 @@ func (s Step) ToValid() valid.Step {
 		// After validation we assume there's only one key and it's a valid
 		// step name so we just use the first one.
 		for stepName, stepArgs := range s.Map {
-			return valid.Step{
+			if stepName == ModulePinCheckStepName {
+				return valid.Step{
+					StepName:           stepName,
+					ModulePinAllowlist: stepArgs[AllowArgKey],
+				}
+			}
+			if stepName == LockProvidersStepName {
+				return valid.Step{
+					StepName:               stepName,
+					LockProvidersPlatforms: stepArgs[PlatformsArgKey],
+				}
+			}
+			step := valid.Step{
 				StepName:  stepName,
 				ExtraArgs: stepArgs[ExtraArgsKey],
+				Targets:   stepArgs[TargetsArgKey],
+			}
+			for _, output := range stepArgs[OutputArgKey] {
+				step.Output = append(step.Output, valid.PostProcessRunOutputOption(output))
 			}
+			return step
 		}
 	}
 
@@ -511,6 +848,119 @@ func (s Step) ToValid() valid.Step {
 	panic("step was not valid. This is a bug!")
 }
 
+// planOutput converts the output option of a plan step parsed as a generic
+// map, either a single option or a list of them, to a list of strings.
+func planOutput(value interface{}) ([]string, error) {
+	if str, ok := value.(string); ok {
+		return []string{str}, nil
+	}
+	return stepStringList(PlanStepName, OutputArgKey, value)
+}
+
+// validPlanOutput returns an error if an output option of a plan step isn't
+// supported. Plan steps only support the options that format plan output.
+func validPlanOutput(output []string) error {
+	for _, o := range output {
+		if o != valid.PostProcessRunOutputShow && o != valid.PostProcessRunOutputCollapseUnchanged {
+			return fmt.Errorf("%s step %s option must be %q or %q, found %q",
+				PlanStepName, OutputArgKey, valid.PostProcessRunOutputShow, valid.PostProcessRunOutputCollapseUnchanged, o)
+		}
+	}
+	return nil
+}
+
+// validPlanTargets returns an error if a target of a plan step isn't a
+// resource address.
+func validPlanTargets(targets []string) error {
+	for _, target := range targets {
+		if !planTargetRegex.MatchString(target) {
+			return fmt.Errorf("%s step %s must be resource addresses, ex. module.a, found %q",
+				PlanStepName, TargetsArgKey, target)
+		}
+	}
+	return nil
+}
+
+// envVaultSecret returns the path and key of the secret the vault option of
+// an env step parsed as a generic map references.
+func envVaultSecret(value interface{}) (secretPath string, key string, err error) {
+	fields := make(map[string]interface{})
+	switch t := value.(type) {
+	case map[string]interface{}:
+		fields = t
+	case map[string]string:
+		for k, v := range t {
+			fields[k] = v
+		}
+	default:
+		return "", "", fmt.Errorf("env step %q option must be a map with the %q and %q keys, found %v",
+			VaultArgKey, VaultPathArgKey, VaultKeyArgKey, value)
+	}
+	for k, v := range fields {
+		if k != VaultPathArgKey && k != VaultKeyArgKey {
+			return "", "", fmt.Errorf("env step %q option only supports keys %q and %q, found key %q",
+				VaultArgKey, VaultPathArgKey, VaultKeyArgKey, k)
+		}
+		if str, _ := v.(string); strings.TrimSpace(str) == "" {
+			return "", "", fmt.Errorf("env step %q option %q must be a non-empty string, found %v",
+				VaultArgKey, k, v)
+		}
+	}
+	secretPath, _ = fields[VaultPathArgKey].(string)
+	if secretPath == "" {
+		return "", "", fmt.Errorf("env step %q option must have a %q key set", VaultArgKey, VaultPathArgKey)
+	}
+	key, _ = fields[VaultKeyArgKey].(string)
+	if key == "" {
+		return "", "", fmt.Errorf("env step %q option must have a %q key set", VaultArgKey, VaultKeyArgKey)
+	}
+	return secretPath, key, nil
+}
+
+// lockProvidersPlatforms converts the platforms of a lock_providers step
+// parsed as a generic map to a list of strings.
+func lockProvidersPlatforms(value interface{}) ([]string, error) {
+	return stepStringList(LockProvidersStepName, PlatformsArgKey, value)
+}
+
+// stepStringList converts the value of the list option argKey of a step
+// parsed as a generic map to a list of strings.
+func stepStringList(stepName string, argKey string, value interface{}) ([]string, error) {
+	switch t := value.(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return t, nil
+	case []interface{}:
+		var list []string
+		for _, e := range t {
+			str, ok := e.(string)
+			if !ok {
+				return nil, fmt.Errorf("%q step %q option must contain only strings, found %v",
+					stepName, argKey, e)
+			}
+			list = append(list, str)
+		}
+		return list, nil
+	default:
+		return nil, fmt.Errorf("%q step %q option must be a list of strings, found %v",
+			stepName, argKey, t)
+	}
+}
+
+func validLockProvidersPlatforms(platforms []string) error {
+	if len(platforms) == 0 {
+		return fmt.Errorf("%q step must have a %q key set", LockProvidersStepName, PlatformsArgKey)
+	}
+	for _, platform := range platforms {
+		if !lockProvidersPlatformRegex.MatchString(platform) {
+			return fmt.Errorf("%q step %q entries must be in the form os_arch, ex. \"linux_amd64\", found %q",
+				LockProvidersStepName, PlatformsArgKey, platform)
+		}
+	}
+	return nil
+}
+
 // unmarshalGeneric is used by UnmarshalJSON and UnmarshalYAML to unmarshal
 // a step into one of its three forms. We need to implement a custom unmarshal
 // function because steps can either be:
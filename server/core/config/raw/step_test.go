@@ -6,6 +6,7 @@ package raw_test
 import (
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/runatlantis/atlantis/server/core/config/raw"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -108,6 +109,26 @@ env:
 				},
 			},
 		},
+		{
+			description: "env step vault",
+			input: `
+env:
+  name: test
+  vault:
+    path: secret/data/x
+    key: token`,
+			exp: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"name": "test",
+						"vault": map[string]interface{}{
+							"path": "secret/data/x",
+							"key":  "token",
+						},
+					},
+				},
+			},
+		},
 
 		// Run-step style
 		{
@@ -133,6 +154,38 @@ key: value`,
 			},
 		},
 
+		// Mixed values i.e. lock_providers with push.
+		{
+			description: "lock_providers with push",
+			input: `
+lock_providers:
+  platforms: [linux_amd64, darwin_arm64]
+  push: true`,
+			exp: raw.Step{
+				CommandMap: EnvType{
+					"lock_providers": {
+						"platforms": []interface{}{"linux_amd64", "darwin_arm64"},
+						"push":      true,
+					},
+				},
+			},
+		},
+		{
+			description: "plan with refresh",
+			input: `
+plan:
+  extra_args: [-var-file=staging.tfvars]
+  refresh: false`,
+			exp: raw.Step{
+				CommandMap: EnvType{
+					"plan": {
+						"extra_args": []interface{}{"-var-file=staging.tfvars"},
+						"refresh":    false,
+					},
+				},
+			},
+		},
+
 		// Empty
 		{
 			description: "empty",
@@ -206,6 +259,49 @@ func TestStep_Validate(t *testing.T) {
 			},
 			expErr: "",
 		},
+		{
+			description: "validate step",
+			input: raw.Step{
+				Key: String("validate"),
+			},
+			expErr: "",
+		},
+		{
+			description: "validate extra_args",
+			input: raw.Step{
+				Map: MapType{
+					"validate": {
+						"extra_args": []string{"-json"},
+					},
+				},
+			},
+			expErr: "",
+		},
+		{
+			description: "fmt_check step",
+			input: raw.Step{
+				Key: String("fmt_check"),
+			},
+			expErr: "",
+		},
+		{
+			description: "fmt_check extra_args",
+			input: raw.Step{
+				Map: MapType{
+					"fmt_check": {
+						"extra_args": []string{"-recursive"},
+					},
+				},
+			},
+			expErr: "",
+		},
+		{
+			description: "cost_estimate step",
+			input: raw.Step{
+				Key: String("cost_estimate"),
+			},
+			expErr: "",
+		},
 		{
 			description: "init extra_args",
 			input: raw.Step{
@@ -279,209 +375,707 @@ func TestStep_Validate(t *testing.T) {
 					},
 				},
 			},
-			expErr: "",
+			expErr: "",
+		},
+		{
+			description: "apply extra_args",
+			input: raw.Step{
+				Map: MapType{
+					"apply": {
+						"extra_args": []string{"arg1", "arg2"},
+					},
+				},
+			},
+			expErr: "",
+		},
+		{
+			description: "run step",
+			input: raw.Step{
+				StringVal: map[string]string{
+					"run": "my command",
+				},
+			},
+			expErr: "",
+		},
+
+		// Invalid inputs.
+		{
+			description: "empty elem",
+			input:       raw.Step{},
+			expErr:      "step element is empty",
+		},
+		{
+			description: "invalid step name",
+			input: raw.Step{
+				Key: String("invalid"),
+			},
+			expErr: "\"invalid\" is not a valid step type, maybe you omitted the 'run' key",
+		},
+		{
+			description: "multiple keys in map",
+			input: raw.Step{
+				Map: MapType{
+					"key1": nil,
+					"key2": nil,
+				},
+			},
+			expErr: "step element can only contain a single key, found 2: key1,key2",
+		},
+		{
+			description: "multiple keys in env",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"key1": nil,
+					"key2": nil,
+				},
+			},
+			expErr: "step element can only contain a single key, found 2: key1,key2",
+		},
+		{
+			description: "multiple keys in string val",
+			input: raw.Step{
+				StringVal: map[string]string{
+					"key1": "",
+					"key2": "",
+				},
+			},
+			expErr: "step element can only contain a single key, found 2: key1,key2",
+		},
+		{
+			description: "invalid key in map",
+			input: raw.Step{
+				Map: MapType{
+					"invalid": nil,
+				},
+			},
+			expErr: "\"invalid\" is not a valid step type",
+		},
+		{
+			description: "invalid key in env",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"invalid": nil,
+				},
+			},
+			expErr: "\"invalid\" is not a valid step type",
+		},
+		{
+			description: "invalid key in string val",
+			input: raw.Step{
+				StringVal: map[string]string{
+					"invalid": "",
+				},
+			},
+			expErr: "\"invalid\" is not a valid step type",
+		},
+		{
+			description: "non extra_arg key",
+			input: raw.Step{
+				Map: MapType{
+					"init": {
+						"invalid": nil,
+					},
+				},
+			},
+			expErr: "built-in steps only support a single extra_args key, found \"invalid\" in step init",
+		},
+		{
+			description: "non extra_arg key",
+			input: raw.Step{
+				Map: MapType{
+					"init": {
+						"invalid": nil,
+						"zzzzzzz": nil,
+					},
+				},
+			},
+			expErr: "built-in steps only support a single extra_args key, found 2: invalid,zzzzzzz",
+		},
+		{
+			description: "plan step with targets",
+			input: raw.Step{
+				Map: MapType{
+					"plan": {
+						"extra_args": []string{"-var-file=staging.tfvars"},
+						"targets":    []string{"module.a", `aws_instance.web["blue"]`},
+					},
+				},
+			},
+			expErr: "",
+		},
+		{
+			description: "plan step with invalid target",
+			input: raw.Step{
+				Map: MapType{
+					"plan": {
+						"targets": []string{"module.a", "-target=module.b"},
+					},
+				},
+			},
+			expErr: "plan step targets must be resource addresses, ex. module.a, found \"-target=module.b\"",
+		},
+		{
+			description: "plan step with extra key",
+			input: raw.Step{
+				Map: MapType{
+					"plan": {
+						"invalid": nil,
+					},
+				},
+			},
+			expErr: "plan steps only support keys \"extra_args\", \"targets\", \"refresh\" and \"output\", found extra keys \"invalid\"",
+		},
+		{
+			description: "plan step with list refresh",
+			input: raw.Step{
+				Map: MapType{
+					"plan": {
+						"refresh": []string{"false"},
+					},
+				},
+			},
+			expErr: "\"plan\" step \"refresh\" option must be a boolean, found [false]",
+		},
+		{
+			description: "plan step with output",
+			input: raw.Step{
+				Map: MapType{
+					"plan": {
+						"output": []string{"collapse_unchanged"},
+					},
+				},
+			},
+			expErr: "",
+		},
+		{
+			description: "plan step with unsupported output",
+			input: raw.Step{
+				Map: MapType{
+					"plan": {
+						"output": []string{"hide"},
+					},
+				},
+			},
+			expErr: "plan step output option must be \"show\" or \"collapse_unchanged\", found \"hide\"",
+		},
+		{
+			description: "plan step with refresh and unsupported output",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"plan": {
+						"refresh": false,
+						"output":  "strip_refreshing",
+					},
+				},
+			},
+			expErr: "plan step output option must be \"show\" or \"collapse_unchanged\", found \"strip_refreshing\"",
+		},
+		{
+			description: "plan step with refresh",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"plan": {
+						"extra_args": []interface{}{"-var-file=staging.tfvars"},
+						"targets":    []interface{}{"module.a"},
+						"refresh":    false,
+					},
+				},
+			},
+			expErr: "",
+		},
+		{
+			description: "plan step with non-boolean refresh",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"plan": {
+						"refresh": "false",
+					},
+				},
+			},
+			expErr: "\"plan\" step \"refresh\" option must be a boolean, found false",
+		},
+		{
+			description: "plan step with refresh and invalid target",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"plan": {
+						"targets": []interface{}{"-target=module.b"},
+						"refresh": false,
+					},
+				},
+			},
+			expErr: "plan step targets must be resource addresses, ex. module.a, found \"-target=module.b\"",
+		},
+		{
+			description: "plan step with refresh and extra key",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"plan": {
+						"refresh": false,
+						"invalid": "value",
+					},
+				},
+			},
+			expErr: "\"plan\" steps only support keys \"extra_args\", \"targets\", \"refresh\" and \"output\", found extra keys \"invalid\"",
+		},
+		{
+			description: "env step with no name key set",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"value": "value",
+					},
+				},
+			},
+			expErr: "env steps must have a \"name\" key set",
+		},
+		{
+			description: "env step with invalid key",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"abc":      "",
+						"invalid2": "",
+					},
+				},
+			},
+			expErr: "env steps only support keys \"name\", \"value\", \"command\", \"vault\", \"shell\" and \"shellArgs\", found key \"abc\"",
+		},
+		{
+			description: "env step with vault",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"name":  "name",
+						"vault": map[string]interface{}{"path": "secret/data/x", "key": "token"},
+					},
+				},
+			},
+			expErr: "",
+		},
+		{
+			description: "env step with vault and value set",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"name":  "name",
+						"value": "value",
+						"vault": map[string]interface{}{"path": "secret/data/x", "key": "token"},
+					},
+				},
+			},
+			expErr: "env steps don't support the \"vault\" key with the \"value\" or \"command\" keys",
+		},
+		{
+			description: "env step with vault that isn't a map",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"name":  "name",
+						"vault": "secret/data/x",
+					},
+				},
+			},
+			expErr: "env step \"vault\" option must be a map with the \"path\" and \"key\" keys, found secret/data/x",
+		},
+		{
+			description: "env step with vault without key",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"name":  "name",
+						"vault": map[string]interface{}{"path": "secret/data/x"},
+					},
+				},
+			},
+			expErr: "env step \"vault\" option must have a \"key\" key set",
+		},
+		{
+			description: "env step with vault with empty path",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"name":  "name",
+						"vault": map[string]interface{}{"path": "", "key": "token"},
+					},
+				},
+			},
+			expErr: "env step \"vault\" option \"path\" must be a non-empty string, found ",
+		},
+		{
+			description: "env step with vault with extra key",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"name":  "name",
+						"vault": map[string]interface{}{"path": "secret/data/x", "key": "token", "version": "2"},
+					},
+				},
+			},
+			expErr: "env step \"vault\" option only supports keys \"path\" and \"key\", found key \"version\"",
+		},
+		{
+			description: "env step with both command and value set",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"name":    "name",
+						"command": "command",
+						"value":   "value",
+					},
+				},
+			},
+			expErr: "env steps only support one of the \"value\" or \"command\" keys, found both",
+		},
+		{
+			description: "env step with shell set but not command",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"name":  "name",
+						"shell": "bash",
+					},
+				},
+			},
+			expErr: "workflow steps only support \"shell\" key in combination with \"command\" key",
+		},
+		{
+			description: "env step with shellArgs set but not shell",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"name":      "name",
+						"shellArgs": "-c",
+					},
+				},
+			},
+			expErr: "workflow steps only support \"shellArgs\" key in combination with \"shell\" key",
+		},
+		{
+			description: "run step with shellArgs is not list of strings",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"run": {
+						"name":      "name",
+						"command":   "echo",
+						"shell":     "shell",
+						"shellArgs": []int{42, 42},
+					},
+				},
+			},
+			expErr: "\"run\" step \"shellArgs\" option must be a string or a list of strings, found [42 42]",
+		},
+		{
+			description: "run step with shellArgs contain not strings",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"run": {
+						"name":      "name",
+						"command":   "echo",
+						"shell":     "shell",
+						"shellArgs": []interface{}{"-c", 42},
+					},
+				},
+			},
+			expErr: "\"run\" step \"shellArgs\" option must contain only strings, found 42",
+		},
+		{
+			description: "run step with store_as",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"run": {
+						"command":  "./build-ami.sh",
+						"output":   "hide",
+						"store_as": "ami_id",
+					},
+				},
+			},
+			expErr: "",
+		},
+		{
+			description: "run step with invalid store_as",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"run": {
+						"command":  "./build-ami.sh",
+						"store_as": "ami-id",
+					},
+				},
+			},
+			expErr: "\"run\" step \"store_as\" option must only contain letters, digits and underscores and not start with a digit, found ami-id",
+		},
+		{
+			description: "module_pin_check step",
+			input: raw.Step{
+				Key: String("module_pin_check"),
+			},
+			expErr: "",
+		},
+		{
+			description: "module_pin_check step with allowlist",
+			input: raw.Step{
+				Map: MapType{
+					"module_pin_check": {
+						"allow": []string{"git::https://github.com/acme/"},
+					},
+				},
+			},
+			expErr: "",
+		},
+		{
+			description: "module_pin_check step with extra_args",
+			input: raw.Step{
+				Map: MapType{
+					"module_pin_check": {
+						"extra_args": []string{"arg1"},
+					},
+				},
+			},
+			expErr: "module_pin_check steps only support a single allow key, found \"extra_args\"",
+		},
+		{
+			description: "module_pin_check step with empty allowlist entry",
+			input: raw.Step{
+				Map: MapType{
+					"module_pin_check": {
+						"allow": []string{""},
+					},
+				},
+			},
+			expErr: "module_pin_check step allow entries cannot be empty",
+		},
+		{
+			description: "archive step",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"archive": {
+						"backend": "s3",
+						"bucket":  "my-plans",
+						"key":     "{{ .Repo.FullName }}/{{ .Pull.Num }}.tfplan",
+					},
+				},
+			},
+			expErr: "",
+		},
+		{
+			description: "archive step with invalid backend",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"archive": {
+						"backend": "azure",
+						"bucket":  "my-plans",
+						"key":     "plan.tfplan",
+					},
+				},
+			},
+			expErr: "\"archive\" step \"backend\" option must be one of [\"gcs\" \"s3\"], found \"azure\"",
 		},
 		{
-			description: "apply extra_args",
+			description: "archive step without bucket",
 			input: raw.Step{
-				Map: MapType{
-					"apply": {
-						"extra_args": []string{"arg1", "arg2"},
+				CommandMap: EnvType{
+					"archive": {
+						"backend": "gcs",
+						"key":     "plan.tfplan",
 					},
 				},
 			},
-			expErr: "",
+			expErr: "\"archive\" step must have a \"bucket\" key set",
 		},
 		{
-			description: "run step",
+			description: "archive step with invalid bucket",
 			input: raw.Step{
-				StringVal: map[string]string{
-					"run": "my command",
+				CommandMap: EnvType{
+					"archive": {
+						"backend": "gcs",
+						"bucket":  "my-plans/dir",
+						"key":     "plan.tfplan",
+					},
 				},
 			},
-			expErr: "",
-		},
-
-		// Invalid inputs.
-		{
-			description: "empty elem",
-			input:       raw.Step{},
-			expErr:      "step element is empty",
+			expErr: "\"archive\" step \"bucket\" option must be a bucket name, found \"my-plans/dir\"",
 		},
 		{
-			description: "invalid step name",
+			description: "archive step without key",
 			input: raw.Step{
-				Key: String("invalid"),
+				CommandMap: EnvType{
+					"archive": {
+						"backend": "s3",
+						"bucket":  "my-plans",
+					},
+				},
 			},
-			expErr: "\"invalid\" is not a valid step type, maybe you omitted the 'run' key",
+			expErr: "\"archive\" step must have a \"key\" key set",
 		},
 		{
-			description: "multiple keys in map",
+			description: "archive step with invalid key template",
 			input: raw.Step{
-				Map: MapType{
-					"key1": nil,
-					"key2": nil,
+				CommandMap: EnvType{
+					"archive": {
+						"backend": "s3",
+						"bucket":  "my-plans",
+						"key":     "{{ .Pull.Num",
+					},
 				},
 			},
-			expErr: "step element can only contain a single key, found 2: key1,key2",
+			expErr: "\"archive\" step \"key\" option is not a valid template: template: key:1: unclosed action",
 		},
 		{
-			description: "multiple keys in env",
+			description: "archive step with extra keys",
 			input: raw.Step{
 				CommandMap: EnvType{
-					"key1": nil,
-					"key2": nil,
+					"archive": {
+						"backend": "s3",
+						"bucket":  "my-plans",
+						"key":     "plan.tfplan",
+						"command": "echo",
+					},
 				},
 			},
-			expErr: "step element can only contain a single key, found 2: key1,key2",
+			expErr: "\"archive\" steps only support keys \"backend\", \"bucket\" and \"key\", found extra keys \"command\"",
 		},
 		{
-			description: "multiple keys in string val",
+			description: "await_approval step",
 			input: raw.Step{
-				StringVal: map[string]string{
-					"key1": "",
-					"key2": "",
+				CommandMap: EnvType{
+					"await_approval": {
+						"command": "./request-approval.sh",
+						"timeout": "30m",
+					},
 				},
 			},
-			expErr: "step element can only contain a single key, found 2: key1,key2",
 		},
 		{
-			description: "invalid key in map",
+			description: "await_approval step without timeout",
 			input: raw.Step{
-				Map: MapType{
-					"invalid": nil,
+				CommandMap: EnvType{
+					"await_approval": {
+						"command": "./request-approval.sh",
+					},
 				},
 			},
-			expErr: "\"invalid\" is not a valid step type",
+			expErr: "\"await_approval\" step must have a \"timeout\" key set",
 		},
 		{
-			description: "invalid key in env",
+			description: "await_approval step with invalid timeout",
 			input: raw.Step{
 				CommandMap: EnvType{
-					"invalid": nil,
+					"await_approval": {
+						"timeout": "-5m",
+					},
 				},
 			},
-			expErr: "\"invalid\" is not a valid step type",
+			expErr: "\"await_approval\" step \"timeout\" option must be a positive duration, ex. \"30m\", found \"-5m\"",
 		},
 		{
-			description: "invalid key in string val",
+			description: "await_approval step with empty command",
 			input: raw.Step{
-				StringVal: map[string]string{
-					"invalid": "",
+				CommandMap: EnvType{
+					"await_approval": {
+						"command": " ",
+						"timeout": "30m",
+					},
 				},
 			},
-			expErr: "\"invalid\" is not a valid step type",
+			expErr: "\"await_approval\" step \"command\" option must be a non-empty string",
 		},
 		{
-			description: "non extra_arg key",
+			description: "await_approval step with extra keys",
 			input: raw.Step{
-				Map: MapType{
-					"init": {
-						"invalid": nil,
+				CommandMap: EnvType{
+					"await_approval": {
+						"timeout": "30m",
+						"output":  "hide",
 					},
 				},
 			},
-			expErr: "built-in steps only support a single extra_args key, found \"invalid\" in step init",
+			expErr: "\"await_approval\" steps only support keys \"command\" and \"timeout\", found extra keys \"output\"",
 		},
 		{
-			description: "non extra_arg key",
+			description: "lock_providers step",
 			input: raw.Step{
 				Map: MapType{
-					"init": {
-						"invalid": nil,
-						"zzzzzzz": nil,
+					"lock_providers": {
+						"platforms": {"linux_amd64", "darwin_arm64"},
 					},
 				},
 			},
-			expErr: "built-in steps only support a single extra_args key, found 2: invalid,zzzzzzz",
 		},
 		{
-			description: "env step with no name key set",
+			description: "lock_providers step with push",
 			input: raw.Step{
 				CommandMap: EnvType{
-					"env": {
-						"value": "value",
+					"lock_providers": {
+						"platforms": []interface{}{"linux_amd64"},
+						"push":      true,
 					},
 				},
 			},
-			expErr: "env steps must have a \"name\" key set",
 		},
 		{
-			description: "env step with invalid key",
+			description: "lock_providers step without platforms",
 			input: raw.Step{
-				CommandMap: EnvType{
-					"env": {
-						"abc":      "",
-						"invalid2": "",
-					},
-				},
+				Key: String("lock_providers"),
 			},
-			expErr: "env steps only support keys \"name\", \"value\", \"command\", \"shell\" and \"shellArgs\", found key \"abc\"",
+			expErr: "\"lock_providers\" step must have a \"platforms\" key set",
 		},
 		{
-			description: "env step with both command and value set",
+			description: "lock_providers step with push and without platforms",
 			input: raw.Step{
 				CommandMap: EnvType{
-					"env": {
-						"name":    "name",
-						"command": "command",
-						"value":   "value",
+					"lock_providers": {
+						"push": true,
 					},
 				},
 			},
-			expErr: "env steps only support one of the \"value\" or \"command\" keys, found both",
+			expErr: "\"lock_providers\" step must have a \"platforms\" key set",
 		},
 		{
-			description: "env step with shell set but not command",
+			description: "lock_providers step with invalid platform",
 			input: raw.Step{
-				CommandMap: EnvType{
-					"env": {
-						"name":  "name",
-						"shell": "bash",
+				Map: MapType{
+					"lock_providers": {
+						"platforms": {"linux-amd64"},
 					},
 				},
 			},
-			expErr: "workflow steps only support \"shell\" key in combination with \"command\" key",
+			expErr: "\"lock_providers\" step \"platforms\" entries must be in the form os_arch, ex. \"linux_amd64\", found \"linux-amd64\"",
 		},
 		{
-			description: "env step with shellArgs set but not shell",
+			description: "lock_providers step with non-string platform",
 			input: raw.Step{
 				CommandMap: EnvType{
-					"env": {
-						"name":      "name",
-						"shellArgs": "-c",
+					"lock_providers": {
+						"platforms": []interface{}{42},
+						"push":      true,
 					},
 				},
 			},
-			expErr: "workflow steps only support \"shellArgs\" key in combination with \"shell\" key",
+			expErr: "\"lock_providers\" step \"platforms\" option must contain only strings, found 42",
 		},
 		{
-			description: "run step with shellArgs is not list of strings",
+			description: "lock_providers step with non-bool push",
 			input: raw.Step{
 				CommandMap: EnvType{
-					"run": {
-						"name":      "name",
-						"command":   "echo",
-						"shell":     "shell",
-						"shellArgs": []int{42, 42},
+					"lock_providers": {
+						"platforms": []interface{}{"linux_amd64"},
+						"push":      "yes please",
 					},
 				},
 			},
-			expErr: "\"run\" step \"shellArgs\" option must be a string or a list of strings, found [42 42]",
+			expErr: "\"lock_providers\" step \"push\" option must be a boolean, found yes please",
 		},
 		{
-			description: "run step with shellArgs contain not strings",
+			description: "lock_providers step with extra keys",
 			input: raw.Step{
-				CommandMap: EnvType{
-					"run": {
-						"name":      "name",
-						"command":   "echo",
-						"shell":     "shell",
-						"shellArgs": []interface{}{"-c", 42},
+				Map: MapType{
+					"lock_providers": {
+						"platforms":  {"linux_amd64"},
+						"extra_args": {"-fs-mirror=/mirror"},
 					},
 				},
 			},
-			expErr: "\"run\" step \"shellArgs\" option must contain only strings, found 42",
+			expErr: "\"lock_providers\" steps only support keys \"platforms\" and \"push\", found extra keys \"extra_args\"",
 		},
 		{
 			// For atlantis.yaml v2, this wouldn't parse, but now there should
@@ -567,6 +1161,22 @@ func TestStep_ToValid(t *testing.T) {
 				EnvVarName: "test",
 			},
 		},
+		{
+			description: "env step with vault",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"env": {
+						"name":  "test",
+						"vault": map[string]interface{}{"path": "secret/data/x", "key": "token"},
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:    "env",
+				EnvVarName:  "test",
+				EnvVarVault: &valid.VaultSecret{Path: "secret/data/x", Key: "token"},
+			},
+		},
 		{
 			description: "import step",
 			input: raw.Step{
@@ -576,6 +1186,111 @@ func TestStep_ToValid(t *testing.T) {
 				StepName: "import",
 			},
 		},
+		{
+			description: "validate step with extra_args",
+			input: raw.Step{
+				Map: MapType{
+					"validate": {
+						"extra_args": []string{"-json"},
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:  "validate",
+				ExtraArgs: []string{"-json"},
+			},
+		},
+		{
+			description: "plan step with targets",
+			input: raw.Step{
+				Map: MapType{
+					"plan": {
+						"extra_args": []string{"-var-file=staging.tfvars"},
+						"targets":    []string{"module.a", "module.b"},
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:  "plan",
+				ExtraArgs: []string{"-var-file=staging.tfvars"},
+				Targets:   []string{"module.a", "module.b"},
+			},
+		},
+		{
+			description: "plan step with refresh false",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"plan": {
+						"extra_args": []interface{}{"-var-file=staging.tfvars"},
+						"targets":    []interface{}{"module.a"},
+						"refresh":    false,
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:    "plan",
+				ExtraArgs:   []string{"-var-file=staging.tfvars"},
+				Targets:     []string{"module.a"},
+				SkipRefresh: true,
+			},
+		},
+		{
+			description: "plan step with output",
+			input: raw.Step{
+				Map: MapType{
+					"plan": {
+						"output": []string{"collapse_unchanged"},
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName: "plan",
+				Output:   []valid.PostProcessRunOutputOption{valid.PostProcessRunOutputCollapseUnchanged},
+			},
+		},
+		{
+			description: "plan step with refresh and output",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"plan": {
+						"refresh": false,
+						"output":  "collapse_unchanged",
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:    "plan",
+				SkipRefresh: true,
+				Output:      []valid.PostProcessRunOutputOption{valid.PostProcessRunOutputCollapseUnchanged},
+			},
+		},
+		{
+			description: "plan step with refresh true",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"plan": {
+						"refresh": true,
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName: "plan",
+			},
+		},
+		{
+			description: "fmt_check step with extra_args",
+			input: raw.Step{
+				Map: MapType{
+					"fmt_check": {
+						"extra_args": []string{"-recursive"},
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:  "fmt_check",
+				ExtraArgs: []string{"-recursive"},
+			},
+		},
 		{
 			description: "init extra_args",
 			input: raw.Step{
@@ -814,6 +1529,102 @@ func TestStep_ToValid(t *testing.T) {
 				RunCommand: "envs.sh",
 			},
 		},
+		{
+			description: "archive step",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"archive": {
+						"backend": "s3",
+						"bucket":  "my-plans",
+						"key":     "{{ .Pull.Num }}.tfplan",
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:       "archive",
+				ArchiveBackend: "s3",
+				ArchiveBucket:  "my-plans",
+				ArchiveKey:     "{{ .Pull.Num }}.tfplan",
+			},
+		},
+		{
+			description: "run step with store_as",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"run": {
+						"command":  "./build-ami.sh",
+						"output":   "hide",
+						"store_as": "ami_id",
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:   "run",
+				RunCommand: "./build-ami.sh",
+				Output:     []valid.PostProcessRunOutputOption{"hide"},
+				StoreAs:    "ami_id",
+			},
+		},
+		{
+			description: "await_approval step",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"await_approval": {
+						"command": "./request-approval.sh",
+						"timeout": "30m",
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:        "await_approval",
+				RunCommand:      "./request-approval.sh",
+				ApprovalTimeout: 30 * time.Minute,
+			},
+		},
+		{
+			description: "lock_providers step",
+			input: raw.Step{
+				Map: MapType{
+					"lock_providers": {
+						"platforms": {"linux_amd64", "darwin_arm64"},
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:               "lock_providers",
+				LockProvidersPlatforms: []string{"linux_amd64", "darwin_arm64"},
+			},
+		},
+		{
+			description: "lock_providers step with push",
+			input: raw.Step{
+				CommandMap: EnvType{
+					"lock_providers": {
+						"platforms": []interface{}{"linux_amd64", "darwin_arm64"},
+						"push":      true,
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:               "lock_providers",
+				LockProvidersPlatforms: []string{"linux_amd64", "darwin_arm64"},
+				LockProvidersPush:      true,
+			},
+		},
+		{
+			description: "module_pin_check step with allowlist",
+			input: raw.Step{
+				Map: MapType{
+					"module_pin_check": {
+						"allow": []string{"git::https://github.com/acme/"},
+					},
+				},
+			},
+			exp: valid.Step{
+				StepName:           "module_pin_check",
+				ModulePinAllowlist: []string{"git::https://github.com/acme/"},
+			},
+		},
 		{
 			description: "multienv step with single output",
 			input: raw.Step{
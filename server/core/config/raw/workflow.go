@@ -27,7 +27,12 @@ func (w Workflow) Validate() error {
 }
 
 func (w Workflow) toValidStage(stage *Stage, defaultStage valid.Stage) valid.Stage {
-	if stage == nil || stage.Steps == nil {
+	if stage == nil {
+		return defaultStage
+	}
+	if stage.Steps == nil {
+		// Allow retries to be configured for the default steps.
+		defaultStage.Retry = stage.Retry.ToValid()
 		return defaultStage
 	}
 
@@ -0,0 +1,146 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	getter "github.com/hashicorp/go-getter/v2"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// DefaultRemoteRepoCfgCacheTTL is how long a repo config fetched from a
+// repo_config_url is reused before it's fetched again.
+const DefaultRemoteRepoCfgCacheTTL = 5 * time.Minute
+
+// remoteRepoCfgFetchTimeout is the timeout of fetching a repo config so an
+// unavailable source doesn't block the command.
+const remoteRepoCfgFetchTimeout = 2 * time.Minute
+
+// RemoteRepoCfgFetcher fetches and caches repo configs from remote sources.
+// Its zero value is ready to use.
+type RemoteRepoCfgFetcher struct {
+	// CacheTTL is how long a fetched config is reused before it's fetched
+	// again. If it's 0, DefaultRemoteRepoCfgCacheTTL is used.
+	CacheTTL time.Duration
+
+	// mu guards cache and fetchLocks. It isn't held while fetching so
+	// fetching one source doesn't block the others.
+	mu    sync.Mutex
+	cache map[string]remoteRepoCfgCacheEntry
+	// fetchLocks holds a lock for each source so concurrent commands fetch
+	// it once.
+	fetchLocks map[string]*sync.Mutex
+}
+
+type remoteRepoCfgCacheEntry struct {
+	data      []byte
+	fetchedAt time.Time
+}
+
+// Fetch returns the content of the repo config at src, which is a go-getter
+// URL, ex. https://example.com/atlantis.yaml or
+// git::https://example.com/org/configs.git//teams/atlantis.yaml?ref=main.
+// Environment variables in src, ex. ${CONFIG_TOKEN}, are expanded so
+// credentials don't have to be written in the server-side repo config.
+// If fetching fails, the last fetched content is returned with a warning.
+func (f *RemoteRepoCfgFetcher) Fetch(log logging.SimpleLogging, src string) ([]byte, error) {
+	ttl := f.CacheTTL
+	if ttl == 0 {
+		ttl = DefaultRemoteRepoCfgCacheTTL
+	}
+
+	fetchLock := f.fetchLock(src)
+	fetchLock.Lock()
+	defer fetchLock.Unlock()
+
+	f.mu.Lock()
+	entry, ok := f.cache[src]
+	f.mu.Unlock()
+	if ok && time.Since(entry.fetchedAt) < ttl {
+		log.Debug("using repo config fetched from %s at %s", src, entry.fetchedAt.Format(time.RFC3339))
+		return entry.data, nil
+	}
+
+	data, err := fetchRemoteFile(src)
+	if err != nil {
+		if ok {
+			log.Warn("%s, using the repo config fetched at %s", err, entry.fetchedAt.Format(time.RFC3339))
+			return entry.data, nil
+		}
+		return nil, err
+	}
+	f.mu.Lock()
+	if f.cache == nil {
+		f.cache = make(map[string]remoteRepoCfgCacheEntry)
+	}
+	f.cache[src] = remoteRepoCfgCacheEntry{data: data, fetchedAt: time.Now()}
+	f.mu.Unlock()
+	log.Info("fetched repo config from %s", src)
+	return data, nil
+}
+
+// fetchLock returns the lock of src.
+func (f *RemoteRepoCfgFetcher) fetchLock(src string) *sync.Mutex {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.fetchLocks == nil {
+		f.fetchLocks = make(map[string]*sync.Mutex)
+	}
+	l, ok := f.fetchLocks[src]
+	if !ok {
+		l = &sync.Mutex{}
+		f.fetchLocks[src] = l
+	}
+	return l
+}
+
+func fetchRemoteFile(src string) ([]byte, error) {
+	expanded := os.ExpandEnv(src)
+	// The errors of go-getter contain the URL, which mustn't show the
+	// expanded credentials.
+	redact := func(err error) error {
+		return fmt.Errorf("fetching repo config from %s: %s", src, strings.ReplaceAll(err.Error(), expanded, src))
+	}
+
+	tmpDir, err := os.MkdirTemp("", "atlantis-repo-cfg")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir) // nolint: errcheck
+
+	ctx, cancel := context.WithTimeout(context.Background(), remoteRepoCfgFetchTimeout)
+	defer cancel()
+
+	// A path in a repository or archive, ex. //teams/atlantis.yaml, requires
+	// getting the whole directory.
+	dirSrc, subDir := getter.SourceDirSubdir(expanded)
+	var path string
+	if subDir != "" {
+		if !filepath.IsLocal(subDir) {
+			return nil, fmt.Errorf("fetching repo config from %s: the path %q must be inside the source", src, subDir)
+		}
+		dst := filepath.Join(tmpDir, "src")
+		if _, err := getter.Get(ctx, dst, dirSrc); err != nil {
+			return nil, redact(err)
+		}
+		path = filepath.Join(dst, subDir)
+	} else {
+		path = filepath.Join(tmpDir, "repo-cfg")
+		if _, err := getter.GetFile(ctx, path, expanded); err != nil {
+			return nil, redact(err)
+		}
+	}
+	data, err := os.ReadFile(path) // nolint: gosec
+	if err != nil {
+		return nil, redact(err)
+	}
+	return data, nil
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package config_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/config"
+	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+const remoteRepoCfg = `
+version: 3
+projects:
+- dir: mydir
+`
+
+func TestRemoteRepoCfgFetcher_Fetch(t *testing.T) {
+	var requests atomic.Int32
+	var fail atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			return
+		}
+		requests.Add(1)
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(remoteRepoCfg)) // nolint: errcheck
+	}))
+	defer server.Close()
+	logger := logging.NewNoopLogger(t)
+	src := server.URL + "/atlantis.yaml"
+
+	t.Run("cached", func(t *testing.T) {
+		requests.Store(0)
+		fetcher := config.RemoteRepoCfgFetcher{}
+		for range 2 {
+			data, err := fetcher.Fetch(logger, src)
+			Ok(t, err)
+			Equals(t, remoteRepoCfg, string(data))
+		}
+		Equals(t, int32(1), requests.Load())
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		requests.Store(0)
+		fetcher := config.RemoteRepoCfgFetcher{CacheTTL: time.Nanosecond}
+		for range 2 {
+			_, err := fetcher.Fetch(logger, src)
+			Ok(t, err)
+		}
+		Equals(t, int32(2), requests.Load())
+	})
+
+	t.Run("failed fetch uses the last fetched config", func(t *testing.T) {
+		fetcher := config.RemoteRepoCfgFetcher{CacheTTL: time.Nanosecond}
+		_, err := fetcher.Fetch(logger, src)
+		Ok(t, err)
+		fail.Store(true)
+		defer fail.Store(false)
+		data, err := fetcher.Fetch(logger, src)
+		Ok(t, err)
+		Equals(t, remoteRepoCfg, string(data))
+	})
+
+	t.Run("failed fetch", func(t *testing.T) {
+		fail.Store(true)
+		defer fail.Store(false)
+		t.Setenv("REPO_CFG_TOKEN", "secret")
+		fetcher := config.RemoteRepoCfgFetcher{}
+		_, err := fetcher.Fetch(logger, src+"?token=${REPO_CFG_TOKEN}")
+		ErrContains(t, "fetching repo config from "+src+"?token=${REPO_CFG_TOKEN}: ", err)
+		Assert(t, !strings.Contains(err.Error(), "secret"), "expected the token to be redacted from %q", err)
+	})
+}
+
+// Test that fetching a source doesn't block fetching other sources.
+func TestRemoteRepoCfgFetcher_FetchConcurrently(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			return
+		}
+		if r.URL.Path == "/slow.yaml" {
+			close(started)
+			<-release
+		}
+		w.Write([]byte(remoteRepoCfg)) // nolint: errcheck
+	}))
+	defer server.Close()
+	defer close(release)
+	logger := logging.NewNoopLogger(t)
+	fetcher := config.RemoteRepoCfgFetcher{}
+
+	go fetcher.Fetch(logger, server.URL+"/slow.yaml") // nolint: errcheck
+	<-started
+
+	fastDone := make(chan error, 1)
+	go func() {
+		_, err := fetcher.Fetch(logger, server.URL+"/fast.yaml")
+		fastDone <- err
+	}()
+	select {
+	case err := <-fastDone:
+		Ok(t, err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("fetching a source was blocked by fetching another source")
+	}
+}
+
+func TestRemoteRepoCfgFetcher_FetchGitSubdir(t *testing.T) {
+	repoDir := t.TempDir()
+	Ok(t, os.MkdirAll(filepath.Join(repoDir, "teams"), 0700))
+	Ok(t, os.WriteFile(filepath.Join(repoDir, "teams", "atlantis.yaml"), []byte(remoteRepoCfg), 0600))
+	for _, args := range [][]string{
+		{"init", "--initial-branch=main"},
+		{"add", "."},
+		{"-c", "user.name=atlantis", "-c", "user.email=atlantis@example.com", "commit", "-m", "initial commit"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		Assert(t, err == nil, "git %v: %s", args, out)
+	}
+
+	fetcher := config.RemoteRepoCfgFetcher{}
+	data, err := fetcher.Fetch(logging.NewNoopLogger(t), "git::file://"+repoDir+"//teams/atlantis.yaml?ref=main")
+	Ok(t, err)
+	Equals(t, remoteRepoCfg, string(data))
+
+	_, err = fetcher.Fetch(logging.NewNoopLogger(t), "git::file://"+repoDir+"//../atlantis.yaml")
+	ErrContains(t, `the path "../atlantis.yaml" must be inside the source`, err)
+}
+
+func TestParseRemoteRepoCfg(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(remoteRepoCfg)) // nolint: errcheck
+	}))
+	defer server.Close()
+
+	globalCfg := valid.NewGlobalCfgFromArgs(globalCfgArgs)
+	globalCfg.Repos = append(globalCfg.Repos, valid.Repo{
+		ID:            "github.com/owner/repo",
+		RepoConfigURL: server.URL + "/atlantis.yaml",
+	})
+	p := config.ParserValidator{}
+
+	hasRepoCfg, repoCfg, err := p.ParseRemoteRepoCfg(logging.NewNoopLogger(t), globalCfg, "github.com/owner/repo", "main")
+	Ok(t, err)
+	Assert(t, hasRepoCfg, "expected the repo to have a remote repo config")
+	Equals(t, 1, len(repoCfg.Projects))
+	Equals(t, "mydir", repoCfg.Projects[0].Dir)
+
+	hasRepoCfg, _, err = p.ParseRemoteRepoCfg(logging.NewNoopLogger(t), globalCfg, "github.com/owner/other", "main")
+	Ok(t, err)
+	Assert(t, !hasRepoCfg, "expected the repo not to have a remote repo config")
+}
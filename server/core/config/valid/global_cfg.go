@@ -5,7 +5,10 @@ package valid
 
 import (
 	"fmt"
+	"maps"
 	"regexp"
+	"slices"
+	"sort"
 	"strings"
 
 	version "github.com/hashicorp/go-version"
@@ -17,6 +20,7 @@ const MergeableCommandReq = "mergeable"
 const ApprovedCommandReq = "approved"
 const UnDivergedCommandReq = "undiverged"
 const PoliciesPassedCommandReq = "policies_passed"
+
 const PlanRequirementsKey = "plan_requirements"
 const ApplyRequirementsKey = "apply_requirements"
 const ImportRequirementsKey = "import_requirements"
@@ -31,9 +35,26 @@ const PolicyCheckKey = "policy_check"
 const CustomPolicyCheckKey = "custom_policy_check"
 const AutoDiscoverKey = "autodiscover"
 const SilencePRCommentsKey = "silence_pr_comments"
+const QuietPolicyChecksKey = "quiet_policy_checks"
+const LockedOverridesKey = "locked_overrides"
+const LockedOverridesActionKey = "locked_overrides_action"
+const AllowedRunCommandsKey = "allowed_run_commands"
+
+// Actions taken when a repo config sets a key listed in locked_overrides.
+const (
+	// LockedOverridesRejectAction fails the command with an error.
+	LockedOverridesRejectAction = "reject"
+	// LockedOverridesIgnoreAction uses the server-side value and logs a
+	// warning.
+	LockedOverridesIgnoreAction = "ignore"
+)
 
 var AllowedSilencePRComments = []string{"plan", "apply"}
 
+// DefaultAllowedShells are the shells workflow steps may set via the 'shell'
+// key when the server-side config doesn't define 'allowed_shells'.
+var DefaultAllowedShells = []string{"sh", "bash", "zsh", "pwsh"}
+
 // DefaultAtlantisFile is the default name of the config file for each repo.
 const DefaultAtlantisFile = "atlantis.yaml"
 
@@ -44,6 +65,13 @@ const DefaultAtlantisFile = "atlantis.yaml"
 // requirements in the config and removing the flag to enable policy checking.
 var NonOverridableApplyReqs = []string{PoliciesPassedCommandReq}
 
+// CustomRequirement is an apply requirement that passes when Command exits
+// zero. Its command runs on the Atlantis server so it can only be set in the
+// server-side repo config.
+type CustomRequirement struct {
+	Command string
+}
+
 // GlobalCfg is the final parsed version of server-side repo config.
 type GlobalCfg struct {
 	Repos      []Repo
@@ -51,6 +79,15 @@ type GlobalCfg struct {
 	PolicySets PolicySets
 	Metrics    Metrics
 	TeamAuthz  TeamAuthz
+	// AllowedShells is the list of shells workflow steps may use. If empty,
+	// DefaultAllowedShells is used.
+	AllowedShells []string
+	// StepOutputDenylist fails commands if any step's output matches one of
+	// its regexes.
+	StepOutputDenylist []*regexp.Regexp
+	// StepOutputMasks are regexes whose matches are replaced with "***" in
+	// the output of every step.
+	StepOutputMasks []*regexp.Regexp
 }
 
 type Metrics struct {
@@ -74,17 +111,25 @@ type Repo struct {
 	ID string
 	// IDRegex is the regex match for this config.
 	// If ID is set then this will be nil.
-	IDRegex                   *regexp.Regexp
-	BranchRegex               *regexp.Regexp
-	RepoConfigFile            string
-	PlanRequirements          []string
-	ApplyRequirements         []string
+	IDRegex        *regexp.Regexp
+	BranchRegex    *regexp.Regexp
+	RepoConfigFile string
+	// RepoConfigURL is the go-getter URL of the repo config used when the
+	// repo doesn't have one.
+	RepoConfigURL     string
+	PlanRequirements  []string
+	ApplyRequirements []string
+	// CustomApplyRequirements are the custom requirements set in
+	// apply_requirements.
+	CustomApplyRequirements   []CustomRequirement
 	ImportRequirements        []string
 	PreWorkflowHooks          []*WorkflowHook
 	Workflow                  *Workflow
 	PostWorkflowHooks         []*WorkflowHook
 	AllowedWorkflows          []string
 	AllowedOverrides          []string
+	LockedOverrides           []string
+	LockedOverridesAction     string
 	AllowCustomWorkflows      *bool
 	DeleteSourceBranchOnMerge *bool
 	RepoLocking               *bool
@@ -93,11 +138,20 @@ type Repo struct {
 	CustomPolicyCheck         *bool
 	AutoDiscover              *AutoDiscover
 	SilencePRComments         []string
+	// QuietPolicyChecks is whether successful policy checks aren't
+	// commented, like --quiet-policy-checks for the repo.
+	QuietPolicyChecks *bool
+	// AllowedRunCommands are the regexes the commands of the repo's run, env
+	// and multienv steps must fully match. If nil, all commands are allowed.
+	AllowedRunCommands []*regexp.Regexp
 }
 
 type MergedProjectCfg struct {
-	PlanRequirements          []string
-	ApplyRequirements         []string
+	PlanRequirements  []string
+	ApplyRequirements []string
+	// CustomApplyRequirements are the custom apply requirements of the
+	// server-side repo config. Repo configs can't override them.
+	CustomApplyRequirements   []CustomRequirement
 	ImportRequirements        []string
 	Workflow                  Workflow
 	AllowedWorkflows          []string
@@ -118,6 +172,27 @@ type MergedProjectCfg struct {
 	PolicyCheck               bool
 	CustomPolicyCheck         bool
 	SilencePRComments         []string
+	QuietPolicyChecks         bool
+	NoChangesMessage          string
+	Labels                    map[string]string
+	HidePrevPlanComments      *bool
+	PlanFilePath              string
+	InitUpgrade               bool
+	PushLockFile              bool
+	Automerge                 *bool
+	DestroyThreshold          int
+	DestroyThresholdApproval  bool
+	WorkspaceTfvars           bool
+	// AutomergeMethod is the repo's automerge_method.
+	AutomergeMethod    string
+	StepOutputDenylist []*regexp.Regexp
+	StepOutputMasks    []*regexp.Regexp
+	// AllowedRunCommands are the regexes the commands of run, env and
+	// multienv steps must fully match. If empty, all commands are allowed.
+	AllowedRunCommands []*regexp.Regexp
+	// ConfigOrder is the position of the project in the repo config starting
+	// at 1, or 0 if the project isn't configured there.
+	ConfigOrder int
 }
 
 // WorkflowHook is a map of custom run commands to run before or after workflows.
@@ -290,6 +365,17 @@ func (r Repo) IDString() string {
 func (g GlobalCfg) MergeProjectCfg(log logging.SimpleLogging, repoID string, proj Project, rCfg RepoCfg) MergedProjectCfg {
 	log.Debug("MergeProjectCfg started")
 	planReqs, applyReqs, importReqs, workflow, allowedOverrides, allowCustomWorkflows, deleteSourceBranchOnMerge, repoLocks, policyCheck, customPolicyCheck, _, silencePRComments := g.getMatchingCfg(log, repoID)
+	// Locked keys are never overridden. If they're rejected the repo config
+	// has already failed validation.
+	lockedOverrides, _ := g.lockedOverrides(repoID)
+	for _, key := range lockedOverrides {
+		if overridesKey(key, proj, rCfg) {
+			log.Warn("ignoring repo config %s for dir: %q workspace: %q since the key is locked by server-side config '%s'", key, proj.Dir, proj.Workspace, LockedOverridesKey)
+		}
+	}
+	allowedOverrides = slices.DeleteFunc(slices.Clone(allowedOverrides), func(key string) bool {
+		return utils.SlicesContains(lockedOverrides, key)
+	})
 	// If repos are allowed to override certain keys then override them.
 	for _, key := range allowedOverrides {
 		switch key {
@@ -402,13 +488,14 @@ func (g GlobalCfg) MergeProjectCfg(log logging.SimpleLogging, repoID string, pro
 		DeleteSourceBranchOnMergeKey, deleteSourceBranchOnMerge,
 		RepoLockingKey, repoLocks.Mode,
 		PolicyCheckKey, policyCheck,
-		CustomPolicyCheckKey, policyCheck,
+		CustomPolicyCheckKey, customPolicyCheck,
 		SilencePRCommentsKey, strings.Join(silencePRComments, ","),
 	)
 
 	return MergedProjectCfg{
 		PlanRequirements:          planReqs,
 		ApplyRequirements:         applyReqs,
+		CustomApplyRequirements:   g.customApplyRequirements(repoID),
 		ImportRequirements:        importReqs,
 		Workflow:                  workflow,
 		RepoRelDir:                proj.Dir,
@@ -420,12 +507,28 @@ func (g GlobalCfg) MergeProjectCfg(log logging.SimpleLogging, repoID string, pro
 		TerraformVersion:          proj.TerraformVersion,
 		RepoCfgVersion:            rCfg.Version,
 		PolicySets:                g.PolicySets,
+		StepOutputDenylist:        g.StepOutputDenylist,
+		StepOutputMasks:           g.StepOutputMasks,
 		DeleteSourceBranchOnMerge: deleteSourceBranchOnMerge,
 		ExecutionOrderGroup:       proj.ExecutionOrderGroup,
+		ConfigOrder:               rCfg.ProjectIndex(proj) + 1,
 		RepoLocks:                 repoLocks,
 		PolicyCheck:               policyCheck,
 		CustomPolicyCheck:         customPolicyCheck,
 		SilencePRComments:         silencePRComments,
+		QuietPolicyChecks:         g.quietPolicyChecks(repoID),
+		AllowedRunCommands:        g.allowedRunCommands(repoID),
+		NoChangesMessage:          proj.NoChangesMessage,
+		Labels:                    proj.Labels,
+		HidePrevPlanComments:      proj.HidePrevPlanComments,
+		PlanFilePath:              proj.PlanFilePath,
+		InitUpgrade:               proj.InitUpgrade,
+		PushLockFile:              proj.PushLockFile,
+		Automerge:                 proj.Automerge,
+		DestroyThreshold:          proj.DestroyThreshold,
+		DestroyThresholdApproval:  proj.DestroyThresholdApproval,
+		WorkspaceTfvars:           proj.WorkspaceTfvars,
+		AutomergeMethod:           rCfg.AutomergeMethod,
 	}
 }
 
@@ -437,6 +540,7 @@ func (g GlobalCfg) DefaultProjCfg(log logging.SimpleLogging, repoID string, repo
 	return MergedProjectCfg{
 		PlanRequirements:          planReqs,
 		ApplyRequirements:         applyReqs,
+		CustomApplyRequirements:   g.customApplyRequirements(repoID),
 		ImportRequirements:        importReqs,
 		Workflow:                  workflow,
 		RepoRelDir:                repoRelDir,
@@ -446,11 +550,15 @@ func (g GlobalCfg) DefaultProjCfg(log logging.SimpleLogging, repoID string, repo
 		TerraformDistribution:     nil,
 		TerraformVersion:          nil,
 		PolicySets:                g.PolicySets,
+		StepOutputDenylist:        g.StepOutputDenylist,
+		StepOutputMasks:           g.StepOutputMasks,
 		DeleteSourceBranchOnMerge: deleteSourceBranchOnMerge,
 		RepoLocks:                 repoLocks,
 		PolicyCheck:               policyCheck,
 		CustomPolicyCheck:         customPolicyCheck,
 		SilencePRComments:         silencePRComments,
+		QuietPolicyChecks:         g.quietPolicyChecks(repoID),
+		AllowedRunCommands:        g.allowedRunCommands(repoID),
 	}
 }
 
@@ -465,6 +573,24 @@ func (g GlobalCfg) RepoAutoDiscoverCfg(repoID string) *AutoDiscover {
 	return nil
 }
 
+// RepoCfgWarnings returns the deprecated uses of rCfg for repo with id repoID.
+// They're ignored for now and will fail ValidateRepoCfg in a future release.
+func (g GlobalCfg) RepoCfgWarnings(rCfg RepoCfg, repoID string) []string {
+	var allowedOverrides []string
+	for _, repo := range g.Repos {
+		if repo.IDMatches(repoID) && repo.AllowedOverrides != nil {
+			allowedOverrides = repo.AllowedOverrides
+		}
+	}
+	var warnings []string
+	for _, p := range rCfg.Projects {
+		if p.PolicyCheck != nil && !utils.SlicesContains(allowedOverrides, PolicyCheckKey) {
+			warnings = append(warnings, fmt.Sprintf("ignoring '%s' key of the project in dir %q and workspace %q: server-side config needs '%s: [%s]', this will be an error in a future release", PolicyCheckKey, p.Dir, p.Workspace, AllowedOverridesKey, PolicyCheckKey))
+		}
+	}
+	return warnings
+}
+
 // ValidateRepoCfg validates that rCfg for repo with id repoID is valid based
 // on our global config.
 func (g GlobalCfg) ValidateRepoCfg(rCfg RepoCfg, repoID string) error {
@@ -486,6 +612,20 @@ func (g GlobalCfg) ValidateRepoCfg(rCfg RepoCfg, repoID string) error {
 			}
 		}
 	}
+	// Check locked overrides. If they're ignored, repo config may set them
+	// and MergeProjectCfg won't use them.
+	lockedOverrides, lockedOverridesAction := g.lockedOverrides(repoID)
+	for _, key := range lockedOverrides {
+		if lockedOverridesAction == LockedOverridesIgnoreAction {
+			allowedOverrides = append(slices.Clone(allowedOverrides), key)
+			continue
+		}
+		for _, p := range rCfg.Projects {
+			if overridesKey(key, p, rCfg) {
+				return fmt.Errorf("repo config not allowed to set '%s' key: it is locked by server-side config '%s'", key, LockedOverridesKey)
+			}
+		}
+	}
 	for _, p := range rCfg.Projects {
 		if p.WorkflowName != nil && !utils.SlicesContains(allowedOverrides, WorkflowKey) {
 			return fmt.Errorf("repo config not allowed to set '%s' key: server-side config needs '%s: [%s]'", WorkflowKey, AllowedOverridesKey, WorkflowKey)
@@ -547,6 +687,14 @@ func (g GlobalCfg) ValidateRepoCfg(rCfg RepoCfg, repoID string) error {
 		return fmt.Errorf("repo config not allowed to define custom workflows: server-side config needs '%s: true'", AllowCustomWorkflowsKey)
 	}
 
+	if err := g.ValidateWorkflowShells(rCfg.Workflows); err != nil {
+		return err
+	}
+
+	if err := g.ValidateWorkflowRunCommands(repoID, rCfg.Workflows); err != nil {
+		return err
+	}
+
 	// Check if the repo has set a workflow name that doesn't exist.
 	for _, p := range rCfg.Projects {
 		if p.WorkflowName != nil {
@@ -588,6 +736,223 @@ func (g GlobalCfg) ValidateRepoCfg(rCfg RepoCfg, repoID string) error {
 	return nil
 }
 
+// SelectWorkflow returns the workflow named name for the projects of the repo
+// with id repoID, ex. when it's selected with a comment flag. Like setting
+// the workflow in rCfg, it errors if the server-side config doesn't allow the
+// repo to override the workflow or to use that workflow.
+func (g GlobalCfg) SelectWorkflow(log logging.SimpleLogging, repoID string, rCfg RepoCfg, name string) (Workflow, error) {
+	_, _, _, _, allowedOverrides, allowCustomWorkflows, _, _, _, _, _, _ := g.getMatchingCfg(log, repoID)
+	lockedOverrides, _ := g.lockedOverrides(repoID)
+	if utils.SlicesContains(lockedOverrides, WorkflowKey) {
+		return Workflow{}, fmt.Errorf("cannot select workflow %q: '%s' is locked by server-side config '%s'", name, WorkflowKey, LockedOverridesKey)
+	}
+	if !utils.SlicesContains(allowedOverrides, WorkflowKey) {
+		return Workflow{}, fmt.Errorf("cannot select workflow %q: server-side config needs '%s: [%s]'", name, AllowedOverridesKey, WorkflowKey)
+	}
+
+	// Repo workflows override global workflows, like in MergeProjectCfg.
+	workflow, ok := g.Workflows[name]
+	repoWorkflow, isRepoWorkflow := rCfg.Workflows[name]
+	isRepoWorkflow = isRepoWorkflow && allowCustomWorkflows
+	if isRepoWorkflow {
+		workflow, ok = repoWorkflow, true
+	}
+	if !ok {
+		names := slices.Collect(maps.Keys(g.Workflows))
+		if allowCustomWorkflows {
+			names = append(names, slices.Collect(maps.Keys(rCfg.Workflows))...)
+		}
+		slices.Sort(names)
+		return Workflow{}, fmt.Errorf("workflow %q is not defined, the defined workflows are: %s", name, strings.Join(slices.Compact(names), ", "))
+	}
+
+	var allowedWorkflows []string
+	for _, repo := range g.Repos {
+		if repo.IDMatches(repoID) && repo.AllowedWorkflows != nil {
+			allowedWorkflows = repo.AllowedWorkflows
+		}
+	}
+	if len(allowedWorkflows) != 0 && !isRepoWorkflow && !utils.SlicesContains(allowedWorkflows, name) {
+		return Workflow{}, fmt.Errorf("workflow '%s' is not allowed for this repo", name)
+	}
+	return workflow, nil
+}
+
+// lockedOverrides returns the keys locked by every repo that matches repoID
+// and the action taken if repo config sets them. Unlike other settings, locks
+// from earlier repos can't be removed by later ones.
+func (g GlobalCfg) lockedOverrides(repoID string) (locked []string, action string) {
+	action = LockedOverridesRejectAction
+	for _, repo := range g.Repos {
+		if !repo.IDMatches(repoID) {
+			continue
+		}
+		for _, key := range repo.LockedOverrides {
+			if !utils.SlicesContains(locked, key) {
+				locked = append(locked, key)
+			}
+		}
+		if repo.LockedOverridesAction != "" {
+			action = repo.LockedOverridesAction
+		}
+	}
+	return locked, action
+}
+
+// quietPolicyChecks returns the quiet_policy_checks setting of the last repo
+// matching repoID that sets it.
+func (g GlobalCfg) quietPolicyChecks(repoID string) bool {
+	quiet := false
+	for _, repo := range g.Repos {
+		if repo.IDMatches(repoID) && repo.QuietPolicyChecks != nil {
+			quiet = *repo.QuietPolicyChecks
+		}
+	}
+	return quiet
+}
+
+// allowedRunCommands returns the allowed_run_commands of the last repo
+// matching repoID that sets them.
+func (g GlobalCfg) allowedRunCommands(repoID string) []*regexp.Regexp {
+	var allowed []*regexp.Regexp
+	for _, repo := range g.Repos {
+		if repo.IDMatches(repoID) && repo.AllowedRunCommands != nil {
+			allowed = repo.AllowedRunCommands
+		}
+	}
+	return allowed
+}
+
+// customApplyRequirements returns the custom apply requirements of the last
+// repo matching repoID that sets apply_requirements.
+func (g GlobalCfg) customApplyRequirements(repoID string) []CustomRequirement {
+	var reqs []CustomRequirement
+	for _, repo := range g.Repos {
+		if repo.IDMatches(repoID) && (repo.ApplyRequirements != nil || repo.CustomApplyRequirements != nil) {
+			reqs = repo.CustomApplyRequirements
+		}
+	}
+	return reqs
+}
+
+// overridesKey returns true if proj or the repo-root level settings in rCfg
+// set the override key.
+func overridesKey(key string, proj Project, rCfg RepoCfg) bool {
+	switch key {
+	case PlanRequirementsKey:
+		return proj.PlanRequirements != nil
+	case ApplyRequirementsKey:
+		return proj.ApplyRequirements != nil
+	case ImportRequirementsKey:
+		return proj.ImportRequirements != nil
+	case WorkflowKey:
+		return proj.WorkflowName != nil
+	case DeleteSourceBranchOnMergeKey:
+		return proj.DeleteSourceBranchOnMerge != nil || rCfg.DeleteSourceBranchOnMerge != nil
+	case RepoLockingKey:
+		return proj.RepoLocking != nil
+	case RepoLocksKey:
+		return proj.RepoLocks != nil || rCfg.RepoLocks != nil
+	case PolicyCheckKey:
+		return proj.PolicyCheck != nil
+	case CustomPolicyCheckKey:
+		return proj.CustomPolicyCheck != nil
+	case SilencePRCommentsKey:
+		return proj.SilencePRComments != nil || rCfg.SilencePRComments != nil
+	}
+	return false
+}
+
+// ValidateWorkflowShells returns an error if any step in workflows sets a
+// shell that isn't on the allowed shells list. Steps that don't set a shell
+// are always valid since they use the default shell.
+func (g GlobalCfg) ValidateWorkflowShells(workflows map[string]Workflow) error {
+	allowedShells := g.AllowedShells
+	if len(allowedShells) == 0 {
+		allowedShells = DefaultAllowedShells
+	}
+
+	var names []string
+	for name := range workflows {
+		names = append(names, name)
+	}
+	// Sort so errors are deterministic.
+	sort.Strings(names)
+
+	for _, name := range names {
+		w := workflows[name]
+		for _, stage := range []Stage{w.Plan, w.Apply, w.PolicyCheck, w.Import, w.StateRm} {
+			for _, step := range stage.Steps {
+				if step.RunShell == nil || step.RunShell.Shell == "" {
+					continue
+				}
+				if !utils.SlicesContains(allowedShells, step.RunShell.Shell) {
+					return fmt.Errorf("workflow %q: %q step shell %q is not allowed, allowed shells are [%s]",
+						name, step.StepName, step.RunShell.Shell, strings.Join(allowedShells, ", "))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateWorkflowRunCommands returns an error if any run, env or multienv
+// step in workflows runs a command that the server-side config doesn't allow
+// for the repo with id repoID.
+func (g GlobalCfg) ValidateWorkflowRunCommands(repoID string, workflows map[string]Workflow) error {
+	allowed := g.allowedRunCommands(repoID)
+	if len(allowed) == 0 {
+		return nil
+	}
+
+	var names []string
+	for name := range workflows {
+		names = append(names, name)
+	}
+	// Sort so errors are deterministic.
+	sort.Strings(names)
+
+	for _, name := range names {
+		w := workflows[name]
+		for _, stage := range []Stage{w.Plan, w.Apply, w.PolicyCheck, w.Import, w.StateRm} {
+			for _, step := range stage.Steps {
+				if err := ValidateRunCommand(allowed, step); err != nil {
+					return fmt.Errorf("workflow %q: %w", name, err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// ValidateRunCommand returns an error if step is a run, env or multienv step
+// whose command doesn't fully match any of the allowed regexes. env steps that
+// read from Vault are matched as "vault read <path>" so the allowlist also
+// restricts the secrets they read. All commands are allowed if allowed is
+// empty.
+func ValidateRunCommand(allowed []*regexp.Regexp, step Step) error {
+	if len(allowed) == 0 {
+		return nil
+	}
+	if step.StepName != "run" && step.StepName != "env" && step.StepName != "multienv" {
+		return nil
+	}
+	command := step.RunCommand
+	if step.EnvVarVault != nil {
+		command = "vault read " + step.EnvVarVault.Path
+	}
+	if command == "" {
+		return nil
+	}
+	for _, r := range allowed {
+		if r.MatchString(command) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q step command %q is not allowed for this repo: it must match one of the server-side config's '%s'",
+		step.StepName, command, AllowedRunCommandsKey)
+}
+
 // getMatchingCfg returns the key settings for repoID.
 func (g GlobalCfg) getMatchingCfg(log logging.SimpleLogging, repoID string) (planReqs []string, applyReqs []string, importReqs []string, workflow Workflow, allowedOverrides []string, allowCustomWorkflows bool, deleteSourceBranchOnMerge bool, repoLocks RepoLocks, policyCheck bool, customPolicyCheck bool, autoDiscover AutoDiscover, silencePRComments []string) {
 	toLog := make(map[string]string)
@@ -720,3 +1085,13 @@ func (g GlobalCfg) RepoConfigFile(repoID string) string {
 	}
 	return DefaultAtlantisFile
 }
+
+// RepoConfigURL returns the URL of the repo config used when the repository
+// doesn't have one, or "" if there isn't one.
+func (g GlobalCfg) RepoConfigURL(repoID string) string {
+	repo := g.MatchingRepo(repoID)
+	if repo != nil {
+		return repo.RepoConfigURL
+	}
+	return ""
+}
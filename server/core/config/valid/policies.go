@@ -37,17 +37,24 @@ type PolicySet struct {
 	ApproveCount       int
 	Owners             PolicyOwners
 	PreventSelfApprove bool
+	// SoftFail is whether failing policies of the set are only warnings that
+	// don't require approval or block apply.
+	SoftFail bool
+	// Approvers are the teams, or GitLab groups, whose members are the only
+	// ones that can approve the policy set if it's set. Owners that aren't
+	// members of them can't approve it.
+	Approvers []string
 }
 
 func (p *PolicySets) HasPolicies() bool {
 	return len(p.PolicySets) > 0
 }
 
-// Check if any level of policy owners includes teams
+// Check if any level of policy owners or approvers includes teams
 func (p *PolicySets) HasTeamOwners() bool {
 	hasTeamOwners := len(p.Owners.Teams) > 0
 	for _, policySet := range p.PolicySets {
-		if len(policySet.Owners.Teams) > 0 {
+		if len(policySet.Owners.Teams) > 0 || len(policySet.Approvers) > 0 {
 			hasTeamOwners = true
 		}
 	}
@@ -72,11 +79,21 @@ func (o *PolicyOwners) IsOwner(username string, userTeams []string) bool {
 	return false
 }
 
-// Return all owner teams from all policy sets
+// IsApprover returns whether a member of userTeams can approve the policy set
+// given its Approvers.
+func (p *PolicySet) IsApprover(userTeams []string) bool {
+	if len(p.Approvers) == 0 {
+		return true
+	}
+	approvers := PolicyOwners{Teams: p.Approvers}
+	return approvers.IsOwner("", userTeams)
+}
+
+// Return all owner and approver teams from all policy sets
 func (p *PolicySets) AllTeams() []string {
 	teams := p.Owners.Teams
 	for _, policySet := range p.PolicySets {
-		for _, team := range policySet.Owners.Teams {
+		for _, team := range slices.Concat(policySet.Owners.Teams, policySet.Approvers) {
 			if !slices.Contains(teams, team) {
 				teams = append(teams, team)
 			}
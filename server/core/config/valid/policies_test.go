@@ -59,6 +59,18 @@ func TestPoliciesConfig_HasTeamOwners(t *testing.T) {
 			},
 			expResult: true,
 		},
+		{
+			description: "has policy-level approvers",
+			input: valid.PolicySets{
+				PolicySets: []valid.PolicySet{
+					{
+						Name:      "policy1",
+						Approvers: []string{"someteam"},
+					},
+				},
+			},
+			expResult: true,
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.description, func(t *testing.T) {
@@ -178,6 +190,23 @@ func TestPoliciesConfig_AllTeams(t *testing.T) {
 			},
 			expResult: []string{"team1", "team2"},
 		},
+		{
+			description: "has policy-level approvers",
+			input: valid.PolicySets{
+				Owners: valid.PolicyOwners{
+					Teams: []string{
+						"team1",
+					},
+				},
+				PolicySets: []valid.PolicySet{
+					{
+						Name:      "policy1",
+						Approvers: []string{"team1", "security"},
+					},
+				},
+			},
+			expResult: []string{"team1", "security"},
+		},
 	}
 	for _, c := range cases {
 		t.Run(c.description, func(t *testing.T) {
@@ -186,3 +215,36 @@ func TestPoliciesConfig_AllTeams(t *testing.T) {
 		})
 	}
 }
+
+func TestPolicySet_IsApprover(t *testing.T) {
+	cases := []struct {
+		description string
+		approvers   []string
+		userTeams   []string
+		expResult   bool
+	}{
+		{
+			description: "no approvers",
+			userTeams:   []string{"team1"},
+			expResult:   true,
+		},
+		{
+			description: "member of an approver team",
+			approvers:   []string{"security", "platform"},
+			userTeams:   []string{"team1", "Platform"},
+			expResult:   true,
+		},
+		{
+			description: "not a member of an approver team",
+			approvers:   []string{"security"},
+			userTeams:   []string{"team1"},
+			expResult:   false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			policySet := valid.PolicySet{Name: "policy1", Approvers: c.approvers}
+			Equals(t, c.expResult, policySet.IsApprover(c.userTeams))
+		})
+	}
+}
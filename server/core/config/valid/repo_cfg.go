@@ -8,21 +8,36 @@ package valid
 import (
 	"fmt"
 	"log"
+	"path"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	version "github.com/hashicorp/go-version"
+	"github.com/moby/patternmatcher"
+)
+
+// Merge methods of automerge.
+const (
+	MergeAutomergeMethod  = "merge"
+	RebaseAutomergeMethod = "rebase"
+	SquashAutomergeMethod = "squash"
 )
 
 // RepoCfg is the atlantis.yaml config after it's been parsed and validated.
 type RepoCfg struct {
 	// Version is the version of the atlantis YAML file.
-	Version                   int
-	Projects                  []Project
-	Workflows                 map[string]Workflow
-	PolicySets                PolicySets
-	Automerge                 *bool
+	Version    int
+	Projects   []Project
+	Workflows  map[string]Workflow
+	PolicySets PolicySets
+	Automerge  *bool
+	// AutomergeMethod is the merge method of automerge. If it's empty, the
+	// VCS host picks the method.
+	AutomergeMethod           string
 	AutoDiscover              *AutoDiscover
+	Autoplan                  *RepoAutoplan
 	ParallelApply             *bool
 	ParallelPlan              *bool
 	ParallelPolicyCheck       *bool
@@ -33,12 +48,27 @@ type RepoCfg struct {
 	AllowedRegexpPrefixes     []string
 	AbortOnExecutionOrderFail bool
 	SilencePRComments         []string
+	// BranchSkippedProjects are the projects that were removed from Projects
+	// since their branch doesn't match the pull request's base branch.
+	BranchSkippedProjects []Project
+	// Warnings are the deprecated uses of the config found when validating
+	// it, which will fail validation in a future release.
+	Warnings []string
 }
 
+// FindProjectsByDirWorkspace returns all projects in repoRelDir that use
+// workspace. Projects that allow workspace through AllowedWorkspaces are
+// returned with their Workspace set to workspace.
 func (r RepoCfg) FindProjectsByDirWorkspace(repoRelDir string, workspace string) []Project {
 	var ps []Project
 	for _, p := range r.Projects {
-		if p.Dir == repoRelDir && p.Workspace == workspace {
+		if p.Dir != repoRelDir {
+			continue
+		}
+		if p.Workspace == workspace {
+			ps = append(ps, p)
+		} else if slices.Contains(p.AllowedWorkspaces, workspace) {
+			p.Workspace = workspace
 			ps = append(ps, p)
 		}
 	}
@@ -56,6 +86,16 @@ func (r RepoCfg) FindProjectsByDir(dir string) []Project {
 	return ps
 }
 
+// ProjectIndex returns the index of p in the projects of the repo config or
+// -1 if it's not one of them.
+func (r RepoCfg) ProjectIndex(p Project) int {
+	return slices.IndexFunc(r.Projects, func(c Project) bool {
+		return c.Dir == p.Dir &&
+			c.GetName() == p.GetName() &&
+			(c.Workspace == p.Workspace || slices.Contains(c.AllowedWorkspaces, p.Workspace))
+	})
+}
+
 func (r RepoCfg) FindProjectByName(name string) *Project {
 	for _, p := range r.Projects {
 		if p.Name != nil && *p.Name == name {
@@ -130,10 +170,11 @@ func (r RepoCfg) ValidateWorkspaceAllowed(repoRelDir string, workspace string) e
 
 	var configuredSpaces []string
 	for _, p := range projects {
-		if p.Workspace == workspace {
+		if p.Workspace == workspace || slices.Contains(p.AllowedWorkspaces, workspace) {
 			return nil
 		}
 		configuredSpaces = append(configuredSpaces, p.Workspace)
+		configuredSpaces = append(configuredSpaces, p.AllowedWorkspaces...)
 	}
 
 	return fmt.Errorf(
@@ -145,9 +186,15 @@ func (r RepoCfg) ValidateWorkspaceAllowed(repoRelDir string, workspace string) e
 }
 
 type Project struct {
-	Dir                       string
-	BranchRegex               *regexp.Regexp
-	Workspace                 string
+	Dir         string
+	BranchRegex *regexp.Regexp
+	// BranchGlob is a path.Match pattern the base branch must match, it's
+	// set instead of BranchRegex if the branch isn't a /regex/.
+	BranchGlob string
+	Workspace  string
+	// AllowedWorkspaces are the workspaces other than Workspace that commands
+	// for this project may target.
+	AllowedWorkspaces         []string
 	Name                      *string
 	WorkflowName              *string
 	TerraformDistribution     *string
@@ -164,6 +211,36 @@ type Project struct {
 	PolicyCheck               *bool
 	CustomPolicyCheck         *bool
 	SilencePRComments         []string
+	// NoChangesMessage is shown in comments instead of the generic summary
+	// when the plan has no changes.
+	NoChangesMessage string
+	// Labels are metadata about the project, ex. the team that owns it,
+	// that comment templates can render as {{ .Project.Labels.team }}.
+	Labels map[string]string
+	// HidePrevPlanComments overrides the server's --hide-prev-plan-comments
+	// setting for this project. nil means the server setting is used.
+	HidePrevPlanComments *bool
+	// PlanFilePath is a template for the path of the plan file relative to
+	// the project's dir. If empty, the default plan file name is used.
+	PlanFilePath string
+	// InitUpgrade runs init with -upgrade so providers and modules are
+	// upgraded to the newest versions allowed by their constraints.
+	InitUpgrade bool
+	// PushLockFile pushes the .terraform.lock.hcl updated by init to the pull
+	// request's branch.
+	PushLockFile bool
+	// Automerge overrides the repo's automerge setting for this project. nil
+	// means the repo setting is used.
+	Automerge *bool
+	// DestroyThreshold is the number of resources a plan can destroy before
+	// its comment warns about it. 0 disables the warning.
+	DestroyThreshold int
+	// DestroyThresholdApproval requires the pull request to be approved to
+	// apply a plan that destroys more resources than DestroyThreshold.
+	DestroyThresholdApproval bool
+	// WorkspaceTfvars plans with -var-file <workspace>.tfvars if that file
+	// exists in the project's dir.
+	WorkspaceTfvars bool
 }
 
 // GetName returns the name of the project or an empty string if there is no
@@ -175,11 +252,56 @@ func (p Project) GetName() string {
 	return ""
 }
 
+// MatchesBranch returns true if the project is used for pull requests into
+// the base branch. Projects without a branch match all branches.
+func (p Project) MatchesBranch(branch string) bool {
+	if p.BranchRegex != nil {
+		return p.BranchRegex.MatchString(branch)
+	}
+	if p.BranchGlob != "" {
+		// The pattern was validated so there's no error.
+		ok, _ := path.Match(p.BranchGlob, branch)
+		return ok
+	}
+	return true
+}
+
 type Autoplan struct {
 	WhenModified []string
 	Enabled      bool
 }
 
+// RepoAutoplan is the repo-level autoplan config.
+type RepoAutoplan struct {
+	// Ignore are .dockerignore style patterns, relative to the repo root, for
+	// modified files that don't cause any project to be planned.
+	Ignore []string
+}
+
+// FilterIgnored returns the files in modifiedFiles that aren't matched by the
+// ignore patterns. It's applied before the projects' when_modified patterns
+// are evaluated.
+func (a RepoAutoplan) FilterIgnored(modifiedFiles []string) ([]string, error) {
+	if len(a.Ignore) == 0 {
+		return modifiedFiles, nil
+	}
+	pm, err := patternmatcher.New(a.Ignore)
+	if err != nil {
+		return nil, fmt.Errorf("matching modified files with autoplan ignore patterns: %w", err)
+	}
+	var filtered []string
+	for _, file := range modifiedFiles {
+		ignored, err := pm.MatchesOrParentMatches(file)
+		if err != nil {
+			return nil, fmt.Errorf("matching %q with autoplan ignore patterns: %w", file, err)
+		}
+		if !ignored {
+			filtered = append(filtered, file)
+		}
+	}
+	return filtered, nil
+}
+
 // PostProcessRunOutputOption is an enum of options for post-processing RunCommand output
 type PostProcessRunOutputOption string
 
@@ -188,10 +310,51 @@ const (
 	PostProcessRunOutputHide            = "hide"
 	PostProcessRunOutputStripRefreshing = "strip_refreshing"
 	PostProcessRunOutputFilterRegexKey  = "filter_regex"
+	// PostProcessRunOutputCollapseUnchanged collapses runs of unchanged
+	// attributes and blocks in plan output. Plan steps support it too.
+	PostProcessRunOutputCollapseUnchanged = "collapse_unchanged"
 )
 
 type Stage struct {
 	Steps []Step
+	// Retry is set if the stage should be re-run when it fails with certain
+	// output.
+	Retry *StageRetry
+}
+
+// MaxStageRetryBackoff is the longest a stage waits before it's retried. The
+// project and workspace locks are held while waiting so the doubling backoff
+// is capped at it.
+const MaxStageRetryBackoff = 5 * time.Minute
+
+// StageRetry configures re-running a whole stage when it fails with output
+// matching one of Patterns.
+type StageRetry struct {
+	// MaxAttempts is the total number of times the stage may be run.
+	MaxAttempts int
+	// Backoff is how long to wait before the first retry. It doubles for
+	// every retry after that, up to MaxStageRetryBackoff.
+	Backoff time.Duration
+	// Patterns are matched against the stage's output and error.
+	Patterns []*regexp.Regexp
+}
+
+// ShouldRetry returns whether a stage that failed with output after attempts
+// runs should be run again, and how long to wait before doing so.
+func (r *StageRetry) ShouldRetry(attempts int, output string) (time.Duration, bool) {
+	if r == nil || attempts >= r.MaxAttempts {
+		return 0, false
+	}
+	for _, p := range r.Patterns {
+		if p.MatchString(output) {
+			backoff := r.Backoff * time.Duration(1<<(attempts-1))
+			if backoff > MaxStageRetryBackoff || backoff < 0 {
+				backoff = MaxStageRetryBackoff
+			}
+			return backoff, true
+		}
+	}
+	return 0, false
 }
 
 // CommandShell sets up the shell for command execution
@@ -223,6 +386,46 @@ type Step struct {
 	// FilterRegex is a list of regexes for post-processing a RunCommand output
 	// these will be executed in the received order
 	FilterRegexes []*regexp.Regexp
+	// ArchiveBackend is the object storage backend an archive step uploads
+	// the plan file to, either "s3" or "gcs".
+	ArchiveBackend string
+	// ArchiveBucket is the bucket an archive step uploads the plan file to.
+	ArchiveBucket string
+	// ArchiveKey is the object key template an archive step uploads the plan
+	// file to, ex. "{{ .Repo.FullName }}/{{ .Pull.Num }}.tfplan".
+	ArchiveKey string
+	// ModulePinAllowlist is the list of module source prefixes a
+	// module_pin_check step doesn't require to be pinned.
+	ModulePinAllowlist []string
+	// LockProvidersPlatforms are the platforms a lock_providers step records
+	// provider checksums for, ex. "linux_amd64".
+	LockProvidersPlatforms []string
+	// LockProvidersPush is set if a lock_providers step should push the
+	// updated lock file to the pull request's branch.
+	LockProvidersPush bool
+	// ApprovalTimeout is how long an await_approval step waits for the
+	// approval callback before failing.
+	ApprovalTimeout time.Duration
+	// Targets are the resource addresses a plan step passes as -target
+	// flags, in addition to any -target flags from the comment.
+	Targets []string
+	// SkipRefresh is set if a plan step has refresh: false so Terraform
+	// plans without refreshing the state first.
+	SkipRefresh bool
+	// EnvVarVault is the secret in Vault an env step sets its environment
+	// variable to, if it uses one instead of a value or command.
+	EnvVarVault *VaultSecret
+	// StoreAs is the name a run step stores its output as so later steps
+	// can render it in their commands, ex. {{ .Artifacts.ami_id }}.
+	StoreAs string
+}
+
+// VaultSecret is a key of a secret in Vault that an env step reads.
+type VaultSecret struct {
+	// Path is the Vault API path of the secret, ex. "secret/data/x".
+	Path string
+	// Key is the key in the secret's data whose value is read.
+	Key string
 }
 
 type Workflow struct {
@@ -4,7 +4,9 @@
 package valid_test
 
 import (
+	"regexp"
 	"testing"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation"
 	version "github.com/hashicorp/go-version"
@@ -220,6 +222,20 @@ func TestConfig_FindProjectsByDir(t *testing.T) {
 	}
 }
 
+func TestConfig_ProjectIndex(t *testing.T) {
+	cfg := valid.RepoCfg{
+		Projects: []valid.Project{
+			{Dir: "a", Workspace: "default"},
+			{Dir: "a", Workspace: "default", Name: String("named")},
+			{Dir: "b", Workspace: "default", AllowedWorkspaces: []string{"staging"}},
+		},
+	}
+	Equals(t, 0, cfg.ProjectIndex(valid.Project{Dir: "a", Workspace: "default"}))
+	Equals(t, 1, cfg.ProjectIndex(valid.Project{Dir: "a", Workspace: "default", Name: String("named")}))
+	Equals(t, 2, cfg.ProjectIndex(valid.Project{Dir: "b", Workspace: "staging"}))
+	Equals(t, -1, cfg.ProjectIndex(valid.Project{Dir: "c", Workspace: "default"}))
+}
+
 func TestConfig_AutoDiscoverEnabled(t *testing.T) {
 	cases := []struct {
 		description         string
@@ -328,3 +344,61 @@ func TestConfig_AutoDiscoverEnabled(t *testing.T) {
 		})
 	}
 }
+
+func TestRepoAutoplan_FilterIgnored(t *testing.T) {
+	modifiedFiles := []string{"main.tf", "generated/main.tf", "generated/keep.tf", "modules/generated/vars.tf"}
+	cases := []struct {
+		description string
+		ignore      []string
+		exp         []string
+	}{
+		{
+			description: "no patterns",
+			exp:         modifiedFiles,
+		},
+		{
+			description: "ignore dir at any depth",
+			ignore:      []string{"**/generated/**"},
+			exp:         []string{"main.tf"},
+		},
+		{
+			description: "ignore dir at the repo root",
+			ignore:      []string{"generated"},
+			exp:         []string{"main.tf", "modules/generated/vars.tf"},
+		},
+		{
+			description: "exception",
+			ignore:      []string{"**/generated/**", "!generated/keep.tf"},
+			exp:         []string{"main.tf", "generated/keep.tf"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			act, err := valid.RepoAutoplan{Ignore: c.ignore}.FilterIgnored(modifiedFiles)
+			Ok(t, err)
+			Equals(t, c.exp, act)
+		})
+	}
+}
+
+func TestStageRetry_ShouldRetry(t *testing.T) {
+	r := &valid.StageRetry{
+		MaxAttempts: 10,
+		Backoff:     time.Minute,
+		Patterns:    []*regexp.Regexp{regexp.MustCompile("rate limit")},
+	}
+
+	_, retry := r.ShouldRetry(1, "invalid config")
+	Equals(t, false, retry)
+	_, retry = r.ShouldRetry(10, "rate limit exceeded")
+	Equals(t, false, retry)
+
+	backoff, retry := r.ShouldRetry(1, "rate limit exceeded")
+	Equals(t, true, retry)
+	Equals(t, time.Minute, backoff)
+	backoff, _ = r.ShouldRetry(2, "rate limit exceeded")
+	Equals(t, 2*time.Minute, backoff)
+	// The doubling backoff is capped.
+	backoff, _ = r.ShouldRetry(9, "rate limit exceeded")
+	Equals(t, valid.MaxStageRetryBackoff, backoff)
+}
@@ -26,6 +26,21 @@ func NewMockDatabase(options ...pegomock.Option) *MockDatabase {
 func (mock *MockDatabase) SetFailHandler(fh pegomock.FailHandler) { mock.fail = fh }
 func (mock *MockDatabase) FailHandler() pegomock.FailHandler      { return mock.fail }
 
+func (mock *MockDatabase) AddExternalApproval(pull models.PullRequest, approval models.ExternalApproval) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockDatabase().")
+	}
+	_params := []pegomock.Param{pull, approval}
+	_result := pegomock.GetGenericMockFrom(mock).Invoke("AddExternalApproval", _params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var _ret0 error
+	if len(_result) != 0 {
+		if _result[0] != nil {
+			_ret0 = _result[0].(error)
+		}
+	}
+	return _ret0
+}
+
 func (mock *MockDatabase) CheckCommandLock(cmdName command.Name) (*command.Lock, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockDatabase().")
@@ -298,6 +313,41 @@ type VerifierMockDatabase struct {
 	timeout                time.Duration
 }
 
+func (verifier *VerifierMockDatabase) AddExternalApproval(pull models.PullRequest, approval models.ExternalApproval) *MockDatabase_AddExternalApproval_OngoingVerification {
+	_params := []pegomock.Param{pull, approval}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "AddExternalApproval", _params, verifier.timeout)
+	return &MockDatabase_AddExternalApproval_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockDatabase_AddExternalApproval_OngoingVerification struct {
+	mock              *MockDatabase
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockDatabase_AddExternalApproval_OngoingVerification) GetCapturedArguments() (models.PullRequest, models.ExternalApproval) {
+	pull, approval := c.GetAllCapturedArguments()
+	return pull[len(pull)-1], approval[len(approval)-1]
+}
+
+func (c *MockDatabase_AddExternalApproval_OngoingVerification) GetAllCapturedArguments() (_param0 []models.PullRequest, _param1 []models.ExternalApproval) {
+	_params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(_params) > 0 {
+		if len(_params) > 0 {
+			_param0 = make([]models.PullRequest, len(c.methodInvocations))
+			for u, param := range _params[0] {
+				_param0[u] = param.(models.PullRequest)
+			}
+		}
+		if len(_params) > 1 {
+			_param1 = make([]models.ExternalApproval, len(c.methodInvocations))
+			for u, param := range _params[1] {
+				_param1[u] = param.(models.ExternalApproval)
+			}
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockDatabase) CheckCommandLock(cmdName command.Name) *MockDatabase_CheckCommandLock_OngoingVerification {
 	_params := []pegomock.Param{cmdName}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "CheckCommandLock", _params, verifier.timeout)
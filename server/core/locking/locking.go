@@ -18,7 +18,9 @@ package locking
 
 import (
 	"errors"
+	"path"
 	"regexp"
+	"sort"
 	"time"
 
 	"github.com/runatlantis/atlantis/server/core/db"
@@ -35,9 +37,25 @@ type TryLockResponse struct {
 	LockKey string
 }
 
+const (
+	// WorkspaceLockGranularity locks each workspace of a project's dir
+	// separately, so pull requests can plan different workspaces at the same
+	// time.
+	WorkspaceLockGranularity = "workspace"
+	// ProjectLockGranularity locks all workspaces of a project's dir
+	// together, so a pull request can't plan a workspace while another pull
+	// request holds the lock of any workspace of the dir.
+	ProjectLockGranularity = "project"
+)
+
+// ValidLockGranularities are the granularities accepted by
+// NewClientWithGranularity.
+var ValidLockGranularities = []string{WorkspaceLockGranularity, ProjectLockGranularity}
+
 // Client is used to perform locking actions.
 type Client struct {
-	database db.Database
+	database    db.Database
+	granularity string
 }
 
 //go:generate pegomock generate --package mocks -o mocks/mock_locker.go Locker
@@ -50,10 +68,18 @@ type Locker interface {
 	GetLock(key string) (*models.ProjectLock, error)
 }
 
-// NewClient returns a new locking client.
+// NewClient returns a new locking client that locks each workspace
+// separately.
 func NewClient(database db.Database) *Client {
+	return NewClientWithGranularity(database, WorkspaceLockGranularity)
+}
+
+// NewClientWithGranularity returns a new locking client whose locks have
+// granularity, one of ValidLockGranularities.
+func NewClientWithGranularity(database db.Database, granularity string) *Client {
 	return &Client{
-		database: database,
+		database:    database,
+		granularity: granularity,
 	}
 }
 
@@ -73,9 +99,45 @@ func (c *Client) TryLock(p models.Project, workspace string, pull models.PullReq
 	if err != nil {
 		return TryLockResponse{}, err
 	}
+	if c.granularity == ProjectLockGranularity && (lockAcquired || currLock.Pull.Num == pull.Num) {
+		// Locks are still stored per workspace so switching granularities
+		// doesn't strand existing locks. Another pull request's lock of
+		// another workspace is checked after taking this workspace's lock
+		// so two pull requests racing for different workspaces can't both
+		// get the dir.
+		otherLock, err := c.otherWorkspaceLock(p, workspace, pull)
+		if err != nil || otherLock != nil {
+			if lockAcquired {
+				if _, unlockErr := c.database.Unlock(p, workspace); unlockErr != nil && err == nil {
+					err = unlockErr
+				}
+			}
+			if err != nil {
+				return TryLockResponse{}, err
+			}
+			return TryLockResponse{false, *otherLock, c.key(p, workspace)}, nil
+		}
+	}
 	return TryLockResponse{lockAcquired, currLock, c.key(p, workspace)}, nil
 }
 
+// otherWorkspaceLock returns the lock of another workspace of p's dir held
+// by another pull request than pull, or nil if there isn't one.
+func (c *Client) otherWorkspaceLock(p models.Project, workspace string, pull models.PullRequest) (*models.ProjectLock, error) {
+	locks, err := c.database.List()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(locks, func(i, j int) bool { return locks[i].Workspace < locks[j].Workspace })
+	for _, lock := range locks {
+		if lock.Project.RepoFullName == p.RepoFullName && path.Clean(lock.Project.Path) == path.Clean(p.Path) &&
+			lock.Workspace != workspace && lock.Pull.Num != pull.Num {
+			return &lock, nil
+		}
+	}
+	return nil, nil
+}
+
 // Unlock attempts to unlock a project and workspace. If successful,
 // a pointer to the now deleted lock will be returned. Else, that
 // pointer will be nil. An error will only be returned if there was
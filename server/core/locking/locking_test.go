@@ -57,6 +57,65 @@ func TestTryLock_Success(t *testing.T) {
 	Equals(t, locking.TryLockResponse{LockAcquired: true, CurrLock: currLock, LockKey: "owner/repo/path/workspace"}, r)
 }
 
+func TestTryLock_ProjectGranularity(t *testing.T) {
+	otherPull := models.PullRequest{Num: 2}
+	otherWorkspaceLock := models.ProjectLock{Project: models.NewProject("owner/repo", "path/", ""), Pull: otherPull, Workspace: "other"}
+	cases := []struct {
+		description string
+		locks       []models.ProjectLock
+		expAcquired bool
+		expCurrLock models.ProjectLock
+	}{
+		{
+			description: "no other locks",
+			expAcquired: true,
+		},
+		{
+			description: "other workspace locked by another pull",
+			locks:       []models.ProjectLock{otherWorkspaceLock},
+			expCurrLock: otherWorkspaceLock,
+		},
+		{
+			description: "other workspace locked by the same pull",
+			locks:       []models.ProjectLock{{Project: project, Pull: pull, Workspace: "other"}},
+			expAcquired: true,
+		},
+		{
+			description: "other dir locked by another pull",
+			locks:       []models.ProjectLock{{Project: models.NewProject("owner/repo", "other", ""), Pull: otherPull, Workspace: "other"}},
+			expAcquired: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			database := mocks.NewMockDatabase()
+			When(database.TryLock(Any[models.ProjectLock]())).ThenReturn(true, models.ProjectLock{}, nil)
+			When(database.List()).ThenReturn(c.locks, nil)
+			l := locking.NewClientWithGranularity(database, locking.ProjectLockGranularity)
+			r, err := l.TryLock(project, workspace, pull, user)
+			Ok(t, err)
+			Equals(t, locking.TryLockResponse{LockAcquired: c.expAcquired, CurrLock: c.expCurrLock, LockKey: "owner/repo/path/workspace"}, r)
+			if c.expAcquired {
+				database.VerifyWasCalled(Never()).Unlock(Any[models.Project](), Any[string]())
+			} else {
+				database.VerifyWasCalledOnce().Unlock(project, workspace)
+			}
+		})
+	}
+}
+
+func TestTryLock_WorkspaceGranularityIgnoresOtherWorkspaces(t *testing.T) {
+	RegisterMockTestingT(t)
+	database := mocks.NewMockDatabase()
+	When(database.TryLock(Any[models.ProjectLock]())).ThenReturn(true, models.ProjectLock{}, nil)
+	l := locking.NewClient(database)
+	r, err := l.TryLock(project, workspace, pull, user)
+	Ok(t, err)
+	Assert(t, r.LockAcquired, "exp lock to be acquired")
+	database.VerifyWasCalled(Never()).List()
+}
+
 func TestUnlock_InvalidKey(t *testing.T) {
 	RegisterMockTestingT(t)
 	database := mocks.NewMockDatabase()
@@ -162,7 +162,7 @@ func (r *RedisDB) GetLock(project models.Project, workspace string) (*models.Pro
 func (r *RedisDB) UnlockByPull(repoFullName string, pullNum int) ([]models.ProjectLock, error) {
 	var locks []models.ProjectLock
 
-	iter := r.client.Scan(ctx, 0, fmt.Sprintf("pr/%s*", repoFullName), 0).Iterator()
+	iter := r.client.Scan(ctx, 0, fmt.Sprintf("pr/%s/*", repoFullName), 0).Iterator()
 	for iter.Next(ctx) {
 		var lock models.ProjectLock
 		val, err := r.client.Get(ctx, iter.Val()).Result()
@@ -172,7 +172,9 @@ func (r *RedisDB) UnlockByPull(repoFullName string, pullNum int) ([]models.Proje
 		if err := json.Unmarshal([]byte(val), &lock); err != nil {
 			return locks, errors.Wrap(err, fmt.Sprintf("failed to deserialize lock at key '%s'", iter.Val()))
 		}
-		if lock.Pull.Num == pullNum {
+		// The pattern also matches other repos whose names start with
+		// repoFullName, ex. GitLab subgroups.
+		if lock.Project.RepoFullName == repoFullName && lock.Pull.Num == pullNum {
 			locks = append(locks, lock)
 			if _, err := r.Unlock(lock.Project, lock.Workspace); err != nil {
 				return locks, errors.Wrapf(err, "unlocking repo %s, path %s, workspace %s", lock.Project.RepoFullName, lock.Project.Path, lock.Workspace)
@@ -279,6 +281,30 @@ func (r *RedisDB) UpdateProjectStatus(pull models.PullRequest, workspace string,
 	return nil
 }
 
+// AddExternalApproval adds approval to pull's status. It does nothing if
+// there's no status for pull.
+func (r *RedisDB) AddExternalApproval(pull models.PullRequest, approval models.ExternalApproval) error {
+	key, err := r.pullKey(pull)
+	if err != nil {
+		return err
+	}
+
+	currStatus, err := r.getPull(key)
+	if err != nil {
+		return err
+	}
+	if currStatus == nil {
+		return nil
+	}
+	currStatus.ExternalApprovals = append(currStatus.ExternalApprovals, approval)
+
+	err = r.writePull(key, *currStatus)
+	if err != nil {
+		return errors.Wrap(err, "db transaction failed")
+	}
+	return nil
+}
+
 func (r *RedisDB) GetPullStatus(pull models.PullRequest) (*models.PullStatus, error) {
 	key, err := r.pullKey(pull)
 	if err != nil {
@@ -327,6 +353,9 @@ func (r *RedisDB) UpdatePullWithResults(pull models.PullRequest, newResults []co
 			Pull:     pull,
 			Projects: statuses,
 		}
+		if currStatus != nil {
+			newStatus.CreatedWorkspaces = currStatus.CreatedWorkspaces
+		}
 	} else {
 		// If there's an existing pull at the right commit then we have to
 		// merge our project results with the existing ones. We do a merge
@@ -346,6 +375,9 @@ func (r *RedisDB) UpdatePullWithResults(pull models.PullRequest, newResults []co
 					res.ProjectName == proj.ProjectName {
 
 					proj.Status = res.PlanStatus()
+					if res.Command == command.Plan {
+						proj.PlanStats = res.PlanStats()
+					}
 
 					// Updating only policy sets which are included in results; keeping the rest.
 					if len(proj.PolicyStatus) > 0 {
@@ -373,6 +405,12 @@ func (r *RedisDB) UpdatePullWithResults(pull models.PullRequest, newResults []co
 		}
 	}
 
+	for _, res := range newResults {
+		if ws := res.CreatedWorkspace(); ws != nil {
+			newStatus.AddCreatedWorkspace(*ws)
+		}
+	}
+
 	// Now, we overwrite the key with our new status.
 	err = r.writePull(key, newStatus)
 	if err != nil {
@@ -444,6 +482,7 @@ func (r *RedisDB) projectResultToProject(p command.ProjectResult) models.Project
 		ProjectName:  p.ProjectName,
 		PolicyStatus: p.PolicyStatus(),
 		Status:       p.PlanStatus(),
+		PlanStats:    p.PlanStats(),
 	}
 }
 
@@ -116,6 +116,37 @@ func TestRun_AppliesCorrectProjectPlan(t *testing.T) {
 	Assert(t, os.IsNotExist(err), "planfile should be deleted")
 }
 
+func TestRun_AppliesPlanFilePath(t *testing.T) {
+	tmpDir := t.TempDir()
+	ctx := command.ProjectContext{
+		Log:          logging.NewNoopLogger(t),
+		Workspace:    "default",
+		RepoRelDir:   ".",
+		PlanFilePath: "plans/{{ .Workspace }}.tfplan",
+	}
+	Ok(t, runtime.PreparePlanFile(ctx, tmpDir))
+	planPath := filepath.Join(tmpDir, "plans", "default.tfplan")
+	Ok(t, os.WriteFile(planPath, nil, 0600))
+	Ok(t, runtime.LinkPlanFile(ctx, tmpDir))
+
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	tfDistribution := tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+	o := runtime.ApplyStepRunner{
+		TerraformExecutor:     terraform,
+		DefaultTFDistribution: tfDistribution,
+	}
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+		ThenReturn("output", nil)
+	_, err := o.Run(ctx, nil, tmpDir, map[string]string(nil))
+	Ok(t, err)
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, tmpDir, []string{"apply", "-input=false", fmt.Sprintf("%q", planPath)}, map[string]string(nil), tfDistribution, nil, "default")
+	_, err = os.Stat(planPath)
+	Assert(t, os.IsNotExist(err), "planfile should be deleted")
+	_, err = os.Lstat(filepath.Join(tmpDir, "default.tfplan"))
+	Assert(t, os.IsNotExist(err), "planfile link should be deleted")
+}
+
 func TestApplyStepRunner_TestRun_UsesConfiguredTFVersion(t *testing.T) {
 	tmpDir := t.TempDir()
 	planPath := filepath.Join(tmpDir, "workspace.tfplan")
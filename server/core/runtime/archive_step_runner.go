@@ -0,0 +1,100 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// ArchiveKeyTemplateData is the data that archive step keys and projects'
+// plan_file_path are rendered with, ex. "{{ .Repo.FullName }}/{{ .Pull.Num }}.tfplan".
+type ArchiveKeyTemplateData struct {
+	Repo        models.Repo
+	Pull        models.PullRequest
+	Workspace   string
+	ProjectName string
+	RepoRelDir  string
+}
+
+// ArchiveStepRunner uploads the project's plan file to object storage.
+// Uploads are done with the backend's CLI (aws or gcloud) so credentials are
+// picked up from the ambient environment the same way they are for Terraform.
+type ArchiveStepRunner struct {
+	Exec runtime_models.Exec
+}
+
+// Run uploads the plan file in path to key in bucket on backend.
+func (a *ArchiveStepRunner) Run(ctx command.ProjectContext, backend string, bucket string, key string, path string, envs map[string]string) (string, error) {
+	planFile, err := GetPlanFilePath(ctx, path)
+	if err != nil {
+		return "", fmt.Errorf("archive step: %w", err)
+	}
+	if _, err := os.Stat(planFile); err != nil {
+		return "", fmt.Errorf("archive step: unable to read plan file: %w", err)
+	}
+
+	renderedKey, err := a.renderKey(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	var args []string
+	var dest string
+	switch backend {
+	case "s3":
+		dest = fmt.Sprintf("s3://%s/%s", bucket, renderedKey)
+		args = []string{"aws", "s3", "cp", "--only-show-errors"}
+	case "gcs":
+		dest = fmt.Sprintf("gs://%s/%s", bucket, renderedKey)
+		args = []string{"gcloud", "storage", "cp"}
+	default:
+		return "", fmt.Errorf("archive step: unsupported backend %q", backend)
+	}
+	if _, err := a.Exec.LookPath(args[0]); err != nil {
+		return "", fmt.Errorf("archive step: %s is required to upload to %s: %w", args[0], backend, err)
+	}
+	args = append(args, shellQuote(planFile), shellQuote(dest))
+
+	out, err := a.Exec.CombinedOutput(args, envs, path)
+	if err != nil {
+		return "", fmt.Errorf("archive step: uploading plan to %s: %w: %s", dest, err, strings.TrimSpace(out))
+	}
+	ctx.Log.Info("archived plan to %s", dest)
+	return "", nil
+}
+
+func (a *ArchiveStepRunner) renderKey(ctx command.ProjectContext, key string) (string, error) {
+	tmpl, err := template.New("key").Option("missingkey=error").Parse(key)
+	if err != nil {
+		return "", fmt.Errorf("archive step: parsing key %q: %w", key, err)
+	}
+	var buf strings.Builder
+	err = tmpl.Execute(&buf, ArchiveKeyTemplateData{
+		Repo:        ctx.BaseRepo,
+		Pull:        ctx.Pull,
+		Workspace:   ctx.Workspace,
+		ProjectName: ctx.ProjectName,
+		RepoRelDir:  ctx.RepoRelDir,
+	})
+	if err != nil {
+		return "", fmt.Errorf("archive step: rendering key %q: %w", key, err)
+	}
+	rendered := strings.TrimPrefix(buf.String(), "/")
+	if rendered == "" {
+		return "", fmt.Errorf("archive step: key %q rendered to an empty string", key)
+	}
+	return rendered, nil
+}
+
+// shellQuote quotes s so it is passed as a single argument to sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,105 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	models_mocks "github.com/runatlantis/atlantis/server/core/runtime/models/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestArchiveStepRunner_Run(t *testing.T) {
+	cases := []struct {
+		description string
+		backend     string
+		key         string
+		execErr     error
+		expArgs     []string
+		expErr      string
+	}{
+		{
+			description: "s3",
+			backend:     "s3",
+			key:         "{{ .Repo.FullName }}/{{ .Pull.Num }}.tfplan",
+			expArgs:     []string{"aws", "s3", "cp", "--only-show-errors", "'$PLANFILE'", "'s3://my-plans/owner/repo/2.tfplan'"},
+		},
+		{
+			description: "gcs",
+			backend:     "gcs",
+			key:         "{{ .Workspace }}/{{ .Pull.HeadCommit }}.tfplan",
+			expArgs:     []string{"gcloud", "storage", "cp", "'$PLANFILE'", "'gs://my-plans/default/abc123.tfplan'"},
+		},
+		{
+			description: "unknown template field",
+			backend:     "s3",
+			key:         "{{ .Unknown }}.tfplan",
+			expErr:      "archive step: rendering key \"{{ .Unknown }}.tfplan\": template: key:1:3: executing \"key\" at <.Unknown>: can't evaluate field Unknown in type runtime.ArchiveKeyTemplateData",
+		},
+		{
+			description: "upload fails",
+			backend:     "s3",
+			key:         "plan.tfplan",
+			execErr:     errors.New("exit status 1"),
+			expArgs:     []string{"aws", "s3", "cp", "--only-show-errors", "'$PLANFILE'", "'s3://my-plans/plan.tfplan'"},
+			expErr:      "archive step: uploading plan to s3://my-plans/plan.tfplan: exit status 1: access denied",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpDir := t.TempDir()
+			planFile := filepath.Join(tmpDir, "default.tfplan")
+			Ok(t, os.WriteFile(planFile, nil, 0600))
+
+			mockExec := models_mocks.NewMockExec()
+			When(mockExec.LookPath(Any[string]())).ThenReturn("/usr/bin/cli", nil)
+			When(mockExec.CombinedOutput(Any[[]string](), Any[map[string]string](), Any[string]())).ThenReturn("access denied\n", c.execErr)
+			r := runtime.ArchiveStepRunner{Exec: mockExec}
+
+			ctx := command.ProjectContext{
+				Log:       logging.NewNoopLogger(t),
+				Workspace: "default",
+				BaseRepo:  models.Repo{FullName: "owner/repo"},
+				Pull:      models.PullRequest{Num: 2, HeadCommit: "abc123"},
+			}
+			envs := map[string]string{"test": "var"}
+			_, err := r.Run(ctx, c.backend, "my-plans", c.key, tmpDir, envs)
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+			} else {
+				Ok(t, err)
+			}
+			if c.expArgs != nil {
+				for i, arg := range c.expArgs {
+					if arg == "'$PLANFILE'" {
+						c.expArgs[i] = "'" + planFile + "'"
+					}
+				}
+				mockExec.VerifyWasCalledOnce().CombinedOutput(c.expArgs, envs, tmpDir)
+			}
+		})
+	}
+}
+
+func TestArchiveStepRunner_Run_NoPlanFile(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockExec := models_mocks.NewMockExec()
+	r := runtime.ArchiveStepRunner{Exec: mockExec}
+	ctx := command.ProjectContext{
+		Log:       logging.NewNoopLogger(t),
+		Workspace: "default",
+	}
+	_, err := r.Run(ctx, "s3", "my-plans", "plan.tfplan", t.TempDir(), map[string]string{})
+	ErrContains(t, "archive step: unable to read plan file", err)
+	mockExec.VerifyWasCalled(Never()).CombinedOutput(Any[[]string](), Any[map[string]string](), Any[string]())
+}
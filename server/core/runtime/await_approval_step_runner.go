@@ -0,0 +1,136 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// ErrUnknownApprovalToken is returned when resolving a token that no
+// await_approval step is waiting on.
+var ErrUnknownApprovalToken = errors.New("no step is waiting for approval with this token")
+
+// ApprovalDecision is the result an external system sends back to an
+// await_approval step.
+type ApprovalDecision struct {
+	Approved bool
+	// Reason is shown to the user, ex. the ticket that approved the apply.
+	Reason string
+}
+
+// ApprovalRegistry tracks the await_approval steps waiting on a callback.
+// It's shared between the step runner and the API endpoint receiving the
+// callbacks.
+type ApprovalRegistry struct {
+	mu      sync.Mutex
+	pending map[string]chan ApprovalDecision
+}
+
+// NewApprovalRegistry returns an empty ApprovalRegistry.
+func NewApprovalRegistry() *ApprovalRegistry {
+	return &ApprovalRegistry{
+		pending: make(map[string]chan ApprovalDecision),
+	}
+}
+
+// Resolve sends decision to the step waiting on token.
+func (r *ApprovalRegistry) Resolve(token string, decision ApprovalDecision) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	ch, ok := r.pending[token]
+	if !ok {
+		return ErrUnknownApprovalToken
+	}
+	// Remove the token so each approval can only be used once.
+	delete(r.pending, token)
+	ch <- decision
+	return nil
+}
+
+func (r *ApprovalRegistry) register(token string) chan ApprovalDecision {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	// Buffered so Resolve never blocks on a step that already timed out.
+	ch := make(chan ApprovalDecision, 1)
+	r.pending[token] = ch
+	return ch
+}
+
+func (r *ApprovalRegistry) unregister(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.pending, token)
+}
+
+// AwaitApprovalStepRunner pauses the workflow until an external system
+// approves or rejects it through the approvals API, or the timeout expires.
+type AwaitApprovalStepRunner struct {
+	Registry *ApprovalRegistry
+	Exec     runtime_models.Exec
+	// ApprovalURL is the URL of the approvals API endpoint passed to the
+	// notify command.
+	ApprovalURL string
+}
+
+// Run generates a correlation token, runs notifyCmd (if set) so the external
+// system learns about the token and then waits for the decision.
+func (a *AwaitApprovalStepRunner) Run(ctx command.ProjectContext, notifyCmd string, timeout time.Duration, path string, envs map[string]string) (string, error) {
+	token, err := newApprovalToken()
+	if err != nil {
+		return "", fmt.Errorf("await_approval step: generating token: %w", err)
+	}
+	decisions := a.Registry.register(token)
+	defer a.Registry.unregister(token)
+
+	if notifyCmd != "" {
+		cmdEnvs := map[string]string{
+			"ATLANTIS_APPROVAL_TOKEN": token,
+			"ATLANTIS_APPROVAL_URL":   a.ApprovalURL,
+		}
+		for k, v := range envs {
+			cmdEnvs[k] = v
+		}
+		out, err := a.Exec.CombinedOutput([]string{notifyCmd}, cmdEnvs, path)
+		if err != nil {
+			return "", fmt.Errorf("await_approval step: running %q: %w: %s", notifyCmd, err, strings.TrimSpace(out))
+		}
+	}
+
+	ctx.Log.Info("waiting up to %s for approval with token %q", timeout, token)
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case decision := <-decisions:
+		if !decision.Approved {
+			if decision.Reason != "" {
+				return "", fmt.Errorf("await_approval step: rejected: %s", decision.Reason)
+			}
+			return "", errors.New("await_approval step: rejected")
+		}
+		ctx.Log.Info("approval with token %q granted", token)
+		if decision.Reason != "" {
+			return fmt.Sprintf("Approved: %s", decision.Reason), nil
+		}
+		return "", nil
+	case <-timer.C:
+		return "", fmt.Errorf("await_approval step: timed out after %s waiting for approval", timeout)
+	}
+}
+
+func newApprovalToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
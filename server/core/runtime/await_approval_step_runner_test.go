@@ -0,0 +1,95 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	models_mocks "github.com/runatlantis/atlantis/server/core/runtime/models/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestAwaitApprovalStepRunner_Run(t *testing.T) {
+	cases := []struct {
+		description string
+		decision    *runtime.ApprovalDecision
+		timeout     time.Duration
+		execErr     error
+		expOut      string
+		expErr      string
+	}{
+		{
+			description: "approved",
+			decision:    &runtime.ApprovalDecision{Approved: true, Reason: "CHG-123"},
+			expOut:      "Approved: CHG-123",
+		},
+		{
+			description: "rejected",
+			decision:    &runtime.ApprovalDecision{Approved: false, Reason: "change freeze"},
+			expErr:      "await_approval step: rejected: change freeze",
+		},
+		{
+			description: "timed out",
+			timeout:     50 * time.Millisecond,
+			expErr:      "await_approval step: timed out after 50ms waiting for approval",
+		},
+		{
+			description: "notify command fails",
+			execErr:     errors.New("exit status 1"),
+			expErr:      "await_approval step: running \"./notify.sh\": exit status 1: ticket system unavailable",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			registry := runtime.NewApprovalRegistry()
+			tokens := make(chan string, 1)
+			mockExec := models_mocks.NewMockExec()
+			When(mockExec.CombinedOutput(Any[[]string](), Any[map[string]string](), Any[string]())).Then(func(params []Param) ReturnValues {
+				tokens <- params[1].(map[string]string)["ATLANTIS_APPROVAL_TOKEN"]
+				return ReturnValues{"ticket system unavailable\n", c.execErr}
+			})
+			// Simulate the external system calling back once it's notified.
+			resolved := make(chan error, 1)
+			if c.decision != nil {
+				go func() {
+					resolved <- registry.Resolve(<-tokens, *c.decision)
+				}()
+			}
+			timeout := c.timeout
+			if timeout == 0 {
+				timeout = time.Minute
+			}
+
+			r := runtime.AwaitApprovalStepRunner{
+				Registry:    registry,
+				Exec:        mockExec,
+				ApprovalURL: "https://atlantis/api/approvals",
+			}
+			ctx := command.ProjectContext{Log: logging.NewNoopLogger(t)}
+			out, err := r.Run(ctx, "./notify.sh", timeout, t.TempDir(), map[string]string{"test": "var"})
+			if c.decision != nil {
+				Ok(t, <-resolved)
+			}
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, c.expOut, out)
+		})
+	}
+}
+
+func TestApprovalRegistry_Resolve_UnknownToken(t *testing.T) {
+	registry := runtime.NewApprovalRegistry()
+	err := registry.Resolve("unknown", runtime.ApprovalDecision{Approved: true})
+	Equals(t, runtime.ErrUnknownApprovalToken, err)
+}
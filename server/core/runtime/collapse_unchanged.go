@@ -0,0 +1,183 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// collapseUnchangedMinLines is the fewest lines a run of unchanged attributes
+// and blocks must span to be collapsed. Shorter runs are kept since they're
+// usually the attributes Terraform shows to identify a resource, ex. id.
+const collapseUnchangedMinLines = 3
+
+// heredocStartRegex matches lines starting a heredoc, ex. "policy = <<-EOT".
+var heredocStartRegex = regexp.MustCompile(`<<-?([A-Za-z_][A-Za-z0-9_]*)$`)
+
+// planOutputNode is a line of plan output, a heredoc or a block with the
+// nodes nested in it.
+type planOutputNode struct {
+	// lines is the line, the lines of the heredoc or the opening line of the
+	// block.
+	lines []string
+	// children are the nodes nested in the block.
+	children []*planOutputNode
+	// closing is the closing line of the block. It's empty if the block
+	// isn't closed.
+	closing string
+	block   bool
+	// changed is set if the node or any node nested in it has a change
+	// marker, ex. "~".
+	changed bool
+}
+
+// CollapseUnchangedAttributes replaces runs of unchanged attributes and
+// blocks in the resources of plan output with a marker saying how many were
+// hidden, ex. "# (4 unchanged elements hidden)". Lines outside of resources,
+// like the "Plan: 1 to add, 0 to change, 0 to destroy." summary, are kept.
+func CollapseUnchangedAttributes(output string) string {
+	nodes, _, _ := parsePlanOutputNodes(strings.Split(output, "\n"), 0, false)
+	var lines []string
+	for _, node := range nodes {
+		// Only resources are collapsed, ex. "~ resource "a" "b" {", so text
+		// that happens to contain brackets isn't.
+		if node.block && node.closing != "" && hasChangeMarker(node.lines[0]) {
+			lines = append(lines, node.lines[0])
+			lines = appendCollapsedNodes(lines, node.children)
+			lines = append(lines, node.closing)
+			continue
+		}
+		lines = appendNode(lines, node)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parsePlanOutputNodes parses lines starting at i into nodes. If nested is
+// set it stops after the line closing the block the nodes are nested in and
+// returns it.
+func parsePlanOutputNodes(lines []string, i int, nested bool) (nodes []*planOutputNode, closing string, next int) {
+	for i < len(lines) {
+		line := lines[i]
+		trimmed := strings.TrimSpace(line)
+		if nested && isClosingLine(trimmed) {
+			return nodes, line, i + 1
+		}
+		node := &planOutputNode{lines: []string{line}, changed: hasChangeMarker(line)}
+		i++
+		if match := heredocStartRegex.FindStringSubmatch(trimmed); match != nil {
+			for i < len(lines) {
+				node.lines = append(node.lines, lines[i])
+				node.changed = node.changed || hasChangeMarker(lines[i])
+				i++
+				if strings.TrimSpace(lines[i-1]) == match[1] {
+					break
+				}
+			}
+		} else if isOpeningLine(trimmed) {
+			node.block = true
+			node.children, node.closing, i = parsePlanOutputNodes(lines, i, true)
+			for _, child := range node.children {
+				node.changed = node.changed || child.changed
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, "", i
+}
+
+// appendCollapsedNodes appends the lines of nodes to lines with runs of
+// unchanged nodes collapsed.
+func appendCollapsedNodes(lines []string, nodes []*planOutputNode) []string {
+	for i := 0; i < len(nodes); {
+		node := nodes[i]
+		if !node.unchanged() {
+			if node.changed && node.block && node.closing != "" {
+				lines = append(lines, node.lines[0])
+				lines = appendCollapsedNodes(lines, node.children)
+				lines = append(lines, node.closing)
+			} else {
+				lines = appendNode(lines, node)
+			}
+			i++
+			continue
+		}
+
+		// Blank lines between unchanged nodes are part of the run but not at
+		// its end so the spacing before the next changed node is kept.
+		end := i
+		for end < len(nodes) && (nodes[end].unchanged() || nodes[end].blank()) {
+			end++
+		}
+		for nodes[end-1].blank() {
+			end--
+		}
+		run := nodes[i:end]
+		i = end
+
+		var hidden []string
+		count := 0
+		for _, n := range run {
+			hidden = appendNode(hidden, n)
+			if !n.blank() {
+				count++
+			}
+		}
+		if len(hidden) < collapseUnchangedMinLines {
+			lines = append(lines, hidden...)
+			continue
+		}
+		indent := run[0].lines[0][:len(run[0].lines[0])-len(strings.TrimLeft(run[0].lines[0], " "))]
+		noun := "elements"
+		if count == 1 {
+			noun = "element"
+		}
+		lines = append(lines, fmt.Sprintf("%s# (%d unchanged %s hidden)", indent, count, noun))
+	}
+	return lines
+}
+
+// appendNode appends all the lines of node to lines.
+func appendNode(lines []string, node *planOutputNode) []string {
+	lines = append(lines, node.lines...)
+	for _, child := range node.children {
+		lines = appendNode(lines, child)
+	}
+	if node.closing != "" {
+		lines = append(lines, node.closing)
+	}
+	return lines
+}
+
+func (n *planOutputNode) blank() bool {
+	return !n.block && len(n.lines) == 1 && strings.TrimSpace(n.lines[0]) == ""
+}
+
+// unchanged returns true if the node is an attribute, heredoc or block
+// without changes. Comments, like Terraform's own "# (2 unchanged
+// attributes hidden)", aren't.
+func (n *planOutputNode) unchanged() bool {
+	return !n.changed && !n.blank() && !strings.HasPrefix(strings.TrimSpace(n.lines[0]), "#")
+}
+
+// hasChangeMarker returns true if line starts with one of the markers
+// Terraform prefixes changes with, ex. "+" or "-/+".
+func hasChangeMarker(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	for _, marker := range []string{"-/+", "+/-", "<=", "+", "-", "~"} {
+		if trimmed == marker || strings.HasPrefix(trimmed, marker+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func isOpeningLine(trimmed string) bool {
+	return strings.HasSuffix(trimmed, "{") || strings.HasSuffix(trimmed, "[") || strings.HasSuffix(trimmed, "(")
+}
+
+func isClosingLine(trimmed string) bool {
+	return strings.HasPrefix(trimmed, "}") || strings.HasPrefix(trimmed, "]") || strings.HasPrefix(trimmed, ")")
+}
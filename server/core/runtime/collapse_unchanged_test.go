@@ -0,0 +1,184 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime_test
+
+import (
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestCollapseUnchangedAttributes(t *testing.T) {
+	cases := []struct {
+		description string
+		output      string
+		exp         string
+	}{
+		{
+			description: "replacement with nested blocks",
+			output: `
+Terraform will perform the following actions:
+
+  # aws_instance.web must be replaced
+-/+ resource "aws_instance" "web" {
+        ami                          = "ami-123"
+        associate_public_ip_address  = true
+      ~ id                           = "i-123" -> (known after apply)
+      ~ instance_type                = "t2.micro" -> "t2.small" # forces replacement
+        monitoring                   = false
+        source_dest_check            = true
+        subnet_id                    = "subnet-123"
+        tags                         = {
+            "Name" = "web"
+        }
+
+        root_block_device {
+            delete_on_termination = true
+            volume_size           = 8
+            volume_type           = "gp2"
+        }
+
+      ~ ebs_block_device {
+            device_name = "/dev/sdb"
+            encrypted   = false
+          ~ volume_size = 10 -> 20
+            volume_type = "gp2"
+        }
+        # (3 unchanged attributes hidden)
+    }
+
+Plan: 1 to add, 0 to change, 1 to destroy.
+`,
+			exp: `
+Terraform will perform the following actions:
+
+  # aws_instance.web must be replaced
+-/+ resource "aws_instance" "web" {
+        ami                          = "ami-123"
+        associate_public_ip_address  = true
+      ~ id                           = "i-123" -> (known after apply)
+      ~ instance_type                = "t2.micro" -> "t2.small" # forces replacement
+        # (5 unchanged elements hidden)
+
+      ~ ebs_block_device {
+            device_name = "/dev/sdb"
+            encrypted   = false
+          ~ volume_size = 10 -> 20
+            volume_type = "gp2"
+        }
+        # (3 unchanged attributes hidden)
+    }
+
+Plan: 1 to add, 0 to change, 1 to destroy.
+`,
+		},
+		{
+			description: "unchanged heredoc",
+			output: `
+~ resource "aws_iam_policy" "p" {
+        id     = "p"
+        name   = "p"
+        policy = <<-EOT
+            {
+              "Version": "2012-10-17"
+            }
+        EOT
+      ~ tags   = {
+          + "team" = "infra"
+        }
+    }
+`,
+			exp: `
+~ resource "aws_iam_policy" "p" {
+        # (3 unchanged elements hidden)
+      ~ tags   = {
+          + "team" = "infra"
+        }
+    }
+`,
+		},
+		{
+			description: "short runs are kept",
+			output: `
+~ resource "aws_s3_bucket" "b" {
+        id     = "b"
+      ~ acl    = "private" -> "public-read"
+        bucket = "b"
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`,
+			exp: `
+~ resource "aws_s3_bucket" "b" {
+        id     = "b"
+      ~ acl    = "private" -> "public-read"
+        bucket = "b"
+    }
+
+Plan: 0 to add, 1 to change, 0 to destroy.
+`,
+		},
+		{
+			description: "created resources are kept",
+			output: `
++ resource "null_resource" "a" {
+      + id       = (known after apply)
+      + triggers = {
+          + "a" = "b"
+        }
+    }
+`,
+			exp: `
++ resource "null_resource" "a" {
+      + id       = (known after apply)
+      + triggers = {
+          + "a" = "b"
+        }
+    }
+`,
+		},
+		{
+			description: "text outside of resources is kept",
+			output: `
+Note: this has brackets {
+  a = 1
+  b = 2
+  c = 3
+}
+No changes. Your infrastructure matches the configuration.
+`,
+			exp: `
+Note: this has brackets {
+  a = 1
+  b = 2
+  c = 3
+}
+No changes. Your infrastructure matches the configuration.
+`,
+		},
+		{
+			description: "unclosed resource is kept",
+			output: `
+~ resource "a" "b" {
+        a = 1
+        b = 2
+        c = 3
+Plan: 0 to add, 1 to change, 0 to destroy.
+`,
+			exp: `
+~ resource "a" "b" {
+        a = 1
+        b = 2
+        c = 3
+Plan: 0 to add, 1 to change, 0 to destroy.
+`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			Equals(t, c.exp, runtime.CollapseUnchangedAttributes(c.output))
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// CostEstimateStepRunner runs infracost diff against the JSON of the
+// project's plan. infracost picks up its credentials, ex. INFRACOST_API_KEY,
+// from the ambient environment. The diff is saved for the plan comment
+// instead of being part of the plan's output.
+type CostEstimateStepRunner struct {
+	Exec runtime_models.Exec
+}
+
+// Run saves the cost diff of the plan in path. It does nothing if infracost
+// isn't installed.
+func (c *CostEstimateStepRunner) Run(ctx command.ProjectContext, extraArgs []string, path string, envs map[string]string) (string, error) {
+	if _, err := c.Exec.LookPath("infracost"); err != nil {
+		ctx.Log.Warn("skipping cost_estimate step since infracost isn't installed: %s", err)
+		return "", nil
+	}
+	showFile := filepath.Join(path, ctx.GetShowResultFileName())
+	if _, err := os.Stat(showFile); err != nil {
+		return "", fmt.Errorf("cost_estimate step: unable to read the plan's JSON, a show step must run before it: %w", err)
+	}
+
+	args := []string{"infracost", "diff", "--no-color", "--path", shellQuote(showFile)}
+	for _, arg := range extraArgs {
+		args = append(args, shellQuote(arg))
+	}
+	out, err := c.Exec.CombinedOutput(args, envs, path)
+	if err != nil {
+		return "", fmt.Errorf("cost_estimate step: running infracost: %w: %s", err, strings.TrimSpace(out))
+	}
+	if err := os.WriteFile(costEstimatePath(ctx, path), []byte(ctx.MaskOutput(strings.TrimSpace(out))), 0600); err != nil {
+		return "", fmt.Errorf("cost_estimate step: saving the cost estimate: %w", err)
+	}
+	return "", nil
+}
+
+// costEstimatePath returns the path of the file the cost_estimate step saves
+// the cost diff of the project's plan to given path, the absolute path to the
+// project.
+func costEstimatePath(ctx command.ProjectContext, path string) string {
+	name := strings.TrimSuffix(GetPlanFilename(ctx.Workspace, ctx.ProjectName), ".tfplan")
+	return filepath.Join(path, name+".cost-estimate")
+}
+
+// RemoveCostEstimate removes the cost diff saved by a previous plan of the
+// project so it isn't mistaken for the cost diff of the next plan.
+func RemoveCostEstimate(ctx command.ProjectContext, path string) error {
+	if err := os.Remove(costEstimatePath(ctx, path)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ReadCostEstimate returns the cost diff the cost_estimate step saved for
+// the project's plan. It returns "" if it wasn't saved, ex. since the
+// workflow has no cost_estimate step.
+func ReadCostEstimate(ctx command.ProjectContext, path string) (string, error) {
+	content, err := os.ReadFile(costEstimatePath(ctx, path))
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	return string(content), err
+}
@@ -0,0 +1,91 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	models_mocks "github.com/runatlantis/atlantis/server/core/runtime/models/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestCostEstimateStepRunner_Run(t *testing.T) {
+	cases := []struct {
+		description string
+		lookPathErr error
+		noShowFile  bool
+		execErr     error
+		expErr      string
+		expEstimate string
+	}{
+		{
+			description: "saves the cost diff",
+			expEstimate: "Monthly cost will increase by $10",
+		},
+		{
+			description: "infracost isn't installed",
+			lookPathErr: errors.New("executable file not found in $PATH"),
+		},
+		{
+			description: "no plan JSON",
+			noShowFile:  true,
+			expErr:      "cost_estimate step: unable to read the plan's JSON, a show step must run before it",
+		},
+		{
+			description: "infracost fails",
+			execErr:     errors.New("exit status 1"),
+			expErr:      "cost_estimate step: running infracost: exit status 1: Monthly cost will increase by $10",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpDir := t.TempDir()
+			ctx := command.ProjectContext{
+				Log:        logging.NewNoopLogger(t),
+				Workspace:  "default",
+				RepoRelDir: ".",
+			}
+			showFile := filepath.Join(tmpDir, ctx.GetShowResultFileName())
+			if !c.noShowFile {
+				Ok(t, os.WriteFile(showFile, []byte("{}"), 0600))
+			}
+
+			mockExec := models_mocks.NewMockExec()
+			When(mockExec.LookPath("infracost")).ThenReturn("/usr/bin/infracost", c.lookPathErr)
+			When(mockExec.CombinedOutput(Any[[]string](), Any[map[string]string](), Any[string]())).ThenReturn("Monthly cost will increase by $10\n", c.execErr)
+			r := runtime.CostEstimateStepRunner{Exec: mockExec}
+
+			envs := map[string]string{"test": "var"}
+			out, err := r.Run(ctx, []string{"--show-skipped"}, tmpDir, envs)
+			if c.expErr != "" {
+				ErrContains(t, c.expErr, err)
+			} else {
+				Ok(t, err)
+			}
+			Equals(t, "", out)
+			if c.lookPathErr == nil && !c.noShowFile {
+				mockExec.VerifyWasCalledOnce().CombinedOutput([]string{"infracost", "diff", "--no-color", "--path", "'" + showFile + "'", "'--show-skipped'"}, envs, tmpDir)
+			} else {
+				mockExec.VerifyWasCalled(Never()).CombinedOutput(Any[[]string](), Any[map[string]string](), Any[string]())
+			}
+
+			estimate, err := runtime.ReadCostEstimate(ctx, tmpDir)
+			Ok(t, err)
+			Equals(t, c.expEstimate, estimate)
+
+			Ok(t, runtime.RemoveCostEstimate(ctx, tmpDir))
+			estimate, err = runtime.ReadCostEstimate(ctx, tmpDir)
+			Ok(t, err)
+			Equals(t, "", estimate)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/hashicorp/go-version"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// driftExitCode is the exit code of terraform plan -detailed-exitcode when the
+// plan has changes.
+const driftExitCode = 2
+
+// ErrDriftDetected is returned by the drift step with the plan's output if
+// the plan has changes.
+var ErrDriftDetected = errors.New("drift detected")
+
+// NewDriftStepRunner returns a runner of the drift step, which runs terraform
+// plan with -detailed-exitcode and without saving the plan. It's used for
+// the plan steps of projects when running drift so pending plans and locks
+// aren't touched.
+func NewDriftStepRunner(terraformExecutor TerraformExec, defaultTfDistribution terraform.Distribution, defaultTfVersion *version.Version) Runner {
+	runner := &driftStepRunner{
+		terraformExecutor:     terraformExecutor,
+		defaultTFDistribution: defaultTfDistribution,
+		defaultTFVersion:      defaultTfVersion,
+	}
+	return NewWorkspaceStepRunnerDelegate(terraformExecutor, defaultTfDistribution, defaultTfVersion, runner)
+}
+
+type driftStepRunner struct {
+	terraformExecutor     TerraformExec
+	defaultTFDistribution terraform.Distribution
+	defaultTFVersion      *version.Version
+}
+
+func (d *driftStepRunner) Run(ctx command.ProjectContext, extraArgs []string, path string, envs map[string]string) (string, error) {
+	tfDistribution := d.defaultTFDistribution
+	tfVersion := d.defaultTFVersion
+	if ctx.TerraformDistribution != nil {
+		tfDistribution = terraform.NewDistribution(*ctx.TerraformDistribution)
+	}
+	if ctx.TerraformVersion != nil {
+		tfVersion = ctx.TerraformVersion
+	}
+
+	driftCmd := []string{"plan", "-input=false", "-refresh", "-detailed-exitcode", "-no-color"}
+	driftCmd = append(driftCmd, extraArgs...)
+	driftCmd = append(driftCmd, ctx.EscapedCommentArgs...)
+	// Like plan, env/{workspace}.tfvars is used if it exists.
+	envFile := filepath.Join(path, "env", ctx.Workspace+".tfvars")
+	if _, err := os.Stat(envFile); err == nil {
+		driftCmd = append(driftCmd, "-var-file", envFile)
+	}
+	out, err := d.terraformExecutor.RunCommandWithVersion(ctx, filepath.Clean(path), driftCmd, envs, tfDistribution, tfVersion, ctx.Workspace)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == driftExitCode {
+		return StripRefreshingFromPlanOutput(out, tfVersion), ErrDriftDetected
+	}
+	if err != nil {
+		return out, err
+	}
+	return StripRefreshingFromPlanOutput(out, tfVersion), nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	. "github.com/petergtz/pegomock/v4"
+	pkgerrors "github.com/pkg/errors"
+	tf "github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	tfclientmocks "github.com/runatlantis/atlantis/server/core/terraform/tfclient/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestDriftStepRunner_Run(t *testing.T) {
+	// The terraform client wraps the error of the command it ran.
+	exitErr := func(code string) error {
+		err := exec.Command("sh", "-c", "exit "+code).Run()
+		return pkgerrors.Wrapf(err, "running 'terraform plan' in '/path'")
+	}
+	cases := []struct {
+		description string
+		tfErr       error
+		expErr      error
+	}{
+		{
+			description: "no drift",
+		},
+		{
+			description: "drift",
+			tfErr:       exitErr("2"),
+			expErr:      ErrDriftDetected,
+		},
+		{
+			description: "plan fails",
+			tfErr:       exitErr("1"),
+		},
+		{
+			description: "other error",
+			tfErr:       errors.New("terraform not found"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			terraform := tfclientmocks.NewMockClient()
+			tfVersion, _ := version.NewVersion("1.5.0")
+			tfDistribution := tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+			r := &driftStepRunner{
+				terraformExecutor:     terraform,
+				defaultTFDistribution: tfDistribution,
+				defaultTFVersion:      tfVersion,
+			}
+			tmpDir := t.TempDir()
+			ctx := command.ProjectContext{
+				Log:                logging.NewNoopLogger(t),
+				EscapedCommentArgs: []string{"-target=aws_instance.web"},
+				Workspace:          "default",
+			}
+			When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+				ThenReturn("Plan: 0 to add, 1 to change, 0 to destroy.", c.tfErr)
+
+			output, err := r.Run(ctx, []string{"-lock=false"}, tmpDir, map[string]string(nil))
+			Equals(t, "Plan: 0 to add, 1 to change, 0 to destroy.", output)
+			switch {
+			case c.expErr != nil:
+				Equals(t, c.expErr, err)
+			case c.tfErr != nil:
+				Equals(t, c.tfErr, err)
+			default:
+				Ok(t, err)
+			}
+			// The plan isn't saved.
+			expArgs := []string{"plan", "-input=false", "-refresh", "-detailed-exitcode", "-no-color", "-lock=false", "-target=aws_instance.web"}
+			terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, tmpDir, expArgs, map[string]string(nil), tfDistribution, tfVersion, "default")
+		})
+	}
+}
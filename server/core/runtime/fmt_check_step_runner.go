@@ -0,0 +1,64 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// FmtCheckStepRunner runs terraform fmt -check and fails the command if any
+// file isn't formatted, listing the files before the diff.
+type FmtCheckStepRunner struct {
+	TerraformExecutor     TerraformExec
+	DefaultTFDistribution terraform.Distribution
+	DefaultTFVersion      *version.Version
+}
+
+func (f *FmtCheckStepRunner) Run(ctx command.ProjectContext, extraArgs []string, path string, envs map[string]string) (string, error) {
+	tfDistribution := f.DefaultTFDistribution
+	tfVersion := f.DefaultTFVersion
+	if ctx.TerraformDistribution != nil {
+		tfDistribution = terraform.NewDistribution(*ctx.TerraformDistribution)
+	}
+	if ctx.TerraformVersion != nil {
+		tfVersion = ctx.TerraformVersion
+	}
+
+	fmtCmd := append([]string{"fmt", "-check", "-diff", "-no-color"}, extraArgs...)
+	out, err := f.TerraformExecutor.RunCommandWithVersion(ctx, filepath.Clean(path), fmtCmd, envs, tfDistribution, tfVersion, ctx.Workspace)
+	if err == nil {
+		return "", nil
+	}
+	files := unformattedFiles(out)
+	// Otherwise fmt failed for another reason, ex. a syntax error.
+	if len(files) == 0 {
+		return out, err
+	}
+	var list strings.Builder
+	for _, file := range files {
+		fmt.Fprintf(&list, "  %s\n", filepath.ToSlash(filepath.Join(ctx.RepoRelDir, file)))
+	}
+	return fmt.Sprintf("%s\n%s", list.String(), strings.TrimSpace(out)),
+		fmt.Errorf("%d file(s) aren't formatted, run `%s fmt` to fix them", len(files), tfDistribution.BinName())
+}
+
+// unformattedFiles returns the files listed by terraform fmt -check -diff.
+// Each file's name is printed on its own line right before its diff, which
+// is labelled with the same name.
+func unformattedFiles(out string) []string {
+	var files []string
+	lines := strings.Split(out, "\n")
+	for i := 0; i+1 < len(lines); i++ {
+		if name := strings.TrimSpace(lines[i]); name != "" && strings.HasPrefix(lines[i+1], "--- old/"+name) {
+			files = append(files, name)
+		}
+	}
+	return files
+}
@@ -0,0 +1,106 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime_test
+
+import (
+	"errors"
+	"testing"
+
+	version "github.com/hashicorp/go-version"
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	tf "github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	tfclientmocks "github.com/runatlantis/atlantis/server/core/terraform/tfclient/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestFmtCheckStepRunner_Run(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	tfDistribution := tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+	tfVersion, _ := version.NewVersion("1.5.0")
+	r := runtime.FmtCheckStepRunner{
+		TerraformExecutor:     terraform,
+		DefaultTFDistribution: tfDistribution,
+		DefaultTFVersion:      tfVersion,
+	}
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+		ThenReturn("", nil)
+
+	ctx := command.ProjectContext{
+		Workspace:  "default",
+		RepoRelDir: ".",
+		Log:        logging.NewNoopLogger(t),
+	}
+	output, err := r.Run(ctx, []string{"-recursive"}, "/path", map[string]string(nil))
+	Ok(t, err)
+	Equals(t, "", output)
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, "/path", []string{"fmt", "-check", "-diff", "-no-color", "-recursive"}, map[string]string(nil), tfDistribution, tfVersion, "default")
+}
+
+func TestFmtCheckStepRunner_Run_NotFormatted(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	r := runtime.FmtCheckStepRunner{
+		TerraformExecutor:     terraform,
+		DefaultTFDistribution: tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader()),
+	}
+	fmtOutput := `main.tf
+--- old/main.tf
++++ new/main.tf
+@@ -1,3 +1,3 @@
+ resource "null_resource" "a" {
+-  triggers = { a="b" }
++  triggers = { a = "b" }
+ }
+modules/vpc/vars.tf
+--- old/modules/vpc/vars.tf
++++ new/modules/vpc/vars.tf
+@@ -1 +1 @@
+-variable "cidr" {  }
++variable "cidr" {}
+`
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+		ThenReturn(fmtOutput, errors.New("exit status 3"))
+
+	ctx := command.ProjectContext{
+		Workspace:  "default",
+		RepoRelDir: "project",
+		Log:        logging.NewNoopLogger(t),
+	}
+	output, err := r.Run(ctx, []string{"-recursive"}, "/path/project", map[string]string(nil))
+	ErrEquals(t, "2 file(s) aren't formatted, run `terraform fmt` to fix them", err)
+	Equals(t, "  project/main.tf\n  project/modules/vpc/vars.tf\n\n"+fmtOutput[:len(fmtOutput)-1], output)
+}
+
+func TestFmtCheckStepRunner_Run_UsesConfiguredVersion(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	defaultVersion, _ := version.NewVersion("1.5.0")
+	r := runtime.FmtCheckStepRunner{
+		TerraformExecutor:     terraform,
+		DefaultTFDistribution: tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader()),
+		DefaultTFVersion:      defaultVersion,
+	}
+	// Errors that aren't about formatting are returned as is.
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+		ThenReturn("Error: Invalid character", errors.New("exit status 2"))
+
+	projDistribution := "opentofu"
+	projVersion, _ := version.NewVersion("1.8.0")
+	ctx := command.ProjectContext{
+		Workspace:             "staging",
+		RepoRelDir:            "project",
+		TerraformDistribution: &projDistribution,
+		TerraformVersion:      projVersion,
+		Log:                   logging.NewNoopLogger(t),
+	}
+	output, err := r.Run(ctx, nil, "/path/project/", map[string]string(nil))
+	ErrEquals(t, "exit status 2", err)
+	Equals(t, "Error: Invalid character", output)
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(Eq(ctx), Eq("/path/project"), Eq([]string{"fmt", "-check", "-diff", "-no-color"}), Eq(map[string]string(nil)), NotEq[tf.Distribution](r.DefaultTFDistribution), Eq(projVersion), Eq("staging"))
+}
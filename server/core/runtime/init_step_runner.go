@@ -4,10 +4,12 @@
 package runtime
 
 import (
+	"fmt"
 	"path/filepath"
 
 	version "github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/core/runtime/common"
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
 	"github.com/runatlantis/atlantis/server/core/terraform"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/utils"
@@ -18,10 +20,13 @@ type InitStepRunner struct {
 	TerraformExecutor     TerraformExec
 	DefaultTFDistribution terraform.Distribution
 	DefaultTFVersion      *version.Version
+	// Exec runs the git commands used to push the lock file of the projects
+	// with push_lock_file set.
+	Exec runtime_models.Exec
 }
 
 func (i *InitStepRunner) Run(ctx command.ProjectContext, extraArgs []string, path string, envs map[string]string) (string, error) {
-	lockFileName := ".terraform.lock.hcl"
+	lockFileName := terraformLockFileName
 	terraformLockfilePath := filepath.Join(path, lockFileName)
 	terraformLockFileTracked, err := common.IsFileTracked(path, lockFileName)
 	if err != nil {
@@ -58,8 +63,13 @@ func (i *InitStepRunner) Run(ctx command.ProjectContext, extraArgs []string, pat
 		terraformInitArgs = []string{}
 	}
 
+	// -upgrade also updates a tracked lock file to the upgraded versions, so
+	// the lock file in the pull request is only kept as is when not upgrading.
 	if MustConstraint("< 0.14.0").Check(tfVersion) || !common.FileExists(terraformLockfilePath) {
 		terraformInitArgs = append(terraformInitArgs, "-upgrade")
+	} else if ctx.InitUpgrade && terraformInitVerb[0] == "init" {
+		ctx.Log.Info("init_upgrade is set, running init with -upgrade")
+		terraformInitArgs = append(terraformInitArgs, "-upgrade")
 	}
 
 	finalArgs := common.DeDuplicateExtraArgs(terraformInitArgs, extraArgs)
@@ -72,5 +82,16 @@ func (i *InitStepRunner) Run(ctx command.ProjectContext, extraArgs []string, pat
 	if err != nil {
 		return out, err
 	}
+	if ctx.PushLockFile && terraformInitVerb[0] == "init" && i.Exec != nil && common.FileExists(terraformLockfilePath) {
+		pushOut, pushErr := pushLockFile(ctx, i.Exec, path, "init step")
+		if pushErr != nil {
+			// Pushing is best effort, ex. the token may not be allowed to push
+			// to the branch, so the command continues with the updated lock
+			// file in the working dir.
+			ctx.Log.Warn("failed to push updated %s: %s", lockFileName, pushErr)
+			return fmt.Sprintf("Could not push the updated `%s` to `%s`, commit it yourself or allow Atlantis to push to the branch: %s", lockFileName, ctx.Pull.HeadBranch, pushErr), nil
+		}
+		return pushOut, nil
+	}
 	return "", nil
 }
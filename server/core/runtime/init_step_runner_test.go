@@ -15,10 +15,12 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/runatlantis/atlantis/server/core/runtime"
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
 	tf "github.com/runatlantis/atlantis/server/core/terraform"
 	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
 	tfclientmocks "github.com/runatlantis/atlantis/server/core/terraform/tfclient/mocks"
 	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/logging"
 	. "github.com/runatlantis/atlantis/testing"
 )
@@ -216,6 +218,47 @@ func TestRun_InitOmitsUpgradeFlagIfLockFileTracked(t *testing.T) {
 	terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, repoDir, expectedArgs, map[string]string(nil), tfDistribution, tfVersion, "workspace")
 }
 
+func TestRun_InitUpgradeAddsUpgradeFlagIfLockFileTracked(t *testing.T) {
+	// Initialize the git repo.
+	repoDir := initRepo(t)
+
+	lockFilePath := filepath.Join(repoDir, ".terraform.lock.hcl")
+	err := os.WriteFile(lockFilePath, nil, 0600)
+	Ok(t, err)
+	// commit lock file
+	runCmd(t, repoDir, "git", "add", ".terraform.lock.hcl")
+	runCmd(t, repoDir, "git", "commit", "-m", "add .terraform.lock.hcl")
+
+	logger := logging.NewNoopLogger(t)
+	ctx := command.ProjectContext{
+		Workspace:   "workspace",
+		RepoRelDir:  ".",
+		Log:         logger,
+		InitUpgrade: true,
+	}
+
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	mockDownloader := mocks.NewMockDownloader()
+	tfDistribution := tf.NewDistributionTerraformWithDownloader(mockDownloader)
+	tfVersion, _ := version.NewVersion("0.14.0")
+	iso := runtime.InitStepRunner{
+		TerraformExecutor:     terraform,
+		DefaultTFDistribution: tfDistribution,
+		DefaultTFVersion:      tfVersion,
+	}
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+		ThenReturn("output", nil)
+
+	output, err := iso.Run(ctx, []string{"extra", "args"}, repoDir, map[string]string(nil))
+	Ok(t, err)
+	// When there is no error, should not return init output to PR.
+	Equals(t, "", output)
+
+	expectedArgs := []string{"init", "-input=false", "-upgrade", "extra", "args"}
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, repoDir, expectedArgs, map[string]string(nil), tfDistribution, tfVersion, "workspace")
+}
+
 func TestRun_InitKeepsUpgradeFlagIfLockFileNotPresent(t *testing.T) {
 	tmpDir := t.TempDir()
 
@@ -352,6 +395,55 @@ func TestRun_InitExtraArgsDeDupe(t *testing.T) {
 	}
 }
 
+func TestRun_InitPushLockFile(t *testing.T) {
+	RegisterMockTestingT(t)
+	remoteDir := t.TempDir()
+	runCmd(t, remoteDir, "git", "init", "--bare")
+	repoDir := initRepo(t)
+	runCmd(t, repoDir, "git", "checkout", "-b", "deps")
+	lockFilePath := filepath.Join(repoDir, ".terraform.lock.hcl")
+	Ok(t, os.WriteFile(lockFilePath, []byte("# old\n"), 0600))
+	runCmd(t, repoDir, "git", "add", ".terraform.lock.hcl")
+	runCmd(t, repoDir, "git", "commit", "-m", "add .terraform.lock.hcl")
+	runCmd(t, repoDir, "git", "push", remoteDir, "deps")
+	headCommit := strings.TrimSpace(runCmd(t, repoDir, "git", "rev-parse", "HEAD"))
+
+	terraform := tfclientmocks.NewMockClient()
+	tfVersion, _ := version.NewVersion("1.5.0")
+	// init updates the lock file.
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+		Then(func(_ []Param) ReturnValues {
+			Ok(t, os.WriteFile(lockFilePath, []byte("# upgraded\n"), 0600))
+			return ReturnValues{"output", nil}
+		})
+	iso := runtime.InitStepRunner{
+		TerraformExecutor:     terraform,
+		DefaultTFDistribution: tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader()),
+		DefaultTFVersion:      tfVersion,
+		Exec:                  runtime_models.LocalExec{},
+	}
+	ctx := command.ProjectContext{
+		Workspace:    "default",
+		RepoRelDir:   ".",
+		HeadRepo:     models.Repo{CloneURL: remoteDir, SanitizedCloneURL: remoteDir},
+		Pull:         models.PullRequest{HeadBranch: "deps", HeadCommit: headCommit},
+		Log:          logging.NewNoopLogger(t),
+		PushLockFile: true,
+	}
+
+	output, err := iso.Run(ctx, nil, repoDir, map[string]string(nil))
+	Ok(t, err)
+	Assert(t, strings.HasPrefix(output, "Pushed updated `.terraform.lock.hcl` to `deps` in commit "), "got %q", output)
+	Equals(t, headCommit+"\n", runCmd(t, remoteDir, "git", "rev-parse", "deps^"))
+	Equals(t, "# upgraded\n", runCmd(t, remoteDir, "git", "show", "deps:.terraform.lock.hcl"))
+
+	// A failed push is reported without failing init.
+	ctx.HeadRepo = models.Repo{CloneURL: filepath.Join(remoteDir, "missing"), SanitizedCloneURL: "missing"}
+	output, err = iso.Run(ctx, nil, repoDir, map[string]string(nil))
+	Ok(t, err)
+	Assert(t, strings.HasPrefix(output, "Could not push the updated `.terraform.lock.hcl` to `deps`, commit it yourself or allow Atlantis to push to the branch: init step: running git push: "), "got %q", output)
+}
+
 func TestRun_InitDeletesLockFileIfPresentAndNotTracked(t *testing.T) {
 	// Initialize the git repo.
 	repoDir := initRepo(t)
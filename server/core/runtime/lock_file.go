@@ -0,0 +1,90 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+const terraformLockFileName = ".terraform.lock.hcl"
+
+// pushLockFile commits the lock file of the project in path on top of the pull
+// request's head commit and pushes it to the head branch. The commit is built
+// with git plumbing so the working dir, which may be a merge of the base
+// branch, doesn't change. Nothing is pushed if the lock file is unchanged.
+// Errors start with errPrefix, ex. the name of the step.
+func pushLockFile(ctx command.ProjectContext, exec runtime_models.Exec, path string, errPrefix string) (string, error) {
+	repoDir := path
+	if ctx.RepoRelDir != "." {
+		repoDir = filepath.Clean(strings.TrimSuffix(filepath.Clean(path), filepath.Clean(ctx.RepoRelDir)))
+	}
+	indexDir, err := os.MkdirTemp("", "atlantis-lock-providers")
+	if err != nil {
+		return "", fmt.Errorf("%s: creating index dir: %w", errPrefix, err)
+	}
+	defer os.RemoveAll(indexDir) // nolint: errcheck
+
+	gitEnvs := map[string]string{
+		"LOCKFILE":            filepath.ToSlash(filepath.Join(ctx.RepoRelDir, terraformLockFileName)),
+		"HEAD_COMMIT":         ctx.Pull.HeadCommit,
+		"GIT_INDEX_FILE":      filepath.Join(indexDir, "index"),
+		"GIT_AUTHOR_NAME":     "atlantis",
+		"GIT_AUTHOR_EMAIL":    "atlantis@runatlantis.io",
+		"GIT_COMMITTER_NAME":  "atlantis",
+		"GIT_COMMITTER_EMAIL": "atlantis@runatlantis.io",
+	}
+	git := func(args ...string) (string, error) {
+		out, err := exec.CombinedOutput(append([]string{"git"}, args...), gitEnvs, repoDir)
+		out = strings.TrimSpace(out)
+		if err != nil {
+			// The clone URL contains credentials.
+			out = strings.ReplaceAll(out, ctx.HeadRepo.CloneURL, ctx.HeadRepo.SanitizedCloneURL)
+			return "", fmt.Errorf("%s: running git %s: %w: %s", errPrefix, args[0], err, out)
+		}
+		return out, nil
+	}
+
+	blob, err := git("hash-object", "-w", `"$LOCKFILE"`)
+	if err != nil {
+		return "", err
+	}
+	// If the lock file doesn't exist at the head commit this fails and the
+	// lock file is added.
+	if existing, err := git("rev-parse", "--verify", "--quiet", `"$HEAD_COMMIT:$LOCKFILE"`); err == nil && existing == blob {
+		ctx.Log.Debug("%s is up to date", terraformLockFileName)
+		return "", nil
+	}
+
+	if _, err := git("read-tree", `"$HEAD_COMMIT"`); err != nil {
+		return "", err
+	}
+	gitEnvs["BLOB"] = blob
+	if _, err := git("update-index", "--add", "--cacheinfo", `"100644,$BLOB,$LOCKFILE"`); err != nil {
+		return "", err
+	}
+	tree, err := git("write-tree")
+	if err != nil {
+		return "", err
+	}
+	gitEnvs["TREE"] = tree
+	gitEnvs["MESSAGE"] = fmt.Sprintf("Update %s for %s", terraformLockFileName, ctx.RepoRelDir)
+	commit, err := git("commit-tree", `"$TREE"`, "-p", `"$HEAD_COMMIT"`, "-m", `"$MESSAGE"`)
+	if err != nil {
+		return "", err
+	}
+	gitEnvs["COMMIT"] = commit
+	gitEnvs["PUSH_URL"] = ctx.HeadRepo.CloneURL
+	gitEnvs["HEAD_BRANCH"] = ctx.Pull.HeadBranch
+	if _, err := git("push", `"$PUSH_URL"`, `"$COMMIT:refs/heads/$HEAD_BRANCH"`); err != nil {
+		return "", err
+	}
+	ctx.Log.Info("pushed updated %s for %s to %s in commit %s", terraformLockFileName, ctx.RepoRelDir, ctx.Pull.HeadBranch, commit)
+	return fmt.Sprintf("Pushed updated `%s` to `%s` in commit %s.", terraformLockFileName, ctx.Pull.HeadBranch, commit), nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"fmt"
+
+	version "github.com/hashicorp/go-version"
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// LockProvidersStepRunner runs `terraform providers lock` to refresh the
+// project's .terraform.lock.hcl for a list of platforms and optionally pushes
+// the updated lock file to the pull request's branch.
+type LockProvidersStepRunner struct {
+	TerraformExecutor     TerraformExec
+	DefaultTFDistribution terraform.Distribution
+	DefaultTFVersion      *version.Version
+	// Exec runs the git commands used to push the lock file.
+	Exec runtime_models.Exec
+}
+
+// Run locks the providers used by the project in path for platforms.
+func (l *LockProvidersStepRunner) Run(ctx command.ProjectContext, platforms []string, push bool, path string, envs map[string]string) (string, error) {
+	tfDistribution := l.DefaultTFDistribution
+	if ctx.TerraformDistribution != nil {
+		tfDistribution = terraform.NewDistribution(*ctx.TerraformDistribution)
+	}
+	tfVersion := l.DefaultTFVersion
+	if ctx.TerraformVersion != nil {
+		tfVersion = ctx.TerraformVersion
+	}
+
+	args := []string{"providers", "lock"}
+	for _, platform := range platforms {
+		args = append(args, fmt.Sprintf("-platform=%s", platform))
+	}
+	out, err := l.TerraformExecutor.RunCommandWithVersion(ctx, path, args, envs, tfDistribution, tfVersion, ctx.Workspace)
+	// Like init, the output is only useful if there was an error.
+	if err != nil {
+		return out, err
+	}
+	if !push {
+		return "", nil
+	}
+	return pushLockFile(ctx, l.Exec, path, "lock_providers step")
+}
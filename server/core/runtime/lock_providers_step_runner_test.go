@@ -0,0 +1,133 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	version "github.com/hashicorp/go-version"
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
+	models_mocks "github.com/runatlantis/atlantis/server/core/runtime/models/mocks"
+	tf "github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	tfclientmocks "github.com/runatlantis/atlantis/server/core/terraform/tfclient/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestLockProvidersStepRunner_Run(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	mockExec := models_mocks.NewMockExec()
+	tfDistribution := tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+	tfVersion, _ := version.NewVersion("1.5.0")
+	r := runtime.LockProvidersStepRunner{
+		TerraformExecutor:     terraform,
+		DefaultTFDistribution: tfDistribution,
+		DefaultTFVersion:      tfVersion,
+		Exec:                  mockExec,
+	}
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+		ThenReturn("output", nil)
+
+	ctx := command.ProjectContext{
+		Workspace:  "default",
+		RepoRelDir: ".",
+		Log:        logging.NewNoopLogger(t),
+	}
+	output, err := r.Run(ctx, []string{"linux_amd64", "darwin_arm64"}, false, "/path", map[string]string(nil))
+	Ok(t, err)
+	Equals(t, "", output)
+
+	expArgs := []string{"providers", "lock", "-platform=linux_amd64", "-platform=darwin_arm64"}
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, "/path", expArgs, map[string]string(nil), tfDistribution, tfVersion, "default")
+	// Nothing is pushed unless push is set.
+	mockExec.VerifyWasCalled(Never()).CombinedOutput(Any[[]string](), Any[map[string]string](), Any[string]())
+}
+
+func TestLockProvidersStepRunner_Run_Error(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	mockExec := models_mocks.NewMockExec()
+	r := runtime.LockProvidersStepRunner{
+		TerraformExecutor:     terraform,
+		DefaultTFDistribution: tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader()),
+		Exec:                  mockExec,
+	}
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+		ThenReturn("could not query provider registry", errors.New("exit status 1"))
+
+	ctx := command.ProjectContext{
+		Workspace:  "default",
+		RepoRelDir: ".",
+		Log:        logging.NewNoopLogger(t),
+	}
+	output, err := r.Run(ctx, []string{"linux_amd64"}, true, "/path", map[string]string(nil))
+	ErrEquals(t, "exit status 1", err)
+	Equals(t, "could not query provider registry", output)
+	mockExec.VerifyWasCalled(Never()).CombinedOutput(Any[[]string](), Any[map[string]string](), Any[string]())
+}
+
+func TestLockProvidersStepRunner_Run_Push(t *testing.T) {
+	RegisterMockTestingT(t)
+	// The pull request's branch lives in a bare repo that the lock file is
+	// pushed to.
+	remoteDir := t.TempDir()
+	runCmd(t, remoteDir, "git", "init", "--bare")
+	repoDir := initRepo(t)
+	runCmd(t, repoDir, "git", "checkout", "-b", "deps")
+	Ok(t, os.MkdirAll(filepath.Join(repoDir, "project"), 0700))
+	Ok(t, os.WriteFile(filepath.Join(repoDir, "project", "main.tf"), []byte("terraform {}\n"), 0600))
+	runCmd(t, repoDir, "git", "add", ".")
+	runCmd(t, repoDir, "git", "commit", "-m", "add project")
+	runCmd(t, repoDir, "git", "push", remoteDir, "deps")
+	headCommit := strings.TrimSpace(runCmd(t, repoDir, "git", "rev-parse", "HEAD"))
+
+	terraform := tfclientmocks.NewMockClient()
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+		ThenReturn("", nil)
+	r := runtime.LockProvidersStepRunner{
+		TerraformExecutor:     terraform,
+		DefaultTFDistribution: tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader()),
+		Exec:                  runtime_models.LocalExec{},
+	}
+	ctx := command.ProjectContext{
+		Workspace:  "default",
+		RepoRelDir: "project",
+		HeadRepo:   models.Repo{CloneURL: remoteDir, SanitizedCloneURL: remoteDir},
+		Pull:       models.PullRequest{HeadBranch: "deps", HeadCommit: headCommit},
+		Log:        logging.NewNoopLogger(t),
+	}
+	projectDir := filepath.Join(repoDir, "project")
+	lockFile := "# This file is maintained automatically by \"terraform init\".\n"
+	Ok(t, os.WriteFile(filepath.Join(projectDir, ".terraform.lock.hcl"), []byte(lockFile), 0600))
+
+	output, err := r.Run(ctx, []string{"linux_amd64"}, true, projectDir, map[string]string(nil))
+	Ok(t, err)
+	Assert(t, strings.HasPrefix(output, "Pushed updated `.terraform.lock.hcl` to `deps` in commit "), "got %q", output)
+
+	// The branch has a new commit on top of the head commit that only adds
+	// the lock file.
+	Equals(t, headCommit+"\n", runCmd(t, remoteDir, "git", "rev-parse", "deps^"))
+	Equals(t, "project/.terraform.lock.hcl\n", runCmd(t, remoteDir, "git", "diff", "--name-only", "deps^", "deps"))
+	Equals(t, lockFile, runCmd(t, remoteDir, "git", "show", "deps:project/.terraform.lock.hcl"))
+	// The working dir isn't changed.
+	Equals(t, headCommit+"\n", runCmd(t, repoDir, "git", "rev-parse", "HEAD"))
+
+	// Running again with the pushed lock file doesn't push anything.
+	ctx.Pull.HeadCommit = strings.TrimSpace(runCmd(t, remoteDir, "git", "rev-parse", "deps"))
+	runCmd(t, repoDir, "git", "fetch", remoteDir, "deps")
+	output, err = r.Run(ctx, []string{"linux_amd64"}, true, projectDir, map[string]string(nil))
+	Ok(t, err)
+	Equals(t, "", output)
+	Equals(t, ctx.Pull.HeadCommit+"\n", runCmd(t, remoteDir, "git", "rev-parse", "deps"))
+}
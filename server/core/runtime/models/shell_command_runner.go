@@ -60,6 +60,7 @@ func NewShellCommandRunner(
 	cmd := exec.Command(shell.Shell, args...) // #nosec
 	cmd.Env = environ
 	cmd.Dir = workingDir
+	setProcessGroup(cmd)
 
 	return &ShellCommandRunner{
 		command:       command,
@@ -123,6 +124,20 @@ func (s *ShellCommandRunner) RunCommandAsync(ctx command.ProjectContext) (chan<-
 			return
 		}
 
+		// Interrupt the command if it's canceled so it can exit gracefully.
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			select {
+			case <-ctx.Canceled:
+				ctx.Log.Info("interrupting '%s %q' in '%s' since it was canceled", s.shell.String(), s.command, s.workingDir)
+				if err := interrupt(s.cmd); err != nil {
+					ctx.Log.Warn("unable to interrupt '%s %q': %s", s.shell.String(), s.command, err)
+				}
+			case <-done:
+			}
+		}()
+
 		// If we get anything on inCh, write it to stdin.
 		// This function will exit when inCh is closed which we do in our defer.
 		go func() {
@@ -148,7 +163,7 @@ func (s *ShellCommandRunner) RunCommandAsync(ctx command.ProjectContext) (chan<-
 				message := scanner.Text()
 				outCh <- Line{Line: message}
 				if s.streamOutput {
-					s.outputHandler.Send(ctx, message, false)
+					s.outputHandler.Send(ctx, ctx.MaskOutput(message), false)
 				}
 			}
 			wg.Done()
@@ -159,7 +174,7 @@ func (s *ShellCommandRunner) RunCommandAsync(ctx command.ProjectContext) (chan<-
 				message := scanner.Text()
 				outCh <- Line{Line: message}
 				if s.streamOutput {
-					s.outputHandler.Send(ctx, message, false)
+					s.outputHandler.Send(ctx, ctx.MaskOutput(message), false)
 				}
 			}
 			wg.Done()
@@ -176,7 +191,11 @@ func (s *ShellCommandRunner) RunCommandAsync(ctx command.ProjectContext) (chan<-
 		log := ctx.Log.With("duration", dur)
 
 		// We're done now. Send an error if there was one.
-		if err != nil {
+		if err != nil && ctx.IsCanceled() {
+			err = errors.Wrapf(err, "canceled while running '%s' '%s' in '%s'", s.shell.String(), s.command, s.workingDir)
+			log.Warn(err.Error())
+			outCh <- Line{Err: err}
+		} else if err != nil {
 			err = errors.Wrapf(err, "running '%s' '%s' in '%s'", s.shell.String(), s.command, s.workingDir)
 			log.Err(err.Error())
 			outCh <- Line{Err: err}
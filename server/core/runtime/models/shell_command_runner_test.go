@@ -13,6 +13,7 @@ import (
 	"github.com/runatlantis/atlantis/server/core/runtime/models"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/jobs/mocks"
+	"github.com/runatlantis/atlantis/server/logging"
 	logmocks "github.com/runatlantis/atlantis/server/logging/mocks"
 	. "github.com/runatlantis/atlantis/testing"
 )
@@ -81,3 +82,33 @@ func TestShellCommandRunner_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestShellCommandRunner_RunCommandAsync_Canceled(t *testing.T) {
+	canceled := make(chan struct{})
+	ctx := command.ProjectContext{
+		Log:        logging.NewNoopLogger(t),
+		Workspace:  "default",
+		RepoRelDir: ".",
+		Canceled:   canceled,
+	}
+	cwd, err := os.Getwd()
+	Ok(t, err)
+
+	// The command is interrupted, not killed, so its trap still runs.
+	runner := models.NewShellCommandRunner(nil, "trap 'echo interrupted; exit 1' INT; echo started; while true; do sleep 0.1; done", nil, cwd, false, nil)
+	_, outCh := runner.RunCommandAsync(ctx)
+
+	Equals(t, models.Line{Line: "started"}, <-outCh)
+	close(canceled)
+	var lines []string
+	var lineErr error
+	for line := range outCh {
+		if line.Err != nil {
+			lineErr = line.Err
+			break
+		}
+		lines = append(lines, line.Line)
+	}
+	Equals(t, []string{"interrupted"}, lines)
+	ErrContains(t, "canceled while running", lineErr)
+}
@@ -0,0 +1,27 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+//go:build !windows
+
+package models
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// setProcessGroup starts cmd in its own process group so interrupt also
+// reaches the commands started by the shell. Since the group isn't the
+// foreground group of Atlantis' terminal, pressing Ctrl-C or sending SIGTERM
+// to the Atlantis process group doesn't reach cmd anymore. That's what
+// graceful shutdown expects: Atlantis stops accepting commands and waits for
+// the running ones, including their Terraform processes, to complete.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// interrupt sends SIGINT to the process group of cmd. Terraform then stops
+// after the operations in progress and saves the state.
+func interrupt(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGINT)
+}
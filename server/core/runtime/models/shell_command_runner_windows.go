@@ -0,0 +1,16 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package models
+
+import (
+	"os/exec"
+)
+
+func setProcessGroup(_ *exec.Cmd) {}
+
+// interrupt kills cmd since Windows doesn't support sending interrupts to
+// processes.
+func interrupt(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
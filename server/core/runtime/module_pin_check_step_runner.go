@@ -0,0 +1,146 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+var (
+	// registrySourceRegex matches Terraform registry module addresses, ex.
+	// "hashicorp/consul/aws" or "app.terraform.io/acme/vpc/aws".
+	registrySourceRegex = regexp.MustCompile(`^([0-9a-zA-Z.-]+\.[0-9a-zA-Z-]+/)?[0-9A-Za-z_-]+/[0-9A-Za-z_-]+/[0-9A-Za-z_-]+(//.*)?$`)
+	// pinnedVersionRegex matches an exact registry version constraint, ex.
+	// "1.2.3" or "= 1.2.3".
+	pinnedVersionRegex = regexp.MustCompile(`^(=\s*)?v?\d+\.\d+\.\d+([-+][0-9A-Za-z.-]+)?$`)
+	// pinnedRefRegex matches a git or mercurial ref that is a commit hash
+	// or a version tag, ex. "v1.2.3" or "0b9f1c2".
+	pinnedRefRegex = regexp.MustCompile(`^([0-9a-f]{7,40}|v?\d+(\.\d+)*([-+][0-9A-Za-z.-]+)?)$`)
+)
+
+// ModulePinCheckStepRunner fails if any module in the project uses a source
+// that isn't pinned to a version tag or commit.
+type ModulePinCheckStepRunner struct{}
+
+// Run checks the module sources of the Terraform files in path, following
+// local modules. Sources starting with any of the allowlist prefixes are
+// skipped.
+func (m *ModulePinCheckStepRunner) Run(ctx command.ProjectContext, allowlist []string, path string, _ map[string]string) (string, error) {
+	var unpinned []string
+	visited := make(map[string]bool)
+	if err := m.checkDir(path, path, allowlist, visited, &unpinned); err != nil {
+		return "", err
+	}
+	if len(unpinned) > 0 {
+		return "", fmt.Errorf("found %d module(s) with unpinned sources, use a version tag or commit:\n%s",
+			len(unpinned), strings.Join(unpinned, "\n"))
+	}
+	ctx.Log.Debug("all module sources in %q are pinned", path)
+	return "", nil
+}
+
+func (m *ModulePinCheckStepRunner) checkDir(root string, dir string, allowlist []string, visited map[string]bool, unpinned *[]string) error {
+	if visited[dir] {
+		return nil
+	}
+	visited[dir] = true
+
+	mod, diags := tfconfig.LoadModule(dir)
+	if diags.HasErrors() {
+		return fmt.Errorf("loading modules in %q: %w", dir, diags.Err())
+	}
+
+	var names []string
+	for name := range mod.ModuleCalls {
+		names = append(names, name)
+	}
+	// Sort so output is deterministic.
+	sort.Strings(names)
+
+	for _, name := range names {
+		call := mod.ModuleCalls[name]
+		if isLocalModuleSource(call.Source) {
+			if err := m.checkDir(root, filepath.Join(dir, call.Source), allowlist, visited, unpinned); err != nil {
+				return err
+			}
+			continue
+		}
+		if isAllowlistedModuleSource(call.Source, allowlist) {
+			continue
+		}
+		if reason := unpinnedReason(call.Source, call.Version); reason != "" {
+			file, err := filepath.Rel(root, call.Pos.Filename)
+			if err != nil {
+				file = call.Pos.Filename
+			}
+			*unpinned = append(*unpinned, fmt.Sprintf("- module.%s (%s:%d): %q %s",
+				call.Name, file, call.Pos.Line, call.Source, reason))
+		}
+	}
+	return nil
+}
+
+func isLocalModuleSource(source string) bool {
+	return strings.HasPrefix(source, "./") || strings.HasPrefix(source, "../")
+}
+
+func isAllowlistedModuleSource(source string, allowlist []string) bool {
+	for _, prefix := range allowlist {
+		if strings.HasPrefix(source, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// unpinnedReason returns why source is not pinned, or an empty string if it
+// is pinned.
+func unpinnedReason(source string, version string) string {
+	if registrySourceRegex.MatchString(source) {
+		if version == "" {
+			return "has no version"
+		}
+		if !pinnedVersionRegex.MatchString(strings.TrimSpace(version)) {
+			return fmt.Sprintf("version %q is not an exact version", version)
+		}
+		return ""
+	}
+
+	refKey := "ref"
+	switch {
+	case strings.HasPrefix(source, "hg::"):
+		refKey = "rev"
+	case strings.HasPrefix(source, "git::"),
+		strings.HasPrefix(source, "github.com/"),
+		strings.HasPrefix(source, "bitbucket.org/"),
+		strings.HasPrefix(source, "git@"):
+	default:
+		return "is not a git, mercurial or registry source"
+	}
+
+	var query string
+	if i := strings.Index(source, "?"); i >= 0 {
+		query = source[i+1:]
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return fmt.Sprintf("has an invalid query string: %s", err)
+	}
+	ref := values.Get(refKey)
+	if ref == "" {
+		return fmt.Sprintf("has no %q", refKey)
+	}
+	if !pinnedRefRegex.MatchString(ref) {
+		return fmt.Sprintf("%s %q is not a version tag or commit", refKey, ref)
+	}
+	return ""
+}
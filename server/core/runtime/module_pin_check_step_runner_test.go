@@ -0,0 +1,123 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestModulePinCheckStepRunner_Run(t *testing.T) {
+	cases := []struct {
+		description string
+		files       map[string]string
+		allowlist   []string
+		expErr      string
+	}{
+		{
+			description: "pinned modules",
+			files: map[string]string{
+				"main.tf": `
+module "registry" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "5.1.2"
+}
+
+module "git_tag" {
+  source = "git::https://github.com/acme/modules.git//vpc?ref=v1.2.0"
+}
+
+module "git_commit" {
+  source = "github.com/acme/modules?ref=0b9f1c2d"
+}
+
+module "local" {
+  source = "./modules/local"
+}
+`,
+				"modules/local/main.tf": `
+module "nested" {
+  source  = "app.terraform.io/acme/network/aws"
+  version = "= 2.0.0"
+}
+`,
+			},
+		},
+		{
+			description: "unpinned modules",
+			files: map[string]string{
+				"main.tf": `
+module "pinned" {
+  source = "git::https://github.com/acme/modules.git?ref=v1.2.0"
+}
+
+module "branch" {
+  source = "git::https://github.com/acme/modules.git?ref=main"
+}
+
+module "no_ref" {
+  source = "github.com/acme/modules"
+}
+
+module "range" {
+  source  = "terraform-aws-modules/vpc/aws"
+  version = "~> 5.0"
+}
+
+module "local" {
+  source = "./modules/local"
+}
+`,
+				"modules/local/main.tf": `
+module "http" {
+  source = "https://example.com/module.zip"
+}
+`,
+			},
+			expErr: `found 4 module(s) with unpinned sources, use a version tag or commit:
+- module.branch (main.tf:6): "git::https://github.com/acme/modules.git?ref=main" ref "main" is not a version tag or commit
+- module.http (modules/local/main.tf:2): "https://example.com/module.zip" is not a git, mercurial or registry source
+- module.no_ref (main.tf:10): "github.com/acme/modules" has no "ref"
+- module.range (main.tf:14): "terraform-aws-modules/vpc/aws" version "~> 5.0" is not an exact version`,
+		},
+		{
+			description: "allowlisted module",
+			files: map[string]string{
+				"main.tf": `
+module "internal" {
+  source = "git::https://github.com/acme/internal.git?ref=main"
+}
+`,
+			},
+			allowlist: []string{"git::https://github.com/acme/internal"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for name, contents := range c.files {
+				path := filepath.Join(tmpDir, name)
+				Ok(t, os.MkdirAll(filepath.Dir(path), 0700))
+				Ok(t, os.WriteFile(path, []byte(contents), 0600))
+			}
+			r := runtime.ModulePinCheckStepRunner{}
+			ctx := command.ProjectContext{
+				Log: logging.NewNoopLogger(t),
+			}
+			out, err := r.Run(ctx, c.allowlist, tmpDir, map[string]string{})
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, "", out)
+		})
+	}
+}
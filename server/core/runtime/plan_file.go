@@ -0,0 +1,103 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/utils"
+)
+
+// GetPlanFilePath returns the path of the project's plan file given path, the
+// absolute path to the project. By default it's GetPlanFilename in path. If
+// the project sets plan_file_path, the template is rendered with the same data
+// as archive step keys and the result is relative to path. It can't point
+// outside of the repo.
+func GetPlanFilePath(ctx command.ProjectContext, path string) (string, error) {
+	if ctx.PlanFilePath == "" {
+		return filepath.Join(path, GetPlanFilename(ctx.Workspace, ctx.ProjectName)), nil
+	}
+	tmpl, err := template.New("plan_file_path").Option("missingkey=error").Parse(ctx.PlanFilePath)
+	if err != nil {
+		return "", fmt.Errorf("parsing plan_file_path %q: %w", ctx.PlanFilePath, err)
+	}
+	var buf strings.Builder
+	err = tmpl.Execute(&buf, ArchiveKeyTemplateData{
+		Repo:        ctx.BaseRepo,
+		Pull:        ctx.Pull,
+		Workspace:   ctx.Workspace,
+		ProjectName: ctx.ProjectName,
+		RepoRelDir:  ctx.RepoRelDir,
+	})
+	if err != nil {
+		return "", fmt.Errorf("rendering plan_file_path %q: %w", ctx.PlanFilePath, err)
+	}
+	rendered := buf.String()
+	if filepath.IsAbs(rendered) || !filepath.IsLocal(filepath.Join(ctx.RepoRelDir, rendered)) {
+		return "", fmt.Errorf("plan_file_path %q must be a file path relative to the project's dir inside the repo", rendered)
+	}
+	return filepath.Join(path, rendered), nil
+}
+
+// PreparePlanFile creates the directory the project's plan file is written to
+// if the project sets plan_file_path.
+func PreparePlanFile(ctx command.ProjectContext, path string) error {
+	if ctx.PlanFilePath == "" {
+		return nil
+	}
+	planFile, err := GetPlanFilePath(ctx, path)
+	if err != nil {
+		return err
+	}
+	return os.MkdirAll(filepath.Dir(planFile), 0700)
+}
+
+// LinkPlanFile links the default plan file name in path to the plan file if
+// the project sets plan_file_path. Pending plans are found and deleted using
+// the default name so the link lets apply without flags and unlocking work the
+// same way for these projects.
+func LinkPlanFile(ctx command.ProjectContext, path string) error {
+	if ctx.PlanFilePath == "" {
+		return nil
+	}
+	planFile, err := GetPlanFilePath(ctx, path)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(planFile); err != nil {
+		// The plan didn't produce a plan file, ex. a custom run step skipped
+		// it.
+		return nil
+	}
+	defaultPlanFile := filepath.Join(path, GetPlanFilename(ctx.Workspace, ctx.ProjectName))
+	if defaultPlanFile == planFile {
+		return nil
+	}
+	target, err := filepath.Rel(path, planFile)
+	if err != nil {
+		return err
+	}
+	if err := utils.RemoveIgnoreNonExistent(defaultPlanFile); err != nil {
+		return err
+	}
+	return os.Symlink(target, defaultPlanFile)
+}
+
+// RemovePlanFile deletes the project's plan file and its link if the project
+// sets plan_file_path.
+func RemovePlanFile(ctx command.ProjectContext, path string) error {
+	planFile, err := GetPlanFilePath(ctx, path)
+	if err != nil {
+		return err
+	}
+	if err := utils.RemoveIgnoreNonExistent(planFile); err != nil {
+		return err
+	}
+	return utils.RemoveIgnoreNonExistent(filepath.Join(path, GetPlanFilename(ctx.Workspace, ctx.ProjectName)))
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestGetPlanFilePath(t *testing.T) {
+	cases := []struct {
+		description  string
+		planFilePath string
+		repoRelDir   string
+		exp          string
+		expErr       string
+	}{
+		{
+			description: "default",
+			repoRelDir:  "project",
+			exp:         "/repo/project/my-project-staging.tfplan",
+		},
+		{
+			description:  "template",
+			planFilePath: "plans/{{ .Pull.Num }}/{{ .Workspace }}.tfplan",
+			repoRelDir:   "project",
+			exp:          "/repo/project/plans/2/staging.tfplan",
+		},
+		{
+			description:  "outside the project's dir",
+			planFilePath: "../plans/{{ .RepoRelDir }}.tfplan",
+			repoRelDir:   "project",
+			exp:          "/repo/plans/project.tfplan",
+		},
+		{
+			description:  "outside the repo",
+			planFilePath: "../../{{ .Workspace }}.tfplan",
+			repoRelDir:   "project",
+			expErr:       "plan_file_path \"../../staging.tfplan\" must be a file path relative to the project's dir inside the repo",
+		},
+		{
+			description:  "unknown field",
+			planFilePath: "{{ .Unknown }}.tfplan",
+			repoRelDir:   ".",
+			expErr:       "rendering plan_file_path \"{{ .Unknown }}.tfplan\": template: plan_file_path:1:3: executing \"plan_file_path\" at <.Unknown>: can't evaluate field Unknown in type runtime.ArchiveKeyTemplateData",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			ctx := command.ProjectContext{
+				Workspace:    "staging",
+				ProjectName:  "my-project",
+				RepoRelDir:   c.repoRelDir,
+				Pull:         models.PullRequest{Num: 2},
+				PlanFilePath: c.planFilePath,
+			}
+			act, err := runtime.GetPlanFilePath(ctx, filepath.Join("/repo", c.repoRelDir))
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, c.exp, act)
+		})
+	}
+}
+
+func TestLinkPlanFile(t *testing.T) {
+	path := t.TempDir()
+	ctx := command.ProjectContext{
+		Workspace:    "default",
+		RepoRelDir:   ".",
+		PlanFilePath: "plans/{{ .Workspace }}.plan",
+	}
+	Ok(t, runtime.PreparePlanFile(ctx, path))
+	planFile := filepath.Join(path, "plans", "default.plan")
+	Ok(t, os.WriteFile(planFile, []byte("plan"), 0600))
+
+	Ok(t, runtime.LinkPlanFile(ctx, path))
+	target, err := os.Readlink(filepath.Join(path, "default.tfplan"))
+	Ok(t, err)
+	Equals(t, filepath.Join("plans", "default.plan"), target)
+
+	// Linking again after a new plan replaces the link.
+	Ok(t, runtime.LinkPlanFile(ctx, path))
+
+	Ok(t, runtime.RemovePlanFile(ctx, path))
+	_, err = os.Lstat(planFile)
+	Assert(t, os.IsNotExist(err), "plan file should be deleted")
+	_, err = os.Lstat(filepath.Join(path, "default.tfplan"))
+	Assert(t, os.IsNotExist(err), "link should be deleted")
+}
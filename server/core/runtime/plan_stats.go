@@ -0,0 +1,58 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// planStatsPath returns the path of the file the plan step saves the
+// resource changes of the project's plan to given path, the absolute path to
+// the project.
+func planStatsPath(ctx command.ProjectContext, path string) string {
+	name := strings.TrimSuffix(GetPlanFilename(ctx.Workspace, ctx.ProjectName), ".tfplan")
+	return filepath.Join(path, name+".stats.json")
+}
+
+// writePlanStats saves stats as the resource changes of the project's plan.
+func writePlanStats(ctx command.ProjectContext, path string, stats models.PlanSuccessStats) error {
+	content, err := json.Marshal(stats)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(planStatsPath(ctx, path), content, 0600)
+}
+
+// RemovePlanStats removes the resource changes saved by a previous plan of
+// the project so they aren't mistaken for the changes of the next plan.
+func RemovePlanStats(ctx command.ProjectContext, path string) error {
+	if err := os.Remove(planStatsPath(ctx, path)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// ReadPlanStats returns the resource changes the plan step saved for the
+// project's plan. It returns nil if they weren't saved, ex. since
+// --plan-json-stats isn't set.
+func ReadPlanStats(ctx command.ProjectContext, path string) (*models.PlanSuccessStats, error) {
+	content, err := os.ReadFile(planStatsPath(ctx, path))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var stats models.PlanSuccessStats
+	if err := json.Unmarshal(content, &stats); err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
@@ -35,15 +35,19 @@ type planStepRunner struct {
 	DefaultTFVersion      *version.Version
 	CommitStatusUpdater   StatusUpdater
 	AsyncTFExec           AsyncTFExec
+	// JSONStats saves the resource changes of the plan read with
+	// terraform show -json instead of parsing them from the plan's output.
+	JSONStats bool
 }
 
-func NewPlanStepRunner(terraformExecutor TerraformExec, defaultTfDistribution terraform.Distribution, defaultTfVersion *version.Version, commitStatusUpdater StatusUpdater, asyncTFExec AsyncTFExec) Runner {
+func NewPlanStepRunner(terraformExecutor TerraformExec, defaultTfDistribution terraform.Distribution, defaultTfVersion *version.Version, commitStatusUpdater StatusUpdater, asyncTFExec AsyncTFExec, jsonStats bool) Runner {
 	runner := &planStepRunner{
 		TerraformExecutor:     terraformExecutor,
 		DefaultTFDistribution: defaultTfDistribution,
 		DefaultTFVersion:      defaultTfVersion,
 		CommitStatusUpdater:   commitStatusUpdater,
 		AsyncTFExec:           asyncTFExec,
+		JSONStats:             jsonStats,
 	}
 	return NewWorkspaceStepRunnerDelegate(terraformExecutor, defaultTfDistribution, defaultTfVersion, runner)
 }
@@ -58,7 +62,10 @@ func (p *planStepRunner) Run(ctx command.ProjectContext, extraArgs []string, pat
 		tfVersion = ctx.TerraformVersion
 	}
 
-	planFile := filepath.Join(path, GetPlanFilename(ctx.Workspace, ctx.ProjectName))
+	planFile, err := GetPlanFilePath(ctx, path)
+	if err != nil {
+		return "", err
+	}
 	planCmd := p.buildPlanCmd(ctx, extraArgs, path, tfVersion, planFile)
 	output, err := p.TerraformExecutor.RunCommandWithVersion(ctx, filepath.Clean(path), planCmd, envs, tfDistribution, tfVersion, ctx.Workspace)
 	if p.isRemoteOpsErr(output, err) {
@@ -68,9 +75,40 @@ func (p *planStepRunner) Run(ctx command.ProjectContext, extraArgs []string, pat
 	if err != nil {
 		return output, err
 	}
+	if p.JSONStats {
+		p.savePlanStats(ctx, path, planFile, envs, tfDistribution, tfVersion)
+	}
 	return p.fmtPlanOutput(output, tfVersion), nil
 }
 
+// savePlanStats saves the resource changes of the plan in planFile read with
+// terraform show -json. If that fails, nothing is saved so the changes are
+// parsed from the plan's output instead.
+func (p *planStepRunner) savePlanStats(ctx command.ProjectContext, path string, planFile string, envs map[string]string, tfDistribution terraform.Distribution, tfVersion *version.Version) {
+	if tfVersion != nil && tfVersion.LessThan(version.Must(version.NewVersion(minimumShowTfVersion))) {
+		ctx.Log.Debug("parsing resource changes from the plan output since terraform show -json requires version %s+", minimumShowTfVersion)
+		return
+	}
+	output, err := p.TerraformExecutor.RunCommandWithVersion(ctx, filepath.Clean(path), []string{"show", "-json", filepath.Clean(planFile)}, envs, tfDistribution, tfVersion, ctx.Workspace)
+	if err != nil {
+		ctx.Log.Warn("unable to show plan as json, parsing resource changes from the plan output: %s", err)
+		return
+	}
+	stats, err := models.NewPlanSuccessStatsFromJSON([]byte(output))
+	if err != nil {
+		ctx.Log.Warn("%s, parsing resource changes from the plan output", err)
+		return
+	}
+	// Applying a refresh-only plan saves the objects that changed outside of
+	// Terraform in the state, so it must not be skipped as a no-op.
+	if ctx.PlanRefreshOnly && stats.ChangesOutside {
+		stats.NoChanges = false
+	}
+	if err := writePlanStats(ctx, path, stats); err != nil {
+		ctx.Log.Warn("unable to save resource changes of the plan: %s", err)
+	}
+}
+
 // isRemoteOpsErr returns true if there was an error caused due to this
 // project using TFE remote operations.
 func (p *planStepRunner) isRemoteOpsErr(output string, err error) bool {
@@ -85,6 +123,7 @@ func (p *planStepRunner) isRemoteOpsErr(output string, err error) bool {
 func (p *planStepRunner) remotePlan(ctx command.ProjectContext, extraArgs []string, path string, tfDistribution terraform.Distribution, tfVersion *version.Version, planFile string, envs map[string]string) (string, error) {
 	argList := [][]string{
 		{"plan", "-input=false", "-refresh", "-no-color"},
+		p.planModeArgs(ctx),
 		extraArgs,
 		ctx.EscapedCommentArgs,
 	}
@@ -127,11 +166,23 @@ func (p *planStepRunner) buildPlanCmd(ctx command.ProjectContext, extraArgs []st
 		envFileArgs = []string{"-var-file", envFile}
 	}
 
+	// The workspace's var file comes before the extra args so var files
+	// passed explicitly override its values.
+	var workspaceVarFileArgs []string
+	if ctx.WorkspaceTfvars {
+		workspaceVarFile := filepath.Join(path, ctx.Workspace+".tfvars")
+		if _, err := os.Stat(workspaceVarFile); err == nil {
+			workspaceVarFileArgs = []string{"-var-file", workspaceVarFile}
+		}
+	}
+
 	argList := [][]string{
 		// NOTE: we need to quote the plan filename because Bitbucket Server can
 		// have spaces in its repo owner names.
 		{"plan", "-input=false", "-refresh", "-out", fmt.Sprintf("%q", planFile)},
+		p.planModeArgs(ctx),
 		tfVars,
+		workspaceVarFileArgs,
 		extraArgs,
 		ctx.EscapedCommentArgs,
 		envFileArgs,
@@ -140,6 +191,18 @@ func (p *planStepRunner) buildPlanCmd(ctx command.ProjectContext, extraArgs []st
 	return p.flatten(argList)
 }
 
+// planModeArgs returns -destroy or -refresh-only if the comment used plan
+// --destroy or --refresh-only.
+func (p *planStepRunner) planModeArgs(ctx command.ProjectContext) []string {
+	if ctx.PlanDestroy {
+		return []string{"-destroy"}
+	}
+	if ctx.PlanRefreshOnly {
+		return []string{"-refresh-only"}
+	}
+	return nil
+}
+
 // tfVars returns a list of "-var", "key=value" pairs that identify who and which
 // repo this command is running for. This can be used for naming the
 // session name in AWS which will identify in CloudTrail the source of
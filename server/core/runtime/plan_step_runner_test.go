@@ -47,7 +47,7 @@ func TestRun_AddsEnvVarFile(t *testing.T) {
 	// Using version >= 0.10 here so we don't expect any env commands.
 	tfVersion, _ := version.NewVersion("0.10.0")
 	logger := logging.NewNoopLogger(t)
-	s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec)
+	s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec, false)
 
 	expPlanArgs := []string{"plan",
 		"-input=false",
@@ -97,6 +97,71 @@ func TestRun_AddsEnvVarFile(t *testing.T) {
 	Equals(t, "output", output)
 }
 
+func TestRun_WorkspaceTfvars(t *testing.T) {
+	cases := []struct {
+		description     string
+		workspaceTfvars bool
+		createFile      bool
+		expVarFile      bool
+	}{
+		{
+			description:     "file exists",
+			workspaceTfvars: true,
+			createFile:      true,
+			expVarFile:      true,
+		},
+		{
+			description:     "file doesn't exist",
+			workspaceTfvars: true,
+		},
+		{
+			description: "disabled",
+			createFile:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			terraform := tfclientmocks.NewMockClient()
+			When(terraform.RunCommandWithVersion(
+				Any[command.ProjectContext](),
+				Any[string](),
+				Any[[]string](),
+				Any[map[string]string](),
+				Any[tf.Distribution](),
+				Any[*version.Version](),
+				Any[string]())).ThenReturn("output", nil)
+
+			tmpDir := t.TempDir()
+			varFile := filepath.Join(tmpDir, "staging.tfvars")
+			if c.createFile {
+				Ok(t, os.WriteFile(varFile, nil, 0600))
+			}
+
+			tfDistribution := tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+			tfVersion, _ := version.NewVersion("1.0.0")
+			s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, runtimemocks.NewMockStatusUpdater(), runtimemocks.NewMockAsyncTFExec(), false)
+			ctx := command.ProjectContext{
+				Log:             logging.NewNoopLogger(t),
+				Workspace:       "staging",
+				RepoRelDir:      ".",
+				WorkspaceTfvars: c.workspaceTfvars,
+			}
+
+			// Explicit var files come after the workspace's so they override it.
+			_, err := s.Run(ctx, []string{"-var-file", "explicit.tfvars"}, tmpDir, map[string]string(nil))
+			Ok(t, err)
+
+			expPlanArgs := []string{"plan", "-input=false", "-refresh", "-out", fmt.Sprintf("%q", filepath.Join(tmpDir, "staging.tfplan"))}
+			if c.expVarFile {
+				expPlanArgs = append(expPlanArgs, "-var-file", varFile)
+			}
+			expPlanArgs = append(expPlanArgs, "-var-file", "explicit.tfvars")
+			terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, tmpDir, expPlanArgs, map[string]string(nil), tfDistribution, tfVersion, "staging")
+		})
+	}
+}
+
 func TestRun_UsesDiffPathForProject(t *testing.T) {
 	// Test that if running for a project, uses a different path for the plan
 	// file.
@@ -108,7 +173,7 @@ func TestRun_UsesDiffPathForProject(t *testing.T) {
 	tfDistribution := tf.NewDistributionTerraformWithDownloader(mockDownloader)
 	tfVersion, _ := version.NewVersion("0.10.0")
 	logger := logging.NewNoopLogger(t)
-	s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec)
+	s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec, false)
 	ctx := command.ProjectContext{
 		Log:                logger,
 		Workspace:          "default",
@@ -189,7 +254,7 @@ Terraform will perform the following actions:
 	mockDownloader := mocks.NewMockDownloader()
 	tfDistribution := tf.NewDistributionTerraformWithDownloader(mockDownloader)
 	tfVersion, _ := version.NewVersion("0.10.0")
-	s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec)
+	s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec, false)
 	When(terraform.RunCommandWithVersion(
 		Any[command.ProjectContext](),
 		Any[string](),
@@ -242,7 +307,7 @@ func TestRun_OutputOnErr(t *testing.T) {
 	mockDownloader := mocks.NewMockDownloader()
 	tfDistribution := tf.NewDistributionTerraformWithDownloader(mockDownloader)
 	tfVersion, _ := version.NewVersion("0.10.0")
-	s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec)
+	s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec, false)
 	expOutput := "expected output"
 	expErrMsg := "error!"
 	When(terraform.RunCommandWithVersion(
@@ -318,7 +383,7 @@ func TestRun_NoOptionalVarsIn012(t *testing.T) {
 			mockDownloader := mocks.NewMockDownloader()
 			tfDistribution := tf.NewDistributionTerraformWithDownloader(mockDownloader)
 			tfVersion, _ := version.NewVersion(c.tfVersion)
-			s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec)
+			s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec, false)
 			ctx := command.ProjectContext{
 				Workspace:          "default",
 				RepoRelDir:         ".",
@@ -344,6 +409,70 @@ func TestRun_NoOptionalVarsIn012(t *testing.T) {
 
 }
 
+func TestRun_Destroy(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	commitStatusUpdater := runtimemocks.NewMockStatusUpdater()
+	asyncTfExec := runtimemocks.NewMockAsyncTFExec()
+	When(terraform.RunCommandWithVersion(
+		Any[command.ProjectContext](),
+		Any[string](),
+		Any[[]string](),
+		Any[map[string]string](),
+		Any[tf.Distribution](),
+		Any[*version.Version](),
+		Any[string]())).ThenReturn("output", nil)
+
+	tfDistribution := tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+	tfVersion, _ := version.NewVersion("1.0.0")
+	s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec, false)
+	ctx := command.ProjectContext{
+		Workspace:          "default",
+		RepoRelDir:         ".",
+		EscapedCommentArgs: []string{"comment", "args"},
+		PlanDestroy:        true,
+	}
+
+	output, err := s.Run(ctx, []string{"extra", "args"}, "/path", map[string]string(nil))
+	Ok(t, err)
+	Equals(t, "output", output)
+
+	expPlanArgs := []string{"plan", "-input=false", "-refresh", "-out", fmt.Sprintf("%q", "/path/default.tfplan"), "-destroy", "extra", "args", "comment", "args"}
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, "/path", expPlanArgs, map[string]string(nil), tfDistribution, tfVersion, "default")
+}
+
+func TestRun_RefreshOnly(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	commitStatusUpdater := runtimemocks.NewMockStatusUpdater()
+	asyncTfExec := runtimemocks.NewMockAsyncTFExec()
+	When(terraform.RunCommandWithVersion(
+		Any[command.ProjectContext](),
+		Any[string](),
+		Any[[]string](),
+		Any[map[string]string](),
+		Any[tf.Distribution](),
+		Any[*version.Version](),
+		Any[string]())).ThenReturn("output", nil)
+
+	tfDistribution := tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+	tfVersion, _ := version.NewVersion("1.0.0")
+	s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec, false)
+	ctx := command.ProjectContext{
+		Workspace:          "default",
+		RepoRelDir:         ".",
+		EscapedCommentArgs: []string{"comment", "args"},
+		PlanRefreshOnly:    true,
+	}
+
+	output, err := s.Run(ctx, []string{"extra", "args"}, "/path", map[string]string(nil))
+	Ok(t, err)
+	Equals(t, "output", output)
+
+	expPlanArgs := []string{"plan", "-input=false", "-refresh", "-out", fmt.Sprintf("%q", "/path/default.tfplan"), "-refresh-only", "extra", "args", "comment", "args"}
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, "/path", expPlanArgs, map[string]string(nil), tfDistribution, tfVersion, "default")
+}
+
 // Test plans if using remote ops.
 func TestRun_RemoteOps(t *testing.T) {
 	cases := []struct {
@@ -410,7 +539,7 @@ locally at this time.
 			tfDistribution := tf.NewDistributionTerraformWithDownloader(mockDownloader)
 			tfVersion, _ := version.NewVersion(c.tfVersion)
 			asyncTf := &remotePlanMock{}
-			s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTf)
+			s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTf, false)
 			absProjectPath := t.TempDir()
 
 			// First, terraform workspace gets run.
@@ -607,7 +736,7 @@ func TestPlanStepRunner_TestRun_UsesConfiguredDistribution(t *testing.T) {
 			mockDownloader := mocks.NewMockDownloader()
 			tfDistribution := tf.NewDistributionTerraformWithDownloader(mockDownloader)
 			tfVersion, _ := version.NewVersion(c.tfVersion)
-			s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec)
+			s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, commitStatusUpdater, asyncTfExec, false)
 			ctx := command.ProjectContext{
 				Workspace:          "default",
 				RepoRelDir:         ".",
@@ -795,3 +924,81 @@ var remotePlanOutputSensitiveMasked = `Terraform will perform the following acti
         EOT
     }
 Plan: 0 to add, 1 to change, 0 to destroy.`
+
+func TestRun_JSONStats(t *testing.T) {
+	planJSON := `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "null_resource.create", "change": {"actions": ["create"]}},
+    {"address": "null_resource.replace", "change": {"actions": ["delete", "create"]}},
+    {"address": "null_resource.update", "change": {"actions": ["update"]}},
+    {"address": "null_resource.import", "change": {"actions": ["no-op"], "importing": {"id": "a"}}},
+    {"address": "data.null_data_source.read", "change": {"actions": ["read"]}}
+  ]
+}`
+	driftJSON := `{
+  "format_version": "1.2",
+  "resource_drift": [
+    {"address": "null_resource.drifted", "change": {"actions": ["update"]}}
+  ]
+}`
+	cases := []struct {
+		description string
+		refreshOnly bool
+		showOutput  string
+		showErr     error
+		exp         *models.PlanSuccessStats
+	}{
+		{
+			description: "stats are read from json",
+			showOutput:  planJSON,
+			exp:         &models.PlanSuccessStats{Changes: true, Import: 1, Add: 2, Change: 1, Destroy: 1},
+		},
+		{
+			description: "drift only",
+			showOutput:  driftJSON,
+			exp:         &models.PlanSuccessStats{ChangesOutside: true, NoChanges: true},
+		},
+		{
+			description: "refresh-only plan with drift has changes",
+			refreshOnly: true,
+			showOutput:  driftJSON,
+			exp:         &models.PlanSuccessStats{ChangesOutside: true},
+		},
+		{
+			description: "show fails",
+			showErr:     errors.New("error"),
+		},
+		{
+			description: "show output isn't json",
+			showOutput:  "Plan: 1 to add, 0 to change, 0 to destroy.",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			terraform := tfclientmocks.NewMockClient()
+			tfDistribution := tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+			tfVersion, _ := version.NewVersion("1.5.0")
+			s := runtime.NewPlanStepRunner(terraform, tfDistribution, tfVersion, runtimemocks.NewMockStatusUpdater(), runtimemocks.NewMockAsyncTFExec(), true)
+			tmpDir := t.TempDir()
+			ctx := command.ProjectContext{
+				Log:             logging.NewNoopLogger(t),
+				Workspace:       "default",
+				RepoRelDir:      ".",
+				PlanRefreshOnly: c.refreshOnly,
+			}
+			When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+				ThenReturn("Plan: 1 to add, 0 to change, 0 to destroy.", nil)
+			When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Eq(tmpDir), Eq([]string{"show", "-json", filepath.Join(tmpDir, "default.tfplan")}), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Eq("default"))).
+				ThenReturn(c.showOutput, c.showErr)
+
+			_, err := s.Run(ctx, nil, tmpDir, map[string]string(nil))
+			Ok(t, err)
+
+			stats, err := runtime.ReadPlanStats(ctx, tmpDir)
+			Ok(t, err)
+			Equals(t, c.exp, stats)
+		})
+	}
+}
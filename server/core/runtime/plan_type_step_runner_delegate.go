@@ -5,7 +5,6 @@ package runtime
 
 import (
 	"os"
-	"path/filepath"
 
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/events/command"
@@ -35,7 +34,10 @@ func (p *planTypeStepRunnerDelegate) isRemotePlan(planFile string) (bool, error)
 }
 
 func (p *planTypeStepRunnerDelegate) Run(ctx command.ProjectContext, extraArgs []string, path string, envs map[string]string) (string, error) {
-	planFile := filepath.Join(path, GetPlanFilename(ctx.Workspace, ctx.ProjectName))
+	planFile, err := GetPlanFilePath(ctx, path)
+	if err != nil {
+		return "", err
+	}
 	remotePlan, err := p.isRemotePlan(planFile)
 
 	if err != nil {
@@ -158,9 +158,13 @@ type ConfTestExecutorWorkflow struct {
 	VersionCache           cache.ExecutionVersionCache
 	DefaultConftestVersion *version.Version
 	Exec                   runtime_models.Exec
+	// ResultCacheDir is the directory in the Atlantis data dir where the
+	// result of each policy set is cached by the hashes of the plan, the
+	// policy set and the command. Results aren't cached if it's empty.
+	ResultCacheDir string
 }
 
-func NewConfTestExecutorWorkflow(log logging.SimpleLogging, versionRootDir string, conftestDownloder Downloader) *ConfTestExecutorWorkflow {
+func NewConfTestExecutorWorkflow(log logging.SimpleLogging, versionRootDir string, conftestDownloder Downloader, resultCacheDir string) *ConfTestExecutorWorkflow {
 	downloader := ConfTestVersionDownloader{
 		downloader: conftestDownloder,
 	}
@@ -183,7 +187,8 @@ func NewConfTestExecutorWorkflow(log logging.SimpleLogging, versionRootDir strin
 		SourceResolver: &SourceResolverProxy{
 			localSourceResolver: &LocalSourceResolver{},
 		},
-		Exec: runtime_models.LocalExec{},
+		Exec:           runtime_models.LocalExec{},
+		ResultCacheDir: resultCacheDir,
 	}
 }
 
@@ -210,8 +215,7 @@ func (c *ConfTestExecutorWorkflow) Run(ctx command.ProjectContext, executablePat
 			Command:    executablePath,
 		}
 
-		serializedArgs, _ := args.build()
-		cmdOutput, cmdErr := c.Exec.CombinedOutput(serializedArgs, envs, workdir)
+		cmdOutput, cmdErr := c.runPolicySet(ctx, policySet.Name, args, envs, workdir)
 
 		if cmdErr != nil {
 			// Since we're running conftest for each policyset, individual command errors should be concatenated.
@@ -232,9 +236,16 @@ func (c *ConfTestExecutorWorkflow) Run(ctx command.ProjectContext, executablePat
 			PolicyOutput:  cmdOutput,
 			Passed:        passed,
 			ReqApprovals:  policySet.ApproveCount,
+			SoftFail:      policySet.SoftFail,
 		})
 	}
 
+	if c.ResultCacheDir != "" {
+		if err := pruneResultCache(c.ResultCacheDir); err != nil {
+			ctx.Log.Warn("unable to prune policy check cache: %s", err)
+		}
+	}
+
 	if policySetResults == nil {
 		ctx.Log.Warn("no policies have been configured.")
 		return "", nil
@@ -259,6 +270,43 @@ func (c *ConfTestExecutorWorkflow) Run(ctx command.ProjectContext, executablePat
 
 }
 
+// runPolicySet runs conftest with args for the policy set. If results are
+// cached, the cached result is used if the plan and the policy set are
+// unchanged, otherwise the result is cached.
+func (c *ConfTestExecutorWorkflow) runPolicySet(ctx command.ProjectContext, name string, args ConftestTestCommandArgs, envs map[string]string, workdir string) (string, error) {
+	serializedArgs, _ := args.build()
+	if c.ResultCacheDir == "" {
+		return c.Exec.CombinedOutput(serializedArgs, envs, workdir)
+	}
+	key, err := resultCacheKey(args.InputFile, args.PolicyArgs[0].Param, serializedArgs)
+	if err != nil {
+		ctx.Log.Warn("unable to compute policy check cache key for policy set %s: %s", name, err)
+		return c.Exec.CombinedOutput(serializedArgs, envs, workdir)
+	}
+	if entry, ok := readResultCacheEntry(c.ResultCacheDir, key); ok {
+		ctx.Log.Info("plan and policy set %s are unchanged, using cached policy check result", name)
+		if entry.Err != "" {
+			return entry.Output, errors.New(entry.Err)
+		}
+		return entry.Output, nil
+	}
+
+	output, cmdErr := c.Exec.CombinedOutput(serializedArgs, envs, workdir)
+	// Errors that aren't failing policies may be transient so they're not
+	// cached.
+	if cmdErr != nil && !isValidConftestOutput(output) {
+		return output, cmdErr
+	}
+	entry := resultCacheEntry{Output: output}
+	if cmdErr != nil {
+		entry.Err = cmdErr.Error()
+	}
+	if err := writeResultCacheEntry(c.ResultCacheDir, key, entry); err != nil {
+		ctx.Log.Warn("unable to write policy check cache: %s", err)
+	}
+	return output, cmdErr
+}
+
 func (c *ConfTestExecutorWorkflow) sanitizeOutput(inputFile string, output string) string {
 	return strings.ReplaceAll(output, inputFile, "<redacted plan file>")
 }
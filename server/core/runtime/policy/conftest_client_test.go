@@ -6,8 +6,10 @@ package policy
 import (
 	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/go-version"
 	. "github.com/petergtz/pegomock/v4"
@@ -351,3 +353,80 @@ func TestRun(t *testing.T) {
 
 	})
 }
+
+func TestRun_CacheResults(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockResolver := conftest_mocks.NewMockSourceResolver()
+	mockExec := models_mocks.NewMockExec()
+	cacheDir := t.TempDir()
+	subject := &ConfTestExecutorWorkflow{
+		SourceResolver: mockResolver,
+		Exec:           mockExec,
+		ResultCacheDir: cacheDir,
+	}
+
+	workdir := t.TempDir()
+	policyPath := t.TempDir()
+	planFile := filepath.Join(workdir, "testproj-default.json")
+	policyFile := filepath.Join(policyPath, "policy.rego")
+	Ok(t, os.WriteFile(planFile, []byte(`{"resource_changes":[]}`), 0600))
+	Ok(t, os.WriteFile(policyFile, []byte("package main"), 0600))
+
+	policySet := valid.PolicySet{Source: valid.LocalPolicySet, Path: policyPath, Name: "policy1"}
+	ctx := command.ProjectContext{
+		PolicySets:  valid.PolicySets{PolicySets: []valid.PolicySet{policySet}},
+		ProjectName: "testproj",
+		Workspace:   "default",
+		Log:         logging.NewNoopLogger(t),
+	}
+	executablePath := "/usr/bin/conftest"
+	expectedArgs := []string{executablePath, "test", "-p", policyPath, planFile, "--no-color"}
+	failure := "FAIL - " + planFile + " - main - denied\n1 test, 0 passed, 0 warnings, 1 failure, 0 exceptions"
+	expectedResult := `[{"PolicySetName":"policy1","PolicyOutput":"FAIL - <redacted plan file> - main - denied\n1 test, 0 passed, 0 warnings, 1 failure, 0 exceptions","Passed":false,"ReqApprovals":0,"CurApprovals":0}]`
+	When(mockResolver.Resolve(policySet)).ThenReturn(policyPath, nil)
+	When(mockExec.CombinedOutput(expectedArgs, map[string]string(nil), workdir)).ThenReturn(failure, errors.New("exit status 1"))
+
+	run := func() {
+		result, err := subject.Run(ctx, executablePath, nil, workdir, nil)
+		Equals(t, expectedResult, result)
+		ErrEquals(t, "policy_set: policy1: conftest: some policies failed", err)
+	}
+
+	run()
+	run()
+	// The unchanged plan and policy set are evaluated once.
+	mockExec.VerifyWasCalledOnce().CombinedOutput(expectedArgs, map[string]string(nil), workdir)
+	// The result is cached in the cache dir, not in the pull request's clone.
+	cached, err := os.ReadDir(cacheDir)
+	Ok(t, err)
+	Equals(t, 1, len(cached))
+	files, err := os.ReadDir(workdir)
+	Ok(t, err)
+	Equals(t, 2, len(files))
+
+	// Changing the policy set invalidates the cache.
+	Ok(t, os.WriteFile(policyFile, []byte("package main\n\ndeny[msg] { msg := \"denied\" }"), 0600))
+	run()
+	mockExec.VerifyWasCalled(Times(2)).CombinedOutput(expectedArgs, map[string]string(nil), workdir)
+
+	// Changing the plan invalidates the cache.
+	Ok(t, os.WriteFile(planFile, []byte(`{"resource_changes":[{}]}`), 0600))
+	run()
+	mockExec.VerifyWasCalled(Times(3)).CombinedOutput(expectedArgs, map[string]string(nil), workdir)
+}
+
+func TestPruneResultCache(t *testing.T) {
+	dir := t.TempDir()
+	Ok(t, writeResultCacheEntry(dir, "old", resultCacheEntry{Output: "old"}))
+	Ok(t, writeResultCacheEntry(dir, "new", resultCacheEntry{Output: "new"}))
+	old := time.Now().Add(-resultCacheMaxAge - time.Hour)
+	Ok(t, os.Chtimes(filepath.Join(dir, "old.json"), old, old))
+
+	Ok(t, pruneResultCache(dir))
+
+	_, ok := readResultCacheEntry(dir, "old")
+	Assert(t, !ok, "exp old entry to be pruned")
+	entry, ok := readResultCacheEntry(dir, "new")
+	Assert(t, ok, "exp new entry to be kept")
+	Equals(t, "new", entry.Output)
+}
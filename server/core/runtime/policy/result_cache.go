@@ -0,0 +1,105 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package policy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// resultCacheMaxAge is how long a cached result is kept after it was last
+// used.
+const resultCacheMaxAge = 7 * 24 * time.Hour
+
+// resultCacheEntry is the cached result of running conftest for a policy set.
+type resultCacheEntry struct {
+	Output string
+	// Err is the error of running conftest, if it failed.
+	Err string
+}
+
+// resultCacheKey returns the key of the result of running conftest with args
+// on the plan in inputFile with the policies in policyPath. It changes if the
+// plan, any file of the policy set or the command changes.
+func resultCacheKey(inputFile string, policyPath string, args []string) (string, error) {
+	h := sha256.New()
+	io.WriteString(h, strings.Join(args, "\x00")) // nolint: errcheck
+	if err := hashFile(h, inputFile); err != nil {
+		return "", err
+	}
+	err := filepath.WalkDir(policyPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		io.WriteString(h, "\x00"+path+"\x00") // nolint: errcheck
+		return hashFile(h, path)
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// readResultCacheEntry returns the entry with key in the cache in dir. A
+// missing or invalid entry isn't found. Reading an entry marks it as used so
+// it isn't pruned.
+func readResultCacheEntry(dir string, key string) (resultCacheEntry, bool) {
+	var entry resultCacheEntry
+	path := filepath.Join(dir, key+".json")
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(content, &entry); err != nil {
+		return entry, false
+	}
+	now := time.Now()
+	os.Chtimes(path, now, now) // nolint: errcheck
+	return entry, true
+}
+
+// writeResultCacheEntry stores entry with key in the cache in dir.
+func writeResultCacheEntry(dir string, key string, entry resultCacheEntry) error {
+	content, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, key+".json"), content, 0600)
+}
+
+// pruneResultCache removes the entries of the cache in dir that haven't been
+// used for longer than resultCacheMaxAge.
+func pruneResultCache(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || e.IsDir() || time.Since(info.ModTime()) < resultCacheMaxAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
@@ -23,9 +23,41 @@ type DefaultPostWorkflowHookRunner struct {
 	OutputHandler jobs.ProjectCommandOutputHandler
 }
 
+// PostWorkflowHookTemplateData is the data that post-workflow hook commands
+// are rendered with, ex. `{{ if not .Success }}./alert.sh {{ .CommandName }}{{ end }}`.
+// All fields are always set.
+type PostWorkflowHookTemplateData struct {
+	// CommandName is the command the hook runs after, ex. "plan" or "apply".
+	CommandName string
+	// CommentArgs are the shell-escaped extra arguments from the comment. It's
+	// never nil so templates can safely range over it.
+	CommentArgs CommentArgs
+	// Success is false if the command had any errors.
+	Success bool
+}
+
+// renderPostWorkflowHookCommand renders command as a template with the data
+// from ctx, the same way as run steps.
+func renderPostWorkflowHookCommand(ctx models.WorkflowHookCommandContext, command string) (string, error) {
+	data := PostWorkflowHookTemplateData{
+		CommandName: ctx.CommandName,
+		CommentArgs: CommentArgs{},
+		Success:     !ctx.CommandHasErrors,
+	}
+	if ctx.EscapedCommentArgs != nil {
+		data.CommentArgs = CommentArgs(ctx.EscapedCommentArgs)
+	}
+	return renderCommandTemplate("hook", command, data)
+}
+
 func (wh DefaultPostWorkflowHookRunner) Run(ctx models.WorkflowHookCommandContext, command string, shell string, shellArgs string, path string) (string, string, error) {
 	outputFilePath := filepath.Join(path, "OUTPUT_STATUS_FILE")
 
+	command, err := renderPostWorkflowHookCommand(ctx, command)
+	if err != nil {
+		ctx.Log.Debug("error: %s", err)
+		return "", "", err
+	}
 	shellArgsSlice := append(strings.Split(shellArgs, " "), command)
 	cmd := exec.Command(shell, shellArgsSlice...) // #nosec
 	cmd.Dir = path
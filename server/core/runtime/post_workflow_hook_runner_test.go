@@ -206,3 +206,76 @@ func TestPostWorkflowHookRunner_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestPostWorkflowHookRunner_Run_Template(t *testing.T) {
+	cases := []struct {
+		description string
+		command     string
+		hasErrors   bool
+		commentArgs []string
+		expOut      string
+		expErr      string
+	}{
+		{
+			description: "command name and success",
+			command:     "echo {{ .CommandName }} success={{ .Success }}",
+			expOut:      "apply success=true\n",
+		},
+		{
+			description: "branch on failure",
+			command:     "{{ if .Success }}echo ok{{ else }}echo {{ .CommandName }} failed{{ end }}",
+			hasErrors:   true,
+			expOut:      "apply failed\n",
+		},
+		{
+			description: "comment args",
+			command:     "echo args={{ .CommentArgs }}",
+			commentArgs: []string{"\\-\\-\\a\\u\\t\\o"},
+			expOut:      "args=--auto\n",
+		},
+		{
+			description: "no comment args",
+			command:     "echo count={{ len .CommentArgs }}",
+			expOut:      "count=0\n",
+		},
+		{
+			description: "unknown field",
+			command:     "echo '{{.Unknown}}'",
+			expErr:      `rendering "echo '{{.Unknown}}'" as a template`,
+		},
+		{
+			description: "invalid template",
+			command:     "echo {{ if .Success }}ok",
+			expErr:      `parsing "echo {{ if .Success }}ok" as a template`,
+		},
+		{
+			description: "escaped braces",
+			command:     `echo '{{ "{{" }}.Unknown}}'`,
+			expOut:      "{{.Unknown}}\n",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			projectCmdOutputHandler := jobmocks.NewMockProjectCommandOutputHandler()
+			r := runtime.DefaultPostWorkflowHookRunner{
+				OutputHandler: projectCmdOutputHandler,
+			}
+			ctx := models.WorkflowHookCommandContext{
+				Log:                logging.NewNoopLogger(t),
+				CommandName:        "apply",
+				CommandHasErrors:   c.hasErrors,
+				EscapedCommentArgs: c.commentArgs,
+			}
+			out, _, err := r.Run(ctx, c.command, "sh", "-c", t.TempDir())
+			if c.expErr != "" {
+				ErrContains(t, c.expErr, err)
+				projectCmdOutputHandler.VerifyWasCalled(Never()).SendWorkflowHook(
+					Any[models.WorkflowHookCommandContext](), Any[string](), Any[bool]())
+				return
+			}
+			Ok(t, err)
+			Equals(t, c.expOut, out)
+		})
+	}
+}
@@ -4,6 +4,7 @@
 package runtime
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -14,6 +15,24 @@ import (
 	"github.com/runatlantis/atlantis/server/jobs"
 )
 
+// PreWorkflowHookAbortExitCode is the exit code a pre-workflow hook uses to
+// abort the command it runs before. Its output is commented on the pull
+// request as the reason.
+const PreWorkflowHookAbortExitCode = 99
+
+// PreWorkflowHookAbortError is returned when a pre-workflow hook exits with
+// PreWorkflowHookAbortExitCode.
+type PreWorkflowHookAbortError struct {
+	// Hook is the description of the hook.
+	Hook string
+	// Message is the output of the hook.
+	Message string
+}
+
+func (e *PreWorkflowHookAbortError) Error() string {
+	return fmt.Sprintf("%s aborted the command: %s", e.Hook, e.Message)
+}
+
 //go:generate pegomock generate --package mocks -o mocks/mock_pre_workflows_hook_runner.go PreWorkflowHookRunner
 type PreWorkflowHookRunner interface {
 	Run(ctx models.WorkflowHookCommandContext, command string, shell string, shellArgs string, path string) (string, string, error)
@@ -61,6 +80,14 @@ func (wh DefaultPreWorkflowHookRunner) Run(ctx models.WorkflowHookCommandContext
 	wh.OutputHandler.SendWorkflowHook(ctx, outString, false)
 	wh.OutputHandler.SendWorkflowHook(ctx, "\n", true)
 
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == PreWorkflowHookAbortExitCode {
+		ctx.Log.Info("'%s' in '%s' aborted the command", shell+" "+shellArgs+" "+command, path)
+		return string(out), "", &PreWorkflowHookAbortError{
+			Hook:    ctx.HookDescription,
+			Message: strings.TrimSpace(string(out)),
+		}
+	}
 	if err != nil {
 		err = fmt.Errorf("%s: running %q in %q: \n%s", err, shell+" "+shellArgs+" "+command, path, out)
 		ctx.Log.Debug("error: %s", err)
@@ -4,6 +4,7 @@
 package runtime_test
 
 import (
+	"errors"
 	"fmt"
 	goruntime "runtime"
 	"strings"
@@ -217,3 +218,26 @@ func TestPreWorkflowHookRunner_Run(t *testing.T) {
 		})
 	}
 }
+
+func TestPreWorkflowHookRunner_Run_Abort(t *testing.T) {
+	RegisterMockTestingT(t)
+	r := runtime.DefaultPreWorkflowHookRunner{
+		OutputHandler: jobmocks.NewMockProjectCommandOutputHandler(),
+	}
+	ctx := models.WorkflowHookCommandContext{
+		Log:             logging.NewNoopLogger(t),
+		HookDescription: "Check freeze",
+		CommandName:     "apply",
+	}
+
+	_, _, err := r.Run(ctx, "echo 'Changes are frozen until Monday.'; exit 99", "sh", "-c", t.TempDir())
+	var abortErr *runtime.PreWorkflowHookAbortError
+	Assert(t, errors.As(err, &abortErr), "exp abort error but got %v", err)
+	Equals(t, "Check freeze", abortErr.Hook)
+	Equals(t, "Changes are frozen until Monday.", abortErr.Message)
+
+	// Other exit codes are still errors.
+	_, _, err = r.Run(ctx, "echo 'Changes are frozen until Monday.'; exit 1", "sh", "-c", t.TempDir())
+	ErrContains(t, "exit status 1: running", err)
+	Assert(t, !errors.As(err, &abortErr), "exp error not to be an abort")
+}
@@ -10,6 +10,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
 
 	"github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
@@ -29,6 +30,82 @@ type RunStepRunner struct {
 	ProjectCmdOutputHandler jobs.ProjectCommandOutputHandler
 }
 
+// CommentArgs are the shell-escaped extra arguments from the comment that
+// triggered the command, ex. `atlantis plan -- -var region=us-west-2`.
+// When rendered in a template the args are joined by spaces so they can be
+// passed straight through to a shell command.
+type CommentArgs []string
+
+func (c CommentArgs) String() string {
+	return strings.Join(c, " ")
+}
+
+// RunStepTemplateData is the data that run step commands are rendered with,
+// ex. `terraform plan {{ .CommentArgs }}`.
+type RunStepTemplateData struct {
+	// CommentArgs is never nil so templates can safely range over it.
+	CommentArgs CommentArgs
+	// Workspace is the shell-quoted Terraform workspace the command is
+	// running in. It's never empty, the default workspace is rendered as
+	// "default".
+	Workspace string
+	// Artifacts are the shell-quoted outputs stored by the project's run
+	// steps with store_as since the last plan, by name, ex.
+	// {{ .Artifacts.ami_id }}.
+	Artifacts map[string]string
+}
+
+// ShellQuote quotes s so it's passed to a shell command as a single argument.
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// renderCommandTemplate renders command as a template with data. Commands
+// without "{{" are returned as-is. Literal braces can be written as
+// {{ "{{" }} in commands that are templates.
+func renderCommandTemplate(name string, command string, data any) (string, error) {
+	if !strings.Contains(command, "{{") {
+		return command, nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(command)
+	if err != nil {
+		return "", fmt.Errorf("parsing %q as a template: %w", command, err)
+	}
+	var buf strings.Builder
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("rendering %q as a template: %w", command, err)
+	}
+	return buf.String(), nil
+}
+
+// renderRunCommand renders command as a template with the data from ctx and
+// the artifacts stored in path.
+func renderRunCommand(ctx command.ProjectContext, path string, command string) (string, error) {
+	if !strings.Contains(command, "{{") {
+		return command, nil
+	}
+	workspace := ctx.Workspace
+	if workspace == "" {
+		workspace = defaultWorkspace
+	}
+	data := RunStepTemplateData{
+		CommentArgs: CommentArgs{},
+		Workspace:   ShellQuote(workspace),
+		Artifacts:   map[string]string{},
+	}
+	if ctx.EscapedCommentArgs != nil {
+		data.CommentArgs = CommentArgs(ctx.EscapedCommentArgs)
+	}
+	artifacts, err := readStepArtifacts(ctx, path)
+	if err != nil {
+		ctx.Log.Warn("unable to read the outputs stored by run steps: %s", err)
+	}
+	for name, value := range artifacts {
+		data.Artifacts[name] = ShellQuote(value)
+	}
+	return renderCommandTemplate("run", command, data)
+}
+
 func (r *RunStepRunner) Run(
 	ctx command.ProjectContext,
 	shell *valid.CommandShell,
@@ -55,6 +132,11 @@ func (r *RunStepRunner) Run(
 		return "", err
 	}
 
+	planFile, err := GetPlanFilePath(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
 	baseEnvVars := os.Environ()
 	customEnvVars := map[string]string{
 		"ATLANTIS_TERRAFORM_DISTRIBUTION": tfDistribution.BinName(),
@@ -69,7 +151,9 @@ func (r *RunStepRunner) Run(
 		"HEAD_REPO_NAME":                  ctx.HeadRepo.Name,
 		"HEAD_REPO_OWNER":                 ctx.HeadRepo.Owner,
 		"PATH":                            fmt.Sprintf("%s:%s", os.Getenv("PATH"), r.TerraformBinDir),
-		"PLANFILE":                        filepath.Join(path, GetPlanFilename(ctx.Workspace, ctx.ProjectName)),
+		"PLANFILE":                        planFile,
+		"PLAN_DESTROY":                    strconv.FormatBool(ctx.PlanDestroy),
+		"PLAN_REFRESH_ONLY":               strconv.FormatBool(ctx.PlanRefreshOnly),
 		"SHOWFILE":                        filepath.Join(path, ctx.GetShowResultFileName()),
 		"POLICYCHECKFILE":                 filepath.Join(path, ctx.GetPolicyCheckResultFileName()),
 		"PROJECT_NAME":                    ctx.ProjectName,
@@ -94,6 +178,11 @@ func (r *RunStepRunner) Run(
 		finalEnvVars = append(finalEnvVars, fmt.Sprintf("%s=%s", key, val))
 	}
 
+	command, err = renderRunCommand(ctx, path, command)
+	if err != nil {
+		ctx.Log.Debug("error: %s", err)
+		return "", err
+	}
 	runner := models.NewShellCommandRunner(shell, command, finalEnvVars, path, streamOutput, r.ProjectCmdOutputHandler)
 	output, err := runner.Run(ctx)
 
@@ -102,6 +191,8 @@ func (r *RunStepRunner) Run(
 		switch processOutput {
 		case valid.PostProcessRunOutputStripRefreshing:
 			output = StripRefreshingFromPlanOutput(output, tfVersion)
+		case valid.PostProcessRunOutputCollapseUnchanged:
+			output = CollapseUnchangedAttributes(output)
 		case valid.PostProcessRunOutputFilterRegexKey:
 			for _, filterRegexes := range postProcessFilterRegexes {
 				output = FilterRegexFromPlanOutput(output, filterRegexes)
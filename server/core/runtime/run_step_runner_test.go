@@ -107,6 +107,38 @@ func TestRunStepRunner_Run(t *testing.T) {
 			Command: "echo args=$COMMENT_ARGS",
 			ExpOut:  "args=-target=resource1,-target=resource2\n",
 		},
+		{
+			Command: "echo args={{ .CommentArgs }}",
+			ExpOut:  "args=-target=resource1 -target=resource2\n",
+		},
+		{
+			Command: "{{ range .CommentArgs }}echo {{ . }};{{ end }}",
+			ExpOut:  "-target=resource1\n-target=resource2\n",
+		},
+		{
+			Command: "echo plan_destroy=$PLAN_DESTROY",
+			ExpOut:  "plan_destroy=false\n",
+		},
+		{
+			Command: "echo plan_refresh_only=$PLAN_REFRESH_ONLY",
+			ExpOut:  "plan_refresh_only=false\n",
+		},
+		{
+			Command: "echo workspace={{ .Workspace }}",
+			ExpOut:  "workspace=myworkspace\n",
+		},
+		{
+			Command: "echo '{{.Unknown}}'",
+			ExpErr:  `rendering "echo '{{.Unknown}}'" as a template`,
+		},
+		{
+			Command: "echo '{{ .Workspace'",
+			ExpErr:  `parsing "echo '{{ .Workspace'" as a template`,
+		},
+		{
+			Command: `echo '{{ "{{" }}.Id}}'`,
+			ExpOut:  "{{.Id}}\n",
+		},
 		{
 			Command: `echo mySecret: \"foo\"`,
 			ExpOut:  "mySecret: \"<redacted>\"\n",
@@ -205,3 +237,87 @@ func TestRunStepRunner_Run(t *testing.T) {
 		}
 	}
 }
+
+func TestRunStepRunner_Run_NoCommentArgs(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	When(terraform.EnsureVersion(Any[logging.SimpleLogging](), Any[tf.Distribution](), Any[*version.Version]())).
+		ThenReturn(nil)
+	defaultVersion, _ := version.NewVersion("0.8")
+	r := runtime.RunStepRunner{
+		TerraformExecutor:       terraform,
+		DefaultTFDistribution:   tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader()),
+		DefaultTFVersion:        defaultVersion,
+		ProjectCmdOutputHandler: jobmocks.NewMockProjectCommandOutputHandler(),
+	}
+	ctx := command.ProjectContext{
+		Log: logging.NewNoopLogger(t),
+	}
+	out, err := r.Run(ctx, nil, "echo count={{ len .CommentArgs }} args={{ .CommentArgs }} workspace={{ .Workspace }}", t.TempDir(), map[string]string{}, true, nil, nil)
+	Ok(t, err)
+	Equals(t, "count=0 args= workspace=default\n", out)
+}
+
+func TestRunStepRunner_Run_Artifacts(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	When(terraform.EnsureVersion(Any[logging.SimpleLogging](), Any[tf.Distribution](), Any[*version.Version]())).
+		ThenReturn(nil)
+	defaultVersion, _ := version.NewVersion("0.8")
+	r := runtime.RunStepRunner{
+		TerraformExecutor:       terraform,
+		DefaultTFDistribution:   tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader()),
+		DefaultTFVersion:        defaultVersion,
+		ProjectCmdOutputHandler: jobmocks.NewMockProjectCommandOutputHandler(),
+	}
+	ctx := command.ProjectContext{
+		Log:       logging.NewNoopLogger(t),
+		Workspace: "default",
+	}
+	tmpDir := t.TempDir()
+	Ok(t, runtime.StoreStepArtifact(ctx, tmpDir, "ami_id", "ami-123"))
+
+	out, err := r.Run(ctx, nil, "echo ami={{ .Artifacts.ami_id }}", tmpDir, map[string]string{}, true, nil, nil)
+	Ok(t, err)
+	Equals(t, "ami=ami-123\n", out)
+
+	err = runtime.StoreStepArtifact(ctx, tmpDir, "big", strings.Repeat("a", runtime.MaxStepArtifactBytes+1))
+	ErrContains(t, "more than the limit of", err)
+
+	Ok(t, runtime.StoreStepArtifact(ctx, tmpDir, "unsafe", "$(echo injected); 'quoted'"))
+	out, err = r.Run(ctx, nil, "echo {{ .Artifacts.unsafe }}", tmpDir, map[string]string{}, true, nil, nil)
+	Ok(t, err)
+	Equals(t, "$(echo injected); 'quoted'\n", out)
+
+	Ok(t, runtime.RemoveStepArtifacts(ctx, tmpDir))
+	out, err = r.Run(ctx, nil, "echo {{ len .Artifacts }}", tmpDir, map[string]string{}, true, nil, nil)
+	Ok(t, err)
+	Equals(t, "0\n", out)
+}
+
+func TestRunStepRunner_Run_WorkspaceIsQuoted(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	When(terraform.EnsureVersion(Any[logging.SimpleLogging](), Any[tf.Distribution](), Any[*version.Version]())).
+		ThenReturn(nil)
+	defaultVersion, _ := version.NewVersion("0.8")
+	r := runtime.RunStepRunner{
+		TerraformExecutor:       terraform,
+		DefaultTFDistribution:   tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader()),
+		DefaultTFVersion:        defaultVersion,
+		ProjectCmdOutputHandler: jobmocks.NewMockProjectCommandOutputHandler(),
+	}
+	ctx := command.ProjectContext{
+		Log:       logging.NewNoopLogger(t),
+		Workspace: "ws;echo injected",
+	}
+	out, err := r.Run(ctx, nil, "echo workspace={{ .Workspace }}", t.TempDir(), map[string]string{}, true, nil, nil)
+	Ok(t, err)
+	Equals(t, "workspace=ws;echo injected\n", out)
+}
+
+func TestShellQuote(t *testing.T) {
+	Equals(t, "'default'", runtime.ShellQuote("default"))
+	Equals(t, `'it'\''s'`, runtime.ShellQuote("it's"))
+	Equals(t, "''", runtime.ShellQuote(""))
+}
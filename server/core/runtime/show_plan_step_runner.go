@@ -0,0 +1,80 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// NewShowPlanStepRunner returns a runner that renders the plan file of a
+// project as text, ex. to show what applying it would change without
+// applying it.
+func NewShowPlanStepRunner(executor TerraformExec, defaultTfDistribution terraform.Distribution, defaultTFVersion *version.Version) Runner {
+	showPlanStepRunner := &showPlanStepRunner{
+		terraformExecutor:     executor,
+		defaultTfDistribution: defaultTfDistribution,
+		defaultTFVersion:      defaultTFVersion,
+	}
+	return NewPlanTypeStepRunnerDelegate(showPlanStepRunner, remoteShowPlanStepRunner{})
+}
+
+// showPlanStepRunner runs terraform show on an existing plan file.
+type showPlanStepRunner struct {
+	terraformExecutor     TerraformExec
+	defaultTfDistribution terraform.Distribution
+	defaultTFVersion      *version.Version
+}
+
+func (p *showPlanStepRunner) Run(ctx command.ProjectContext, _ []string, path string, envs map[string]string) (string, error) {
+	tfDistribution := p.defaultTfDistribution
+	tfVersion := p.defaultTFVersion
+	if ctx.TerraformDistribution != nil {
+		tfDistribution = terraform.NewDistribution(*ctx.TerraformDistribution)
+	}
+	if ctx.TerraformVersion != nil {
+		tfVersion = ctx.TerraformVersion
+	}
+
+	planFile, err := GetPlanFilePath(ctx, path)
+	if err != nil {
+		return "", err
+	}
+
+	output, err := p.terraformExecutor.RunCommandWithVersion(
+		ctx,
+		path,
+		[]string{"show", "-no-color", filepath.Clean(planFile)},
+		envs,
+		tfDistribution,
+		tfVersion,
+		ctx.Workspace,
+	)
+	if err != nil {
+		return "", errors.Wrap(err, "running terraform show")
+	}
+	return output, nil
+}
+
+// remoteShowPlanStepRunner returns the output of a plan created by remote
+// ops, which is stored in its plan file since there's no plan to show.
+type remoteShowPlanStepRunner struct{}
+
+func (remoteShowPlanStepRunner) Run(ctx command.ProjectContext, _ []string, path string, _ map[string]string) (string, error) {
+	planFile, err := GetPlanFilePath(ctx, path)
+	if err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(planFile)
+	if err != nil {
+		return "", errors.Wrapf(err, "unable to read %s", planFile)
+	}
+	return strings.TrimPrefix(string(data), remoteOpsHeader), nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	. "github.com/petergtz/pegomock/v4"
+	tf "github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	tfclientmocks "github.com/runatlantis/atlantis/server/core/terraform/tfclient/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestShowPlanStepRunner(t *testing.T) {
+	RegisterMockTestingT(t)
+	logger := logging.NewNoopLogger(t)
+	envs := map[string]string{"key": "val"}
+	tfDistribution := tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+	tfVersion, _ := version.NewVersion("0.12")
+	ctx := command.ProjectContext{
+		Workspace:   "default",
+		ProjectName: "test",
+		Log:         logger,
+	}
+	mockExecutor := tfclientmocks.NewMockClient()
+	subject := NewShowPlanStepRunner(mockExecutor, tfDistribution, tfVersion)
+
+	t.Run("plan", func(t *testing.T) {
+		path := t.TempDir()
+		planFile := filepath.Join(path, "test-default.tfplan")
+		Ok(t, os.WriteFile(planFile, []byte("binary plan"), 0600))
+		When(mockExecutor.RunCommandWithVersion(
+			ctx, path, []string{"show", "-no-color", planFile}, envs, tfDistribution, tfVersion, ctx.Workspace,
+		)).ThenReturn("Plan: 1 to add, 0 to change, 0 to destroy.", nil)
+
+		out, err := subject.Run(ctx, nil, path, envs)
+		Ok(t, err)
+		Equals(t, "Plan: 1 to add, 0 to change, 0 to destroy.", out)
+	})
+
+	t.Run("show fails", func(t *testing.T) {
+		path := t.TempDir()
+		planFile := filepath.Join(path, "test-default.tfplan")
+		Ok(t, os.WriteFile(planFile, []byte("binary plan"), 0600))
+		When(mockExecutor.RunCommandWithVersion(
+			ctx, path, []string{"show", "-no-color", planFile}, envs, tfDistribution, tfVersion, ctx.Workspace,
+		)).ThenReturn("", errors.New("error"))
+
+		_, err := subject.Run(ctx, nil, path, envs)
+		ErrEquals(t, "running terraform show: error", err)
+	})
+
+	t.Run("remote ops plan", func(t *testing.T) {
+		path := t.TempDir()
+		planFile := filepath.Join(path, "test-default.tfplan")
+		Ok(t, os.WriteFile(planFile, []byte(remoteOpsHeader+"Plan: 1 to add, 0 to change, 0 to destroy."), 0600))
+
+		out, err := subject.Run(ctx, nil, path, envs)
+		Ok(t, err)
+		Equals(t, "Plan: 1 to add, 0 to change, 0 to destroy.", out)
+	})
+}
@@ -43,7 +43,10 @@ func (p *showStepRunner) Run(ctx command.ProjectContext, _ []string, path string
 		tfVersion = ctx.TerraformVersion
 	}
 
-	planFile := filepath.Join(path, GetPlanFilename(ctx.Workspace, ctx.ProjectName))
+	planFile, err := GetPlanFilePath(ctx, path)
+	if err != nil {
+		return "", err
+	}
 	showResultFile := filepath.Join(path, ctx.GetShowResultFileName())
 
 	output, err := p.terraformExecutor.RunCommandWithVersion(
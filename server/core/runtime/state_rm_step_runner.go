@@ -10,7 +10,6 @@ import (
 	version "github.com/hashicorp/go-version"
 	"github.com/runatlantis/atlantis/server/core/terraform"
 	"github.com/runatlantis/atlantis/server/events/command"
-	"github.com/runatlantis/atlantis/server/utils"
 )
 
 type stateRmStepRunner struct {
@@ -44,11 +43,14 @@ func (p *stateRmStepRunner) Run(ctx command.ProjectContext, extraArgs []string,
 	out, err := p.terraformExecutor.RunCommandWithVersion(ctx, filepath.Clean(path), stateRmCmd, envs, tfDistribution, tfVersion, ctx.Workspace)
 
 	// If the state rm was successful and a plan file exists, delete the plan.
-	planPath := filepath.Join(path, GetPlanFilename(ctx.Workspace, ctx.ProjectName))
 	if err == nil {
+		planPath, planPathErr := GetPlanFilePath(ctx, path)
+		if planPathErr != nil {
+			return out, planPathErr
+		}
 		if _, planPathErr := os.Stat(planPath); !os.IsNotExist(planPathErr) {
 			ctx.Log.Info("state rm successful, deleting planfile")
-			if removeErr := utils.RemoveIgnoreNonExistent(planPath); removeErr != nil {
+			if removeErr := RemovePlanFile(ctx, path); removeErr != nil {
 				ctx.Log.Warn("failed to delete planfile after successful state rm: %s", removeErr)
 			}
 		}
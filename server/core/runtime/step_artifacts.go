@@ -0,0 +1,80 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// MaxStepArtifactBytes is the largest output a run step can store with
+// store_as. Artifacts are read into memory whenever a run command is
+// rendered so they're meant for small values, ex. a version or an ID.
+const MaxStepArtifactBytes = 64 * 1024
+
+// StepArtifactsDir returns the directory the outputs stored by run steps are
+// saved in given planPath, the path to the plan file of the project. Keeping
+// them next to the plan ties them to its lifecycle, they're removed when the
+// plan is deleted or replaced.
+func StepArtifactsDir(planPath string) string {
+	return strings.TrimSuffix(planPath, ".tfplan") + ".artifacts"
+}
+
+// stepArtifactsDir returns the directory the outputs stored by the project's
+// run steps are saved in given path, the absolute path to the project.
+func stepArtifactsDir(ctx command.ProjectContext, path string) string {
+	return StepArtifactsDir(filepath.Join(path, GetPlanFilename(ctx.Workspace, ctx.ProjectName)))
+}
+
+// StoreStepArtifact saves output as the artifact name so later steps, ex. in
+// the apply stage, can render it in their commands with
+// {{ .Artifacts.name }}.
+func StoreStepArtifact(ctx command.ProjectContext, path string, name string, output string) error {
+	if len(output) > MaxStepArtifactBytes {
+		return fmt.Errorf("output stored as %q is %d bytes, more than the limit of %d bytes", name, len(output), MaxStepArtifactBytes)
+	}
+	dir := stepArtifactsDir(ctx, path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("creating %s: %w", dir, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(output), 0600); err != nil {
+		return fmt.Errorf("storing output as %q: %w", name, err)
+	}
+	return nil
+}
+
+// RemoveStepArtifacts removes the outputs stored by the run steps of a
+// previous plan of the project so they can't leak into the next plan.
+func RemoveStepArtifacts(ctx command.ProjectContext, path string) error {
+	return os.RemoveAll(stepArtifactsDir(ctx, path))
+}
+
+// readStepArtifacts returns the outputs stored by the project's run steps by
+// name. It returns an empty map if no output was stored.
+func readStepArtifacts(ctx command.ProjectContext, path string) (map[string]string, error) {
+	artifacts := make(map[string]string)
+	dir := stepArtifactsDir(ctx, path)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return artifacts, nil
+	}
+	if err != nil {
+		return artifacts, err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return artifacts, err
+		}
+		artifacts[entry.Name()] = string(content)
+	}
+	return artifacts, nil
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"path/filepath"
+
+	"github.com/hashicorp/go-version"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// ValidateStepRunner runs terraform validate. The project must have been
+// initialized by an earlier init step.
+type ValidateStepRunner struct {
+	TerraformExecutor     TerraformExec
+	DefaultTFDistribution terraform.Distribution
+	DefaultTFVersion      *version.Version
+}
+
+func (v *ValidateStepRunner) Run(ctx command.ProjectContext, extraArgs []string, path string, envs map[string]string) (string, error) {
+	tfDistribution := v.DefaultTFDistribution
+	tfVersion := v.DefaultTFVersion
+	if ctx.TerraformDistribution != nil {
+		tfDistribution = terraform.NewDistribution(*ctx.TerraformDistribution)
+	}
+	if ctx.TerraformVersion != nil {
+		tfVersion = ctx.TerraformVersion
+	}
+
+	validateCmd := append([]string{"validate", "-no-color"}, extraArgs...)
+	out, err := v.TerraformExecutor.RunCommandWithVersion(ctx, filepath.Clean(path), validateCmd, envs, tfDistribution, tfVersion, ctx.Workspace)
+	// Like init, the output is only useful if the configuration is invalid.
+	if err != nil {
+		return out, err
+	}
+	return "", nil
+}
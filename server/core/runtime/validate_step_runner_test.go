@@ -0,0 +1,70 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime_test
+
+import (
+	"errors"
+	"testing"
+
+	version "github.com/hashicorp/go-version"
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	tf "github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/core/terraform/mocks"
+	tfclientmocks "github.com/runatlantis/atlantis/server/core/terraform/tfclient/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestValidateStepRunner_Run(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	tfDistribution := tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+	tfVersion, _ := version.NewVersion("1.5.0")
+	r := runtime.ValidateStepRunner{
+		TerraformExecutor:     terraform,
+		DefaultTFDistribution: tfDistribution,
+		DefaultTFVersion:      tfVersion,
+	}
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+		ThenReturn("Success! The configuration is valid.", nil)
+
+	ctx := command.ProjectContext{
+		Workspace:  "default",
+		RepoRelDir: ".",
+		Log:        logging.NewNoopLogger(t),
+	}
+	output, err := r.Run(ctx, []string{"-json"}, "/path", map[string]string(nil))
+	Ok(t, err)
+	Equals(t, "", output)
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(ctx, "/path", []string{"validate", "-no-color", "-json"}, map[string]string(nil), tfDistribution, tfVersion, "default")
+}
+
+func TestValidateStepRunner_Run_UsesConfiguredVersion(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	defaultVersion, _ := version.NewVersion("1.5.0")
+	r := runtime.ValidateStepRunner{
+		TerraformExecutor:     terraform,
+		DefaultTFDistribution: tf.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader()),
+		DefaultTFVersion:      defaultVersion,
+	}
+	When(terraform.RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[tf.Distribution](), Any[*version.Version](), Any[string]())).
+		ThenReturn("Error: Unsupported argument", errors.New("exit status 1"))
+
+	projDistribution := "opentofu"
+	projVersion, _ := version.NewVersion("1.8.0")
+	ctx := command.ProjectContext{
+		Workspace:             "staging",
+		RepoRelDir:            "project",
+		TerraformDistribution: &projDistribution,
+		TerraformVersion:      projVersion,
+		Log:                   logging.NewNoopLogger(t),
+	}
+	output, err := r.Run(ctx, nil, "/path/project/", map[string]string(nil))
+	ErrEquals(t, "exit status 1", err)
+	Equals(t, "Error: Unsupported argument", output)
+	terraform.VerifyWasCalledOnce().RunCommandWithVersion(Eq(ctx), Eq("/path/project"), Eq([]string{"validate", "-no-color"}), Eq(map[string]string(nil)), NotEq[tf.Distribution](r.DefaultTFDistribution), Eq(projVersion), Eq("staging"))
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+// VaultSecretReader reads the secrets env steps reference in Vault. Secrets
+// are read with the vault CLI so it authenticates from the ambient
+// environment the same way it does for Terraform's Vault provider, ex. with
+// VAULT_ADDR and VAULT_TOKEN or ~/.vault-token.
+type VaultSecretReader struct {
+	Exec runtime_models.Exec
+}
+
+// vaultReadResponse is the part of the output of vault read -format=json
+// secrets are read from.
+type vaultReadResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+// Read returns the value of key in the secret at the Vault API path
+// secretPath, ex. "secret/data/x". Secrets of KV version 2 engines are
+// unwrapped from their metadata.
+func (v *VaultSecretReader) Read(ctx command.ProjectContext, secretPath string, key string, path string, envs map[string]string) (string, error) {
+	if _, err := v.Exec.LookPath("vault"); err != nil {
+		return "", fmt.Errorf("env step: vault is required to read secret %q: %w", secretPath, err)
+	}
+	out, err := v.Exec.CombinedOutput([]string{"vault", "read", "-format=json", shellQuote(secretPath)}, envs, path)
+	if err != nil {
+		return "", fmt.Errorf("env step: reading secret %q from vault: %w: %s", secretPath, err, strings.TrimSpace(out))
+	}
+
+	// Warnings are printed to stderr before the JSON so they're skipped.
+	var resp vaultReadResponse
+	if i := strings.Index(out, "{"); i < 0 {
+		return "", fmt.Errorf("env step: reading secret %q from vault: unexpected output: %s", secretPath, strings.TrimSpace(out))
+	} else if err := json.Unmarshal([]byte(out[i:]), &resp); err != nil {
+		return "", fmt.Errorf("env step: parsing secret %q from vault: %w", secretPath, err)
+	}
+
+	data := resp.Data
+	if inner, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	value, ok := data[key]
+	if !ok || value == nil {
+		return "", fmt.Errorf("env step: secret %q in vault has no key %q", secretPath, key)
+	}
+	ctx.Log.Debug("read key %q of secret %q from vault", key, secretPath)
+	if str, ok := value.(string); ok {
+		return str, nil
+	}
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("env step: encoding key %q of secret %q: %w", key, secretPath, err)
+	}
+	return string(encoded), nil
+}
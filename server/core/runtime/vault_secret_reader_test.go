@@ -0,0 +1,97 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package runtime_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/runtime"
+	models_mocks "github.com/runatlantis/atlantis/server/core/runtime/models/mocks"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestVaultSecretReader_Read(t *testing.T) {
+	cases := []struct {
+		description string
+		key         string
+		out         string
+		execErr     error
+		exp         string
+		expErr      string
+	}{
+		{
+			description: "kv version 2",
+			key:         "token",
+			out:         `{"data": {"data": {"token": "s3cr3t"}, "metadata": {"version": 3}}}`,
+			exp:         "s3cr3t",
+		},
+		{
+			description: "kv version 1",
+			key:         "token",
+			out:         `{"data": {"token": "s3cr3t"}}`,
+			exp:         "s3cr3t",
+		},
+		{
+			description: "warning before the output",
+			key:         "token",
+			out:         "WARNING! The following warnings were returned from Vault:\n\n{\"data\": {\"token\": \"s3cr3t\"}}",
+			exp:         "s3cr3t",
+		},
+		{
+			description: "non-string value",
+			key:         "ports",
+			out:         `{"data": {"ports": [80, 443]}}`,
+			exp:         "[80,443]",
+		},
+		{
+			description: "missing key",
+			key:         "password",
+			out:         `{"data": {"data": {"token": "s3cr3t"}, "metadata": {"version": 3}}}`,
+			expErr:      "env step: secret \"secret/data/x\" in vault has no key \"password\"",
+		},
+		{
+			description: "read fails",
+			key:         "token",
+			out:         "Error reading secret/data/x: permission denied\n",
+			execErr:     errors.New("exit status 2"),
+			expErr:      "env step: reading secret \"secret/data/x\" from vault: exit status 2: Error reading secret/data/x: permission denied",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpDir := t.TempDir()
+			mockExec := models_mocks.NewMockExec()
+			When(mockExec.LookPath(Any[string]())).ThenReturn("/usr/bin/vault", nil)
+			When(mockExec.CombinedOutput(Any[[]string](), Any[map[string]string](), Any[string]())).ThenReturn(c.out, c.execErr)
+			r := runtime.VaultSecretReader{Exec: mockExec}
+
+			ctx := command.ProjectContext{Log: logging.NewNoopLogger(t)}
+			envs := map[string]string{"VAULT_NAMESPACE": "team"}
+			value, err := r.Read(ctx, "secret/data/x", c.key, tmpDir, envs)
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+			} else {
+				Ok(t, err)
+				Equals(t, c.exp, value)
+			}
+			mockExec.VerifyWasCalledOnce().CombinedOutput([]string{"vault", "read", "-format=json", "'secret/data/x'"}, envs, tmpDir)
+		})
+	}
+}
+
+func TestVaultSecretReader_Read_NoVaultCLI(t *testing.T) {
+	RegisterMockTestingT(t)
+	mockExec := models_mocks.NewMockExec()
+	When(mockExec.LookPath(Any[string]())).ThenReturn("", errors.New("executable file not found in $PATH"))
+	r := runtime.VaultSecretReader{Exec: mockExec}
+
+	_, err := r.Read(command.ProjectContext{Log: logging.NewNoopLogger(t)}, "secret/data/x", "token", t.TempDir(), map[string]string{})
+	ErrEquals(t, "env step: vault is required to read secret \"secret/data/x\": executable file not found in $PATH", err)
+	mockExec.VerifyWasCalled(Never()).CombinedOutput(Any[[]string](), Any[map[string]string](), Any[string]())
+}
@@ -5,6 +5,8 @@ package runtime
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/hashicorp/go-version"
@@ -96,6 +98,40 @@ func (r *workspaceStepRunnerDelegate) switchWorkspace(ctx command.ProjectContext
 		if err != nil {
 			return fmt.Errorf("%s: %s", err, out)
 		}
+		ctx.Log.Info("created workspace %q since it didn't exist", ctx.Workspace)
+		if err := writeWorkspaceCreated(ctx, path); err != nil {
+			ctx.Log.Warn("unable to save that workspace %q was created: %s", ctx.Workspace, err)
+		}
 	}
 	return nil
 }
+
+// workspaceCreatedPath returns the path of the file that's written when the
+// project's workspace is created given path, the absolute path to the
+// project.
+func workspaceCreatedPath(ctx command.ProjectContext, path string) string {
+	name := strings.TrimSuffix(GetPlanFilename(ctx.Workspace, ctx.ProjectName), ".tfplan")
+	return filepath.Join(path, name+".workspace-created")
+}
+
+// writeWorkspaceCreated saves that the project's workspace was created.
+func writeWorkspaceCreated(ctx command.ProjectContext, path string) error {
+	return os.WriteFile(workspaceCreatedPath(ctx, path), nil, 0600)
+}
+
+// RemoveWorkspaceCreated removes the file saved when a previous command
+// created the project's workspace so the next command only reports the
+// workspaces it created.
+func RemoveWorkspaceCreated(ctx command.ProjectContext, path string) error {
+	if err := os.Remove(workspaceCreatedPath(ctx, path)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// WorkspaceCreated returns true if the project's workspace was created since
+// RemoveWorkspaceCreated was called.
+func WorkspaceCreated(ctx command.ProjectContext, path string) bool {
+	_, err := os.Stat(workspaceCreatedPath(ctx, path))
+	return err == nil
+}
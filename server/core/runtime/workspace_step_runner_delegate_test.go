@@ -258,6 +258,32 @@ func TestRun_CreatesWorkspace(t *testing.T) {
 	}
 }
 
+func TestRun_CreatesWorkspaceSavesCreated(t *testing.T) {
+	RegisterMockTestingT(t)
+	terraform := tfclientmocks.NewMockClient()
+	mockDownloader := mocks.NewMockDownloader()
+	tfDistribution := tf.NewDistributionTerraformWithDownloader(mockDownloader)
+	tfVersion, _ := version.NewVersion("0.11.0")
+	ctx := command.ProjectContext{
+		Log:        logging.NewNoopLogger(t),
+		Workspace:  "workspace",
+		RepoRelDir: ".",
+	}
+	path := t.TempDir()
+	s := NewWorkspaceStepRunnerDelegate(terraform, tfDistribution, tfVersion, &NullRunner{})
+	When(terraform.RunCommandWithVersion(ctx, path, []string{"workspace", "show"}, map[string]string(nil), tfDistribution, tfVersion, "workspace")).ThenReturn("default\n", nil)
+	When(terraform.RunCommandWithVersion(ctx, path, []string{"workspace", "select", "workspace"}, map[string]string(nil), tfDistribution, tfVersion, "workspace")).ThenReturn("", errors.New("workspace does not exist"))
+
+	Assert(t, !WorkspaceCreated(ctx, path), "exp workspace not created before running")
+	_, err := s.Run(ctx, []string{"extra", "args"}, path, map[string]string(nil))
+	Ok(t, err)
+	Assert(t, WorkspaceCreated(ctx, path), "exp workspace created")
+
+	Ok(t, RemoveWorkspaceCreated(ctx, path))
+	Assert(t, !WorkspaceCreated(ctx, path), "exp workspace created to be removed")
+	Ok(t, RemoveWorkspaceCreated(ctx, path))
+}
+
 func TestRun_NoWorkspaceSwitchIfNotNecessary(t *testing.T) {
 	// Tests that if workspace show says we're on the right workspace we don't
 	// switch.
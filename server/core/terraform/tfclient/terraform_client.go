@@ -54,7 +54,9 @@ type Client interface {
 	EnsureVersion(log logging.SimpleLogging, d terraform.Distribution, v *version.Version) error
 
 	// DetectVersion Extracts required_version from Terraform configuration in the specified project directory. Returns nil if unable to determine the version.
-	DetectVersion(log logging.SimpleLogging, projectDirectory string) *version.Version
+	// Version constraints are resolved against the releases of d, or of the default distribution if d is nil.
+	// It returns an error if the version in the project's .terraform-version file conflicts with required_version.
+	DetectVersion(log logging.SimpleLogging, d terraform.Distribution, projectDirectory string) (*version.Version, error)
 }
 
 type DefaultClient struct {
@@ -74,8 +76,11 @@ type DefaultClient struct {
 	// settings for the downloader.
 	downloadBaseURL string
 	downloadAllowed bool
-	// versions maps from the string representation of a tf version (ex. 0.11.10)
-	// to the absolute path of that binary on disk (if it exists).
+	// versions maps from the binary name and string representation of a tf
+	// version (ex. terraform0.11.10 or tofu1.6.0) to the absolute path of that
+	// binary on disk (if it exists). The binary name is part of the key since
+	// Terraform and OpenTofu can be used for different projects and their
+	// version numbers overlap.
 	// Use versionsLock to control access.
 	versions map[string]string
 
@@ -130,7 +135,7 @@ func NewClientWithDefaultVersion(
 		if err != nil {
 			return nil, err
 		}
-		versions[localVersion.String()] = localPath
+		versions[distribution.BinName()+localVersion.String()] = localPath
 		if defaultVersionStr == "" {
 
 			// If they haven't set a default version, then whatever they had
@@ -272,6 +277,32 @@ func (c *DefaultClient) TerraformBinDir() string {
 	return c.binDir
 }
 
+// VersionFile is the name of the file in a project's directory that pins the
+// project's Terraform version, like it does for tfenv.
+const VersionFile = ".terraform-version"
+
+// readVersionFile returns the version in the VersionFile of projectDirectory
+// or nil if there's no such file.
+func readVersionFile(projectDirectory string) (*version.Version, error) {
+	content, err := os.ReadFile(filepath.Join(projectDirectory, VersionFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	line, _, _ := strings.Cut(strings.TrimSpace(string(content)), "\n")
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, errors.New("file is empty")
+	}
+	v, err := version.NewVersion(line)
+	if err != nil {
+		return nil, fmt.Errorf("%q is not an exact version: %w", line, err)
+	}
+	return v, nil
+}
+
 // ExtractExactRegex attempts to extract an exact version number from the provided string as a fallback.
 // The function expects the version string to be in one of the following formats: "= x.y.z", "=x.y.z", or "x.y.z" where x, y, and z are integers.
 // If the version string matches one of these formats, the function returns a slice containing the exact version number.
@@ -289,18 +320,37 @@ func (c *DefaultClient) ExtractExactRegex(log logging.SimpleLogging, version str
 	return tfVersions
 }
 
-// DetectVersion extracts required_version from Terraform configuration in the specified project directory. Returns nil if unable to determine the version.
+// DetectVersion returns the version in the .terraform-version file of the specified project directory or else extracts required_version
+// from its Terraform configuration. Returns nil if unable to determine the version, and an error if the version in the
+// .terraform-version file doesn't satisfy the required_version of the configuration.
 // It will also try to evaluate non-exact matches by passing the Constraints to the hc-install Releases API, which will return a list of available versions.
 // It will then select the highest version that satisfies the constraint.
-func (c *DefaultClient) DetectVersion(log logging.SimpleLogging, projectDirectory string) *version.Version {
+func (c *DefaultClient) DetectVersion(log logging.SimpleLogging, d terraform.Distribution, projectDirectory string) (*version.Version, error) {
+	if d == nil {
+		d = c.distribution
+	}
 	module, diags := tfconfig.LoadModule(projectDirectory)
 	if diags.HasErrors() {
 		log.Err("trying to detect required version: %s", diags.Error())
 	}
 
+	fileVersion, err := readVersionFile(projectDirectory)
+	if err != nil {
+		log.Err("ignoring %s, using required_version instead: %s", VersionFile, err)
+	} else if fileVersion != nil {
+		log.Debug("found version %s in %s", fileVersion, VersionFile)
+		for _, requiredVersion := range module.RequiredCore {
+			constraint, err := version.NewConstraint(requiredVersion)
+			if err == nil && !constraint.Check(fileVersion) {
+				return nil, fmt.Errorf("version %s in %s doesn't satisfy the required_version %q of the Terraform configuration", fileVersion, VersionFile, requiredVersion)
+			}
+		}
+		return fileVersion, nil
+	}
+
 	if len(module.RequiredCore) != 1 {
 		log.Info("cannot determine which version to use from terraform configuration, detected %d possibilities.", len(module.RequiredCore))
-		return nil
+		return nil, nil
 	}
 	requiredVersionSetting := module.RequiredCore[0]
 	log.Debug("Found required_version setting of %q", requiredVersionSetting)
@@ -310,24 +360,24 @@ func (c *DefaultClient) DetectVersion(log logging.SimpleLogging, projectDirector
 		matched := c.ExtractExactRegex(log, requiredVersionSetting)
 		if len(matched) == 0 {
 			log.Debug("did not specify exact version in terraform configuration, found %q", requiredVersionSetting)
-			return nil
+			return nil, nil
 		}
 
 		version, err := version.NewVersion(matched[0])
 		if err != nil {
 			log.Err("error parsing version string: %s", err)
-			return nil
+			return nil, nil
 		}
-		return version
+		return version, nil
 	}
 
-	downloadVersion, err := c.distribution.ResolveConstraint(context.Background(), requiredVersionSetting)
+	downloadVersion, err := d.ResolveConstraint(context.Background(), requiredVersionSetting)
 	if err != nil {
 		log.Err("%s", err)
-		return nil
+		return nil, nil
 	}
 
-	return downloadVersion
+	return downloadVersion, nil
 }
 
 // See Client.EnsureVersion.
@@ -488,6 +538,45 @@ func MustConstraint(v string) version.Constraints {
 	return c
 }
 
+// FindInstalledVersion returns the newest version of dist's binary that's
+// already on disk and satisfies constraint. It looks at the binary in $PATH
+// and at {binName}{version} binaries in $PATH and binDir, the same places
+// ensureVersion looks before downloading.
+func FindInstalledVersion(dist terraform.Distribution, binDir string, constraint version.Constraints) (*version.Version, error) {
+	var installed []*version.Version
+	if localPath, err := exec.LookPath(dist.BinName()); err == nil {
+		if localVersion, err := getVersion(localPath, dist.BinName()); err == nil {
+			installed = append(installed, localVersion)
+		}
+	}
+	for _, dir := range append(filepath.SplitList(os.Getenv("PATH")), binDir) {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			versionStr, ok := strings.CutPrefix(entry.Name(), dist.BinName())
+			if !ok || entry.IsDir() {
+				continue
+			}
+			if v, err := version.NewVersion(versionStr); err == nil {
+				installed = append(installed, v)
+			}
+		}
+	}
+
+	var newest *version.Version
+	for _, v := range installed {
+		if constraint.Check(v) && (newest == nil || v.GreaterThan(newest)) {
+			newest = v
+		}
+	}
+	if newest == nil {
+		return nil, fmt.Errorf("no installed %s version in PATH or %s satisfies %q", dist.BinName(), binDir, constraint.String())
+	}
+	return newest, nil
+}
+
 // ensureVersion returns the path to a terraform binary of version v.
 // It will download this version if we don't have it.
 func ensureVersion(
@@ -499,16 +588,16 @@ func ensureVersion(
 	downloadURL string,
 	downloadsAllowed bool,
 ) (string, error) {
-	if binPath, ok := versions[v.String()]; ok {
+	binFile := dist.BinName() + v.String()
+	if binPath, ok := versions[binFile]; ok {
 		return binPath, nil
 	}
 
 	// This tf version might not yet be in the versions map even though it
 	// exists on disk. This would happen if users have manually added
 	// terraform{version} binaries. In this case we don't want to re-download.
-	binFile := dist.BinName() + v.String()
 	if binPath, err := exec.LookPath(binFile); err == nil {
-		versions[v.String()] = binPath
+		versions[binFile] = binPath
 		return binPath, nil
 	}
 
@@ -516,7 +605,7 @@ func ensureVersion(
 	// This could happen if Atlantis was restarted without losing its disk.
 	dest := filepath.Join(binDir, binFile)
 	if _, err := os.Stat(dest); err == nil {
-		versions[v.String()] = dest
+		versions[binFile] = dest
 		return dest, nil
 	}
 	if !downloadsAllowed {
@@ -539,7 +628,7 @@ func ensureVersion(
 	}
 
 	log.Info("Downloaded %s %s to %s", dist.BinName(), v.String(), execPath)
-	versions[v.String()] = execPath
+	versions[binFile] = execPath
 	return execPath, nil
 }
 
@@ -210,6 +210,34 @@ func TestNewClient_DefaultTFFlagInBinDir(t *testing.T) {
 	Equals(t, fakeBinOut+"\n", output)
 }
 
+// Test that Terraform and OpenTofu binaries with the same version aren't
+// mixed up when projects use different distributions.
+func TestRunCommandWithVersion_Distributions(t *testing.T) {
+	tmp, binDir, cacheDir := mkSubDirs(t)
+	ctx := command.ProjectContext{
+		Log:        logging.NewNoopLogger(t),
+		Workspace:  "default",
+		RepoRelDir: ".",
+	}
+	Ok(t, os.WriteFile(filepath.Join(tmp, "terraform1.6.0"), []byte("#!/bin/sh\necho 'Terraform v1.6.0'"), 0700)) // #nosec G306
+	Ok(t, os.WriteFile(filepath.Join(tmp, "tofu1.6.0"), []byte("#!/bin/sh\necho 'OpenTofu v1.6.0'"), 0700))       // #nosec G306
+	defer tempSetEnv(t, "PATH", fmt.Sprintf("%s:%s", tmp, os.Getenv("PATH")))()
+
+	tfDistribution := terraform.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+	tofuDistribution := terraform.NewDistributionOpenTofuWithDownloader(mocks.NewMockDownloader())
+	c, err := tfclient.NewClient(logging.NewNoopLogger(t), tfDistribution, binDir, cacheDir, "", "", "1.6.0", cmd.DefaultTFVersionFlag, cmd.DefaultTFDownloadURL, false, true, jobmocks.NewMockProjectCommandOutputHandler())
+	Ok(t, err)
+	v, err := version.NewVersion("1.6.0")
+	Ok(t, err)
+
+	output, err := c.RunCommandWithVersion(ctx, tmp, []string{"version"}, map[string]string{}, tfDistribution, v, "")
+	Ok(t, err)
+	Equals(t, "Terraform v1.6.0\n", output)
+	output, err = c.RunCommandWithVersion(ctx, tmp, []string{"version"}, map[string]string{}, tofuDistribution, v, "")
+	Ok(t, err)
+	Equals(t, "OpenTofu v1.6.0\n", output)
+}
+
 // Test that if we don't have that version of TF that we download it.
 func TestNewClient_DefaultTFFlagDownload(t *testing.T) {
 	RegisterMockTestingT(t)
@@ -386,6 +414,42 @@ func TestEnsureVersion_downloaded_downloadingDisabled(t *testing.T) {
 
 // tempSetEnv sets env var key to value. It returns a function that when called
 // will reset the env var to its original value.
+// Test that the newest installed version matching the constraint is picked.
+func TestFindInstalledVersion(t *testing.T) {
+	tmp, binDir, _ := mkSubDirs(t)
+	// terraform in PATH is 1.4.0, terraform1.6.2 is also in PATH and 1.5.7 and
+	// 1.7.0 were downloaded to the bin dir.
+	Ok(t, os.WriteFile(filepath.Join(tmp, "terraform"), []byte("#!/bin/sh\necho 'Terraform v1.4.0'"), 0700)) // #nosec G306
+	for _, path := range []string{filepath.Join(tmp, "terraform1.6.2"), filepath.Join(binDir, "terraform1.5.7"), filepath.Join(binDir, "terraform1.7.0")} {
+		Ok(t, os.WriteFile(path, nil, 0700)) // #nosec G306
+	}
+	defer tempSetEnv(t, "PATH", tmp)()
+	distribution := terraform.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+
+	cases := []struct {
+		constraint string
+		expVersion string
+		expErr     string
+	}{
+		{constraint: ">= 1.5, < 1.7", expVersion: "1.6.2"},
+		{constraint: ">= 1.5", expVersion: "1.7.0"},
+		{constraint: "~> 1.4.0", expVersion: "1.4.0"},
+		{constraint: "1.5.7", expVersion: "1.5.7"},
+		{constraint: ">= 2", expErr: fmt.Sprintf("no installed terraform version in PATH or %s satisfies \">= 2\"", binDir)},
+	}
+	for _, c := range cases {
+		t.Run(c.constraint, func(t *testing.T) {
+			v, err := tfclient.FindInstalledVersion(distribution, binDir, tfclient.MustConstraint(c.constraint))
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, c.expVersion, v.String())
+		})
+	}
+}
+
 func tempSetEnv(t *testing.T, key string, value string) func() {
 	orig := os.Getenv(key)
 	Ok(t, os.Setenv(key, value))
@@ -520,14 +584,14 @@ terraform {
 			tmpDir := DirStructure(t, testCase.DirStructure)
 
 			for project, expectedVersion := range testCase.Exp {
-				detectedVersion := c.DetectVersion(logger, filepath.Join(tmpDir, project))
+				detectedVersion, err := c.DetectVersion(logger, nil, filepath.Join(tmpDir, project))
+				Ok(t, err)
 
 				expectNil := expectedVersion == "" || (!testCase.IsExact && !downloadsAllowed)
 				if expectNil {
 					Assert(t, detectedVersion == nil, "TerraformVersion is supposed to be nil.")
 				} else {
 					Assert(t, detectedVersion != nil, "TerraformVersion is nil.")
-					Ok(t, err)
 					Equals(t, expectedVersion, detectedVersion.String())
 				}
 			}
@@ -541,6 +605,64 @@ terraform {
 	}
 }
 
+func TestDetectVersion_VersionFile(t *testing.T) {
+	cases := []struct {
+		description string
+		versionFile string
+		mainTF      string
+		expVersion  string
+		expErr      string
+	}{
+		{
+			description: "version file",
+			versionFile: "1.5.7\n",
+			expVersion:  "1.5.7",
+		},
+		{
+			description: "version file satisfies required_version",
+			versionFile: "v1.5.7",
+			mainTF:      `terraform { required_version = ">= 1.4.0" }`,
+			expVersion:  "1.5.7",
+		},
+		{
+			description: "version file conflicts with required_version",
+			versionFile: "1.5.7",
+			mainTF:      `terraform { required_version = "= 1.4.0" }`,
+			expErr:      `version 1.5.7 in .terraform-version doesn't satisfy the required_version "= 1.4.0" of the Terraform configuration`,
+		},
+		{
+			description: "invalid version file uses required_version",
+			versionFile: "latest",
+			mainTF:      `terraform { required_version = "= 1.4.0" }`,
+			expVersion:  "1.4.0",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			logger := logging.NewNoopLogger(t)
+			_, binDir, cacheDir := mkSubDirs(t)
+			distribution := terraform.NewDistributionTerraformWithDownloader(mocks.NewMockDownloader())
+			client, err := tfclient.NewTestClient(logger, distribution, binDir, cacheDir, "", "", "1.5.7", cmd.DefaultTFVersionFlag, cmd.DefaultTFDownloadURL, false, true, jobmocks.NewMockProjectCommandOutputHandler())
+			Ok(t, err)
+
+			projectDir := t.TempDir()
+			Ok(t, os.WriteFile(filepath.Join(projectDir, tfclient.VersionFile), []byte(c.versionFile), 0600))
+			if c.mainTF != "" {
+				Ok(t, os.WriteFile(filepath.Join(projectDir, "main.tf"), []byte(c.mainTF), 0600))
+			}
+			v, err := client.DetectVersion(logger, nil, projectDir)
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Assert(t, v != nil, "exp version to be detected")
+			Equals(t, c.expVersion, v.String())
+		})
+	}
+}
+
 func TestExtractExactRegex(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
 	RegisterMockTestingT(t)
@@ -4,11 +4,16 @@
 package events
 
 import (
+	"fmt"
+	"slices"
+	"sync"
+
 	"github.com/runatlantis/atlantis/server/core/db"
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/recovery"
 )
 
 func NewApplyCommandRunner(
@@ -26,6 +31,8 @@ func NewApplyCommandRunner(
 	SilenceNoProjects bool,
 	silenceVCSStatusNoProjects bool,
 	pullReqStatusFetcher vcs.PullReqStatusFetcher,
+	asyncApply bool,
+	drainer *Drainer,
 ) *ApplyCommandRunner {
 	return &ApplyCommandRunner{
 		vcsClient:                  vcsClient,
@@ -42,6 +49,9 @@ func NewApplyCommandRunner(
 		SilenceNoProjects:          SilenceNoProjects,
 		silenceVCSStatusNoProjects: silenceVCSStatusNoProjects,
 		pullReqStatusFetcher:       pullReqStatusFetcher,
+		asyncApply:                 asyncApply,
+		drainer:                    drainer,
+		asyncApplies:               make(map[string]bool),
 	}
 }
 
@@ -65,6 +75,14 @@ type ApplyCommandRunner struct {
 	// are found
 	silenceVCSStatusNoProjects bool
 	SilencePRComments          []string
+	// asyncApply is whether applies are acknowledged immediately and run in
+	// the background, with the results commented once they complete.
+	asyncApply bool
+	// drainer tracks background applies so shutdown waits for them.
+	drainer *Drainer
+	// asyncApplies holds the pulls that have a background apply in progress.
+	asyncApplies     map[string]bool
+	asyncAppliesLock sync.Mutex
 }
 
 func (a *ApplyCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
@@ -82,7 +100,7 @@ func (a *ApplyCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
 
 	if locked {
 		ctx.Log.Info("ignoring apply command since apply disabled globally")
-		if err := a.vcsClient.CreateComment(ctx.Log, baseRepo, pull.Num, applyDisabledComment, command.Apply.String()); err != nil {
+		if _, err := a.vcsClient.CreateComment(ctx.Log, baseRepo, pull.Num, applyDisabledComment, command.Apply.String()); err != nil {
 			ctx.Log.Err("unable to comment on pull request: %s", err)
 		}
 
@@ -91,7 +109,7 @@ func (a *ApplyCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
 
 	if a.DisableApplyAll && !cmd.IsForSpecificProject() {
 		ctx.Log.Info("ignoring apply command without flags since apply all is disabled")
-		if err := a.vcsClient.CreateComment(ctx.Log, baseRepo, pull.Num, applyAllDisabledComment, command.Apply.String()); err != nil {
+		if _, err := a.vcsClient.CreateComment(ctx.Log, baseRepo, pull.Num, applyAllDisabledComment, command.Apply.String()); err != nil {
 			ctx.Log.Err("unable to comment on pull request: %s", err)
 		}
 
@@ -158,13 +176,121 @@ func (a *ApplyCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
 		return
 	}
 
-	// Only run commands in parallel if enabled
+	if cmd.DryRun {
+		a.runDryRun(ctx, cmd, projectCmds)
+		return
+	}
+	if a.asyncApply {
+		a.runAsync(ctx, cmd, projectCmds)
+		return
+	}
+	a.runProjectCmds(ctx, cmd, projectCmds)
+}
+
+// runDryRun checks the apply requirements of projectCmds and comments the
+// plans that would be applied. Nothing is applied so the pull status, commit
+// statuses and automerge are left as they are. ProjectOutputWrapper doesn't
+// set the project statuses of dry runs either.
+func (a *ApplyCommandRunner) runDryRun(ctx *command.Context, cmd *CommentCommand, projectCmds []command.ProjectContext) {
+	ctx.Log.Info("checking apply requirements of %d projects without applying", len(projectCmds))
+	result := runProjectCmds(projectCmds, a.prjCmdRunner.Apply)
+	ctx.CommandHasErrors = result.HasErrors()
+	a.pullUpdater.updatePull(ctx, cmd, result)
+}
+
+// runAsync acknowledges the apply on the pull request and then applies
+// projectCmds in the background, commenting the results once they complete.
+// Only one background apply may run per pull request at a time. Each project
+// still holds its working dir lock for the duration of its apply.
+func (a *ApplyCommandRunner) runAsync(ctx *command.Context, cmd *CommentCommand, projectCmds []command.ProjectContext) {
+	baseRepo := ctx.Pull.BaseRepo
+	pull := ctx.Pull
+	key := fmt.Sprintf("%s/%d", baseRepo.FullName, pull.Num)
+
+	a.asyncAppliesLock.Lock()
+	if a.asyncApplies[key] {
+		a.asyncAppliesLock.Unlock()
+		ctx.Log.Info("ignoring apply command since a background apply is already running")
+		if _, err := a.vcsClient.CreateComment(ctx.Log, baseRepo, pull.Num, asyncApplyInProgressComment, command.Apply.String()); err != nil {
+			ctx.Log.Err("unable to comment on pull request: %s", err)
+		}
+		return
+	}
+	a.asyncApplies[key] = true
+	a.asyncAppliesLock.Unlock()
+
+	if !a.drainer.StartOp() {
+		a.finishAsync(key)
+		if _, err := a.vcsClient.CreateComment(ctx.Log, baseRepo, pull.Num, ShutdownComment, command.Apply.String()); err != nil {
+			ctx.Log.Err("unable to comment that Atlantis is shutting down: %s", err)
+		}
+		return
+	}
+
+	comment := fmt.Sprintf(asyncApplyStartedComment, len(projectCmds))
+	if _, err := a.vcsClient.CreateComment(ctx.Log, baseRepo, pull.Num, comment, command.Apply.String()); err != nil {
+		ctx.Log.Warn("unable to comment on pull request: %s", err)
+	}
+
+	// The command is finished, ex. the post-workflow hooks run, once the
+	// apply completed rather than when Run returns.
+	done := func() {}
+	if ctx.Detach != nil {
+		done = ctx.Detach()
+	}
+
+	ctx.Log.Info("running apply for %d projects in the background", len(projectCmds))
+	go func() {
+		defer a.drainer.OpDone()
+		defer a.finishAsync(key)
+		defer done()
+		defer func() {
+			if err := recover(); err != nil {
+				stack := recovery.Stack(3)
+				ctx.Log.Err("PANIC: %s\n%s", err, stack)
+				if _, commentErr := a.vcsClient.CreateComment(ctx.Log, baseRepo, pull.Num,
+					fmt.Sprintf("**Error: goroutine panic. This is a bug.**\n```\n%s\n%s```", err, stack), command.Apply.String()); commentErr != nil {
+					ctx.Log.Err("unable to comment: %s", commentErr)
+				}
+			}
+		}()
+		a.runProjectCmds(ctx, cmd, projectCmds)
+		ctx.Log.Info("background apply finished")
+	}()
+}
+
+func (a *ApplyCommandRunner) finishAsync(key string) {
+	a.asyncAppliesLock.Lock()
+	defer a.asyncAppliesLock.Unlock()
+	delete(a.asyncApplies, key)
+}
+
+// runProjectCmds applies projectCmds and reports the results on the pull
+// request, in the database and in the commit status.
+func (a *ApplyCommandRunner) runProjectCmds(ctx *command.Context, cmd *CommentCommand, projectCmds []command.ProjectContext) {
+	pull := ctx.Pull
+
+	// Projects that depend on other projects in this apply are run after
+	// their dependencies so the dependency check sees them as applied.
 	var result command.Result
-	if a.isParallelEnabled(projectCmds) {
-		ctx.Log.Info("Running applies in parallel")
-		result = runProjectCmdsParallelGroups(ctx, projectCmds, a.prjCmdRunner.Apply, a.parallelPoolSize)
-	} else {
-		result = runProjectCmds(projectCmds, a.prjCmdRunner.Apply)
+	applied := make(map[string]bool)
+	for _, batch := range splitByDependencies(projectCmds) {
+		batch = withAppliedProjects(batch, applied)
+
+		// Only run commands in parallel if enabled
+		var batchResult command.Result
+		if a.isParallelEnabled(batch) {
+			ctx.Log.Info("Running applies in parallel")
+			batchResult = runProjectCmdsParallelGroups(ctx, batch, a.prjCmdRunner.Apply, a.parallelPoolSize)
+		} else {
+			batchResult = runProjectCmds(batch, a.prjCmdRunner.Apply)
+		}
+		for _, res := range batchResult.ProjectResults {
+			if res.ProjectName != "" && res.Error == nil && res.Failure == "" {
+				applied[res.ProjectName] = true
+			}
+		}
+		result.ProjectResults = append(result.ProjectResults, batchResult.ProjectResults...)
 	}
 	ctx.CommandHasErrors = result.HasErrors()
 
@@ -182,8 +308,37 @@ func (a *ApplyCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
 	a.updateCommitStatus(ctx, pullStatus)
 
 	if a.autoMerger.automergeEnabled(projectCmds) && !cmd.AutoMergeDisabled {
-		a.autoMerger.automerge(ctx, pullStatus, a.autoMerger.deleteSourceBranchOnMergeEnabled(projectCmds), cmd.AutoMergeMethod)
+		mergeMethod := cmd.AutoMergeMethod
+		if mergeMethod == "" {
+			mergeMethod = a.autoMerger.automergeMethod(projectCmds)
+		}
+		a.autoMerger.automerge(ctx, pullStatus, a.autoMerger.deleteSourceBranchOnMergeEnabled(projectCmds), mergeMethod)
+	}
+}
+
+// withAppliedProjects returns cmds with their pull status updated to show the
+// applied projects as applied. The pull status was loaded before the command
+// started so without this, projects that depend on projects applied earlier
+// in the same command would fail their dependency check.
+func withAppliedProjects(cmds []command.ProjectContext, applied map[string]bool) []command.ProjectContext {
+	if len(applied) == 0 {
+		return cmds
 	}
+	updated := make([]command.ProjectContext, len(cmds))
+	for i, cmd := range cmds {
+		if cmd.PullStatus != nil {
+			pullStatus := *cmd.PullStatus
+			pullStatus.Projects = slices.Clone(cmd.PullStatus.Projects)
+			for j, project := range pullStatus.Projects {
+				if applied[project.ProjectName] {
+					pullStatus.Projects[j].Status = models.AppliedPlanStatus
+				}
+			}
+			cmd.PullStatus = &pullStatus
+		}
+		updated[i] = cmd
+	}
+	return updated
 }
 
 func (a *ApplyCommandRunner) IsLocked() (bool, error) {
@@ -232,3 +387,10 @@ var applyAllDisabledComment = "**Error:** Running `atlantis apply` without flags
 
 // applyDisabledComment is posted when apply commands are disabled globally and an apply command is issued.
 var applyDisabledComment = "**Error:** Running `atlantis apply` is disabled."
+
+// asyncApplyStartedComment is posted when an apply is started in the background.
+var asyncApplyStartedComment = "Running apply for %d project(s) in the background. The results will be commented once they complete."
+
+// asyncApplyInProgressComment is posted when an apply is issued while a
+// background apply is still running for the same pull request.
+var asyncApplyInProgressComment = "**Error:** An apply is already running in the background for this pull request. Wait for its results before running `atlantis apply` again."
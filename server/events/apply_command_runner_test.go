@@ -557,3 +557,204 @@ func TestApplyCommandRunner_ExecutionOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestApplyCommandRunner_Async(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	RegisterMockTestingT(t)
+	vcsClient := setup(t, func(tc *TestConfig) {
+		tc.asyncApply = true
+	})
+
+	scopeNull := metricstest.NewLoggingScope(t, logger, "atlantis")
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	cmd := &events.CommentCommand{Name: command.Apply}
+	ctx := &command.Context{
+		User:     testdata.User,
+		Log:      logging.NewNoopLogger(t),
+		Scope:    scopeNull,
+		Pull:     modelPull,
+		HeadRepo: testdata.GithubRepo,
+		Trigger:  command.CommentTrigger,
+	}
+	// The command, ex. its post-workflow hooks, is only finished once the
+	// apply completed.
+	finished := make(chan struct{})
+	ctx.Detach = func() func() {
+		return func() { close(finished) }
+	}
+	projectCtx := command.ProjectContext{
+		CommandName: command.Apply,
+		ProjectName: "project",
+	}
+
+	release := make(chan struct{})
+	When(projectCommandBuilder.BuildApplyCommands(ctx, cmd)).ThenReturn([]command.ProjectContext{projectCtx}, nil)
+	When(projectCommandRunner.Apply(projectCtx)).Then(func(_ []Param) ReturnValues {
+		<-release
+		return ReturnValues{command.ProjectResult{
+			Command:      command.Apply,
+			ApplySuccess: "Great success!",
+		}}
+	})
+
+	applyCommandRunner.Run(ctx, cmd)
+
+	// Run returns before the apply completes and only the acknowledgement
+	// has been commented.
+	vcsClient.VerifyWasCalledOnce().CreateComment(
+		Any[logging.SimpleLogging](), Any[models.Repo](), Any[int](), Any[string](), Any[string]())
+	vcsClient.VerifyWasCalledOnce().CreateComment(
+		Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(modelPull.Num),
+		Eq("Running apply for 1 project(s) in the background. The results will be commented once they complete."), Eq("apply"))
+
+	// A second apply is rejected while the first is still running.
+	applyCommandRunner.Run(ctx, cmd)
+	vcsClient.VerifyWasCalledOnce().CreateComment(
+		Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(modelPull.Num),
+		Eq("**Error:** An apply is already running in the background for this pull request. Wait for its results before running `atlantis apply` again."), Eq("apply"))
+
+	select {
+	case <-finished:
+		t.Fatal("command finished before the apply completed")
+	default:
+	}
+
+	close(release)
+	drainer.ShutdownBlocking()
+	<-finished
+
+	projectCommandRunner.VerifyWasCalledOnce().Apply(projectCtx)
+	vcsClient.VerifyWasCalledOnce().CreateComment(
+		Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(modelPull.Num),
+		Eq("Ran Apply for dir: `` workspace: ``\n\n```diff\nGreat success!\n```"), Eq("apply"))
+}
+
+func TestApplyCommandRunner_DryRun(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	RegisterMockTestingT(t)
+	// Dry runs don't run in the background even if async applies are enabled.
+	vcsClient := setup(t, func(tc *TestConfig) {
+		tc.asyncApply = true
+	})
+
+	scopeNull := metricstest.NewLoggingScope(t, logger, "atlantis")
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	cmd := &events.CommentCommand{Name: command.Apply, DryRun: true}
+	ctx := &command.Context{
+		User:     testdata.User,
+		Log:      logging.NewNoopLogger(t),
+		Scope:    scopeNull,
+		Pull:     modelPull,
+		HeadRepo: testdata.GithubRepo,
+		Trigger:  command.CommentTrigger,
+	}
+	projectCtx := command.ProjectContext{
+		CommandName: command.Apply,
+		ApplyDryRun: true,
+	}
+
+	When(projectCommandBuilder.BuildApplyCommands(ctx, cmd)).ThenReturn([]command.ProjectContext{projectCtx}, nil)
+	When(projectCommandRunner.Apply(projectCtx)).ThenReturn(command.ProjectResult{
+		Command:      command.Apply,
+		ApplySuccess: "Dry run: the apply requirements are met, the plan wasn't applied.",
+	})
+
+	applyCommandRunner.Run(ctx, cmd)
+
+	projectCommandRunner.VerifyWasCalledOnce().Apply(projectCtx)
+	vcsClient.VerifyWasCalledOnce().CreateComment(
+		Any[logging.SimpleLogging](), Any[models.Repo](), Any[int](), Any[string](), Any[string]())
+	vcsClient.VerifyWasCalledOnce().CreateComment(
+		Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(modelPull.Num),
+		Eq("Ran Apply for dir: `` workspace: ``\n\n```diff\nDry run: the apply requirements are met, the plan wasn't applied.\n```"), Eq("apply"))
+	commitUpdater.VerifyWasCalled(Never()).UpdateCombinedCount(
+		Any[logging.SimpleLogging](),
+		Any[models.Repo](),
+		Any[models.PullRequest](),
+		Any[models.CommitStatus](),
+		Any[command.Name](),
+		Any[int](),
+		Any[int](),
+	)
+}
+
+func TestApplyCommandRunner_SingleProject(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	RegisterMockTestingT(t)
+	tmp := t.TempDir()
+	db, err := boltdb.New(tmp)
+	t.Cleanup(func() {
+		db.Close()
+	})
+	Ok(t, err)
+	vcsClient := setup(t, func(tc *TestConfig) {
+		tc.database = db
+	})
+
+	scopeNull := metricstest.NewLoggingScope(t, logger, "atlantis")
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	_, err = db.UpdatePullWithResults(modelPull, []command.ProjectResult{
+		{
+			Command:     command.Plan,
+			RepoRelDir:  "proj1",
+			Workspace:   "default",
+			ProjectName: "proj1",
+			PlanSuccess: &models.PlanSuccess{TerraformOutput: "Plan: 1 to add, 0 to change, 0 to destroy."},
+		},
+		{
+			Command:     command.Plan,
+			RepoRelDir:  "proj2",
+			Workspace:   "default",
+			ProjectName: "proj2",
+			PlanSuccess: &models.PlanSuccess{TerraformOutput: "Plan: 1 to add, 0 to change, 0 to destroy."},
+		},
+	})
+	Ok(t, err)
+
+	cmd := &events.CommentCommand{Name: command.Apply, ProjectName: "proj1"}
+	ctx := &command.Context{
+		User:     testdata.User,
+		Log:      logging.NewNoopLogger(t),
+		Scope:    scopeNull,
+		Pull:     modelPull,
+		HeadRepo: testdata.GithubRepo,
+		Trigger:  command.CommentTrigger,
+	}
+	projectCtx := command.ProjectContext{
+		CommandName:       command.Apply,
+		RepoRelDir:        "proj1",
+		Workspace:         "default",
+		ProjectName:       "proj1",
+		ProjectPlanStatus: models.PlannedPlanStatus,
+	}
+	When(projectCommandBuilder.BuildApplyCommands(ctx, cmd)).ThenReturn([]command.ProjectContext{projectCtx}, nil)
+	When(projectCommandRunner.Apply(projectCtx)).ThenReturn(command.ProjectResult{
+		Command:      command.Apply,
+		RepoRelDir:   "proj1",
+		Workspace:    "default",
+		ProjectName:  "proj1",
+		ApplySuccess: "Apply complete!",
+	})
+
+	applyCommandRunner.Run(ctx, cmd)
+
+	// Only the targeted project is applied and the other keeps its plan, so
+	// the apply status stays pending until it's applied too.
+	projectCommandRunner.VerifyWasCalledOnce().Apply(projectCtx)
+	vcsClient.VerifyWasCalledOnce().CreateComment(
+		Any[logging.SimpleLogging](), Any[models.Repo](), Any[int](), Any[string](), Any[string]())
+	commitUpdater.VerifyWasCalledOnce().UpdateCombinedCount(
+		Any[logging.SimpleLogging](),
+		Any[models.Repo](),
+		Any[models.PullRequest](),
+		Eq(models.PendingCommitStatus),
+		Eq(command.Apply),
+		Eq(1),
+		Eq(2),
+	)
+	pullStatus, err := db.GetPullStatus(modelPull)
+	Ok(t, err)
+	Equals(t, 2, len(pullStatus.Projects))
+	Equals(t, models.AppliedPlanStatus, pullStatus.Projects[0].Status)
+	Equals(t, models.PlannedPlanStatus, pullStatus.Projects[1].Status)
+}
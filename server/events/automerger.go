@@ -5,6 +5,7 @@ package events
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
@@ -26,7 +27,7 @@ func (c *AutoMerger) automerge(ctx *command.Context, pullStatus models.PullStatu
 	}
 
 	// Comment that we're automerging the pull request.
-	if err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, automergeComment, command.Apply.String()); err != nil {
+	if _, err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, automergeComment, command.Apply.String()); err != nil {
 		ctx.Log.Err("failed to comment about automerge: %s", err)
 		// Commenting isn't required so continue.
 	}
@@ -42,24 +43,40 @@ func (c *AutoMerger) automerge(ctx *command.Context, pullStatus models.PullStatu
 		ctx.Log.Err("automerging failed: %s", err)
 
 		failureComment := fmt.Sprintf("Automerging failed:\n```\n%s\n```", err)
-		if commentErr := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, failureComment, command.Apply.String()); commentErr != nil {
+		if _, commentErr := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, failureComment, command.Apply.String()); commentErr != nil {
 			ctx.Log.Err("failed to comment about automerge failing: %s", err)
 		}
 	}
 }
 
-// automergeEnabled returns true if automerging is enabled in this context.
+// automergeEnabled returns true if automerging is enabled in this context,
+// which is when any of its projects enables automerge.
 func (c *AutoMerger) automergeEnabled(projectCmds []command.ProjectContext) bool {
 	// Use project automerge settings if projects exist; otherwise, use global automerge settings.
-	automerge := c.GlobalAutomerge
-	if len(projectCmds) > 0 {
-		automerge = projectCmds[0].AutomergeEnabled
+	if len(projectCmds) == 0 {
+		return c.GlobalAutomerge
 	}
-	return automerge
+	return slices.ContainsFunc(projectCmds, func(projectCmd command.ProjectContext) bool {
+		return projectCmd.AutomergeEnabled
+	})
 }
 
-// deleteSourceBranchOnMergeEnabled returns true if we should delete the source branch on merge in this context.
+// automergeMethod returns the merge method of automerge set in the repo
+// config of projectCmds, or "" if it isn't set.
+func (c *AutoMerger) automergeMethod(projectCmds []command.ProjectContext) string {
+	for _, projectCmd := range projectCmds {
+		if projectCmd.AutomergeMethod != "" {
+			return projectCmd.AutomergeMethod
+		}
+	}
+	return ""
+}
+
+// deleteSourceBranchOnMergeEnabled returns true if we should delete the source
+// branch on merge in this context, which is when any of its projects sets
+// delete_source_branch_on_merge.
 func (c *AutoMerger) deleteSourceBranchOnMergeEnabled(projectCmds []command.ProjectContext) bool {
-	//check if this repo is configured for automerging.
-	return (len(projectCmds) > 0 && projectCmds[0].DeleteSourceBranchOnMerge)
+	return slices.ContainsFunc(projectCmds, func(projectCmd command.ProjectContext) bool {
+		return projectCmd.DeleteSourceBranchOnMerge
+	})
 }
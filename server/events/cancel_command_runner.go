@@ -0,0 +1,60 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+)
+
+// noRunningCommandsComment is commented when there's nothing to cancel.
+const noRunningCommandsComment = "There's no plan or apply running for this pull request to cancel."
+
+// canceledComment is commented after the running commands were canceled.
+const canceledComment = "Canceled the running %s for this pull request.\n\n" +
+	"Terraform is interrupted so it finishes the resource changes in progress and saves them to the state before it exits. " +
+	"Projects that didn't start yet are skipped. " +
+	"Changes that were already made aren't rolled back, plan again to see the changes that are left."
+
+func NewCancelCommandRunner(
+	vcsClient vcs.Client,
+	runningCommands *RunningCommands,
+) *CancelCommandRunner {
+	return &CancelCommandRunner{
+		vcsClient:       vcsClient,
+		runningCommands: runningCommands,
+	}
+}
+
+// CancelCommandRunner cancels the plans and applies running for a pull
+// request.
+type CancelCommandRunner struct {
+	vcsClient       vcs.Client
+	runningCommands *RunningCommands
+}
+
+func (c *CancelCommandRunner) Run(ctx *command.Context, _ *CommentCommand) {
+	var names []string
+	for _, name := range c.runningCommands.Cancel(ctx.Pull) {
+		if !slices.Contains(names, name.String()) {
+			names = append(names, name.String())
+		}
+	}
+
+	comment := noRunningCommandsComment
+	if len(names) > 0 {
+		ctx.Log.Info("canceled running %s", strings.Join(names, ", "))
+		comment = fmt.Sprintf(canceledComment, strings.Join(names, " and "))
+	} else {
+		ctx.Log.Info("no running commands to cancel")
+	}
+
+	if _, err := c.vcsClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, comment, command.Cancel.String()); err != nil {
+		ctx.Log.Err("unable to comment: %s", err)
+	}
+}
@@ -50,9 +50,23 @@ type Context struct {
 	// API is true if plan/apply by API endpoints
 	API bool
 
+	// RunID identifies a plan/apply run by the API endpoints.
+	RunID string
+
 	// TeamAllowlistChecker is used to check authorization on a project-level
 	TeamAllowlistChecker TeamAllowlistChecker
 
 	// Set true if there were any errors during the command execution
 	CommandHasErrors bool
+
+	// Canceled is closed when the command is canceled by the cancel command.
+	// It's nil if the command can't be canceled.
+	Canceled <-chan struct{}
+
+	// Detach is called by command runners that continue running the command
+	// in the background after they return, ex. async applies. The work left
+	// once the command completes, ex. running the post-workflow hooks, is
+	// then deferred to the returned func, which must be called when the
+	// background work finished. It's nil if the command can't be detached.
+	Detach func() (done func())
 }
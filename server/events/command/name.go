@@ -33,6 +33,10 @@ const (
 	Import
 	// State is a command to run terraform state rm
 	State
+	// Drift is a command to run terraform plan only to report drift.
+	Drift
+	// Cancel is a command to cancel the plans and applies running for a pull request.
+	Cancel
 	// Adding more? Don't forget to update String() below
 )
 
@@ -50,6 +54,8 @@ var AllCommentCommands = []Name{
 	ApprovePolicies,
 	Import,
 	State,
+	Drift,
+	Cancel,
 }
 
 // TitleString returns the string representation in title form.
@@ -77,6 +83,10 @@ func (c Name) String() string {
 		return "import"
 	case State:
 		return "state"
+	case Drift:
+		return "drift"
+	case Cancel:
+		return "cancel"
 	}
 	return ""
 }
@@ -152,6 +162,10 @@ func ParseCommandName(name string) (Name, error) {
 		return Import, nil
 	case "state":
 		return State, nil
+	case "drift":
+		return Drift, nil
+	case "cancel":
+		return Cancel, nil
 	}
 	return -1, fmt.Errorf("unknown command name: %s", name)
 }
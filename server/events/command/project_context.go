@@ -5,6 +5,8 @@ package command
 
 import (
 	"fmt"
+	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -35,12 +37,18 @@ type ProjectContext struct {
 	// ApplyRequirements is the list of requirements that must be satisfied
 	// before we will run the apply stage.
 	ApplyRequirements []string
+	// CustomApplyRequirements are the commands that must exit zero before we
+	// will run the apply stage.
+	CustomApplyRequirements []valid.CustomRequirement
 	// ImportRequirements is the list of requirements that must be satisfied
 	// before we will run the import stage.
 	ImportRequirements []string
-	// AutomergeEnabled is true if automerge is enabled for the repo that this
-	// project is in.
+	// AutomergeEnabled is true if automerge is enabled for this project or,
+	// if the project doesn't set it, for the repo that this project is in.
 	AutomergeEnabled bool
+	// AutomergeMethod is the merge method of automerge set in the repo
+	// config. The --auto-merge-method flag of apply takes precedence.
+	AutomergeMethod string
 	// ParallelApplyEnabled is true if parallel apply is enabled for this project.
 	ParallelApplyEnabled bool
 	// ParallelPlanEnabled is true if parallel plan is enabled for this project.
@@ -79,6 +87,9 @@ type ProjectContext struct {
 	PullStatus *models.PullStatus
 	// ProjectPolicyStatus is the status of policy sets of the current project prior to this command.
 	ProjectPolicyStatus []models.PolicySetStatus
+	// ProjectPlanStats are the resource changes of the current project's
+	// latest plan prior to this command. They're nil if it wasn't planned.
+	ProjectPlanStats *models.PlanSuccessStats
 
 	// Pull is the pull request we're responding to.
 	Pull models.PullRequest
@@ -96,6 +107,9 @@ type ProjectContext struct {
 	// Steps are the sequence of commands we need to run for this project and this
 	// stage.
 	Steps []valid.Step
+	// StageRetry is set if Steps should be re-run when they fail with certain
+	// output.
+	StageRetry *valid.StageRetry
 	// TerraformDistribution is the distribution of terraform we should use when
 	// executing commands for this project. This can be set to nil in which case
 	// we will use the default Atlantis terraform distribution.
@@ -108,6 +122,9 @@ type ProjectContext struct {
 	User models.User
 	// Verbose is true when the user would like verbose output.
 	Verbose bool
+	// TerraformLogPath is where Terraform writes its debug log when Verbose
+	// is set for a plan. If empty, TF_LOG isn't set.
+	TerraformLogPath string
 	// Workspace is the Terraform workspace this project is in. It will always
 	// be set.
 	Workspace string
@@ -128,14 +145,78 @@ type ProjectContext struct {
 	JobID string
 	// The index of order group. Before planning/applying it will use to sort projects. Default is 0.
 	ExecutionOrderGroup int
+	// ConfigOrder is the position of the project in the repo config starting
+	// at 1, or 0 if the project isn't configured there.
+	ConfigOrder int
 	// If plans/applies should be aborted if any prior plan/apply fails
 	AbortOnExecutionOrderFail bool
 	// Allows custom policy check tools outside of Conftest to run in checks
 	CustomPolicyCheck bool
 	SilencePRComments []string
+	// QuietPolicyChecks is whether the project's successful policy checks
+	// aren't commented.
+	QuietPolicyChecks bool
+	// NoChangesMessage is shown in comments instead of the generic summary
+	// when the plan has no changes.
+	NoChangesMessage string
+	// Labels are the project's labels from the repo config.
+	Labels map[string]string
+	// HidePrevPlanComments overrides the server's --hide-prev-plan-comments
+	// setting for this project. nil means the server setting is used.
+	HidePrevPlanComments *bool
+	// PlanFilePath is a template for the path of the plan file relative to
+	// the project's dir. If empty, the default plan file name is used.
+	PlanFilePath string
+	// InitUpgrade is true if init should run with -upgrade, either because the
+	// project sets init_upgrade or the comment used --upgrade.
+	InitUpgrade bool
+	// PlanDestroy is true if plan should run with -destroy because the
+	// comment used --destroy.
+	PlanDestroy bool
+	// PlanRefreshOnly is true if plan should run with -refresh-only because
+	// the comment used --refresh-only.
+	PlanRefreshOnly bool
+	// DestroyThreshold is the number of resources a plan can destroy before
+	// its comment warns about it. 0 disables the warning.
+	DestroyThreshold int
+	// DestroyThresholdApproval is true if applying a plan that destroys more
+	// resources than DestroyThreshold requires the pull request to be approved.
+	DestroyThresholdApproval bool
+	// WorkspaceTfvars is true if plan should use <workspace>.tfvars in the
+	// project's dir as a var file if it exists.
+	WorkspaceTfvars bool
+	// PushLockFile is true if the .terraform.lock.hcl updated by init should
+	// be pushed to the pull request's branch.
+	PushLockFile bool
+	// StepOutputDenylist fails the command if any step's output matches one
+	// of its regexes.
+	StepOutputDenylist []*regexp.Regexp
+	// StepOutputMasks are regexes whose matches are replaced with "***" in
+	// step output before it's streamed or commented.
+	StepOutputMasks []*regexp.Regexp
+	// AllowedRunCommands are the regexes the commands of run, env and
+	// multienv steps must fully match. If empty, all commands are allowed.
+	AllowedRunCommands []*regexp.Regexp
+	// ApplyDryRun is true if apply should only check the apply requirements
+	// and show the plan that would be applied, it's set by apply --dry-run.
+	ApplyDryRun bool
 
 	// TeamAllowlistChecker is used to check authorization on a project-level
 	TeamAllowlistChecker TeamAllowlistChecker
+
+	// Canceled is closed when the command is canceled by the cancel command.
+	// It's nil if the command can't be canceled.
+	Canceled <-chan struct{}
+}
+
+// IsCanceled returns true if the command was canceled by the cancel command.
+func (p ProjectContext) IsCanceled() bool {
+	select {
+	case <-p.Canceled:
+		return true
+	default:
+		return false
+	}
 }
 
 // SetProjectScopeTags adds ProjectContext tags to a new returned scope.
@@ -175,6 +256,45 @@ func (p ProjectContext) GetPolicyCheckResultFileName() string {
 	return fmt.Sprintf("%s-%s-policyout.json", projName, p.Workspace)
 }
 
+// MaskOutput returns out with everything matching one of StepOutputMasks
+// replaced with "***". The matches of all the masks are found in the original
+// output before replacing so masks that overlap still hide the whole secret,
+// and overlapping or adjacent matches are replaced with a single "***".
+func (p ProjectContext) MaskOutput(out string) string {
+	if len(p.StepOutputMasks) == 0 || out == "" {
+		return out
+	}
+	var matches [][]int
+	for _, mask := range p.StepOutputMasks {
+		matches = append(matches, mask.FindAllStringIndex(out, -1)...)
+	}
+	if len(matches) == 0 {
+		return out
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i][0] < matches[j][0]
+	})
+
+	var masked strings.Builder
+	masked.Grow(len(out))
+	written := 0
+	for i := 0; i < len(matches); {
+		start, end := matches[i][0], matches[i][1]
+		for i++; i < len(matches) && matches[i][0] <= end; i++ {
+			end = max(end, matches[i][1])
+		}
+		// Masks that match the empty string don't hide anything.
+		if start == end {
+			continue
+		}
+		masked.WriteString(out[written:start])
+		masked.WriteString("***")
+		written = end
+	}
+	masked.WriteString(out[written:])
+	return masked.String()
+}
+
 // Gets a unique identifier for the current pull request as a single string
 func (p ProjectContext) PullInfo() string {
 	normalizedOwner := strings.ReplaceAll(p.BaseRepo.Owner, "/", "-")
@@ -207,7 +327,7 @@ func (p ProjectContext) PolicyCleared() bool {
 		}
 		for _, psCfg := range p.PolicySets.PolicySets {
 			if psStatus.PolicySetName == psCfg.Name {
-				if psStatus.Approvals != psCfg.ApproveCount {
+				if !psCfg.SoftFail && psStatus.Approvals != psCfg.ApproveCount {
 					passing = false
 				}
 			}
@@ -21,8 +21,27 @@ type ProjectResult struct {
 	VersionSuccess     string
 	ImportSuccess      *models.ImportSuccess
 	StateRmSuccess     *models.StateRmSuccess
+	DriftSuccess       *models.DriftSuccess
 	ProjectName        string
 	SilencePRComments  []string
+	// QuietPolicyChecks is whether a successful policy check isn't
+	// commented.
+	QuietPolicyChecks bool
+	// NoChangesMessage is shown in comments instead of the generic summary
+	// when the plan has no changes.
+	NoChangesMessage string
+	// Labels are the project's labels from the repo config so comments can
+	// render them.
+	Labels map[string]string
+	// HidePrevPlanComments overrides the server's --hide-prev-plan-comments
+	// setting for this project. nil means the server setting is used.
+	HidePrevPlanComments *bool
+	// TerraformDebugLog is the tail of Terraform's debug log. It's only set
+	// for verbose plans.
+	TerraformDebugLog string
+	// JobURL links to the job streaming the project's output, if there is
+	// one.
+	JobURL string
 }
 
 // CommitStatus returns the vcs commit status of this project result.
@@ -84,7 +103,31 @@ func (p ProjectResult) PlanStatus() models.ProjectPlanStatus {
 	panic("PlanStatus() missing a combination")
 }
 
+// PlanStats returns the stats of the plan if this is the result of a
+// successful plan, otherwise nil.
+func (p ProjectResult) PlanStats() *models.PlanSuccessStats {
+	if p.Command != Plan || p.PlanSuccess == nil {
+		return nil
+	}
+	stats := p.PlanSuccess.Stats()
+	return &stats
+}
+
+// CreatedWorkspace returns the workspace that was created for the plan if
+// this is the result of a successful plan that created it, otherwise nil.
+func (p ProjectResult) CreatedWorkspace() *models.CreatedWorkspace {
+	if p.Command != Plan || p.PlanSuccess == nil || p.PlanSuccess.CreatedWorkspace == "" {
+		return nil
+	}
+	return &models.CreatedWorkspace{
+		RepoRelDir:            p.RepoRelDir,
+		Workspace:             p.PlanSuccess.CreatedWorkspace,
+		TerraformVersion:      p.PlanSuccess.TerraformVersion,
+		TerraformDistribution: p.PlanSuccess.TerraformDistribution,
+	}
+}
+
 // IsSuccessful returns true if this project result had no errors.
 func (p ProjectResult) IsSuccessful() bool {
-	return p.PlanSuccess != nil || (p.PolicyCheckResults != nil && p.Error == nil && p.Failure == "") || p.ApplySuccess != ""
+	return p.PlanSuccess != nil || (p.PolicyCheckResults != nil && p.Error == nil && p.Failure == "") || p.ApplySuccess != "" || p.DriftSuccess != nil
 }
@@ -5,11 +5,15 @@ package events
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
 
+	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/config/raw"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
 )
 
 //go:generate pegomock generate --package mocks -o mocks/mock_command_requirement_handler.go CommandRequirementHandler
@@ -20,8 +24,25 @@ type CommandRequirementHandler interface {
 	ValidateImportProject(repoDir string, ctx command.ProjectContext) (string, error)
 }
 
+//go:generate pegomock generate --package mocks -o mocks/mock_pipeline_status_getter.go PipelineStatusGetter
+
+// PipelineStatusGetter gets the status of a pull request's latest CI
+// pipeline without the commit statuses Atlantis sets, which are named
+// vcsStatusName/.... It's implemented by the GitLab client.
+type PipelineStatusGetter interface {
+	GetPipelineStatus(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, vcsStatusName string) (models.PipelineStatus, error)
+}
+
 type DefaultCommandRequirementHandler struct {
 	WorkingDir WorkingDir
+	// CustomRequirementRunner runs the commands of custom requirements.
+	CustomRequirementRunner CustomStepRunner
+	// GitlabPipelineStatusGetter gets the pipeline status for the
+	// pipeline_success requirement. It's nil if GitLab isn't configured.
+	GitlabPipelineStatusGetter PipelineStatusGetter
+	// VCSStatusName is the prefix of Atlantis's commit statuses, which the
+	// pipeline_success requirement ignores.
+	VCSStatusName string
 }
 
 func (a *DefaultCommandRequirementHandler) ValidateProjectDependencies(ctx command.ProjectContext) (failure string, err error) {
@@ -43,7 +64,16 @@ func (a *DefaultCommandRequirementHandler) ValidatePlanProject(repoDir string, c
 }
 
 func (a *DefaultCommandRequirementHandler) ValidateApplyProject(repoDir string, ctx command.ProjectContext) (failure string, err error) {
-	return a.validateCommandRequirement(repoDir, ctx, command.Apply, ctx.ApplyRequirements)
+	failure, err = a.validateCommandRequirement(repoDir, ctx, command.Apply, ctx.ApplyRequirements)
+	if failure != "" || err != nil {
+		return failure, err
+	}
+	for _, req := range ctx.CustomApplyRequirements {
+		if failure := a.validateCustomRequirement(repoDir, ctx, command.Apply, req); failure != "" {
+			return failure, nil
+		}
+	}
+	return validateDestroyThreshold(ctx), nil
 }
 
 func (a *DefaultCommandRequirementHandler) ValidateImportProject(repoDir string, ctx command.ProjectContext) (failure string, err error) {
@@ -73,10 +103,89 @@ func (a *DefaultCommandRequirementHandler) validateCommandRequirement(repoDir st
 			}
 		case raw.UnDivergedRequirement:
 			if a.WorkingDir.HasDiverged(ctx.Log, repoDir) {
-				return fmt.Sprintf("Default branch must be rebased onto pull request before running %s.", cmd), nil
+				failure := fmt.Sprintf("Default branch must be rebased onto pull request before running %s.", cmd)
+				if cmd == command.Apply {
+					// The plan was generated against an older base so it
+					// needs to be regenerated, not just applied again.
+					failure += " The base branch has changed since the plan was generated, run plan again to update it."
+				}
+				return failure, nil
+			}
+		case raw.PipelineSuccessRequirement:
+			failure, err := a.validatePipelineSuccess(ctx, cmd)
+			if failure != "" || err != nil {
+				return failure, err
+			}
+		case raw.ExternalApprovalRequirement:
+			if !isExternallyApproved(ctx) {
+				return fmt.Sprintf("Pull request must be approved by an external approval system before running %s.", cmd), nil
 			}
 		}
 	}
 	// Passed all requirements configured.
 	return "", nil
 }
+
+// isExternallyApproved returns true if an external approval of the pull
+// request's head commit covers the project.
+func isExternallyApproved(ctx command.ProjectContext) bool {
+	if ctx.PullStatus == nil {
+		return false
+	}
+	for _, approval := range ctx.PullStatus.ExternalApprovals {
+		if approval.Approves(ctx.Pull.HeadCommit, ctx.ProjectName) {
+			return true
+		}
+	}
+	return false
+}
+
+// validatePipelineSuccess returns a failure if the latest pipeline of a
+// GitLab merge request didn't succeed. Pull requests of other VCS hosts
+// always pass.
+func (a *DefaultCommandRequirementHandler) validatePipelineSuccess(ctx command.ProjectContext, cmd command.Name) (string, error) {
+	if ctx.Pull.BaseRepo.VCSHost.Type != models.Gitlab || a.GitlabPipelineStatusGetter == nil {
+		return "", nil
+	}
+	status, err := a.GitlabPipelineStatusGetter.GetPipelineStatus(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull, a.VCSStatusName)
+	if err != nil {
+		return "", errors.Wrap(err, "fetching pipeline status")
+	}
+	switch status.Status {
+	case "success":
+		return "", nil
+	case "":
+		return fmt.Sprintf("The merge request's pipeline must succeed before running %s, but it has no pipeline.", cmd), nil
+	default:
+		return fmt.Sprintf("The merge request's pipeline must succeed before running %s, its latest [pipeline](%s) is %s.", cmd, status.URL, status.Status), nil
+	}
+}
+
+// validateDestroyThreshold returns a failure if the project sets
+// destroy_threshold_approval, its plan destroys more resources than its
+// destroy_threshold and the pull request isn't approved. If the plan's stats
+// are unknown, ex. for plans made by older versions, approval is required.
+func validateDestroyThreshold(ctx command.ProjectContext) string {
+	if !ctx.DestroyThresholdApproval || ctx.DestroyThreshold == 0 || ctx.PullReqStatus.ApprovalStatus.IsApproved {
+		return ""
+	}
+	if ctx.ProjectPlanStats == nil {
+		return fmt.Sprintf("Pull request must be approved according to the project's approval rules before running %s since the number of resources the plan destroys is unknown and the project sets a destroy_threshold of %d.", command.Apply, ctx.DestroyThreshold)
+	}
+	if ctx.ProjectPlanStats.Destroy <= ctx.DestroyThreshold {
+		return ""
+	}
+	return fmt.Sprintf("Pull request must be approved according to the project's approval rules before running %s since the plan destroys %d resources, more than the project's destroy_threshold of %d.", command.Apply, ctx.ProjectPlanStats.Destroy, ctx.DestroyThreshold)
+}
+
+// validateCustomRequirement runs the command of req in the project directory
+// with the same environment as run steps and returns a failure with its
+// output if it doesn't exit zero.
+func (a *DefaultCommandRequirementHandler) validateCustomRequirement(repoDir string, ctx command.ProjectContext, cmd command.Name, req valid.CustomRequirement) string {
+	path := filepath.Join(repoDir, ctx.RepoRelDir)
+	_, err := a.CustomRequirementRunner.Run(ctx, nil, req.Command, path, map[string]string{}, false, nil, nil)
+	if err == nil {
+		return ""
+	}
+	return fmt.Sprintf("Custom requirement %q must pass before running %s:\n```\n%s\n```", req.Command, cmd, strings.TrimSpace(err.Error()))
+}
@@ -4,7 +4,9 @@
 package events_test
 
 import (
+	"errors"
 	"fmt"
+	"regexp"
 	"testing"
 
 	. "github.com/petergtz/pegomock/v4"
@@ -208,7 +210,111 @@ func TestAggregateApplyRequirements_ValidateApplyProject(t *testing.T) {
 			setup: func(workingDir *mocks.MockWorkingDir) {
 				When(workingDir.HasDiverged(Any[logging.SimpleLogging](), Any[string]())).ThenReturn(true)
 			},
-			wantFailure: "Default branch must be rebased onto pull request before running apply.",
+			wantFailure: "Default branch must be rebased onto pull request before running apply. The base branch has changed since the plan was generated, run plan again to update it.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "fail by destroy threshold not approved",
+			ctx: command.ProjectContext{
+				DestroyThreshold:         5,
+				DestroyThresholdApproval: true,
+				ProjectPlanStats:         &models.PlanSuccessStats{Destroy: 6},
+			},
+			wantFailure: "Pull request must be approved according to the project's approval rules before running apply since the plan destroys 6 resources, more than the project's destroy_threshold of 5.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "pass destroy threshold approved",
+			ctx: command.ProjectContext{
+				DestroyThreshold:         5,
+				DestroyThresholdApproval: true,
+				ProjectPlanStats:         &models.PlanSuccessStats{Destroy: 6},
+				PullReqStatus: models.PullReqStatus{
+					ApprovalStatus: models.ApprovalStatus{IsApproved: true},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "fail by destroy threshold with unknown plan stats",
+			ctx: command.ProjectContext{
+				DestroyThreshold:         5,
+				DestroyThresholdApproval: true,
+			},
+			wantFailure: "Pull request must be approved according to the project's approval rules before running apply since the number of resources the plan destroys is unknown and the project sets a destroy_threshold of 5.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "pass destroy threshold with unknown plan stats approved",
+			ctx: command.ProjectContext{
+				DestroyThreshold:         5,
+				DestroyThresholdApproval: true,
+				PullReqStatus: models.PullReqStatus{
+					ApprovalStatus: models.ApprovalStatus{IsApproved: true},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "pass destroy threshold not exceeded",
+			ctx: command.ProjectContext{
+				DestroyThreshold:         5,
+				DestroyThresholdApproval: true,
+				ProjectPlanStats:         &models.PlanSuccessStats{Destroy: 5},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "pass destroy threshold without approval",
+			ctx: command.ProjectContext{
+				DestroyThreshold: 5,
+				ProjectPlanStats: &models.PlanSuccessStats{Destroy: 6},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "pass externally approved",
+			ctx: command.ProjectContext{
+				ApplyRequirements: []string{raw.ExternalApprovalRequirement},
+				ProjectName:       "prod",
+				Pull:              models.PullRequest{HeadCommit: "sha"},
+				PullStatus: &models.PullStatus{
+					ExternalApprovals: []models.ExternalApproval{{HeadCommit: "sha", Projects: []string{"prod"}}},
+				},
+			},
+			wantErr: assert.NoError,
+		},
+		{
+			name: "fail by external approval of other commit",
+			ctx: command.ProjectContext{
+				ApplyRequirements: []string{raw.ExternalApprovalRequirement},
+				Pull:              models.PullRequest{HeadCommit: "sha"},
+				PullStatus: &models.PullStatus{
+					ExternalApprovals: []models.ExternalApproval{{HeadCommit: "oldsha"}},
+				},
+			},
+			wantFailure: "Pull request must be approved by an external approval system before running apply.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "fail by external approval of other project",
+			ctx: command.ProjectContext{
+				ApplyRequirements: []string{raw.ExternalApprovalRequirement},
+				ProjectName:       "prod",
+				Pull:              models.PullRequest{HeadCommit: "sha"},
+				PullStatus: &models.PullStatus{
+					ExternalApprovals: []models.ExternalApproval{{HeadCommit: "sha", Projects: []string{"staging"}}},
+				},
+			},
+			wantFailure: "Pull request must be approved by an external approval system before running apply.",
+			wantErr:     assert.NoError,
+		},
+		{
+			name: "fail by no external approval",
+			ctx: command.ProjectContext{
+				ApplyRequirements: []string{raw.ExternalApprovalRequirement},
+			},
+			wantFailure: "Pull request must be approved by an external approval system before running apply.",
 			wantErr:     assert.NoError,
 		},
 	}
@@ -229,6 +335,110 @@ func TestAggregateApplyRequirements_ValidateApplyProject(t *testing.T) {
 	}
 }
 
+func TestAggregateApplyRequirements_ValidateApplyProject_Custom(t *testing.T) {
+	tests := []struct {
+		name        string
+		runErr      error
+		wantFailure string
+	}{
+		{
+			name: "pass custom requirement",
+		},
+		{
+			name:        "fail custom requirement",
+			runErr:      errors.New("exit status 1: running \"./check-ticket.sh\" in \"repoDir/project\": \nno approved ticket found\n"),
+			wantFailure: "Custom requirement \"./check-ticket.sh\" must pass before running apply:\n```\nexit status 1: running \"./check-ticket.sh\" in \"repoDir/project\": \nno approved ticket found\n```",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			runner := mocks.NewMockCustomStepRunner()
+			When(runner.Run(Any[command.ProjectContext](), Any[*valid.CommandShell](), Eq("./check-ticket.sh"), Eq("repoDir/project"), Any[map[string]string](), Eq(false), Any[[]valid.PostProcessRunOutputOption](), Any[[]*regexp.Regexp]())).
+				ThenReturn("", tt.runErr)
+			a := &events.DefaultCommandRequirementHandler{
+				WorkingDir:              mocks.NewMockWorkingDir(),
+				CustomRequirementRunner: runner,
+			}
+			ctx := command.ProjectContext{
+				RepoRelDir:              "project",
+				CustomApplyRequirements: []valid.CustomRequirement{{Command: "./check-ticket.sh"}},
+			}
+			gotFailure, err := a.ValidateApplyProject("repoDir", ctx)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantFailure, gotFailure)
+		})
+	}
+}
+
+func TestAggregateApplyRequirements_ValidateApplyProject_PipelineSuccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		vcsHost     models.VCSHostType
+		status      models.PipelineStatus
+		statusErr   error
+		wantFailure string
+		wantErr     string
+	}{
+		{
+			name:    "pass pipeline succeeded",
+			vcsHost: models.Gitlab,
+			status:  models.PipelineStatus{Status: "success", URL: "https://gitlab.com/pipelines/1"},
+		},
+		{
+			name:        "fail pipeline running",
+			vcsHost:     models.Gitlab,
+			status:      models.PipelineStatus{Status: "running", URL: "https://gitlab.com/pipelines/1"},
+			wantFailure: "The merge request's pipeline must succeed before running apply, its latest [pipeline](https://gitlab.com/pipelines/1) is running.",
+		},
+		{
+			name:        "fail no pipeline",
+			vcsHost:     models.Gitlab,
+			wantFailure: "The merge request's pipeline must succeed before running apply, but it has no pipeline.",
+		},
+		{
+			name:      "error fetching pipeline",
+			vcsHost:   models.Gitlab,
+			statusErr: errors.New("not found"),
+			wantErr:   "fetching pipeline status: not found",
+		},
+		{
+			name:    "pass other vcs",
+			vcsHost: models.Github,
+			status:  models.PipelineStatus{Status: "failed"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			getter := mocks.NewMockPipelineStatusGetter()
+			When(getter.GetPipelineStatus(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](), Eq("atlantis"))).
+				ThenReturn(tt.status, tt.statusErr)
+			a := &events.DefaultCommandRequirementHandler{
+				WorkingDir:                 mocks.NewMockWorkingDir(),
+				GitlabPipelineStatusGetter: getter,
+				VCSStatusName:              "atlantis",
+			}
+			ctx := command.ProjectContext{
+				Pull: models.PullRequest{
+					BaseRepo: models.Repo{VCSHost: models.VCSHost{Type: tt.vcsHost}},
+				},
+				ApplyRequirements: []string{raw.PipelineSuccessRequirement},
+			}
+			gotFailure, err := a.ValidateApplyProject("repoDir", ctx)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantFailure, gotFailure)
+			if tt.vcsHost != models.Gitlab {
+				getter.VerifyWasCalled(Never()).GetPipelineStatus(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](), Any[string]())
+			}
+		})
+	}
+}
+
 func TestRequirements_ValidateProjectDependencies(t *testing.T) {
 	tests := []struct {
 		name        string
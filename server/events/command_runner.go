@@ -21,6 +21,7 @@ import (
 	"github.com/google/go-github/v71/github"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/runtime"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
@@ -133,12 +134,15 @@ type DefaultCommandRunner struct {
 	TeamAllowlistChecker           command.TeamAllowlistChecker          `validate:"required"`
 	VarFileAllowlistChecker        *VarFileAllowlistChecker              `validate:"required"`
 	CommitStatusUpdater            CommitStatusUpdater                   `validate:"required"`
+	// RunningCommands tracks the running plans and applies so they can be
+	// canceled. If nil, they can't be canceled.
+	RunningCommands *RunningCommands
 }
 
 // RunAutoplanCommand runs plan and policy_checks when a pull request is opened or updated.
 func (c *DefaultCommandRunner) RunAutoplanCommand(baseRepo models.Repo, headRepo models.Repo, pull models.PullRequest, user models.User) {
 	if opStarted := c.Drainer.StartOp(); !opStarted {
-		if commentErr := c.VCSClient.CreateComment(c.Logger, baseRepo, pull.Num, ShutdownComment, command.Plan.String()); commentErr != nil {
+		if _, commentErr := c.VCSClient.CreateComment(c.Logger, baseRepo, pull.Num, ShutdownComment, command.Plan.String()); commentErr != nil {
 			c.Logger.Log(logging.Error, "unable to comment that Atlantis is shutting down: %s", commentErr)
 		}
 		return
@@ -218,13 +222,16 @@ func (c *DefaultCommandRunner) RunAutoplanCommand(baseRepo models.Repo, headRepo
 
 	preWorkflowHooksErr := c.PreWorkflowHooksCommandRunner.RunPreHooks(ctx, cmd)
 
+	if c.abortedByPreWorkflowHook(ctx, command.Plan, preWorkflowHooksErr) {
+		return
+	}
 	if preWorkflowHooksErr != nil {
 		if c.FailOnPreWorkflowHookError {
 			ctx.Log.Err("'fail-on-pre-workflow-hook-error' set, so not running %s command.", command.Plan)
 
 			// Create comment on pull request about the pre-workflow hook failure
 			errMsg := fmt.Sprintf("```\nError: Pre-workflow hook failed: %s\n```", preWorkflowHooksErr.Error())
-			if err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, errMsg, ""); err != nil {
+			if _, err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, errMsg, ""); err != nil {
 				ctx.Log.Warn("Unable to create comment about pre-workflow hook failure: %s", err)
 			}
 
@@ -248,16 +255,45 @@ func (c *DefaultCommandRunner) RunAutoplanCommand(baseRepo models.Repo, headRepo
 
 	autoPlanRunner := buildCommentCommandRunner(c, command.Plan)
 
-	autoPlanRunner.Run(ctx, nil)
+	c.runCommand(ctx, cmd, command.Plan, func() {
+		autoPlanRunner.Run(ctx, nil)
+	})
+}
+
+// abortedByPreWorkflowHook returns true if err is from a pre-workflow hook
+// that aborted the command. The hook's message is commented on the pull
+// request and the command's commit status is set to failed. Hooks that abort
+// always stop the command, even if --fail-on-pre-workflow-hook-error isn't
+// set.
+func (c *DefaultCommandRunner) abortedByPreWorkflowHook(ctx *command.Context, cmdName command.Name, err error) bool {
+	var abortErr *runtime.PreWorkflowHookAbortError
+	if !errors.As(err, &abortErr) {
+		return false
+	}
+	ctx.Log.Info("%s, so not running %s command", abortErr.Error(), cmdName.String())
 
-	c.PostWorkflowHooksCommandRunner.RunPostHooks(ctx, cmd) // nolint: errcheck
+	comment := fmt.Sprintf("**%s Aborted**: %s aborted the command.", cmdName.TitleString(), abortErr.Hook)
+	if abortErr.Message != "" {
+		comment += "\n\n" + abortErr.Message
+	}
+	if _, err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, comment, cmdName.String()); err != nil {
+		ctx.Log.Warn("unable to comment about the aborted command: %s", err)
+	}
+
+	switch cmdName {
+	case command.Plan, command.Apply:
+		if err := c.CommitStatusUpdater.UpdateCombined(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull, models.FailedCommitStatus, cmdName); err != nil {
+			ctx.Log.Warn("unable to update %s commit status: %s", cmdName.String(), err)
+		}
+	}
+	return true
 }
 
 // commentUserDoesNotHavePermissions comments on the pull request that the user
 // is not allowed to execute the command.
 func (c *DefaultCommandRunner) commentUserDoesNotHavePermissions(baseRepo models.Repo, pullNum int, user models.User, cmd *CommentCommand) {
 	errMsg := fmt.Sprintf("```\nError: User @%s does not have permissions to execute '%s' command.\n```", user.Username, cmd.Name.String())
-	if err := c.VCSClient.CreateComment(c.Logger, baseRepo, pullNum, errMsg, ""); err != nil {
+	if _, err := c.VCSClient.CreateComment(c.Logger, baseRepo, pullNum, errMsg, ""); err != nil {
 		c.Logger.Err("unable to comment on pull request: %s", err)
 	}
 }
@@ -300,7 +336,7 @@ func (c *DefaultCommandRunner) checkVarFilesInPlanCommandAllowlisted(cmd *Commen
 // wasteful) call to get the necessary data.
 func (c *DefaultCommandRunner) RunCommentCommand(baseRepo models.Repo, maybeHeadRepo *models.Repo, maybePull *models.PullRequest, user models.User, pullNum int, cmd *CommentCommand) {
 	if opStarted := c.Drainer.StartOp(); !opStarted {
-		if commentErr := c.VCSClient.CreateComment(c.Logger, baseRepo, pullNum, ShutdownComment, ""); commentErr != nil {
+		if _, commentErr := c.VCSClient.CreateComment(c.Logger, baseRepo, pullNum, ShutdownComment, ""); commentErr != nil {
 			c.Logger.Log(logging.Error, "unable to comment that Atlantis is shutting down: %s", commentErr)
 		}
 		return
@@ -340,7 +376,7 @@ func (c *DefaultCommandRunner) RunCommentCommand(baseRepo models.Repo, maybeHead
 	// Check if the provided var files in a 'plan' command are allowlisted
 	if err := c.checkVarFilesInPlanCommandAllowlisted(cmd); err != nil {
 		errMsg := fmt.Sprintf("```\n%s\n```", err.Error())
-		if commentErr := c.VCSClient.CreateComment(c.Logger, baseRepo, pullNum, errMsg, ""); commentErr != nil {
+		if _, commentErr := c.VCSClient.CreateComment(c.Logger, baseRepo, pullNum, errMsg, ""); commentErr != nil {
 			c.Logger.Err("unable to comment on pull request: %s", commentErr)
 		}
 		return
@@ -374,6 +410,13 @@ func (c *DefaultCommandRunner) RunCommentCommand(baseRepo models.Repo, maybeHead
 		return
 	}
 
+	// Canceling doesn't run the workflow hooks since they'd wait for the
+	// working dir lock of the command that's canceled.
+	if cmd.Name == command.Cancel {
+		buildCommentCommandRunner(c, cmd.CommandName()).Run(ctx, cmd)
+		return
+	}
+
 	// Only set pending status if silence is not enabled
 	// The command runners will handle the final status decision based on project results
 	if !c.SilenceVCSStatusNoProjects {
@@ -394,13 +437,16 @@ func (c *DefaultCommandRunner) RunCommentCommand(baseRepo models.Repo, maybeHead
 
 	preWorkflowHooksErr := c.PreWorkflowHooksCommandRunner.RunPreHooks(ctx, cmd)
 
+	if c.abortedByPreWorkflowHook(ctx, cmd.Name, preWorkflowHooksErr) {
+		return
+	}
 	if preWorkflowHooksErr != nil {
 		if c.FailOnPreWorkflowHookError {
 			ctx.Log.Err("'fail-on-pre-workflow-hook-error' set, so not running %s command.", cmd.Name.String())
 
 			// Create comment on pull request about the pre-workflow hook failure
 			errMsg := fmt.Sprintf("```\nError: Pre-workflow hook failed: %s\n```", preWorkflowHooksErr.Error())
-			if err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, errMsg, ""); err != nil {
+			if _, err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, errMsg, ""); err != nil {
 				ctx.Log.Warn("Unable to create comment about pre-workflow hook failure: %s", err)
 			}
 
@@ -424,9 +470,59 @@ func (c *DefaultCommandRunner) RunCommentCommand(baseRepo models.Repo, maybeHead
 
 	cmdRunner := buildCommentCommandRunner(c, cmd.CommandName())
 
-	cmdRunner.Run(ctx, cmd)
+	c.runCommand(ctx, cmd, cmd.Name, func() {
+		cmdRunner.Run(ctx, cmd)
+	})
+}
+
+// runCommand calls run for the cmdName command and then runs the
+// post-workflow hooks of cmd. Plans and applies can be canceled until they
+// complete. If run detaches the command to continue in the background, it
+// stays cancelable and the hooks are run once it completes instead so they
+// see its results. Dry runs don't change anything so they don't run the hooks.
+func (c *DefaultCommandRunner) runCommand(ctx *command.Context, cmd *CommentCommand, cmdName command.Name, run func()) {
+	finish := func() {}
+	if cmdName == command.Plan || cmdName == command.Apply {
+		finish = c.startCancelable(ctx, cmdName)
+	}
+	postHooks := func() {
+		if cmd != nil && cmd.DryRun {
+			return
+		}
+		c.PostWorkflowHooksCommandRunner.RunPostHooks(ctx, cmd) // nolint: errcheck
+	}
+
+	detached := false
+	ctx.Detach = func() func() {
+		detached = true
+		return func() {
+			finish()
+			postHooks()
+		}
+	}
+	// The command is finished even if run panics so it isn't left running.
+	defer func() {
+		if !detached {
+			finish()
+		}
+	}()
+	run()
+	if !detached {
+		finish()
+		postHooks()
+	}
+}
 
-	c.PostWorkflowHooksCommandRunner.RunPostHooks(ctx, cmd) // nolint: errcheck
+// startCancelable records that the cmdName command of ctx is running so the
+// cancel command can cancel it. The returned func must be called once the
+// command finished.
+func (c *DefaultCommandRunner) startCancelable(ctx *command.Context, cmdName command.Name) func() {
+	if c.RunningCommands == nil {
+		return func() {}
+	}
+	var finish func()
+	ctx.Canceled, finish = c.RunningCommands.Start(ctx.Pull, cmdName)
+	return finish
 }
 
 func (c *DefaultCommandRunner) getGithubData(logger logging.SimpleLogging, baseRepo models.Repo, pullNum int) (models.PullRequest, models.Repo, error) {
@@ -527,7 +623,7 @@ func (c *DefaultCommandRunner) ensureValidRepoMetadata(
 
 	if err != nil {
 		log.Err(err.Error())
-		if commentErr := c.VCSClient.CreateComment(c.Logger, baseRepo, pullNum, fmt.Sprintf("`Error: %s`", err), ""); commentErr != nil {
+		if _, commentErr := c.VCSClient.CreateComment(c.Logger, baseRepo, pullNum, fmt.Sprintf("`Error: %s`", err), ""); commentErr != nil {
 			log.Err("unable to comment: %s", commentErr)
 		}
 	}
@@ -551,7 +647,7 @@ func (c *DefaultCommandRunner) validateCtxAndComment(ctx *command.Context, comma
 			return false
 		}
 		ctx.Log.Info("command was run on a fork pull request which is disallowed")
-		if err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, fmt.Sprintf("Atlantis commands can't be run on fork pull requests. To enable, set --%s  or, to disable this message, set --%s", c.AllowForkPRsFlag, c.SilenceForkPRErrorsFlag), ""); err != nil {
+		if _, err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, fmt.Sprintf("Atlantis commands can't be run on fork pull requests. To enable, set --%s  or, to disable this message, set --%s", c.AllowForkPRsFlag, c.SilenceForkPRErrorsFlag), ""); err != nil {
 			ctx.Log.Err("unable to comment: %s", err)
 		}
 		return false
@@ -559,7 +655,7 @@ func (c *DefaultCommandRunner) validateCtxAndComment(ctx *command.Context, comma
 
 	if ctx.Pull.State != models.OpenPullState && commandName != command.Unlock {
 		ctx.Log.Info("command was run on closed pull request")
-		if err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, "Atlantis commands can't be run on closed pull requests", ""); err != nil {
+		if _, err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, "Atlantis commands can't be run on closed pull requests", ""); err != nil {
 			ctx.Log.Err("unable to comment: %s", err)
 		}
 		return false
@@ -579,7 +675,7 @@ func (c *DefaultCommandRunner) logPanics(baseRepo models.Repo, pullNum int, logg
 	if err := recover(); err != nil {
 		stack := recovery.Stack(3)
 		logger.Err("PANIC: %s\n%s", err, stack)
-		if commentErr := c.VCSClient.CreateComment(
+		if _, commentErr := c.VCSClient.CreateComment(
 			logger,
 			baseRepo,
 			pullNum,
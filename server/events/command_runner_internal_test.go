@@ -303,3 +303,23 @@ func (m *MockCSU) UpdatePreWorkflowHook(_ logging.SimpleLogging, _ models.PullRe
 func (m *MockCSU) UpdatePostWorkflowHook(_ logging.SimpleLogging, _ models.PullRequest, _ models.CommitStatus, _ string, _ string, _ string) error {
 	return nil
 }
+
+func TestPlanCommandRunner_ParallelPlanLimit(t *testing.T) {
+	cmds := []command.ProjectContext{{ParallelPlanEnabled: true}}
+	cases := map[string]struct {
+		limit       int
+		expParallel bool
+		expPoolSize int
+	}{
+		"no limit uses the pool size":   {limit: 0, expParallel: true, expPoolSize: 15},
+		"limit overrides the pool size": {limit: 4, expParallel: true, expPoolSize: 4},
+		"limit of 1 plans serially":     {limit: 1, expParallel: false, expPoolSize: 1},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			r := &PlanCommandRunner{parallelPoolSize: 15, parallelPlanLimit: c.limit}
+			Equals(t, c.expParallel, r.isParallelEnabled(cmds))
+			Equals(t, c.expPoolSize, r.planPoolSize())
+		})
+	}
+}
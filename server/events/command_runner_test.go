@@ -23,6 +23,7 @@ import (
 	"github.com/runatlantis/atlantis/server/core/boltdb"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/core/runtime"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/runatlantis/atlantis/server/metrics/metricstest"
@@ -66,11 +67,13 @@ var lockingLocker *lockingmocks.MockLocker
 var applyCommandRunner *events.ApplyCommandRunner
 var unlockCommandRunner *events.UnlockCommandRunner
 var importCommandRunner *events.ImportCommandRunner
+var runningCommands *events.RunningCommands
 var preWorkflowHooksCommandRunner events.PreWorkflowHooksCommandRunner
 var postWorkflowHooksCommandRunner events.PostWorkflowHooksCommandRunner
 
 type TestConfig struct {
 	parallelPoolSize           int
+	parallelPlanLimit          int
 	SilenceNoProjects          bool
 	silenceVCSStatusNoPlans    bool
 	silenceVCSStatusNoProjects bool
@@ -79,6 +82,7 @@ type TestConfig struct {
 	database                   db.Database
 	DisableUnlockLabel         string
 	PendingApplyStatus         bool
+	asyncApply                 bool
 }
 
 func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.MockClient {
@@ -130,7 +134,7 @@ func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.Mock
 	pullUpdater = &events.PullUpdater{
 		HidePrevPlanComments: false,
 		VCSClient:            vcsClient,
-		MarkdownRenderer:     events.NewMarkdownRenderer(false, false, false, false, false, false, "", "atlantis", false, false),
+		MarkdownRenderer:     events.NewMarkdownRenderer(false, false, false, false, false, false, "", "atlantis", false, false, 0),
 	}
 
 	autoMerger = &events.AutoMerger{
@@ -168,6 +172,7 @@ func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.Mock
 		testConfig.discardApprovalOnPlan,
 		pullReqStatusFetcher,
 		testConfig.PendingApplyStatus,
+		testConfig.parallelPlanLimit,
 	)
 
 	applyCommandRunner = events.NewApplyCommandRunner(
@@ -185,6 +190,8 @@ func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.Mock
 		testConfig.SilenceNoProjects,
 		testConfig.silenceVCSStatusNoProjects,
 		pullReqStatusFetcher,
+		testConfig.asyncApply,
+		drainer,
 	)
 
 	approvePoliciesCommandRunner = events.NewApprovePoliciesCommandRunner(
@@ -221,6 +228,12 @@ func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.Mock
 		testConfig.SilenceNoProjects,
 	)
 
+	runningCommands = &events.RunningCommands{}
+	cancelCommandRunner := events.NewCancelCommandRunner(
+		vcsClient,
+		runningCommands,
+	)
+
 	commentCommandRunnerByCmd := map[command.Name]events.CommentCommandRunner{
 		command.Plan:            planCommandRunner,
 		command.Apply:           applyCommandRunner,
@@ -228,6 +241,7 @@ func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.Mock
 		command.Unlock:          unlockCommandRunner,
 		command.Version:         versionCommandRunner,
 		command.Import:          importCommandRunner,
+		command.Cancel:          cancelCommandRunner,
 	}
 
 	preWorkflowHooksCommandRunner = mocks.NewMockPreWorkflowHooksCommandRunner()
@@ -259,6 +273,7 @@ func setup(t *testing.T, options ...func(testConfig *TestConfig)) *vcsmocks.Mock
 		PostWorkflowHooksCommandRunner: postWorkflowHooksCommandRunner,
 		PullStatusFetcher:              testConfig.database,
 		CommitStatusUpdater:            commitUpdater,
+		RunningCommands:                runningCommands,
 	}
 
 	return vcsClient
@@ -676,6 +691,8 @@ func TestRunUnlockCommand_VCSComment(t *testing.T) {
 				Eq(testdata.Pull.Num))).ThenReturn(pull, nil)
 			When(eventParsing.ParseGithubPull(Any[logging.SimpleLogging](), Eq(pull))).ThenReturn(modelPull, modelPull.BaseRepo,
 				testdata.GithubRepo, nil)
+			When(deleteLockCommand.DeleteLocksByPull(Any[logging.SimpleLogging](), Eq(testdata.GithubRepo.FullName),
+				Eq(testdata.Pull.Num))).ThenReturn(2, nil)
 
 			ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, nil, testdata.User, testdata.Pull.Num,
 				&events.CommentCommand{Name: command.Unlock})
@@ -684,7 +701,115 @@ func TestRunUnlockCommand_VCSComment(t *testing.T) {
 				Eq(testdata.GithubRepo.FullName), Eq(testdata.Pull.Num))
 			vcsClient.VerifyWasCalledOnce().CreateComment(
 				Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(testdata.Pull.Num),
-				Eq("All Atlantis locks for this PR have been unlocked and plans discarded"), Eq("unlock"))
+				Eq("All 2 Atlantis locks for this PR have been unlocked and plans discarded"), Eq("unlock"))
+		})
+	}
+}
+
+func TestRunCancelCommand(t *testing.T) {
+	vcsClient := setup(t)
+	pull := &github.PullRequest{State: github.Ptr("open")}
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	When(githubGetter.GetPullRequest(Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(testdata.Pull.Num))).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(Any[logging.SimpleLogging](), Eq(pull))).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+
+	canceled, finish := runningCommands.Start(modelPull, command.Apply)
+	defer finish()
+	otherPull := models.PullRequest{BaseRepo: testdata.GithubRepo, Num: testdata.Pull.Num + 1}
+	otherCanceled, otherFinish := runningCommands.Start(otherPull, command.Apply)
+	defer otherFinish()
+
+	ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Cancel})
+
+	_, _, _, comment, _ := vcsClient.VerifyWasCalledOnce().CreateComment(
+		Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(testdata.Pull.Num), Any[string](), Eq("cancel")).GetCapturedArguments()
+	Assert(t, strings.HasPrefix(comment, "Canceled the running apply for this pull request."), "unexpected comment %q", comment)
+	select {
+	case <-canceled:
+	default:
+		t.Fatal("exp apply to be canceled")
+	}
+	select {
+	case <-otherCanceled:
+		t.Fatal("exp apply of other pull request not to be canceled")
+	default:
+	}
+	// Canceling doesn't wait for the workflow hooks.
+	preWorkflowHooksCommandRunner.(*mocks.MockPreWorkflowHooksCommandRunner).VerifyWasCalled(Never()).RunPreHooks(Any[*command.Context](), Any[*events.CommentCommand]())
+
+	// Commands are only canceled once.
+	ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Cancel})
+	vcsClient.VerifyWasCalledOnce().CreateComment(
+		Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(testdata.Pull.Num), Eq("There's no plan or apply running for this pull request to cancel."), Eq("cancel"))
+}
+
+func TestRunCommentCommand_FinishesCanceledCommandsOnPanic(t *testing.T) {
+	setup(t)
+	pull := &github.PullRequest{State: github.Ptr("open")}
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	When(githubGetter.GetPullRequest(Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(testdata.Pull.Num))).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(Any[logging.SimpleLogging](), Eq(pull))).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+	When(projectCommandBuilder.BuildPlanCommands(Any[*command.Context](), Any[*events.CommentCommand]())).
+		ThenPanic("panic test - if you're seeing this in a test failure this isn't the failing test")
+
+	ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Plan})
+
+	Equals(t, []command.Name(nil), runningCommands.Cancel(modelPull))
+}
+
+func TestRunCommentCommand_AsyncApplyCancelable(t *testing.T) {
+	setup(t, func(tc *TestConfig) {
+		tc.asyncApply = true
+	})
+	pull := &github.PullRequest{State: github.Ptr("open")}
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	When(githubGetter.GetPullRequest(Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(testdata.Pull.Num))).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(Any[logging.SimpleLogging](), Eq(pull))).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+	When(projectCommandBuilder.BuildApplyCommands(Any[*command.Context](), Any[*events.CommentCommand]())).
+		ThenReturn([]command.ProjectContext{{CommandName: command.Apply, Workspace: "default", RepoRelDir: "."}}, nil)
+	release := make(chan struct{})
+	When(projectCommandRunner.Apply(Any[command.ProjectContext]())).Then(func(_ []Param) ReturnValues {
+		<-release
+		return ReturnValues{command.ProjectResult{Command: command.Apply}}
+	})
+
+	ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Apply})
+
+	// The apply runs in the background and can still be canceled.
+	Equals(t, []command.Name{command.Apply}, runningCommands.Cancel(modelPull))
+	close(release)
+	drainer.ShutdownBlocking()
+	Equals(t, []command.Name(nil), runningCommands.Cancel(modelPull))
+}
+
+func TestRunUnlockCommand_ReportsNumLocks(t *testing.T) {
+	cases := []struct {
+		numLocks   int
+		expComment string
+	}{
+		{0, "There were no Atlantis locks for this PR to unlock"},
+		{1, "1 Atlantis lock for this PR has been unlocked and its plan discarded"},
+		{3, "All 3 Atlantis locks for this PR have been unlocked and plans discarded"},
+	}
+	for _, c := range cases {
+		t.Run(fmt.Sprintf("%d locks", c.numLocks), func(t *testing.T) {
+			vcsClient := setup(t)
+			pull := &github.PullRequest{
+				State: github.Ptr("open"),
+			}
+			modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+			When(githubGetter.GetPullRequest(Any[logging.SimpleLogging](), Eq(testdata.GithubRepo),
+				Eq(testdata.Pull.Num))).ThenReturn(pull, nil)
+			When(eventParsing.ParseGithubPull(Any[logging.SimpleLogging](), Eq(pull))).ThenReturn(modelPull, modelPull.BaseRepo,
+				testdata.GithubRepo, nil)
+			When(deleteLockCommand.DeleteLocksByPull(Any[logging.SimpleLogging](), Eq(testdata.GithubRepo.FullName),
+				Eq(testdata.Pull.Num))).ThenReturn(c.numLocks, nil)
+
+			ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, nil, testdata.User, testdata.Pull.Num,
+				&events.CommentCommand{Name: command.Unlock})
+
+			vcsClient.VerifyWasCalledOnce().CreateComment(
+				Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(testdata.Pull.Num), Eq(c.expComment), Eq("unlock"))
 		})
 	}
 }
@@ -926,6 +1051,68 @@ func TestRunCommentCommand_FailedPreWorkflowHook_FailOnPreWorkflowHookError_True
 	lockingLocker.VerifyWasCalled(Never()).UnlockByPull(Any[string](), Any[int]())
 }
 
+func TestRunAutoplanCommand_PreWorkflowHookAbort(t *testing.T) {
+	vcsClient := setup(t)
+	When(projectCommandBuilder.BuildAutoplanCommands(Any[*command.Context]())).
+		ThenReturn([]command.ProjectContext{
+			{
+				CommandName: command.Plan,
+			},
+		}, nil)
+	When(preWorkflowHooksCommandRunner.RunPreHooks(Any[*command.Context](), Any[*events.CommentCommand]())).
+		ThenReturn(&runtime.PreWorkflowHookAbortError{Hook: "Check freeze", Message: "Changes are frozen until Monday."})
+	testdata.Pull.BaseRepo = testdata.GithubRepo
+	// Aborting doesn't depend on failing on pre-workflow hook errors.
+	ch.FailOnPreWorkflowHookError = false
+	ch.RunAutoplanCommand(testdata.GithubRepo, testdata.GithubRepo, testdata.Pull, testdata.User)
+
+	projectCommandRunner.VerifyWasCalled(Never()).Plan(Any[command.ProjectContext]())
+	commitUpdater.VerifyWasCalledOnce().UpdateCombined(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+		Eq(models.FailedCommitStatus), Eq(command.Plan))
+	vcsClient.VerifyWasCalledOnce().CreateComment(Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(testdata.Pull.Num),
+		Eq("**Plan Aborted**: Check freeze aborted the command.\n\nChanges are frozen until Monday."), Eq("plan"))
+}
+
+func TestRunCommentCommand_PreWorkflowHookAbort(t *testing.T) {
+	vcsClient := setup(t)
+	pull := &github.PullRequest{State: github.Ptr("open")}
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+	When(githubGetter.GetPullRequest(Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(testdata.Pull.Num))).ThenReturn(pull, nil)
+	When(eventParsing.ParseGithubPull(Any[logging.SimpleLogging](), Eq(pull))).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+	When(preWorkflowHooksCommandRunner.RunPreHooks(Any[*command.Context](), Any[*events.CommentCommand]())).
+		ThenReturn(&runtime.PreWorkflowHookAbortError{Hook: "Pre workflow hook #0"})
+	ch.FailOnPreWorkflowHookError = false
+	ch.RunCommentCommand(testdata.GithubRepo, nil, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Apply})
+
+	projectCommandBuilder.VerifyWasCalled(Never()).BuildApplyCommands(Any[*command.Context](), Any[*events.CommentCommand]())
+	commitUpdater.VerifyWasCalledOnce().UpdateCombined(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+		Eq(models.FailedCommitStatus), Eq(command.Apply))
+	vcsClient.VerifyWasCalledOnce().CreateComment(Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(testdata.Pull.Num),
+		Eq("**Apply Aborted**: Pre workflow hook #0 aborted the command."), Eq("apply"))
+}
+
+func TestRunCommentCommand_DryRunSkipsPostWorkflowHooks(t *testing.T) {
+	for _, dryRun := range []bool{false, true} {
+		t.Run(fmt.Sprintf("dry run %t", dryRun), func(t *testing.T) {
+			setup(t)
+			pull := &github.PullRequest{State: github.Ptr("open")}
+			modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+			When(githubGetter.GetPullRequest(Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(testdata.Pull.Num))).ThenReturn(pull, nil)
+			When(eventParsing.ParseGithubPull(Any[logging.SimpleLogging](), Eq(pull))).ThenReturn(modelPull, modelPull.BaseRepo, testdata.GithubRepo, nil)
+			When(projectCommandBuilder.BuildApplyCommands(Any[*command.Context](), Any[*events.CommentCommand]())).
+				ThenReturn([]command.ProjectContext{}, nil)
+
+			ch.RunCommentCommand(testdata.GithubRepo, nil, nil, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Apply, DryRun: dryRun})
+
+			times := Times(1)
+			if dryRun {
+				times = Never()
+			}
+			postWorkflowHooksCommandRunner.(*mocks.MockPostWorkflowHooksCommandRunner).VerifyWasCalled(times).RunPostHooks(Any[*command.Context](), Any[*events.CommentCommand]())
+		})
+	}
+}
+
 func TestRunGenericPlanCommand_DeletePlans(t *testing.T) {
 	setup(t)
 	tmp := t.TempDir()
@@ -1129,6 +1316,86 @@ func TestApplyMergeablityWhenPolicyCheckFails(t *testing.T) {
 	ch.RunCommentCommand(testdata.GithubRepo, &testdata.GithubRepo, &modelPull, testdata.User, testdata.Pull.Num, &events.CommentCommand{Name: command.Apply})
 }
 
+func TestPolicyCheckCommandRunner_QuietPolicyChecks(t *testing.T) {
+	cases := []struct {
+		description string
+		quiet       []bool
+		failure     string
+		expComment  bool
+	}{
+		{
+			description: "quiet success",
+			quiet:       []bool{true},
+		},
+		{
+			description: "quiet failure",
+			quiet:       []bool{true},
+			failure:     "policies failed",
+			expComment:  true,
+		},
+		{
+			description: "success",
+			quiet:       []bool{false},
+			expComment:  true,
+		},
+		{
+			description: "quiet and loud projects",
+			quiet:       []bool{true, false},
+			expComment:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			vcsClient := setup(t)
+			boltDB, err := boltdb.New(t.TempDir())
+			Ok(t, err)
+			t.Cleanup(func() {
+				boltDB.Close()
+			})
+			dbUpdater.Database = boltDB
+
+			When(projectCommandRunner.PolicyCheck(Any[command.ProjectContext]())).Then(func(args []Param) ReturnValues {
+				ctx := args[0].(command.ProjectContext)
+				return ReturnValues{command.ProjectResult{
+					Command:            command.PolicyCheck,
+					PolicyCheckResults: &models.PolicyCheckResults{},
+					Failure:            c.failure,
+					RepoRelDir:         ctx.RepoRelDir,
+					Workspace:          ctx.Workspace,
+					QuietPolicyChecks:  ctx.QuietPolicyChecks,
+				}}
+			})
+			var cmds []command.ProjectContext
+			for i, quiet := range c.quiet {
+				cmds = append(cmds, command.ProjectContext{
+					CommandName:       command.PolicyCheck,
+					RepoRelDir:        fmt.Sprintf("dir%d", i),
+					Workspace:         "default",
+					QuietPolicyChecks: quiet,
+				})
+			}
+			modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num}
+			ctx := &command.Context{
+				Log:      logging.NewNoopLogger(t),
+				Pull:     modelPull,
+				HeadRepo: testdata.GithubRepo,
+				Trigger:  command.AutoTrigger,
+			}
+			policyCheckCommandRunner.Run(ctx, cmds)
+
+			if c.expComment {
+				vcsClient.VerifyWasCalledOnce().CreateComment(Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(modelPull.Num), Any[string](), Eq("policy_check"))
+			} else {
+				vcsClient.VerifyWasCalled(Never()).CreateComment(Any[logging.SimpleLogging](), Any[models.Repo](), Any[int](), Any[string](), Any[string]())
+			}
+			// The policy check status is still tracked for apply.
+			status, err := boltDB.GetPullStatus(modelPull)
+			Ok(t, err)
+			Equals(t, len(c.quiet), len(status.Projects))
+		})
+	}
+}
+
 func TestApplyWithAutoMerge_VSCMerge(t *testing.T) {
 	t.Log("if \"atlantis apply\" is run with automerge then a VCS merge is performed")
 
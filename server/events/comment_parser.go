@@ -18,6 +18,7 @@ import (
 	"fmt"
 	"io"
 	"net/url"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -37,12 +38,30 @@ const (
 	dirFlagShort                 = "d"
 	projectFlagLong              = "project"
 	projectFlagShort             = "p"
+	excludeProjectFlagLong       = "exclude-project"
+	excludeProjectFlagShort      = ""
+	workspacePatternFlagLong     = "workspace-pattern"
+	workspacePatternFlagShort    = ""
+	sinceFlagLong                = "since"
+	sinceFlagShort               = ""
+	upgradeFlagLong              = "upgrade"
+	upgradeFlagShort             = ""
+	workflowFlagLong             = "workflow"
+	workflowFlagShort            = ""
 	policySetFlagLong            = "policy-set"
 	policySetFlagShort           = ""
 	autoMergeDisabledFlagLong    = "auto-merge-disabled"
 	autoMergeDisabledFlagShort   = ""
 	autoMergeMethodFlagLong      = "auto-merge-method"
 	autoMergeMethodFlagShort     = ""
+	dryRunFlagLong               = "dry-run"
+	dryRunFlagShort              = ""
+	destroyFlagLong              = "destroy"
+	destroyFlagShort             = ""
+	refreshOnlyFlagLong          = "refresh-only"
+	refreshOnlyFlagShort         = ""
+	failedFlagLong               = "failed"
+	failedFlagShort              = ""
 	verboseFlagLong              = "verbose"
 	verboseFlagShort             = ""
 	clearPolicyApprovalFlagLong  = "clear-policy-approval"
@@ -227,11 +246,20 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 	var workspace string
 	var dir string
 	var project string
+	var excludeProjects []string
+	var workspacePattern string
+	var since string
+	var upgrade bool
+	var destroy bool
+	var refreshOnly bool
+	var failed bool
+	var workflow string
 	var policySet string
 	var clearPolicyApproval bool
 	var verbose bool
 	var autoMergeDisabled bool
 	var autoMergeMethod string
+	var dryRun bool
 	var flagSet *pflag.FlagSet
 	var name command.Name
 
@@ -243,7 +271,23 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 		flagSet.SetOutput(io.Discard)
 		flagSet.StringVarP(&workspace, workspaceFlagLong, workspaceFlagShort, "", "Switch to this Terraform workspace before planning.")
 		flagSet.StringVarP(&dir, dirFlagLong, dirFlagShort, "", "Which directory to run plan in relative to root of repo, ex. 'child/dir'.")
-		flagSet.StringVarP(&project, projectFlagLong, projectFlagShort, "", "Which project to run plan for. Refers to the name of the project configured in a repo config file. Cannot be used at same time as workspace or dir flags.")
+		flagSet.StringVarP(&project, projectFlagLong, projectFlagShort, "", "Which project to run plan for. Refers to the name of the project configured in a repo config file. Cannot be used at same time as workspace or dir flags. Prefix the name with '!' to exclude the project instead.")
+		flagSet.StringSliceVarP(&excludeProjects, excludeProjectFlagLong, excludeProjectFlagShort, nil, "Don't run plan for this project. Can be repeated or comma separated.")
+		flagSet.BoolVarP(&upgrade, upgradeFlagLong, upgradeFlagShort, false, "Run init with -upgrade to upgrade providers and modules to the newest versions allowed by their constraints.")
+		flagSet.BoolVarP(&destroy, destroyFlagLong, destroyFlagShort, false, "Run plan with -destroy to plan destroying all resources of the projects. Applying the plan destroys them.")
+		flagSet.BoolVarP(&refreshOnly, refreshOnlyFlagLong, refreshOnlyFlagShort, false, "Run plan with -refresh-only to plan updating the Terraform state to match the real infrastructure. Applying the plan only updates the state.")
+		flagSet.BoolVarP(&failed, failedFlagLong, failedFlagShort, false, "Only run plan for the projects whose last plan failed at the pull request's latest commit. Cannot be used at same time as project or dir flags.")
+		flagSet.StringVarP(&since, sinceFlagLong, sinceFlagShort, "", "Only run plan for the projects with files changed since this git ref instead of in the whole pull request, ex. 'origin/main'. Cannot be used at same time as project or dir flags.")
+		flagSet.StringVarP(&workspacePattern, workspacePatternFlagLong, workspacePatternFlagShort, "", "Only run plan for the projects with a Terraform workspace matching this glob, ex. 'prod-*'.")
+		flagSet.StringVarP(&workflow, workflowFlagLong, workflowFlagShort, "", "Run plan with this workflow instead of the projects' configured workflow. It must be allowed by the server-side config.")
+		flagSet.BoolVarP(&verbose, verboseFlagLong, verboseFlagShort, false, "Append Atlantis log and the end of Terraform's debug log to comment.")
+	case command.Drift.String():
+		name = command.Drift
+		flagSet = pflag.NewFlagSet(command.Drift.String(), pflag.ContinueOnError)
+		flagSet.SetOutput(io.Discard)
+		flagSet.StringVarP(&workspace, workspaceFlagLong, workspaceFlagShort, "", "Switch to this Terraform workspace before checking for drift.")
+		flagSet.StringVarP(&dir, dirFlagLong, dirFlagShort, "", "Which directory to check for drift in relative to root of repo, ex. 'child/dir'.")
+		flagSet.StringVarP(&project, projectFlagLong, projectFlagShort, "", "Which project to check for drift. Refers to the name of the project configured in a repo config file. Cannot be used at same time as workspace or dir flags.")
 		flagSet.BoolVarP(&verbose, verboseFlagLong, verboseFlagShort, false, "Append Atlantis log to comment.")
 	case command.Apply.String():
 		name = command.Apply
@@ -253,7 +297,8 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 		flagSet.StringVarP(&dir, dirFlagLong, dirFlagShort, "", "Apply the plan for this directory, relative to root of repo, ex. 'child/dir'.")
 		flagSet.StringVarP(&project, projectFlagLong, projectFlagShort, "", "Apply the plan for this project. Refers to the name of the project configured in a repo config file. Cannot be used at same time as workspace or dir flags.")
 		flagSet.BoolVarP(&autoMergeDisabled, autoMergeDisabledFlagLong, autoMergeDisabledFlagShort, false, "Disable automerge after apply.")
-		flagSet.StringVarP(&autoMergeMethod, autoMergeMethodFlagLong, autoMergeMethodFlagShort, "", "Specifies the merge method for the VCS if automerge is enabled. (Currently only implemented for GitHub)")
+		flagSet.StringVarP(&autoMergeMethod, autoMergeMethodFlagLong, autoMergeMethodFlagShort, "", "Specifies the merge method for the VCS if automerge is enabled. (Currently only implemented for GitHub and GitLab)")
+		flagSet.BoolVarP(&dryRun, dryRunFlagLong, dryRunFlagShort, false, "Check the apply requirements and show the plans that would be applied without applying them.")
 		flagSet.BoolVarP(&verbose, verboseFlagLong, verboseFlagShort, false, "Append Atlantis log to comment.")
 	case command.ApprovePolicies.String():
 		name = command.ApprovePolicies
@@ -269,6 +314,10 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 		name = command.Unlock
 		flagSet = pflag.NewFlagSet(command.Unlock.String(), pflag.ContinueOnError)
 		flagSet.SetOutput(io.Discard)
+	case command.Cancel.String():
+		name = command.Cancel
+		flagSet = pflag.NewFlagSet(command.Cancel.String(), pflag.ContinueOnError)
+		flagSet.SetOutput(io.Discard)
 	case command.Version.String():
 		name = command.Version
 		flagSet = pflag.NewFlagSet(command.Version.String(), pflag.ContinueOnError)
@@ -313,6 +362,19 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 		return CommentParseResult{CommentResponse: e.errMarkdown(fmt.Sprintf("invalid workspace: %q", workspace), cmd, flagSet)}
 	}
 
+	// A project prefixed with "!" is excluded rather than included, ex.
+	// atlantis plan -p '!slow' is the same as atlantis plan --exclude-project slow.
+	if strings.HasPrefix(project, "!") {
+		excludeProjects = append(excludeProjects, strings.TrimPrefix(project, "!"))
+		project = ""
+	}
+	for _, excluded := range excludeProjects {
+		if excluded == "" {
+			err := fmt.Sprintf("--%s cannot be empty", excludeProjectFlagLong)
+			return CommentParseResult{CommentResponse: e.errMarkdown(err, cmd, flagSet)}
+		}
+	}
+
 	// If project is specified, dir or workspace should not be set. Since we
 	// dir/workspace have defaults we can't detect if the user set the flag
 	// to the default or didn't set the flag so there is an edge case here we
@@ -323,20 +385,64 @@ func (e *CommentParser) Parse(rawComment string, vcsHost models.VCSHostType) Com
 		return CommentParseResult{CommentResponse: e.errMarkdown(err, cmd, flagSet)}
 	}
 
+	if workspacePattern != "" {
+		if workspace != "" {
+			err := fmt.Sprintf("cannot use -%s/--%s at same time as --%s", workspaceFlagShort, workspaceFlagLong, workspacePatternFlagLong)
+			return CommentParseResult{CommentResponse: e.errMarkdown(err, cmd, flagSet)}
+		}
+		if _, err := path.Match(workspacePattern, ""); err != nil {
+			return CommentParseResult{CommentResponse: e.errMarkdown(fmt.Sprintf("invalid --%s %q: %s", workspacePatternFlagLong, workspacePattern, err), cmd, flagSet)}
+		}
+	}
+
+	if since != "" {
+		if project != "" || dir != "" {
+			err := fmt.Sprintf("cannot use --%s at same time as -%s/--%s or -%s/--%s", sinceFlagLong, projectFlagShort, projectFlagLong, dirFlagShort, dirFlagLong)
+			return CommentParseResult{CommentResponse: e.errMarkdown(err, cmd, flagSet)}
+		}
+		if strings.HasPrefix(since, "-") {
+			return CommentParseResult{CommentResponse: e.errMarkdown(fmt.Sprintf("--%s cannot start with '-'", sinceFlagLong), cmd, flagSet)}
+		}
+	}
+
+	if failed && (project != "" || dir != "") {
+		err := fmt.Sprintf("cannot use --%s at same time as -%s/--%s or -%s/--%s", failedFlagLong, projectFlagShort, projectFlagLong, dirFlagShort, dirFlagLong)
+		return CommentParseResult{CommentResponse: e.errMarkdown(err, cmd, flagSet)}
+	}
+
+	if destroy && refreshOnly {
+		err := fmt.Sprintf("cannot use --%s at the same time as --%s", destroyFlagLong, refreshOnlyFlagLong)
+		return CommentParseResult{CommentResponse: e.errMarkdown(err, cmd, flagSet)}
+	}
+
+	if flagSet.Changed(workflowFlagLong) && workflow == "" {
+		return CommentParseResult{CommentResponse: e.errMarkdown(fmt.Sprintf("--%s cannot be empty", workflowFlagLong), cmd, flagSet)}
+	}
+
 	if autoMergeMethod != "" {
 		if autoMergeDisabled {
 			err := fmt.Sprintf("cannot use --%s at the same time as --%s", autoMergeMethodFlagLong, autoMergeDisabledFlagLong)
 			return CommentParseResult{CommentResponse: e.errMarkdown(err, cmd, flagSet)}
 		}
 
-		if vcsHost != models.Github {
+		if vcsHost != models.Github && vcsHost != models.Gitlab {
 			err := fmt.Sprintf("--%s is not currently implemented for %s", autoMergeMethodFlagLong, vcsHost.String())
 			return CommentParseResult{CommentResponse: e.errMarkdown(err, cmd, flagSet)}
 		}
 	}
 
+	commentCmd := NewCommentCommand(dir, extraArgs, name, subName, verbose, autoMergeDisabled, autoMergeMethod, workspace, project, policySet, clearPolicyApproval)
+	commentCmd.ExcludeProjectNames = excludeProjects
+	commentCmd.WorkspacePattern = workspacePattern
+	commentCmd.SinceRef = since
+	commentCmd.Failed = failed
+	commentCmd.Upgrade = upgrade
+	commentCmd.Destroy = destroy
+	commentCmd.RefreshOnly = refreshOnly
+	commentCmd.Workflow = workflow
+	commentCmd.DryRun = dryRun
 	return CommentParseResult{
-		Command: NewCommentCommand(dir, extraArgs, name, subName, verbose, autoMergeDisabled, autoMergeMethod, workspace, project, policySet, clearPolicyApproval),
+		Command: commentCmd,
 	}
 }
 
@@ -517,6 +623,8 @@ func (e *CommentParser) HelpComment() string {
 		AllowApprovePolicies bool
 		AllowImport          bool
 		AllowState           bool
+		AllowDrift           bool
+		AllowCancel          bool
 	}{
 		ExecutableName:       e.ExecutableName,
 		AllowVersion:         e.isAllowedCommand(command.Version.String()),
@@ -526,6 +634,8 @@ func (e *CommentParser) HelpComment() string {
 		AllowApprovePolicies: e.isAllowedCommand(command.ApprovePolicies.String()),
 		AllowImport:          e.isAllowedCommand(command.Import.String()),
 		AllowState:           e.isAllowedCommand(command.State.String()),
+		AllowDrift:           e.isAllowedCommand(command.Drift.String()),
+		AllowCancel:          e.isAllowedCommand(command.Cancel.String()),
 	}); err != nil {
 		return fmt.Sprintf("Failed to render template, this is a bug: %v", err)
 	}
@@ -585,6 +695,15 @@ Commands:
   state rm ADDRESS...
            Runs 'terraform state rm' for the passed address resource.
            To remove a specific project resource, use the -d, -w and -p flags.
+{{- end }}
+{{- if .AllowDrift }}
+  drift    Runs 'terraform plan' to report if infrastructure drifted from the
+           configuration. Plans aren't saved and projects aren't locked.
+           To check a specific project, use the -d, -w and -p flags.
+{{- end }}
+{{- if .AllowCancel }}
+  cancel   Cancels the plans and applies running for this pull request.
+           Terraform is interrupted so it can save the state before exiting.
 {{- end }}
   help     View help.
 
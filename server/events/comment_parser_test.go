@@ -266,6 +266,15 @@ func TestParse_UnknownShorthandFlag(t *testing.T) {
 	Equals(t, UnlockUsage, r.CommentResponse)
 }
 
+func TestParse_Cancel(t *testing.T) {
+	r := commentParser.Parse("atlantis cancel", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, command.Cancel, r.Command.Name)
+
+	r = commentParser.Parse("atlantis cancel -p project", models.Github)
+	Assert(t, strings.Contains(r.CommentResponse, "unknown shorthand flag: 'p'"), "exp unknown flag error, got %q", r.CommentResponse)
+}
+
 func TestParse_DidYouMeanAtlantis(t *testing.T) {
 	t.Log("given a comment that should result in a 'did you mean atlantis'" +
 		"response, should set CommentParseResult.CommentResult")
@@ -487,6 +496,211 @@ func TestParse_UsingProjectAtSameTimeAsWorkspaceOrDir(t *testing.T) {
 	}
 }
 
+func TestParse_ExcludeProjects(t *testing.T) {
+	cases := []struct {
+		comment     string
+		expProject  string
+		expExcluded []string
+	}{
+		{"atlantis plan --exclude-project slow", "", []string{"slow"}},
+		{"atlantis plan --exclude-project=slow", "", []string{"slow"}},
+		{"atlantis plan --exclude-project slow,other --exclude-project third", "", []string{"slow", "other", "third"}},
+		{"atlantis plan -p '!slow'", "", []string{"slow"}},
+		{"atlantis plan -p '!slow' --exclude-project other", "", []string{"other", "slow"}},
+		{"atlantis plan -p fast --exclude-project slow", "fast", []string{"slow"}},
+		{"atlantis plan -d dir --exclude-project slow", "", []string{"slow"}},
+	}
+	for _, c := range cases {
+		t.Run(c.comment, func(t *testing.T) {
+			r := commentParser.Parse(c.comment, models.Github)
+			Equals(t, "", r.CommentResponse)
+			Equals(t, c.expProject, r.Command.ProjectName)
+			Equals(t, c.expExcluded, r.Command.ExcludeProjectNames)
+		})
+	}
+
+	r := commentParser.Parse("atlantis plan -p '!'", models.Github)
+	exp := "Error: --exclude-project cannot be empty"
+	Assert(t, strings.Contains(r.CommentResponse, exp), "expected CommentResponse %q to contain %q", r.CommentResponse, exp)
+}
+
+func TestParse_WorkspacePattern(t *testing.T) {
+	cases := []struct {
+		comment    string
+		expPattern string
+		expDir     string
+		expProject string
+	}{
+		{"atlantis plan --workspace-pattern 'prod-*'", "prod-*", "", ""},
+		{"atlantis plan --workspace-pattern=staging", "staging", "", ""},
+		{"atlantis plan -d dir --workspace-pattern 'prod-?'", "prod-?", "dir", ""},
+		{"atlantis plan -p project --workspace-pattern 'prod-[ab]'", "prod-[ab]", "", "project"},
+	}
+	for _, c := range cases {
+		t.Run(c.comment, func(t *testing.T) {
+			r := commentParser.Parse(c.comment, models.Github)
+			Equals(t, "", r.CommentResponse)
+			Equals(t, c.expPattern, r.Command.WorkspacePattern)
+			Equals(t, c.expDir, r.Command.RepoRelDir)
+			Equals(t, c.expProject, r.Command.ProjectName)
+		})
+	}
+
+	errCases := []struct {
+		comment string
+		expErr  string
+	}{
+		{"atlantis plan -w prod --workspace-pattern 'prod-*'", "Error: cannot use -w/--workspace at same time as --workspace-pattern"},
+		{"atlantis plan --workspace-pattern 'prod-['", `Error: invalid --workspace-pattern "prod-[": syntax error in pattern`},
+		{"atlantis apply --workspace-pattern 'prod-*'", "Error: unknown flag: --workspace-pattern"},
+	}
+	for _, c := range errCases {
+		t.Run(c.comment, func(t *testing.T) {
+			r := commentParser.Parse(c.comment, models.Github)
+			Assert(t, strings.Contains(r.CommentResponse, c.expErr), "expected CommentResponse %q to contain %q", r.CommentResponse, c.expErr)
+		})
+	}
+}
+
+func TestParse_Since(t *testing.T) {
+	cases := []struct {
+		comment      string
+		expSince     string
+		expWorkspace string
+	}{
+		{"atlantis plan", "", ""},
+		{"atlantis plan --since origin/main", "origin/main", ""},
+		{"atlantis plan --since=v1.2.3", "v1.2.3", ""},
+		{"atlantis plan --since HEAD~3 -w staging", "HEAD~3", "staging"},
+	}
+	for _, c := range cases {
+		t.Run(c.comment, func(t *testing.T) {
+			r := commentParser.Parse(c.comment, models.Github)
+			Equals(t, "", r.CommentResponse)
+			Equals(t, c.expSince, r.Command.SinceRef)
+			Equals(t, c.expWorkspace, r.Command.Workspace)
+		})
+	}
+
+	errCases := []struct {
+		comment string
+		expErr  string
+	}{
+		{"atlantis plan --since origin/main -p project", "Error: cannot use --since at same time as -p/--project or -d/--dir"},
+		{"atlantis plan --since origin/main -d dir", "Error: cannot use --since at same time as -p/--project or -d/--dir"},
+		{"atlantis plan --since=--output=file", "Error: --since cannot start with '-'"},
+		{"atlantis apply --since origin/main", "Error: unknown flag: --since"},
+	}
+	for _, c := range errCases {
+		t.Run(c.comment, func(t *testing.T) {
+			r := commentParser.Parse(c.comment, models.Github)
+			Assert(t, strings.Contains(r.CommentResponse, c.expErr), "expected CommentResponse %q to contain %q", r.CommentResponse, c.expErr)
+		})
+	}
+}
+
+func TestParse_Upgrade(t *testing.T) {
+	r := commentParser.Parse("atlantis plan --upgrade -d dir", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, true, r.Command.Upgrade)
+	Equals(t, "dir", r.Command.RepoRelDir)
+
+	r = commentParser.Parse("atlantis plan", models.Github)
+	Equals(t, false, r.Command.Upgrade)
+
+	r = commentParser.Parse("atlantis apply --upgrade", models.Github)
+	exp := "Error: unknown flag: --upgrade"
+	Assert(t, strings.Contains(r.CommentResponse, exp), "expected CommentResponse %q to contain %q", r.CommentResponse, exp)
+}
+
+func TestParse_Destroy(t *testing.T) {
+	r := commentParser.Parse("atlantis plan --destroy -p project", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, true, r.Command.Destroy)
+	Equals(t, "project", r.Command.ProjectName)
+
+	r = commentParser.Parse("atlantis plan", models.Github)
+	Equals(t, false, r.Command.Destroy)
+
+	r = commentParser.Parse("atlantis apply --destroy", models.Github)
+	exp := "Error: unknown flag: --destroy"
+	Assert(t, strings.Contains(r.CommentResponse, exp), "expected CommentResponse %q to contain %q", r.CommentResponse, exp)
+}
+
+func TestParse_RefreshOnly(t *testing.T) {
+	r := commentParser.Parse("atlantis plan --refresh-only -p project", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, true, r.Command.RefreshOnly)
+	Equals(t, "project", r.Command.ProjectName)
+
+	r = commentParser.Parse("atlantis plan", models.Github)
+	Equals(t, false, r.Command.RefreshOnly)
+
+	r = commentParser.Parse("atlantis plan --refresh-only --destroy", models.Github)
+	exp := "Error: cannot use --destroy at the same time as --refresh-only"
+	Assert(t, strings.Contains(r.CommentResponse, exp), "expected CommentResponse %q to contain %q", r.CommentResponse, exp)
+
+	r = commentParser.Parse("atlantis apply --refresh-only", models.Github)
+	exp = "Error: unknown flag: --refresh-only"
+	Assert(t, strings.Contains(r.CommentResponse, exp), "expected CommentResponse %q to contain %q", r.CommentResponse, exp)
+}
+
+func TestParse_Failed(t *testing.T) {
+	r := commentParser.Parse("atlantis plan --failed --exclude-project slow", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, true, r.Command.Failed)
+	Equals(t, []string{"slow"}, r.Command.ExcludeProjectNames)
+
+	r = commentParser.Parse("atlantis plan", models.Github)
+	Equals(t, false, r.Command.Failed)
+
+	r = commentParser.Parse("atlantis plan --failed -p project", models.Github)
+	exp := "Error: cannot use --failed at same time as -p/--project or -d/--dir"
+	Assert(t, strings.Contains(r.CommentResponse, exp), "expected CommentResponse %q to contain %q", r.CommentResponse, exp)
+
+	r = commentParser.Parse("atlantis apply --failed", models.Github)
+	exp = "Error: unknown flag: --failed"
+	Assert(t, strings.Contains(r.CommentResponse, exp), "expected CommentResponse %q to contain %q", r.CommentResponse, exp)
+}
+
+func TestParse_DryRun(t *testing.T) {
+	r := commentParser.Parse("atlantis apply --dry-run -p project", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, true, r.Command.DryRun)
+	Equals(t, "project", r.Command.ProjectName)
+
+	r = commentParser.Parse("atlantis apply", models.Github)
+	Equals(t, false, r.Command.DryRun)
+
+	r = commentParser.Parse("atlantis plan --dry-run", models.Github)
+	exp := "Error: unknown flag: --dry-run"
+	Assert(t, strings.Contains(r.CommentResponse, exp), "expected CommentResponse %q to contain %q", r.CommentResponse, exp)
+}
+
+func TestParse_Workflow(t *testing.T) {
+	r := commentParser.Parse("atlantis plan --workflow experimental -p project", models.Github)
+	Equals(t, "", r.CommentResponse)
+	Equals(t, "experimental", r.Command.Workflow)
+	Equals(t, "project", r.Command.ProjectName)
+
+	r = commentParser.Parse("atlantis plan", models.Github)
+	Equals(t, "", r.Command.Workflow)
+
+	errCases := []struct {
+		comment string
+		expErr  string
+	}{
+		{"atlantis plan --workflow=", "Error: --workflow cannot be empty"},
+		{"atlantis apply --workflow experimental", "Error: unknown flag: --workflow"},
+	}
+	for _, c := range errCases {
+		t.Run(c.comment, func(t *testing.T) {
+			r := commentParser.Parse(c.comment, models.Github)
+			Assert(t, strings.Contains(r.CommentResponse, c.expErr), "expected CommentResponse %q to contain %q", r.CommentResponse, c.expErr)
+		})
+	}
+}
+
 func TestParse_Parsing(t *testing.T) {
 	cases := []struct {
 		flags        string
@@ -707,7 +921,7 @@ func TestParse_Parsing(t *testing.T) {
 	}
 
 	for _, test := range cases {
-		for _, cmdName := range []string{"plan", "apply", "import 'some[\"addr\"]' id", "state rm 'some[\"addr\"]'"} {
+		for _, cmdName := range []string{"plan", "apply", "drift", "import 'some[\"addr\"]' id", "state rm 'some[\"addr\"]'"} {
 			comment := fmt.Sprintf("atlantis %s %s", cmdName, test.flags)
 			t.Run(comment, func(t *testing.T) {
 				r := commentParser.Parse(comment, models.Github)
@@ -724,6 +938,10 @@ func TestParse_Parsing(t *testing.T) {
 					Assert(t, r.Command.Name == command.Apply, "did not parse comment %q as apply command", comment)
 					Assert(t, test.expExtraArgs == actExtraArgs, "exp extra args to equal %v but got %v for comment %q", test.expExtraArgs, actExtraArgs, comment)
 				}
+				if cmdName == "drift" {
+					Assert(t, r.Command.Name == command.Drift, "did not parse comment %q as drift command", comment)
+					Assert(t, test.expExtraArgs == actExtraArgs, "exp extra args to equal %v but got %v for comment %q", test.expExtraArgs, actExtraArgs, comment)
+				}
 				if cmdName == "approve_policies" {
 					Assert(t, r.Command.Name == command.ApprovePolicies, "did not parse comment %q as approve_policies command", comment)
 					Assert(t, test.expExtraArgs == actExtraArgs, "exp extra args to equal %v but got %v for comment %q", test.expExtraArgs, actExtraArgs, comment)
@@ -925,6 +1143,11 @@ Commands:
   state rm ADDRESS...
            Runs 'terraform state rm' for the passed address resource.
            To remove a specific project resource, use the -d, -w and -p flags.
+  drift    Runs 'terraform plan' to report if infrastructure drifted from the
+           configuration. Plans aren't saved and projects aren't locked.
+           To check a specific project, use the -d, -w and -p flags.
+  cancel   Cancels the plans and applies running for this pull request.
+           Terraform is interrupted so it can save the state before exiting.
   help     View help.
 
 Flags:
@@ -1048,22 +1271,50 @@ func TestParse_VCSUsername(t *testing.T) {
 }
 
 var PlanUsage = `Usage of plan:
-  -d, --dir string         Which directory to run plan in relative to root of repo,
-                           ex. 'child/dir'.
-  -p, --project string     Which project to run plan for. Refers to the name of the
-                           project configured in a repo config file. Cannot be used
-                           at same time as workspace or dir flags.
-      --verbose            Append Atlantis log to comment.
-  -w, --workspace string   Switch to this Terraform workspace before planning.
+      --destroy                    Run plan with -destroy to plan destroying all
+                                   resources of the projects. Applying the plan
+                                   destroys them.
+  -d, --dir string                 Which directory to run plan in relative to root
+                                   of repo, ex. 'child/dir'.
+      --exclude-project strings    Don't run plan for this project. Can be repeated
+                                   or comma separated.
+      --failed                     Only run plan for the projects whose last plan
+                                   failed at the pull request's latest commit.
+                                   Cannot be used at same time as project or dir flags.
+  -p, --project string             Which project to run plan for. Refers to the name
+                                   of the project configured in a repo config file.
+                                   Cannot be used at same time as workspace or dir
+                                   flags. Prefix the name with '!' to exclude the
+                                   project instead.
+      --refresh-only               Run plan with -refresh-only to plan updating the
+                                   Terraform state to match the real infrastructure.
+                                   Applying the plan only updates the state.
+      --since string               Only run plan for the projects with files changed
+                                   since this git ref instead of in the whole pull
+                                   request, ex. 'origin/main'. Cannot be used at
+                                   same time as project or dir flags.
+      --upgrade                    Run init with -upgrade to upgrade providers and
+                                   modules to the newest versions allowed by their
+                                   constraints.
+      --verbose                    Append Atlantis log and the end of Terraform's
+                                   debug log to comment.
+      --workflow string            Run plan with this workflow instead of the
+                                   projects' configured workflow. It must be allowed
+                                   by the server-side config.
+  -w, --workspace string           Switch to this Terraform workspace before planning.
+      --workspace-pattern string   Only run plan for the projects with a Terraform
+                                   workspace matching this glob, ex. 'prod-*'.
 `
 
 var ApplyUsage = `Usage of apply:
       --auto-merge-disabled        Disable automerge after apply.
       --auto-merge-method string   Specifies the merge method for the VCS if
                                    automerge is enabled. (Currently only implemented
-                                   for GitHub)
+                                   for GitHub and GitLab)
   -d, --dir string                 Apply the plan for this directory, relative to
                                    root of repo, ex. 'child/dir'.
+      --dry-run                    Check the apply requirements and show the plans
+                                   that would be applied without applying them.
   -p, --project string             Apply the plan for this project. Refers to the
                                    name of the project configured in a repo config
                                    file. Cannot be used at same time as workspace or
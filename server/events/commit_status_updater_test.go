@@ -264,3 +264,40 @@ func TestDefaultCommitStatusUpdater_UpdateProjectCustomStatusName(t *testing.T)
 	client.VerifyWasCalledOnce().UpdateStatus(Any[logging.SimpleLogging](), Eq(models.Repo{}), Eq(models.PullRequest{}),
 		Eq(models.SuccessCommitStatus), Eq("custom/apply: ./default"), Eq("Apply succeeded."), Eq("url"))
 }
+
+// Test that the status name prefixes the statuses of all commands and hooks.
+func TestDefaultCommitStatusUpdater_CustomStatusNameAllStatuses(t *testing.T) {
+	RegisterMockTestingT(t)
+	client := mocks.NewMockClient()
+	logger := logging.NewNoopLogger(t)
+	s := events.DefaultCommitStatusUpdater{Client: client, StatusName: "custom"}
+
+	Ok(t, s.UpdateCombined(logger, models.Repo{}, models.PullRequest{}, models.PendingCommitStatus, command.Plan))
+	Ok(t, s.UpdateCombinedCount(logger, models.Repo{}, models.PullRequest{}, models.SuccessCommitStatus, command.Apply, 1, 2))
+	Ok(t, s.UpdatePreWorkflowHook(logger, models.PullRequest{}, models.SuccessCommitStatus, "infracost", "", ""))
+	Ok(t, s.UpdatePostWorkflowHook(logger, models.PullRequest{}, models.FailedCommitStatus, "notify", "", ""))
+
+	for _, src := range []string{"custom/plan", "custom/apply", "custom/pre_workflow_hook: infracost", "custom/post_workflow_hook: notify"} {
+		client.VerifyWasCalledOnce().UpdateStatus(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+			Any[models.CommitStatus](), Eq(src), Any[string](), Any[string]())
+	}
+}
+
+// Test that no project statuses are set when they're disabled.
+func TestDefaultCommitStatusUpdater_UpdateProjectDisabled(t *testing.T) {
+	RegisterMockTestingT(t)
+	client := mocks.NewMockClient()
+	s := events.DefaultCommitStatusUpdater{Client: client, StatusName: "atlantis", DisableProjectStatuses: true}
+	err := s.UpdateProject(command.ProjectContext{
+		RepoRelDir: ".",
+		Workspace:  "default",
+	}, command.Apply, models.FailedCommitStatus, "url", nil)
+	Ok(t, err)
+	client.VerifyWasCalled(Never()).UpdateStatus(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+		Any[models.CommitStatus](), Any[string](), Any[string](), Any[string]())
+
+	// The combined statuses are still set.
+	Ok(t, s.UpdateCombinedCount(logging.NewNoopLogger(t), models.Repo{}, models.PullRequest{}, models.FailedCommitStatus, command.Apply, 1, 2))
+	client.VerifyWasCalledOnce().UpdateStatus(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+		Eq(models.FailedCommitStatus), Eq("atlantis/apply"), Eq("1/2 projects applied successfully."), Eq(""))
+}
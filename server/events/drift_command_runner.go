@@ -0,0 +1,57 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"github.com/runatlantis/atlantis/server/events/command"
+)
+
+func NewDriftCommandRunner(
+	pullUpdater *PullUpdater,
+	prjCmdBuilder ProjectDriftCommandBuilder,
+	prjCmdRunner ProjectDriftCommandRunner,
+	parallelPoolSize int,
+	silenceNoProjects bool,
+) *DriftCommandRunner {
+	return &DriftCommandRunner{
+		pullUpdater:       pullUpdater,
+		prjCmdBuilder:     prjCmdBuilder,
+		prjCmdRunner:      prjCmdRunner,
+		parallelPoolSize:  parallelPoolSize,
+		silenceNoProjects: silenceNoProjects,
+	}
+}
+
+// DriftCommandRunner runs plan for projects to report if their
+// infrastructure drifted from the configuration. Unlike plan, it doesn't
+// lock projects, save plans or set commit statuses.
+type DriftCommandRunner struct {
+	pullUpdater       *PullUpdater
+	prjCmdBuilder     ProjectDriftCommandBuilder
+	prjCmdRunner      ProjectDriftCommandRunner
+	parallelPoolSize  int
+	silenceNoProjects bool
+}
+
+func (d *DriftCommandRunner) Run(ctx *command.Context, cmd *CommentCommand) {
+	projectCmds, err := d.prjCmdBuilder.BuildDriftCommands(ctx, cmd)
+	if err != nil {
+		d.pullUpdater.updatePull(ctx, cmd, command.Result{Error: err})
+		return
+	}
+
+	if len(projectCmds) == 0 && d.silenceNoProjects {
+		ctx.Log.Info("determined there was no project to run drift in")
+		return
+	}
+
+	var result command.Result
+	if len(projectCmds) > 0 && projectCmds[0].ParallelPlanEnabled {
+		ctx.Log.Info("Running drift in parallel")
+		result = runProjectCmdsParallelGroups(ctx, projectCmds, d.prjCmdRunner.Drift, d.parallelPoolSize)
+	} else {
+		result = runProjectCmds(projectCmds, d.prjCmdRunner.Drift)
+	}
+	d.pullUpdater.updatePull(ctx, cmd, result)
+}
@@ -19,6 +19,8 @@ import (
 	"net/url"
 	"os"
 	"path"
+	"regexp"
+	"strconv"
 	"strings"
 
 	giteasdk "code.gitea.io/sdk/gitea"
@@ -42,6 +44,31 @@ const usagesCols = 90
 
 var lastBitbucketSha, _ = lru.New[string, string](300)
 
+// githubMergeQueueBranchPrefix is the prefix of the temporary branches that
+// GitHub's merge queue creates to test pull requests before merging them.
+const githubMergeQueueBranchPrefix = "gh-readonly-queue/"
+
+// githubMergeQueueBranchRegex matches merge queue branches, ex.
+// gh-readonly-queue/main/pr-123-<sha>. The submatch is the pull request
+// number.
+var githubMergeQueueBranchRegex = regexp.MustCompile(`^gh-readonly-queue/.+/pr-(\d+)-[0-9a-f]+$`)
+
+// ParseGithubMergeQueueBranch returns the number of the pull request that the
+// GitHub merge queue branch was created for. branch can be a full ref, ex.
+// refs/heads/gh-readonly-queue/main/pr-123-<sha>. ok is false if branch isn't
+// a merge queue branch.
+func ParseGithubMergeQueueBranch(branch string) (pullNum int, ok bool) {
+	matches := githubMergeQueueBranchRegex.FindStringSubmatch(strings.TrimPrefix(branch, "refs/heads/"))
+	if matches == nil {
+		return 0, false
+	}
+	pullNum, err := strconv.Atoi(matches[1])
+	if err != nil || pullNum == 0 {
+		return 0, false
+	}
+	return pullNum, true
+}
+
 // PullCommand is a command to run on a pull request.
 type PullCommand interface {
 	// Dir is the path relative to the repo root to run the command in.
@@ -140,6 +167,32 @@ type CommentCommand struct {
 	// project specified in an atlantis.yaml file.
 	// If empty then the comment specified no project.
 	ProjectName string
+	// ExcludeProjectNames are the names of projects the command must not run
+	// on. Exclusions win over every other way a project is selected.
+	ExcludeProjectNames []string
+	// WorkspacePattern is a glob matching the workspaces of the projects the
+	// command runs on. If empty then the comment specified no pattern.
+	WorkspacePattern string
+	// SinceRef is the git ref the files to plan changed since. If empty then
+	// the files modified by the pull request are used.
+	SinceRef string
+	// Failed is true if the command only runs on the projects whose last
+	// plan at the pull request's head commit failed.
+	Failed bool
+	// Upgrade is true if init should run with -upgrade for this command.
+	Upgrade bool
+	// Destroy is true if plan should run with -destroy for this command.
+	Destroy bool
+	// RefreshOnly is true if plan should run with -refresh-only for this
+	// command.
+	RefreshOnly bool
+	// DryRun is true if apply should only check the apply requirements and
+	// show the plans that would be applied.
+	DryRun bool
+	// Workflow is the name of the workflow the projects use for this command
+	// instead of their configured workflow. If empty then the configured
+	// workflows are used.
+	Workflow string
 	// PolicySet is the name of a policy set to run an approval on.
 	PolicySet string
 	// ClearPolicyApproval is true if approvals should be cleared out for specified policies.
@@ -180,7 +233,7 @@ func (c CommentCommand) IsAutoplan() bool {
 
 // String returns a string representation of the command.
 func (c CommentCommand) String() string {
-	return fmt.Sprintf("command=%q, verbose=%t, dir=%q, workspace=%q, project=%q, policyset=%q, auto-merge-disabled=%t, auto-merge-method=%s, clear-policy-approval=%t, flags=%q", c.Name.String(), c.Verbose, c.RepoRelDir, c.Workspace, c.ProjectName, c.PolicySet, c.AutoMergeDisabled, c.AutoMergeMethod, c.ClearPolicyApproval, strings.Join(c.Flags, ","))
+	return fmt.Sprintf("command=%q, verbose=%t, dir=%q, workspace=%q, project=%q, exclude-projects=%q, workspace-pattern=%q, since=%q, failed=%t, upgrade=%t, destroy=%t, refresh-only=%t, dry-run=%t, workflow=%q, policyset=%q, auto-merge-disabled=%t, auto-merge-method=%s, clear-policy-approval=%t, flags=%q", c.Name.String(), c.Verbose, c.RepoRelDir, c.Workspace, c.ProjectName, strings.Join(c.ExcludeProjectNames, ","), c.WorkspacePattern, c.SinceRef, c.Failed, c.Upgrade, c.Destroy, c.RefreshOnly, c.DryRun, c.Workflow, c.PolicySet, c.AutoMergeDisabled, c.AutoMergeMethod, c.ClearPolicyApproval, strings.Join(c.Flags, ","))
 }
 
 // NewCommentCommand constructs a CommentCommand, setting all missing fields to defaults.
@@ -301,12 +354,13 @@ type EventParsing interface {
 	// ParseBitbucketServerPullEvent parses a pull request event from Bitbucket
 	// Server.
 	// pull is the parsed pull request.
+	// pullEventType is the type of event, for example opened/closed.
 	// baseRepo is the repo the pull request will be merged into.
 	// headRepo is the repo the pull request branch is from.
 	// user is the pull request author.
-	ParseBitbucketServerPullEvent(body []byte) (
-		pull models.PullRequest, baseRepo models.Repo, headRepo models.Repo,
-		user models.User, err error)
+	ParseBitbucketServerPullEvent(eventTypeHeader string, body []byte) (
+		pull models.PullRequest, pullEventType models.PullRequestEventType,
+		baseRepo models.Repo, headRepo models.Repo, user models.User, err error)
 
 	// ParseBitbucketServerPullCommentEvent parses a pull request comment event
 	// from Bitbucket Server.
@@ -367,8 +421,15 @@ type EventParser struct {
 	BitbucketUser      string
 	BitbucketToken     string
 	BitbucketServerURL string
-	AzureDevopsToken   string
-	AzureDevopsUser    string
+	// BitbucketServerDraftDetection is how draft Bitbucket Server pull
+	// requests are detected, one of the bitbucketserver.DraftDetection*
+	// modes. Defaults to the draft field.
+	BitbucketServerDraftDetection string
+	// BitbucketServerDraftTitlePrefixes are the title prefixes that mark a
+	// Bitbucket Server pull request as a draft. They're case insensitive.
+	BitbucketServerDraftTitlePrefixes []string
+	AzureDevopsToken                  string
+	AzureDevopsUser                   string
 }
 
 func (e *EventParser) ParseAPIPlanRequest(vcsHostType models.VCSHostType, repoFullName string, cloneURL string) (models.Repo, error) {
@@ -550,6 +611,12 @@ func (e *EventParser) ParseGithubPullEvent(logger logging.SimpleLogging, pullEve
 	if pullEvent.GetPullRequest().GetDraft() && pullEvent.GetAction() != "closed" && !e.AllowDraftPRs {
 		action = "other"
 	}
+	// Pull requests from merge queue branches test changes that were already
+	// planned on the original pull request so we don't autoplan them.
+	if strings.HasPrefix(pull.HeadBranch, githubMergeQueueBranchPrefix) && action != "closed" {
+		logger.Debug("ignoring %q event for merge queue branch %q", action, pull.HeadBranch)
+		action = "other"
+	}
 
 	switch action {
 	case "opened":
@@ -883,7 +950,7 @@ func (e *EventParser) parseCommonBitbucketServerEventData(event bitbucketserver.
 // ParseBitbucketServerPullEvent parses a pull request event from Bitbucket
 // Server.
 // See EventParsing for return value docs.
-func (e *EventParser) ParseBitbucketServerPullEvent(body []byte) (pull models.PullRequest, baseRepo models.Repo, headRepo models.Repo, user models.User, err error) {
+func (e *EventParser) ParseBitbucketServerPullEvent(eventTypeHeader string, body []byte) (pull models.PullRequest, pullEventType models.PullRequestEventType, baseRepo models.Repo, headRepo models.Repo, user models.User, err error) {
 	var event bitbucketserver.PullRequestEvent
 	if err = json.Unmarshal(body, &event); err != nil {
 		err = errors.Wrap(err, "parsing json")
@@ -894,9 +961,58 @@ func (e *EventParser) ParseBitbucketServerPullEvent(body []byte) (pull models.Pu
 		return
 	}
 	pull, baseRepo, headRepo, user, err = e.parseCommonBitbucketServerEventData(event.CommonEventData)
+	if err != nil {
+		return
+	}
+
+	pullEventType = e.GetBitbucketServerPullEventType(eventTypeHeader)
+	draft := e.isBitbucketServerDraft(event.PullRequest)
+	// Check for a pull request that has been marked as ready, either with its
+	// draft field or by removing the draft prefix from its title.
+	if eventTypeHeader == bitbucketserver.PullModifiedHeader && !draft && e.wasBitbucketServerDraft(event) {
+		pullEventType = models.UpdatedPullEvent
+	}
+	if draft && pullEventType != models.ClosedPullEvent && !e.AllowDraftPRs {
+		pullEventType = models.OtherPullEvent
+	}
 	return
 }
 
+// isBitbucketServerDraft returns true if pull is a draft according to the
+// configured draft detection mode.
+func (e *EventParser) isBitbucketServerDraft(pull *bitbucketserver.PullRequest) bool {
+	if e.BitbucketServerDraftDetection != bitbucketserver.DraftDetectionTitle && pull.Draft != nil && *pull.Draft {
+		return true
+	}
+	return pull.Title != nil && e.hasBitbucketServerDraftTitle(*pull.Title)
+}
+
+// wasBitbucketServerDraft returns true if the pull request of a pr:modified
+// event was a draft before it was modified according to the configured draft
+// detection mode.
+func (e *EventParser) wasBitbucketServerDraft(event bitbucketserver.PullRequestEvent) bool {
+	if e.BitbucketServerDraftDetection != bitbucketserver.DraftDetectionTitle && event.PreviousDraft != nil && *event.PreviousDraft {
+		return true
+	}
+	return event.PreviousTitle != nil && e.hasBitbucketServerDraftTitle(*event.PreviousTitle)
+}
+
+// hasBitbucketServerDraftTitle returns true if title starts with one of the
+// draft title prefixes and draft detection uses the title.
+func (e *EventParser) hasBitbucketServerDraftTitle(title string) bool {
+	if e.BitbucketServerDraftDetection != bitbucketserver.DraftDetectionTitle && e.BitbucketServerDraftDetection != bitbucketserver.DraftDetectionAny {
+		return false
+	}
+	title = strings.ToLower(strings.TrimSpace(title))
+	for _, prefix := range e.BitbucketServerDraftTitlePrefixes {
+		prefix = strings.ToLower(strings.TrimSpace(prefix))
+		if prefix != "" && strings.HasPrefix(title, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // ParseAzureDevopsPullEvent parses Azure DevOps pull request events.
 // See EventParsing for return value docs.
 func (e *EventParser) ParseAzureDevopsPullEvent(event azuredevops.Event) (pull models.PullRequest, pullEventType models.PullRequestEventType, baseRepo models.Repo, headRepo models.Repo, user models.User, err error) {
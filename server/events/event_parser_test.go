@@ -27,6 +27,7 @@ import (
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs/bitbucketserver"
 	. "github.com/runatlantis/atlantis/server/events/vcs/testdata"
 	"github.com/runatlantis/atlantis/server/logging"
 	. "github.com/runatlantis/atlantis/testing"
@@ -191,6 +192,42 @@ func TestParseGithubPullEventFromDraft(t *testing.T) {
 	Equals(t, models.OpenedPullEvent, evType)
 }
 
+func TestParseGithubPullEventFromMergeQueue(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	testEvent := deepcopy.Copy(PullEvent).(github.PullRequestEvent)
+	testEvent.PullRequest.Head.Ref = github.Ptr("gh-readonly-queue/main/pr-1-0123abcd")
+	_, evType, _, _, _, err := parser.ParseGithubPullEvent(logger, &testEvent)
+	Ok(t, err)
+	Equals(t, models.OtherPullEvent, evType)
+
+	// Closing the pull request still cleans it up.
+	testEvent.Action = github.Ptr("closed")
+	_, evType, _, _, _, err = parser.ParseGithubPullEvent(logger, &testEvent)
+	Ok(t, err)
+	Equals(t, models.ClosedPullEvent, evType)
+}
+
+func TestParseGithubMergeQueueBranch(t *testing.T) {
+	cases := []struct {
+		branch string
+		expNum int
+		expOk  bool
+	}{
+		{"gh-readonly-queue/main/pr-123-0123abcd", 123, true},
+		{"refs/heads/gh-readonly-queue/release/v1/pr-7-0123abcd", 7, true},
+		{"gh-readonly-queue/main/pr-abc-0123abcd", 0, false},
+		{"feature/pr-123-0123abcd", 0, false},
+		{"main", 0, false},
+	}
+	for _, c := range cases {
+		t.Run(c.branch, func(t *testing.T) {
+			num, ok := events.ParseGithubMergeQueueBranch(c.branch)
+			Equals(t, c.expOk, ok)
+			Equals(t, c.expNum, num)
+		})
+	}
+}
+
 func TestParseGithubPullEvent_EventType(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
 	cases := []struct {
@@ -817,7 +854,7 @@ func TestCommentCommand_IsAutoplan(t *testing.T) {
 }
 
 func TestCommentCommand_String(t *testing.T) {
-	exp := `command="plan", verbose=true, dir="mydir", workspace="myworkspace", project="myproject", policyset="", auto-merge-disabled=false, auto-merge-method=, clear-policy-approval=false, flags="flag1,flag2"`
+	exp := `command="plan", verbose=true, dir="mydir", workspace="myworkspace", project="myproject", exclude-projects="", workspace-pattern="", since="", failed=false, upgrade=false, destroy=false, refresh-only=false, dry-run=false, workflow="", policyset="", auto-merge-disabled=false, auto-merge-method=, clear-policy-approval=false, flags="flag1,flag2"`
 	Equals(t, exp, (events.CommentCommand{
 		RepoRelDir:  "mydir",
 		Flags:       []string{"flag1", "flag2"},
@@ -1187,8 +1224,9 @@ func TestParseBitbucketServerPullEvent_ValidEvent(t *testing.T) {
 	if err != nil {
 		Ok(t, err)
 	}
-	pull, baseRepo, headRepo, user, err := parser.ParseBitbucketServerPullEvent(bytes)
+	pull, pullEventType, baseRepo, headRepo, user, err := parser.ParseBitbucketServerPullEvent("pr:merged", bytes)
 	Ok(t, err)
+	Equals(t, models.ClosedPullEvent, pullEventType)
 	expBaseRepo := models.Repo{
 		FullName:          "atlantis/atlantis-example",
 		Owner:             "atlantis",
@@ -1227,6 +1265,180 @@ func TestParseBitbucketServerPullEvent_ValidEvent(t *testing.T) {
 	}, user)
 }
 
+func TestParseBitbucketServerPullEvent_Drafts(t *testing.T) {
+	bytes, err := os.ReadFile(filepath.Join("testdata", "bitbucket-server-pull-event-created.json"))
+	Ok(t, err)
+	cases := []struct {
+		description   string
+		detection     string
+		allowDrafts   bool
+		header        string
+		title         string
+		draft         *bool
+		previousTitle *string
+		previousDraft *bool
+		exp           models.PullRequestEventType
+	}{
+		{
+			description: "draft field",
+			detection:   bitbucketserver.DraftDetectionField,
+			header:      "pr:opened",
+			title:       "Null resource",
+			draft:       github.Ptr(true),
+			exp:         models.OtherPullEvent,
+		},
+		{
+			description: "draft field with drafts allowed",
+			detection:   bitbucketserver.DraftDetectionField,
+			allowDrafts: true,
+			header:      "pr:opened",
+			title:       "Null resource",
+			draft:       github.Ptr(true),
+			exp:         models.OpenedPullEvent,
+		},
+		{
+			description: "draft field not set",
+			detection:   bitbucketserver.DraftDetectionField,
+			header:      "pr:from_ref_updated",
+			title:       "Null resource",
+			exp:         models.OpenedPullEvent,
+		},
+		{
+			description: "draft field is closed",
+			detection:   bitbucketserver.DraftDetectionField,
+			header:      "pr:declined",
+			title:       "Null resource",
+			draft:       github.Ptr(true),
+			exp:         models.ClosedPullEvent,
+		},
+		{
+			description: "title prefix ignored when detecting by field",
+			detection:   bitbucketserver.DraftDetectionField,
+			header:      "pr:opened",
+			title:       "WIP: Null resource",
+			exp:         models.OpenedPullEvent,
+		},
+		{
+			description: "title prefix",
+			detection:   bitbucketserver.DraftDetectionTitle,
+			header:      "pr:opened",
+			title:       "wip: Null resource",
+			exp:         models.OtherPullEvent,
+		},
+		{
+			description: "draft field ignored when detecting by title",
+			detection:   bitbucketserver.DraftDetectionTitle,
+			header:      "pr:opened",
+			title:       "Null resource",
+			draft:       github.Ptr(true),
+			exp:         models.OpenedPullEvent,
+		},
+		{
+			description: "any with draft field",
+			detection:   bitbucketserver.DraftDetectionAny,
+			header:      "pr:opened",
+			title:       "Null resource",
+			draft:       github.Ptr(true),
+			exp:         models.OtherPullEvent,
+		},
+		{
+			description: "any with title prefix",
+			detection:   bitbucketserver.DraftDetectionAny,
+			header:      "pr:from_ref_updated",
+			title:       "[WIP] Null resource",
+			draft:       github.Ptr(false),
+			exp:         models.OtherPullEvent,
+		},
+		{
+			description:   "title prefix removed",
+			detection:     bitbucketserver.DraftDetectionTitle,
+			header:        "pr:modified",
+			title:         "Null resource",
+			previousTitle: github.Ptr("WIP: Null resource"),
+			exp:           models.UpdatedPullEvent,
+		},
+		{
+			description:   "title changed",
+			detection:     bitbucketserver.DraftDetectionTitle,
+			header:        "pr:modified",
+			title:         "Null resource",
+			previousTitle: github.Ptr("Null"),
+			exp:           models.OtherPullEvent,
+		},
+		{
+			description:   "title prefix removed when detecting by field",
+			detection:     bitbucketserver.DraftDetectionField,
+			header:        "pr:modified",
+			title:         "Null resource",
+			previousTitle: github.Ptr("WIP: Null resource"),
+			exp:           models.OtherPullEvent,
+		},
+		{
+			description:   "draft field unset",
+			detection:     bitbucketserver.DraftDetectionField,
+			header:        "pr:modified",
+			title:         "Null resource",
+			draft:         github.Ptr(false),
+			previousDraft: github.Ptr(true),
+			exp:           models.UpdatedPullEvent,
+		},
+		{
+			description:   "draft field set",
+			detection:     bitbucketserver.DraftDetectionField,
+			header:        "pr:modified",
+			title:         "Null resource",
+			draft:         github.Ptr(true),
+			previousDraft: github.Ptr(false),
+			exp:           models.OtherPullEvent,
+		},
+		{
+			description:   "draft field unset when detecting by title",
+			detection:     bitbucketserver.DraftDetectionTitle,
+			header:        "pr:modified",
+			title:         "Null resource",
+			draft:         github.Ptr(false),
+			previousDraft: github.Ptr(true),
+			exp:           models.OtherPullEvent,
+		},
+		{
+			description:   "draft field unset with any",
+			detection:     bitbucketserver.DraftDetectionAny,
+			header:        "pr:modified",
+			title:         "Null resource",
+			draft:         github.Ptr(false),
+			previousDraft: github.Ptr(true),
+			exp:           models.UpdatedPullEvent,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			var event map[string]interface{}
+			Ok(t, json.Unmarshal(bytes, &event))
+			pullRequest := event["pullRequest"].(map[string]interface{})
+			pullRequest["title"] = c.title
+			if c.draft != nil {
+				pullRequest["draft"] = *c.draft
+			}
+			if c.previousTitle != nil {
+				event["previousTitle"] = *c.previousTitle
+			}
+			if c.previousDraft != nil {
+				event["previousDraft"] = *c.previousDraft
+			}
+			body, err := json.Marshal(event)
+			Ok(t, err)
+
+			draftParser := parser
+			draftParser.AllowDraftPRs = c.allowDrafts
+			draftParser.BitbucketServerDraftDetection = c.detection
+			draftParser.BitbucketServerDraftTitlePrefixes = []string{"WIP:", " [WIP]", "Draft:"}
+			_, act, _, _, _, err := draftParser.ParseBitbucketServerPullEvent(c.header, body)
+			Ok(t, err)
+			Equals(t, c.exp, act)
+		})
+	}
+}
+
 func TestGetBitbucketServerEventType(t *testing.T) {
 	cases := []struct {
 		header string
@@ -61,6 +61,15 @@ func (b *InstrumentedProjectCommandBuilder) BuildStateRmCommands(ctx *command.Co
 	)
 }
 
+func (b *InstrumentedProjectCommandBuilder) BuildDriftCommands(ctx *command.Context, comment *CommentCommand) ([]command.ProjectContext, error) {
+	return b.buildAndEmitStats(
+		"drift",
+		func() ([]command.ProjectContext, error) {
+			return b.ProjectCommandBuilder.BuildDriftCommands(ctx, comment)
+		},
+	)
+}
+
 func (b *InstrumentedProjectCommandBuilder) buildAndEmitStats(
 	command string,
 	execute func() ([]command.ProjectContext, error),
@@ -0,0 +1,96 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/core/db"
+	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// LockExpirySweeper releases project locks that have been held for longer
+// than TTL so abandoned pull requests don't block other pull requests forever.
+// It's run periodically by the scheduled executor service.
+type LockExpirySweeper struct {
+	TTL               time.Duration
+	Locker            locking.Locker
+	DeleteLockCommand DeleteLockCommand
+	WorkingDirLocker  WorkingDirLocker
+	Database          db.Database
+	VCSClient         vcs.Client
+	Logger            logging.SimpleLogging
+}
+
+// Run releases every expired lock that isn't being used by a command.
+func (s *LockExpirySweeper) Run() {
+	if s.TTL <= 0 {
+		return
+	}
+	locks, err := s.Locker.List()
+	if err != nil {
+		s.Logger.Err("listing locks to expire: %s", err)
+		return
+	}
+
+	var keys []string
+	for key := range locks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if lock := locks[key]; time.Since(lock.Time) >= s.TTL {
+			s.expire(key, lock)
+		}
+	}
+}
+
+func (s *LockExpirySweeper) expire(key string, lock models.ProjectLock) {
+	// Commands hold the working dir lock while they run so if we can't get
+	// it, the lock is in use and must not be released.
+	unlockFn, err := s.WorkingDirLocker.TryLock(lock.Pull.BaseRepo.FullName, lock.Pull.Num, lock.Workspace, lock.Project.Path, command.Unlock)
+	if err != nil {
+		s.Logger.Debug("not expiring lock %q since a command is running: %s", key, err)
+		return
+	}
+	defer unlockFn()
+
+	// The lock could have been released and acquired again since we listed
+	// it.
+	current, err := s.Locker.GetLock(key)
+	if err != nil {
+		s.Logger.Err("getting lock %q to expire: %s", key, err)
+		return
+	}
+	if current == nil || current.Pull.Num != lock.Pull.Num || !current.Time.Equal(lock.Time) {
+		return
+	}
+
+	if _, err := s.DeleteLockCommand.DeleteLock(s.Logger, key); err != nil {
+		s.Logger.Err("expiring lock %q: %s", key, err)
+		return
+	}
+	s.Logger.Info("released lock %q held by pull request %d since %s because it exceeded the lock TTL of %s", key, lock.Pull.Num, lock.Time.Format(time.RFC3339), s.TTL)
+
+	// Locks from older installations don't have BaseRepo set so we can't
+	// comment on their pull requests.
+	if lock.Pull.BaseRepo == (models.Repo{}) {
+		return
+	}
+	if err := s.Database.UpdateProjectStatus(lock.Pull, lock.Workspace, lock.Project.Path, models.DiscardedPlanStatus); err != nil {
+		s.Logger.Err("unable to update project status: %s", err)
+	}
+	comment := fmt.Sprintf("**Warning**: The lock for dir: `%s` workspace: `%s` was held for longer than %s and has been **released**. "+
+		"The plan was discarded.\n\n"+
+		"To `apply` this plan you must run `plan` again.", lock.Project.Path, lock.Workspace, s.TTL)
+	if _, err := s.VCSClient.CreateComment(s.Logger, lock.Pull.BaseRepo, lock.Pull.Num, comment, ""); err != nil {
+		s.Logger.Warn("failed commenting on pull request: %s", err)
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package events_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/petergtz/pegomock/v4"
+	dbmocks "github.com/runatlantis/atlantis/server/core/db/mocks"
+	lockmocks "github.com/runatlantis/atlantis/server/core/locking/mocks"
+	"github.com/runatlantis/atlantis/server/events"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/mocks"
+	"github.com/runatlantis/atlantis/server/events/models"
+	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestLockExpirySweeper_Run(t *testing.T) {
+	RegisterMockTestingT(t)
+	repo := models.Repo{FullName: "owner/repo"}
+	expired := models.ProjectLock{
+		Project:   models.Project{RepoFullName: "owner/repo", Path: "expired"},
+		Workspace: "default",
+		Pull:      models.PullRequest{Num: 1, BaseRepo: repo},
+		Time:      time.Now().Add(-25 * time.Hour),
+	}
+	running := models.ProjectLock{
+		Project:   models.Project{RepoFullName: "owner/repo", Path: "running"},
+		Workspace: "default",
+		Pull:      models.PullRequest{Num: 2, BaseRepo: repo},
+		Time:      time.Now().Add(-25 * time.Hour),
+	}
+	fresh := models.ProjectLock{
+		Project:   models.Project{RepoFullName: "owner/repo", Path: "fresh"},
+		Workspace: "default",
+		Pull:      models.PullRequest{Num: 3, BaseRepo: repo},
+		Time:      time.Now().Add(-time.Hour),
+	}
+	locker := lockmocks.NewMockLocker()
+	When(locker.List()).ThenReturn(map[string]models.ProjectLock{
+		"owner/repo/expired/default": expired,
+		"owner/repo/running/default": running,
+		"owner/repo/fresh/default":   fresh,
+	}, nil)
+	When(locker.GetLock("owner/repo/expired/default")).ThenReturn(&expired, nil)
+	deleteLockCommand := mocks.NewMockDeleteLockCommand()
+	When(deleteLockCommand.DeleteLock(Any[logging.SimpleLogging](), Eq("owner/repo/expired/default"))).ThenReturn(&expired, nil)
+	database := dbmocks.NewMockDatabase()
+	vcsClient := vcsmocks.NewMockClient()
+
+	// A command is running for the second lock so its working dir is
+	// locked.
+	workingDirLocker := events.NewDefaultWorkingDirLocker()
+	unlockRunning, err := workingDirLocker.TryLock("owner/repo", 2, "default", "running", command.Apply)
+	Ok(t, err)
+	defer unlockRunning()
+
+	sweeper := &events.LockExpirySweeper{
+		TTL:               24 * time.Hour,
+		Locker:            locker,
+		DeleteLockCommand: deleteLockCommand,
+		WorkingDirLocker:  workingDirLocker,
+		Database:          database,
+		VCSClient:         vcsClient,
+		Logger:            logging.NewNoopLogger(t),
+	}
+	sweeper.Run()
+
+	deleteLockCommand.VerifyWasCalledOnce().DeleteLock(Any[logging.SimpleLogging](), Eq("owner/repo/expired/default"))
+	deleteLockCommand.VerifyWasCalled(Never()).DeleteLock(Any[logging.SimpleLogging](), Eq("owner/repo/running/default"))
+	deleteLockCommand.VerifyWasCalled(Never()).DeleteLock(Any[logging.SimpleLogging](), Eq("owner/repo/fresh/default"))
+	database.VerifyWasCalledOnce().UpdateProjectStatus(expired.Pull, "default", "expired", models.DiscardedPlanStatus)
+	_, _, pullNum, _, _ := vcsClient.VerifyWasCalledOnce().CreateComment(Any[logging.SimpleLogging](), Any[models.Repo](), Any[int](), Any[string](), Any[string]()).GetCapturedArguments()
+	Equals(t, 1, pullNum)
+
+	// The sweeper must release the working dir lock it took.
+	unlock, err := workingDirLocker.TryLock("owner/repo", 1, "default", "expired", command.Plan)
+	Ok(t, err)
+	unlock()
+}
+
+func TestLockExpirySweeper_Run_Reacquired(t *testing.T) {
+	RegisterMockTestingT(t)
+	listed := models.ProjectLock{
+		Project:   models.Project{RepoFullName: "owner/repo", Path: "."},
+		Workspace: "default",
+		Pull:      models.PullRequest{Num: 1},
+		Time:      time.Now().Add(-25 * time.Hour),
+	}
+	// The lock was released and taken by another pull request after it was
+	// listed.
+	current := listed
+	current.Pull = models.PullRequest{Num: 2}
+	current.Time = time.Now()
+	locker := lockmocks.NewMockLocker()
+	When(locker.List()).ThenReturn(map[string]models.ProjectLock{"owner/repo/./default": listed}, nil)
+	When(locker.GetLock("owner/repo/./default")).ThenReturn(&current, nil)
+	deleteLockCommand := mocks.NewMockDeleteLockCommand()
+
+	sweeper := &events.LockExpirySweeper{
+		TTL:               24 * time.Hour,
+		Locker:            locker,
+		DeleteLockCommand: deleteLockCommand,
+		WorkingDirLocker:  events.NewDefaultWorkingDirLocker(),
+		Database:          dbmocks.NewMockDatabase(),
+		VCSClient:         vcsmocks.NewMockClient(),
+		Logger:            logging.NewNoopLogger(t),
+	}
+	sweeper.Run()
+
+	deleteLockCommand.VerifyWasCalled(Never()).DeleteLock(Any[logging.SimpleLogging](), Any[string]())
+}
+
+func TestLockExpirySweeper_Run_Disabled(t *testing.T) {
+	RegisterMockTestingT(t)
+	locker := lockmocks.NewMockLocker()
+	sweeper := &events.LockExpirySweeper{
+		Locker: locker,
+		Logger: logging.NewNoopLogger(t),
+	}
+	sweeper.Run()
+	locker.VerifyWasCalled(Never()).List()
+}
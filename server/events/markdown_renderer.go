@@ -17,10 +17,13 @@ import (
 	"bytes"
 	"embed"
 	"fmt"
+	"path/filepath"
+	"regexp"
 	"strings"
 	"text/template"
 
 	"github.com/Masterminds/sprig/v3"
+	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"golang.org/x/text/cases"
@@ -35,9 +38,12 @@ var (
 	versionCommandTitle         = command.Version.TitleString()
 	importCommandTitle          = command.Import.TitleString()
 	stateCommandTitle           = command.State.TitleString()
+	driftCommandTitle           = command.Drift.TitleString()
 	// maxUnwrappedLines is the maximum number of lines the Terraform output
 	// can be before we wrap it in an expandable template.
 	maxUnwrappedLines = 12
+	// reApplySummary matches the summary at the end of the apply output.
+	reApplySummary = regexp.MustCompile(`(?m)^Apply complete! Resources: .*$`)
 
 	//go:embed templates/*
 	templatesFS embed.FS
@@ -58,6 +64,9 @@ type MarkdownRenderer struct {
 	executableName            string
 	hideUnchangedPlanComments bool
 	quietPolicyChecks         bool
+	// maxCommentOutputSize is the most bytes of each project's plan or apply
+	// output that's commented. 0 means the output isn't truncated.
+	maxCommentOutputSize int
 }
 
 // commonData is data that all responses have.
@@ -112,14 +121,71 @@ type applyResultData struct {
 	NumApplyErrors    int
 }
 
+// projectTmplData is the metadata of the project a result is for, ex.
+// {{ .Project.Labels.team }}. It's the same in every template that renders
+// a single project.
+type projectTmplData struct {
+	Name      string
+	Dir       string
+	Workspace string
+	// Labels is never nil so templates can safely index it.
+	Labels map[string]string
+}
+
+func newProjectTmplData(result command.ProjectResult) projectTmplData {
+	labels := result.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	return projectTmplData{
+		Name:      result.ProjectName,
+		Dir:       result.RepoRelDir,
+		Workspace: result.Workspace,
+		Labels:    labels,
+	}
+}
+
 type planSuccessData struct {
 	models.PlanSuccess
+	Project                  projectTmplData
 	PlanSummary              string
 	PlanWasDeleted           bool
 	DisableApply             bool
 	DisableRepoLocking       bool
 	EnableDiffMarkdownFormat bool
 	PlanStats                models.PlanSuccessStats
+	// NoChangesMessage is the project's custom message, set only if the plan
+	// has no changes.
+	NoChangesMessage string
+	// ReviewCommentPath is the file the plan's output was posted on as a
+	// review comment, if it was.
+	ReviewCommentPath string
+	// OutputTruncated is set if TerraformOutput was cut to the max comment
+	// output size.
+	OutputTruncated bool
+	// FullOutputURL links to the project's job, which has the full output.
+	// It's only set if the output was truncated.
+	FullOutputURL string
+}
+
+type applySuccessData struct {
+	Project projectTmplData
+	Output  string
+	// Summary is the apply summary if it was cut from Output.
+	Summary string
+	// FullOutputURL links to the project's job, which has the full output.
+	// It's only set if the output was truncated.
+	FullOutputURL string
+}
+
+type driftSuccessData struct {
+	TerraformOutput  string
+	Drifted          bool
+	Summary          string
+	DriftedResources []string
+	// FullOutputURL links to the project's job, which has the full output.
+	// It's only set if the output was truncated.
+	FullOutputURL string
 }
 
 type policyCheckResultsData struct {
@@ -133,6 +199,7 @@ type policyCheckResultsData struct {
 }
 
 type projectResultTmplData struct {
+	Project      projectTmplData
 	Workspace    string
 	RepoRelDir   string
 	ProjectName  string
@@ -153,12 +220,13 @@ func NewMarkdownRenderer(
 	executableName string,
 	hideUnchangedPlanComments bool,
 	quietPolicyChecks bool,
+	maxCommentOutputSize int,
 ) *MarkdownRenderer {
-	var templates *template.Template
-	templates, _ = template.New("").Funcs(sprig.TxtFuncMap()).ParseFS(templatesFS, "templates/*.tmpl")
-	if overrides, err := templates.ParseGlob(fmt.Sprintf("%s/*.tmpl", markdownTemplateOverridesDir)); err == nil {
-		// doesn't override if templates directory doesn't exist
-		templates = overrides
+	templates, err := ParseMarkdownTemplates(markdownTemplateOverridesDir)
+	if err != nil {
+		// The server fails to start on errors in the overrides, so this
+		// only falls back to the default templates if it isn't checked.
+		templates, _ = ParseMarkdownTemplates("")
 	}
 	return &MarkdownRenderer{
 		gitlabSupportsCommonMark:  gitlabSupportsCommonMark,
@@ -171,12 +239,44 @@ func NewMarkdownRenderer(
 		executableName:            executableName,
 		hideUnchangedPlanComments: hideUnchangedPlanComments,
 		quietPolicyChecks:         quietPolicyChecks,
+		maxCommentOutputSize:      maxCommentOutputSize,
+	}
+}
+
+// ParseMarkdownTemplates returns the default markdown templates with the
+// overrides in the .tmpl files of overridesDir, which replace the default
+// templates they define. It returns an error if an override doesn't parse.
+// If overridesDir doesn't exist or has no .tmpl files the default templates
+// are used.
+func ParseMarkdownTemplates(overridesDir string) (*template.Template, error) {
+	templates := template.Must(template.New("").Funcs(sprig.TxtFuncMap()).ParseFS(templatesFS, "templates/*.tmpl"))
+	if overridesDir == "" {
+		return templates, nil
+	}
+	overrides, err := filepath.Glob(filepath.Join(overridesDir, "*.tmpl"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding markdown template overrides in %s", overridesDir)
+	}
+	if len(overrides) == 0 {
+		return templates, nil
+	}
+	if _, err := templates.ParseFiles(overrides...); err != nil {
+		return nil, errors.Wrapf(err, "parsing markdown template overrides in %s", overridesDir)
 	}
+	return templates, nil
 }
 
 // Render formats the data into a markdown string.
 // nolint: interfacer
 func (m *MarkdownRenderer) Render(ctx *command.Context, res command.Result, cmd PullCommand) string {
+	return m.RenderWithReviewComments(ctx, res, cmd, nil)
+}
+
+// RenderWithReviewComments formats the data like Render except the plan
+// output of each project that was posted as a review comment is replaced with
+// the file it was posted on. reviewCommentPaths[i] is the file for
+// res.ProjectResults[i] or empty if its output wasn't posted.
+func (m *MarkdownRenderer) RenderWithReviewComments(ctx *command.Context, res command.Result, cmd PullCommand, reviewCommentPaths []string) string {
 	commandStr := cases.Title(language.English).String(strings.ReplaceAll(cmd.CommandName().String(), "_", " "))
 	var vcsRequestType string
 	if ctx.Pull.BaseRepo.VCSHost.Type == models.Gitlab {
@@ -209,10 +309,10 @@ func (m *MarkdownRenderer) Render(ctx *command.Context, res command.Result, cmd
 	if res.Failure != "" {
 		return m.renderTemplateTrimSpace(templates.Lookup("failureWithLog"), failureData{res.Failure, "", common})
 	}
-	return m.renderProjectResults(ctx, res.ProjectResults, common)
+	return m.renderProjectResults(ctx, res.ProjectResults, reviewCommentPaths, common)
 }
 
-func (m *MarkdownRenderer) renderProjectResults(ctx *command.Context, results []command.ProjectResult, common commonData) string {
+func (m *MarkdownRenderer) renderProjectResults(ctx *command.Context, results []command.ProjectResult, reviewCommentPaths []string, common commonData) string {
 	vcsHost := ctx.Pull.BaseRepo.VCSHost.Type
 
 	var resultsTmplData []projectResultTmplData
@@ -228,8 +328,9 @@ func (m *MarkdownRenderer) renderProjectResults(ctx *command.Context, results []
 
 	templates := m.markdownTemplates
 
-	for _, result := range results {
+	for i, result := range results {
 		resultData := projectResultTmplData{
+			Project:      newProjectTmplData(result),
 			Workspace:    result.Workspace,
 			RepoRelDir:   result.RepoRelDir,
 			ProjectName:  result.ProjectName,
@@ -239,12 +340,27 @@ func (m *MarkdownRenderer) renderProjectResults(ctx *command.Context, results []
 			result.PlanSuccess.TerraformOutput = strings.TrimSpace(result.PlanSuccess.TerraformOutput)
 			data := planSuccessData{
 				PlanSuccess:              *result.PlanSuccess,
+				Project:                  resultData.Project,
 				PlanWasDeleted:           common.PlansDeleted,
 				DisableApply:             common.DisableApply,
 				DisableRepoLocking:       common.DisableRepoLocking,
 				EnableDiffMarkdownFormat: common.EnableDiffMarkdownFormat,
 				PlanStats:                result.PlanSuccess.Stats(),
 			}
+			if result.PlanSuccess.NoChanges() {
+				data.NoChangesMessage = result.NoChangesMessage
+			}
+			if i < len(reviewCommentPaths) {
+				data.ReviewCommentPath = reviewCommentPaths[i]
+			}
+			// The summary is taken from the full output so it's kept even if
+			// the output is cut.
+			if output, truncated := truncateOutput(data.TerraformOutput, m.maxCommentOutputSize); truncated {
+				data.TerraformOutput = output
+				data.OutputTruncated = true
+				data.PlanSummary = result.PlanSuccess.Summary()
+				data.FullOutputURL = result.JobURL
+			}
 			if m.shouldUseWrappedTmpl(vcsHost, result.PlanSuccess.TerraformOutput) {
 				data.PlanSummary = result.PlanSuccess.Summary()
 				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("planSuccessWrapped"), data)
@@ -293,11 +409,18 @@ func (m *MarkdownRenderer) renderProjectResults(ctx *command.Context, results []
 				numPolicyApprovalSuccesses++
 			}
 		} else if result.ApplySuccess != "" {
-			output := strings.TrimSpace(result.ApplySuccess)
+			data := applySuccessData{Project: resultData.Project, Output: strings.TrimSpace(result.ApplySuccess)}
+			if output, truncated := truncateOutput(data.Output, m.maxCommentOutputSize); truncated {
+				if summary := reApplySummary.FindString(data.Output); !strings.Contains(output, summary) {
+					data.Summary = summary
+				}
+				data.Output = output
+				data.FullOutputURL = result.JobURL
+			}
 			if m.shouldUseWrappedTmpl(vcsHost, result.ApplySuccess) {
-				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("applyWrappedSuccess"), struct{ Output string }{output})
+				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("applyWrappedSuccess"), data)
 			} else {
-				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("applyUnwrappedSuccess"), struct{ Output string }{output})
+				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("applyUnwrappedSuccess"), data)
 			}
 			numApplySuccesses++
 		} else if result.VersionSuccess != "" {
@@ -322,6 +445,24 @@ func (m *MarkdownRenderer) renderProjectResults(ctx *command.Context, results []
 			} else {
 				resultData.Rendered = m.renderTemplateTrimSpace(templates.Lookup("stateRmSuccessUnwrapped"), result.StateRmSuccess)
 			}
+		} else if result.DriftSuccess != nil {
+			// The summary and resources are taken from the full output so
+			// they're kept even if the output is cut.
+			data := driftSuccessData{
+				TerraformOutput:  strings.TrimSpace(result.DriftSuccess.TerraformOutput),
+				Drifted:          result.DriftSuccess.Drifted,
+				Summary:          result.DriftSuccess.Summary(),
+				DriftedResources: result.DriftSuccess.DriftedResources(),
+			}
+			if output, truncated := truncateOutput(data.TerraformOutput, m.maxCommentOutputSize); truncated {
+				data.FullOutputURL = result.JobURL
+				data.TerraformOutput = output
+			}
+			tmpl := templates.Lookup("driftSuccessUnwrapped")
+			if m.shouldUseWrappedTmpl(vcsHost, result.DriftSuccess.TerraformOutput) {
+				tmpl = templates.Lookup("driftSuccessWrapped")
+			}
+			resultData.Rendered = m.renderTemplateTrimSpace(tmpl, data)
 			// Error out if no template was found, only if there are no errors or failures.
 			// This is because some errors and failures rely on additional context rendered by templates, but not all errors or failures.
 		} else if result.Error == nil && result.Failure == "" {
@@ -343,6 +484,10 @@ func (m *MarkdownRenderer) renderProjectResults(ctx *command.Context, results []
 				numApplyFailures++
 			}
 		}
+		if result.TerraformDebugLog != "" {
+			debugLog := m.renderTemplateTrimSpace(templates.Lookup("terraformDebugLog"), struct{ TerraformDebugLog string }{result.TerraformDebugLog})
+			resultData.Rendered = fmt.Sprintf("%s\n\n%s", resultData.Rendered, debugLog)
+		}
 		resultsTmplData = append(resultsTmplData, resultData)
 	}
 
@@ -364,6 +509,8 @@ func (m *MarkdownRenderer) renderProjectResults(ctx *command.Context, results []
 		tmpl = templates.Lookup("singleProjectApply")
 	case len(resultsTmplData) == 1 && common.Command == importCommandTitle:
 		tmpl = templates.Lookup("singleProjectImport")
+	case len(resultsTmplData) == 1 && common.Command == driftCommandTitle:
+		tmpl = templates.Lookup("singleProjectDrift")
 	case len(resultsTmplData) == 1 && common.Command == stateCommandTitle:
 		switch common.SubCommand {
 		case "rm":
@@ -391,6 +538,8 @@ func (m *MarkdownRenderer) renderProjectResults(ctx *command.Context, results []
 		tmpl = templates.Lookup("multiProjectVersion")
 	case common.Command == importCommandTitle:
 		tmpl = templates.Lookup("multiProjectImport")
+	case common.Command == driftCommandTitle:
+		tmpl = templates.Lookup("multiProjectDrift")
 	case common.Command == stateCommandTitle:
 		switch common.SubCommand {
 		case "rm":
@@ -412,6 +561,22 @@ func (m *MarkdownRenderer) renderProjectResults(ctx *command.Context, results []
 	return m.renderTemplateTrimSpace(tmpl, resultData{resultsTmplData, common})
 }
 
+// outputTruncatedNotice ends output that was cut to the max comment output
+// size.
+const outputTruncatedNotice = "... output truncated"
+
+// truncateOutput cuts output to at most maxSize bytes, including the
+// outputTruncatedNotice it ends with, at a line boundary. It returns whether
+// output was cut.
+func truncateOutput(output string, maxSize int) (string, bool) {
+	if maxSize <= 0 || len(output) <= maxSize {
+		return output, false
+	}
+	cut := max(maxSize-len(outputTruncatedNotice), 0)
+	cut = strings.LastIndex(output[:cut], "\n") + 1
+	return output[:cut] + outputTruncatedNotice, true
+}
+
 // shouldUseWrappedTmpl returns true if we should use the wrapped markdown
 // templates that collapse the output to make the comment smaller on initial
 // load. Some VCS providers or versions of VCS providers don't support this
@@ -14,9 +14,11 @@
 package events_test
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -71,6 +73,7 @@ func TestRenderErr(t *testing.T) {
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -146,6 +149,7 @@ func TestRenderFailure(t *testing.T) {
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -196,6 +200,7 @@ func TestRenderErrAndFailure(t *testing.T) {
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	ctx := &command.Context{
@@ -319,6 +324,108 @@ $$$
   $$$
 :twisted_rightwards_arrows: Upstream was modified, a new merge was performed.
 
+---
+* :fast_forward: To **apply** all unapplied plans from this Pull Request, comment:
+  $$$shell
+  atlantis apply
+  $$$
+* :put_litter_in_its_place: To **delete** all plans and locks from this Pull Request, comment:
+  $$$shell
+  atlantis unlock
+  $$$
+`,
+		},
+		{
+			"single successful plan with created workspace",
+			command.Plan,
+			"",
+			[]command.ProjectResult{
+				{
+					PlanSuccess: &models.PlanSuccess{
+						TerraformOutput:  "terraform-output",
+						LockURL:          "lock-url",
+						RePlanCmd:        "atlantis plan -d path -w workspace",
+						ApplyCmd:         "atlantis apply -d path -w workspace",
+						MergedAgain:      true,
+						CreatedWorkspace: "workspace",
+					},
+					Workspace:  "workspace",
+					RepoRelDir: "path",
+				},
+			},
+			models.Github,
+			`
+Ran Plan for dir: $path$ workspace: $workspace$
+
+$$$diff
+terraform-output
+$$$
+
+* :arrow_forward: To **apply** this plan, comment:
+  $$$shell
+  atlantis apply -d path -w workspace
+  $$$
+* :put_litter_in_its_place: To **delete** this plan and lock, click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+  $$$shell
+  atlantis plan -d path -w workspace
+  $$$
+:twisted_rightwards_arrows: Upstream was modified, a new merge was performed.
+:new: The $workspace$ workspace didn't exist so it was created.
+
+---
+* :fast_forward: To **apply** all unapplied plans from this Pull Request, comment:
+  $$$shell
+  atlantis apply
+  $$$
+* :put_litter_in_its_place: To **delete** all plans and locks from this Pull Request, comment:
+  $$$shell
+  atlantis unlock
+  $$$
+`,
+		},
+		{
+			"single successful plan with cost estimate",
+			command.Plan,
+			"",
+			[]command.ProjectResult{
+				{
+					PlanSuccess: &models.PlanSuccess{
+						TerraformOutput: "terraform-output",
+						LockURL:         "lock-url",
+						RePlanCmd:       "atlantis plan -d path -w workspace",
+						ApplyCmd:        "atlantis apply -d path -w workspace",
+						CostEstimate:    "Monthly cost will increase by $10",
+					},
+					Workspace:  "workspace",
+					RepoRelDir: "path",
+				},
+			},
+			models.Github,
+			`
+Ran Plan for dir: $path$ workspace: $workspace$
+
+$$$diff
+terraform-output
+$$$
+
+<details><summary>Cost Estimate</summary>
+
+$$$
+Monthly cost will increase by $10
+$$$
+</details>
+
+* :arrow_forward: To **apply** this plan, comment:
+  $$$shell
+  atlantis apply -d path -w workspace
+  $$$
+* :put_litter_in_its_place: To **delete** this plan and lock, click [here](lock-url)
+* :repeat: To **plan** this project again, comment:
+  $$$shell
+  atlantis plan -d path -w workspace
+  $$$
+
 ---
 * :fast_forward: To **apply** all unapplied plans from this Pull Request, comment:
   $$$shell
@@ -1203,6 +1310,7 @@ $$$
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -1577,6 +1685,7 @@ $$$
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		true,       // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -1778,6 +1887,7 @@ $$$
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -1963,6 +2073,7 @@ $$$
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -2002,6 +2113,32 @@ $$$
 	}
 }
 
+func TestParseMarkdownTemplates(t *testing.T) {
+	t.Run("override", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		Ok(t, os.WriteFile(filepath.Join(tmpDir, "plan.tmpl"), []byte(`{{ define "planSuccessUnwrapped" }}custom plan{{ end }}`), 0600))
+		templates, err := events.ParseMarkdownTemplates(tmpDir)
+		Ok(t, err)
+		buf := &bytes.Buffer{}
+		Ok(t, templates.Lookup("planSuccessUnwrapped").Execute(buf, nil))
+		Equals(t, "custom plan", buf.String())
+		Assert(t, templates.Lookup("applyUnwrappedSuccess") != nil, "exp default apply template")
+	})
+
+	t.Run("missing dir", func(t *testing.T) {
+		templates, err := events.ParseMarkdownTemplates(filepath.Join(t.TempDir(), "missing"))
+		Ok(t, err)
+		Assert(t, templates.Lookup("planSuccessUnwrapped") != nil, "exp default plan template")
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		tmpDir := t.TempDir()
+		Ok(t, os.WriteFile(filepath.Join(tmpDir, "plan.tmpl"), []byte(`{{ define "planSuccessUnwrapped" }}{{ .TerraformOutput }`), 0600))
+		_, err := events.ParseMarkdownTemplates(tmpDir)
+		ErrContains(t, "parsing markdown template overrides in "+tmpDir, err)
+	})
+}
+
 // Run policy check with a custom template to validate custom template rendering.
 func TestRenderCustomPolicyCheckTemplate_DisableApplyAll(t *testing.T) {
 	var exp string
@@ -2022,6 +2159,7 @@ func TestRenderCustomPolicyCheckTemplate_DisableApplyAll(t *testing.T) {
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -2097,6 +2235,7 @@ func TestRenderProjectResults_DisableFolding(t *testing.T) {
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -2207,6 +2346,7 @@ func TestRenderProjectResults_WrappedErr(t *testing.T) {
 					"atlantis",                // executableName
 					false,                     // hideUnchangedPlanComments
 					false,                     // quietPolicyChecks
+					0,                         // maxCommentOutputSize
 				)
 				logger := logging.NewNoopLogger(t).WithHistory()
 				logText := "log"
@@ -2353,6 +2493,7 @@ func TestRenderProjectResults_WrapSingleProject(t *testing.T) {
 						"atlantis",                // executableName
 						false,                     // hideUnchangedPlanComments
 						false,                     // quietPolicyChecks
+						0,                         // maxCommentOutputSize
 					)
 					logger := logging.NewNoopLogger(t).WithHistory()
 					logText := "log"
@@ -2504,6 +2645,7 @@ func TestRenderProjectResults_MultiProjectApplyWrapped(t *testing.T) {
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -2584,6 +2726,7 @@ func TestRenderProjectResults_MultiProjectPlanWrapped(t *testing.T) {
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -2811,6 +2954,7 @@ This plan was not saved because one or more projects failed and automerge requir
 				"atlantis", // executableName
 				false,      // hideUnchangedPlanComments
 				false,      // quietPolicyChecks
+				0,          // maxCommentOutputSize
 			)
 			logger := logging.NewNoopLogger(t).WithHistory()
 			logText := "log"
@@ -3368,6 +3512,7 @@ $$$
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -3506,6 +3651,7 @@ $$$
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -3966,6 +4112,7 @@ func TestRenderProjectResultsWithEnableDiffMarkdownFormat(t *testing.T) {
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -4022,6 +4169,7 @@ func BenchmarkRenderProjectResultsWithEnableDiffMarkdownFormat(b *testing.B) {
 		"atlantis", // executableName
 		false,      // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(b).WithHistory()
 	logText := "log"
@@ -4235,6 +4383,7 @@ Ran Plan for 3 projects:
 		"atlantis", // executableName
 		true,       // hideUnchangedPlanComments
 		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
 	)
 	logger := logging.NewNoopLogger(t).WithHistory()
 	logText := "log"
@@ -4275,3 +4424,510 @@ Ran Plan for 3 projects:
 		})
 	}
 }
+
+func TestRenderProjectResults_NoChangesMessage(t *testing.T) {
+	message := "No changes, as expected for the nightly drift check."
+	cases := []struct {
+		description string
+		output      string
+		expMessage  bool
+	}{
+		{
+			description: "unwrapped no changes",
+			output:      "No changes. Your infrastructure matches the configuration.",
+			expMessage:  true,
+		},
+		{
+			description: "wrapped no changes",
+			output:      strings.Repeat("line\n", 13) + "No changes. Your infrastructure matches the configuration.",
+			expMessage:  true,
+		},
+		{
+			description: "changes",
+			output:      "Plan: 1 to add, 0 to change, 0 to destroy.",
+			expMessage:  false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mr := events.NewMarkdownRenderer(
+				false,      // gitlabSupportsCommonMark
+				false,      // disableApplyAll
+				false,      // disableApply
+				false,      // disableMarkdownFolding
+				false,      // disableRepoLocking
+				false,      // enableDiffMarkdownFormat
+				"",         // markdownTemplateOverridesDir
+				"atlantis", // executableName
+				false,      // hideUnchangedPlanComments
+				false,      // quietPolicyChecks
+				0,          // maxCommentOutputSize
+			)
+			ctx := &command.Context{
+				Log: logging.NewNoopLogger(t),
+				Pull: models.PullRequest{
+					BaseRepo: models.Repo{VCSHost: models.VCSHost{Type: models.Github}},
+				},
+			}
+			res := command.Result{
+				ProjectResults: []command.ProjectResult{
+					{
+						RepoRelDir: ".",
+						Workspace:  "default",
+						PlanSuccess: &models.PlanSuccess{
+							TerraformOutput: c.output,
+							LockURL:         "lock-url",
+							RePlanCmd:       "replancmd",
+							ApplyCmd:        "applycmd",
+						},
+						NoChangesMessage: message,
+					},
+				},
+			}
+			rendered := mr.Render(ctx, res, &events.CommentCommand{Name: command.Plan})
+			Equals(t, c.expMessage, strings.Contains(rendered, message))
+			if c.expMessage {
+				// The custom message replaces the generic summary.
+				Equals(t, 1, strings.Count(rendered, "No changes. Your infrastructure matches the configuration."))
+			}
+		})
+	}
+}
+
+func TestRenderProjectResults_DestroyPlan(t *testing.T) {
+	warning := ":warning: **This is a destroy plan. Applying it will destroy all resources of this project.**"
+	cases := []struct {
+		description string
+		output      string
+		destroyPlan bool
+	}{
+		{
+			description: "unwrapped destroy plan",
+			output:      "Plan: 0 to add, 0 to change, 2 to destroy.",
+			destroyPlan: true,
+		},
+		{
+			description: "wrapped destroy plan",
+			output:      strings.Repeat("line\n", 13) + "Plan: 0 to add, 0 to change, 2 to destroy.",
+			destroyPlan: true,
+		},
+		{
+			description: "plan",
+			output:      "Plan: 0 to add, 0 to change, 2 to destroy.",
+			destroyPlan: false,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mr := events.NewMarkdownRenderer(
+				false,      // gitlabSupportsCommonMark
+				false,      // disableApplyAll
+				false,      // disableApply
+				false,      // disableMarkdownFolding
+				false,      // disableRepoLocking
+				false,      // enableDiffMarkdownFormat
+				"",         // markdownTemplateOverridesDir
+				"atlantis", // executableName
+				false,      // hideUnchangedPlanComments
+				false,      // quietPolicyChecks
+				0,          // maxCommentOutputSize
+			)
+			ctx := &command.Context{
+				Log: logging.NewNoopLogger(t),
+				Pull: models.PullRequest{
+					BaseRepo: models.Repo{VCSHost: models.VCSHost{Type: models.Github}},
+				},
+			}
+			res := command.Result{
+				ProjectResults: []command.ProjectResult{
+					{
+						RepoRelDir: ".",
+						Workspace:  "default",
+						PlanSuccess: &models.PlanSuccess{
+							TerraformOutput: c.output,
+							LockURL:         "lock-url",
+							RePlanCmd:       "replancmd",
+							ApplyCmd:        "applycmd",
+							DestroyPlan:     c.destroyPlan,
+						},
+					},
+				},
+			}
+			rendered := mr.Render(ctx, res, &events.CommentCommand{Name: command.Plan})
+			Equals(t, c.destroyPlan, strings.Contains(rendered, warning))
+		})
+	}
+}
+
+func TestRenderProjectResults_DestroyThreshold(t *testing.T) {
+	warning := ":warning: **This plan destroys 6 resources, more than the project's threshold of 5.** Review it carefully before applying."
+	approval := "The pull request must be approved to apply it."
+	cases := []struct {
+		description string
+		threshold   int
+		approval    bool
+		output      string
+		expWarning  bool
+	}{
+		{
+			description: "above threshold",
+			threshold:   5,
+			output:      "Plan: 0 to add, 0 to change, 6 to destroy.",
+			expWarning:  true,
+		},
+		{
+			description: "above threshold with approval",
+			threshold:   5,
+			approval:    true,
+			output:      "Plan: 0 to add, 0 to change, 6 to destroy.",
+			expWarning:  true,
+		},
+		{
+			description: "at threshold",
+			threshold:   5,
+			output:      "Plan: 0 to add, 0 to change, 5 to destroy.",
+		},
+		{
+			description: "disabled",
+			output:      "Plan: 0 to add, 0 to change, 6 to destroy.",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mr := events.NewMarkdownRenderer(
+				false,      // gitlabSupportsCommonMark
+				false,      // disableApplyAll
+				false,      // disableApply
+				false,      // disableMarkdownFolding
+				false,      // disableRepoLocking
+				false,      // enableDiffMarkdownFormat
+				"",         // markdownTemplateOverridesDir
+				"atlantis", // executableName
+				false,      // hideUnchangedPlanComments
+				false,      // quietPolicyChecks
+				0,          // maxCommentOutputSize
+			)
+			ctx := &command.Context{
+				Log: logging.NewNoopLogger(t),
+				Pull: models.PullRequest{
+					BaseRepo: models.Repo{VCSHost: models.VCSHost{Type: models.Github}},
+				},
+			}
+			res := command.Result{
+				ProjectResults: []command.ProjectResult{
+					{
+						RepoRelDir: ".",
+						Workspace:  "default",
+						PlanSuccess: &models.PlanSuccess{
+							TerraformOutput:          c.output,
+							LockURL:                  "lock-url",
+							RePlanCmd:                "replancmd",
+							ApplyCmd:                 "applycmd",
+							DestroyThreshold:         c.threshold,
+							DestroyThresholdApproval: c.approval,
+						},
+					},
+				},
+			}
+			rendered := mr.Render(ctx, res, &events.CommentCommand{Name: command.Plan})
+			Equals(t, c.expWarning, strings.Contains(rendered, warning))
+			Equals(t, c.approval, strings.Contains(rendered, approval))
+		})
+	}
+}
+
+func TestRenderProjectResults_TerraformDebugLog(t *testing.T) {
+	mr := events.NewMarkdownRenderer(
+		false,      // gitlabSupportsCommonMark
+		false,      // disableApplyAll
+		false,      // disableApply
+		false,      // disableMarkdownFolding
+		false,      // disableRepoLocking
+		false,      // enableDiffMarkdownFormat
+		"",         // markdownTemplateOverridesDir
+		"atlantis", // executableName
+		false,      // hideUnchangedPlanComments
+		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
+	)
+	ctx := &command.Context{
+		Log: logging.NewNoopLogger(t).WithHistory(),
+		Pull: models.PullRequest{
+			BaseRepo: models.Repo{VCSHost: models.VCSHost{Type: models.Github}},
+		},
+	}
+	res := command.Result{
+		ProjectResults: []command.ProjectResult{
+			{
+				RepoRelDir: "success",
+				Workspace:  "default",
+				PlanSuccess: &models.PlanSuccess{
+					TerraformOutput: "Plan: 1 to add, 0 to change, 0 to destroy.",
+					LockURL:         "lock-url",
+					RePlanCmd:       "replancmd",
+					ApplyCmd:        "applycmd",
+				},
+				TerraformDebugLog: "[DEBUG] success log",
+			},
+			{
+				RepoRelDir:        "error",
+				Workspace:         "default",
+				Error:             errors.New("provider crashed"),
+				TerraformDebugLog: "[DEBUG] error log",
+			},
+		},
+	}
+	rendered := mr.Render(ctx, res, &events.CommentCommand{Name: command.Plan, Verbose: true})
+	for _, log := range []string{"[DEBUG] success log", "[DEBUG] error log"} {
+		exp := "<details><summary>Terraform Debug Log</summary>\n\n```\n" + log + "\n```\n</details>"
+		Assert(t, strings.Contains(rendered, exp), "expected %q in %q", exp, rendered)
+	}
+}
+
+// Test that overridden templates can render the project's labels.
+func TestRenderProjectResults_ProjectLabels(t *testing.T) {
+	tmpDir := t.TempDir()
+	overrides := `{{ define "planSuccessUnwrapped" }}plan owned by {{ .Project.Labels.team }}{{ end }}
+{{ define "applyUnwrappedSuccess" }}apply owned by {{ .Project.Labels.team }}{{ end }}
+{{ define "multiProjectPlan" }}{{ range .Results }}{{ .Project.Name }}: {{ .Rendered }}{{ with .Project.Labels.team }} ({{ . }}){{ end }}
+{{ end }}{{ end }}`
+	Ok(t, os.WriteFile(filepath.Join(tmpDir, "labels.tmpl"), []byte(overrides), 0600))
+	mr := events.NewMarkdownRenderer(
+		false,      // gitlabSupportsCommonMark
+		false,      // disableApplyAll
+		false,      // disableApply
+		false,      // disableMarkdownFolding
+		false,      // disableRepoLocking
+		false,      // enableDiffMarkdownFormat
+		tmpDir,     // markdownTemplateOverridesDir
+		"atlantis", // executableName
+		false,      // hideUnchangedPlanComments
+		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
+	)
+	ctx := &command.Context{
+		Log: logging.NewNoopLogger(t).WithHistory(),
+		Pull: models.PullRequest{
+			BaseRepo: models.Repo{VCSHost: models.VCSHost{Type: models.Github}},
+		},
+	}
+	planSuccess := &models.PlanSuccess{TerraformOutput: "No changes."}
+	res := command.Result{
+		ProjectResults: []command.ProjectResult{
+			{
+				ProjectName: "network",
+				RepoRelDir:  "network",
+				Workspace:   "default",
+				PlanSuccess: planSuccess,
+				Labels:      map[string]string{"team": "platform"},
+			},
+			{
+				ProjectName: "app",
+				RepoRelDir:  "app",
+				Workspace:   "default",
+				PlanSuccess: planSuccess,
+			},
+		},
+	}
+	rendered := mr.Render(ctx, res, &events.CommentCommand{Name: command.Plan})
+	Equals(t, "network: plan owned by platform (platform)\napp: plan owned by <no value>", rendered)
+
+	res = command.Result{
+		ProjectResults: []command.ProjectResult{
+			{
+				ProjectName:  "network",
+				RepoRelDir:   "network",
+				Workspace:    "default",
+				ApplySuccess: "Apply complete!",
+				Labels:       map[string]string{"team": "platform"},
+			},
+		},
+	}
+	rendered = mr.Render(ctx, res, &events.CommentCommand{Name: command.Apply})
+	Assert(t, strings.Contains(rendered, "apply owned by platform"), "expected the label in %q", rendered)
+}
+
+func TestRenderProjectResults_MaxCommentOutputSize(t *testing.T) {
+	resourceLines := strings.Repeat("  + resource \"null_resource\" \"a\" {}\n", 20)
+	planOutput := resourceLines + "\nPlan: 20 to add, 0 to change, 0 to destroy."
+	applyOutput := resourceLines + "\nApply complete! Resources: 20 added, 0 changed, 0 destroyed."
+	cases := []struct {
+		description   string
+		command       command.Name
+		result        command.ProjectResult
+		disableFolds  bool
+		expContains   []string
+		expNotContain []string
+	}{
+		{
+			description: "wrapped plan",
+			command:     command.Plan,
+			result: command.ProjectResult{
+				PlanSuccess: &models.PlanSuccess{TerraformOutput: planOutput, ApplyCmd: "applycmd", RePlanCmd: "replancmd"},
+				JobURL:      "https://atlantis/jobs/1",
+			},
+			expContains: []string{
+				"  + resource \"null_resource\" \"a\" {}\n... output truncated\n```\n[Show full output](https://atlantis/jobs/1)\n</details>",
+				"Plan: 20 to add, 0 to change, 0 to destroy.",
+				"applycmd",
+			},
+		},
+		{
+			description: "unwrapped plan",
+			command:     command.Plan,
+			result: command.ProjectResult{
+				PlanSuccess: &models.PlanSuccess{TerraformOutput: planOutput, ApplyCmd: "applycmd", RePlanCmd: "replancmd"},
+			},
+			disableFolds: true,
+			expContains: []string{
+				"{}\n... output truncated\n```\nPlan: 20 to add, 0 to change, 0 to destroy.\n",
+				"applycmd",
+			},
+			expNotContain: []string{"Show full output"},
+		},
+		{
+			description: "apply",
+			command:     command.Apply,
+			result: command.ProjectResult{
+				ApplySuccess: applyOutput,
+				JobURL:       "https://atlantis/jobs/1",
+			},
+			expContains: []string{
+				"{}\n... output truncated\n```\nApply complete! Resources: 20 added, 0 changed, 0 destroyed.\n[Show full output](https://atlantis/jobs/1)",
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			mr := events.NewMarkdownRenderer(
+				false,          // gitlabSupportsCommonMark
+				false,          // disableApplyAll
+				false,          // disableApply
+				c.disableFolds, // disableMarkdownFolding
+				false,          // disableRepoLocking
+				false,          // enableDiffMarkdownFormat
+				"",             // markdownTemplateOverridesDir
+				"atlantis",     // executableName
+				false,          // hideUnchangedPlanComments
+				false,          // quietPolicyChecks
+				200,            // maxCommentOutputSize
+			)
+			ctx := &command.Context{
+				Log: logging.NewNoopLogger(t).WithHistory(),
+				Pull: models.PullRequest{
+					BaseRepo: models.Repo{VCSHost: models.VCSHost{Type: models.Github}},
+				},
+			}
+			c.result.RepoRelDir = "."
+			c.result.Workspace = "default"
+			rendered := mr.Render(ctx, command.Result{ProjectResults: []command.ProjectResult{c.result}}, &events.CommentCommand{Name: c.command})
+			for _, exp := range c.expContains {
+				Assert(t, strings.Contains(rendered, exp), "expected %q in %q", exp, rendered)
+			}
+			for _, exp := range c.expNotContain {
+				Assert(t, !strings.Contains(rendered, exp), "didn't expect %q in %q", exp, rendered)
+			}
+			// The output is cut to whole lines that fit in the limit.
+			Equals(t, 5, strings.Count(rendered, "resource \"null_resource\""))
+		})
+	}
+}
+
+func TestRenderProjectResults_Drift(t *testing.T) {
+	mr := events.NewMarkdownRenderer(
+		false,      // gitlabSupportsCommonMark
+		false,      // disableApplyAll
+		false,      // disableApply
+		true,       // disableMarkdownFolding
+		false,      // disableRepoLocking
+		false,      // enableDiffMarkdownFormat
+		"",         // markdownTemplateOverridesDir
+		"atlantis", // executableName
+		false,      // hideUnchangedPlanComments
+		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
+	)
+	ctx := &command.Context{
+		Log: logging.NewNoopLogger(t),
+		Pull: models.PullRequest{
+			BaseRepo: models.Repo{VCSHost: models.VCSHost{Type: models.Github}},
+		},
+	}
+	drifted := command.ProjectResult{
+		RepoRelDir: "drifted",
+		Workspace:  "default",
+		DriftSuccess: &models.DriftSuccess{
+			TerraformOutput: "  # aws_instance.web will be updated in-place\n  ~ resource \"aws_instance\" \"web\" {}\n\nPlan: 0 to add, 1 to change, 0 to destroy.",
+			Drifted:         true,
+		},
+	}
+	inSync := command.ProjectResult{
+		RepoRelDir: "in-sync",
+		Workspace:  "default",
+		DriftSuccess: &models.DriftSuccess{
+			TerraformOutput: "No changes. Your infrastructure matches the configuration.",
+		},
+	}
+
+	rendered := mr.Render(ctx, command.Result{ProjectResults: []command.ProjectResult{drifted}}, &events.CommentCommand{Name: command.Drift})
+	Equals(t, "Ran Drift for dir: `drifted` workspace: `default`\n\n"+
+		":warning: **Drift detected.** Plan: 0 to add, 1 to change, 0 to destroy.\n\n"+
+		"* `aws_instance.web` will be updated in-place\n\n"+
+		"```diff\n# aws_instance.web will be updated in-place\n  ~ resource \"aws_instance\" \"web\" {}\n\nPlan: 0 to add, 1 to change, 0 to destroy.\n```", rendered)
+
+	rendered = mr.Render(ctx, command.Result{ProjectResults: []command.ProjectResult{drifted, inSync}}, &events.CommentCommand{Name: command.Drift})
+	Assert(t, strings.HasPrefix(rendered, "Ran Drift for 2 projects:"), "got %q", rendered)
+	Assert(t, strings.Contains(rendered, "### 2. dir: `in-sync` workspace: `default`\n:white_check_mark: **No drift detected.** No changes. Your infrastructure matches the configuration."), "got %q", rendered)
+}
+
+func TestRenderProjectResults_PolicySoftFail(t *testing.T) {
+	mr := events.NewMarkdownRenderer(
+		false,      // gitlabSupportsCommonMark
+		false,      // disableApplyAll
+		false,      // disableApply
+		true,       // disableMarkdownFolding
+		false,      // disableRepoLocking
+		false,      // enableDiffMarkdownFormat
+		"",         // markdownTemplateOverridesDir
+		"atlantis", // executableName
+		false,      // hideUnchangedPlanComments
+		false,      // quietPolicyChecks
+		0,          // maxCommentOutputSize
+	)
+	ctx := &command.Context{
+		Log: logging.NewNoopLogger(t),
+		Pull: models.PullRequest{
+			BaseRepo: models.Repo{VCSHost: models.VCSHost{Type: models.Github}},
+		},
+	}
+	result := command.ProjectResult{
+		RepoRelDir: "path",
+		Workspace:  "default",
+		PolicyCheckResults: &models.PolicyCheckResults{
+			PolicySetResults: []models.PolicySetResult{
+				{
+					PolicySetName: "warnings",
+					PolicyOutput:  "1 test, 0 passed, 0 warnings, 1 failure, 0 exceptions",
+					ReqApprovals:  1,
+					SoftFail:      true,
+				},
+				{
+					PolicySetName: "required",
+					PolicyOutput:  "1 test, 1 passed, 0 warnings, 0 failures, 0 exceptions",
+					Passed:        true,
+					ReqApprovals:  1,
+				},
+			},
+			LockURL:   "lock-url",
+			RePlanCmd: "atlantis plan -d path",
+			ApplyCmd:  "atlantis apply -d path",
+		},
+	}
+
+	rendered := mr.Render(ctx, command.Result{ProjectResults: []command.ProjectResult{result}}, &events.CommentCommand{Name: command.PolicyCheck})
+	Assert(t, strings.Contains(rendered, "#### Policy Set: `warnings`\n:warning: **Warning:** this policy set failed but has `soft_fail` enabled, so it doesn't block apply.\n```diff\n"), "got %q", rendered)
+	Assert(t, strings.Contains(rendered, "#### Policy Set: `required`\n```diff\n"), "got %q", rendered)
+	// The soft failure doesn't require approval.
+	Assert(t, strings.Contains(rendered, "To **apply** this plan, comment:\n  ```shell\n  atlantis apply -d path\n  ```"), "got %q", rendered)
+	Assert(t, !strings.Contains(rendered, "Policy Approval Status"), "got %q", rendered)
+}
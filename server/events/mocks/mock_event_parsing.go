@@ -262,35 +262,39 @@ func (mock *MockEventParsing) ParseBitbucketServerPullCommentEvent(body []byte)
 	return _ret0, _ret1, _ret2, _ret3, _ret4, _ret5
 }
 
-func (mock *MockEventParsing) ParseBitbucketServerPullEvent(body []byte) (models.PullRequest, models.Repo, models.Repo, models.User, error) {
+func (mock *MockEventParsing) ParseBitbucketServerPullEvent(eventTypeHeader string, body []byte) (models.PullRequest, models.PullRequestEventType, models.Repo, models.Repo, models.User, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockEventParsing().")
 	}
-	_params := []pegomock.Param{body}
-	_result := pegomock.GetGenericMockFrom(mock).Invoke("ParseBitbucketServerPullEvent", _params, []reflect.Type{reflect.TypeOf((*models.PullRequest)(nil)).Elem(), reflect.TypeOf((*models.Repo)(nil)).Elem(), reflect.TypeOf((*models.Repo)(nil)).Elem(), reflect.TypeOf((*models.User)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	_params := []pegomock.Param{eventTypeHeader, body}
+	_result := pegomock.GetGenericMockFrom(mock).Invoke("ParseBitbucketServerPullEvent", _params, []reflect.Type{reflect.TypeOf((*models.PullRequest)(nil)).Elem(), reflect.TypeOf((*models.PullRequestEventType)(nil)).Elem(), reflect.TypeOf((*models.Repo)(nil)).Elem(), reflect.TypeOf((*models.Repo)(nil)).Elem(), reflect.TypeOf((*models.User)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
 	var _ret0 models.PullRequest
-	var _ret1 models.Repo
+	var _ret1 models.PullRequestEventType
 	var _ret2 models.Repo
-	var _ret3 models.User
-	var _ret4 error
+	var _ret3 models.Repo
+	var _ret4 models.User
+	var _ret5 error
 	if len(_result) != 0 {
 		if _result[0] != nil {
 			_ret0 = _result[0].(models.PullRequest)
 		}
 		if _result[1] != nil {
-			_ret1 = _result[1].(models.Repo)
+			_ret1 = _result[1].(models.PullRequestEventType)
 		}
 		if _result[2] != nil {
 			_ret2 = _result[2].(models.Repo)
 		}
 		if _result[3] != nil {
-			_ret3 = _result[3].(models.User)
+			_ret3 = _result[3].(models.Repo)
 		}
 		if _result[4] != nil {
-			_ret4 = _result[4].(error)
+			_ret4 = _result[4].(models.User)
+		}
+		if _result[5] != nil {
+			_ret5 = _result[5].(error)
 		}
 	}
-	return _ret0, _ret1, _ret2, _ret3, _ret4
+	return _ret0, _ret1, _ret2, _ret3, _ret4, _ret5
 }
 
 func (mock *MockEventParsing) ParseGiteaIssueCommentEvent(event gitea0.GiteaIssueCommentPayload) (models.Repo, models.User, int, error) {
@@ -908,8 +912,8 @@ func (c *MockEventParsing_ParseBitbucketServerPullCommentEvent_OngoingVerificati
 	return
 }
 
-func (verifier *VerifierMockEventParsing) ParseBitbucketServerPullEvent(body []byte) *MockEventParsing_ParseBitbucketServerPullEvent_OngoingVerification {
-	_params := []pegomock.Param{body}
+func (verifier *VerifierMockEventParsing) ParseBitbucketServerPullEvent(eventTypeHeader string, body []byte) *MockEventParsing_ParseBitbucketServerPullEvent_OngoingVerification {
+	_params := []pegomock.Param{eventTypeHeader, body}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ParseBitbucketServerPullEvent", _params, verifier.timeout)
 	return &MockEventParsing_ParseBitbucketServerPullEvent_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
 }
@@ -919,18 +923,24 @@ type MockEventParsing_ParseBitbucketServerPullEvent_OngoingVerification struct {
 	methodInvocations []pegomock.MethodInvocation
 }
 
-func (c *MockEventParsing_ParseBitbucketServerPullEvent_OngoingVerification) GetCapturedArguments() []byte {
-	body := c.GetAllCapturedArguments()
-	return body[len(body)-1]
+func (c *MockEventParsing_ParseBitbucketServerPullEvent_OngoingVerification) GetCapturedArguments() (string, []byte) {
+	eventTypeHeader, body := c.GetAllCapturedArguments()
+	return eventTypeHeader[len(eventTypeHeader)-1], body[len(body)-1]
 }
 
-func (c *MockEventParsing_ParseBitbucketServerPullEvent_OngoingVerification) GetAllCapturedArguments() (_param0 [][]byte) {
+func (c *MockEventParsing_ParseBitbucketServerPullEvent_OngoingVerification) GetAllCapturedArguments() (_param0 []string, _param1 [][]byte) {
 	_params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
 	if len(_params) > 0 {
 		if len(_params) > 0 {
-			_param0 = make([][]byte, len(c.methodInvocations))
+			_param0 = make([]string, len(c.methodInvocations))
 			for u, param := range _params[0] {
-				_param0[u] = param.([]byte)
+				_param0[u] = param.(string)
+			}
+		}
+		if len(_params) > 1 {
+			_param1 = make([][]byte, len(c.methodInvocations))
+			for u, param := range _params[1] {
+				_param1[u] = param.([]byte)
 			}
 		}
 	}
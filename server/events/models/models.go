@@ -19,10 +19,12 @@
 package models
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/url"
 	paths "path"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -157,6 +159,15 @@ type MergeableStatus struct {
 	Reason string
 }
 
+// PipelineStatus is the status of a pull request's latest CI pipeline.
+type PipelineStatus struct {
+	// Status is the pipeline's status as reported by the VCS, ex. success or
+	// running. It's empty if the pull request has no pipeline.
+	Status string
+	// URL links to the pipeline.
+	URL string
+}
+
 // PullRequest is a VCS pull request.
 // GitLab calls these Merge Requests.
 type PullRequest struct {
@@ -390,6 +401,32 @@ type PlanSuccess struct {
 	// branch we're merging into had been updated, and we had to merge again
 	// before planning
 	MergedAgain bool
+	// DestroyPlan is true if the plan was run with -destroy so applying it
+	// destroys the project's resources.
+	DestroyPlan bool `json:",omitempty"`
+	// RefreshOnlyPlan is true if the plan was run with -refresh-only so
+	// applying it only updates the state to match the real infrastructure.
+	RefreshOnlyPlan bool `json:",omitempty"`
+	// DestroyThreshold is the number of resources the plan can destroy
+	// before its comment warns about it. 0 disables the warning.
+	DestroyThreshold int `json:",omitempty"`
+	// DestroyThresholdApproval is true if applying the plan requires the pull
+	// request to be approved when it destroys more than DestroyThreshold.
+	DestroyThresholdApproval bool `json:",omitempty"`
+	// JSONStats are the resource changes read from the plan in JSON format.
+	// If they're nil, the changes are parsed from TerraformOutput.
+	JSONStats *PlanSuccessStats `json:",omitempty"`
+	// CreatedWorkspace is the workspace that was created for the plan since
+	// it didn't exist. It's empty if the workspace already existed.
+	CreatedWorkspace string `json:",omitempty"`
+	// TerraformVersion and TerraformDistribution are the version and
+	// distribution the project was planned with. They're empty if the
+	// project uses the server's defaults.
+	TerraformVersion      string `json:",omitempty"`
+	TerraformDistribution string `json:",omitempty"`
+	// CostEstimate is the cost diff of the plan saved by a cost_estimate
+	// step. It's empty if the workflow doesn't estimate costs.
+	CostEstimate string `json:",omitempty"`
 }
 
 type PolicySetResult struct {
@@ -398,6 +435,8 @@ type PolicySetResult struct {
 	Passed        bool
 	ReqApprovals  int
 	CurApprovals  int
+	// SoftFail is whether the policy set only warns if it doesn't pass.
+	SoftFail bool `json:",omitempty"`
 }
 
 // PolicySetApproval tracks the number of approvals a given policy set has.
@@ -410,8 +449,8 @@ type PolicySetStatus struct {
 // Summary regexes
 var (
 	reChangesOutside = regexp.MustCompile(`Note: Objects have changed outside of Terraform`)
-	rePlanChanges    = regexp.MustCompile(`Plan: (?:(\d+) to import, )?(\d+) to add, (\d+) to change, (\d+) to destroy.`)
-	reNoChanges      = regexp.MustCompile(`No changes. (Infrastructure is up-to-date|Your infrastructure matches the configuration).`)
+	rePlanChanges    = regexp.MustCompile(`Plan: (?:(\d+) to import, )?(\d+) to add, (\d+) to change, (\d+) to destroy(?:, (\d+) to forget)?\.`)
+	reNoChanges      = regexp.MustCompile(`No changes. (Infrastructure is up-to-date|Your infrastructure (still )?matches the configuration).`)
 )
 
 // Summary extracts summaries of plan changes from TerraformOutput.
@@ -433,6 +472,9 @@ func (p *PlanSuccess) DiffSummary() string {
 
 // NoChanges returns true if the plan has no changes.
 func (p *PlanSuccess) NoChanges() bool {
+	if p.JSONStats != nil {
+		return p.JSONStats.NoChanges
+	}
 	return reNoChanges.MatchString(p.TerraformOutput)
 }
 
@@ -454,6 +496,9 @@ func (p PlanSuccess) DiffMarkdownFormattedTerraformOutput() string {
 
 // Stats returns plan change stats and contextual information.
 func (p PlanSuccess) Stats() PlanSuccessStats {
+	if p.JSONStats != nil {
+		return *p.JSONStats
+	}
 	return NewPlanSuccessStats(p.TerraformOutput)
 }
 
@@ -493,6 +538,34 @@ type StateRmSuccess struct {
 	RePlanCmd string
 }
 
+// DriftSuccess is the result of a successful drift run.
+type DriftSuccess struct {
+	// TerraformOutput is the output from Terraform of running plan.
+	TerraformOutput string
+	// Drifted is true if the plan has changes.
+	Drifted bool
+}
+
+// reDriftedResource matches the header Terraform prints above each resource
+// in a plan, ex. "# aws_instance.web will be updated in-place".
+var reDriftedResource = regexp.MustCompile(`(?m)^\s*# (\S+) (will be .+|must be replaced|has changed|has been deleted)$`)
+
+// DriftedResources returns the resources in TerraformOutput that changed
+// outside of Terraform or that the plan would change, with what happens to
+// them, ex. "aws_instance.web will be updated in-place".
+func (d DriftSuccess) DriftedResources() []string {
+	var resources []string
+	for _, m := range reDriftedResource.FindAllStringSubmatch(d.TerraformOutput, -1) {
+		resources = append(resources, fmt.Sprintf("`%s` %s", m[1], m[2]))
+	}
+	return resources
+}
+
+// Summary extracts the one line summary of plan changes from TerraformOutput.
+func (d DriftSuccess) Summary() string {
+	return (&PlanSuccess{TerraformOutput: d.TerraformOutput}).DiffSummary()
+}
+
 func (p *PolicyCheckResults) CombinedOutput() string {
 	combinedOutput := ""
 	for _, psResult := range p.PolicySetResults {
@@ -520,7 +593,7 @@ func (p *PolicyCheckResults) Summary() string {
 func (p *PolicyCheckResults) PolicyCleared() bool {
 	passing := true
 	for _, policySetResult := range p.PolicySetResults {
-		if !policySetResult.Passed && (policySetResult.CurApprovals != policySetResult.ReqApprovals) {
+		if !policySetResult.Passed && !policySetResult.SoftFail && (policySetResult.CurApprovals != policySetResult.ReqApprovals) {
 			passing = false
 		}
 	}
@@ -533,6 +606,8 @@ func (p *PolicyCheckResults) PolicySummary() string {
 	for _, policySetResult := range p.PolicySetResults {
 		if policySetResult.Passed {
 			summary = append(summary, fmt.Sprintf("policy set: %s: passed.", policySetResult.PolicySetName))
+		} else if policySetResult.SoftFail {
+			summary = append(summary, fmt.Sprintf("policy set: %s: failed with warnings.", policySetResult.PolicySetName))
 		} else if policySetResult.CurApprovals == policySetResult.ReqApprovals {
 			summary = append(summary, fmt.Sprintf("policy set: %s: approved.", policySetResult.PolicySetName))
 		} else {
@@ -552,6 +627,64 @@ type PullStatus struct {
 	Projects []ProjectStatus
 	// Pull is the original pull request model.
 	Pull PullRequest
+	// ExternalApprovals are the approvals of external approval systems for
+	// the external_approval requirement.
+	ExternalApprovals []ExternalApproval
+	// CreatedWorkspaces are the Terraform workspaces Atlantis created for
+	// this pull request. Unlike the other fields they're kept when new
+	// commits are pushed so they can be deleted once the pull is closed.
+	CreatedWorkspaces []CreatedWorkspace `json:",omitempty"`
+}
+
+// CreatedWorkspace is a Terraform workspace that Atlantis created for a
+// project since it didn't exist.
+type CreatedWorkspace struct {
+	// RepoRelDir is the project's directory relative to the repo root.
+	RepoRelDir string
+	// Workspace is the name of the created workspace.
+	Workspace string
+	// TerraformVersion and TerraformDistribution are the version and
+	// distribution the workspace was created with so it's deleted with
+	// them too. They're empty if the project used the server's defaults.
+	TerraformVersion      string `json:",omitempty"`
+	TerraformDistribution string `json:",omitempty"`
+}
+
+// AddCreatedWorkspace records that the workspace w was created. It does
+// nothing if the workspace is already recorded.
+func (p *PullStatus) AddCreatedWorkspace(w CreatedWorkspace) {
+	for _, c := range p.CreatedWorkspaces {
+		if c.RepoRelDir == w.RepoRelDir && c.Workspace == w.Workspace {
+			return
+		}
+	}
+	p.CreatedWorkspaces = append(p.CreatedWorkspaces, w)
+}
+
+// ExternalApproval is an approval of a pull request by an external approval
+// system, ex. a change management system.
+type ExternalApproval struct {
+	// HeadCommit is the commit that was approved. Approvals of other commits
+	// don't satisfy the external_approval requirement.
+	HeadCommit string
+	// Projects are the names of the approved projects. If it's empty all
+	// projects are approved.
+	Projects []string
+	// Approver identifies who approved, ex. a change request number.
+	Approver string
+	// Reason is the optional reason given by the approver.
+	Reason string
+	// Time is when the approval was recorded.
+	Time time.Time
+}
+
+// Approves returns true if the approval covers project projectName at
+// commit headCommit.
+func (e ExternalApproval) Approves(headCommit string, projectName string) bool {
+	if e.HeadCommit != headCommit {
+		return false
+	}
+	return len(e.Projects) == 0 || slices.Contains(e.Projects, projectName)
 }
 
 // StatusCount returns the number of projects that have status.
@@ -574,6 +707,9 @@ type ProjectStatus struct {
 	PolicyStatus []PolicySetStatus
 	// Status is the status of where this project is at in the planning cycle.
 	Status ProjectPlanStatus
+	// PlanStats are the resource changes of the project's latest successful
+	// plan. They're nil if the project's latest plan failed.
+	PlanStats *PlanSuccessStats `json:",omitempty"`
 }
 
 // ProjectPlanStatus is the status of where this project is at in the planning
@@ -727,8 +863,11 @@ type WorkflowHookCommandContext struct {
 
 // PlanSuccessStats holds stats for a plan.
 type PlanSuccessStats struct {
-	Import, Add, Change, Destroy int
-	Changes, ChangesOutside      bool
+	Import, Add, Change, Destroy, Forget int
+	Changes, ChangesOutside              bool
+	// NoChanges is true if applying the plan wouldn't change anything. It's
+	// false if only outputs change since applying saves them in the state.
+	NoChanges bool `json:",omitempty"`
 }
 
 func NewPlanSuccessStats(output string) PlanSuccessStats {
@@ -737,6 +876,7 @@ func NewPlanSuccessStats(output string) PlanSuccessStats {
 	s := PlanSuccessStats{
 		ChangesOutside: reChangesOutside.MatchString(output),
 		Changes:        len(m) > 0,
+		NoChanges:      reNoChanges.MatchString(output),
 	}
 
 	if s.Changes {
@@ -747,7 +887,62 @@ func NewPlanSuccessStats(output string) PlanSuccessStats {
 		s.Add, _ = strconv.Atoi(m[2])
 		s.Change, _ = strconv.Atoi(m[3])
 		s.Destroy, _ = strconv.Atoi(m[4])
+		s.Forget, _ = strconv.Atoi(m[5])
 	}
 
 	return s
 }
+
+// NewPlanSuccessStatsFromJSON returns the stats of a plan in the JSON format
+// of terraform show -json. Replaced resources are counted as added and
+// destroyed like in the plan's text output.
+func NewPlanSuccessStatsFromJSON(planJSON []byte) (PlanSuccessStats, error) {
+	var plan struct {
+		FormatVersion   string `json:"format_version"`
+		ResourceChanges []struct {
+			Change struct {
+				Actions   []string  `json:"actions"`
+				Importing *struct{} `json:"importing"`
+			} `json:"change"`
+		} `json:"resource_changes"`
+		OutputChanges map[string]struct {
+			Actions []string `json:"actions"`
+		} `json:"output_changes"`
+		ResourceDrift []json.RawMessage `json:"resource_drift"`
+	}
+	if err := json.Unmarshal(planJSON, &plan); err != nil {
+		return PlanSuccessStats{}, errors.Wrap(err, "parsing plan json")
+	}
+	if plan.FormatVersion == "" {
+		return PlanSuccessStats{}, errors.New("parsing plan json: missing format_version")
+	}
+
+	s := PlanSuccessStats{
+		ChangesOutside: len(plan.ResourceDrift) > 0,
+	}
+	for _, rc := range plan.ResourceChanges {
+		if rc.Change.Importing != nil {
+			s.Import++
+		}
+		for _, action := range rc.Change.Actions {
+			switch action {
+			case "create":
+				s.Add++
+			case "update":
+				s.Change++
+			case "delete":
+				s.Destroy++
+			case "forget":
+				s.Forget++
+			}
+		}
+	}
+	s.Changes = s.Import+s.Add+s.Change+s.Destroy+s.Forget > 0
+	s.NoChanges = !s.Changes
+	for _, oc := range plan.OutputChanges {
+		if !slices.Equal(oc.Actions, []string{"no-op"}) {
+			s.NoChanges = false
+		}
+	}
+	return s, nil
+}
@@ -392,6 +392,10 @@ func TestPlanSuccess_Summary(t *testing.T) {
 			"dummy\nPlan: 42 to import, 53 to add, 64 to change, 75 to destroy.",
 			"Plan: 42 to import, 53 to add, 64 to change, 75 to destroy.",
 		},
+		{
+			"dummy\nPlan: 0 to add, 0 to change, 0 to destroy, 2 to forget.",
+			"Plan: 0 to add, 0 to change, 0 to destroy, 2 to forget.",
+		},
 		{
 			"Note: Objects have changed outside of Terraform\ndummy\nNo changes. Infrastructure is up-to-date.",
 			"\n**Note: Objects have changed outside of Terraform**\nNo changes. Infrastructure is up-to-date.",
@@ -443,6 +447,42 @@ func TestPlanSuccess_DiffSummary(t *testing.T) {
 	}
 }
 
+func TestDriftSuccess_DriftedResources(t *testing.T) {
+	output := `Note: Objects have changed outside of Terraform
+
+Terraform detected the following changes made outside of Terraform since the
+last "terraform apply":
+
+  # aws_security_group.web has changed
+  ~ resource "aws_security_group" "web" {
+        id = "sg-123"
+    }
+
+Terraform will perform the following actions:
+
+  # aws_instance.web will be updated in-place
+  ~ resource "aws_instance" "web" {
+      ~ instance_type = "t3.large" -> "t3.micro"
+    }
+
+  # module.db.aws_db_instance.main["primary"] must be replaced
+-/+ resource "aws_db_instance" "main" {
+    }
+
+Plan: 1 to add, 1 to change, 1 to destroy.`
+	drift := models.DriftSuccess{TerraformOutput: output, Drifted: true}
+	Equals(t, []string{
+		"`aws_security_group.web` has changed",
+		"`aws_instance.web` will be updated in-place",
+		"`module.db.aws_db_instance.main[\"primary\"]` must be replaced",
+	}, drift.DriftedResources())
+	Equals(t, "Plan: 1 to add, 1 to change, 1 to destroy.", drift.Summary())
+
+	noDrift := models.DriftSuccess{TerraformOutput: "No changes. Your infrastructure matches the configuration."}
+	Equals(t, []string(nil), noDrift.DriftedResources())
+	Equals(t, "No changes. Your infrastructure matches the configuration.", noDrift.Summary())
+}
+
 func TestPolicyCheckResults_Summary(t *testing.T) {
 	cases := []struct {
 		description      string
@@ -535,6 +575,19 @@ func TestPolicyCheckResults_PolicyFuncs(t *testing.T) {
 			policyClearedExp: true,
 			policySummaryExp: "policy set: policy1: approved.",
 		},
+		{
+			description: "single policy set, soft failed",
+			policysetResults: []models.PolicySetResult{
+				{
+					PolicySetName: "policy1",
+					Passed:        false,
+					ReqApprovals:  1,
+					SoftFail:      true,
+				},
+			},
+			policyClearedExp: true,
+			policySummaryExp: "policy set: policy1: failed with warnings.",
+		},
 		{
 			description: "multiple policy sets, different states.",
 			policysetResults: []models.PolicySetResult{
@@ -664,7 +717,9 @@ func TestPlanSuccessStats(t *testing.T) {
 			`An execution plan has been generated and is shown below.
 					Resource actions are indicated with the following symbols:
 					No changes. Infrastructure is up-to-date.`,
-			models.PlanSuccessStats{},
+			models.PlanSuccessStats{
+				NoChanges: true,
+			},
 		},
 		{
 			"changes outside",
@@ -674,8 +729,17 @@ func TestPlanSuccessStats(t *testing.T) {
 					No changes. Your infrastructure matches the configuration.`,
 			models.PlanSuccessStats{
 				ChangesOutside: true,
+				NoChanges:      true,
 			},
 		},
+		{
+			"only outputs change",
+			`Changes to Outputs:
+					  + name = "hi"
+					You can apply this plan to save these new output values to the Terraform
+					state, without changing any real infrastructure.`,
+			models.PlanSuccessStats{},
+		},
 		{
 			"with imports",
 			`Terraform used the selected providers to generate the following execution
@@ -695,6 +759,18 @@ func TestPlanSuccessStats(t *testing.T) {
 				Destroy: 1,
 			},
 		},
+		{
+			"with forgets",
+			`Terraform will perform the following actions:
+			  # null_resource.hi will no longer be managed by Terraform
+			Plan: 1 to add, 0 to change, 0 to destroy, 2 to forget.`,
+			models.PlanSuccessStats{
+				Changes: true,
+
+				Add:    1,
+				Forget: 2,
+			},
+		},
 		{
 			"changes and changes outside",
 			`Note: Objects have changed outside of Terraform
@@ -726,3 +802,73 @@ func TestPlanSuccessStats(t *testing.T) {
 		})
 	}
 }
+
+func TestNewPlanSuccessStatsFromJSON(t *testing.T) {
+	tests := []struct {
+		name   string
+		json   string
+		exp    models.PlanSuccessStats
+		expErr string
+	}{
+		{
+			name: "has changes",
+			json: `{"format_version": "1.2", "resource_changes": [
+				{"change": {"actions": ["create"]}},
+				{"change": {"actions": ["create", "delete"]}},
+				{"change": {"actions": ["update"], "importing": {"id": "a"}}},
+				{"change": {"actions": ["forget"]}},
+				{"change": {"actions": ["no-op"]}}
+			], "resource_drift": [{"change": {"actions": ["update"]}}]}`,
+			exp: models.PlanSuccessStats{
+				Changes:        true,
+				ChangesOutside: true,
+
+				Import:  1,
+				Add:     2,
+				Change:  1,
+				Destroy: 1,
+				Forget:  1,
+			},
+		},
+		{
+			name: "no changes",
+			json: `{"format_version": "1.0", "resource_changes": [{"change": {"actions": ["no-op"]}}], "output_changes": {"name": {"actions": ["no-op"]}}}`,
+			exp:  models.PlanSuccessStats{NoChanges: true},
+		},
+		{
+			name: "only outputs change",
+			json: `{"format_version": "1.0", "resource_changes": [{"change": {"actions": ["no-op"]}}], "output_changes": {"name": {"actions": ["create"]}}}`,
+			exp:  models.PlanSuccessStats{},
+		},
+		{
+			name:   "not a plan",
+			json:   `{}`,
+			expErr: "parsing plan json: missing format_version",
+		},
+		{
+			name:   "invalid json",
+			json:   `Plan: 1 to add, 0 to change, 0 to destroy.`,
+			expErr: "parsing plan json: invalid character 'P' looking for beginning of value",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := models.NewPlanSuccessStatsFromJSON([]byte(tt.json))
+			if tt.expErr != "" {
+				ErrEquals(t, tt.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, tt.exp, s)
+		})
+	}
+}
+
+func TestPlanSuccess_StatsPrefersJSONStats(t *testing.T) {
+	p := models.PlanSuccess{
+		TerraformOutput: "Plan: 1 to add, 0 to change, 0 to destroy.",
+		JSONStats:       &models.PlanSuccessStats{Changes: true, Add: 2},
+	}
+	Equals(t, models.PlanSuccessStats{Changes: true, Add: 2}, p.Stats())
+}
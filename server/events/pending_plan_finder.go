@@ -51,7 +51,13 @@ func (p *DefaultPendingPlanFinder) findWithAbsPaths(pullDir string) ([]PendingPl
 		return nil, nil, err
 	}
 	var plans []PendingPlan
+	var planPaths []string
 	var absPaths []string
+	// Projects that set plan_file_path have a link with the default plan
+	// file name to their plan file. The plan is found through the link so
+	// it has the right dir and project name and the file it links to is
+	// skipped.
+	linkedPlans := make(map[string]bool)
 	for _, workspaceDir := range workspaceDirs {
 		workspace := workspaceDir.Name()
 		repoDir := filepath.Join(pullDir, workspace)
@@ -71,6 +77,17 @@ func (p *DefaultPendingPlanFinder) findWithAbsPaths(pullDir string) ([]PendingPl
 					continue
 				}
 
+				absPath := filepath.Join(repoDir, file)
+				absPaths = append(absPaths, absPath)
+				if target, isLink := linkedPlanFile(absPath); isLink {
+					if target == "" {
+						// The plan file was deleted so only the link is
+						// left.
+						continue
+					}
+					linkedPlans[target] = true
+					absPaths = append(absPaths, target)
+				}
 				projectName, err := runtime.ProjectNameFromPlanfile(workspace, filepath.Base(file))
 				if err != nil {
 					return nil, nil, err
@@ -81,11 +98,39 @@ func (p *DefaultPendingPlanFinder) findWithAbsPaths(pullDir string) ([]PendingPl
 					Workspace:   workspace,
 					ProjectName: projectName,
 				})
-				absPaths = append(absPaths, filepath.Join(repoDir, file))
+				planPaths = append(planPaths, absPath)
 			}
 		}
 	}
-	return plans, absPaths, nil
+
+	var unlinkedPlans []PendingPlan
+	for i, plan := range plans {
+		if !linkedPlans[planPaths[i]] {
+			unlinkedPlans = append(unlinkedPlans, plan)
+		}
+	}
+	return unlinkedPlans, absPaths, nil
+}
+
+// linkedPlanFile returns the absolute path to the plan file that the file at
+// absPath links to if it's a symlink. target is empty if the plan file
+// doesn't exist.
+func linkedPlanFile(absPath string) (target string, isLink bool) {
+	info, err := os.Lstat(absPath)
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		return "", false
+	}
+	link, err := os.Readlink(absPath)
+	if err != nil {
+		return "", true
+	}
+	if !filepath.IsAbs(link) {
+		link = filepath.Join(filepath.Dir(absPath), link)
+	}
+	if _, err := os.Stat(link); err != nil {
+		return "", true
+	}
+	return filepath.Clean(link), true
 }
 
 // deletePlans deletes all plans in pullDir.
@@ -98,6 +143,9 @@ func (p *DefaultPendingPlanFinder) DeletePlans(pullDir string) error {
 		if err := utils.RemoveIgnoreNonExistent(path); err != nil {
 			return errors.Wrapf(err, "delete plan at %s", path)
 		}
+		if err := os.RemoveAll(runtime.StepArtifactsDir(path)); err != nil {
+			return errors.Wrapf(err, "delete outputs stored for plan at %s", path)
+		}
 	}
 	return nil
 }
@@ -316,6 +316,42 @@ func TestPendingPlanFinder_DeletePlans(t *testing.T) {
 	Equals(t, 0, len(foundPlans))
 }
 
+// Projects with plan_file_path are found through the link to their plan file.
+func TestPendingPlanFinder_FindLinkedPlan(t *testing.T) {
+	tmp := DirStructure(t, map[string]interface{}{
+		"default": map[string]interface{}{
+			"dir1": map[string]interface{}{
+				"plans": map[string]interface{}{
+					"default.tfplan": nil,
+				},
+			},
+			"dir2": map[string]interface{}{},
+		},
+	})
+	repoDir := filepath.Join(tmp, "default")
+	runCmd(t, repoDir, "git", "init")
+	Ok(t, os.Symlink(filepath.Join("plans", "default.tfplan"), filepath.Join(repoDir, "dir1", "default.tfplan")))
+	// The plan file for dir2 was deleted.
+	Ok(t, os.Symlink(filepath.Join("plans", "deleted.tfplan"), filepath.Join(repoDir, "dir2", "default.tfplan")))
+
+	pf := &events.DefaultPendingPlanFinder{}
+	plans, err := pf.Find(tmp)
+	Ok(t, err)
+	Equals(t, []events.PendingPlan{
+		{
+			RepoDir:    repoDir,
+			RepoRelDir: "dir1",
+			Workspace:  "default",
+		},
+	}, plans)
+
+	Ok(t, pf.DeletePlans(tmp))
+	for _, plan := range []string{"dir1/default.tfplan", "dir1/plans/default.tfplan", "dir2/default.tfplan"} {
+		_, err := os.Lstat(filepath.Join(repoDir, plan))
+		Assert(t, os.IsNotExist(err), "%s should be deleted", plan)
+	}
+}
+
 func runCmd(t *testing.T, dir string, name string, args ...string) string {
 	t.Helper()
 	cpCmd := exec.Command(name, args...)
@@ -4,6 +4,8 @@
 package events
 
 import (
+	"fmt"
+
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
@@ -38,7 +40,7 @@ func NewPlanCommandRunner(
 	discardApprovalOnPlan bool,
 	pullReqStatusFetcher vcs.PullReqStatusFetcher,
 	PendingApplyStatus bool,
-
+	parallelPlanLimit int,
 ) *PlanCommandRunner {
 	return &PlanCommandRunner{
 		silenceVCSStatusNoPlans:    silenceVCSStatusNoPlans,
@@ -60,6 +62,7 @@ func NewPlanCommandRunner(
 		DiscardApprovalOnPlan:      discardApprovalOnPlan,
 		pullReqStatusFetcher:       pullReqStatusFetcher,
 		PendingApplyStatus:         PendingApplyStatus,
+		parallelPlanLimit:          parallelPlanLimit,
 	}
 }
 
@@ -84,8 +87,11 @@ type PlanCommandRunner struct {
 	policyCheckCommandRunner   *PolicyCheckCommandRunner
 	autoMerger                 *AutoMerger
 	parallelPoolSize           int
-	pullStatusFetcher          PullStatusFetcher
-	lockingLocker              locking.Locker
+	// parallelPlanLimit is the max number of parallel plans, it overrides
+	// parallelPoolSize if it's set.
+	parallelPlanLimit int
+	pullStatusFetcher PullStatusFetcher
+	lockingLocker     locking.Locker
 	// DiscardApprovalOnPlan controls if all already existing approvals should be removed/dismissed before executing
 	// a plan.
 	DiscardApprovalOnPlan bool
@@ -144,10 +150,11 @@ func (p *PlanCommandRunner) runAutoplan(ctx *command.Context) {
 	var result command.Result
 	if p.isParallelEnabled(projectCmds) {
 		ctx.Log.Info("Running plans in parallel")
-		result = runProjectCmdsParallelGroups(ctx, projectCmds, p.prjCmdRunner.Plan, p.parallelPoolSize)
+		result = runProjectCmdsParallelGroups(ctx, projectCmds, p.prjCmdRunner.Plan, p.planPoolSize())
 	} else {
 		result = runProjectCmds(projectCmds, p.prjCmdRunner.Plan)
 	}
+	ctx.CommandHasErrors = result.HasErrors()
 
 	if p.autoMerger.automergeEnabled(projectCmds) && result.HasErrors() {
 		ctx.Log.Info("deleting plans because there were errors and automerge requires all plans succeed")
@@ -159,13 +166,16 @@ func (p *PlanCommandRunner) runAutoplan(ctx *command.Context) {
 		result.PlansDeleted = true
 	}
 
-	p.pullUpdater.updatePull(ctx, AutoplanCommand{}, result)
+	commentURL := p.pullUpdater.updatePull(ctx, AutoplanCommand{}, result)
 
 	pullStatus, err := p.dbUpdater.updateDB(ctx, ctx.Pull, result.ProjectResults)
 	if err != nil {
 		ctx.Log.Err("writing results: %s", err)
 	}
 
+	p.pullUpdater.updatePullDescription(ctx, projectCmds, result.ProjectResults, pullStatus, commentURL)
+	p.pullUpdater.commentPlanSummary(ctx, pullStatus)
+
 	p.updateCommitStatus(ctx, pullStatus, command.Plan)
 	p.updateCommitStatus(ctx, pullStatus, command.Apply)
 
@@ -214,6 +224,20 @@ func (p *PlanCommandRunner) run(ctx *command.Context, cmd *CommentCommand) {
 		return
 	}
 
+	if cmd.Failed && len(projectCmds) == 0 {
+		ctx.Log.Info("no projects failed to plan at commit %s", pull.HeadCommit)
+		if _, err := p.vcsClient.CreateComment(ctx.Log, baseRepo, pull.Num, fmt.Sprintf(noFailedPlansComment, pull.HeadCommit), command.Plan.String()); err != nil {
+			ctx.Log.Err("unable to comment: %s", err)
+		}
+		// Reset the status that was set to pending when the command started.
+		if ctx.PullStatus != nil && ctx.PullStatus.Pull.HeadCommit == pull.HeadCommit {
+			p.updateCommitStatus(ctx, *ctx.PullStatus, command.Plan)
+		} else if err := p.commitStatusUpdater.UpdateCombinedCount(ctx.Log, baseRepo, pull, models.SuccessCommitStatus, command.Plan, 0, 0); err != nil {
+			ctx.Log.Warn("unable to update commit status: %s", err)
+		}
+		return
+	}
+
 	if len(projectCmds) == 0 && p.SilenceNoProjects {
 		ctx.Log.Info("determined there was no project to run plan in")
 		if !p.silenceVCSStatusNoProjects {
@@ -260,8 +284,11 @@ func (p *PlanCommandRunner) run(ctx *command.Context, cmd *CommentCommand) {
 	projectCmds, policyCheckCmds := p.partitionProjectCmds(ctx, projectCmds)
 
 	// if the plan is generic, new plans will be generated based on changes
-	// discard previous plans that might not be relevant anymore
-	if !cmd.IsForSpecificProject() {
+	// discard previous plans that might not be relevant anymore. When projects
+	// are excluded, filtered by workspace, changed since a ref or only the
+	// failed ones are planned the previous plans are kept so the other
+	// projects keep theirs.
+	if !cmd.IsForSpecificProject() && len(cmd.ExcludeProjectNames) == 0 && cmd.WorkspacePattern == "" && cmd.SinceRef == "" && !cmd.Failed {
 		ctx.Log.Debug("deleting previous plans and locks")
 		p.deletePlans(ctx)
 		_, err := p.lockingLocker.UnlockByPull(ctx.Pull.BaseRepo.FullName, ctx.Pull.Num)
@@ -274,7 +301,7 @@ func (p *PlanCommandRunner) run(ctx *command.Context, cmd *CommentCommand) {
 	var result command.Result
 	if p.isParallelEnabled(projectCmds) {
 		ctx.Log.Info("Running plans in parallel")
-		result = runProjectCmdsParallelGroups(ctx, projectCmds, p.prjCmdRunner.Plan, p.parallelPoolSize)
+		result = runProjectCmdsParallelGroups(ctx, projectCmds, p.prjCmdRunner.Plan, p.planPoolSize())
 	} else {
 		result = runProjectCmds(projectCmds, p.prjCmdRunner.Plan)
 	}
@@ -290,7 +317,7 @@ func (p *PlanCommandRunner) run(ctx *command.Context, cmd *CommentCommand) {
 		result.PlansDeleted = true
 	}
 
-	p.pullUpdater.updatePull(
+	commentURL := p.pullUpdater.updatePull(
 		ctx,
 		cmd,
 		result)
@@ -301,6 +328,9 @@ func (p *PlanCommandRunner) run(ctx *command.Context, cmd *CommentCommand) {
 		return
 	}
 
+	p.pullUpdater.updatePullDescription(ctx, projectCmds, result.ProjectResults, pullStatus, commentURL)
+	p.pullUpdater.commentPlanSummary(ctx, pullStatus)
+
 	p.updateCommitStatus(ctx, pullStatus, command.Plan)
 	p.updateCommitStatus(ctx, pullStatus, command.Apply)
 
@@ -415,5 +445,18 @@ func (p *PlanCommandRunner) partitionProjectCmds(
 }
 
 func (p *PlanCommandRunner) isParallelEnabled(projectCmds []command.ProjectContext) bool {
-	return len(projectCmds) > 0 && projectCmds[0].ParallelPlanEnabled
+	// A limit of 1 is the same as planning serially.
+	return len(projectCmds) > 0 && projectCmds[0].ParallelPlanEnabled && p.parallelPlanLimit != 1
 }
+
+// planPoolSize returns the max number of projects planned in parallel.
+func (p *PlanCommandRunner) planPoolSize() int {
+	if p.parallelPlanLimit > 0 {
+		return p.parallelPlanLimit
+	}
+	return p.parallelPoolSize
+}
+
+// noFailedPlansComment is the comment for atlantis plan --failed when no
+// projects failed to plan at the head commit %s.
+var noFailedPlansComment = "No projects failed to plan at commit %s, there's nothing to plan again."
@@ -893,6 +893,42 @@ func TestPlanCommandRunner_SilenceFlagsClearsPendingStatus(t *testing.T) {
 		)
 	})
 }
+func TestPlanCommandRunner_FailedWithoutFailures(t *testing.T) {
+	RegisterMockTestingT(t)
+	vcsClient := setup(t)
+
+	modelPull := models.PullRequest{BaseRepo: testdata.GithubRepo, State: models.OpenPullState, Num: testdata.Pull.Num, HeadCommit: "head"}
+	pullStatus := &models.PullStatus{
+		Pull: modelPull,
+		Projects: []models.ProjectStatus{
+			{ProjectName: "a", RepoRelDir: "a", Workspace: "default", Status: models.PlannedPlanStatus},
+			{ProjectName: "b", RepoRelDir: "b", Workspace: "default", Status: models.AppliedPlanStatus},
+		},
+	}
+	ctx := &command.Context{
+		User:       testdata.User,
+		Log:        logging.NewNoopLogger(t),
+		Scope:      metricstest.NewLoggingScope(t, logging.NewNoopLogger(t), "atlantis"),
+		Pull:       modelPull,
+		PullStatus: pullStatus,
+		HeadRepo:   testdata.GithubRepo,
+		Trigger:    command.CommentTrigger,
+	}
+	cmd := &events.CommentCommand{Name: command.Plan, Failed: true}
+	When(projectCommandBuilder.BuildPlanCommands(ctx, cmd)).ThenReturn(nil, nil)
+
+	planCommandRunner.Run(ctx, cmd)
+
+	vcsClient.VerifyWasCalledOnce().CreateComment(
+		Any[logging.SimpleLogging](), Eq(testdata.GithubRepo), Eq(modelPull.Num),
+		Eq("No projects failed to plan at commit head, there's nothing to plan again."), Eq("plan"))
+	// The plan status is reset from the existing results instead of 0/0.
+	commitUpdater.VerifyWasCalledOnce().UpdateCombinedCount(
+		Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+		Eq(models.SuccessCommitStatus), Eq(command.Plan), Eq(2), Eq(2))
+	lockingLocker.VerifyWasCalled(Never()).UnlockByPull(Any[string](), Any[int]())
+}
+
 func TestPlanCommandRunner_PendingApplyStatus(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
 	RegisterMockTestingT(t)
@@ -0,0 +1,82 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// planSummaryCommand is the command of plan summary comments. It's in their
+// first line so previous summaries can be hidden.
+const planSummaryCommand = "Plan Summary"
+
+// commentPlanSummary posts a comment with a table of every project in
+// pullStatus and the resource changes of its latest plan. Previous summary
+// comments are hidden so only the latest summary is shown, which includes
+// projects that weren't part of this run.
+func (c *PullUpdater) commentPlanSummary(ctx *command.Context, pullStatus models.PullStatus) {
+	if !c.PlanSummaryComment || len(pullStatus.Projects) == 0 {
+		return
+	}
+
+	if err := c.VCSClient.HidePrevCommandComments(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, planSummaryCommand, ""); err != nil {
+		ctx.Log.Warn("unable to hide previous plan summary comments: %s", err)
+	}
+	if _, err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, renderPlanSummary(pullStatus), command.Plan.String()); err != nil {
+		ctx.Log.Err("unable to comment plan summary: %s", err)
+	}
+}
+
+// renderPlanSummary returns the markdown of the plan summary comment for
+// the projects in pullStatus.
+func renderPlanSummary(pullStatus models.PullStatus) string {
+	var summary strings.Builder
+	var add, change, destroy int
+	fmt.Fprintf(&summary, "### Atlantis %s\n\n", planSummaryCommand)
+	summary.WriteString("| Project | Workspace | Status | Add | Change | Destroy |\n")
+	summary.WriteString("|---|---|---|--:|--:|--:|\n")
+	for _, p := range pullStatus.Projects {
+		project := fmt.Sprintf("`%s`", p.ProjectName)
+		if p.ProjectName == "" {
+			project = fmt.Sprintf("dir: `%s`", p.RepoRelDir)
+		}
+		counts := "- | - | -"
+		if p.PlanStats != nil {
+			add += p.PlanStats.Add
+			change += p.PlanStats.Change
+			destroy += p.PlanStats.Destroy
+			counts = fmt.Sprintf("%d | %d | %d", p.PlanStats.Add, p.PlanStats.Change, p.PlanStats.Destroy)
+		}
+		fmt.Fprintf(&summary, "| %s | `%s` | %s | %s |\n", project, p.Workspace, planSummaryStatus(p.Status), counts)
+	}
+	fmt.Fprintf(&summary, "| **Total** | | | **%d** | **%d** | **%d** |\n", add, change, destroy)
+	return summary.String()
+}
+
+// planSummaryStatus returns the description of status in the plan summary.
+func planSummaryStatus(status models.ProjectPlanStatus) string {
+	switch status {
+	case models.ErroredPlanStatus:
+		return ":x: plan errored"
+	case models.PlannedPlanStatus:
+		return ":page_facing_up: planned"
+	case models.PlannedNoChangesPlanStatus:
+		return ":white_check_mark: no changes"
+	case models.ErroredApplyStatus:
+		return ":x: apply errored"
+	case models.AppliedPlanStatus:
+		return ":white_check_mark: applied"
+	case models.DiscardedPlanStatus:
+		return ":put_litter_in_its_place: plan discarded"
+	case models.ErroredPolicyCheckStatus:
+		return ":x: policy check errored"
+	case models.PassedPolicyCheckStatus:
+		return ":white_check_mark: policy check passed"
+	}
+	return status.String()
+}
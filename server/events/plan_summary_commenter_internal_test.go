@@ -0,0 +1,58 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestPullUpdater_CommentPlanSummary(t *testing.T) {
+	RegisterMockTestingT(t)
+	vcsClient := vcsmocks.NewMockClient()
+	updater := &PullUpdater{
+		VCSClient:          vcsClient,
+		PlanSummaryComment: true,
+	}
+	ctx := &command.Context{
+		Log:  logging.NewNoopLogger(t),
+		Pull: models.PullRequest{Num: 1},
+	}
+	pullStatus := models.PullStatus{
+		Projects: []models.ProjectStatus{
+			{ProjectName: "app", RepoRelDir: "app", Workspace: "default", Status: models.PlannedPlanStatus, PlanStats: &models.PlanSuccessStats{Changes: true, Add: 2, Change: 1}},
+			{RepoRelDir: "network", Workspace: "staging", Status: models.PlannedNoChangesPlanStatus, PlanStats: &models.PlanSuccessStats{}},
+			{ProjectName: "dns", RepoRelDir: "dns", Workspace: "default", Status: models.ErroredPlanStatus},
+			{ProjectName: "cache", RepoRelDir: "cache", Workspace: "default", Status: models.AppliedPlanStatus, PlanStats: &models.PlanSuccessStats{Changes: true, Add: 1, Destroy: 3}},
+		},
+	}
+
+	updater.commentPlanSummary(ctx, pullStatus)
+
+	vcsClient.VerifyWasCalledOnce().HidePrevCommandComments(Any[logging.SimpleLogging](), Any[models.Repo](), Eq(1), Eq("Plan Summary"), Eq(""))
+	_, _, _, comment, _ := vcsClient.VerifyWasCalledOnce().CreateComment(Any[logging.SimpleLogging](), Any[models.Repo](), Eq(1), Any[string](), Eq("plan")).GetCapturedArguments()
+	Equals(t, "### Atlantis Plan Summary\n\n"+
+		"| Project | Workspace | Status | Add | Change | Destroy |\n"+
+		"|---|---|---|--:|--:|--:|\n"+
+		"| `app` | `default` | :page_facing_up: planned | 2 | 1 | 0 |\n"+
+		"| dir: `network` | `staging` | :white_check_mark: no changes | 0 | 0 | 0 |\n"+
+		"| `dns` | `default` | :x: plan errored | - | - | - |\n"+
+		"| `cache` | `default` | :white_check_mark: applied | 1 | 0 | 3 |\n"+
+		"| **Total** | | | **3** | **1** | **3** |\n", comment)
+}
+
+func TestPullUpdater_CommentPlanSummary_Disabled(t *testing.T) {
+	RegisterMockTestingT(t)
+	vcsClient := vcsmocks.NewMockClient()
+	updater := &PullUpdater{VCSClient: vcsClient}
+	ctx := &command.Context{Log: logging.NewNoopLogger(t)}
+	updater.commentPlanSummary(ctx, models.PullStatus{Projects: []models.ProjectStatus{{RepoRelDir: "."}}})
+	vcsClient.VerifyWasCalled(Never()).CreateComment(Any[logging.SimpleLogging](), Any[models.Repo](), Any[int](), Any[string](), Any[string]())
+}
@@ -70,7 +70,9 @@ func (p *PolicyCheckCommandRunner) Run(ctx *command.Context, cmds []command.Proj
 
 	// Quiet policy checks unless there's an error
 	if result.HasErrors() || !p.quietPolicyChecks {
-		p.pullUpdater.updatePull(ctx, PolicyCheckCommand{}, result)
+		if commentResult, ok := withoutQuietPolicyChecks(result); ok {
+			p.pullUpdater.updatePull(ctx, PolicyCheckCommand{}, commentResult)
+		}
 	}
 
 	pullStatus, err := p.dbUpdater.updateDB(ctx, ctx.Pull, result.ProjectResults)
@@ -81,6 +83,27 @@ func (p *PolicyCheckCommandRunner) Run(ctx *command.Context, cmds []command.Proj
 	p.updateCommitStatus(ctx, pullStatus)
 }
 
+// withoutQuietPolicyChecks returns result without the successful policy
+// checks of projects with quiet_policy_checks. It returns false if there's
+// nothing left to comment.
+func withoutQuietPolicyChecks(result command.Result) (command.Result, bool) {
+	if len(result.ProjectResults) == 0 {
+		return result, true
+	}
+	var commentResults []command.ProjectResult
+	for _, r := range result.ProjectResults {
+		if r.QuietPolicyChecks && r.IsSuccessful() {
+			continue
+		}
+		commentResults = append(commentResults, r)
+	}
+	if len(commentResults) == 0 && result.Error == nil && result.Failure == "" {
+		return result, false
+	}
+	result.ProjectResults = commentResults
+	return result, true
+}
+
 func (p *PolicyCheckCommandRunner) updateCommitStatus(ctx *command.Context, pullStatus models.PullStatus) {
 	var numSuccess int
 	var numErrored int
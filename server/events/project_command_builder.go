@@ -4,11 +4,13 @@
 package events
 
 import (
+	"cmp"
 	"fmt"
+	"math"
 	"os"
+	"path"
 	"path/filepath"
 	"slices"
-	"sort"
 	"strings"
 
 	tally "github.com/uber-go/tally/v4"
@@ -61,6 +63,7 @@ func NewInstrumentedProjectCommandBuilder(
 	SilenceNoProjects bool,
 	IncludeGitUntrackedFiles bool,
 	AutoDiscoverMode string,
+	ProjectOrder string,
 	scope tally.Scope,
 	terraformClient tfclient.Client,
 ) *InstrumentedProjectCommandBuilder {
@@ -92,6 +95,7 @@ func NewInstrumentedProjectCommandBuilder(
 			SilenceNoProjects,
 			IncludeGitUntrackedFiles,
 			AutoDiscoverMode,
+			ProjectOrder,
 			scope,
 			terraformClient,
 		),
@@ -121,6 +125,7 @@ func NewProjectCommandBuilder(
 	SilenceNoProjects bool,
 	IncludeGitUntrackedFiles bool,
 	AutoDiscoverMode string,
+	ProjectOrder string,
 	scope tally.Scope,
 	terraformClient tfclient.Client,
 ) *DefaultProjectCommandBuilder {
@@ -143,6 +148,7 @@ func NewProjectCommandBuilder(
 		SilenceNoProjects:        SilenceNoProjects,
 		IncludeGitUntrackedFiles: IncludeGitUntrackedFiles,
 		AutoDiscoverMode:         AutoDiscoverMode,
+		ProjectOrder:             ProjectOrder,
 		ProjectCommandContextBuilder: NewProjectCommandContextBuilder(
 			policyChecksSupported,
 			commentBuilder,
@@ -152,6 +158,21 @@ func NewProjectCommandBuilder(
 	}
 }
 
+const (
+	// ProjectOrderDiscovery keeps projects in the order they're found, ex.
+	// the order of the modified files for plans and of the plan files for
+	// applies.
+	ProjectOrderDiscovery = "discovery"
+	// ProjectOrderConfig orders projects like the repo config. Projects that
+	// aren't configured there come last.
+	ProjectOrderConfig = "config"
+	// ProjectOrderName orders projects by name and then by dir and workspace.
+	ProjectOrderName = "name"
+)
+
+// ValidProjectOrders are the values of DefaultProjectCommandBuilder.ProjectOrder.
+var ValidProjectOrders = []string{ProjectOrderDiscovery, ProjectOrderConfig, ProjectOrderName}
+
 type ProjectPlanCommandBuilder interface {
 	// BuildAutoplanCommands builds project commands that will run plan on
 	// the projects determined to be modified.
@@ -195,6 +216,13 @@ type ProjectStateCommandBuilder interface {
 	BuildStateRmCommands(ctx *command.Context, comment *CommentCommand) ([]command.ProjectContext, error)
 }
 
+type ProjectDriftCommandBuilder interface {
+	// BuildDriftCommands builds project drift commands for this ctx and comment. If
+	// comment doesn't specify one project then there may be multiple commands
+	// to be run.
+	BuildDriftCommands(ctx *command.Context, comment *CommentCommand) ([]command.ProjectContext, error)
+}
+
 //go:generate pegomock generate github.com/runatlantis/atlantis/server/events --package mocks -o mocks/mock_project_command_builder.go ProjectCommandBuilder
 
 // ProjectCommandBuilder builds commands that run on individual projects.
@@ -205,6 +233,7 @@ type ProjectCommandBuilder interface {
 	ProjectVersionCommandBuilder
 	ProjectImportCommandBuilder
 	ProjectStateCommandBuilder
+	ProjectDriftCommandBuilder
 }
 
 // DefaultProjectCommandBuilder implements ProjectCommandBuilder.
@@ -251,13 +280,16 @@ type DefaultProjectCommandBuilder struct {
 	IncludeGitUntrackedFiles bool
 	// User config option: Controls auto-discovery of projects in a repository.
 	AutoDiscoverMode string
+	// User config option: The order projects run in and are commented in
+	// within their execution order group, one of ValidProjectOrders.
+	ProjectOrder string
 	// Handles the actual running of Terraform commands.
 	TerraformExecutor tfclient.Client
 }
 
 // See ProjectCommandBuilder.BuildAutoplanCommands.
 func (p *DefaultProjectCommandBuilder) BuildAutoplanCommands(ctx *command.Context) ([]command.ProjectContext, error) {
-	projCtxs, err := p.buildAllCommandsByCfg(ctx, command.Plan, "", nil, false)
+	projCtxs, err := p.buildAllCommandsByCfg(ctx, command.Plan, "", nil, false, "", "")
 	if err != nil {
 		return nil, err
 	}
@@ -274,21 +306,150 @@ func (p *DefaultProjectCommandBuilder) BuildAutoplanCommands(ctx *command.Contex
 
 // See ProjectCommandBuilder.BuildPlanCommands.
 func (p *DefaultProjectCommandBuilder) BuildPlanCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
+	var projCtxs []command.ProjectContext
+	var err error
 	if !cmd.IsForSpecificProject() {
 		ctx.Log.Debug("Building plan command for all affected projects")
-		return p.buildAllCommandsByCfg(ctx, cmd.CommandName(), cmd.SubName, cmd.Flags, cmd.Verbose)
+		projCtxs, err = p.buildAllCommandsByCfg(ctx, cmd.CommandName(), cmd.SubName, cmd.Flags, cmd.Verbose, cmd.SinceRef, cmd.Workflow)
+	} else {
+		ctx.Log.Debug("Building plan command for specific project with directory: '%v', workspace: '%v', project: '%v'",
+			cmd.RepoRelDir, cmd.Workspace, cmd.ProjectName)
+		projCtxs, err = p.buildProjectPlanCommand(ctx, cmd)
+	}
+	if err != nil {
+		return nil, err
+	}
+	projCtxs, err = filterWorkspacePattern(ctx, projCtxs, cmd.WorkspacePattern)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.Upgrade {
+		for i := range projCtxs {
+			projCtxs[i].InitUpgrade = true
+		}
+	}
+	if cmd.Destroy {
+		for i := range projCtxs {
+			projCtxs[i].PlanDestroy = true
+			// The re-plan command starts with "<executable> plan" so planning
+			// again also plans the destroy.
+			projCtxs[i].RePlanCmd = strings.Replace(projCtxs[i].RePlanCmd, " "+command.Plan.String(), fmt.Sprintf(" %s --%s", command.Plan.String(), destroyFlagLong), 1)
+		}
+	}
+	if cmd.RefreshOnly {
+		for i := range projCtxs {
+			projCtxs[i].PlanRefreshOnly = true
+			// Planning again also plans a refresh-only plan.
+			projCtxs[i].RePlanCmd = strings.Replace(projCtxs[i].RePlanCmd, " "+command.Plan.String(), fmt.Sprintf(" %s --%s", command.Plan.String(), refreshOnlyFlagLong), 1)
+		}
+	}
+	projCtxs, err = excludeProjectCmds(ctx, projCtxs, cmd.ExcludeProjectNames)
+	if err != nil {
+		return nil, err
 	}
-	ctx.Log.Debug("Building plan command for specific project with directory: '%v', workspace: '%v', project: '%v'",
-		cmd.RepoRelDir, cmd.Workspace, cmd.ProjectName)
-	return p.buildProjectPlanCommand(ctx, cmd)
+	if cmd.Failed {
+		// Filtered last so projects that didn't fail can still be excluded
+		// without an error.
+		projCtxs = filterFailedPlans(ctx, projCtxs)
+	}
+	return projCtxs, nil
+}
+
+// filterWorkspacePattern keeps the projects of projCtxs with a workspace
+// matching the glob pattern. It errors with the workspaces of the projects if
+// none match so the user can fix the pattern.
+func filterWorkspacePattern(ctx *command.Context, projCtxs []command.ProjectContext, pattern string) ([]command.ProjectContext, error) {
+	if pattern == "" {
+		return projCtxs, nil
+	}
+	var matched []command.ProjectContext
+	var workspaces []string
+	for _, projCtx := range projCtxs {
+		if ok, _ := path.Match(pattern, projCtx.Workspace); ok {
+			matched = append(matched, projCtx)
+			continue
+		}
+		ctx.Log.Debug("ignoring project at dir '%s', workspace '%s' since the workspace doesn't match %q", projCtx.RepoRelDir, projCtx.Workspace, pattern)
+		if !slices.Contains(workspaces, projCtx.Workspace) {
+			workspaces = append(workspaces, projCtx.Workspace)
+		}
+	}
+	if len(matched) == 0 && len(projCtxs) > 0 {
+		slices.Sort(workspaces)
+		return nil, fmt.Errorf("no projects have a workspace matching %q, the workspaces of the projects to plan are: %s", pattern, strings.Join(workspaces, ", "))
+	}
+	return matched, nil
+}
+
+// filterFailedPlans keeps the projects of projCtxs whose last plan at the
+// pull request's head commit failed. Results of previous commits are ignored
+// since the failures may have been fixed since.
+func filterFailedPlans(ctx *command.Context, projCtxs []command.ProjectContext) []command.ProjectContext {
+	if ctx.PullStatus == nil || ctx.PullStatus.Pull.HeadCommit != ctx.Pull.HeadCommit {
+		ctx.Log.Debug("no plan results for commit %s, no projects failed to plan", ctx.Pull.HeadCommit)
+		return nil
+	}
+	var failed []command.ProjectContext
+	for _, projCtx := range projCtxs {
+		if slices.ContainsFunc(ctx.PullStatus.Projects, func(status models.ProjectStatus) bool {
+			return status.Status == models.ErroredPlanStatus &&
+				status.ProjectName == projCtx.ProjectName &&
+				status.Workspace == projCtx.Workspace &&
+				status.RepoRelDir == projCtx.RepoRelDir
+		}) {
+			failed = append(failed, projCtx)
+			continue
+		}
+		ctx.Log.Debug("ignoring project at dir '%s', workspace '%s' since its last plan didn't fail", projCtx.RepoRelDir, projCtx.Workspace)
+	}
+	return failed
+}
+
+// excludeProjectCmds removes the projects named in excludeNames from
+// projCtxs. It errors if a name doesn't match any of the projects so typos
+// don't silently plan the project the user wanted to skip.
+func excludeProjectCmds(ctx *command.Context, projCtxs []command.ProjectContext, excludeNames []string) ([]command.ProjectContext, error) {
+	if len(excludeNames) == 0 {
+		return projCtxs, nil
+	}
+	var unknown []string
+	for _, name := range excludeNames {
+		if !slices.ContainsFunc(projCtxs, func(projCtx command.ProjectContext) bool { return projCtx.ProjectName == name }) {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("cannot exclude project(s) not in the plan list of this pull request: %s", strings.Join(unknown, ", "))
+	}
+	var included []command.ProjectContext
+	for _, projCtx := range projCtxs {
+		if slices.Contains(excludeNames, projCtx.ProjectName) {
+			ctx.Log.Info("excluding project %q at dir '%s', workspace '%s'", projCtx.ProjectName, projCtx.RepoRelDir, projCtx.Workspace)
+			continue
+		}
+		included = append(included, projCtx)
+	}
+	return included, nil
 }
 
 // See ProjectCommandBuilder.BuildApplyCommands.
 func (p *DefaultProjectCommandBuilder) BuildApplyCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
+	var projCtxs []command.ProjectContext
+	var err error
 	if !cmd.IsForSpecificProject() {
-		return p.buildAllProjectCommandsByPlan(ctx, cmd)
+		projCtxs, err = p.buildAllProjectCommandsByPlan(ctx, cmd)
+	} else {
+		projCtxs, err = p.buildProjectCommand(ctx, cmd)
 	}
-	return p.buildProjectCommand(ctx, cmd)
+	if err != nil {
+		return nil, err
+	}
+	if cmd.DryRun {
+		for i := range projCtxs {
+			projCtxs[i].ApplyDryRun = true
+		}
+	}
+	return projCtxs, nil
 }
 
 func (p *DefaultProjectCommandBuilder) BuildApprovePoliciesCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
@@ -308,7 +469,7 @@ func (p *DefaultProjectCommandBuilder) BuildVersionCommands(ctx *command.Context
 func (p *DefaultProjectCommandBuilder) BuildImportCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
 	if !cmd.IsForSpecificProject() {
 		// import discard a plan file, so use buildAllCommandsByCfg instead buildAllProjectCommandsByPlan.
-		return p.buildAllCommandsByCfg(ctx, cmd.CommandName(), cmd.SubName, cmd.Flags, cmd.Verbose)
+		return p.buildAllCommandsByCfg(ctx, cmd.CommandName(), cmd.SubName, cmd.Flags, cmd.Verbose, "", "")
 	}
 	return p.buildProjectCommand(ctx, cmd)
 }
@@ -316,11 +477,17 @@ func (p *DefaultProjectCommandBuilder) BuildImportCommands(ctx *command.Context,
 func (p *DefaultProjectCommandBuilder) BuildStateRmCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
 	if !cmd.IsForSpecificProject() {
 		// state rm discard a plan file, so use buildAllCommandsByCfg instead buildAllProjectCommandsByPlan.
-		return p.buildAllCommandsByCfg(ctx, cmd.CommandName(), cmd.SubName, cmd.Flags, cmd.Verbose)
+		return p.buildAllCommandsByCfg(ctx, cmd.CommandName(), cmd.SubName, cmd.Flags, cmd.Verbose, "", "")
 	}
 	return p.buildProjectCommand(ctx, cmd)
 }
 
+// See ProjectCommandBuilder.BuildDriftCommands.
+func (p *DefaultProjectCommandBuilder) BuildDriftCommands(ctx *command.Context, cmd *CommentCommand) ([]command.ProjectContext, error) {
+	// Drift runs for the same projects as plan.
+	return p.BuildPlanCommands(ctx, cmd)
+}
+
 // shouldSkipClone determines whether we should skip cloning for a given context
 func (p *DefaultProjectCommandBuilder) shouldSkipClone(ctx *command.Context, modifiedFiles []string) (bool, error) {
 	// NOTE: We discard this work here and end up doing it again after
@@ -336,15 +503,23 @@ func (p *DefaultProjectCommandBuilder) shouldSkipClone(ctx *command.Context, mod
 	if err != nil {
 		return false, errors.Wrapf(err, "downloading %s", repoCfgFile)
 	}
+	var repoCfg valid.RepoCfg
+	if hasRepoCfg {
+		repoCfg, err = p.ParserValidator.ParseRepoCfgData(repoCfgData, p.GlobalCfg, ctx.Pull.BaseRepo.ID(), ctx.Pull.BaseBranch)
+		if err != nil {
+			return false, errors.Wrapf(err, "parsing %s", repoCfgFile)
+		}
+		ctx.Log.Info("successfully parsed remote %s file", repoCfgFile)
+	} else {
+		hasRepoCfg, repoCfg, err = p.ParserValidator.ParseRemoteRepoCfg(ctx.Log, p.GlobalCfg, ctx.Pull.BaseRepo.ID(), ctx.Pull.BaseBranch)
+		if err != nil {
+			return false, errors.Wrapf(err, "parsing %s", repoCfgFile)
+		}
+	}
 	// We can only skip if we determine that none of the modified files belong to projects configured in a repo config
 	if !hasRepoCfg {
 		return false, nil
 	}
-	repoCfg, err := p.ParserValidator.ParseRepoCfgData(repoCfgData, p.GlobalCfg, ctx.Pull.BaseRepo.ID(), ctx.Pull.BaseBranch)
-	if err != nil {
-		return false, errors.Wrapf(err, "parsing %s", repoCfgFile)
-	}
-	ctx.Log.Info("successfully parsed remote %s file", repoCfgFile)
 
 	// If auto discover is enabled, we never want to skip cloning
 	if p.autoDiscoverModeEnabled(ctx, repoCfg) {
@@ -357,6 +532,10 @@ func (p *DefaultProjectCommandBuilder) shouldSkipClone(ctx *command.Context, mod
 		return false, nil
 	}
 
+	modifiedFiles, err = filterAutoplanIgnored(ctx, modifiedFiles, repoCfg)
+	if err != nil {
+		return false, err
+	}
 	matchingProjects, err := p.ProjectFinder.DetermineProjectsViaConfig(ctx.Log, modifiedFiles, repoCfg, "", nil)
 	if err != nil {
 		return false, err
@@ -395,10 +574,32 @@ func (p *DefaultProjectCommandBuilder) isAutoDiscoverPathIgnored(ctx *command.Co
 	return false
 }
 
+// filterAutoplanIgnored removes the modified files matched by the repo's
+// autoplan ignore patterns so they're never matched against the projects'
+// when_modified patterns or used to discover projects.
+func filterAutoplanIgnored(ctx *command.Context, modifiedFiles []string, repoCfg valid.RepoCfg) ([]string, error) {
+	if repoCfg.Autoplan == nil {
+		return modifiedFiles, nil
+	}
+	filtered, err := repoCfg.Autoplan.FilterIgnored(modifiedFiles)
+	if err != nil {
+		return nil, err
+	}
+	if ignored := len(modifiedFiles) - len(filtered); ignored > 0 {
+		ctx.Log.Info("ignoring %d modified files that match the autoplan ignore patterns", ignored)
+	}
+	return filtered, nil
+}
+
 // getMergedProjectCfgs gets all merged project configs for building commands given a context and a clone repo
 func (p *DefaultProjectCommandBuilder) getMergedProjectCfgs(ctx *command.Context, repoDir string, modifiedFiles []string, repoCfg valid.RepoCfg) ([]valid.MergedProjectCfg, error) {
 	mergedCfgs := make([]valid.MergedProjectCfg, 0)
 
+	modifiedFiles, err := filterAutoplanIgnored(ctx, modifiedFiles, repoCfg)
+	if err != nil {
+		return nil, err
+	}
+
 	moduleInfo, err := FindModuleProjects(repoDir, p.AutoDetectModuleFiles)
 	if err != nil {
 		ctx.Log.Warn("error(s) loading project module dependencies: %s", err)
@@ -464,8 +665,9 @@ func (p *DefaultProjectCommandBuilder) getMergedProjectCfgs(ctx *command.Context
 }
 
 // buildAllCommandsByCfg builds init contexts for all projects we determine were
-// modified in this ctx.
-func (p *DefaultProjectCommandBuilder) buildAllCommandsByCfg(ctx *command.Context, cmdName command.Name, subCmdName string, commentFlags []string, verbose bool) ([]command.ProjectContext, error) {
+// modified in this ctx. If sinceRef is set, the files changed since that git
+// ref are used instead of the files modified by the pull request.
+func (p *DefaultProjectCommandBuilder) buildAllCommandsByCfg(ctx *command.Context, cmdName command.Name, subCmdName string, commentFlags []string, verbose bool, sinceRef string, workflowName string) ([]command.ProjectContext, error) {
 	// We'll need the list of modified files.
 	modifiedFiles, err := p.VCSClient.GetModifiedFiles(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull)
 	if err != nil {
@@ -475,7 +677,8 @@ func (p *DefaultProjectCommandBuilder) buildAllCommandsByCfg(ctx *command.Contex
 	ctx.Log.Debug("%d files were modified in this pull request. Modified files: %v", len(modifiedFiles), modifiedFiles)
 
 	// If we're not including git untracked files, we can skip the clone if there are no modified files.
-	if !p.IncludeGitUntrackedFiles {
+	// The files changed since a ref are only known after cloning.
+	if !p.IncludeGitUntrackedFiles && sinceRef == "" {
 		shouldSkipClone, err := p.shouldSkipClone(ctx, modifiedFiles)
 		if err != nil {
 			return nil, err
@@ -501,6 +704,14 @@ func (p *DefaultProjectCommandBuilder) buildAllCommandsByCfg(ctx *command.Contex
 		return nil, err
 	}
 
+	if sinceRef != "" {
+		modifiedFiles, err = p.WorkingDir.GetGitChangedFilesSince(ctx.Log, ctx.HeadRepo, ctx.Pull, DefaultWorkspace, sinceRef)
+		if err != nil {
+			return nil, err
+		}
+		ctx.Log.Debug("%d files were changed since %s. Changed files: %v", len(modifiedFiles), sinceRef, modifiedFiles)
+	}
+
 	if p.IncludeGitUntrackedFiles {
 		ctx.Log.Debug(("'include-git-untracked-files' option is set, getting untracked files"))
 		untrackedFiles, err := p.WorkingDir.GetGitUntrackedFiles(ctx.Log, ctx.HeadRepo, ctx.Pull, DefaultWorkspace)
@@ -529,13 +740,30 @@ func (p *DefaultProjectCommandBuilder) buildAllCommandsByCfg(ctx *command.Contex
 		}
 		ctx.Log.Info("successfully parsed %s file", repoCfgFile)
 	} else {
-		ctx.Log.Info("repo config file %s is absent, using global defaults", repoCfgFile)
+		hasRepoCfg, repoCfg, err = p.ParserValidator.ParseRemoteRepoCfg(ctx.Log, p.GlobalCfg, ctx.Pull.BaseRepo.ID(), ctx.Pull.BaseBranch)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", repoCfgFile)
+		}
+		if !hasRepoCfg {
+			ctx.Log.Info("repo config file %s is absent, using global defaults", repoCfgFile)
+		}
 	}
+	logBranchSkippedProjects(ctx, repoCfg)
+	logRepoCfgWarnings(ctx, repoCfg)
 
 	mergedProjectCfgs, err := p.getMergedProjectCfgs(ctx, repoDir, modifiedFiles, repoCfg)
 	if err != nil {
 		return nil, err
 	}
+	selectedWorkflow, err := p.selectWorkflow(ctx, &repoCfg, workflowName)
+	if err != nil {
+		return nil, err
+	}
+	if selectedWorkflow != nil {
+		for i := range mergedProjectCfgs {
+			mergedProjectCfgs[i].Workflow = *selectedWorkflow
+		}
+	}
 
 	automerge := p.EnableAutoMerge
 	parallelApply := p.EnableParallelApply
@@ -555,26 +783,27 @@ func (p *DefaultProjectCommandBuilder) buildAllCommandsByCfg(ctx *command.Contex
 	}
 
 	for _, mergedProjectCfg := range mergedProjectCfgs {
-		projCtxs = append(projCtxs,
-			p.ProjectCommandContextBuilder.BuildProjectContext(
-				ctx,
-				cmdName,
-				subCmdName,
-				mergedProjectCfg,
-				commentFlags,
-				repoDir,
-				automerge,
-				parallelApply,
-				parallelPlan,
-				verbose,
-				abortOnExecutionOrderFail,
-				p.TerraformExecutor,
-			)...)
+		ctxs, err := p.ProjectCommandContextBuilder.BuildProjectContext(
+			ctx,
+			cmdName,
+			subCmdName,
+			mergedProjectCfg,
+			commentFlags,
+			repoDir,
+			automerge,
+			parallelApply,
+			parallelPlan,
+			verbose,
+			abortOnExecutionOrderFail,
+			p.TerraformExecutor,
+		)
+		if err != nil {
+			return nil, err
+		}
+		projCtxs = append(projCtxs, ctxs...)
 	}
 
-	sort.Slice(projCtxs, func(i, j int) bool {
-		return projCtxs[i].ExecutionOrderGroup < projCtxs[j].ExecutionOrderGroup
-	})
+	sortProjectCmds(projCtxs, p.ProjectOrder)
 
 	// Filter projects to only include ones the user is authorized for
 	projCtxs = slices.DeleteFunc(projCtxs, func(projCtx command.ProjectContext) bool {
@@ -671,10 +900,13 @@ func (p *DefaultProjectCommandBuilder) buildProjectPlanCommand(ctx *command.Cont
 			var notFoundFiles = []string{}
 			var repoConfig valid.RepoCfg
 
-			repoConfig, err = p.ParserValidator.ParseRepoCfg(defaultRepoDir, p.GlobalCfg, ctx.Pull.BaseRepo.ID(), ctx.Pull.BaseBranch)
+			hasRepoCfg, repoConfig, err := p.parseRepoCfg(ctx, defaultRepoDir)
 			if err != nil {
 				return pcc, err
 			}
+			if !hasRepoCfg {
+				return pcc, fmt.Errorf("cannot specify a project name unless an %s file exists to configure projects", p.GlobalCfg.RepoConfigFile(ctx.Pull.BaseRepo.ID()))
+			}
 			repoCfgProjects := repoConfig.FindProjectsByName(cmd.ProjectName)
 
 			for _, f := range modifiedFiles {
@@ -712,24 +944,77 @@ func (p *DefaultProjectCommandBuilder) buildProjectPlanCommand(ctx *command.Cont
 
 	return p.buildProjectCommandCtx(
 		ctx,
-		command.Plan,
+		cmd.Name,
 		"",
 		cmd.ProjectName,
 		cmd.Flags,
 		defaultRepoDir,
 		repoRelDir,
-		workspace,
+		projectCmdWorkspace(cmd, workspace),
 		cmd.Verbose,
+		cmd.Workflow,
 	)
 }
 
+// selectWorkflow returns the workflow named name that the projects use instead
+// of their configured workflow, or nil if name is empty. repoCfg is nil if the
+// repo doesn't have a repo config.
+func (p *DefaultProjectCommandBuilder) selectWorkflow(ctx *command.Context, repoCfg *valid.RepoCfg, name string) (*valid.Workflow, error) {
+	if name == "" {
+		return nil, nil
+	}
+	var rCfg valid.RepoCfg
+	if repoCfg != nil {
+		rCfg = *repoCfg
+	}
+	workflow, err := p.GlobalCfg.SelectWorkflow(ctx.Log, ctx.Pull.BaseRepo.ID(), rCfg, name)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Log.Info("using workflow %q selected by the comment", name)
+	return &workflow, nil
+}
+
+// parseRepoCfg returns the repo config in repoDir or, if the repo doesn't have
+// one, the config at its repo_config_url. It returns false if there's neither.
+func (p *DefaultProjectCommandBuilder) parseRepoCfg(ctx *command.Context, repoDir string) (bool, valid.RepoCfg, error) {
+	repoCfgFile := p.GlobalCfg.RepoConfigFile(ctx.Pull.BaseRepo.ID())
+	hasRepoCfg, err := p.ParserValidator.HasRepoCfg(repoDir, repoCfgFile)
+	if err != nil {
+		return false, valid.RepoCfg{}, errors.Wrapf(err, "looking for '%s' file in '%s'", repoCfgFile, repoDir)
+	}
+	var repoCfg valid.RepoCfg
+	if hasRepoCfg {
+		repoCfg, err = p.ParserValidator.ParseRepoCfg(repoDir, p.GlobalCfg, ctx.Pull.BaseRepo.ID(), ctx.Pull.BaseBranch)
+	} else {
+		hasRepoCfg, repoCfg, err = p.ParserValidator.ParseRemoteRepoCfg(ctx.Log, p.GlobalCfg, ctx.Pull.BaseRepo.ID(), ctx.Pull.BaseBranch)
+	}
+	logBranchSkippedProjects(ctx, repoCfg)
+	logRepoCfgWarnings(ctx, repoCfg)
+	return hasRepoCfg, repoCfg, err
+}
+
+// logBranchSkippedProjects logs the projects of repoCfg that are skipped since
+// their branch doesn't match the pull request's base branch.
+func logBranchSkippedProjects(ctx *command.Context, repoCfg valid.RepoCfg) {
+	for _, project := range repoCfg.BranchSkippedProjects {
+		ctx.Log.Info("skipping project %q in dir %q since its branch doesn't match the base branch %q", project.GetName(), project.Dir, ctx.Pull.BaseBranch)
+	}
+}
+
+// logRepoCfgWarnings logs the deprecated uses of repoCfg.
+func logRepoCfgWarnings(ctx *command.Context, repoCfg valid.RepoCfg) {
+	for _, warning := range repoCfg.Warnings {
+		ctx.Log.Warn("repo config: %s", warning)
+	}
+}
+
 // getCfg returns the atlantis.yaml config (if it exists) for this project. If
 // there is no config, then projectCfg and repoCfg will be nil.
 func (p *DefaultProjectCommandBuilder) getCfg(ctx *command.Context, projectName string, dir string, workspace string, repoDir string) (projectsCfg []valid.Project, repoCfg *valid.RepoCfg, err error) {
 	repoCfgFile := p.GlobalCfg.RepoConfigFile(ctx.Pull.BaseRepo.ID())
-	hasRepoCfg, err := p.ParserValidator.HasRepoCfg(repoDir, repoCfgFile)
+	hasRepoCfg, repoConfig, err := p.parseRepoCfg(ctx, repoDir)
 	if err != nil {
-		err = errors.Wrapf(err, "looking for '%s' file in '%s'", repoCfgFile, repoDir)
 		return
 	}
 	if !hasRepoCfg {
@@ -739,12 +1024,6 @@ func (p *DefaultProjectCommandBuilder) getCfg(ctx *command.Context, projectName
 		}
 		return
 	}
-
-	var repoConfig valid.RepoCfg
-	repoConfig, err = p.ParserValidator.ParseRepoCfg(repoDir, p.GlobalCfg, ctx.Pull.BaseRepo.ID(), ctx.Pull.BaseBranch)
-	if err != nil {
-		return
-	}
 	repoCfg = &repoConfig
 
 	// If they've specified a project by name we look it up. Otherwise we
@@ -757,6 +1036,13 @@ func (p *DefaultProjectCommandBuilder) getCfg(ctx *command.Context, projectName
 				projectsCfg = append(projectsCfg, *p)
 			}
 		}
+		// Pending plans for a project can be in one of its allowed
+		// workspaces rather than the configured one.
+		for i, proj := range projectsCfg {
+			if workspace != "" && slices.Contains(proj.AllowedWorkspaces, workspace) {
+				projectsCfg[i].Workspace = workspace
+			}
+		}
 		if len(projectsCfg) == 0 {
 			if p.SilenceNoProjects && len(repoConfig.Projects) > 0 {
 				ctx.Log.Debug("no project with name '%s' found but silencing the error", projectName)
@@ -768,6 +1054,12 @@ func (p *DefaultProjectCommandBuilder) getCfg(ctx *command.Context, projectName
 		return
 	}
 
+	// Without a workspace, ex. for workspace patterns, every project in the
+	// dir matches.
+	if workspace == "" {
+		projectsCfg = repoCfg.FindProjectsByDir(dir)
+		return
+	}
 	projCfgs := repoCfg.FindProjectsByDirWorkspace(dir, workspace)
 	if len(projCfgs) == 0 {
 		return
@@ -808,16 +1100,14 @@ func (p *DefaultProjectCommandBuilder) buildAllProjectCommandsByPlan(ctx *comman
 			return nil, err
 		}
 		defer unlockFn()
-		commentCmds, err := p.buildProjectCommandCtx(ctx, commentCmd.CommandName(), commentCmd.SubName, plan.ProjectName, commentCmd.Flags, defaultRepoDir, plan.RepoRelDir, plan.Workspace, commentCmd.Verbose)
+		commentCmds, err := p.buildProjectCommandCtx(ctx, commentCmd.CommandName(), commentCmd.SubName, plan.ProjectName, commentCmd.Flags, defaultRepoDir, plan.RepoRelDir, plan.Workspace, commentCmd.Verbose, commentCmd.Workflow)
 		if err != nil {
 			return nil, errors.Wrapf(err, "building command for dir '%s'", plan.RepoRelDir)
 		}
 		cmds = append(cmds, commentCmds...)
 	}
 
-	sort.Slice(cmds, func(i, j int) bool {
-		return cmds[i].ExecutionOrderGroup < cmds[j].ExecutionOrderGroup
-	})
+	sortProjectCmds(cmds, p.ProjectOrder)
 
 	return cmds, nil
 }
@@ -859,11 +1149,24 @@ func (p *DefaultProjectCommandBuilder) buildProjectCommand(ctx *command.Context,
 		cmd.Flags,
 		repoDir,
 		repoRelDir,
-		workspace,
+		projectCmdWorkspace(cmd, workspace),
 		cmd.Verbose,
+		cmd.Workflow,
 	)
 }
 
+// projectCmdWorkspace returns the workspace to look up cmd's project with.
+// Comments can't set a workspace along with a project name so in that case
+// the workspace is left empty and the project's configured workspace is used.
+// It's also left empty for workspace patterns so all the projects in the dir
+// are matched against the pattern.
+func projectCmdWorkspace(cmd *CommentCommand, workspace string) string {
+	if cmd.Workspace == "" && (cmd.ProjectName != "" || cmd.WorkspacePattern != "") {
+		return ""
+	}
+	return workspace
+}
+
 // buildProjectCommandCtx builds a context for a single or several projects identified
 // by the parameters.
 func (p *DefaultProjectCommandBuilder) buildProjectCommandCtx(ctx *command.Context,
@@ -874,12 +1177,20 @@ func (p *DefaultProjectCommandBuilder) buildProjectCommandCtx(ctx *command.Conte
 	repoDir string,
 	repoRelDir string,
 	workspace string,
-	verbose bool) ([]command.ProjectContext, error) {
+	verbose bool,
+	workflowName string) ([]command.ProjectContext, error) {
 
 	matchingProjects, repoCfgPtr, err := p.getCfg(ctx, projectName, repoRelDir, workspace, repoDir)
 	if err != nil {
 		return []command.ProjectContext{}, err
 	}
+	selectedWorkflow, err := p.selectWorkflow(ctx, repoCfgPtr, workflowName)
+	if err != nil {
+		return []command.ProjectContext{}, err
+	}
+	if workspace == "" {
+		workspace = DefaultWorkspace
+	}
 	var projCtxs []command.ProjectContext
 	var projCfg valid.MergedProjectCfg
 	automerge := p.EnableAutoMerge
@@ -908,33 +1219,11 @@ func (p *DefaultProjectCommandBuilder) buildProjectCommandCtx(ctx *command.Conte
 		for _, mp := range matchingProjects {
 			ctx.Log.Debug("Merging config for project at dir: '%s' workspace: '%s'", mp.Dir, mp.Workspace)
 			projCfg = p.GlobalCfg.MergeProjectCfg(ctx.Log, ctx.Pull.BaseRepo.ID(), mp, *repoCfgPtr)
+			if selectedWorkflow != nil {
+				projCfg.Workflow = *selectedWorkflow
+			}
 
-			projCtxs = append(projCtxs,
-				p.ProjectCommandContextBuilder.BuildProjectContext(
-					ctx,
-					cmd,
-					subCmd,
-					projCfg,
-					commentFlags,
-					repoDir,
-					automerge,
-					parallelApply,
-					parallelPlan,
-					verbose,
-					abortOnExecutionOrderFail,
-					p.TerraformExecutor,
-				)...)
-		}
-	} else {
-		// Ignore the project if silenced with projects set in the repo config
-		if p.SilenceNoProjects && repoCfgPtr != nil && len(repoCfgPtr.Projects) > 0 {
-			ctx.Log.Debug("silencing is in effect, project will be ignored")
-			return []command.ProjectContext{}, nil
-		}
-
-		projCfg = p.GlobalCfg.DefaultProjCfg(ctx.Log, ctx.Pull.BaseRepo.ID(), repoRelDir, workspace)
-		projCtxs = append(projCtxs,
-			p.ProjectCommandContextBuilder.BuildProjectContext(
+			ctxs, err := p.ProjectCommandContextBuilder.BuildProjectContext(
 				ctx,
 				cmd,
 				subCmd,
@@ -947,7 +1236,41 @@ func (p *DefaultProjectCommandBuilder) buildProjectCommandCtx(ctx *command.Conte
 				verbose,
 				abortOnExecutionOrderFail,
 				p.TerraformExecutor,
-			)...)
+			)
+			if err != nil {
+				return nil, err
+			}
+			projCtxs = append(projCtxs, ctxs...)
+		}
+	} else {
+		// Ignore the project if silenced with projects set in the repo config
+		if p.SilenceNoProjects && repoCfgPtr != nil && len(repoCfgPtr.Projects) > 0 {
+			ctx.Log.Debug("silencing is in effect, project will be ignored")
+			return []command.ProjectContext{}, nil
+		}
+
+		projCfg = p.GlobalCfg.DefaultProjCfg(ctx.Log, ctx.Pull.BaseRepo.ID(), repoRelDir, workspace)
+		if selectedWorkflow != nil {
+			projCfg.Workflow = *selectedWorkflow
+		}
+		ctxs, err := p.ProjectCommandContextBuilder.BuildProjectContext(
+			ctx,
+			cmd,
+			subCmd,
+			projCfg,
+			commentFlags,
+			repoDir,
+			automerge,
+			parallelApply,
+			parallelPlan,
+			verbose,
+			abortOnExecutionOrderFail,
+			p.TerraformExecutor,
+		)
+		if err != nil {
+			return nil, err
+		}
+		projCtxs = append(projCtxs, ctxs...)
 	}
 
 	if err := p.validateWorkspaceAllowed(repoCfgPtr, repoRelDir, workspace); err != nil {
@@ -993,3 +1316,33 @@ func (p *DefaultProjectCommandBuilder) validateWorkspaceAllowed(repoCfg *valid.R
 
 	return repoCfg.ValidateWorkspaceAllowed(repoRelDir, workspace)
 }
+
+// sortProjectCmds sorts cmds by their execution order group and within a
+// group by order, one of ValidProjectOrders. Commands that are equal by order
+// keep the order they were built in.
+func sortProjectCmds(cmds []command.ProjectContext, order string) {
+	slices.SortStableFunc(cmds, func(a, b command.ProjectContext) int {
+		if c := cmp.Compare(a.ExecutionOrderGroup, b.ExecutionOrderGroup); c != 0 {
+			return c
+		}
+		switch order {
+		case ProjectOrderConfig:
+			return cmp.Compare(configOrderKey(a), configOrderKey(b))
+		case ProjectOrderName:
+			return cmp.Or(
+				cmp.Compare(a.ProjectName, b.ProjectName),
+				cmp.Compare(a.RepoRelDir, b.RepoRelDir),
+				cmp.Compare(a.Workspace, b.Workspace),
+			)
+		}
+		return 0
+	})
+}
+
+// configOrderKey returns the key to order ctx by like the repo config.
+func configOrderKey(ctx command.ProjectContext) int {
+	if ctx.ConfigOrder == 0 {
+		return math.MaxInt
+	}
+	return ctx.ConfigOrder
+}
@@ -6,6 +6,7 @@ package events
 import (
 	"os"
 	"path/filepath"
+	"slices"
 	"testing"
 
 	version "github.com/hashicorp/go-version"
@@ -141,6 +142,67 @@ projects:
 				RepoConfigVersion:  3,
 				RePlanCmd:          "atlantis plan -d project1 -w myworkspace -- flag",
 				RepoRelDir:         "project1",
+				ConfigOrder:        1,
+				TerraformVersion:   mustVersion("10.0"),
+				User:               models.User{},
+				Verbose:            true,
+				Workspace:          "myworkspace",
+				PolicySets:         emptyPolicySets,
+				RepoLocksMode:      valid.DefaultRepoLocksMode,
+			},
+			expPlanSteps:  []string{"init", "plan"},
+			expApplySteps: []string{"apply"},
+		},
+
+		// Project automerge settings override the repo setting.
+		"project automerge": {
+			globalCfg: `
+repos:
+- id: /.*/
+  workflow: default
+workflows:
+  default:
+    plan:
+      steps:
+      - init
+      - plan
+    apply:
+      steps:
+      - apply`,
+			repoCfg: `
+version: 3
+automerge: true
+projects:
+- dir: project1
+  workspace: myworkspace
+  autoplan:
+    enabled: true
+    when_modified: [../modules/**/*.tf]
+  terraform_version: v10.0
+  automerge: false
+  `,
+			expCtx: command.ProjectContext{
+				ApplyCmd:           "atlantis apply -d project1 -w myworkspace",
+				ApprovePoliciesCmd: "atlantis approve_policies -d project1 -w myworkspace",
+				BaseRepo:           baseRepo,
+				EscapedCommentArgs: []string{`\f\l\a\g`},
+				AutomergeEnabled:   false,
+				AutoplanEnabled:    true,
+				HeadRepo:           models.Repo{},
+				Log:                logger,
+				Scope:              statsScope,
+				PullReqStatus: models.PullReqStatus{
+					MergeableStatus: models.MergeableStatus{IsMergeable: true},
+				},
+				Pull:               pull,
+				ProjectName:        "",
+				PlanRequirements:   []string{},
+				ApplyRequirements:  []string{},
+				ImportRequirements: []string{},
+				RepoConfigVersion:  3,
+				RePlanCmd:          "atlantis plan -d project1 -w myworkspace -- flag",
+				RepoRelDir:         "project1",
+				ConfigOrder:        1,
 				TerraformVersion:   mustVersion("10.0"),
 				User:               models.User{},
 				Verbose:            true,
@@ -202,6 +264,7 @@ projects:
 				RepoConfigVersion:  3,
 				RePlanCmd:          "atlantis plan -d project1 -w myworkspace -- flag",
 				RepoRelDir:         "project1",
+				ConfigOrder:        1,
 				TerraformVersion:   mustVersion("10.0"),
 				User:               models.User{},
 				Verbose:            true,
@@ -271,6 +334,7 @@ projects:
 				RepoConfigVersion:  3,
 				RePlanCmd:          "atlantis plan -d project1 -w myworkspace -- flag",
 				RepoRelDir:         "project1",
+				ConfigOrder:        1,
 				TerraformVersion:   mustVersion("10.0"),
 				User:               models.User{},
 				Verbose:            true,
@@ -427,6 +491,7 @@ workflows:
 				RepoConfigVersion:  3,
 				RePlanCmd:          "atlantis plan -d project1 -w myworkspace -- flag",
 				RepoRelDir:         "project1",
+				ConfigOrder:        1,
 				TerraformVersion:   mustVersion("10.0"),
 				User:               models.User{},
 				Verbose:            true,
@@ -490,6 +555,7 @@ projects:
 				RepoConfigVersion:  3,
 				RePlanCmd:          "atlantis plan -d project1 -w myworkspace -- flag",
 				RepoRelDir:         "project1",
+				ConfigOrder:        1,
 				TerraformVersion:   mustVersion("10.0"),
 				User:               models.User{},
 				Verbose:            true,
@@ -556,6 +622,7 @@ workflows:
 				RepoConfigVersion:  3,
 				RePlanCmd:          "atlantis plan -d project1 -w myworkspace -- flag",
 				RepoRelDir:         "project1",
+				ConfigOrder:        1,
 				TerraformVersion:   mustVersion("10.0"),
 				User:               models.User{},
 				Verbose:            true,
@@ -608,6 +675,7 @@ projects:
 				RepoConfigVersion:  3,
 				RePlanCmd:          "atlantis plan -d project1 -w myworkspace -- flag",
 				RepoRelDir:         "project1",
+				ConfigOrder:        1,
 				User:               models.User{},
 				Verbose:            true,
 				Workspace:          "myworkspace",
@@ -674,6 +742,7 @@ projects:
 				false,
 				false,
 				"auto",
+				"",
 				statsScope,
 				terraformClient,
 			)
@@ -690,7 +759,7 @@ projects:
 						PullRequestStatus: models.PullReqStatus{
 							MergeableStatus: models.MergeableStatus{IsMergeable: true},
 						},
-					}, cmd, "", "", []string{"flag"}, tmp, "project1", "myworkspace", true)
+					}, cmd, "", "", []string{"flag"}, tmp, "project1", "myworkspace", true, "")
 
 					if c.expErr != "" {
 						ErrEquals(t, c.expErr, err)
@@ -822,6 +891,7 @@ projects:
 				RepoConfigVersion:  3,
 				RePlanCmd:          "atlantis plan -p myproject_1 -- flag",
 				RepoRelDir:         "project1",
+				ConfigOrder:        1,
 				TerraformVersion:   mustVersion("10.0"),
 				User:               models.User{},
 				Verbose:            true,
@@ -891,6 +961,7 @@ projects:
 				false,
 				false,
 				"auto",
+				"",
 				statsScope,
 				terraformClient,
 			)
@@ -907,7 +978,7 @@ projects:
 						PullRequestStatus: models.PullReqStatus{
 							MergeableStatus: models.MergeableStatus{IsMergeable: true},
 						},
-					}, cmd, "", "myproject_[1-2]", []string{"flag"}, tmp, "project1", "myworkspace", true)
+					}, cmd, "", "myproject_[1-2]", []string{"flag"}, tmp, "project1", "myworkspace", true, "")
 
 					if c.expErr != "" {
 						ErrEquals(t, c.expErr, err)
@@ -1067,6 +1138,7 @@ workflows:
 				RepoConfigVersion:  3,
 				RePlanCmd:          "atlantis plan -d project1 -w myworkspace -- flag",
 				RepoRelDir:         "project1",
+				ConfigOrder:        1,
 				TerraformVersion:   mustVersion("v10.0"),
 				User:               models.User{},
 				Verbose:            true,
@@ -1138,6 +1210,7 @@ workflows:
 				false,
 				false,
 				"auto",
+				"",
 				statsScope,
 				terraformClient,
 			)
@@ -1153,7 +1226,7 @@ workflows:
 					PullRequestStatus: models.PullReqStatus{
 						MergeableStatus: models.MergeableStatus{IsMergeable: true},
 					},
-				}, command.Plan, "", "", []string{"flag"}, tmp, "project1", "myworkspace", true)
+				}, command.Plan, "", "", []string{"flag"}, tmp, "project1", "myworkspace", true, "")
 
 				if c.expErr != "" {
 					ErrEquals(t, c.expErr, err)
@@ -1290,6 +1363,7 @@ projects:
 				true,
 				false,
 				"auto",
+				"",
 				statsScope,
 				terraformClient,
 			)
@@ -1305,7 +1379,7 @@ projects:
 						PullRequestStatus: models.PullReqStatus{
 							MergeableStatus: models.MergeableStatus{IsMergeable: true},
 						},
-					}, cmd, "", "", []string{}, tmp, "project1", "myworkspace", true)
+					}, cmd, "", "", []string{}, tmp, "project1", "myworkspace", true, "")
 					Equals(t, c.expLen, len(ctxs))
 					Ok(t, err)
 				})
@@ -1512,6 +1586,7 @@ autodiscover:
 				true,
 				false,
 				"auto",
+				"",
 				statsScope,
 				terraformClient,
 			)
@@ -1542,3 +1617,35 @@ func mustVersion(v string) *version.Version {
 	}
 	return vers
 }
+
+func TestSortProjectCmds(t *testing.T) {
+	cmds := []command.ProjectContext{
+		{ProjectName: "c", RepoRelDir: "c", ConfigOrder: 2},
+		{ProjectName: "", RepoRelDir: "discovered"},
+		{ProjectName: "b", RepoRelDir: "b", ConfigOrder: 3},
+		{ProjectName: "z", RepoRelDir: "z", ConfigOrder: 4, ExecutionOrderGroup: 1},
+		{ProjectName: "a", RepoRelDir: "a", ConfigOrder: 1},
+	}
+	names := func(cmds []command.ProjectContext) []string {
+		var names []string
+		for _, c := range cmds {
+			names = append(names, c.RepoRelDir)
+		}
+		return names
+	}
+	cases := []struct {
+		order string
+		exp   []string
+	}{
+		{ProjectOrderDiscovery, []string{"c", "discovered", "b", "a", "z"}},
+		{ProjectOrderConfig, []string{"a", "c", "b", "discovered", "z"}},
+		{ProjectOrderName, []string{"discovered", "a", "b", "c", "z"}},
+	}
+	for _, c := range cases {
+		t.Run(c.order, func(t *testing.T) {
+			sorted := slices.Clone(cmds)
+			sortProjectCmds(sorted, c.order)
+			Equals(t, c.exp, names(sorted))
+		})
+	}
+}
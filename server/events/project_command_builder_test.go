@@ -4,6 +4,9 @@
 package events_test
 
 import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"sort"
@@ -12,6 +15,7 @@ import (
 
 	"github.com/hashicorp/go-version"
 	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/core/terraform"
 	tfclientmocks "github.com/runatlantis/atlantis/server/core/terraform/tfclient/mocks"
 	"github.com/runatlantis/atlantis/server/metrics/metricstest"
 
@@ -170,6 +174,48 @@ projects:
 			TestDirStructure: defaultTestDirStructure,
 			exp:              nil,
 		},
+		{
+			Description: "autoplan ignore applied before when_modified",
+			AtlantisYAML: `
+version: 3
+autoplan:
+  ignore: ["**/generated/**"]
+projects:
+- dir: .
+  autoplan:
+    when_modified: ["generated/*.tf"]
+- dir: generated
+`,
+			TestDirStructure: map[string]interface{}{
+				"generated": map[string]interface{}{
+					"main.tf": nil,
+				},
+			},
+			exp: nil,
+		},
+		{
+			Description: "autoplan ignore with exception",
+			AtlantisYAML: `
+version: 3
+autoplan:
+  ignore: ["**/generated/**", "!**/generated/keep.tf"]
+projects:
+- dir: generated
+`,
+			TestDirStructure: map[string]interface{}{
+				"generated": map[string]interface{}{
+					"main.tf": nil,
+					"keep.tf": nil,
+				},
+			},
+			exp: []expCtxFields{
+				{
+					ProjectName: "",
+					RepoRelDir:  "generated",
+					Workspace:   "default",
+				},
+			},
+		},
 		{
 			Description: "workspaces from subdirectories detected",
 			TestDirStructure: map[string]interface{}{
@@ -276,6 +322,7 @@ terraform {
 				userConfig.SilenceNoProjects,
 				userConfig.IncludeGitUntrackedFiles,
 				userConfig.AutoDiscoverMode,
+				"",
 				scope,
 				terraformClient,
 			)
@@ -642,6 +689,7 @@ projects:
 					c.Silenced,
 					userConfig.IncludeGitUntrackedFiles,
 					c.AutoDiscoverModeUserCfg,
+					"",
 					scope,
 					terraformClient,
 				)
@@ -830,6 +878,7 @@ projects:
 				userConfig.SilenceNoProjects,
 				userConfig.IncludeGitUntrackedFiles,
 				userConfig.AutoDiscoverMode,
+				"",
 				scope,
 				terraformClient,
 			)
@@ -1195,6 +1244,7 @@ projects:
 				userConfig.SilenceNoProjects,
 				userConfig.IncludeGitUntrackedFiles,
 				userConfig.AutoDiscoverMode,
+				"",
 				scope,
 				terraformClient,
 			)
@@ -1293,6 +1343,7 @@ func TestDefaultProjectCommandBuilder_BuildMultiApply(t *testing.T) {
 		userConfig.SilenceNoProjects,
 		userConfig.IncludeGitUntrackedFiles,
 		userConfig.AutoDiscoverMode,
+		"",
 		scope,
 		terraformClient,
 	)
@@ -1379,6 +1430,7 @@ projects:
 		userConfig.SilenceNoProjects,
 		userConfig.IncludeGitUntrackedFiles,
 		userConfig.AutoDiscoverMode,
+		"",
 		scope,
 		terraformClient,
 	)
@@ -1401,6 +1453,990 @@ projects:
 	ErrEquals(t, "running commands in workspace \"notconfigured\" is not allowed because this directory is only configured for the following workspaces: default, staging", err)
 }
 
+// Test that a project's allowed_workspaces can be targeted and other
+// workspaces are rejected.
+func TestDefaultProjectCommandBuilder_AllowedWorkspaces(t *testing.T) {
+	cases := []struct {
+		workspace string
+		expErr    string
+	}{
+		{
+			workspace: "default",
+		},
+		{
+			workspace: "staging",
+		},
+		{
+			workspace: "stagign",
+			expErr:    "running commands in workspace \"stagign\" is not allowed because this directory is only configured for the following workspaces: default, staging, production",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.workspace, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			workingDir := mocks.NewMockWorkingDir()
+
+			tmpDir := DirStructure(t, map[string]interface{}{
+				"main.tf": nil,
+			})
+			yamlCfg := `version: 3
+projects:
+- name: app
+  dir: .
+  workspace: default
+  allowed_workspaces: [staging, production]
+`
+			err := os.WriteFile(filepath.Join(tmpDir, valid.DefaultAtlantisFile), []byte(yamlCfg), 0600)
+			Ok(t, err)
+
+			When(workingDir.Clone(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+				Any[string]())).ThenReturn(tmpDir, nil)
+			When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(tmpDir, nil)
+
+			globalCfgArgs := valid.GlobalCfgArgs{
+				AllowAllRepoSettings: true,
+			}
+			logger := logging.NewNoopLogger(t)
+			scope := metricstest.NewLoggingScope(t, logger, "atlantis")
+			userConfig := defaultUserConfig
+
+			terraformClient := tfclientmocks.NewMockClient()
+
+			builder := events.NewProjectCommandBuilder(
+				false,
+				&config.ParserValidator{},
+				&events.DefaultProjectFinder{},
+				nil,
+				workingDir,
+				events.NewDefaultWorkingDirLocker(),
+				valid.NewGlobalCfgFromArgs(globalCfgArgs),
+				&events.DefaultPendingPlanFinder{},
+				&events.CommentParser{ExecutableName: "atlantis"},
+				userConfig.SkipCloneNoChanges,
+				userConfig.EnableRegExpCmd,
+				userConfig.EnableAutoMerge,
+				userConfig.EnableParallelPlan,
+				userConfig.EnableParallelApply,
+				userConfig.AutoDetectModuleFiles,
+				userConfig.AutoplanFileList,
+				userConfig.RestrictFileList,
+				userConfig.SilenceNoProjects,
+				userConfig.IncludeGitUntrackedFiles,
+				userConfig.AutoDiscoverMode,
+				"",
+				scope,
+				terraformClient,
+			)
+
+			ctx := &command.Context{
+				HeadRepo: models.Repo{},
+				Pull:     models.PullRequest{},
+				User:     models.User{},
+				Log:      logger,
+				Scope:    scope,
+			}
+			ctxs, err := builder.BuildPlanCommands(ctx, &events.CommentCommand{
+				RepoRelDir: ".",
+				Name:       command.Plan,
+				Workspace:  c.workspace,
+			})
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, 1, len(ctxs))
+			Equals(t, "app", ctxs[0].ProjectName)
+			Equals(t, c.workspace, ctxs[0].Workspace)
+		})
+	}
+}
+
+// Test that projects sharing a directory are distinguished by workspace.
+func TestDefaultProjectCommandBuilder_MultipleProjectsPerDir(t *testing.T) {
+	yamlCfg := `version: 3
+projects:
+- dir: network
+  workspace: staging
+- dir: network
+  workspace: production
+- dir: app
+`
+	cases := []struct {
+		description   string
+		cmd           *events.CommentCommand
+		expWorkspaces []string
+		expErr        string
+	}{
+		{
+			description:   "autoplan plans every project in the modified dir",
+			expWorkspaces: []string{"production", "staging"},
+		},
+		{
+			description: "dir without workspace targets the default workspace",
+			cmd:         &events.CommentCommand{Name: command.Plan, RepoRelDir: "network"},
+			expErr:      "running commands in workspace \"default\" is not allowed because this directory is only configured for the following workspaces: staging, production",
+		},
+		{
+			description:   "dir and workspace targets a single project",
+			cmd:           &events.CommentCommand{Name: command.Plan, RepoRelDir: "network", Workspace: "staging"},
+			expWorkspaces: []string{"staging"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpDir := DirStructure(t, map[string]interface{}{
+				"network": map[string]interface{}{
+					"main.tf": nil,
+				},
+				"app": map[string]interface{}{
+					"main.tf": nil,
+				},
+			})
+			Ok(t, os.WriteFile(filepath.Join(tmpDir, valid.DefaultAtlantisFile), []byte(yamlCfg), 0600))
+
+			workingDir := mocks.NewMockWorkingDir()
+			When(workingDir.Clone(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+				Any[string]())).ThenReturn(tmpDir, nil)
+			When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(tmpDir, nil)
+			vcsClient := vcsmocks.NewMockClient()
+			When(vcsClient.GetModifiedFiles(Any[logging.SimpleLogging](), Any[models.Repo](),
+				Any[models.PullRequest]())).ThenReturn([]string{"network/main.tf"}, nil)
+
+			logger := logging.NewNoopLogger(t)
+			scope := metricstest.NewLoggingScope(t, logger, "atlantis")
+			userConfig := defaultUserConfig
+			builder := events.NewProjectCommandBuilder(
+				false,
+				&config.ParserValidator{},
+				&events.DefaultProjectFinder{},
+				vcsClient,
+				workingDir,
+				events.NewDefaultWorkingDirLocker(),
+				valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}),
+				&events.DefaultPendingPlanFinder{},
+				&events.CommentParser{ExecutableName: "atlantis"},
+				userConfig.SkipCloneNoChanges,
+				userConfig.EnableRegExpCmd,
+				userConfig.EnableAutoMerge,
+				userConfig.EnableParallelPlan,
+				userConfig.EnableParallelApply,
+				userConfig.AutoDetectModuleFiles,
+				userConfig.AutoplanFileList,
+				userConfig.RestrictFileList,
+				userConfig.SilenceNoProjects,
+				userConfig.IncludeGitUntrackedFiles,
+				userConfig.AutoDiscoverMode,
+				"",
+				scope,
+				tfclientmocks.NewMockClient(),
+			)
+
+			ctx := &command.Context{
+				PullRequestStatus: models.PullReqStatus{
+					MergeableStatus: models.MergeableStatus{IsMergeable: true},
+				},
+				Log:   logger,
+				Scope: scope,
+			}
+			var ctxs []command.ProjectContext
+			var err error
+			if c.cmd == nil {
+				ctxs, err = builder.BuildAutoplanCommands(ctx)
+			} else {
+				ctxs, err = builder.BuildPlanCommands(ctx, c.cmd)
+			}
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+
+			var workspaces []string
+			for _, projCtx := range ctxs {
+				Equals(t, "network", projCtx.RepoRelDir)
+				workspaces = append(workspaces, projCtx.Workspace)
+			}
+			sort.Strings(workspaces)
+			Equals(t, c.expWorkspaces, workspaces)
+		})
+	}
+}
+
+func TestDefaultProjectCommandBuilder_BuildPlanCommands_ExcludeProjects(t *testing.T) {
+	yamlCfg := `version: 3
+projects:
+- name: fast
+  dir: fast
+- name: slow
+  dir: slow
+- name: other
+  dir: other
+`
+	cases := []struct {
+		description string
+		cmd         *events.CommentCommand
+		expProjects []string
+		expErr      string
+	}{
+		{
+			description: "all projects but the excluded one",
+			cmd:         &events.CommentCommand{Name: command.Plan, ExcludeProjectNames: []string{"slow"}},
+			expProjects: []string{"fast", "other"},
+		},
+		{
+			description: "excluding the included project wins",
+			cmd:         &events.CommentCommand{Name: command.Plan, ProjectName: "slow", ExcludeProjectNames: []string{"slow"}},
+			expProjects: nil,
+		},
+		{
+			description: "unknown project",
+			cmd:         &events.CommentCommand{Name: command.Plan, ExcludeProjectNames: []string{"slow", "typo", "missing"}},
+			expErr:      "cannot exclude project(s) not in the plan list of this pull request: typo, missing",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpDir := DirStructure(t, map[string]interface{}{
+				"fast": map[string]interface{}{
+					"main.tf": nil,
+				},
+				"slow": map[string]interface{}{
+					"main.tf": nil,
+				},
+				"other": map[string]interface{}{
+					"main.tf": nil,
+				},
+			})
+			Ok(t, os.WriteFile(filepath.Join(tmpDir, valid.DefaultAtlantisFile), []byte(yamlCfg), 0600))
+
+			workingDir := mocks.NewMockWorkingDir()
+			When(workingDir.Clone(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+				Any[string]())).ThenReturn(tmpDir, nil)
+			When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(tmpDir, nil)
+			vcsClient := vcsmocks.NewMockClient()
+			When(vcsClient.GetModifiedFiles(Any[logging.SimpleLogging](), Any[models.Repo](),
+				Any[models.PullRequest]())).ThenReturn([]string{"fast/main.tf", "slow/main.tf", "other/main.tf"}, nil)
+
+			logger := logging.NewNoopLogger(t)
+			scope := metricstest.NewLoggingScope(t, logger, "atlantis")
+			userConfig := defaultUserConfig
+			builder := events.NewProjectCommandBuilder(
+				false,
+				&config.ParserValidator{},
+				&events.DefaultProjectFinder{},
+				vcsClient,
+				workingDir,
+				events.NewDefaultWorkingDirLocker(),
+				valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}),
+				&events.DefaultPendingPlanFinder{},
+				&events.CommentParser{ExecutableName: "atlantis"},
+				userConfig.SkipCloneNoChanges,
+				userConfig.EnableRegExpCmd,
+				userConfig.EnableAutoMerge,
+				userConfig.EnableParallelPlan,
+				userConfig.EnableParallelApply,
+				userConfig.AutoDetectModuleFiles,
+				userConfig.AutoplanFileList,
+				userConfig.RestrictFileList,
+				userConfig.SilenceNoProjects,
+				userConfig.IncludeGitUntrackedFiles,
+				userConfig.AutoDiscoverMode,
+				"",
+				scope,
+				tfclientmocks.NewMockClient(),
+			)
+
+			ctx := &command.Context{
+				PullRequestStatus: models.PullReqStatus{
+					MergeableStatus: models.MergeableStatus{IsMergeable: true},
+				},
+				Log:   logger,
+				Scope: scope,
+			}
+			ctxs, err := builder.BuildPlanCommands(ctx, c.cmd)
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+
+			var projects []string
+			for _, projCtx := range ctxs {
+				projects = append(projects, projCtx.ProjectName)
+			}
+			sort.Strings(projects)
+			Equals(t, c.expProjects, projects)
+		})
+	}
+}
+
+func TestDefaultProjectCommandBuilder_BuildPlanCommands_Failed(t *testing.T) {
+	yamlCfg := `version: 3
+projects:
+- name: fast
+  dir: fast
+- name: slow
+  dir: slow
+- name: other
+  dir: other
+`
+	headStatus := &models.PullStatus{
+		Pull: models.PullRequest{HeadCommit: "head"},
+		Projects: []models.ProjectStatus{
+			{ProjectName: "fast", RepoRelDir: "fast", Workspace: "default", Status: models.PlannedPlanStatus},
+			{ProjectName: "slow", RepoRelDir: "slow", Workspace: "default", Status: models.ErroredPlanStatus},
+			{ProjectName: "other", RepoRelDir: "other", Workspace: "default", Status: models.ErroredApplyStatus},
+		},
+	}
+	cases := []struct {
+		description string
+		cmd         *events.CommentCommand
+		pullStatus  *models.PullStatus
+		expProjects []string
+	}{
+		{
+			description: "only failed plans",
+			cmd:         &events.CommentCommand{Name: command.Plan, Failed: true},
+			pullStatus:  headStatus,
+			expProjects: []string{"slow"},
+		},
+		{
+			description: "projects that didn't fail can be excluded",
+			cmd:         &events.CommentCommand{Name: command.Plan, Failed: true, ExcludeProjectNames: []string{"fast"}},
+			pullStatus:  headStatus,
+			expProjects: []string{"slow"},
+		},
+		{
+			description: "failed plan excluded",
+			cmd:         &events.CommentCommand{Name: command.Plan, Failed: true, ExcludeProjectNames: []string{"slow"}},
+			pullStatus:  headStatus,
+			expProjects: nil,
+		},
+		{
+			description: "failures of a previous commit",
+			cmd:         &events.CommentCommand{Name: command.Plan, Failed: true},
+			pullStatus: &models.PullStatus{
+				Pull:     models.PullRequest{HeadCommit: "previous"},
+				Projects: headStatus.Projects,
+			},
+			expProjects: nil,
+		},
+		{
+			description: "no results",
+			cmd:         &events.CommentCommand{Name: command.Plan, Failed: true},
+			pullStatus:  nil,
+			expProjects: nil,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpDir := DirStructure(t, map[string]interface{}{
+				"fast": map[string]interface{}{
+					"main.tf": nil,
+				},
+				"slow": map[string]interface{}{
+					"main.tf": nil,
+				},
+				"other": map[string]interface{}{
+					"main.tf": nil,
+				},
+			})
+			Ok(t, os.WriteFile(filepath.Join(tmpDir, valid.DefaultAtlantisFile), []byte(yamlCfg), 0600))
+
+			workingDir := mocks.NewMockWorkingDir()
+			When(workingDir.Clone(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+				Any[string]())).ThenReturn(tmpDir, nil)
+			When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(tmpDir, nil)
+			vcsClient := vcsmocks.NewMockClient()
+			When(vcsClient.GetModifiedFiles(Any[logging.SimpleLogging](), Any[models.Repo](),
+				Any[models.PullRequest]())).ThenReturn([]string{"fast/main.tf", "slow/main.tf", "other/main.tf"}, nil)
+
+			logger := logging.NewNoopLogger(t)
+			scope := metricstest.NewLoggingScope(t, logger, "atlantis")
+			userConfig := defaultUserConfig
+			builder := events.NewProjectCommandBuilder(
+				false,
+				&config.ParserValidator{},
+				&events.DefaultProjectFinder{},
+				vcsClient,
+				workingDir,
+				events.NewDefaultWorkingDirLocker(),
+				valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}),
+				&events.DefaultPendingPlanFinder{},
+				&events.CommentParser{ExecutableName: "atlantis"},
+				userConfig.SkipCloneNoChanges,
+				userConfig.EnableRegExpCmd,
+				userConfig.EnableAutoMerge,
+				userConfig.EnableParallelPlan,
+				userConfig.EnableParallelApply,
+				userConfig.AutoDetectModuleFiles,
+				userConfig.AutoplanFileList,
+				userConfig.RestrictFileList,
+				userConfig.SilenceNoProjects,
+				userConfig.IncludeGitUntrackedFiles,
+				userConfig.AutoDiscoverMode,
+				"",
+				scope,
+				tfclientmocks.NewMockClient(),
+			)
+
+			ctx := &command.Context{
+				Pull:       models.PullRequest{HeadCommit: "head"},
+				PullStatus: c.pullStatus,
+				PullRequestStatus: models.PullReqStatus{
+					MergeableStatus: models.MergeableStatus{IsMergeable: true},
+				},
+				Log:   logger,
+				Scope: scope,
+			}
+			ctxs, err := builder.BuildPlanCommands(ctx, c.cmd)
+			Ok(t, err)
+
+			var projects []string
+			for _, projCtx := range ctxs {
+				projects = append(projects, projCtx.ProjectName)
+			}
+			sort.Strings(projects)
+			Equals(t, c.expProjects, projects)
+		})
+	}
+}
+
+func TestDefaultProjectCommandBuilder_BuildPlanCommands_Since(t *testing.T) {
+	yamlCfg := `version: 3
+projects:
+- name: fast
+  dir: fast
+- name: slow
+  dir: slow
+- name: other
+  dir: other
+`
+	cases := []struct {
+		description string
+		cmd         *events.CommentCommand
+		expProjects []string
+		expErr      string
+	}{
+		{
+			description: "pull request files",
+			cmd:         &events.CommentCommand{Name: command.Plan},
+			expProjects: []string{"fast", "other", "slow"},
+		},
+		{
+			description: "files changed since ref",
+			cmd:         &events.CommentCommand{Name: command.Plan, SinceRef: "origin/main"},
+			expProjects: []string{"fast"},
+		},
+		{
+			description: "invalid ref",
+			cmd:         &events.CommentCommand{Name: command.Plan, SinceRef: "origin/missing"},
+			expErr:      `the ref "origin/missing" doesn't exist in the clone of this pull request`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpDir := DirStructure(t, map[string]interface{}{
+				"fast": map[string]interface{}{
+					"main.tf": nil,
+				},
+				"slow": map[string]interface{}{
+					"main.tf": nil,
+				},
+				"other": map[string]interface{}{
+					"main.tf": nil,
+				},
+			})
+			Ok(t, os.WriteFile(filepath.Join(tmpDir, valid.DefaultAtlantisFile), []byte(yamlCfg), 0600))
+
+			workingDir := mocks.NewMockWorkingDir()
+			When(workingDir.Clone(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+				Any[string]())).ThenReturn(tmpDir, nil)
+			When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(tmpDir, nil)
+			When(workingDir.GetGitChangedFilesSince(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+				Any[string](), Eq("origin/main"))).ThenReturn([]string{"fast/main.tf"}, nil)
+			When(workingDir.GetGitChangedFilesSince(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+				Any[string](), Eq("origin/missing"))).ThenReturn(nil, errors.New(`the ref "origin/missing" doesn't exist in the clone of this pull request`))
+			vcsClient := vcsmocks.NewMockClient()
+			When(vcsClient.GetModifiedFiles(Any[logging.SimpleLogging](), Any[models.Repo](),
+				Any[models.PullRequest]())).ThenReturn([]string{"fast/main.tf", "slow/main.tf", "other/main.tf"}, nil)
+
+			logger := logging.NewNoopLogger(t)
+			scope := metricstest.NewLoggingScope(t, logger, "atlantis")
+			userConfig := defaultUserConfig
+			builder := events.NewProjectCommandBuilder(
+				false,
+				&config.ParserValidator{},
+				&events.DefaultProjectFinder{},
+				vcsClient,
+				workingDir,
+				events.NewDefaultWorkingDirLocker(),
+				valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}),
+				&events.DefaultPendingPlanFinder{},
+				&events.CommentParser{ExecutableName: "atlantis"},
+				userConfig.SkipCloneNoChanges,
+				userConfig.EnableRegExpCmd,
+				userConfig.EnableAutoMerge,
+				userConfig.EnableParallelPlan,
+				userConfig.EnableParallelApply,
+				userConfig.AutoDetectModuleFiles,
+				userConfig.AutoplanFileList,
+				userConfig.RestrictFileList,
+				userConfig.SilenceNoProjects,
+				userConfig.IncludeGitUntrackedFiles,
+				userConfig.AutoDiscoverMode,
+				"",
+				scope,
+				tfclientmocks.NewMockClient(),
+			)
+
+			ctx := &command.Context{
+				PullRequestStatus: models.PullReqStatus{
+					MergeableStatus: models.MergeableStatus{IsMergeable: true},
+				},
+				Log:   logger,
+				Scope: scope,
+			}
+			ctxs, err := builder.BuildPlanCommands(ctx, c.cmd)
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+
+			var projects []string
+			for _, projCtx := range ctxs {
+				projects = append(projects, projCtx.ProjectName)
+			}
+			sort.Strings(projects)
+			Equals(t, c.expProjects, projects)
+		})
+	}
+}
+
+func TestDefaultProjectCommandBuilder_BuildPlanCommands_RemoteRepoCfg(t *testing.T) {
+	remoteCfg := `version: 3
+projects:
+- name: remote
+  dir: fast
+`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Write([]byte(remoteCfg)) // nolint: errcheck
+	}))
+	defer server.Close()
+
+	cases := []struct {
+		description string
+		repoCfg     string
+		cmd         *events.CommentCommand
+		expProjects []string
+	}{
+		{
+			description: "remote config",
+			cmd:         &events.CommentCommand{Name: command.Plan},
+			expProjects: []string{"remote"},
+		},
+		{
+			description: "remote config with project name",
+			cmd:         &events.CommentCommand{Name: command.Plan, ProjectName: "remote"},
+			expProjects: []string{"remote"},
+		},
+		{
+			description: "in-repo config takes precedence",
+			repoCfg: `version: 3
+projects:
+- name: local
+  dir: slow
+`,
+			cmd:         &events.CommentCommand{Name: command.Plan},
+			expProjects: []string{"local"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpDir := DirStructure(t, map[string]interface{}{
+				"fast": map[string]interface{}{
+					"main.tf": nil,
+				},
+				"slow": map[string]interface{}{
+					"main.tf": nil,
+				},
+			})
+			if c.repoCfg != "" {
+				Ok(t, os.WriteFile(filepath.Join(tmpDir, valid.DefaultAtlantisFile), []byte(c.repoCfg), 0600))
+			}
+
+			workingDir := mocks.NewMockWorkingDir()
+			When(workingDir.Clone(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+				Any[string]())).ThenReturn(tmpDir, nil)
+			When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(tmpDir, nil)
+			vcsClient := vcsmocks.NewMockClient()
+			When(vcsClient.GetModifiedFiles(Any[logging.SimpleLogging](), Any[models.Repo](),
+				Any[models.PullRequest]())).ThenReturn([]string{"fast/main.tf", "slow/main.tf"}, nil)
+
+			globalCfg := valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{})
+			globalCfg.Repos[0].RepoConfigURL = server.URL + "/atlantis.yaml"
+
+			logger := logging.NewNoopLogger(t)
+			scope := metricstest.NewLoggingScope(t, logger, "atlantis")
+			userConfig := defaultUserConfig
+			builder := events.NewProjectCommandBuilder(
+				false,
+				&config.ParserValidator{},
+				&events.DefaultProjectFinder{},
+				vcsClient,
+				workingDir,
+				events.NewDefaultWorkingDirLocker(),
+				globalCfg,
+				&events.DefaultPendingPlanFinder{},
+				&events.CommentParser{ExecutableName: "atlantis"},
+				userConfig.SkipCloneNoChanges,
+				userConfig.EnableRegExpCmd,
+				userConfig.EnableAutoMerge,
+				userConfig.EnableParallelPlan,
+				userConfig.EnableParallelApply,
+				userConfig.AutoDetectModuleFiles,
+				userConfig.AutoplanFileList,
+				userConfig.RestrictFileList,
+				userConfig.SilenceNoProjects,
+				userConfig.IncludeGitUntrackedFiles,
+				userConfig.AutoDiscoverMode,
+				"",
+				scope,
+				tfclientmocks.NewMockClient(),
+			)
+
+			ctx := &command.Context{
+				PullRequestStatus: models.PullReqStatus{
+					MergeableStatus: models.MergeableStatus{IsMergeable: true},
+				},
+				Log:   logger,
+				Scope: scope,
+			}
+			ctxs, err := builder.BuildPlanCommands(ctx, c.cmd)
+			Ok(t, err)
+
+			var projects []string
+			for _, projCtx := range ctxs {
+				projects = append(projects, projCtx.ProjectName)
+			}
+			Equals(t, c.expProjects, projects)
+		})
+	}
+}
+
+func TestDefaultProjectCommandBuilder_BuildPlanCommands_Workflow(t *testing.T) {
+	yamlCfg := `version: 3
+projects:
+- name: fast
+  dir: fast
+`
+	experimental := valid.Workflow{
+		Name: "experimental",
+		Plan: valid.Stage{Steps: []valid.Step{{StepName: "run", RunCommand: "echo experimental"}}},
+	}
+	cases := []struct {
+		description string
+		cmd         *events.CommentCommand
+		expSteps    []valid.Step
+		expErr      string
+	}{
+		{
+			description: "configured workflow",
+			cmd:         &events.CommentCommand{Name: command.Plan},
+			expSteps:    valid.DefaultPlanStage.Steps,
+		},
+		{
+			description: "selected workflow",
+			cmd:         &events.CommentCommand{Name: command.Plan, Workflow: "experimental"},
+			expSteps:    experimental.Plan.Steps,
+		},
+		{
+			description: "selected workflow for a project",
+			cmd:         &events.CommentCommand{Name: command.Plan, ProjectName: "fast", Workflow: "experimental"},
+			expSteps:    experimental.Plan.Steps,
+		},
+		{
+			description: "unknown workflow",
+			cmd:         &events.CommentCommand{Name: command.Plan, Workflow: "missing"},
+			expErr:      `workflow "missing" is not defined, the defined workflows are: default, experimental`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpDir := DirStructure(t, map[string]interface{}{
+				"fast": map[string]interface{}{
+					"main.tf": nil,
+				},
+			})
+			Ok(t, os.WriteFile(filepath.Join(tmpDir, valid.DefaultAtlantisFile), []byte(yamlCfg), 0600))
+
+			workingDir := mocks.NewMockWorkingDir()
+			When(workingDir.Clone(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+				Any[string]())).ThenReturn(tmpDir, nil)
+			When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(tmpDir, nil)
+			vcsClient := vcsmocks.NewMockClient()
+			When(vcsClient.GetModifiedFiles(Any[logging.SimpleLogging](), Any[models.Repo](),
+				Any[models.PullRequest]())).ThenReturn([]string{"fast/main.tf"}, nil)
+
+			globalCfg := valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{AllowAllRepoSettings: true})
+			globalCfg.Workflows["experimental"] = experimental
+
+			logger := logging.NewNoopLogger(t)
+			scope := metricstest.NewLoggingScope(t, logger, "atlantis")
+			userConfig := defaultUserConfig
+			builder := events.NewProjectCommandBuilder(
+				false,
+				&config.ParserValidator{},
+				&events.DefaultProjectFinder{},
+				vcsClient,
+				workingDir,
+				events.NewDefaultWorkingDirLocker(),
+				globalCfg,
+				&events.DefaultPendingPlanFinder{},
+				&events.CommentParser{ExecutableName: "atlantis"},
+				userConfig.SkipCloneNoChanges,
+				userConfig.EnableRegExpCmd,
+				userConfig.EnableAutoMerge,
+				userConfig.EnableParallelPlan,
+				userConfig.EnableParallelApply,
+				userConfig.AutoDetectModuleFiles,
+				userConfig.AutoplanFileList,
+				userConfig.RestrictFileList,
+				userConfig.SilenceNoProjects,
+				userConfig.IncludeGitUntrackedFiles,
+				userConfig.AutoDiscoverMode,
+				"",
+				scope,
+				tfclientmocks.NewMockClient(),
+			)
+
+			ctx := &command.Context{
+				PullRequestStatus: models.PullReqStatus{
+					MergeableStatus: models.MergeableStatus{IsMergeable: true},
+				},
+				Log:   logger,
+				Scope: scope,
+			}
+			ctxs, err := builder.BuildPlanCommands(ctx, c.cmd)
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, 1, len(ctxs))
+			Equals(t, c.expSteps, ctxs[0].Steps)
+		})
+	}
+}
+
+func TestDefaultProjectCommandBuilder_BuildPlanCommands_WorkspacePattern(t *testing.T) {
+	yamlCfg := `version: 3
+projects:
+- name: network-prod-a
+  dir: network
+  workspace: prod-a
+- name: network-prod-b
+  dir: network
+  workspace: prod-b
+- name: network-staging
+  dir: network
+  workspace: staging
+- name: app-prod-a
+  dir: app
+  workspace: prod-a
+`
+	cases := []struct {
+		description string
+		cmd         *events.CommentCommand
+		expProjects []string
+		expErr      string
+	}{
+		{
+			description: "all projects with a matching workspace",
+			cmd:         &events.CommentCommand{Name: command.Plan, WorkspacePattern: "prod-*"},
+			expProjects: []string{"app-prod-a", "network-prod-a", "network-prod-b"},
+		},
+		{
+			description: "with dir",
+			cmd:         &events.CommentCommand{Name: command.Plan, RepoRelDir: "network", WorkspacePattern: "prod-*"},
+			expProjects: []string{"network-prod-a", "network-prod-b"},
+		},
+		{
+			description: "with project",
+			cmd:         &events.CommentCommand{Name: command.Plan, ProjectName: "network-staging", WorkspacePattern: "prod-*"},
+			expErr:      `no projects have a workspace matching "prod-*", the workspaces of the projects to plan are: staging`,
+		},
+		{
+			description: "with excluded project",
+			cmd:         &events.CommentCommand{Name: command.Plan, WorkspacePattern: "prod-?", ExcludeProjectNames: []string{"app-prod-a"}},
+			expProjects: []string{"network-prod-a", "network-prod-b"},
+		},
+		{
+			description: "no matching workspace",
+			cmd:         &events.CommentCommand{Name: command.Plan, WorkspacePattern: "dev-*"},
+			expErr:      `no projects have a workspace matching "dev-*", the workspaces of the projects to plan are: prod-a, prod-b, staging`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			tmpDir := DirStructure(t, map[string]interface{}{
+				"network": map[string]interface{}{
+					"main.tf": nil,
+				},
+				"app": map[string]interface{}{
+					"main.tf": nil,
+				},
+			})
+			Ok(t, os.WriteFile(filepath.Join(tmpDir, valid.DefaultAtlantisFile), []byte(yamlCfg), 0600))
+
+			workingDir := mocks.NewMockWorkingDir()
+			When(workingDir.Clone(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+				Any[string]())).ThenReturn(tmpDir, nil)
+			When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(tmpDir, nil)
+			vcsClient := vcsmocks.NewMockClient()
+			When(vcsClient.GetModifiedFiles(Any[logging.SimpleLogging](), Any[models.Repo](),
+				Any[models.PullRequest]())).ThenReturn([]string{"network/main.tf", "app/main.tf"}, nil)
+
+			logger := logging.NewNoopLogger(t)
+			scope := metricstest.NewLoggingScope(t, logger, "atlantis")
+			userConfig := defaultUserConfig
+			builder := events.NewProjectCommandBuilder(
+				false,
+				&config.ParserValidator{},
+				&events.DefaultProjectFinder{},
+				vcsClient,
+				workingDir,
+				events.NewDefaultWorkingDirLocker(),
+				valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}),
+				&events.DefaultPendingPlanFinder{},
+				&events.CommentParser{ExecutableName: "atlantis"},
+				userConfig.SkipCloneNoChanges,
+				userConfig.EnableRegExpCmd,
+				userConfig.EnableAutoMerge,
+				userConfig.EnableParallelPlan,
+				userConfig.EnableParallelApply,
+				userConfig.AutoDetectModuleFiles,
+				userConfig.AutoplanFileList,
+				userConfig.RestrictFileList,
+				userConfig.SilenceNoProjects,
+				userConfig.IncludeGitUntrackedFiles,
+				userConfig.AutoDiscoverMode,
+				"",
+				scope,
+				tfclientmocks.NewMockClient(),
+			)
+
+			ctx := &command.Context{
+				PullRequestStatus: models.PullReqStatus{
+					MergeableStatus: models.MergeableStatus{IsMergeable: true},
+				},
+				Log:   logger,
+				Scope: scope,
+			}
+			ctxs, err := builder.BuildPlanCommands(ctx, c.cmd)
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+
+			var projects []string
+			for _, projCtx := range ctxs {
+				projects = append(projects, projCtx.ProjectName)
+			}
+			sort.Strings(projects)
+			Equals(t, c.expProjects, projects)
+		})
+	}
+}
+
+func TestDefaultProjectCommandBuilder_BuildPlanCommands_Destroy(t *testing.T) {
+	RegisterMockTestingT(t)
+	tmpDir := DirStructure(t, map[string]interface{}{
+		"network": map[string]interface{}{
+			"main.tf": nil,
+		},
+	})
+	Ok(t, os.WriteFile(filepath.Join(tmpDir, valid.DefaultAtlantisFile), []byte("version: 3\nprojects:\n- name: network\n  dir: network\n"), 0600))
+
+	workingDir := mocks.NewMockWorkingDir()
+	When(workingDir.Clone(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](),
+		Any[string]())).ThenReturn(tmpDir, nil)
+	When(workingDir.GetWorkingDir(Any[models.Repo](), Any[models.PullRequest](), Any[string]())).ThenReturn(tmpDir, nil)
+	vcsClient := vcsmocks.NewMockClient()
+	When(vcsClient.GetModifiedFiles(Any[logging.SimpleLogging](), Any[models.Repo](),
+		Any[models.PullRequest]())).ThenReturn([]string{"network/main.tf"}, nil)
+
+	logger := logging.NewNoopLogger(t)
+	scope := metricstest.NewLoggingScope(t, logger, "atlantis")
+	userConfig := defaultUserConfig
+	builder := events.NewProjectCommandBuilder(
+		false,
+		&config.ParserValidator{},
+		&events.DefaultProjectFinder{},
+		vcsClient,
+		workingDir,
+		events.NewDefaultWorkingDirLocker(),
+		valid.NewGlobalCfgFromArgs(valid.GlobalCfgArgs{}),
+		&events.DefaultPendingPlanFinder{},
+		&events.CommentParser{ExecutableName: "atlantis"},
+		userConfig.SkipCloneNoChanges,
+		userConfig.EnableRegExpCmd,
+		userConfig.EnableAutoMerge,
+		userConfig.EnableParallelPlan,
+		userConfig.EnableParallelApply,
+		userConfig.AutoDetectModuleFiles,
+		userConfig.AutoplanFileList,
+		userConfig.RestrictFileList,
+		userConfig.SilenceNoProjects,
+		userConfig.IncludeGitUntrackedFiles,
+		userConfig.AutoDiscoverMode,
+		"",
+		scope,
+		tfclientmocks.NewMockClient(),
+	)
+
+	ctx := &command.Context{
+		PullRequestStatus: models.PullReqStatus{
+			MergeableStatus: models.MergeableStatus{IsMergeable: true},
+		},
+		Log:   logger,
+		Scope: scope,
+	}
+	for _, cmd := range []*events.CommentCommand{
+		{Name: command.Plan, Destroy: true, Flags: []string{"-var=a"}},
+		{Name: command.Plan, ProjectName: "network", Destroy: true, Flags: []string{"-var=a"}},
+	} {
+		ctxs, err := builder.BuildPlanCommands(ctx, cmd)
+		Ok(t, err)
+		Equals(t, 1, len(ctxs))
+		Equals(t, true, ctxs[0].PlanDestroy)
+		Equals(t, "atlantis plan --destroy -p network -- -var=a", ctxs[0].RePlanCmd)
+	}
+
+	ctxs, err := builder.BuildPlanCommands(ctx, &events.CommentCommand{Name: command.Plan, RefreshOnly: true})
+	Ok(t, err)
+	Equals(t, 1, len(ctxs))
+	Equals(t, true, ctxs[0].PlanRefreshOnly)
+	Equals(t, false, ctxs[0].PlanDestroy)
+	Equals(t, "atlantis plan --refresh-only -p network", ctxs[0].RePlanCmd)
+
+	ctxs, err = builder.BuildPlanCommands(ctx, &events.CommentCommand{Name: command.Plan})
+	Ok(t, err)
+	Equals(t, false, ctxs[0].PlanDestroy)
+	Equals(t, false, ctxs[0].PlanRefreshOnly)
+	Equals(t, "atlantis plan -p network", ctxs[0].RePlanCmd)
+}
+
 // Test that extra comment args are escaped.
 func TestDefaultProjectCommandBuilder_EscapeArgs(t *testing.T) {
 	cases := []struct {
@@ -1467,6 +2503,7 @@ func TestDefaultProjectCommandBuilder_EscapeArgs(t *testing.T) {
 				userConfig.SilenceNoProjects,
 				userConfig.IncludeGitUntrackedFiles,
 				userConfig.AutoDiscoverMode,
+				"",
 				scope,
 				terraformClient,
 			)
@@ -1598,14 +2635,14 @@ projects:
 			}
 
 			terraformClient := tfclientmocks.NewMockClient()
-			When(terraformClient.DetectVersion(Any[logging.SimpleLogging](), Any[string]())).Then(func(params []Param) ReturnValues {
-				projectName := filepath.Base(params[1].(string))
+			When(terraformClient.DetectVersion(Any[logging.SimpleLogging](), Any[terraform.Distribution](), Any[string]())).Then(func(params []Param) ReturnValues {
+				projectName := filepath.Base(params[2].(string))
 				testVersion := testCase.Exp[projectName]
 				if testVersion != "" {
 					v, _ := version.NewVersion(testVersion)
-					return []ReturnValue{v}
+					return []ReturnValue{v, nil}
 				}
-				return nil
+				return []ReturnValue{nil, nil}
 			})
 
 			builder := events.NewProjectCommandBuilder(
@@ -1629,6 +2666,7 @@ projects:
 				userConfig.SilenceNoProjects,
 				userConfig.IncludeGitUntrackedFiles,
 				userConfig.AutoDiscoverMode,
+				"",
 				scope,
 				terraformClient,
 			)
@@ -1770,6 +2808,7 @@ projects:
 			userConfig.SilenceNoProjects,
 			c.IncludeGitUntrackedFiles,
 			userConfig.AutoDiscoverMode,
+			"",
 			scope,
 			terraformClient,
 		)
@@ -1854,6 +2893,7 @@ func TestDefaultProjectCommandBuilder_WithPolicyCheckEnabled_BuildAutoplanComman
 		userConfig.SilenceNoProjects,
 		userConfig.IncludeGitUntrackedFiles,
 		userConfig.AutoDiscoverMode,
+		"",
 		scope,
 		terraformClient,
 	)
@@ -1942,6 +2982,7 @@ func TestDefaultProjectCommandBuilder_BuildVersionCommand(t *testing.T) {
 		userConfig.SilenceNoProjects,
 		userConfig.IncludeGitUntrackedFiles,
 		userConfig.AutoDiscoverMode,
+		"",
 		scope,
 		terraformClient,
 	)
@@ -2072,6 +3113,7 @@ func TestDefaultProjectCommandBuilder_BuildPlanCommands_Single_With_RestrictFile
 				userConfig.SilenceNoProjects,
 				userConfig.IncludeGitUntrackedFiles,
 				userConfig.AutoDiscoverMode,
+				"",
 				scope,
 				terraformClient,
 			)
@@ -2183,6 +3225,7 @@ func TestDefaultProjectCommandBuilder_BuildPlanCommands_with_IncludeGitUntracked
 				userConfig.SilenceNoProjects,
 				userConfig.IncludeGitUntrackedFiles,
 				userConfig.AutoDiscoverMode,
+				"",
 				scope,
 				terraformClient,
 			)
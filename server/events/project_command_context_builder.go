@@ -4,10 +4,12 @@
 package events
 
 import (
+	"fmt"
 	"path/filepath"
 
 	"github.com/google/uuid"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/terraform"
 	"github.com/runatlantis/atlantis/server/core/terraform/tfclient"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
@@ -42,7 +44,7 @@ type ProjectCommandContextBuilder interface {
 		commentFlags []string,
 		repoDir string,
 		automerge, parallelApply, parallelPlan, verbose, abortOnExecutionOrderFail bool, terraformClient tfclient.Client,
-	) []command.ProjectContext
+	) ([]command.ProjectContext, error)
 }
 
 // CommandScopedStatsProjectCommandContextBuilder ensures that project command context contains a scoped stats
@@ -63,12 +65,15 @@ func (cb *CommandScopedStatsProjectCommandContextBuilder) BuildProjectContext(
 	repoDir string,
 	automerge, parallelApply, parallelPlan, verbose, abortOnExecutionOrderFail bool,
 	terraformClient tfclient.Client,
-) (projectCmds []command.ProjectContext) {
+) (projectCmds []command.ProjectContext, err error) {
 	cb.ProjectCounter.Inc(1)
 
-	cmds := cb.ProjectCommandContextBuilder.BuildProjectContext(
+	cmds, err := cb.ProjectCommandContextBuilder.BuildProjectContext(
 		ctx, cmdName, subCmdName, prjCfg, commentFlags, repoDir, automerge, parallelApply, parallelPlan, verbose, abortOnExecutionOrderFail, terraformClient,
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	projectCmds = []command.ProjectContext{}
 
@@ -97,26 +102,28 @@ func (cb *DefaultProjectCommandContextBuilder) BuildProjectContext(
 	repoDir string,
 	automerge, parallelApply, parallelPlan, verbose, abortOnExecutionOrderFail bool,
 	terraformClient tfclient.Client,
-) (projectCmds []command.ProjectContext) {
+) (projectCmds []command.ProjectContext, err error) {
 	ctx.Log.Debug("Building project command context for %s", cmdName)
 
-	var steps []valid.Step
+	var stage valid.Stage
 	switch cmdName {
 	case command.Plan:
-		steps = prjCfg.Workflow.Plan.Steps
+		stage = prjCfg.Workflow.Plan
 	case command.Apply:
-		steps = prjCfg.Workflow.Apply.Steps
+		stage = prjCfg.Workflow.Apply
 	case command.Version:
 		// Setting statically since there will only be one step
-		steps = []valid.Step{{
+		stage = valid.Stage{Steps: []valid.Step{{
 			StepName: "version",
-		}}
+		}}}
 	case command.Import:
-		steps = prjCfg.Workflow.Import.Steps
+		stage = prjCfg.Workflow.Import
+	case command.Drift:
+		stage = driftStage(prjCfg.Workflow.Plan)
 	case command.State:
 		switch subName {
 		case "rm":
-			steps = prjCfg.Workflow.StateRm.Steps
+			stage = prjCfg.Workflow.StateRm
 		default:
 			// comment_parser prevent invalid subcommand, so not need to handle this.
 			// if comes here, state_command_runner will respond on PR, so it's enough to do log only.
@@ -125,9 +132,11 @@ func (cb *DefaultProjectCommandContextBuilder) BuildProjectContext(
 	}
 
 	// If TerraformVersion not defined in config file look for a
-	// terraform.require_version block.
+	// .terraform-version file or a terraform.require_version block.
 	if prjCfg.TerraformVersion == nil {
-		prjCfg.TerraformVersion = terraformClient.DetectVersion(ctx.Log, filepath.Join(repoDir, prjCfg.RepoRelDir))
+		if prjCfg.TerraformVersion, err = terraformClient.DetectVersion(ctx.Log, projectDistribution(prjCfg), filepath.Join(repoDir, prjCfg.RepoRelDir)); err != nil {
+			return nil, fmt.Errorf("detecting the terraform version of project in dir %q: %w", prjCfg.RepoRelDir, err)
+		}
 	}
 
 	projectCmdContext := newProjectCommandContext(
@@ -137,7 +146,7 @@ func (cb *DefaultProjectCommandContextBuilder) BuildProjectContext(
 		cb.CommentBuilder.BuildApprovePoliciesComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name),
 		cb.CommentBuilder.BuildPlanComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name, commentFlags),
 		prjCfg,
-		steps,
+		stage,
 		prjCfg.PolicySets,
 		escapeArgs(commentFlags),
 		automerge,
@@ -153,7 +162,7 @@ func (cb *DefaultProjectCommandContextBuilder) BuildProjectContext(
 
 	projectCmds = append(projectCmds, projectCmdContext)
 
-	return
+	return projectCmds, nil
 }
 
 type PolicyCheckProjectCommandContextBuilder struct {
@@ -170,7 +179,7 @@ func (cb *PolicyCheckProjectCommandContextBuilder) BuildProjectContext(
 	repoDir string,
 	automerge, parallelApply, parallelPlan, verbose, abortOnExecutionOrderFail bool,
 	terraformClient tfclient.Client,
-) (projectCmds []command.ProjectContext) {
+) (projectCmds []command.ProjectContext, err error) {
 	if prjCfg.PolicyCheck {
 		ctx.Log.Debug("PolicyChecks are enabled")
 	} else {
@@ -179,12 +188,14 @@ func (cb *PolicyCheckProjectCommandContextBuilder) BuildProjectContext(
 	}
 
 	// If TerraformVersion not defined in config file look for a
-	// terraform.require_version block.
+	// .terraform-version file or a terraform.require_version block.
 	if prjCfg.TerraformVersion == nil {
-		prjCfg.TerraformVersion = terraformClient.DetectVersion(ctx.Log, filepath.Join(repoDir, prjCfg.RepoRelDir))
+		if prjCfg.TerraformVersion, err = terraformClient.DetectVersion(ctx.Log, projectDistribution(prjCfg), filepath.Join(repoDir, prjCfg.RepoRelDir)); err != nil {
+			return nil, fmt.Errorf("detecting the terraform version of project in dir %q: %w", prjCfg.RepoRelDir, err)
+		}
 	}
 
-	projectCmds = cb.ProjectCommandContextBuilder.BuildProjectContext(
+	projectCmds, err = cb.ProjectCommandContextBuilder.BuildProjectContext(
 		ctx,
 		cmdName,
 		subCmdName,
@@ -198,10 +209,13 @@ func (cb *PolicyCheckProjectCommandContextBuilder) BuildProjectContext(
 		abortOnExecutionOrderFail,
 		terraformClient,
 	)
+	if err != nil {
+		return nil, err
+	}
 
 	if cmdName == command.Plan && prjCfg.PolicyCheck {
 		ctx.Log.Debug("Building project command context for %s", command.PolicyCheck)
-		steps := prjCfg.Workflow.PolicyCheck.Steps
+		stage := prjCfg.Workflow.PolicyCheck
 
 		projectCmds = append(projectCmds, newProjectCommandContext(
 			ctx,
@@ -210,7 +224,7 @@ func (cb *PolicyCheckProjectCommandContextBuilder) BuildProjectContext(
 			cb.CommentBuilder.BuildApprovePoliciesComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name),
 			cb.CommentBuilder.BuildPlanComment(prjCfg.RepoRelDir, prjCfg.Workspace, prjCfg.Name, commentFlags),
 			prjCfg,
-			steps,
+			stage,
 			prjCfg.PolicySets,
 			escapeArgs(commentFlags),
 			automerge,
@@ -225,7 +239,23 @@ func (cb *PolicyCheckProjectCommandContextBuilder) BuildProjectContext(
 		))
 	}
 
-	return
+	return projectCmds, nil
+}
+
+// driftStage returns the steps of plan up to its first plan step, which is
+// replaced with a drift step. The steps after it are dropped since they
+// usually use the plan file, which drift doesn't save.
+func driftStage(plan valid.Stage) valid.Stage {
+	stage := valid.Stage{Retry: plan.Retry}
+	for _, step := range plan.Steps {
+		if step.StepName == "plan" {
+			step.StepName = "drift"
+			stage.Steps = append(stage.Steps, step)
+			break
+		}
+		stage.Steps = append(stage.Steps, step)
+	}
+	return stage
 }
 
 // newProjectCommandContext is a initializer method that handles constructing the
@@ -236,7 +266,7 @@ func newProjectCommandContext(ctx *command.Context,
 	approvePoliciesCmd string,
 	planCmd string,
 	projCfg valid.MergedProjectCfg,
-	steps []valid.Step,
+	stage valid.Stage,
 	policySets valid.PolicySets,
 	escapedCommentArgs []string,
 	automergeEnabled bool,
@@ -250,8 +280,13 @@ func newProjectCommandContext(ctx *command.Context,
 	teamAllowlistChecker command.TeamAllowlistChecker,
 ) command.ProjectContext {
 
+	if projCfg.Automerge != nil {
+		automergeEnabled = *projCfg.Automerge
+	}
+
 	var projectPlanStatus models.ProjectPlanStatus
 	var projectPolicyStatus []models.PolicySetStatus
+	var projectPlanStats *models.PlanSuccessStats
 
 	if ctx.PullStatus != nil {
 		for _, project := range ctx.PullStatus.Projects {
@@ -260,12 +295,14 @@ func newProjectCommandContext(ctx *command.Context,
 			if projCfg.Name == "" && project.RepoRelDir == projCfg.RepoRelDir {
 				projectPlanStatus = project.Status
 				projectPolicyStatus = project.PolicyStatus
+				projectPlanStats = project.PlanStats
 				break
 			}
 
 			if projCfg.Name != "" && project.ProjectName == projCfg.Name {
 				projectPlanStatus = project.Status
 				projectPolicyStatus = project.PolicyStatus
+				projectPlanStats = project.PlanStats
 				break
 			}
 		}
@@ -278,6 +315,7 @@ func newProjectCommandContext(ctx *command.Context,
 		BaseRepo:                   ctx.Pull.BaseRepo,
 		EscapedCommentArgs:         escapedCommentArgs,
 		AutomergeEnabled:           automergeEnabled,
+		AutomergeMethod:            projCfg.AutomergeMethod,
 		DeleteSourceBranchOnMerge:  projCfg.DeleteSourceBranchOnMerge,
 		RepoLocksMode:              projCfg.RepoLocks.Mode,
 		CustomPolicyCheck:          projCfg.CustomPolicyCheck,
@@ -286,16 +324,19 @@ func newProjectCommandContext(ctx *command.Context,
 		ParallelPolicyCheckEnabled: parallelPlanEnabled,
 		DependsOn:                  projCfg.DependsOn,
 		AutoplanEnabled:            projCfg.AutoplanEnabled,
-		Steps:                      steps,
+		Steps:                      stage.Steps,
+		StageRetry:                 stage.Retry,
 		HeadRepo:                   ctx.HeadRepo,
-		Log:                        ctx.Log,
+		Log:                        ctx.Log.With("project", projCfg.Name, "dir", projCfg.RepoRelDir, "workspace", projCfg.Workspace),
 		Scope:                      scope,
 		ProjectPlanStatus:          projectPlanStatus,
 		ProjectPolicyStatus:        projectPolicyStatus,
+		ProjectPlanStats:           projectPlanStats,
 		Pull:                       ctx.Pull,
 		ProjectName:                projCfg.Name,
 		PlanRequirements:           projCfg.PlanRequirements,
 		ApplyRequirements:          projCfg.ApplyRequirements,
+		CustomApplyRequirements:    projCfg.CustomApplyRequirements,
 		ImportRequirements:         projCfg.ImportRequirements,
 		RePlanCmd:                  planCmd,
 		RepoRelDir:                 projCfg.RepoRelDir,
@@ -312,10 +353,34 @@ func newProjectCommandContext(ctx *command.Context,
 		PullStatus:                 pullStatus,
 		JobID:                      uuid.New().String(),
 		ExecutionOrderGroup:        projCfg.ExecutionOrderGroup,
+		ConfigOrder:                projCfg.ConfigOrder,
 		AbortOnExecutionOrderFail:  abortOnExecutionOrderFail,
 		SilencePRComments:          projCfg.SilencePRComments,
+		QuietPolicyChecks:          projCfg.QuietPolicyChecks,
+		NoChangesMessage:           projCfg.NoChangesMessage,
+		Labels:                     projCfg.Labels,
+		HidePrevPlanComments:       projCfg.HidePrevPlanComments,
+		PlanFilePath:               projCfg.PlanFilePath,
+		InitUpgrade:                projCfg.InitUpgrade,
+		PushLockFile:               projCfg.PushLockFile,
+		DestroyThreshold:           projCfg.DestroyThreshold,
+		DestroyThresholdApproval:   projCfg.DestroyThresholdApproval,
+		WorkspaceTfvars:            projCfg.WorkspaceTfvars,
+		StepOutputDenylist:         projCfg.StepOutputDenylist,
+		StepOutputMasks:            projCfg.StepOutputMasks,
+		AllowedRunCommands:         projCfg.AllowedRunCommands,
 		TeamAllowlistChecker:       teamAllowlistChecker,
+		Canceled:                   ctx.Canceled,
+	}
+}
+
+// projectDistribution returns the distribution set for the project or nil if
+// it uses the server's default distribution.
+func projectDistribution(prjCfg valid.MergedProjectCfg) terraform.Distribution {
+	if prjCfg.TerraformDistribution == nil {
+		return nil
 	}
+	return terraform.NewDistribution(*prjCfg.TerraformDistribution)
 }
 
 func escapeArgs(args []string) []string {
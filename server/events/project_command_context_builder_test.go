@@ -4,10 +4,13 @@
 package events_test
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/hashicorp/go-version"
 	. "github.com/petergtz/pegomock/v4"
 	"github.com/runatlantis/atlantis/server/core/config/valid"
+	"github.com/runatlantis/atlantis/server/core/terraform"
 	tfclientmocks "github.com/runatlantis/atlantis/server/core/terraform/tfclient/mocks"
 	"github.com/runatlantis/atlantis/server/events"
 	"github.com/runatlantis/atlantis/server/events/command"
@@ -64,7 +67,8 @@ func TestProjectCommandContextBuilder_PullStatus(t *testing.T) {
 			},
 		}
 
-		result := subject.BuildProjectContext(commandCtx, command.Plan, "", projCfg, []string{}, "some/dir", false, false, false, false, false, terraformClient)
+		result, err := subject.BuildProjectContext(commandCtx, command.Plan, "", projCfg, []string{}, "some/dir", false, false, false, false, false, terraformClient)
+		assert.NoError(t, err)
 		assert.Equal(t, models.ErroredPolicyCheckStatus, result[0].ProjectPlanStatus)
 	})
 
@@ -83,7 +87,8 @@ func TestProjectCommandContextBuilder_PullStatus(t *testing.T) {
 			},
 		}
 
-		result := subject.BuildProjectContext(commandCtx, command.Plan, "", projCfg, []string{}, "some/dir", false, false, false, false, false, terraformClient)
+		result, err := subject.BuildProjectContext(commandCtx, command.Plan, "", projCfg, []string{}, "some/dir", false, false, false, false, false, terraformClient)
+		assert.NoError(t, err)
 
 		assert.Equal(t, models.ErroredPolicyCheckStatus, result[0].ProjectPlanStatus)
 	})
@@ -103,7 +108,8 @@ func TestProjectCommandContextBuilder_PullStatus(t *testing.T) {
 			},
 		}
 
-		result := subject.BuildProjectContext(commandCtx, command.Plan, "", projCfg, []string{}, "some/dir", false, true, false, false, false, terraformClient)
+		result, err := subject.BuildProjectContext(commandCtx, command.Plan, "", projCfg, []string{}, "some/dir", false, true, false, false, false, terraformClient)
+		assert.NoError(t, err)
 
 		assert.True(t, result[0].ParallelApplyEnabled)
 		assert.False(t, result[0].ParallelPlanEnabled)
@@ -124,8 +130,69 @@ func TestProjectCommandContextBuilder_PullStatus(t *testing.T) {
 			},
 		}
 
-		result := subject.BuildProjectContext(commandCtx, command.Plan, "", projCfg, []string{}, "some/dir", false, false, false, false, true, terraformClient)
+		result, err := subject.BuildProjectContext(commandCtx, command.Plan, "", projCfg, []string{}, "some/dir", false, false, false, false, true, terraformClient)
+		assert.NoError(t, err)
 
 		assert.True(t, result[0].AbortOnExecutionOrderFail)
 	})
+
+	t.Run("with an opentofu project", func(t *testing.T) {
+		RegisterMockTestingT(t)
+		distribution := "opentofu"
+		projCfg.TerraformDistribution = &distribution
+		terraformClient := tfclientmocks.NewMockClient()
+
+		result, err := subject.BuildProjectContext(commandCtx, command.Plan, "", projCfg, []string{}, "some/dir", false, false, false, false, false, terraformClient)
+		assert.NoError(t, err)
+
+		// The required_version is resolved against OpenTofu's releases.
+		_, detectDistribution, _ := terraformClient.VerifyWasCalledOnce().DetectVersion(Any[logging.SimpleLogging](), Any[terraform.Distribution](), Any[string]()).GetCapturedArguments()
+		assert.Equal(t, "tofu", detectDistribution.BinName())
+		assert.Equal(t, &distribution, result[0].TerraformDistribution)
+	})
+
+	t.Run("when the terraform version can't be detected", func(t *testing.T) {
+		RegisterMockTestingT(t)
+		projCfg.TerraformDistribution = nil
+		terraformClient := tfclientmocks.NewMockClient()
+		When(terraformClient.DetectVersion(Any[logging.SimpleLogging](), Any[terraform.Distribution](), Any[string]())).
+			ThenReturn(nil, errors.New("version 1.5.7 in .terraform-version doesn't satisfy the required_version \"= 1.4.0\" of the Terraform configuration"))
+
+		_, err := subject.BuildProjectContext(commandCtx, command.Plan, "", projCfg, []string{}, "some/dir", false, false, false, false, false, terraformClient)
+
+		assert.EqualError(t, err, `detecting the terraform version of project in dir "dir1": version 1.5.7 in .terraform-version doesn't satisfy the required_version "= 1.4.0" of the Terraform configuration`)
+	})
+}
+
+func TestProjectCommandContextBuilder_Drift(t *testing.T) {
+	RegisterMockTestingT(t)
+	subject := events.DefaultProjectCommandContextBuilder{
+		CommentBuilder: mocks.NewMockCommentBuilder(),
+	}
+	projCfg := valid.MergedProjectCfg{
+		RepoRelDir: ".",
+		Workspace:  "default",
+		Workflow: valid.Workflow{
+			Name: valid.DefaultWorkflowName,
+			Plan: valid.Stage{Steps: []valid.Step{
+				{StepName: "init"},
+				{StepName: "plan", ExtraArgs: []string{"-lock=false"}},
+				{StepName: "show"},
+			}},
+		},
+	}
+	commandCtx := &command.Context{Log: logging.NewNoopLogger(t)}
+	tfVersion, _ := version.NewVersion("1.5.0")
+	projCfg.TerraformVersion = tfVersion
+
+	result, err := subject.BuildProjectContext(commandCtx, command.Drift, "", projCfg, []string{}, "some/dir", false, false, false, false, false, tfclientmocks.NewMockClient())
+	assert.NoError(t, err)
+	assert.Len(t, result, 1)
+	// The plan step is replaced and the steps after it, which use the plan
+	// file, are dropped.
+	assert.Equal(t, []valid.Step{
+		{StepName: "init"},
+		{StepName: "drift", ExtraArgs: []string{"-lock=false"}},
+	}, result[0].Steps)
+	assert.Equal(t, command.Drift, result[0].CommandName)
 }
@@ -5,7 +5,6 @@ package events
 
 import (
 	"sort"
-	"sync"
 
 	"github.com/remeh/sizedwaitgroup"
 	"github.com/runatlantis/atlantis/server/events/command"
@@ -13,29 +12,23 @@ import (
 
 type prjCmdRunnerFunc func(ctx command.ProjectContext) command.ProjectResult
 
+// runProjectCmdsParallel runs at most poolSize of cmds at the same time. The
+// results are in the order of cmds, not the order they finished in, so
+// comments don't change between runs.
 func runProjectCmdsParallel(
 	cmds []command.ProjectContext,
 	runnerFunc prjCmdRunnerFunc,
 	poolSize int,
 ) command.Result {
-	var results []command.ProjectResult
-	mux := &sync.Mutex{}
+	results := make([]command.ProjectResult, len(cmds))
 
 	wg := sizedwaitgroup.New(poolSize)
-	for _, pCmd := range cmds {
-		pCmd := pCmd
-		var execute func()
+	for i, pCmd := range cmds {
 		wg.Add()
-
-		execute = func() {
+		go func() {
 			defer wg.Done()
-			res := runnerFunc(pCmd)
-			mux.Lock()
-			results = append(results, res)
-			mux.Unlock()
-		}
-
-		go execute()
+			results[i] = runnerFunc(pCmd)
+		}()
 	}
 
 	wg.Wait()
@@ -74,6 +67,50 @@ func splitByExecutionOrderGroup(cmds []command.ProjectContext) [][]command.Proje
 	return res
 }
 
+// splitByDependencies splits cmds into batches so that each project only
+// depends on projects in earlier batches. Dependencies on projects that aren't
+// in cmds are ignored. If there are no dependencies between cmds a single batch
+// is returned.
+func splitByDependencies(cmds []command.ProjectContext) [][]command.ProjectContext {
+	pending := make(map[string]bool)
+	for _, cmd := range cmds {
+		if cmd.ProjectName != "" {
+			pending[cmd.ProjectName] = true
+		}
+	}
+
+	var batches [][]command.ProjectContext
+	remaining := cmds
+	for len(remaining) > 0 {
+		var batch, next []command.ProjectContext
+		for _, cmd := range remaining {
+			blocked := false
+			for _, dep := range cmd.DependsOn {
+				if pending[dep] && dep != cmd.ProjectName {
+					blocked = true
+					break
+				}
+			}
+			if blocked {
+				next = append(next, cmd)
+			} else {
+				batch = append(batch, cmd)
+			}
+		}
+		// Dependency cycles are rejected when parsing the repo config but
+		// don't loop forever if one slips through.
+		if len(batch) == 0 {
+			batch, next = next, nil
+		}
+		for _, cmd := range batch {
+			delete(pending, cmd.ProjectName)
+		}
+		batches = append(batches, batch)
+		remaining = next
+	}
+	return batches
+}
+
 func runProjectCmdsParallelGroups(
 	ctx *command.Context,
 	cmds []command.ProjectContext,
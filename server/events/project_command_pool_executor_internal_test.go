@@ -0,0 +1,123 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestSplitByDependencies(t *testing.T) {
+	names := func(batches [][]command.ProjectContext) [][]string {
+		var res [][]string
+		for _, batch := range batches {
+			var batchNames []string
+			for _, cmd := range batch {
+				batchNames = append(batchNames, cmd.ProjectName)
+			}
+			res = append(res, batchNames)
+		}
+		return res
+	}
+
+	cases := []struct {
+		description string
+		cmds        []command.ProjectContext
+		exp         [][]string
+	}{
+		{
+			description: "no dependencies",
+			cmds: []command.ProjectContext{
+				{ProjectName: "app"},
+				{ProjectName: "network"},
+			},
+			exp: [][]string{{"app", "network"}},
+		},
+		{
+			description: "dependencies outside of cmds are ignored",
+			cmds: []command.ProjectContext{
+				{ProjectName: "app", DependsOn: []string{"network"}},
+			},
+			exp: [][]string{{"app"}},
+		},
+		{
+			description: "dependents run after dependencies",
+			cmds: []command.ProjectContext{
+				{ProjectName: "app", DependsOn: []string{"network", "dns"}},
+				{ProjectName: "dns", DependsOn: []string{"network"}},
+				{ProjectName: "network"},
+				{ProjectName: "other"},
+			},
+			exp: [][]string{{"network", "other"}, {"dns"}, {"app"}},
+		},
+		{
+			description: "cycles don't loop forever",
+			cmds: []command.ProjectContext{
+				{ProjectName: "a", DependsOn: []string{"b"}},
+				{ProjectName: "b", DependsOn: []string{"a"}},
+			},
+			exp: [][]string{{"a", "b"}},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			Equals(t, c.exp, names(splitByDependencies(c.cmds)))
+		})
+	}
+}
+
+func TestWithAppliedProjects(t *testing.T) {
+	pullStatus := &models.PullStatus{
+		Projects: []models.ProjectStatus{
+			{ProjectName: "network", Status: models.PlannedPlanStatus},
+			{ProjectName: "app", Status: models.PlannedPlanStatus},
+		},
+	}
+	cmds := []command.ProjectContext{
+		{ProjectName: "app", DependsOn: []string{"network"}, PullStatus: pullStatus},
+	}
+
+	updated := withAppliedProjects(cmds, map[string]bool{"network": true})
+	Equals(t, models.AppliedPlanStatus, updated[0].PullStatus.Projects[0].Status)
+	Equals(t, models.PlannedPlanStatus, updated[0].PullStatus.Projects[1].Status)
+	// The original pull status is shared between commands so it must not be
+	// modified.
+	Equals(t, models.PlannedPlanStatus, pullStatus.Projects[0].Status)
+}
+
+func TestRunProjectCmdsParallel(t *testing.T) {
+	cmds := []command.ProjectContext{
+		{ProjectName: "a"},
+		{ProjectName: "b"},
+		{ProjectName: "c"},
+		{ProjectName: "d"},
+	}
+	var running, maxRunning atomic.Int32
+	result := runProjectCmdsParallel(cmds, func(ctx command.ProjectContext) command.ProjectResult {
+		n := running.Add(1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		// Earlier projects finish last so the results are out of order if
+		// they're appended as they finish.
+		time.Sleep(time.Duration('d'-ctx.ProjectName[0]) * 5 * time.Millisecond)
+		running.Add(-1)
+		return command.ProjectResult{ProjectName: ctx.ProjectName}
+	}, 2)
+
+	var names []string
+	for _, res := range result.ProjectResults {
+		names = append(names, res.ProjectName)
+	}
+	Equals(t, []string{"a", "b", "c", "d"}, names)
+	Assert(t, maxRunning.Load() <= 2, "exp at most 2 projects running at the same time, got %d", maxRunning.Load())
+}
@@ -20,7 +20,9 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/runtime"
@@ -28,11 +30,17 @@ import (
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/metrics"
 )
 
 const OperationComplete = true
 
+// canceledFailure is the failure of projects that were canceled before they
+// started.
+const canceledFailure = "Canceled before it started."
+
 // DirNotExistErr is an error caused by the directory not existing.
 type DirNotExistErr struct {
 	RepoRelDir string
@@ -91,6 +99,44 @@ type EnvStepRunner interface {
 	) (string, error)
 }
 
+// VaultSecretReader reads the secrets env steps reference in Vault.
+type VaultSecretReader interface {
+	// Read returns the value of key in the secret at secretPath.
+	Read(ctx command.ProjectContext, secretPath string, key string, path string, envs map[string]string) (string, error)
+}
+
+// ArchiveStepRunner runs archive steps.
+type ArchiveStepRunner interface {
+	// Run uploads the plan file in path to key in bucket on backend.
+	Run(
+		ctx command.ProjectContext,
+		backend string,
+		bucket string,
+		key string,
+		path string,
+		envs map[string]string,
+	) (string, error)
+}
+
+// ModulePinCheckStepRunner runs module_pin_check steps.
+type ModulePinCheckStepRunner interface {
+	// Run checks that the module sources in path are pinned.
+	Run(ctx command.ProjectContext, allowlist []string, path string, envs map[string]string) (string, error)
+}
+
+// AwaitApprovalStepRunner runs await_approval steps.
+type AwaitApprovalStepRunner interface {
+	// Run runs notifyCmd and waits up to timeout for an external approval.
+	Run(ctx command.ProjectContext, notifyCmd string, timeout time.Duration, path string, envs map[string]string) (string, error)
+}
+
+// LockProvidersStepRunner runs lock_providers steps.
+type LockProvidersStepRunner interface {
+	// Run locks the project's providers for platforms, pushing the updated
+	// lock file if push is set.
+	Run(ctx command.ProjectContext, platforms []string, push bool, path string, envs map[string]string) (string, error)
+}
+
 // MultiEnvStepRunner runs multienv steps.
 type MultiEnvStepRunner interface {
 	// Run cmd in path.
@@ -149,6 +195,12 @@ type ProjectStateCommandRunner interface {
 	StateRm(ctx command.ProjectContext) command.ProjectResult
 }
 
+type ProjectDriftCommandRunner interface {
+	// Drift runs terraform plan for the project described by ctx to report
+	// drift without saving the plan.
+	Drift(ctx command.ProjectContext) command.ProjectResult
+}
+
 // ProjectCommandRunner runs project commands. A project command is a command
 // for a specific TF project.
 type ProjectCommandRunner interface {
@@ -159,6 +211,7 @@ type ProjectCommandRunner interface {
 	ProjectVersionCommandRunner
 	ProjectImportCommandRunner
 	ProjectStateCommandRunner
+	ProjectDriftCommandRunner
 }
 
 //go:generate pegomock generate --package mocks -o mocks/mock_job_url_setter.go JobURLSetter
@@ -181,6 +234,9 @@ type ProjectOutputWrapper struct {
 	ProjectCommandRunner
 	JobMessageSender JobMessageSender
 	JobURLSetter     JobURLSetter
+	// JobURLGenerator is optional. If set, results link to their job so
+	// comments can link to the full output.
+	JobURLGenerator jobs.ProjectJobURLGenerator
 }
 
 func (p *ProjectOutputWrapper) Plan(ctx command.ProjectContext) command.ProjectResult {
@@ -190,6 +246,11 @@ func (p *ProjectOutputWrapper) Plan(ctx command.ProjectContext) command.ProjectR
 }
 
 func (p *ProjectOutputWrapper) Apply(ctx command.ProjectContext) command.ProjectResult {
+	// Dry runs don't apply so they don't set the project's apply status,
+	// which could otherwise satisfy checks that require it.
+	if ctx.ApplyDryRun {
+		return p.ProjectCommandRunner.Apply(ctx)
+	}
 	result := p.updateProjectPRStatus(command.Apply, ctx, p.ProjectCommandRunner.Apply)
 	p.JobMessageSender.Send(ctx, "", OperationComplete)
 	return result
@@ -205,6 +266,13 @@ func (p *ProjectOutputWrapper) updateProjectPRStatus(commandName command.Name, c
 
 	// ensures we are differentiating between project level command and overall command
 	result := execute(ctx)
+	if p.JobURLGenerator != nil {
+		url, err := p.JobURLGenerator.GenerateProjectJobURL(ctx)
+		if err != nil {
+			ctx.Log.Warn("unable to generate job url: %s", err)
+		}
+		result.JobURL = url
+	}
 
 	if result.Error != nil || result.Failure != "" {
 		if err := p.JobURLSetter.SetJobURLWithStatus(ctx, commandName, models.FailedCommitStatus, &result); err != nil {
@@ -235,28 +303,77 @@ type DefaultProjectCommandRunner struct {
 	VersionStepRunner         StepRunner
 	ImportStepRunner          StepRunner
 	StateRmStepRunner         StepRunner
+	DriftStepRunner           StepRunner
+	ValidateStepRunner        StepRunner
+	FmtCheckStepRunner        StepRunner
+	CostEstimateStepRunner    StepRunner
 	RunStepRunner             CustomStepRunner
 	EnvStepRunner             EnvStepRunner
+	VaultSecretReader         VaultSecretReader
 	MultiEnvStepRunner        MultiEnvStepRunner
+	ArchiveStepRunner         ArchiveStepRunner
+	ModulePinCheckStepRunner  ModulePinCheckStepRunner
+	AwaitApprovalStepRunner   AwaitApprovalStepRunner
+	LockProvidersStepRunner   LockProvidersStepRunner
 	PullApprovedChecker       runtime.PullApprovedChecker
 	WorkingDir                WorkingDir
 	Webhooks                  WebhooksSender
 	WorkingDirLocker          WorkingDirLocker
 	CommandRequirementHandler CommandRequirementHandler
+	// NoOpApply is what apply does for projects whose plan has no changes,
+	// one of ValidNoOpApplyModes. If it's empty, NoOpApplyRun is used.
+	NoOpApply string
+	// ShowPlanStepRunner renders a project's plan as text for apply dry
+	// runs. If it's nil, dry runs only show the plan's stats.
+	ShowPlanStepRunner StepRunner
 }
 
+const (
+	// NoOpApplyRun applies plans without changes like any other plan.
+	NoOpApplyRun = "apply"
+	// NoOpApplyWarn applies plans without changes and warns that the apply
+	// didn't change anything.
+	NoOpApplyWarn = "warn"
+	// NoOpApplySkip doesn't apply plans without changes. Their plan is
+	// discarded and the apply succeeds.
+	NoOpApplySkip = "skip"
+)
+
+// ValidNoOpApplyModes are the values of DefaultProjectCommandRunner.NoOpApply.
+var ValidNoOpApplyModes = []string{NoOpApplyRun, NoOpApplyWarn, NoOpApplySkip}
+
+const (
+	noOpApplySkippedOutput = "Skipped apply since the plan has no changes."
+	noOpApplyWarning       = ":warning: The plan had no changes so this apply didn't change anything.\n\n"
+	dryRunApplyOutput      = "Dry run: the apply requirements are met, the plan wasn't applied."
+)
+
 // Plan runs terraform plan for the project described by ctx.
 func (p *DefaultProjectCommandRunner) Plan(ctx command.ProjectContext) command.ProjectResult {
+	if ctx.Verbose {
+		logFile, err := os.CreateTemp("", "atlantis-terraform-log")
+		if err != nil {
+			ctx.Log.Warn("unable to create terraform debug log file: %s", err)
+		} else {
+			logFile.Close()                 // nolint: errcheck
+			defer os.Remove(logFile.Name()) // nolint: errcheck
+			ctx.TerraformLogPath = logFile.Name()
+		}
+	}
 	planSuccess, failure, err := p.doPlan(ctx)
 	return command.ProjectResult{
-		Command:           command.Plan,
-		PlanSuccess:       planSuccess,
-		Error:             err,
-		Failure:           failure,
-		RepoRelDir:        ctx.RepoRelDir,
-		Workspace:         ctx.Workspace,
-		ProjectName:       ctx.ProjectName,
-		SilencePRComments: ctx.SilencePRComments,
+		Command:              command.Plan,
+		PlanSuccess:          planSuccess,
+		Error:                err,
+		Failure:              failure,
+		RepoRelDir:           ctx.RepoRelDir,
+		Workspace:            ctx.Workspace,
+		ProjectName:          ctx.ProjectName,
+		Labels:               ctx.Labels,
+		SilencePRComments:    ctx.SilencePRComments,
+		NoChangesMessage:     ctx.NoChangesMessage,
+		HidePrevPlanComments: ctx.HidePrevPlanComments,
+		TerraformDebugLog:    terraformDebugLog(ctx),
 	}
 }
 
@@ -271,6 +388,8 @@ func (p *DefaultProjectCommandRunner) PolicyCheck(ctx command.ProjectContext) co
 		RepoRelDir:         ctx.RepoRelDir,
 		Workspace:          ctx.Workspace,
 		ProjectName:        ctx.ProjectName,
+		Labels:             ctx.Labels,
+		QuietPolicyChecks:  ctx.QuietPolicyChecks,
 	}
 }
 
@@ -278,14 +397,16 @@ func (p *DefaultProjectCommandRunner) PolicyCheck(ctx command.ProjectContext) co
 func (p *DefaultProjectCommandRunner) Apply(ctx command.ProjectContext) command.ProjectResult {
 	applyOut, failure, err := p.doApply(ctx)
 	return command.ProjectResult{
-		Command:           command.Apply,
-		Failure:           failure,
-		Error:             err,
-		ApplySuccess:      applyOut,
-		RepoRelDir:        ctx.RepoRelDir,
-		Workspace:         ctx.Workspace,
-		ProjectName:       ctx.ProjectName,
-		SilencePRComments: ctx.SilencePRComments,
+		Command:              command.Apply,
+		Failure:              failure,
+		Error:                err,
+		ApplySuccess:         applyOut,
+		RepoRelDir:           ctx.RepoRelDir,
+		Workspace:            ctx.Workspace,
+		ProjectName:          ctx.ProjectName,
+		Labels:               ctx.Labels,
+		SilencePRComments:    ctx.SilencePRComments,
+		HidePrevPlanComments: ctx.HidePrevPlanComments,
 	}
 }
 
@@ -299,6 +420,7 @@ func (p *DefaultProjectCommandRunner) ApprovePolicies(ctx command.ProjectContext
 		RepoRelDir:         ctx.RepoRelDir,
 		Workspace:          ctx.Workspace,
 		ProjectName:        ctx.ProjectName,
+		Labels:             ctx.Labels,
 	}
 }
 
@@ -312,6 +434,7 @@ func (p *DefaultProjectCommandRunner) Version(ctx command.ProjectContext) comman
 		RepoRelDir:     ctx.RepoRelDir,
 		Workspace:      ctx.Workspace,
 		ProjectName:    ctx.ProjectName,
+		Labels:         ctx.Labels,
 	}
 }
 
@@ -326,6 +449,7 @@ func (p *DefaultProjectCommandRunner) Import(ctx command.ProjectContext) command
 		RepoRelDir:    ctx.RepoRelDir,
 		Workspace:     ctx.Workspace,
 		ProjectName:   ctx.ProjectName,
+		Labels:        ctx.Labels,
 	}
 }
 
@@ -341,6 +465,22 @@ func (p *DefaultProjectCommandRunner) StateRm(ctx command.ProjectContext) comman
 		RepoRelDir:     ctx.RepoRelDir,
 		Workspace:      ctx.Workspace,
 		ProjectName:    ctx.ProjectName,
+		Labels:         ctx.Labels,
+	}
+}
+
+// Drift runs terraform plan for the project described by ctx to report drift.
+func (p *DefaultProjectCommandRunner) Drift(ctx command.ProjectContext) command.ProjectResult {
+	driftSuccess, failure, err := p.doDrift(ctx)
+	return command.ProjectResult{
+		Command:      command.Drift,
+		DriftSuccess: driftSuccess,
+		Error:        err,
+		Failure:      failure,
+		RepoRelDir:   ctx.RepoRelDir,
+		Workspace:    ctx.Workspace,
+		ProjectName:  ctx.ProjectName,
+		Labels:       ctx.Labels,
 	}
 }
 
@@ -386,12 +526,16 @@ func (p *DefaultProjectCommandRunner) doApprovePolicies(ctx command.ProjectConte
 	allPassed := true
 	for _, policySet := range policySetCfg.PolicySets {
 		isOwner := policySet.Owners.IsOwner(ctx.User.Username, teams) || isAdmin
+		if len(policySet.Approvers) > 0 {
+			// Only members of the approver teams can approve the policy set.
+			isOwner = policySet.IsApprover(teams)
+		}
 		prjPolicyStatus := ctx.ProjectPolicyStatus
 		for i, policyStatus := range prjPolicyStatus {
 			ignorePolicy := false
 			if policySet.Name == policyStatus.PolicySetName {
-				// Policy set either passed or has sufficient approvals. Move on.
-				if policyStatus.Passed || (policyStatus.Approvals == policySet.ApproveCount) {
+				// Policy set either passed, only warns or has sufficient approvals. Move on.
+				if policyStatus.Passed || policySet.SoftFail || (policyStatus.Approvals == policySet.ApproveCount) {
 					if !ctx.ClearPolicyApproval {
 						ignorePolicy = true
 					}
@@ -411,11 +555,13 @@ func (p *DefaultProjectCommandRunner) doApprovePolicies(ctx command.ProjectConte
 				} else if isOwner && !ignorePolicy && ctx.User.Username == ctx.Pull.Author && policySet.PreventSelfApprove {
 					prjErr = errors.Join(prjErr, fmt.Errorf("policy set: %s the author of pr %s matches the command commenter user %s - please contact another policy owners to approve failing policies", policySet.Name, ctx.Pull.Author, ctx.User.Username))
 					// User is not authorized to approve policy set.
+				} else if !ignorePolicy && len(policySet.Approvers) > 0 {
+					prjErr = errors.Join(prjErr, fmt.Errorf("policy set: %s user %s is not a member of the approver team(s) %s - please contact them to approve failing policies", policySet.Name, ctx.User.Username, strings.Join(policySet.Approvers, ", ")))
 				} else if !ignorePolicy {
 					prjErr = errors.Join(prjErr, fmt.Errorf("policy set: %s user %s is not a policy owner - please contact policy owners to approve failing policies", policySet.Name, ctx.User.Username))
 				}
 				// Still bubble up this failure, even if policy set is not targeted.
-				if !policyStatus.Passed && (prjPolicyStatus[i].Approvals != policySet.ApproveCount) {
+				if !policyStatus.Passed && !policySet.SoftFail && (prjPolicyStatus[i].Approvals != policySet.ApproveCount) {
 					allPassed = false
 				}
 
@@ -424,6 +570,7 @@ func (p *DefaultProjectCommandRunner) doApprovePolicies(ctx command.ProjectConte
 					Passed:        policyStatus.Passed,
 					CurApprovals:  prjPolicyStatus[i].Approvals,
 					ReqApprovals:  policySet.ApproveCount,
+					SoftFail:      policySet.SoftFail,
 				})
 			}
 		}
@@ -493,7 +640,7 @@ func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext)
 	}
 
 	var failure string
-	outputs, err := p.runSteps(ctx.Steps, ctx, absPath)
+	outputs, err := p.runStage(ctx, absPath)
 	var errs error
 	if err != nil {
 		for {
@@ -565,6 +712,14 @@ func (p *DefaultProjectCommandRunner) doPolicyCheck(ctx command.ProjectContext)
 }
 
 func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*models.PlanSuccess, string, error) {
+	if ctx.IsCanceled() {
+		return nil, canceledFailure, nil
+	}
+	// A refresh-only plan only refreshes so it can't skip refreshing.
+	if ctx.PlanRefreshOnly && skipsRefresh(ctx.Steps) {
+		return nil, "Can't run plan with `--refresh-only` since the plan step of this project's workflow sets `refresh: false`.", nil
+	}
+
 	// Acquire Atlantis lock for this repo/dir/workspace.
 	lockAttempt, err := p.Locker.TryLock(ctx.Log, ctx.Pull, ctx.User, ctx.Workspace, models.NewProject(ctx.Pull.BaseRepo.FullName, ctx.RepoRelDir, ctx.ProjectName), ctx.RepoLocksMode == valid.RepoLocksOnPlanMode)
 	if err != nil {
@@ -608,7 +763,25 @@ func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*model
 		return nil, failure, err
 	}
 
-	outputs, err := p.runSteps(ctx.Steps, ctx, projAbsPath)
+	if err := runtime.PreparePlanFile(ctx, projAbsPath); err != nil {
+		if unlockErr := lockAttempt.UnlockFn(); unlockErr != nil {
+			ctx.Log.Err("error unlocking state after plan error: %v", unlockErr)
+		}
+		return nil, "", err
+	}
+	if err := runtime.RemovePlanStats(ctx, projAbsPath); err != nil {
+		ctx.Log.Warn("unable to remove resource changes of the previous plan: %s", err)
+	}
+	if err := runtime.RemoveWorkspaceCreated(ctx, projAbsPath); err != nil {
+		ctx.Log.Warn("unable to remove that the previous plan created the workspace: %s", err)
+	}
+	if err := runtime.RemoveCostEstimate(ctx, projAbsPath); err != nil {
+		ctx.Log.Warn("unable to remove the cost estimate of the previous plan: %s", err)
+	}
+	if err := runtime.RemoveStepArtifacts(ctx, projAbsPath); err != nil {
+		ctx.Log.Warn("unable to remove the outputs stored by the previous plan: %s", err)
+	}
+	outputs, err := p.runStage(ctx, projAbsPath)
 
 	if err != nil {
 		if unlockErr := lockAttempt.UnlockFn(); unlockErr != nil {
@@ -616,17 +789,51 @@ func (p *DefaultProjectCommandRunner) doPlan(ctx command.ProjectContext) (*model
 		}
 		return nil, "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
 	}
+	if err := runtime.LinkPlanFile(ctx, projAbsPath); err != nil {
+		return nil, "", fmt.Errorf("linking plan file: %w", err)
+	}
+	stats, err := runtime.ReadPlanStats(ctx, projAbsPath)
+	if err != nil {
+		ctx.Log.Warn("unable to read resource changes of the plan, parsing them from the plan output: %s", err)
+	}
+	var createdWorkspace, tfVersion, tfDistribution string
+	if runtime.WorkspaceCreated(ctx, projAbsPath) {
+		createdWorkspace = ctx.Workspace
+		if ctx.TerraformVersion != nil {
+			tfVersion = ctx.TerraformVersion.String()
+		}
+		if ctx.TerraformDistribution != nil {
+			tfDistribution = *ctx.TerraformDistribution
+		}
+	}
+	costEstimate, err := runtime.ReadCostEstimate(ctx, projAbsPath)
+	if err != nil {
+		ctx.Log.Warn("unable to read the cost estimate of the plan: %s", err)
+	}
 
 	return &models.PlanSuccess{
-		LockURL:         p.LockURLGenerator.GenerateLockURL(lockAttempt.LockKey),
-		TerraformOutput: strings.Join(outputs, "\n"),
-		RePlanCmd:       ctx.RePlanCmd,
-		ApplyCmd:        ctx.ApplyCmd,
-		MergedAgain:     mergedAgain,
+		LockURL:                  p.LockURLGenerator.GenerateLockURL(lockAttempt.LockKey),
+		TerraformOutput:          strings.Join(outputs, "\n"),
+		RePlanCmd:                ctx.RePlanCmd,
+		ApplyCmd:                 ctx.ApplyCmd,
+		MergedAgain:              mergedAgain,
+		DestroyPlan:              ctx.PlanDestroy,
+		RefreshOnlyPlan:          ctx.PlanRefreshOnly,
+		DestroyThreshold:         ctx.DestroyThreshold,
+		DestroyThresholdApproval: ctx.DestroyThresholdApproval,
+		JSONStats:                stats,
+		CreatedWorkspace:         createdWorkspace,
+		TerraformVersion:         tfVersion,
+		TerraformDistribution:    tfDistribution,
+		CostEstimate:             costEstimate,
 	}, "", nil
 }
 
 func (p *DefaultProjectCommandRunner) doApply(ctx command.ProjectContext) (applyOut string, failure string, err error) {
+	if ctx.IsCanceled() {
+		return "", canceledFailure, nil
+	}
+
 	repoDir, err := p.WorkingDir.GetWorkingDir(ctx.Pull.BaseRepo, ctx.Pull, ctx.Workspace)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -649,6 +856,17 @@ func (p *DefaultProjectCommandRunner) doApply(ctx command.ProjectContext) (apply
 		return "", failure, err
 	}
 
+	if ctx.ApplyDryRun {
+		ctx.Log.Info("not applying since this is a dry run")
+		unlockFn, err := p.WorkingDirLocker.TryLock(ctx.Pull.BaseRepo.FullName, ctx.Pull.Num, ctx.Workspace, ctx.RepoRelDir, command.Apply)
+		if err != nil {
+			return "", "", err
+		}
+		defer unlockFn()
+		out, err := p.dryRunApply(ctx, absPath)
+		return out, "", err
+	}
+
 	// Acquire Atlantis lock for this repo/dir/workspace.
 	lockAttempt, err := p.Locker.TryLock(ctx.Log, ctx.Pull, ctx.User, ctx.Workspace, models.NewProject(ctx.Pull.BaseRepo.FullName, ctx.RepoRelDir, ctx.ProjectName), ctx.RepoLocksMode == valid.RepoLocksOnApplyMode)
 	if err != nil {
@@ -666,7 +884,16 @@ func (p *DefaultProjectCommandRunner) doApply(ctx command.ProjectContext) (apply
 	}
 	defer unlockFn()
 
-	outputs, err := p.runSteps(ctx.Steps, ctx, absPath)
+	noOp := p.NoOpApply != "" && p.NoOpApply != NoOpApplyRun && planHasNoChanges(ctx)
+	if noOp && p.NoOpApply == NoOpApplySkip {
+		ctx.Log.Info("skipping apply since the plan has no changes")
+		if err := runtime.RemovePlanFile(ctx, absPath); err != nil {
+			return "", "", fmt.Errorf("deleting plan without changes: %w", err)
+		}
+		return noOpApplySkippedOutput, "", nil
+	}
+
+	outputs, err := p.runStage(ctx, absPath)
 
 	p.Webhooks.Send(ctx.Log, webhooks.ApplyResult{ // nolint: errcheck
 		Workspace:   ctx.Workspace,
@@ -681,10 +908,59 @@ func (p *DefaultProjectCommandRunner) doApply(ctx command.ProjectContext) (apply
 	if err != nil {
 		return "", "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
 	}
+	if noOp {
+		return noOpApplyWarning + strings.Join(outputs, "\n"), "", nil
+	}
 
 	return strings.Join(outputs, "\n"), "", nil
 }
 
+// dryRunApply checks that the project in ctx has a plan and returns what
+// applying it would change, without applying it. If the plan can't be shown,
+// only the plan's stats are returned.
+func (p *DefaultProjectCommandRunner) dryRunApply(ctx command.ProjectContext, absPath string) (string, error) {
+	planPath, err := runtime.GetPlanFilePath(ctx, absPath)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(planPath); err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("no plan found at path %q and workspace %q–did you run plan?", ctx.RepoRelDir, ctx.Workspace)
+		}
+		return "", err
+	}
+
+	out := dryRunApplyOutput
+	if p.ShowPlanStepRunner != nil {
+		plan, err := p.ShowPlanStepRunner.Run(ctx, nil, absPath, map[string]string{})
+		if err == nil {
+			return out + "\n\n" + strings.TrimSpace(ctx.MaskOutput(plan)), nil
+		}
+		ctx.Log.Warn("unable to show plan: %s", err)
+	}
+	if stats := ctx.ProjectPlanStats; stats != nil {
+		if stats.NoChanges {
+			out += "\n\nNo changes."
+		} else {
+			var imports string
+			if stats.Import > 0 {
+				imports = fmt.Sprintf("%d to import, ", stats.Import)
+			}
+			out += fmt.Sprintf("\n\nPlan: %s%d to add, %d to change, %d to destroy.", imports, stats.Add, stats.Change, stats.Destroy)
+		}
+	}
+	return out, nil
+}
+
+// planHasNoChanges returns whether the latest plan of the project in ctx
+// has no changes. Plans that only change outputs have changes.
+func planHasNoChanges(ctx command.ProjectContext) bool {
+	if ctx.ProjectPlanStats != nil {
+		return ctx.ProjectPlanStats.NoChanges
+	}
+	return ctx.ProjectPlanStatus == models.PlannedNoChangesPlanStatus
+}
+
 func (p *DefaultProjectCommandRunner) doVersion(ctx command.ProjectContext) (versionOut string, failure string, err error) {
 	repoDir, err := p.WorkingDir.GetWorkingDir(ctx.Pull.BaseRepo, ctx.Pull, ctx.Workspace)
 	if err != nil {
@@ -705,7 +981,7 @@ func (p *DefaultProjectCommandRunner) doVersion(ctx command.ProjectContext) (ver
 	}
 	defer unlockFn()
 
-	outputs, err := p.runSteps(ctx.Steps, ctx, absPath)
+	outputs, err := p.runStage(ctx, absPath)
 	if err != nil {
 		return "", "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
 	}
@@ -746,7 +1022,7 @@ func (p *DefaultProjectCommandRunner) doImport(ctx command.ProjectContext) (out
 	}
 	defer unlockFn()
 
-	outputs, err := p.runSteps(ctx.Steps, ctx, projAbsPath)
+	outputs, err := p.runStage(ctx, projAbsPath)
 	if err != nil {
 		return nil, "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
 	}
@@ -787,7 +1063,7 @@ func (p *DefaultProjectCommandRunner) doStateRm(ctx command.ProjectContext) (out
 	}
 	defer unlockFn()
 
-	outputs, err := p.runSteps(ctx.Steps, ctx, projAbsPath)
+	outputs, err := p.runStage(ctx, projAbsPath)
 	if err != nil {
 		return nil, "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
 	}
@@ -800,18 +1076,88 @@ func (p *DefaultProjectCommandRunner) doStateRm(ctx command.ProjectContext) (out
 	}, "", nil
 }
 
+func (p *DefaultProjectCommandRunner) doDrift(ctx command.ProjectContext) (out *models.DriftSuccess, failure string, err error) {
+	if !slices.ContainsFunc(ctx.Steps, func(step valid.Step) bool { return step.StepName == "drift" }) {
+		return nil, "", errors.New("the project's plan workflow has no plan step so drift can't be detected")
+	}
+
+	// Unlike plan, drift doesn't take the project lock since it doesn't save
+	// a plan that could be applied.
+	unlockFn, err := p.WorkingDirLocker.TryLock(ctx.Pull.BaseRepo.FullName, ctx.Pull.Num, ctx.Workspace, ctx.RepoRelDir, command.Drift)
+	if err != nil {
+		return nil, "", err
+	}
+	defer unlockFn()
+
+	// Clone is idempotent so okay to run even if the repo was already cloned.
+	repoDir, err := p.WorkingDir.Clone(ctx.Log, ctx.HeadRepo, ctx.Pull, ctx.Workspace)
+	if err != nil {
+		return nil, "", err
+	}
+	projAbsPath := filepath.Join(repoDir, ctx.RepoRelDir)
+	if _, err = os.Stat(projAbsPath); os.IsNotExist(err) {
+		return nil, "", DirNotExistErr{RepoRelDir: ctx.RepoRelDir}
+	}
+
+	outputs, err := p.runStage(ctx, projAbsPath)
+	drifted := errors.Is(err, runtime.ErrDriftDetected)
+	if err != nil && !drifted {
+		return nil, "", fmt.Errorf("%s\n%s", err, strings.Join(outputs, "\n"))
+	}
+	return &models.DriftSuccess{
+		TerraformOutput: strings.Join(outputs, "\n"),
+		Drifted:         drifted,
+	}, "", nil
+}
+
+// runStage runs the steps for ctx, re-running all of them if they fail with
+// output matching the stage's retry patterns.
+func (p *DefaultProjectCommandRunner) runStage(ctx command.ProjectContext, absPath string) ([]string, error) {
+	for attempt := 1; ; attempt++ {
+		outputs, err := p.runSteps(ctx.Steps, ctx, absPath)
+		if err == nil || errors.Is(err, runtime.ErrDriftDetected) {
+			return outputs, err
+		}
+		output := strings.Join(append(outputs, err.Error()), "\n")
+		delay, retry := ctx.StageRetry.ShouldRetry(attempt, output)
+		if !retry {
+			return outputs, err
+		}
+		ctx.Log.Warn("%s failed with output matching a retry pattern, retrying in %s (attempt %d/%d): %s",
+			ctx.CommandName, delay, attempt+1, ctx.StageRetry.MaxAttempts, err)
+		time.Sleep(delay)
+	}
+}
+
 func (p *DefaultProjectCommandRunner) runSteps(steps []valid.Step, ctx command.ProjectContext, absPath string) ([]string, error) {
 	var outputs []string
 
 	envs := make(map[string]string)
+	if ctx.TerraformLogPath != "" {
+		envs["TF_LOG"] = "DEBUG"
+		envs["TF_LOG_PATH"] = ctx.TerraformLogPath
+	}
 	for _, step := range steps {
+		if ctx.IsCanceled() {
+			return outputs, fmt.Errorf("canceled before running the %s step", step.StepName)
+		}
+		// Repo config is validated against the allowed run commands but
+		// server-side workflows and defaults aren't so they're checked here.
+		if err := valid.ValidateRunCommand(ctx.AllowedRunCommands, step); err != nil {
+			ctx.Log.Warn("not running %s step: %s", step.StepName, err)
+			return outputs, err
+		}
 		var out string
 		var err error
+		start := time.Now()
 		switch step.StepName {
 		case "init":
 			out, err = p.InitStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
 		case "plan":
-			out, err = p.PlanStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+			out, err = p.PlanStepRunner.Run(ctx, planStepArgs(step), absPath, envs)
+			if slices.Contains(step.Output, valid.PostProcessRunOutputCollapseUnchanged) {
+				out = runtime.CollapseUnchangedAttributes(out)
+			}
 		case "show":
 			_, err = p.ShowStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
 		case "policy_check":
@@ -824,24 +1170,184 @@ func (p *DefaultProjectCommandRunner) runSteps(steps []valid.Step, ctx command.P
 			out, err = p.ImportStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
 		case "state_rm":
 			out, err = p.StateRmStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+		case "drift":
+			out, err = p.DriftStepRunner.Run(ctx, planStepArgs(step), absPath, envs)
+		case "validate":
+			out, err = p.ValidateStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+		case "fmt_check":
+			out, err = p.FmtCheckStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
+		case "cost_estimate":
+			out, err = p.CostEstimateStepRunner.Run(ctx, step.ExtraArgs, absPath, envs)
 		case "run":
+			if step.StoreAs != "" {
+				out, err = p.runStoringOutput(ctx, step, absPath, envs)
+				break
+			}
 			out, err = p.RunStepRunner.Run(ctx, step.RunShell, step.RunCommand, absPath, envs, true, step.Output, step.FilterRegexes)
 		case "env":
-			out, err = p.EnvStepRunner.Run(ctx, step.RunShell, step.RunCommand, step.EnvVarValue, absPath, envs)
+			if step.EnvVarVault != nil {
+				out, err = p.VaultSecretReader.Read(ctx, step.EnvVarVault.Path, step.EnvVarVault.Key, absPath, envs)
+			} else {
+				out, err = p.EnvStepRunner.Run(ctx, step.RunShell, step.RunCommand, step.EnvVarValue, absPath, envs)
+			}
 			envs[step.EnvVarName] = out
 			// We reset out to the empty string because we don't want it to
 			// be printed to the PR, it's solely to set the environment variable.
 			out = ""
 		case "multienv":
 			out, err = p.MultiEnvStepRunner.Run(ctx, step.RunShell, step.RunCommand, absPath, envs, step.Output)
+		case "archive":
+			out, err = p.ArchiveStepRunner.Run(ctx, step.ArchiveBackend, step.ArchiveBucket, step.ArchiveKey, absPath, envs)
+		case "module_pin_check":
+			out, err = p.ModulePinCheckStepRunner.Run(ctx, step.ModulePinAllowlist, absPath, envs)
+		case "await_approval":
+			out, err = p.AwaitApprovalStepRunner.Run(ctx, step.RunCommand, step.ApprovalTimeout, absPath, envs)
+		case "lock_providers":
+			out, err = p.LockProvidersStepRunner.Run(ctx, step.LockProvidersPlatforms, step.LockProvidersPush, absPath, envs)
 		}
 
+		// Output matching the denylist is redacted before it's commented and
+		// fails the command.
+		var denied bool
+		out, denied = redactDeniedOutput(ctx.StepOutputDenylist, out)
+		if err != nil {
+			if msg, errDenied := redactDeniedOutput(ctx.StepOutputDenylist, err.Error()); errDenied {
+				err = errors.New(msg)
+				denied = true
+			}
+		}
+		if denied {
+			ctx.Log.Warn("output of %s step matched the step output denylist", step.StepName)
+		}
+		// Unlike the denylist, masks only hide what they match.
+		out = ctx.MaskOutput(out)
+		if err != nil {
+			if msg := ctx.MaskOutput(err.Error()); msg != err.Error() {
+				err = errors.New(msg)
+			}
+		}
+
+		recordStepDuration(ctx, step.StepName, time.Since(start), err == nil && !denied)
+
 		if out != "" {
 			outputs = append(outputs, out)
 		}
 		if err != nil {
 			return outputs, err
 		}
+		if denied {
+			return outputs, fmt.Errorf("output of %s step matched the step output denylist, the matching content has been redacted", step.StepName)
+		}
 	}
 	return outputs, nil
 }
+
+// recordStepDuration records the duration of a step in a histogram tagged by
+// the step name, ex. plan or run, and whether it succeeded. Only the step name
+// is used so the commands of run steps don't create a series each.
+func recordStepDuration(ctx command.ProjectContext, stepName string, duration time.Duration, success bool) {
+	if ctx.Scope == nil {
+		return
+	}
+	result := metrics.ExecutionSuccessMetric
+	if !success {
+		result = metrics.ExecutionErrorMetric
+	}
+	ctx.Scope.SubScope("step").Tagged(map[string]string{
+		"step":   stepName,
+		"result": result,
+	}).Histogram(metrics.ExecutionTimeMetric, metrics.DurationBuckets).RecordDuration(duration)
+}
+
+// skipsRefresh returns true if a plan step in steps sets refresh: false.
+func skipsRefresh(steps []valid.Step) bool {
+	return slices.ContainsFunc(steps, func(step valid.Step) bool {
+		return step.StepName == "plan" && step.SkipRefresh
+	})
+}
+
+// planStepArgs returns the extra args of a plan step with a -target flag for
+// each of its targets. Terraform plans the union of all -target flags so
+// targets from the comment are added to these. -refresh=false comes first so
+// the step's extra args and the comment can still turn refreshing back on.
+// Targets are shell-quoted since Terraform is run in a shell and addresses
+// can contain quotes and brackets, ex. aws_instance.web["blue"].
+func planStepArgs(step valid.Step) []string {
+	var args []string
+	if step.SkipRefresh {
+		args = append(args, "-refresh=false")
+	}
+	args = append(args, step.ExtraArgs...)
+	for _, target := range step.Targets {
+		args = append(args, "-target="+runtime.ShellQuote(target))
+	}
+	return args
+}
+
+// terraformDebugLogMaxLen is the most of Terraform's debug log that's
+// commented. Debug logs are huge and comments have size limits so only the end
+// of the log, which is usually where the failure is, is kept.
+const terraformDebugLogMaxLen = 10000
+
+// terraformDebugLog returns the tail of the debug log Terraform wrote to
+// ctx.TerraformLogPath with the step output denylist and masks applied.
+func terraformDebugLog(ctx command.ProjectContext) string {
+	if ctx.TerraformLogPath == "" {
+		return ""
+	}
+	content, err := os.ReadFile(ctx.TerraformLogPath)
+	if err != nil {
+		ctx.Log.Warn("unable to read terraform debug log: %s", err)
+		return ""
+	}
+	log := string(content)
+	if len(log) > terraformDebugLogMaxLen {
+		log = log[len(log)-terraformDebugLogMaxLen:]
+		// Don't start in the middle of a line.
+		if i := strings.Index(log, "\n"); i != -1 {
+			log = log[i+1:]
+		}
+		log = fmt.Sprintf("[truncated to the last %d bytes]\n%s", len(log), log)
+	}
+	log, _ = redactDeniedOutput(ctx.StepOutputDenylist, log)
+	return strings.TrimSpace(ctx.MaskOutput(log))
+}
+
+// runStoringOutput runs a run step that stores its output with store_as.
+// The output is stored before it's hidden so steps with output: hide can
+// pass values to later steps without commenting them.
+func (p *DefaultProjectCommandRunner) runStoringOutput(ctx command.ProjectContext, step valid.Step, absPath string, envs map[string]string) (string, error) {
+	var postProcessOutput []valid.PostProcessRunOutputOption
+	hide := false
+	for _, option := range step.Output {
+		if option == valid.PostProcessRunOutputHide {
+			hide = true
+			continue
+		}
+		postProcessOutput = append(postProcessOutput, option)
+	}
+	out, err := p.RunStepRunner.Run(ctx, step.RunShell, step.RunCommand, absPath, envs, true, postProcessOutput, step.FilterRegexes)
+	if err != nil {
+		return "", err
+	}
+	if err := runtime.StoreStepArtifact(ctx, absPath, step.StoreAs, strings.TrimSpace(out)); err != nil {
+		return "", fmt.Errorf("run step: %w", err)
+	}
+	if hide {
+		return "", nil
+	}
+	return out, nil
+}
+
+// redactDeniedOutput replaces everything in out matching one of the denylist
+// regexes and returns whether anything matched.
+func redactDeniedOutput(denylist []*regexp.Regexp, out string) (string, bool) {
+	denied := false
+	for _, pattern := range denylist {
+		if pattern.MatchString(out) {
+			out = pattern.ReplaceAllLiteralString(out, "<redacted>")
+			denied = true
+		}
+	}
+	return out, denied
+}
@@ -550,6 +550,54 @@ func TestDefaultProjectFinder_DetermineProjectsViaConfig(t *testing.T) {
 			modified:     []string{"project1/subdir1/main.tf", "project1/subdir2/main.tf"},
 			expProjPaths: nil,
 		},
+		{
+			description: "dir excluded with double star",
+			config: valid.RepoCfg{
+				Projects: []valid.Project{
+					{
+						Dir: "project1",
+						Autoplan: valid.Autoplan{
+							Enabled:      true,
+							WhenModified: []string{"**/*.tf", "!subdir1/**"},
+						},
+					},
+				},
+			},
+			modified:     []string{"project1/subdir1/nested/main.tf"},
+			expProjPaths: nil,
+		},
+		{
+			description: "later pattern re-includes excluded file",
+			config: valid.RepoCfg{
+				Projects: []valid.Project{
+					{
+						Dir: "project1",
+						Autoplan: valid.Autoplan{
+							Enabled:      true,
+							WhenModified: []string{"**/*.tf", "!subdir1/**", "subdir1/main.tf"},
+						},
+					},
+				},
+			},
+			modified:     []string{"project1/subdir1/main.tf"},
+			expProjPaths: []string{"project1"},
+		},
+		{
+			description: "only exclusions match nothing",
+			config: valid.RepoCfg{
+				Projects: []valid.Project{
+					{
+						Dir: "project1",
+						Autoplan: valid.Autoplan{
+							Enabled:      true,
+							WhenModified: []string{"!subdir1/**"},
+						},
+					},
+				},
+			},
+			modified:     []string{"project1/main.tf", "project1/subdir2/main.tf"},
+			expProjPaths: nil,
+		},
 	}
 
 	for _, c := range cases {
@@ -15,11 +15,27 @@ package events
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/runatlantis/atlantis/server/core/locking"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/runatlantis/atlantis/server/metrics"
+	tally "github.com/uber-go/tally/v4"
+)
+
+// lockDurationBuckets are the buckets of the lock acquisition histogram, from
+// 1ms to about 16s.
+var lockDurationBuckets = tally.MustMakeExponentialDurationBuckets(time.Millisecond, 2, 15)
+
+const (
+	// lockAcquiredResult tags the attempts that acquired the lock.
+	lockAcquiredResult = "acquired"
+	// lockBlockedResult tags the attempts blocked by the lock of another pull.
+	lockBlockedResult = "blocked"
+	// lockErrorResult tags the attempts that failed with an error.
+	lockErrorResult = "error"
 )
 
 //go:generate pegomock generate --package mocks -o mocks/mock_project_lock.go ProjectLocker
@@ -41,6 +57,8 @@ type DefaultProjectLocker struct {
 	Locker     locking.Locker
 	NoOpLocker locking.Locker
 	VCSClient  vcs.Client
+	// Scope records how long acquiring locks took by result, if set.
+	Scope tally.Scope
 }
 
 // TryLockResponse is the result of trying to lock a project.
@@ -65,11 +83,17 @@ func (p *DefaultProjectLocker) TryLock(log logging.SimpleLogging, pull models.Pu
 		locker = p.NoOpLocker
 	}
 
+	start := time.Now()
 	lockAttempt, err := locker.TryLock(project, workspace, pull, user)
+	duration := time.Since(start)
 	if err != nil {
+		p.recordLockDuration(lockErrorResult, duration)
+		log.Warn("Failed to acquire lock after %s: %s", duration, err)
 		return nil, err
 	}
 	if !lockAttempt.LockAcquired && lockAttempt.CurrLock.Pull.Num != pull.Num {
+		p.recordLockDuration(lockBlockedResult, duration)
+		log.Info("Lock is held by pull %d, gave up after %s", lockAttempt.CurrLock.Pull.Num, duration)
 		link, err := p.VCSClient.MarkdownPullLink(lockAttempt.CurrLock.Pull)
 		if err != nil {
 			return nil, err
@@ -83,7 +107,8 @@ func (p *DefaultProjectLocker) TryLock(log logging.SimpleLogging, pull models.Pu
 			LockFailureReason: failureMsg,
 		}, nil
 	}
-	log.Info("Acquired lock with id '%s'", lockAttempt.LockKey)
+	p.recordLockDuration(lockAcquiredResult, duration)
+	log.Info("Acquired lock with id '%s' in %s", lockAttempt.LockKey, duration)
 	return &TryLockResponse{
 		LockAcquired: true,
 		UnlockFn: func() error {
@@ -93,3 +118,12 @@ func (p *DefaultProjectLocker) TryLock(log logging.SimpleLogging, pull models.Pu
 		LockKey: lockAttempt.LockKey,
 	}, nil
 }
+
+func (p *DefaultProjectLocker) recordLockDuration(result string, duration time.Duration) {
+	if p.Scope == nil {
+		return
+	}
+	p.Scope.Tagged(map[string]string{"result": result}).
+		Histogram(metrics.ExecutionTimeMetric, lockDurationBuckets).
+		RecordDuration(duration)
+}
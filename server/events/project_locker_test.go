@@ -14,6 +14,7 @@
 package events_test
 
 import (
+	"errors"
 	"fmt"
 	"testing"
 
@@ -25,6 +26,7 @@ import (
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/logging"
 	. "github.com/runatlantis/atlantis/testing"
+	tally "github.com/uber-go/tally/v4"
 )
 
 func TestDefaultProjectLocker_TryLockWhenLocked(t *testing.T) {
@@ -101,6 +103,48 @@ func TestDefaultProjectLocker_TryLockWhenLockedSamePull(t *testing.T) {
 	mockLocker.VerifyWasCalledOnce().Unlock(lockKey)
 }
 
+func TestDefaultProjectLocker_TryLockRecordsDuration(t *testing.T) {
+	RegisterMockTestingT(t)
+	var githubClient *vcs.GithubClient
+	mockClient := vcs.NewClientProxy(githubClient, nil, nil, nil, nil, nil)
+	mockLocker := mocks.NewMockLocker()
+	scope := tally.NewTestScope("project_lock", nil)
+	locker := events.DefaultProjectLocker{
+		Locker:    mockLocker,
+		VCSClient: mockClient,
+		Scope:     scope,
+	}
+	expProject := models.Project{}
+	expWorkspace := "default"
+	expUser := models.User{}
+
+	acquiredPull := models.PullRequest{Num: 1}
+	When(mockLocker.TryLock(expProject, expWorkspace, acquiredPull, expUser)).ThenReturn(
+		locking.TryLockResponse{LockAcquired: true, CurrLock: models.ProjectLock{Pull: acquiredPull}, LockKey: "key"}, nil)
+	blockedPull := models.PullRequest{Num: 2}
+	When(mockLocker.TryLock(expProject, expWorkspace, blockedPull, expUser)).ThenReturn(
+		locking.TryLockResponse{LockAcquired: false, CurrLock: models.ProjectLock{Pull: acquiredPull}}, nil)
+	errorPull := models.PullRequest{Num: 3}
+	When(mockLocker.TryLock(expProject, expWorkspace, errorPull, expUser)).ThenReturn(
+		locking.TryLockResponse{}, errors.New("db unavailable"))
+
+	for _, pull := range []models.PullRequest{acquiredPull, blockedPull, blockedPull, errorPull} {
+		locker.TryLock(logging.NewNoopLogger(t), pull, expUser, expWorkspace, expProject, true) // nolint: errcheck
+	}
+
+	samples := make(map[string]int64)
+	for name, h := range scope.Snapshot().Histograms() {
+		for _, count := range h.Durations() {
+			samples[name] += count
+		}
+	}
+	Equals(t, map[string]int64{
+		"project_lock.execution_time+result=acquired": 1,
+		"project_lock.execution_time+result=blocked":  2,
+		"project_lock.execution_time+result=error":    1,
+	}, samples)
+}
+
 func TestDefaultProjectLocker_TryLockUnlocked(t *testing.T) {
 	RegisterMockTestingT(t)
 	var githubClient *vcs.GithubClient
@@ -17,15 +17,21 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"os"
+	"path/filepath"
 	"sort"
 	"strings"
 	"text/template"
 
 	"github.com/runatlantis/atlantis/server/logging"
 
+	"github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/db"
 	"github.com/runatlantis/atlantis/server/core/locking"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	"github.com/runatlantis/atlantis/server/core/terraform/tfclient"
+	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/models"
 	"github.com/runatlantis/atlantis/server/events/vcs"
 	"github.com/runatlantis/atlantis/server/jobs"
@@ -55,6 +61,10 @@ type PullClosedExecutor struct {
 	Database                 db.Database
 	PullClosedTemplate       PullCleanupTemplate
 	LogStreamResourceCleaner ResourceCleaner
+	// TerraformClient deletes the workspaces Atlantis created for the pull
+	// with TerraformDistribution's default version.
+	TerraformClient       tfclient.Client
+	TerraformDistribution terraform.Distribution
 }
 
 type templatedProject struct {
@@ -97,6 +107,7 @@ func (p *PullClosedExecutor) CleanUpPull(logger logging.SimpleLogging, repo mode
 			}
 			p.LogStreamResourceCleaner.CleanUp(jobContext)
 		}
+		p.deleteCreatedWorkspaces(logger, repo, pull, pullStatus.CreatedWorkspaces)
 	}
 
 	if err := p.WorkingDir.Delete(logger, repo, pull); err != nil {
@@ -126,7 +137,61 @@ func (p *PullClosedExecutor) CleanUpPull(logger logging.SimpleLogging, repo mode
 	if err = pullClosedTemplate.Execute(&buf, templateData); err != nil {
 		return errors.Wrap(err, "rendering template for comment")
 	}
-	return p.VCSClient.CreateComment(logger, repo, pull.Num, buf.String(), "")
+	_, err = p.VCSClient.CreateComment(logger, repo, pull.Num, buf.String(), "")
+	return err
+}
+
+// deleteCreatedWorkspaces deletes the Terraform workspaces Atlantis created
+// for pull with the Terraform version and distribution they were created with.
+// Failures are logged so they don't block the rest of the cleanup. Workspaces
+// that still contain resources aren't deleted by Terraform.
+func (p *PullClosedExecutor) deleteCreatedWorkspaces(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, created []models.CreatedWorkspace) {
+	for _, w := range created {
+		cloneDir, err := p.WorkingDir.GetWorkingDir(repo, pull, w.Workspace)
+		if err != nil {
+			logger.Err("unable to delete workspace %q in dir %q: %s", w.Workspace, w.RepoRelDir, err)
+			continue
+		}
+		var tfVersion *version.Version
+		if w.TerraformVersion != "" {
+			if tfVersion, err = version.NewVersion(w.TerraformVersion); err != nil {
+				logger.Err("unable to delete workspace %q in dir %q, parsing terraform version %q: %s", w.Workspace, w.RepoRelDir, w.TerraformVersion, err)
+				continue
+			}
+		}
+		tfDistribution := p.TerraformDistribution
+		if w.TerraformDistribution != "" {
+			tfDistribution = terraform.NewDistribution(w.TerraformDistribution)
+		}
+		path := filepath.Join(cloneDir, w.RepoRelDir)
+		ctx := command.ProjectContext{
+			Log:        logger,
+			BaseRepo:   repo,
+			Pull:       pull,
+			RepoRelDir: w.RepoRelDir,
+			Workspace:  w.Workspace,
+		}
+		run := func(args ...string) (string, error) {
+			return p.TerraformClient.RunCommandWithVersion(ctx, path, args, map[string]string{}, tfDistribution, tfVersion, w.Workspace)
+		}
+		// Workspace commands need the backend so the clone has to be
+		// initialized, which it isn't if it was re-cloned since the plan.
+		if _, err := os.Stat(filepath.Join(path, ".terraform")); os.IsNotExist(err) {
+			if out, err := run("init", "-input=false", "-no-color"); err != nil {
+				logger.Err("unable to delete workspace %q in dir %q, running init: %s: %s", w.Workspace, w.RepoRelDir, err, out)
+				continue
+			}
+		}
+		if out, err := run("workspace", "select", "default"); err != nil {
+			logger.Err("unable to delete workspace %q in dir %q, selecting the default workspace: %s: %s", w.Workspace, w.RepoRelDir, err, out)
+			continue
+		}
+		if out, err := run("workspace", "delete", w.Workspace); err != nil {
+			logger.Err("unable to delete workspace %q in dir %q: %s: %s", w.Workspace, w.RepoRelDir, err, out)
+			continue
+		}
+		logger.Info("deleted workspace %q in dir %q created for this pull request", w.Workspace, w.RepoRelDir)
+	}
 }
 
 // buildTemplateData formats the lock data into a slice that can easily be
@@ -15,10 +15,14 @@ package events_test
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
 
+	version "github.com/hashicorp/go-version"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/core/boltdb"
+	"github.com/runatlantis/atlantis/server/core/terraform"
+	tfclientmocks "github.com/runatlantis/atlantis/server/core/terraform/tfclient/mocks"
 	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/logging"
 	"github.com/stretchr/testify/assert"
@@ -392,3 +396,74 @@ func TestCleanUpPullWithCorrectJobContext(t *testing.T) {
 	}
 	Equals(t, expectedPullInfo2, capturedArgs[1])
 }
+
+func TestCleanUpPullDeletesCreatedWorkspaces(t *testing.T) {
+	t.Log("CleanUpPull should delete the workspaces Atlantis created and keep cleaning up if that fails")
+	RegisterMockTestingT(t)
+	logger := logging.NewNoopLogger(t)
+	workingDir := mocks.NewMockWorkingDir()
+	locker := lockmocks.NewMockLocker()
+	terraformClient := tfclientmocks.NewMockClient()
+	tmp := t.TempDir()
+	db, err := boltdb.New(tmp)
+	t.Cleanup(func() {
+		db.Close()
+	})
+	Ok(t, err)
+
+	_, err = db.UpdatePullWithResults(testdata.Pull, []command.ProjectResult{
+		{
+			Command:     command.Plan,
+			RepoRelDir:  "project1",
+			Workspace:   "pr-1",
+			PlanSuccess: &models.PlanSuccess{CreatedWorkspace: "pr-1", TerraformVersion: "1.5.7"},
+		},
+		{
+			Command:     command.Plan,
+			RepoRelDir:  "project2",
+			Workspace:   "pr-1",
+			PlanSuccess: &models.PlanSuccess{CreatedWorkspace: "pr-1"},
+		},
+		{
+			Command:     command.Plan,
+			RepoRelDir:  "project2",
+			Workspace:   "default",
+			PlanSuccess: &models.PlanSuccess{},
+		},
+	})
+	Ok(t, err)
+
+	pce := events.PullClosedExecutor{
+		Locker:                   locker,
+		VCSClient:                vcsmocks.NewMockClient(),
+		WorkingDir:               workingDir,
+		Database:                 db,
+		PullClosedTemplate:       &events.PullClosedEventTemplate{},
+		LogStreamResourceCleaner: mocks.NewMockResourceCleaner(),
+		TerraformClient:          terraformClient,
+	}
+	// project1 is still initialized, project2 was re-cloned since its plan.
+	cloneDir := t.TempDir()
+	Ok(t, os.MkdirAll(filepath.Join(cloneDir, "project1", ".terraform"), 0700))
+	Ok(t, os.MkdirAll(filepath.Join(cloneDir, "project2"), 0700))
+	project1, project2 := filepath.Join(cloneDir, "project1"), filepath.Join(cloneDir, "project2")
+	When(workingDir.GetWorkingDir(testdata.GithubRepo, testdata.Pull, "pr-1")).ThenReturn(cloneDir, nil)
+	deleteArgs := []string{"workspace", "delete", "pr-1"}
+	When(terraformClient.RunCommandWithVersion(Any[command.ProjectContext](), Eq(project1), Eq(deleteArgs), Any[map[string]string](), Any[terraform.Distribution](), Any[*version.Version](), Eq("pr-1"))).
+		ThenReturn("Workspace \"pr-1\" is not empty.", errors.New("exit status 1"))
+	When(locker.UnlockByPull(testdata.GithubRepo.FullName, testdata.Pull.Num)).ThenReturn(nil, nil)
+
+	err = pce.CleanUpPull(logger, testdata.GithubRepo, testdata.Pull)
+	Ok(t, err)
+
+	// project1 is deleted with the version it was planned with.
+	v157 := version.Must(version.NewVersion("1.5.7"))
+	terraformClient.VerifyWasCalledOnce().RunCommandWithVersion(Any[command.ProjectContext](), Eq(project1), Eq([]string{"workspace", "select", "default"}), Any[map[string]string](), Any[terraform.Distribution](), Eq(v157), Eq("pr-1"))
+	terraformClient.VerifyWasCalledOnce().RunCommandWithVersion(Any[command.ProjectContext](), Eq(project1), Eq(deleteArgs), Any[map[string]string](), Any[terraform.Distribution](), Eq(v157), Eq("pr-1"))
+	// project2 is initialized first.
+	terraformClient.VerifyWasCalledOnce().RunCommandWithVersion(Any[command.ProjectContext](), Eq(project2), Eq([]string{"init", "-input=false", "-no-color"}), Any[map[string]string](), Any[terraform.Distribution](), Any[*version.Version](), Eq("pr-1"))
+	terraformClient.VerifyWasCalledOnce().RunCommandWithVersion(Any[command.ProjectContext](), Eq(project2), Eq([]string{"workspace", "select", "default"}), Any[map[string]string](), Any[terraform.Distribution](), Any[*version.Version](), Eq("pr-1"))
+	terraformClient.VerifyWasCalledOnce().RunCommandWithVersion(Any[command.ProjectContext](), Eq(project2), Eq(deleteArgs), Any[map[string]string](), Any[terraform.Distribution](), Any[*version.Version](), Eq("pr-1"))
+	terraformClient.VerifyWasCalled(Times(5)).RunCommandWithVersion(Any[command.ProjectContext](), Any[string](), Any[[]string](), Any[map[string]string](), Any[terraform.Distribution](), Any[*version.Version](), Any[string]())
+	workingDir.VerifyWasCalledOnce().Delete(logger, testdata.GithubRepo, testdata.Pull)
+}
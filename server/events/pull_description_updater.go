@@ -0,0 +1,171 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+const (
+	// planLinksStartMarker and planLinksEndMarker delimit the section of the
+	// pull request description that Atlantis maintains.
+	planLinksStartMarker = "<!-- atlantis-plan-links:start -->"
+	planLinksEndMarker   = "<!-- atlantis-plan-links:end -->"
+	planLinksHeader      = "### Atlantis Plans"
+	// maxPullDescriptionLength is the maximum number of characters in a pull
+	// request description. GitHub's limit is used since it's the smallest of
+	// the supported VCS hosts.
+	maxPullDescriptionLength = 65536
+)
+
+// updatePullDescription maintains a section of the pull request description
+// listing each project with a link to its latest plan output. Lines for
+// projects that weren't part of this run are kept as long as the project is
+// still in pullStatus. commentURL is the URL of the comment with the plans,
+// if it's empty the projects link to their job output instead.
+func (c *PullUpdater) updatePullDescription(ctx *command.Context, projectCmds []command.ProjectContext, results []command.ProjectResult, pullStatus models.PullStatus, commentURL string) {
+	if !c.PullDescriptionPlanLinks || len(results) == 0 {
+		return
+	}
+
+	description, err := c.VCSClient.GetPullDescription(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull)
+	if err != nil {
+		ctx.Log.Warn("unable to get pull request description: %s", err)
+		return
+	}
+
+	lines := parsePlanLinks(description)
+	tracked := make(map[string]bool)
+	for _, p := range pullStatus.Projects {
+		tracked[planLinkKey(p.RepoRelDir, p.Workspace, p.ProjectName)] = true
+	}
+	for key := range lines {
+		if !tracked[key] {
+			delete(lines, key)
+		}
+	}
+	for _, result := range results {
+		key := planLinkKey(result.RepoRelDir, result.Workspace, result.ProjectName)
+		lines[key] = c.planLinkLine(ctx, key, result, projectCmds, commentURL)
+	}
+
+	updated, ok := renderPlanLinks(description, lines)
+	if !ok {
+		ctx.Log.Warn("not updating pull request description with plan links since it would exceed %d characters", maxPullDescriptionLength)
+		return
+	}
+	if updated == description {
+		ctx.Log.Debug("pull request description plan links are up to date")
+		return
+	}
+	if err := c.VCSClient.UpdatePullDescription(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull, updated); err != nil {
+		ctx.Log.Warn("unable to update pull request description: %s", err)
+	}
+}
+
+func (c *PullUpdater) planLinkLine(ctx *command.Context, key string, result command.ProjectResult, projectCmds []command.ProjectContext, commentURL string) string {
+	var status string
+	switch {
+	case result.PlanSuccess == nil:
+		status = "errored"
+	case result.PlanSuccess.NoChanges():
+		status = "no changes"
+	default:
+		status = "has changes"
+	}
+
+	url := commentURL
+	if url == "" && c.JobURLGenerator != nil {
+		for _, cmd := range projectCmds {
+			if cmd.RepoRelDir == result.RepoRelDir && cmd.Workspace == result.Workspace && cmd.ProjectName == result.ProjectName {
+				var err error
+				if url, err = c.JobURLGenerator.GenerateProjectJobURL(cmd); err != nil {
+					ctx.Log.Warn("unable to generate job url for project %q: %s", key, err)
+				}
+				break
+			}
+		}
+	}
+	if url == "" {
+		return fmt.Sprintf("- %s: %s", key, status)
+	}
+	return fmt.Sprintf("- %s: [%s](%s)", key, status, url)
+}
+
+// planLinkKey identifies a project's line in the plan links section.
+func planLinkKey(repoRelDir string, workspace string, projectName string) string {
+	if projectName != "" {
+		return fmt.Sprintf("`%s`", projectName)
+	}
+	return fmt.Sprintf("`%s` (workspace `%s`)", repoRelDir, workspace)
+}
+
+// parsePlanLinks returns the project lines in description's plan links
+// section keyed by planLinkKey.
+func parsePlanLinks(description string) map[string]string {
+	lines := make(map[string]string)
+	start := strings.Index(description, planLinksStartMarker)
+	end := strings.Index(description, planLinksEndMarker)
+	if start < 0 || end < start {
+		return lines
+	}
+	for _, line := range strings.Split(description[start+len(planLinksStartMarker):end], "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "- ") {
+			continue
+		}
+		if i := strings.LastIndex(line, ": "); i > 0 {
+			lines[line[len("- "):i]] = line
+		}
+	}
+	return lines
+}
+
+// renderPlanLinks returns description with its plan links section replaced
+// by lines, or appended if it doesn't have one. If the description would be
+// too long, lines are dropped and replaced with a count. ok is false if even
+// that doesn't fit.
+func renderPlanLinks(description string, lines map[string]string) (string, bool) {
+	var before, after string
+	start := strings.Index(description, planLinksStartMarker)
+	end := strings.Index(description, planLinksEndMarker)
+	if start >= 0 && end > start {
+		before = description[:start]
+		after = description[end+len(planLinksEndMarker):]
+	} else {
+		before = description
+		if before != "" && !strings.HasSuffix(before, "\n\n") {
+			before = strings.TrimRight(before, "\n") + "\n\n"
+		}
+	}
+
+	var keys []string
+	for key := range lines {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for shown := len(keys); shown >= 0; shown-- {
+		var section strings.Builder
+		section.WriteString(planLinksStartMarker + "\n" + planLinksHeader + "\n\n")
+		for _, key := range keys[:shown] {
+			section.WriteString(lines[key] + "\n")
+		}
+		if hidden := len(keys) - shown; hidden > 0 {
+			fmt.Fprintf(&section, "- ...and %d more project(s)\n", hidden)
+		}
+		section.WriteString(planLinksEndMarker)
+
+		updated := before + section.String() + after
+		if len(updated) <= maxPullDescriptionLength {
+			return updated, true
+		}
+	}
+	return "", false
+}
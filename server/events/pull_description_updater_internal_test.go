@@ -0,0 +1,153 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	jobmocks "github.com/runatlantis/atlantis/server/jobs/mocks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestPullUpdater_UpdatePullDescription(t *testing.T) {
+	projectCmds := []command.ProjectContext{
+		{ProjectName: "app", RepoRelDir: "app", Workspace: "default", JobID: "app-job"},
+		{RepoRelDir: "network", Workspace: "staging", JobID: "network-job"},
+		{ProjectName: "dns", RepoRelDir: "dns", Workspace: "default", JobID: "dns-job"},
+	}
+	results := []command.ProjectResult{
+		{ProjectName: "app", RepoRelDir: "app", Workspace: "default", PlanSuccess: &models.PlanSuccess{TerraformOutput: "Plan: 1 to add, 0 to change, 0 to destroy."}},
+		{RepoRelDir: "network", Workspace: "staging", PlanSuccess: &models.PlanSuccess{TerraformOutput: "No changes. Your infrastructure matches the configuration."}},
+		{ProjectName: "dns", RepoRelDir: "dns", Workspace: "default", Error: errors.New("error")},
+	}
+	pullStatus := models.PullStatus{
+		Projects: []models.ProjectStatus{
+			{ProjectName: "app", RepoRelDir: "app", Workspace: "default"},
+			{RepoRelDir: "network", Workspace: "staging"},
+			{ProjectName: "dns", RepoRelDir: "dns", Workspace: "default"},
+			{ProjectName: "cache", RepoRelDir: "cache", Workspace: "default"},
+		},
+	}
+	commentURL := "https://github.com/runatlantis/atlantis/pull/1#issuecomment-1"
+	expSection := `<!-- atlantis-plan-links:start -->
+### Atlantis Plans
+
+- ` + "`app`" + `: [has changes](` + commentURL + `)
+- ` + "`cache`" + `: [no changes](https://atlantis/jobs/old-cache-job)
+- ` + "`dns`" + `: [errored](` + commentURL + `)
+- ` + "`network` (workspace `staging`)" + `: [no changes](` + commentURL + `)
+<!-- atlantis-plan-links:end -->`
+	oldSection := `<!-- atlantis-plan-links:start -->
+### Atlantis Plans
+
+- ` + "`app`" + `: [errored](https://atlantis/jobs/old-app-job)
+- ` + "`cache`" + `: [no changes](https://atlantis/jobs/old-cache-job)
+- ` + "`removed`" + `: [has changes](https://atlantis/jobs/old-removed-job)
+<!-- atlantis-plan-links:end -->`
+
+	cases := []struct {
+		description string
+		existing    string
+		commentURL  string
+		exp         string
+	}{
+		{
+			description: "empty description",
+			existing:    "",
+			commentURL:  commentURL,
+			exp:         strings.Replace(expSection, "- `cache`: [no changes](https://atlantis/jobs/old-cache-job)\n", "", 1),
+		},
+		{
+			description: "section is appended",
+			existing:    "Adds a cache.\n",
+			commentURL:  commentURL,
+			exp:         "Adds a cache.\n\n" + strings.Replace(expSection, "- `cache`: [no changes](https://atlantis/jobs/old-cache-job)\n", "", 1),
+		},
+		{
+			description: "section is replaced, keeping projects that weren't planned",
+			existing:    "Adds a cache.\n\n" + oldSection + "\n\nFooter",
+			commentURL:  commentURL,
+			exp:         "Adds a cache.\n\n" + expSection + "\n\nFooter",
+		},
+		{
+			description: "up to date section isn't updated",
+			existing:    "Adds a cache.\n\n" + expSection,
+			commentURL:  commentURL,
+			exp:         "",
+		},
+		{
+			description: "without a comment url projects link to their job",
+			existing:    "Adds a cache.\n\n" + oldSection,
+			exp: "Adds a cache.\n\n" + strings.NewReplacer(
+				"[has changes]("+commentURL+")", "[has changes](https://atlantis/jobs/app-job)",
+				"[errored]("+commentURL+")", "[errored](https://atlantis/jobs/dns-job)",
+				"[no changes]("+commentURL+")", "[no changes](https://atlantis/jobs/network-job)",
+			).Replace(expSection),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			vcsClient := vcsmocks.NewMockClient()
+			When(vcsClient.GetPullDescription(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest]())).ThenReturn(c.existing, nil)
+			jobURLGenerator := jobmocks.NewMockProjectJobURLGenerator()
+			for _, cmd := range projectCmds {
+				When(jobURLGenerator.GenerateProjectJobURL(Eq(cmd))).ThenReturn("https://atlantis/jobs/"+cmd.JobID, nil)
+			}
+			updater := &PullUpdater{
+				VCSClient:                vcsClient,
+				PullDescriptionPlanLinks: true,
+				JobURLGenerator:          jobURLGenerator,
+			}
+			ctx := &command.Context{
+				Log:  logging.NewNoopLogger(t),
+				Pull: models.PullRequest{Num: 1},
+			}
+
+			updater.updatePullDescription(ctx, projectCmds, results, pullStatus, c.commentURL)
+
+			if c.exp == "" {
+				vcsClient.VerifyWasCalled(Never()).UpdatePullDescription(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](), Any[string]())
+				return
+			}
+			_, _, _, description := vcsClient.VerifyWasCalledOnce().UpdatePullDescription(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](), Any[string]()).GetCapturedArguments()
+			Equals(t, c.exp, description)
+			if c.commentURL != "" {
+				jobURLGenerator.VerifyWasCalled(Never()).GenerateProjectJobURL(Any[command.ProjectContext]())
+			}
+		})
+	}
+}
+
+func TestPullUpdater_UpdatePullDescription_Disabled(t *testing.T) {
+	RegisterMockTestingT(t)
+	vcsClient := vcsmocks.NewMockClient()
+	updater := &PullUpdater{VCSClient: vcsClient}
+	ctx := &command.Context{Log: logging.NewNoopLogger(t)}
+	updater.updatePullDescription(ctx, nil, []command.ProjectResult{{RepoRelDir: "."}}, models.PullStatus{}, "")
+	vcsClient.VerifyWasCalled(Never()).GetPullDescription(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest]())
+}
+
+func TestRenderPlanLinks_Truncated(t *testing.T) {
+	url := "https://atlantis/jobs/" + strings.Repeat("0", 80)
+	lines := map[string]string{
+		"`a`": "- `a`: [has changes](" + url + ")",
+		"`b`": "- `b`: [has changes](" + url + ")",
+	}
+	description := strings.Repeat("x", maxPullDescriptionLength-250)
+	updated, ok := renderPlanLinks(description, lines)
+	Assert(t, ok, "expected description to fit")
+	Assert(t, len(updated) <= maxPullDescriptionLength, "expected description to be truncated")
+	Assert(t, strings.Contains(updated, "- `a`: [has changes]("+url+")\n- ...and 1 more project(s)\n"), "got %q", updated[len(description):])
+
+	_, ok = renderPlanLinks(strings.Repeat("x", maxPullDescriptionLength), lines)
+	Assert(t, !ok, "expected description not to fit")
+}
@@ -4,29 +4,50 @@
 package events
 
 import (
+	"path"
+	"strings"
+
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/events/vcs"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/utils"
 )
 
 type PullUpdater struct {
 	HidePrevPlanComments bool
-	VCSClient            vcs.Client
-	MarkdownRenderer     *MarkdownRenderer
+	// PlanReviewComments posts each project's plan as a review comment on a
+	// file it modified if the VCS supports it.
+	PlanReviewComments bool
+	VCSClient          vcs.Client
+	MarkdownRenderer   *MarkdownRenderer
+	// PullDescriptionPlanLinks maintains a section in the pull request
+	// description linking to each project's plan.
+	PullDescriptionPlanLinks bool
+	// PlanSummaryComment posts a comment summarizing the resource changes of
+	// every project's latest plan after each plan.
+	PlanSummaryComment bool
+	JobURLGenerator    jobs.ProjectJobURLGenerator
+	// Notifier, if set, is sent a summary of the results of plan and apply
+	// commands.
+	Notifier webhooks.CommandNotifier
 }
 
-func (c *PullUpdater) updatePull(ctx *command.Context, cmd PullCommand, res command.Result) {
+// updatePull comments the result of cmd on the pull request. It returns the
+// URL of the comment, or "" if there wasn't one or the VCS didn't return it.
+func (c *PullUpdater) updatePull(ctx *command.Context, cmd PullCommand, res command.Result) string {
 	// Log if we got any errors or failures.
 	if res.Error != nil {
 		ctx.Log.Err(res.Error.Error())
 	} else if res.Failure != "" {
 		ctx.Log.Warn(res.Failure)
 	}
+	c.notify(ctx, cmd, res)
 
 	// HidePrevCommandComments will hide old comments left from previous runs to reduce
 	// clutter in a pull/merge request. This will not delete the comment, since the
 	// comment trail may be useful in auditing or backtracing problems.
-	if c.HidePrevPlanComments {
+	if c.hidePrevPlanComments(res.ProjectResults) {
 		ctx.Log.Debug("hiding previous plan comments for command: '%v', directory: '%v'", cmd.CommandName().TitleString(), cmd.Dir())
 		if err := c.VCSClient.HidePrevCommandComments(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, cmd.CommandName().TitleString(), cmd.Dir()); err != nil {
 			ctx.Log.Err("unable to hide old comments: %s", err)
@@ -44,14 +65,116 @@ func (c *PullUpdater) updatePull(ctx *command.Context, cmd PullCommand, res comm
 		}
 
 		if len(commentOnProjects) == 0 {
-			return
+			return ""
 		}
 
 		res.ProjectResults = commentOnProjects
 	}
 
-	comment := c.MarkdownRenderer.Render(ctx, res, cmd)
-	if err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, comment, cmd.CommandName().String()); err != nil {
+	var reviewCommentPaths []string
+	if c.PlanReviewComments && cmd.CommandName() == command.Plan && res.Error == nil && res.Failure == "" {
+		reviewCommentPaths = c.createPlanReviewComments(ctx, cmd, res)
+	}
+	comment := c.MarkdownRenderer.RenderWithReviewComments(ctx, res, cmd, reviewCommentPaths)
+	url, err := c.VCSClient.CreateComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull.Num, comment, cmd.CommandName().String())
+	if err != nil {
 		ctx.Log.Err("unable to comment: %s", err)
 	}
+	return url
+}
+
+// notify sends the summary of the result of plan and apply commands to the
+// Notifier.
+func (c *PullUpdater) notify(ctx *command.Context, cmd PullCommand, res command.Result) {
+	if c.Notifier == nil || (cmd.CommandName() != command.Plan && cmd.CommandName() != command.Apply) {
+		return
+	}
+	result := webhooks.CommandResult{
+		Command: cmd.CommandName().String(),
+		Repo:    ctx.Pull.BaseRepo,
+		Pull:    ctx.Pull,
+		User:    ctx.User,
+		Success: !res.HasErrors(),
+	}
+	for _, p := range res.ProjectResults {
+		result.Projects = append(result.Projects, webhooks.ProjectCommandResult{
+			ProjectName: p.ProjectName,
+			Directory:   p.RepoRelDir,
+			Workspace:   p.Workspace,
+			Success:     p.IsSuccessful(),
+		})
+	}
+	if err := c.Notifier.Notify(ctx.Log, result); err != nil {
+		ctx.Log.Warn("unable to send %s notification: %s", result.Command, err)
+	}
+}
+
+// createPlanReviewComments posts the plan of each project with changes as a
+// review comment on a file modified in the project's dir. It returns the file
+// for each of res.ProjectResults. Plans that weren't posted, e.g. since the VCS
+// doesn't support review comments, have no file and stay in the pull request
+// comment.
+func (c *PullUpdater) createPlanReviewComments(ctx *command.Context, cmd PullCommand, res command.Result) []string {
+	if !c.VCSClient.SupportsReviewComments(ctx.Pull.BaseRepo) {
+		ctx.Log.Debug("review comments aren't supported, posting plans in the pull request comment")
+		return nil
+	}
+	modifiedFiles, err := c.VCSClient.GetModifiedFiles(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull)
+	if err != nil {
+		ctx.Log.Warn("unable to get modified files to post plans as review comments: %s", err)
+		return nil
+	}
+
+	paths := make([]string, len(res.ProjectResults))
+	for i, result := range res.ProjectResults {
+		if result.PlanSuccess == nil || result.PlanSuccess.NoChanges() {
+			continue
+		}
+		path := firstFileInDir(modifiedFiles, result.RepoRelDir)
+		if path == "" {
+			continue
+		}
+		projectRes := command.Result{ProjectResults: []command.ProjectResult{result}, PlansDeleted: res.PlansDeleted}
+		comment := c.MarkdownRenderer.Render(ctx, projectRes, cmd)
+		if err := c.VCSClient.CreateReviewComment(ctx.Log, ctx.Pull.BaseRepo, ctx.Pull, path, comment, cmd.CommandName().String()); err != nil {
+			ctx.Log.Warn("unable to post plan for dir %q workspace %q as a review comment: %s", result.RepoRelDir, result.Workspace, err)
+			continue
+		}
+		paths[i] = path
+	}
+	return paths
+}
+
+// firstFileInDir returns the first of files that's in dir or one of its
+// subdirectories.
+func firstFileInDir(files []string, dir string) string {
+	dir = path.Clean(dir)
+	for _, file := range files {
+		if dir == "." || strings.HasPrefix(path.Clean(file), dir+"/") {
+			return file
+		}
+	}
+	return ""
+}
+
+// hidePrevPlanComments returns whether previous comments should be hidden.
+// A project's hide_prev_plan_comments setting overrides the server's
+// --hide-prev-plan-comments flag, so comments are hidden if any of the
+// projects in results wants them hidden.
+func (c *PullUpdater) hidePrevPlanComments(results []command.ProjectResult) bool {
+	if len(results) == 0 {
+		return c.HidePrevPlanComments
+	}
+	for _, result := range results {
+		if result.HidePrevPlanComments != nil {
+			if *result.HidePrevPlanComments {
+				return true
+			}
+			continue
+		}
+		if c.HidePrevPlanComments {
+			return true
+		}
+	}
+	return false
 }
@@ -0,0 +1,195 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/petergtz/pegomock/v4"
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+	vcsmocks "github.com/runatlantis/atlantis/server/events/vcs/mocks"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	webhookmocks "github.com/runatlantis/atlantis/server/events/webhooks/mocks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestPullUpdater_HidePrevPlanComments(t *testing.T) {
+	enabled := true
+	disabled := false
+	cases := []struct {
+		description string
+		global      bool
+		results     []command.ProjectResult
+		exp         bool
+	}{
+		{
+			description: "global flag off and no projects",
+			exp:         false,
+		},
+		{
+			description: "global flag on and no projects",
+			global:      true,
+			exp:         true,
+		},
+		{
+			description: "global flag on and project unset",
+			global:      true,
+			results:     []command.ProjectResult{{RepoRelDir: "."}},
+			exp:         true,
+		},
+		{
+			description: "global flag off and project unset",
+			results:     []command.ProjectResult{{RepoRelDir: "."}},
+			exp:         false,
+		},
+		{
+			description: "project enables it",
+			results:     []command.ProjectResult{{RepoRelDir: "a"}, {RepoRelDir: "b", HidePrevPlanComments: &enabled}},
+			exp:         true,
+		},
+		{
+			description: "project disables it",
+			global:      true,
+			results:     []command.ProjectResult{{RepoRelDir: ".", HidePrevPlanComments: &disabled}},
+			exp:         false,
+		},
+		{
+			description: "another project uses the global flag",
+			global:      true,
+			results:     []command.ProjectResult{{RepoRelDir: "a", HidePrevPlanComments: &disabled}, {RepoRelDir: "b"}},
+			exp:         true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			updater := &PullUpdater{HidePrevPlanComments: c.global}
+			Equals(t, c.exp, updater.hidePrevPlanComments(c.results))
+		})
+	}
+}
+
+func TestPullUpdater_PlanReviewComments(t *testing.T) {
+	planResult := func(dir string, output string) command.ProjectResult {
+		return command.ProjectResult{
+			RepoRelDir: dir,
+			Workspace:  "default",
+			PlanSuccess: &models.PlanSuccess{
+				TerraformOutput: output,
+				LockURL:         "lock-url",
+				RePlanCmd:       "atlantis plan -d " + dir,
+				ApplyCmd:        "atlantis apply -d " + dir,
+			},
+		}
+	}
+	results := []command.ProjectResult{
+		planResult("a", "Plan: 1 to add, 0 to change, 0 to destroy."),
+		// There are no modified files in this dir so its plan stays in the
+		// pull request comment.
+		planResult("b", "Plan: 2 to add, 0 to change, 0 to destroy."),
+		{RepoRelDir: "c", Workspace: "default", Error: errors.New("plan failed")},
+	}
+
+	cases := []struct {
+		description     string
+		supported       bool
+		reviewErr       error
+		expReviewed     bool
+		expInPullOutput []string
+	}{
+		{
+			description:     "supported",
+			supported:       true,
+			expReviewed:     true,
+			expInPullOutput: []string{"Output posted as a review comment on `a/main.tf`.", "Plan: 2 to add", "plan failed"},
+		},
+		{
+			description:     "not supported",
+			expInPullOutput: []string{"Plan: 1 to add", "Plan: 2 to add", "plan failed"},
+		},
+		{
+			description:     "review comment fails",
+			supported:       true,
+			reviewErr:       errors.New("unprocessable entity"),
+			expInPullOutput: []string{"Plan: 1 to add", "Plan: 2 to add", "plan failed"},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			RegisterMockTestingT(t)
+			vcsClient := vcsmocks.NewMockClient()
+			When(vcsClient.SupportsReviewComments(Any[models.Repo]())).ThenReturn(c.supported)
+			When(vcsClient.GetModifiedFiles(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest]())).
+				ThenReturn([]string{"README.md", "a/main.tf", "c/main.tf"}, nil)
+			When(vcsClient.CreateReviewComment(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](), Any[string](), Any[string](), Any[string]())).
+				ThenReturn(c.reviewErr)
+			updater := &PullUpdater{
+				PlanReviewComments: true,
+				VCSClient:          vcsClient,
+				MarkdownRenderer:   NewMarkdownRenderer(false, false, false, false, false, false, "", "atlantis", false, false, 0),
+			}
+			ctx := &command.Context{
+				Log:  logging.NewNoopLogger(t),
+				Pull: models.PullRequest{Num: 1, BaseRepo: models.Repo{VCSHost: models.VCSHost{Type: models.Github}}},
+			}
+
+			updater.updatePull(ctx, AutoplanCommand{}, command.Result{ProjectResults: results})
+
+			if c.supported {
+				_, _, _, path, reviewComment, _ := vcsClient.VerifyWasCalledOnce().CreateReviewComment(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](), Any[string](), Any[string](), Any[string]()).GetCapturedArguments()
+				Equals(t, "a/main.tf", path)
+				Assert(t, strings.HasPrefix(reviewComment, "Ran Plan for dir: `a` workspace: `default`"), "got %q", reviewComment)
+				Assert(t, strings.Contains(reviewComment, "Plan: 1 to add"), "got %q", reviewComment)
+			} else {
+				vcsClient.VerifyWasCalled(Never()).CreateReviewComment(Any[logging.SimpleLogging](), Any[models.Repo](), Any[models.PullRequest](), Any[string](), Any[string](), Any[string]())
+			}
+			_, _, _, comment, _ := vcsClient.VerifyWasCalledOnce().CreateComment(Any[logging.SimpleLogging](), Any[models.Repo](), Any[int](), Any[string](), Any[string]()).GetCapturedArguments()
+			for _, exp := range c.expInPullOutput {
+				Assert(t, strings.Contains(comment, exp), "expected %q in %q", exp, comment)
+			}
+			Equals(t, !c.expReviewed, strings.Contains(comment, "Plan: 1 to add"))
+		})
+	}
+}
+
+func TestPullUpdater_Notify(t *testing.T) {
+	RegisterMockTestingT(t)
+	vcsClient := vcsmocks.NewMockClient()
+	notifier := webhookmocks.NewMockCommandNotifier()
+	updater := &PullUpdater{
+		VCSClient:        vcsClient,
+		MarkdownRenderer: NewMarkdownRenderer(false, false, false, false, false, false, "", "atlantis", false, false, 0),
+		Notifier:         notifier,
+	}
+	pull := models.PullRequest{Num: 1, URL: "https://github.com/owner/repo/pull/1", BaseRepo: models.Repo{FullName: "owner/repo"}}
+	ctx := &command.Context{
+		Log:  logging.NewNoopLogger(t),
+		Pull: pull,
+		User: models.User{Username: "user"},
+	}
+
+	updater.updatePull(ctx, AutoplanCommand{}, command.Result{ProjectResults: []command.ProjectResult{
+		{RepoRelDir: "a", Workspace: "default", PlanSuccess: &models.PlanSuccess{}},
+		{RepoRelDir: "b", Workspace: "default", ProjectName: "b", Error: errors.New("error")},
+	}})
+	_, result := notifier.VerifyWasCalledOnce().Notify(Any[logging.SimpleLogging](), Any[webhooks.CommandResult]()).GetCapturedArguments()
+	Equals(t, webhooks.CommandResult{
+		Command: "plan",
+		Repo:    pull.BaseRepo,
+		Pull:    pull,
+		User:    models.User{Username: "user"},
+		Success: false,
+		Projects: []webhooks.ProjectCommandResult{
+			{Directory: "a", Workspace: "default", Success: true},
+			{ProjectName: "b", Directory: "b", Workspace: "default", Success: false},
+		},
+	}, result)
+
+	// Only plan and apply are notified.
+	updater.updatePull(ctx, &CommentCommand{Name: command.Unlock}, command.Result{})
+	notifier.VerifyWasCalledOnce().Notify(Any[logging.SimpleLogging](), Any[webhooks.CommandResult]())
+}
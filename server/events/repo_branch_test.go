@@ -62,6 +62,13 @@ projects:
     autoplan:
       when_modified:
         - "**/*"
+  - name: release
+    branch: release/*
+    dir: terraform/release
+    workflow: production
+    autoplan:
+      when_modified:
+        - "**/*"
 `
 
 	tmp := t.TempDir()
@@ -84,6 +91,20 @@ projects:
 	require.NoError(t, err)
 
 	require.Len(t, repo.Projects, 1)
+	require.Equal(t, "development", repo.Projects[0].GetName())
+	require.Len(t, repo.BranchSkippedProjects, 2)
+	require.Equal(t, "production", repo.BranchSkippedProjects[0].GetName())
+	require.Equal(t, "release", repo.BranchSkippedProjects[1].GetName())
+
+	repo, err = parser.ParseRepoCfg(tmp, global, "github.com/foo/bar", "release/1.2")
+	require.NoError(t, err)
+	require.Len(t, repo.Projects, 1)
+	require.Equal(t, "release", repo.Projects[0].GetName())
+
+	// Globs don't match across slashes.
+	repo, err = parser.ParseRepoCfg(tmp, global, "github.com/foo/bar", "release/1.2/hotfix")
+	require.NoError(t, err)
+	require.Len(t, repo.Projects, 0)
 
 	t.Logf("Projects: %+v", repo.Projects)
 }
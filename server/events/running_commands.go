@@ -0,0 +1,81 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package events
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/runatlantis/atlantis/server/events/command"
+	"github.com/runatlantis/atlantis/server/events/models"
+)
+
+// RunningCommands tracks the commands that are running for each pull request
+// so they can be canceled by the cancel command.
+type RunningCommands struct {
+	mutex   sync.Mutex
+	running map[string][]*runningCommand
+}
+
+type runningCommand struct {
+	name     command.Name
+	canceled chan struct{}
+}
+
+// Start records that a name command started for pull. It returns a channel
+// that's closed when the command is canceled and a func that must be called
+// once the command finished.
+func (r *RunningCommands) Start(pull models.PullRequest, name command.Name) (<-chan struct{}, func()) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if r.running == nil {
+		r.running = make(map[string][]*runningCommand)
+	}
+	key := runningCommandsKey(pull)
+	cmd := &runningCommand{name: name, canceled: make(chan struct{})}
+	r.running[key] = append(r.running[key], cmd)
+
+	return cmd.canceled, func() {
+		r.mutex.Lock()
+		defer r.mutex.Unlock()
+
+		cmds := r.running[key]
+		for i, c := range cmds {
+			if c == cmd {
+				cmds = append(cmds[:i], cmds[i+1:]...)
+				break
+			}
+		}
+		if len(cmds) == 0 {
+			delete(r.running, key)
+		} else {
+			r.running[key] = cmds
+		}
+	}
+}
+
+// Cancel cancels the commands running for pull and returns their names. A
+// command is only canceled once, so commands that were canceled before but
+// are still finishing aren't returned.
+func (r *RunningCommands) Cancel(pull models.PullRequest) []command.Name {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var names []command.Name
+	for _, cmd := range r.running[runningCommandsKey(pull)] {
+		select {
+		case <-cmd.canceled:
+			continue
+		default:
+		}
+		close(cmd.canceled)
+		names = append(names, cmd.name)
+	}
+	return names
+}
+
+func runningCommandsKey(pull models.PullRequest) string {
+	return fmt.Sprintf("%s/%d", pull.BaseRepo.FullName, pull.Num)
+}
@@ -4,6 +4,7 @@
 package events
 
 import (
+	"fmt"
 	"slices"
 
 	"github.com/runatlantis/atlantis/server/events/command"
@@ -39,7 +40,7 @@ func (u *UnlockCommandRunner) Run(ctx *command.Context, _ *CommentCommand) {
 	disableUnlockLabel := u.DisableUnlockLabel
 
 	ctx.Log.Info("Unlocking all locks")
-	vcsMessage := "All Atlantis locks for this PR have been unlocked and plans discarded"
+	var vcsMessage string
 
 	var hasLabel bool
 	var err error
@@ -63,6 +64,8 @@ func (u *UnlockCommandRunner) Run(ctx *command.Context, _ *CommentCommand) {
 		if err != nil {
 			vcsMessage = "Failed to delete PR locks"
 			ctx.Log.Err("failed to delete locks by pull %s", err.Error())
+		} else {
+			vcsMessage = unlockedMessage(numLocks)
 		}
 	}
 
@@ -74,7 +77,20 @@ func (u *UnlockCommandRunner) Run(ctx *command.Context, _ *CommentCommand) {
 		}
 	}
 
-	if commentErr := u.vcsClient.CreateComment(ctx.Log, baseRepo, pullNum, vcsMessage, command.Unlock.String()); commentErr != nil {
+	if _, commentErr := u.vcsClient.CreateComment(ctx.Log, baseRepo, pullNum, vcsMessage, command.Unlock.String()); commentErr != nil {
 		ctx.Log.Err("unable to comment: %s", commentErr)
 	}
 }
+
+// unlockedMessage returns the comment reporting that numLocks locks of the
+// pull request were unlocked.
+func unlockedMessage(numLocks int) string {
+	switch numLocks {
+	case 0:
+		return "There were no Atlantis locks for this PR to unlock"
+	case 1:
+		return "1 Atlantis lock for this PR has been unlocked and its plan discarded"
+	default:
+		return fmt.Sprintf("All %d Atlantis locks for this PR have been unlocked and plans discarded", numLocks)
+	}
+}
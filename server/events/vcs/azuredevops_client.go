@@ -24,6 +24,10 @@ type AzureDevopsClient struct {
 	Client   *azuredevops.Client
 	ctx      context.Context
 	UserName string
+	// ThreadComments is true if plan and apply comments are posted as replies
+	// in the thread of the first plan comment of the pull request, rather
+	// than each starting a new thread.
+	ThreadComments bool
 }
 
 // NewAzureDevopsClient returns a valid Azure DevOps client.
@@ -113,8 +117,8 @@ func (g *AzureDevopsClient) GetModifiedFiles(logger logging.SimpleLogging, repo
 // CreateComment creates a comment on a pull request.
 //
 // If comment length is greater than the max comment length we split into
-// multiple comments.
-func (g *AzureDevopsClient) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) error { //nolint: revive
+// multiple comments. The returned URL links to the comment's thread.
+func (g *AzureDevopsClient) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) (string, error) { //nolint: revive
 	sepEnd := "\n```\n</details>" +
 		"\n<br>\n\n**Warning**: Output length greater than max comment size. Continued in next comment."
 	sepStart := "Continued from previous comment.\n<details><summary>Show Output</summary>\n\n" +
@@ -128,25 +132,107 @@ func (g *AzureDevopsClient) CreateComment(logger logging.SimpleLogging, repo mod
 	comments := common.SplitComment(comment, maxCommentLength, sepEnd, sepStart, 0, "")
 	owner, project, repoName := SplitAzureDevopsRepoFullName(repo.FullName)
 
+	threaded := g.ThreadComments && (command == "plan" || command == "apply")
+	threadID := 0
+	parentCommentID := 0
+	firstThreadID := 0
+	if threaded {
+		thread, err := g.planThread(owner, project, repoName, pullNum)
+		if err != nil {
+			return "", err
+		}
+		if thread != nil {
+			logger.Debug("replying in thread %d of Azure DevOps pull request %d", thread.ID, pullNum)
+			threadID = thread.ID
+			parentCommentID = thread.Comments[0].GetID()
+			firstThreadID = thread.ID
+		}
+	}
+
 	for i := range comments {
 		commentType := "text"
-		parentCommentID := 0
 
 		prComment := azuredevops.Comment{
 			CommentType:     &commentType,
 			Content:         &comments[i],
 			ParentCommentID: &parentCommentID,
 		}
+		if threadID != 0 {
+			_, _, err := g.Client.PullRequests.CreateComment(g.ctx, owner, project, repoName, pullNum, threadID, &prComment)
+			if err != nil {
+				return "", err
+			}
+			continue
+		}
+
 		prComments := []*azuredevops.Comment{&prComment}
 		body := azuredevops.GitPullRequestCommentThread{
 			Comments: prComments,
 		}
-		_, _, err := g.Client.PullRequests.CreateComments(g.ctx, owner, project, repoName, pullNum, &body)
+		thread, _, err := g.Client.PullRequests.CreateComments(g.ctx, owner, project, repoName, pullNum, &body)
 		if err != nil {
-			return err
+			return "", err
+		}
+		if i == 0 {
+			firstThreadID = thread.GetID()
+		}
+		// The rest of a split comment continues in the new thread.
+		if threaded && len(thread.Comments) > 0 {
+			threadID = thread.GetID()
+			parentCommentID = thread.Comments[0].GetID()
 		}
 	}
-	return nil
+	if firstThreadID == 0 {
+		return "", nil
+	}
+	return fmt.Sprintf("%s%s/%s/_git/%s/pullrequest/%d?discussionId=%d", g.Client.BaseURL.String(), owner, project, repoName, pullNum, firstThreadID), nil
+}
+
+// azureDevopsThread is a comment thread of a pull request. The thread type of
+// the azuredevops package can't be used to list threads since its type of
+// properties doesn't match the API.
+type azureDevopsThread struct {
+	ID        int                    `json:"id"`
+	IsDeleted bool                   `json:"isDeleted"`
+	Comments  []*azuredevops.Comment `json:"comments"`
+}
+
+// planThread returns the oldest thread of the pull request that was started
+// by a plan comment of the Atlantis user, or nil if there isn't one.
+func (g *AzureDevopsClient) planThread(owner string, project string, repoName string, pullNum int) (*azureDevopsThread, error) {
+	URL := fmt.Sprintf("%s/%s/_apis/git/repositories/%s/pullrequests/%d/threads?api-version=5.1-preview.1",
+		owner,
+		project,
+		repoName,
+		pullNum,
+	)
+	req, err := g.Client.NewRequest("GET", URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	var threads struct {
+		Value []*azureDevopsThread `json:"value"`
+	}
+	if _, err := g.Client.Execute(g.ctx, req, &threads); err != nil {
+		return nil, errors.Wrap(err, "listing pull request threads")
+	}
+
+	for _, thread := range threads.Value {
+		if thread.IsDeleted || len(thread.Comments) == 0 {
+			continue
+		}
+		first := thread.Comments[0]
+		// Usernames aren't case sensitive.
+		if !strings.EqualFold(first.GetAuthor().GetUniqueName(), g.UserName) {
+			continue
+		}
+		// The comment templates include the command name in the first line.
+		firstLine, _, _ := strings.Cut(first.GetContent(), "\n")
+		if strings.Contains(strings.ToLower(firstLine), "plan") {
+			return thread, nil
+		}
+	}
+	return nil, nil
 }
 
 func (g *AzureDevopsClient) ReactToComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, commentID int64, reaction string) error { //nolint: revive
@@ -335,6 +421,10 @@ func (g *AzureDevopsClient) UpdateStatus(logger logging.SimpleLogging, repo mode
 // until we handle branch policies
 // https://docs.microsoft.com/en-us/azure/devops/repos/git/branch-policies?view=azure-devops
 func (g *AzureDevopsClient) MergePull(logger logging.SimpleLogging, pull models.PullRequest, pullOptions models.PullRequestOptions) error {
+	if pullOptions.MergeMethod != "" {
+		return fmt.Errorf("merge method '%s' is not supported by Azure DevOps", pullOptions.MergeMethod)
+	}
+
 	owner, project, repoName := SplitAzureDevopsRepoFullName(pull.BaseRepo.FullName)
 	descriptor := "Atlantis Terraform Pull Request Automation"
 
@@ -459,3 +549,19 @@ func (g *AzureDevopsClient) GetCloneURL(_ logging.SimpleLogging, VCSHostType mod
 func (g *AzureDevopsClient) GetPullLabels(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest) ([]string, error) {
 	return nil, fmt.Errorf("not yet implemented")
 }
+
+func (g *AzureDevopsClient) GetPullDescription(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest) (string, error) {
+	return "", fmt.Errorf("not yet implemented")
+}
+
+func (g *AzureDevopsClient) UpdatePullDescription(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest, _ string) error {
+	return fmt.Errorf("not yet implemented")
+}
+
+func (g *AzureDevopsClient) SupportsReviewComments(_ models.Repo) bool {
+	return false
+}
+
+func (g *AzureDevopsClient) CreateReviewComment(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest, _ string, _ string, _ string) error {
+	return fmt.Errorf("not yet implemented")
+}
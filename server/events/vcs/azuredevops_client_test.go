@@ -5,6 +5,7 @@ package vcs_test
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
@@ -586,6 +587,100 @@ func TestAzureDevopsClient_GetPullRequest(t *testing.T) {
 	})
 }
 
+func TestAzureDevopsClient_CreateComment_ThreadComments(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	threadsURI := "/owner/project/_apis/git/repositories/repo/pullrequests/1/threads?api-version=5.1-preview.1"
+	planThreads := `{"value": [
+		{"id": 5, "comments": [{"id": 1, "author": {"uniqueName": "other"}, "content": "Ran Plan for dir: ` + "`.`" + `"}]},
+		{"id": 6, "comments": [{"id": 1, "author": {"uniqueName": "User"}, "content": "**Unlocked**"}]},
+		{"id": 7, "comments": [{"id": 1, "author": {"uniqueName": "User"}, "content": "Ran Plan for dir: ` + "`.`" + `\nmore"}]},
+		{"id": 8, "comments": [{"id": 1, "author": {"uniqueName": "User"}, "content": "Ran Plan for dir: ` + "`.`" + `"}]}
+	]}`
+	cases := []struct {
+		description    string
+		threadComments bool
+		command        string
+		threads        string
+		expRequests    []string
+	}{
+		{
+			description:    "disabled",
+			threadComments: false,
+			command:        "plan",
+			threads:        planThreads,
+			expRequests:    []string{"POST " + threadsURI},
+		},
+		{
+			description:    "first plan",
+			threadComments: true,
+			command:        "plan",
+			threads:        `{"value": []}`,
+			expRequests:    []string{"GET " + threadsURI, "POST " + threadsURI},
+		},
+		{
+			description:    "re-plan",
+			threadComments: true,
+			command:        "plan",
+			threads:        planThreads,
+			expRequests:    []string{"GET " + threadsURI, "POST /owner/project/_apis/git/repositories/repo/pullrequests/1/threads/7/comments?api-version=5.1-preview.1"},
+		},
+		{
+			description:    "apply",
+			threadComments: true,
+			command:        "apply",
+			threads:        planThreads,
+			expRequests:    []string{"GET " + threadsURI, "POST /owner/project/_apis/git/repositories/repo/pullrequests/1/threads/7/comments?api-version=5.1-preview.1"},
+		},
+		{
+			description:    "unlock",
+			threadComments: true,
+			command:        "unlock",
+			threads:        planThreads,
+			expRequests:    []string{"POST " + threadsURI},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			var requests []string
+			testServer := httptest.NewTLSServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					requests = append(requests, r.Method+" "+r.RequestURI)
+					switch {
+					case r.Method == http.MethodGet && r.RequestURI == threadsURI:
+						w.Write([]byte(c.threads)) // nolint: errcheck
+					case r.Method == http.MethodPost && r.RequestURI == threadsURI:
+						w.Write([]byte(`{"id": 9, "comments": [{"id": 1}]}`)) // nolint: errcheck
+					case r.Method == http.MethodPost:
+						var comment azuredevops.Comment
+						Ok(t, json.NewDecoder(r.Body).Decode(&comment))
+						Equals(t, 1, comment.GetParentCommentID())
+						Equals(t, "comment", comment.GetContent())
+						w.Write([]byte(`{"id": 2}`)) // nolint: errcheck
+					default:
+						t.Errorf("got unexpected request at %q", r.RequestURI)
+						http.Error(w, "not found", http.StatusNotFound)
+					}
+				}))
+			defer testServer.Close()
+			testServerURL, err := url.Parse(testServer.URL)
+			Ok(t, err)
+			client, err := vcs.NewAzureDevopsClient(testServerURL.Host, "user", "token")
+			Ok(t, err)
+			client.ThreadComments = c.threadComments
+			defer disableSSLVerification()()
+
+			repo := models.Repo{
+				FullName: "owner/project/repo",
+				Owner:    "owner",
+				Name:     "repo",
+			}
+			_, err = client.CreateComment(logger, repo, 1, "comment", c.command)
+			Ok(t, err)
+			Equals(t, c.expRequests, requests)
+		})
+	}
+}
+
 func TestAzureDevopsClient_MarkdownPullLink(t *testing.T) {
 	client, err := vcs.NewAzureDevopsClient("hostname", "user", "token")
 	Ok(t, err)
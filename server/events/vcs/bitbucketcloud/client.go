@@ -100,7 +100,7 @@ func (b *Client) GetModifiedFiles(logger logging.SimpleLogging, repo models.Repo
 }
 
 // CreateComment creates a comment on the merge request.
-func (b *Client) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, _ string) error {
+func (b *Client) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, _ string) (string, error) {
 	// NOTE: I tried to find the maximum size of a comment for bitbucket.org but
 	// I got up to 200k chars without issue so for now I'm not going to bother
 	// to detect this.
@@ -108,11 +108,20 @@ func (b *Client) CreateComment(logger logging.SimpleLogging, repo models.Repo, p
 		"raw": comment,
 	}})
 	if err != nil {
-		return errors.Wrap(err, "json encoding")
+		return "", errors.Wrap(err, "json encoding")
 	}
 	path := fmt.Sprintf("%s/2.0/repositories/%s/pullrequests/%d/comments", b.BaseURL, repo.FullName, pullNum)
-	_, err = b.makeRequest("POST", path, bytes.NewBuffer(bodyBytes))
-	return err
+	resp, err := b.makeRequest("POST", path, bytes.NewBuffer(bodyBytes))
+	if err != nil {
+		return "", err
+	}
+	// The comment was created so a response without its link isn't an error.
+	var created PullRequestComment
+	if err := json.Unmarshal(resp, &created); err != nil || created.Links == nil || created.Links.HTML == nil || created.Links.HTML.HREF == nil {
+		logger.Debug("comment link missing from response %q", string(resp))
+		return "", nil
+	}
+	return *created.Links.HTML.HREF, nil
 }
 
 // UpdateComment updates the body of a comment on the merge request.
@@ -309,10 +318,21 @@ func (b *Client) UpdateStatus(logger logging.SimpleLogging, repo models.Repo, pu
 	return err
 }
 
-// MergePull merges the pull request.
-func (b *Client) MergePull(logger logging.SimpleLogging, pull models.PullRequest, _ models.PullRequestOptions) error {
+// MergePull merges the pull request. Bitbucket deletes the source branch
+// if pullOptions.DeleteSourceBranchOnMerge is set.
+func (b *Client) MergePull(logger logging.SimpleLogging, pull models.PullRequest, pullOptions models.PullRequestOptions) error {
+	if pullOptions.MergeMethod != "" {
+		return fmt.Errorf("merge method '%s' is not supported by Bitbucket Cloud", pullOptions.MergeMethod)
+	}
+
+	bodyBytes, err := json.Marshal(map[string]bool{
+		"close_source_branch": pullOptions.DeleteSourceBranchOnMerge,
+	})
+	if err != nil {
+		return errors.Wrap(err, "json encoding")
+	}
 	path := fmt.Sprintf("%s/2.0/repositories/%s/pullrequests/%d/merge", b.BaseURL, pull.BaseRepo.FullName, pull.Num)
-	_, err := b.makeRequest("POST", path, nil)
+	_, err = b.makeRequest("POST", path, bytes.NewBuffer(bodyBytes))
 	return err
 }
 
@@ -389,3 +409,19 @@ func (b *Client) GetCloneURL(_ logging.SimpleLogging, _ models.VCSHostType, _ st
 func (b *Client) GetPullLabels(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest) ([]string, error) {
 	return nil, fmt.Errorf("not yet implemented")
 }
+
+func (b *Client) GetPullDescription(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest) (string, error) {
+	return "", fmt.Errorf("not yet implemented")
+}
+
+func (b *Client) UpdatePullDescription(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest, _ string) error {
+	return fmt.Errorf("not yet implemented")
+}
+
+func (b *Client) SupportsReviewComments(_ models.Repo) bool {
+	return false
+}
+
+func (b *Client) CreateReviewComment(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest, _ string, _ string, _ string) error {
+	return fmt.Errorf("not yet implemented")
+}
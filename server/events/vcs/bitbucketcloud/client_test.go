@@ -5,6 +5,7 @@ package bitbucketcloud_test
 
 import (
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -438,6 +439,28 @@ func TestClient_GetComment(t *testing.T) {
 	Assert(t, strings.Contains(v[1].Content.Raw, exp), "Comment should contain word \"%s\", has \"%s\"", exp, v[1].Content.Raw)
 }
 
+func TestClient_CreateComment(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.RequestURI {
+		case "POST /2.0/repositories/myorg/myrepo/pullrequests/5/comments":
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": 1, "links": {"html": {"href": "https://bitbucket.org/myorg/myrepo/pull-requests/5/_/diff#comment-1"}}}`)) // nolint: errcheck
+			return
+		default:
+			t.Errorf("got unexpected request at %q", r.RequestURI)
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+	}))
+	defer testServer.Close()
+
+	client := bitbucketcloud.NewClient(http.DefaultClient, "user", "pass", "", "runatlantis.io")
+	client.BaseURL = testServer.URL
+	url, err := client.CreateComment(logging.NewNoopLogger(t), models.Repo{FullName: "myorg/myrepo"}, 5, "comment", "")
+	Ok(t, err)
+	Equals(t, "https://bitbucket.org/myorg/myrepo/pull-requests/5/_/diff#comment-1", url)
+}
+
 func TestClient_DeleteComment(t *testing.T) {
 	testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.RequestURI {
@@ -471,6 +494,44 @@ func TestClient_DeleteComment(t *testing.T) {
 	Ok(t, err)
 }
 
+func TestClient_MergePull(t *testing.T) {
+	for _, deleteSourceBranch := range []bool{true, false} {
+		t.Run(fmt.Sprintf("delete source branch %t", deleteSourceBranch), func(t *testing.T) {
+			testServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.RequestURI {
+				case "/2.0/repositories/myorg/myrepo/pullrequests/5/merge":
+					body, err := io.ReadAll(r.Body)
+					Ok(t, err)
+					Equals(t, fmt.Sprintf(`{"close_source_branch":%t}`, deleteSourceBranch), string(body))
+					w.Write([]byte("{}")) // nolint: errcheck
+					return
+				default:
+					t.Errorf("got unexpected request at %q", r.RequestURI)
+					http.Error(w, "not found", http.StatusNotFound)
+					return
+				}
+			}))
+			defer testServer.Close()
+
+			client := bitbucketcloud.NewClient(http.DefaultClient, "user", "pass", "", "runatlantis.io")
+			client.BaseURL = testServer.URL
+			err := client.MergePull(logging.NewNoopLogger(t), models.PullRequest{
+				Num: 5,
+				BaseRepo: models.Repo{
+					FullName: "myorg/myrepo",
+					Owner:    "myorg",
+					Name:     "myrepo",
+					VCSHost: models.VCSHost{
+						Type:     models.BitbucketCloud,
+						Hostname: "bitbucket.org",
+					},
+				},
+			}, models.PullRequestOptions{DeleteSourceBranchOnMerge: deleteSourceBranch})
+			Ok(t, err)
+		})
+	}
+}
+
 func TestClient_HidePRComments(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
 	comments, err := os.ReadFile(filepath.Join("testdata", "comments.json"))
@@ -70,6 +70,7 @@ type PullRequestComment struct {
 	Content *struct {
 		Raw string `json:"raw"`
 	} `json:"content" validate:"required"`
+	Links *Links `json:"links,omitempty"`
 }
 
 type PullRequestComments struct {
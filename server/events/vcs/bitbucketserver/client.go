@@ -136,17 +136,18 @@ func (b *Client) GetProjectKey(repoName string, cloneURL string) (string, error)
 }
 
 // CreateComment creates a comment on the merge request. It will write multiple
-// comments if a single comment is too long.
-func (b *Client) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, _ string) error {
+// comments if a single comment is too long. Bitbucket Server doesn't return
+// the comment's URL so it's always "".
+func (b *Client) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, _ string) (string, error) {
 	sepEnd := "\n```\n**Warning**: Output length greater than max comment size. Continued in next comment."
 	sepStart := "Continued from previous comment.\n```diff\n"
 	comments := common.SplitComment(comment, maxCommentLength, sepEnd, sepStart, 0, "")
 	for _, c := range comments {
 		if err := b.postComment(repo, pullNum, c); err != nil {
-			return err
+			return "", err
 		}
 	}
-	return nil
+	return "", nil
 }
 
 func (b *Client) ReactToComment(_ logging.SimpleLogging, _ models.Repo, _ int, _ int64, _ string) error {
@@ -270,6 +271,10 @@ func (b *Client) UpdateStatus(logger logging.SimpleLogging, _ models.Repo, pull
 
 // MergePull merges the pull request.
 func (b *Client) MergePull(logger logging.SimpleLogging, pull models.PullRequest, pullOptions models.PullRequestOptions) error {
+	if pullOptions.MergeMethod != "" {
+		return fmt.Errorf("merge method '%s' is not supported by Bitbucket Server", pullOptions.MergeMethod)
+	}
+
 	projectKey, err := b.GetProjectKey(pull.BaseRepo.Name, pull.BaseRepo.SanitizedCloneURL)
 	if err != nil {
 		return err
@@ -300,12 +305,13 @@ func (b *Client) MergePull(logger logging.SimpleLogging, pull models.PullRequest
 		}
 
 		path = fmt.Sprintf("%s/rest/branch-utils/1.0/projects/%s/repos/%s/branches", b.BaseURL, projectKey, pull.BaseRepo.Name)
-		_, err = b.makeRequest("DELETE", path, bytes.NewBuffer(bodyBytes))
-		if err != nil {
-			return err
+		// The pull request is merged so failing to delete its branch isn't
+		// an error.
+		if _, err := b.makeRequest("DELETE", path, bytes.NewBuffer(bodyBytes)); err != nil {
+			logger.Warn("could not delete the source branch %q of pull request %d: %s", pull.HeadBranch, pull.Num, err)
 		}
 	}
-	return err
+	return nil
 }
 
 // MarkdownPullLink specifies the character used in a pull request comment.
@@ -379,3 +385,19 @@ func (b *Client) GetCloneURL(_ logging.SimpleLogging, _ models.VCSHostType, _ st
 func (b *Client) GetPullLabels(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest) ([]string, error) {
 	return nil, fmt.Errorf("not yet implemented")
 }
+
+func (b *Client) GetPullDescription(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest) (string, error) {
+	return "", fmt.Errorf("not yet implemented")
+}
+
+func (b *Client) UpdatePullDescription(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest, _ string) error {
+	return fmt.Errorf("not yet implemented")
+}
+
+func (b *Client) SupportsReviewComments(_ models.Repo) bool {
+	return false
+}
+
+func (b *Client) CreateReviewComment(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest, _ string, _ string, _ string) error {
+	return fmt.Errorf("not yet implemented")
+}
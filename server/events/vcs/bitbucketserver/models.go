@@ -7,6 +7,7 @@ const (
 	DiagnosticsPingHeader    = "diagnostics:ping"
 	PullCreatedHeader        = "pr:opened"
 	PullFromRefUpdatedHeader = "pr:from_ref_updated"
+	PullModifiedHeader       = "pr:modified"
 	PullMergedHeader         = "pr:merged"
 	PullDeclinedHeader       = "pr:declined"
 	PullDeletedHeader        = "pr:deleted"
@@ -18,8 +19,24 @@ type CommentEvent struct {
 	Comment *Comment `json:"comment,omitempty" validate:"required"`
 }
 
+// Draft pull request detection modes.
+const (
+	// DraftDetectionField uses the pull request's draft field which is set by
+	// Bitbucket Data Center 8.18 and later.
+	DraftDetectionField = "field"
+	// DraftDetectionTitle uses a prefix of the pull request's title, e.g. WIP:.
+	DraftDetectionTitle = "title"
+	// DraftDetectionAny uses both the draft field and the title.
+	DraftDetectionAny = "any"
+)
+
 type PullRequestEvent struct {
 	CommonEventData
+	// PreviousTitle is only set for pr:modified events.
+	PreviousTitle *string `json:"previousTitle,omitempty"`
+	// PreviousDraft is only set for pr:modified events that change the
+	// draft field.
+	PreviousDraft *bool `json:"previousDraft,omitempty"`
 }
 
 type CommonEventData struct {
@@ -30,6 +47,8 @@ type CommonEventData struct {
 type PullRequest struct {
 	Version   *int    `json:"version,omitempty" validate:"required"`
 	ID        *int    `json:"id,omitempty" validate:"required"`
+	Title     *string `json:"title,omitempty"`
+	Draft     *bool   `json:"draft,omitempty"`
 	FromRef   *Ref    `json:"fromRef,omitempty" validate:"required"`
 	ToRef     *Ref    `json:"toRef,omitempty" validate:"required"`
 	State     *string `json:"state,omitempty" validate:"required"`
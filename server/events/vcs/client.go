@@ -25,7 +25,10 @@ type Client interface {
 	// GetModifiedFiles returns the names of files that were modified in the merge request
 	// relative to the repo root, e.g. parent/child/file.txt.
 	GetModifiedFiles(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest) ([]string, error)
-	CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) error
+	// CreateComment comments on the pull request and returns the URL of the
+	// comment, or of the first one if it was split, or "" if the VCS doesn't
+	// return it.
+	CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) (string, error)
 
 	ReactToComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, commentID int64, reaction string) error
 	HidePrevCommandComments(logger logging.SimpleLogging, repo models.Repo, pullNum int, command string, dir string) error
@@ -53,4 +56,17 @@ type Client interface {
 
 	// GetPullLabels returns the labels of a pull request
 	GetPullLabels(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest) ([]string, error)
+
+	// GetPullDescription returns the description of a pull request.
+	GetPullDescription(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest) (string, error)
+	// UpdatePullDescription replaces the description of a pull request.
+	UpdatePullDescription(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, description string) error
+
+	// SupportsReviewComments returns true if the VCS supports
+	// CreateReviewComment.
+	SupportsReviewComments(repo models.Repo) bool
+	// CreateReviewComment creates a comment on the file path, relative to the
+	// repo root, in pull's head commit instead of the pull request's
+	// conversation.
+	CreateReviewComment(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, path string, comment string, command string) error
 }
@@ -8,6 +8,7 @@ package common
 import (
 	"fmt"
 	"math"
+	"strings"
 )
 
 // AutomergeCommitMsg returns the commit message to use when automerging.
@@ -57,3 +58,134 @@ func SplitComment(comment string, maxSize int, sepEnd string, sepStart string, m
 	}
 	return comments
 }
+
+/*
+SplitCommentIntoParts splits comment into a slice of comments that are under
+maxSize like SplitComment, but it keeps the whole comment and numbers the parts.
+- It only splits at line boundaries, unless a line is longer than a part.
+- A code block or <details> block that's open at the end of a part is closed
+and opened again at the start of the next part, so fences are never split
+mid-block.
+- The first line of the comment, which names the command and project, is
+repeated at the start of every part with a "(part i/n)" suffix so each part
+can be found like the first one.
+*/
+func SplitCommentIntoParts(comment string, maxSize int) []string {
+	if len(comment) <= maxSize {
+		return []string{comment}
+	}
+
+	firstLine, rest, _ := strings.Cut(comment, "\n")
+	// The header is longest for the parts after the first one. Any part number
+	// has fewer digits than the comment's length.
+	partSize := maxSize - len(partHeader(firstLine, len(comment), len(comment))) - len("\n\n")
+	if partSize < maxSize/2 {
+		// The first line is too long to be repeated.
+		return SplitComment(comment, maxSize, "", "", 0, "")
+	}
+
+	var parts []string
+	var part strings.Builder
+	var blocks commentBlocks
+	partStart := ""
+	for _, line := range splitLines(rest, partSize/2) {
+		next := blocks.after(line)
+		// One byte more is kept for the newline a closing fence may need.
+		if part.Len() > len(partStart) && part.Len()+len(line)+len(next.closing())+1 > partSize {
+			if closing := blocks.closing(); closing != "" {
+				if !strings.HasSuffix(part.String(), "\n") {
+					part.WriteString("\n")
+				}
+				part.WriteString(closing)
+			}
+			parts = append(parts, part.String())
+			part.Reset()
+			partStart = blocks.opening()
+			part.WriteString(partStart)
+		}
+		part.WriteString(line)
+		blocks = next
+	}
+	parts = append(parts, part.String())
+
+	for i := range parts {
+		if i == 0 {
+			parts[i] = partHeader(firstLine, 1, len(parts)) + "\n" + parts[i]
+			continue
+		}
+		parts[i] = partHeader(firstLine, i+1, len(parts)) + "\n\n" + parts[i]
+	}
+	return parts
+}
+
+func partHeader(firstLine string, part int, numParts int) string {
+	return fmt.Sprintf("%s (part %d/%d)", firstLine, part, numParts)
+}
+
+// splitLines splits s after each newline and splits lines longer than
+// maxLen.
+func splitLines(s string, maxLen int) []string {
+	var lines []string
+	for _, line := range strings.SplitAfter(s, "\n") {
+		for len(line) > maxLen {
+			lines = append(lines, line[:maxLen])
+			line = line[maxLen:]
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// commentBlocks are the code and <details> blocks open at a line of a
+// comment.
+type commentBlocks struct {
+	// details is the line that opened the <details> block.
+	details string
+	// fence is the line that opened the code block, ex. "```diff".
+	fence string
+}
+
+func (b commentBlocks) after(line string) commentBlocks {
+	trimmed := strings.TrimSpace(line)
+	switch {
+	case strings.HasPrefix(trimmed, "```"):
+		if b.fence == "" {
+			b.fence = strings.TrimRight(line, "\n")
+		} else {
+			b.fence = ""
+		}
+	case b.fence != "":
+	case strings.HasPrefix(trimmed, "<details") && !strings.Contains(trimmed, "</details>"):
+		b.details = strings.TrimRight(line, "\n")
+	case strings.Contains(trimmed, "</details>"):
+		b.details = ""
+	}
+	return b
+}
+
+// closing closes the open blocks.
+func (b commentBlocks) closing() string {
+	var s string
+	if b.fence != "" {
+		indent := b.fence[:strings.Index(b.fence, "```")]
+		s += indent + "```\n"
+	}
+	if b.details != "" {
+		s += "</details>\n"
+	}
+	return s
+}
+
+// opening opens the blocks again.
+func (b commentBlocks) opening() string {
+	var s string
+	if b.details != "" {
+		s += b.details + "\n\n"
+	}
+	if b.fence != "" {
+		s += b.fence + "\n"
+	}
+	return s
+}
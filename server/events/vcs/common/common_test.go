@@ -14,6 +14,7 @@
 package common_test
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -78,6 +79,40 @@ func TestSplitComment_Limited(t *testing.T) {
 		sepStart + comment[len(comment)-expMax:]}, split)
 }
 
+func TestSplitCommentIntoParts_UnderMax(t *testing.T) {
+	comment := "comment under max size"
+	Equals(t, []string{comment}, common.SplitCommentIntoParts(comment, len(comment)))
+}
+
+func TestSplitCommentIntoParts(t *testing.T) {
+	output := strings.Repeat("+ resource\n", 30)
+	comment := "Ran Plan for dir: `a` workspace: `default`\n\n" +
+		"<details><summary>Show Output</summary>\n\n" +
+		"```diff\n" + output + "```\n</details>\n\n" +
+		"Plan: 30 to add, 0 to change, 0 to destroy."
+	split := common.SplitCommentIntoParts(comment, 250)
+
+	Equals(t, 4, len(split))
+	for i, part := range split {
+		Assert(t, len(part) <= 250, "part %d is %d bytes", i, len(part))
+		header := fmt.Sprintf("Ran Plan for dir: `a` workspace: `default` (part %d/4)\n", i+1)
+		Assert(t, strings.HasPrefix(part, header), "expected part %d to start with %q but was %q", i, header, part)
+		// Every part has balanced fences and details blocks.
+		Equals(t, 0, strings.Count(part, "```")%2)
+		Equals(t, strings.Count(part, "<details>"), strings.Count(part, "</details>"))
+	}
+	Assert(t, strings.HasSuffix(split[0], "+ resource\n```\n</details>\n"), "got %q", split[0])
+	Assert(t, strings.Contains(split[1], "(part 2/4)\n\n<details><summary>Show Output</summary>\n\n```diff\n+ resource\n"), "got %q", split[1])
+	Assert(t, strings.HasSuffix(split[3], "Plan: 30 to add, 0 to change, 0 to destroy."), "got %q", split[3])
+
+	// No output is lost.
+	var lines int
+	for _, part := range split {
+		lines += strings.Count(part, "+ resource\n")
+	}
+	Equals(t, 30, lines)
+}
+
 func TestAutomergeCommitMsg(t *testing.T) {
 	tests := []struct {
 		name    string
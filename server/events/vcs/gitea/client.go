@@ -134,23 +134,23 @@ func (c *GiteaClient) GetModifiedFiles(logger logging.SimpleLogging, repo models
 }
 
 // CreateComment creates a comment on the merge request. As far as we're aware, Gitea has no built in max comment length right now.
-func (c *GiteaClient) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) error {
+func (c *GiteaClient) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) (string, error) {
 	logger.Debug("Creating comment on Gitea pull request %d", pullNum)
 
 	opt := gitea.CreateIssueCommentOption{
 		Body: comment,
 	}
 
-	_, resp, err := c.giteaClient.CreateIssueComment(repo.Owner, repo.Name, int64(pullNum), opt)
+	created, resp, err := c.giteaClient.CreateIssueComment(repo.Owner, repo.Name, int64(pullNum), opt)
 
 	if err != nil {
 		logger.Debug("POST /repos/%v/%v/issues/%d/comments returned: %v", repo.Owner, repo.Name, pullNum, resp.StatusCode)
-		return err
+		return "", err
 	}
 
 	logger.Debug("Added comment to Gitea pull request %d: %s", pullNum, comment)
 
-	return nil
+	return created.HTMLURL, nil
 }
 
 // ReactToComment adds a reaction to a comment.
@@ -384,6 +384,9 @@ func (c *GiteaClient) DiscardReviews(_ logging.SimpleLogging, repo models.Repo,
 
 func (c *GiteaClient) MergePull(logger logging.SimpleLogging, pull models.PullRequest, pullOptions models.PullRequestOptions) error {
 	logger.Debug("Merging Gitea pull request %d", pull.Num)
+	if pullOptions.MergeMethod != "" {
+		return fmt.Errorf("merge method '%s' is not supported by Gitea", pullOptions.MergeMethod)
+	}
 
 	mergeOptions := gitea.MergePullRequestOption{
 		Style:                  gitea.MergeStyleMerge,
@@ -516,3 +519,19 @@ func ValidateSignature(payload []byte, signature string, secretKey []byte) error
 
 	return nil
 }
+
+func (c *GiteaClient) GetPullDescription(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest) (string, error) {
+	return "", fmt.Errorf("not yet implemented")
+}
+
+func (c *GiteaClient) UpdatePullDescription(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest, _ string) error {
+	return fmt.Errorf("not yet implemented")
+}
+
+func (c *GiteaClient) SupportsReviewComments(_ models.Repo) bool {
+	return false
+}
+
+func (c *GiteaClient) CreateReviewComment(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest, _ string, _ string, _ string) error {
+	return fmt.Errorf("not yet implemented")
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package vcs
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/bradleyfalzon/ghinstallation/v2"
+	"github.com/google/go-github/v71/github"
+	"github.com/pkg/errors"
+)
+
+// DefaultGithubAppTokenRefreshLeadTime is how long before a GitHub App
+// installation token expires that it's refreshed.
+const DefaultGithubAppTokenRefreshLeadTime = 5 * time.Minute
+
+const (
+	// githubAppTokenRefreshAttempts is how many times refreshing an
+	// installation token is attempted before failing.
+	githubAppTokenRefreshAttempts = 3
+	// githubAppTokenRefreshRetryDelay is the delay before the first retry of
+	// a failed refresh, it increases with every attempt.
+	githubAppTokenRefreshRetryDelay = time.Second
+)
+
+// githubAppTransport authenticates requests as a GitHub App installation.
+// Its token is refreshed once it expires in less than leadTime so that
+// long-running operations don't use a token that expires midway. Concurrent
+// requests wait for a single refresh rather than each refreshing the token.
+type githubAppTransport struct {
+	apps           *ghinstallation.AppsTransport
+	installationID int64
+	apiURL         *url.URL
+	leadTime       time.Duration
+	retryDelay     time.Duration
+	tr             http.RoundTripper
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+func newGithubAppTransport(tr http.RoundTripper, appID int64, installationID int64, key []byte, apiURL *url.URL, leadTime time.Duration) (*githubAppTransport, error) {
+	apps, err := ghinstallation.NewAppsTransport(tr, appID, key)
+	if err != nil {
+		return nil, err
+	}
+	if leadTime == 0 {
+		leadTime = DefaultGithubAppTokenRefreshLeadTime
+	}
+	return &githubAppTransport{
+		apps:           apps,
+		installationID: installationID,
+		apiURL:         apiURL,
+		leadTime:       leadTime,
+		retryDelay:     githubAppTokenRefreshRetryDelay,
+		tr:             tr,
+	}, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *githubAppTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.Token(req.Context())
+	if err != nil {
+		if req.Body != nil {
+			req.Body.Close() // nolint: errcheck
+		}
+		return nil, err
+	}
+
+	// A RoundTripper mustn't modify the request.
+	creq := req.Clone(req.Context())
+	creq.Header.Set("Authorization", "token "+token)
+	if creq.Header.Get("Accept") == "" {
+		creq.Header.Set("Accept", "application/vnd.github.v3+json")
+	}
+	return t.tr.RoundTrip(creq)
+}
+
+// Token returns the installation token, refreshing it if it expires in less
+// than the lead time. If refreshing fails while the current token is still
+// valid, the current token is returned and the next call retries.
+func (t *githubAppTransport) Token(ctx context.Context) (string, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.token != "" && time.Until(t.expiresAt) > t.leadTime {
+		return t.token, nil
+	}
+
+	token, expiresAt, err := t.refresh(ctx)
+	if err != nil {
+		if t.token != "" && time.Now().Before(t.expiresAt) {
+			return t.token, nil
+		}
+		return "", err
+	}
+	t.token = token
+	t.expiresAt = expiresAt
+	return t.token, nil
+}
+
+// refresh creates a new installation token, retrying with an increasing
+// delay if it fails.
+func (t *githubAppTransport) refresh(ctx context.Context) (string, time.Time, error) {
+	client := github.NewClient(&http.Client{Transport: t.apps})
+	client.BaseURL = t.apiURL
+
+	var err error
+	for attempt := 1; attempt <= githubAppTokenRefreshAttempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return "", time.Time{}, errors.Wrapf(ctx.Err(), "refreshing installation %d's token", t.installationID)
+			case <-time.After(time.Duration(attempt-1) * t.retryDelay):
+			}
+		}
+		var token *github.InstallationToken
+		token, _, err = client.Apps.CreateInstallationToken(ctx, t.installationID, nil)
+		if err == nil {
+			return token.GetToken(), token.GetExpiresAt().Time, nil
+		}
+	}
+	return "", time.Time{}, errors.Wrapf(err, "refreshing installation %d's token after %d attempts", t.installationID, githubAppTokenRefreshAttempts)
+}
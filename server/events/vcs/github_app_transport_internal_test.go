@@ -0,0 +1,120 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package vcs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events/vcs/testdata"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+// githubAppTokenServer returns a transport whose installation tokens are
+// created by a server that fails the first failures requests and returns
+// tokens that expire after expiresIn. It also returns the number of token
+// requests.
+func githubAppTokenServer(t *testing.T, failures int32, expiresIn time.Duration) (*githubAppTransport, *atomic.Int32) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Equals(t, "/app/installations/1/access_tokens", r.URL.Path)
+		n := requests.Add(1)
+		if n <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"token": "token-%d", "expires_at": %q}`, n, time.Now().Add(expiresIn).Format(time.RFC3339)) // nolint: errcheck
+	}))
+	t.Cleanup(server.Close)
+
+	apiURL, err := url.Parse(server.URL + "/")
+	Ok(t, err)
+	tr, err := newGithubAppTransport(http.DefaultTransport, 1, 1, []byte(testdata.GithubPrivateKey), apiURL, 10*time.Minute)
+	Ok(t, err)
+	tr.retryDelay = time.Millisecond
+	return tr, &requests
+}
+
+func TestGithubAppTransport_Token_ReusedUntilLeadTime(t *testing.T) {
+	tr, requests := githubAppTokenServer(t, 0, time.Hour)
+	token, err := tr.Token(context.Background())
+	Ok(t, err)
+	Equals(t, "token-1", token)
+
+	token, err = tr.Token(context.Background())
+	Ok(t, err)
+	Equals(t, "token-1", token)
+	Equals(t, int32(1), requests.Load())
+
+	// The token now expires within the lead time so it's refreshed.
+	tr.expiresAt = time.Now().Add(5 * time.Minute)
+	token, err = tr.Token(context.Background())
+	Ok(t, err)
+	Equals(t, "token-2", token)
+	Equals(t, int32(2), requests.Load())
+}
+
+func TestGithubAppTransport_Token_ConcurrentRefreshOnce(t *testing.T) {
+	tr, requests := githubAppTokenServer(t, 0, time.Hour)
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := tr.Token(context.Background())
+			Ok(t, err)
+			Equals(t, "token-1", token)
+		}()
+	}
+	wg.Wait()
+	Equals(t, int32(1), requests.Load())
+}
+
+func TestGithubAppTransport_Token_RetriesFailedRefresh(t *testing.T) {
+	tr, requests := githubAppTokenServer(t, 2, time.Hour)
+	token, err := tr.Token(context.Background())
+	Ok(t, err)
+	Equals(t, "token-3", token)
+	Equals(t, int32(3), requests.Load())
+}
+
+func TestGithubAppTransport_Token_RefreshFails(t *testing.T) {
+	tr, requests := githubAppTokenServer(t, githubAppTokenRefreshAttempts, time.Hour)
+	_, err := tr.Token(context.Background())
+	ErrContains(t, "refreshing installation 1's token after 3 attempts", err)
+	Equals(t, int32(githubAppTokenRefreshAttempts), requests.Load())
+}
+
+func TestGithubAppTransport_Token_RefreshFailsKeepsValidToken(t *testing.T) {
+	tr, requests := githubAppTokenServer(t, githubAppTokenRefreshAttempts, time.Hour)
+	tr.token = "old-token"
+	tr.expiresAt = time.Now().Add(time.Minute)
+	token, err := tr.Token(context.Background())
+	Ok(t, err)
+	Equals(t, "old-token", token)
+	Equals(t, int32(githubAppTokenRefreshAttempts), requests.Load())
+}
+
+func TestGithubAppTransport_RoundTrip(t *testing.T) {
+	tr, _ := githubAppTokenServer(t, 0, time.Hour)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Equals(t, "token token-1", r.Header.Get("Authorization"))
+		Equals(t, "application/vnd.github.v3+json", r.Header.Get("Accept"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	resp, err := (&http.Client{Transport: tr}).Get(server.URL)
+	Ok(t, err)
+	defer resp.Body.Close()
+	Equals(t, http.StatusOK, resp.StatusCode)
+}
@@ -25,7 +25,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/gofri/go-github-ratelimit/github_ratelimit"
 	"github.com/google/go-github/v71/github"
 	"github.com/pkg/errors"
 	"github.com/runatlantis/atlantis/server/events/command"
@@ -125,10 +124,15 @@ func NewGithubClient(hostname string, credentials GithubCredentials, config Gith
 		return nil, errors.Wrap(err, "error initializing github authentication transport")
 	}
 
-	transportWithRateLimit, err := github_ratelimit.NewRateLimitWaiterClient(transport.Transport)
-	if err != nil {
-		return nil, errors.Wrap(err, "error initializing github rate limit transport")
+	base := transport.Transport
+	if base == nil {
+		base = http.DefaultTransport
 	}
+	transportWithRateLimit := &http.Client{Transport: &githubRateLimitTransport{
+		base:    base,
+		maxWait: config.RateLimitMaxWait,
+		logger:  logger,
+	}}
 
 	var graphqlURL string
 	var client *github.Client
@@ -226,8 +230,76 @@ listloop:
 // CreateComment creates a comment on the pull request.
 // If comment length is greater than the max comment length we split into
 // multiple comments.
-func (g *GithubClient) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) error {
+func (g *GithubClient) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) (string, error) {
 	logger.Debug("Creating comment on GitHub pull request %d", pullNum)
+	var url string
+	comments := g.splitComment(comment, command)
+	for i := range comments {
+		created, resp, err := g.client.Issues.CreateComment(g.ctx, repo.Owner, repo.Name, pullNum, &github.IssueComment{Body: &comments[i]})
+		if resp != nil {
+			logger.Debug("POST /repos/%v/%v/issues/%d/comments returned: %v", repo.Owner, repo.Name, pullNum, resp.StatusCode)
+		}
+		if err != nil {
+			return "", err
+		}
+		if i == 0 {
+			url = created.GetHTMLURL()
+		}
+	}
+	return url, nil
+}
+
+// SupportsReviewComments returns true since GitHub supports commenting on a
+// pull request's files.
+func (g *GithubClient) SupportsReviewComments(_ models.Repo) bool {
+	return true
+}
+
+// CreateReviewComment creates a file-level review comment on path. If the
+// comment is too long, the rest of it is posted as replies so it stays in one
+// thread.
+func (g *GithubClient) CreateReviewComment(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, path string, comment string, command string) error {
+	logger.Debug("Creating review comment on %q in GitHub pull request %d", path, pull.Num)
+	var threadID int64
+	for i, body := range g.splitComment(comment, command) {
+		if i == 0 {
+			created, resp, err := g.client.PullRequests.CreateComment(g.ctx, repo.Owner, repo.Name, pull.Num, &github.PullRequestComment{
+				Body:        github.Ptr(body),
+				CommitID:    github.Ptr(pull.HeadCommit),
+				Path:        github.Ptr(path),
+				SubjectType: github.Ptr("file"),
+			})
+			if resp != nil {
+				logger.Debug("POST /repos/%v/%v/pulls/%d/comments returned: %v", repo.Owner, repo.Name, pull.Num, resp.StatusCode)
+			}
+			if err != nil {
+				return err
+			}
+			threadID = created.GetID()
+			continue
+		}
+		_, resp, err := g.client.PullRequests.CreateCommentInReplyTo(g.ctx, repo.Owner, repo.Name, pull.Num, body, threadID)
+		if resp != nil {
+			logger.Debug("POST /repos/%v/%v/pulls/%d/comments returned: %v", repo.Owner, repo.Name, pull.Num, resp.StatusCode)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitComment splits comment into comments that fit in GitHub's max
+// comment length.
+func (g *GithubClient) splitComment(comment string, command string) []string {
+	// If there would be more parts than comments per command, the output is
+	// truncated as usual.
+	if g.config.SplitLargeComments {
+		if parts := common.SplitCommentIntoParts(comment, maxCommentLength); g.maxCommentsPerCommand == 0 || len(parts) <= g.maxCommentsPerCommand {
+			return parts
+		}
+	}
+
 	var sepStart string
 
 	sepEnd := "\n```\n</details>" +
@@ -245,17 +317,7 @@ func (g *GithubClient) CreateComment(logger logging.SimpleLogging, repo models.R
 		"> **Warning**: Command output is larger than the maximum number of comments per command. Output truncated.\n<details><summary>Show Output</summary>\n\n" +
 		"```diff\n"
 
-	comments := common.SplitComment(comment, maxCommentLength, sepEnd, sepStart, g.maxCommentsPerCommand, truncationHeader)
-	for i := range comments {
-		_, resp, err := g.client.Issues.CreateComment(g.ctx, repo.Owner, repo.Name, pullNum, &github.IssueComment{Body: &comments[i]})
-		if resp != nil {
-			logger.Debug("POST /repos/%v/%v/issues/%d/comments returned: %v", repo.Owner, repo.Name, pullNum, resp.StatusCode)
-		}
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return common.SplitComment(comment, maxCommentLength, sepEnd, sepStart, g.maxCommentsPerCommand, truncationHeader)
 }
 
 // ReactToComment adds a reaction to a comment.
@@ -291,6 +353,32 @@ func (g *GithubClient) HidePrevCommandComments(logger logging.SimpleLogging, rep
 		nextPage = resp.NextPage
 	}
 
+	// Plans can also be posted as review comments. They're hidden the same
+	// way as issue comments.
+	if g.config.PlanReviewComments {
+		nextPage = 0
+		for {
+			comments, resp, err := g.client.PullRequests.ListComments(g.ctx, repo.Owner, repo.Name, pullNum, &github.PullRequestListCommentsOptions{
+				Sort:        "created",
+				Direction:   "asc",
+				ListOptions: github.ListOptions{Page: nextPage},
+			})
+			if resp != nil {
+				logger.Debug("GET /repos/%v/%v/pulls/%d/comments returned: %v", repo.Owner, repo.Name, pullNum, resp.StatusCode)
+			}
+			if err != nil {
+				return errors.Wrap(err, "listing review comments")
+			}
+			for _, comment := range comments {
+				allComments = append(allComments, &github.IssueComment{User: comment.User, Body: comment.Body, NodeID: comment.NodeID})
+			}
+			if resp.NextPage == 0 {
+				break
+			}
+			nextPage = resp.NextPage
+		}
+	}
+
 	for _, comment := range allComments {
 		// Using a case insensitive compare here because usernames aren't case
 		// sensitive and users may enter their atlantis users with different
@@ -954,6 +1042,10 @@ func (g *GithubClient) UpdateStatus(logger logging.SimpleLogging, repo models.Re
 		ghState = "failure"
 	}
 
+	if g.config.CheckRuns {
+		return g.updateCheckRun(logger, repo, pull, state, src, description, url)
+	}
+
 	logger.Info("Updating GitHub Check status for '%s' to '%s'", src, ghState)
 
 	status := &github.RepoStatus{
@@ -969,6 +1061,72 @@ func (g *GithubClient) UpdateStatus(logger logging.SimpleLogging, repo models.Re
 	return err
 }
 
+// updateCheckRun publishes the status as the check run named src of the
+// pull request's head commit. The check run that's in progress is updated if
+// there's one, otherwise a new one is created, so every project keeps a
+// single check whose latest run reflects its status.
+func (g *GithubClient) updateCheckRun(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, state models.CommitStatus, src string, description string, url string) error {
+	// Check runs that are in progress have no conclusion yet.
+	status := "completed"
+	conclusion := github.Ptr("failure")
+	switch state {
+	case models.PendingCommitStatus:
+		status = "in_progress"
+		conclusion = nil
+	case models.SuccessCommitStatus:
+		conclusion = github.Ptr("success")
+	}
+	logger.Info("Updating GitHub check run '%s' to '%s'", src, state.String())
+
+	var detailsURL *string
+	if url != "" {
+		detailsURL = github.Ptr(url)
+	}
+	output := &github.CheckRunOutput{
+		Title:   github.Ptr(description),
+		Summary: github.Ptr(description),
+	}
+
+	runs, resp, err := g.client.Checks.ListCheckRunsForRef(g.ctx, repo.Owner, repo.Name, pull.HeadCommit, &github.ListCheckRunsOptions{
+		CheckName: github.Ptr(src),
+		Status:    github.Ptr("in_progress"),
+	})
+	if resp != nil {
+		logger.Debug("GET /repos/%v/%v/commits/%s/check-runs returned: %v", repo.Owner, repo.Name, pull.HeadCommit, resp.StatusCode)
+	}
+	if err != nil {
+		return errors.Wrap(err, "listing check runs")
+	}
+
+	if len(runs.CheckRuns) > 0 {
+		id := runs.CheckRuns[0].GetID()
+		_, resp, err = g.client.Checks.UpdateCheckRun(g.ctx, repo.Owner, repo.Name, id, github.UpdateCheckRunOptions{
+			Name:       src,
+			DetailsURL: detailsURL,
+			Status:     github.Ptr(status),
+			Conclusion: conclusion,
+			Output:     output,
+		})
+		if resp != nil {
+			logger.Debug("PATCH /repos/%v/%v/check-runs/%d returned: %v", repo.Owner, repo.Name, id, resp.StatusCode)
+		}
+		return errors.Wrap(err, "updating check run")
+	}
+
+	_, resp, err = g.client.Checks.CreateCheckRun(g.ctx, repo.Owner, repo.Name, github.CreateCheckRunOptions{
+		Name:       src,
+		HeadSHA:    pull.HeadCommit,
+		DetailsURL: detailsURL,
+		Status:     github.Ptr(status),
+		Conclusion: conclusion,
+		Output:     output,
+	})
+	if resp != nil {
+		logger.Debug("POST /repos/%v/%v/check-runs returned: %v", repo.Owner, repo.Name, resp.StatusCode)
+	}
+	return errors.Wrap(err, "creating check run")
+}
+
 // MergePull merges the pull request.
 func (g *GithubClient) MergePull(logger logging.SimpleLogging, pull models.PullRequest, pullOptions models.PullRequestOptions) error {
 	logger.Debug("Merging GitHub pull request %d", pull.Num)
@@ -1043,6 +1201,37 @@ func (g *GithubClient) MergePull(logger logging.SimpleLogging, pull models.PullR
 	if !mergeResult.GetMerged() {
 		return fmt.Errorf("could not merge pull request: %s", mergeResult.GetMessage())
 	}
+	if pullOptions.DeleteSourceBranchOnMerge && !repo.GetDeleteBranchOnMerge() {
+		// The pull request is merged so failing to delete its branch isn't an
+		// error.
+		if err := g.deleteSourceBranch(logger, pull); err != nil {
+			logger.Warn("could not delete the source branch %q of pull request %d: %s", pull.HeadBranch, pull.Num, err)
+		}
+	}
+	return nil
+}
+
+// deleteSourceBranch deletes the head branch of pull if it's in the base
+// repository. Branches of forks can't be deleted.
+func (g *GithubClient) deleteSourceBranch(logger logging.SimpleLogging, pull models.PullRequest) error {
+	ghPull, err := g.GetPullRequest(logger, pull.BaseRepo, pull.Num)
+	if err != nil {
+		return err
+	}
+	if ghPull.GetHead().GetRepo().GetFullName() != pull.BaseRepo.FullName {
+		logger.Info("not deleting the source branch of pull request %d because it's in the fork %s", pull.Num, ghPull.GetHead().GetRepo().GetFullName())
+		return nil
+	}
+	branch := ghPull.GetHead().GetRef()
+	logger.Debug("DELETE /repos/%v/%v/git/refs/heads/%v", pull.BaseRepo.Owner, pull.BaseRepo.Name, branch)
+	resp, err := g.client.Git.DeleteRef(g.ctx, pull.BaseRepo.Owner, pull.BaseRepo.Name, "heads/"+branch)
+	if resp != nil {
+		logger.Debug("DELETE /repos/%v/%v/git/refs/heads/%v returned: %v", pull.BaseRepo.Owner, pull.BaseRepo.Name, branch, resp.StatusCode)
+	}
+	if err != nil {
+		return err
+	}
+	logger.Info("deleted the source branch %q of pull request %d", branch, pull.Num)
 	return nil
 }
 
@@ -1176,3 +1365,26 @@ func (g *GithubClient) GetPullLabels(logger logging.SimpleLogging, repo models.R
 
 	return labels, nil
 }
+
+// GetPullDescription returns the description of the pull request.
+func (g *GithubClient) GetPullDescription(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest) (string, error) {
+	logger.Debug("Getting description for GitHub pull request %d", pull.Num)
+	pullDetails, resp, err := g.client.PullRequests.Get(g.ctx, repo.Owner, repo.Name, pull.Num)
+	if resp != nil {
+		logger.Debug("GET /repos/%v/%v/pulls/%d returned: %v", repo.Owner, repo.Name, pull.Num, resp.StatusCode)
+	}
+	if err != nil {
+		return "", err
+	}
+	return pullDetails.GetBody(), nil
+}
+
+// UpdatePullDescription replaces the description of the pull request.
+func (g *GithubClient) UpdatePullDescription(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, description string) error {
+	logger.Debug("Updating description for GitHub pull request %d", pull.Num)
+	_, resp, err := g.client.PullRequests.Edit(g.ctx, repo.Owner, repo.Name, pull.Num, &github.PullRequest{Body: github.Ptr(description)})
+	if resp != nil {
+		logger.Debug("PATCH /repos/%v/%v/pulls/%d returned: %v", repo.Owner, repo.Name, pull.Num, resp.StatusCode)
+	}
+	return err
+}
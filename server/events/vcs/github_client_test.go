@@ -370,6 +370,93 @@ func TestGithubClient_HideOldComments(t *testing.T) {
 	}
 }
 
+func TestGithubClient_HideOldComments_ReviewComments(t *testing.T) {
+	issueResp := strings.ReplaceAll(`[
+	{"node_id": "1", "body": "Ran Plan for 2 projects:", "user": {"login": "AtlantisUser"}}
+]`, "'", "`")
+	reviewResp := strings.ReplaceAll(`[
+	{"node_id": "2", "body": "Ran Plan for dir: 'stack1' workspace: 'default'", "user": {"login": "AtlantisUser"}},
+	{"node_id": "3", "body": "Ran Plan for dir: 'stack1' workspace: 'default'", "user": {"login": "someone-else"}}
+]`, "'", "`")
+	var minimized []string
+	testServer := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.RequestURI {
+			case "GET /api/v3/repos/owner/repo/issues/123/comments?direction=asc&sort=created":
+				w.Write([]byte(issueResp)) // nolint: errcheck
+			case "GET /api/v3/repos/owner/repo/pulls/123/comments?direction=asc&sort=created":
+				w.Write([]byte(reviewResp)) // nolint: errcheck
+			case "POST /api/graphql":
+				var call struct {
+					Variables struct {
+						Input githubv4.MinimizeCommentInput `json:"input"`
+					} `json:"variables"`
+				}
+				Ok(t, json.NewDecoder(r.Body).Decode(&call))
+				minimized = append(minimized, fmt.Sprint(call.Variables.Input.SubjectID))
+				w.Write([]byte("{}")) // nolint: errcheck
+			default:
+				t.Errorf("got unexpected request at %q", r.RequestURI)
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}),
+	)
+	testServerURL, err := url.Parse(testServer.URL)
+	Ok(t, err)
+	client, err := vcs.NewGithubClient(testServerURL.Host, &vcs.GithubUserCredentials{"AtlantisUser", "pass", ""}, vcs.GithubConfig{PlanReviewComments: true}, 0, logging.NewNoopLogger(t))
+	Ok(t, err)
+	defer disableSSLVerification()()
+
+	repo := models.Repo{FullName: "owner/repo", Owner: "owner", Name: "repo"}
+	Ok(t, client.HidePrevCommandComments(logging.NewNoopLogger(t), repo, 123, command.Plan.TitleString(), ""))
+	Equals(t, []string{"1", "2"}, minimized)
+}
+
+func TestGithubClient_CreateReviewComment(t *testing.T) {
+	type reviewComment struct {
+		Body        string `json:"body"`
+		CommitID    string `json:"commit_id"`
+		Path        string `json:"path"`
+		SubjectType string `json:"subject_type"`
+		InReplyTo   int64  `json:"in_reply_to"`
+	}
+	var comments []reviewComment
+	testServer := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.RequestURI {
+			case "POST /api/v3/repos/owner/repo/pulls/1/comments":
+				var comment reviewComment
+				Ok(t, json.NewDecoder(r.Body).Decode(&comment))
+				comments = append(comments, comment)
+				w.Write([]byte(`{"id": 42}`)) // nolint: errcheck
+			default:
+				t.Errorf("got unexpected request at %q", r.RequestURI)
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}),
+	)
+	testServerURL, err := url.Parse(testServer.URL)
+	Ok(t, err)
+	client, err := vcs.NewGithubClient(testServerURL.Host, &vcs.GithubUserCredentials{"user", "pass", ""}, vcs.GithubConfig{}, 0, logging.NewNoopLogger(t))
+	Ok(t, err)
+	defer disableSSLVerification()()
+
+	repo := models.Repo{FullName: "owner/repo", Owner: "owner", Name: "repo"}
+	pull := models.PullRequest{Num: 1, HeadCommit: "sha"}
+	Assert(t, client.SupportsReviewComments(repo), "expected review comments to be supported")
+
+	// Comments that are too long are continued in replies to the first one.
+	comment := strings.Repeat("a", 70000)
+	Ok(t, client.CreateReviewComment(logging.NewNoopLogger(t), repo, pull, "dir/main.tf", comment, "plan"))
+	Equals(t, 2, len(comments))
+	Equals(t, "sha", comments[0].CommitID)
+	Equals(t, "dir/main.tf", comments[0].Path)
+	Equals(t, "file", comments[0].SubjectType)
+	Equals(t, int64(0), comments[0].InReplyTo)
+	Equals(t, int64(42), comments[1].InReplyTo)
+	Assert(t, strings.HasPrefix(comments[1].Body, "Continued plan output from previous comment."), "got %q", comments[1].Body)
+}
+
 func TestGithubClient_UpdateStatus(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
 	cases := []struct {
@@ -435,6 +522,74 @@ func TestGithubClient_UpdateStatus(t *testing.T) {
 	}
 }
 
+func TestGithubClient_UpdateStatus_CheckRuns(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	cases := []struct {
+		status     models.CommitStatus
+		inProgress string
+		expMethod  string
+		expURI     string
+		expBody    string
+	}{
+		{
+			status:     models.PendingCommitStatus,
+			inProgress: `{"total_count":0,"check_runs":[]}`,
+			expMethod:  "POST",
+			expURI:     "/api/v3/repos/owner/repo/check-runs",
+			expBody:    `{"name":"atlantis/plan: project","head_sha":"sha","details_url":"https://google.com","status":"in_progress","output":{"title":"description","summary":"description"}}`,
+		},
+		{
+			status:     models.SuccessCommitStatus,
+			inProgress: `{"total_count":1,"check_runs":[{"id":4}]}`,
+			expMethod:  "PATCH",
+			expURI:     "/api/v3/repos/owner/repo/check-runs/4",
+			expBody:    `{"name":"atlantis/plan: project","details_url":"https://google.com","status":"completed","conclusion":"success","output":{"title":"description","summary":"description"}}`,
+		},
+		{
+			status:     models.FailedCommitStatus,
+			inProgress: `{"total_count":0,"check_runs":[]}`,
+			expMethod:  "POST",
+			expURI:     "/api/v3/repos/owner/repo/check-runs",
+			expBody:    `{"name":"atlantis/plan: project","head_sha":"sha","details_url":"https://google.com","status":"completed","conclusion":"failure","output":{"title":"description","summary":"description"}}`,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.status.String(), func(t *testing.T) {
+			called := false
+			testServer := httptest.NewTLSServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.RequestURI {
+					case "/api/v3/repos/owner/repo/commits/sha/check-runs?check_name=atlantis%2Fplan%3A+project&status=in_progress":
+						w.Write([]byte(c.inProgress)) // nolint: errcheck
+					case c.expURI:
+						Equals(t, c.expMethod, r.Method)
+						body, err := io.ReadAll(r.Body)
+						Ok(t, err)
+						Equals(t, c.expBody+"\n", string(body))
+						called = true
+						w.Write([]byte(`{"id":4}`)) // nolint: errcheck
+					default:
+						t.Errorf("got unexpected request at %q", r.RequestURI)
+						http.Error(w, "not found", http.StatusNotFound)
+						return
+					}
+				}))
+
+			testServerURL, err := url.Parse(testServer.URL)
+			Ok(t, err)
+			client, err := vcs.NewGithubClient(testServerURL.Host, &vcs.GithubUserCredentials{"user", "pass", ""}, vcs.GithubConfig{CheckRuns: true}, 0, logging.NewNoopLogger(t))
+			Ok(t, err)
+			defer disableSSLVerification()()
+
+			repo := models.Repo{FullName: "owner/repo", Owner: "owner", Name: "repo"}
+			err = client.UpdateStatus(logger, repo, models.PullRequest{Num: 1, HeadCommit: "sha"}, c.status, "atlantis/plan: project", "description", "https://google.com")
+			Ok(t, err)
+			Assert(t, called, "expected the check run to be published")
+		})
+	}
+}
+
 func TestGithubClient_PullIsApproved(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
 	respTemplate := `[
@@ -1112,6 +1267,94 @@ func TestGithubClient_MergePullHandlesError(t *testing.T) {
 	}
 }
 
+func TestGithubClient_MergePullDeletesSourceBranch(t *testing.T) {
+	cases := []struct {
+		description    string
+		deleteOnMerge  bool
+		headRepo       string
+		deleteCode     int
+		expDeleteCalls int
+	}{
+		{
+			description:    "branch in the base repo",
+			headRepo:       "owner/repo",
+			deleteCode:     http.StatusNoContent,
+			expDeleteCalls: 1,
+		},
+		{
+			description:    "deleting the branch fails",
+			headRepo:       "owner/repo",
+			deleteCode:     http.StatusForbidden,
+			expDeleteCalls: 1,
+		},
+		{
+			description: "branch in a fork",
+			headRepo:    "fork/repo",
+		},
+		{
+			description:   "repo deletes branches on merge",
+			deleteOnMerge: true,
+			headRepo:      "owner/repo",
+		},
+	}
+
+	jsBytes, err := os.ReadFile("testdata/github-repo.json")
+	Ok(t, err)
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			repoJSON := jsBytes
+			if c.deleteOnMerge {
+				repoJSON = append([]byte(`{"delete_branch_on_merge": true,`), jsBytes[1:]...)
+			}
+			deleteCalls := 0
+			testServer := httptest.NewTLSServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.Method + " " + r.RequestURI {
+					case "GET /api/v3/repos/owner/repo":
+						w.Write(repoJSON) // nolint: errcheck
+					case "PUT /api/v3/repos/owner/repo/pulls/1/merge":
+						w.Write([]byte(`{"merged":true}`)) // nolint: errcheck
+					case "GET /api/v3/repos/owner/repo/pulls/1":
+						fmt.Fprintf(w, `{"head":{"ref":"feature","repo":{"full_name":%q}}}`, c.headRepo)
+					case "DELETE /api/v3/repos/owner/repo/git/refs/heads/feature":
+						deleteCalls++
+						w.WriteHeader(c.deleteCode)
+					default:
+						t.Errorf("got unexpected request %s %q", r.Method, r.RequestURI)
+						http.Error(w, "not found", http.StatusNotFound)
+					}
+				}))
+
+			testServerURL, err := url.Parse(testServer.URL)
+			Ok(t, err)
+			client, err := vcs.NewGithubClient(testServerURL.Host, &vcs.GithubUserCredentials{"user", "pass", ""}, vcs.GithubConfig{}, 0, logging.NewNoopLogger(t))
+			Ok(t, err)
+			defer disableSSLVerification()()
+
+			err = client.MergePull(
+				logging.NewNoopLogger(t),
+				models.PullRequest{
+					BaseRepo: models.Repo{
+						FullName: "owner/repo",
+						Owner:    "owner",
+						Name:     "repo",
+						VCSHost: models.VCSHost{
+							Type:     models.Github,
+							Hostname: "github.com",
+						},
+					},
+					HeadBranch: "feature",
+					Num:        1,
+				}, models.PullRequestOptions{
+					DeleteSourceBranchOnMerge: true,
+				})
+			Ok(t, err)
+			Equals(t, c.expDeleteCalls, deleteCalls)
+		})
+	}
+}
+
 // Test that if the pull request only allows a certain merge method that we
 // use that method
 func TestGithubClient_MergePullCorrectMethod(t *testing.T) {
@@ -1367,9 +1610,9 @@ func TestGithubClient_SplitComments(t *testing.T) {
 	}
 	// create an extra long string
 	comment := strings.Repeat("a", 65537)
-	err = client.CreateComment(logger, repo, pull.Num, comment, command.Plan.String())
+	_, err = client.CreateComment(logger, repo, pull.Num, comment, command.Plan.String())
 	Ok(t, err)
-	err = client.CreateComment(logger, repo, pull.Num, comment, "")
+	_, err = client.CreateComment(logger, repo, pull.Num, comment, "")
 	Ok(t, err)
 
 	body := strings.Split(githubComments[1].Body, "\n")
@@ -1382,6 +1625,61 @@ func TestGithubClient_SplitComments(t *testing.T) {
 	Assert(t, strings.Contains(secondSplit, "continued from previous comment"), fmt.Sprintf("comment should contain no reference to the command name but was %q", secondSplit))
 }
 
+func TestGithubClient_SplitLargeComments(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	var githubComments []string
+
+	testServer := httptest.NewTLSServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.RequestURI {
+			case "POST /api/v3/repos/runatlantis/atlantis/issues/1/comments":
+				defer r.Body.Close() // nolint: errcheck
+				var requestBody struct {
+					Body string `json:"body"`
+				}
+				if err := json.NewDecoder(r.Body).Decode(&requestBody); err != nil {
+					t.Errorf("parse body error: %v", err)
+					http.Error(w, "server error", http.StatusInternalServerError)
+					return
+				}
+				githubComments = append(githubComments, requestBody.Body)
+				fmt.Fprintf(w, `{"html_url": "https://github.com/runatlantis/atlantis/pull/1#issuecomment-%d"}`, len(githubComments)) // nolint: errcheck
+				return
+			default:
+				t.Errorf("got unexpected request at %q", r.RequestURI)
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+		}))
+
+	testServerURL, err := url.Parse(testServer.URL)
+	Ok(t, err)
+	client, err := vcs.NewGithubClient(testServerURL.Host, &vcs.GithubUserCredentials{"user", "pass", ""}, vcs.GithubConfig{SplitLargeComments: true}, 0, logging.NewNoopLogger(t))
+	Ok(t, err)
+	defer disableSSLVerification()()
+	repo := models.Repo{
+		FullName: "runatlantis/atlantis",
+		Owner:    "runatlantis",
+		Name:     "atlantis",
+		VCSHost: models.VCSHost{
+			Type:     models.Github,
+			Hostname: "github.com",
+		},
+	}
+	comment := "Ran Plan for dir: `.` workspace: `default`\n\n```diff\n" + strings.Repeat("+ resource\n", 10000) + "```"
+	commentURL, err := client.CreateComment(logger, repo, 1, comment, command.Plan.String())
+	Ok(t, err)
+	Equals(t, "https://github.com/runatlantis/atlantis/pull/1#issuecomment-1", commentURL)
+
+	Equals(t, 2, len(githubComments))
+	for i, body := range githubComments {
+		header := fmt.Sprintf("Ran Plan for dir: `.` workspace: `default` (part %d/2)\n", i+1)
+		Assert(t, strings.HasPrefix(body, header), "expected comment to start with %q but was %q", header, body[:100])
+		Equals(t, 0, strings.Count(body, "```")%2)
+	}
+	Equals(t, 10000, strings.Count(githubComments[0]+githubComments[1], "+ resource\n"))
+}
+
 // Test that we retry the get pull request call if it 404s.
 func TestGithubClient_Retry404(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
@@ -1862,7 +2160,7 @@ func TestGithubClient_SecondaryRateLimitHandling_CreateComment(t *testing.T) {
 	testServerURL, err := url.Parse(testServer.URL)
 	Ok(t, err)
 
-	client, err := vcs.NewGithubClient(testServerURL.Host, &vcs.GithubUserCredentials{User: "user", Token: "pass"}, vcs.GithubConfig{}, 0, logger)
+	client, err := vcs.NewGithubClient(testServerURL.Host, &vcs.GithubUserCredentials{User: "user", Token: "pass"}, vcs.GithubConfig{RateLimitMaxWait: time.Minute}, 0, logger)
 	Ok(t, err)
 	defer disableSSLVerification()()
 
@@ -1875,7 +2173,7 @@ func TestGithubClient_SecondaryRateLimitHandling_CreateComment(t *testing.T) {
 	pullNum := 1
 	comment := "Test comment"
 
-	err = client.CreateComment(logger, repo, pullNum, comment, "")
+	_, err = client.CreateComment(logger, repo, pullNum, comment, "")
 	Ok(t, err)
 
 	// Verify that the number of calls is greater than maxCalls, indicating that retries occurred
@@ -3,7 +3,22 @@
 
 package vcs
 
+import "time"
+
 // GithubConfig allows for custom github-specific functionality and behavior
 type GithubConfig struct {
 	AllowMergeableBypassApply bool
+	// PlanReviewComments is true if plans are posted as review comments so
+	// they need to be hidden along with issue comments.
+	PlanReviewComments bool
+	// SplitLargeComments splits comments longer than GitHub's max comment
+	// length into numbered parts at line and code block boundaries.
+	SplitLargeComments bool
+	// RateLimitMaxWait is how long a request rejected by GitHub's rate limits
+	// waits in total for the limit to reset before it fails. If it's 0,
+	// rate limited requests aren't retried.
+	RateLimitMaxWait time.Duration
+	// CheckRuns is true if statuses are published as check runs instead of
+	// commit statuses. Only GitHub Apps can create check runs.
+	CheckRuns bool
 }
@@ -10,6 +10,8 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bradleyfalzon/ghinstallation/v2"
 	"github.com/google/go-github/v71/github"
@@ -113,8 +115,14 @@ type GithubAppCredentials struct {
 	Hostname       string
 	apiURL         *url.URL
 	InstallationID int64
-	tr             *ghinstallation.Transport
 	AppSlug        string
+	// TokenRefreshLeadTime is how long before the installation token expires
+	// that it's refreshed. If it's 0, DefaultGithubAppTokenRefreshLeadTime
+	// is used.
+	TokenRefreshLeadTime time.Duration
+
+	mu sync.Mutex
+	tr *githubAppTransport
 }
 
 // Client returns a github app installation client.
@@ -193,7 +201,9 @@ func (c *GithubAppCredentials) getInstallationID() (int64, error) {
 	return c.InstallationID, nil
 }
 
-func (c *GithubAppCredentials) transport() (*ghinstallation.Transport, error) {
+func (c *GithubAppCredentials) transport() (*githubAppTransport, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
 	if c.tr != nil {
 		return c.tr, nil
 	}
@@ -203,14 +213,12 @@ func (c *GithubAppCredentials) transport() (*ghinstallation.Transport, error) {
 		return nil, err
 	}
 
-	tr := http.DefaultTransport
-	itr, err := ghinstallation.New(tr, c.AppID, installationID, c.Key)
-	if err == nil {
-		apiURL := c.getAPIURL()
-		itr.BaseURL = strings.TrimSuffix(apiURL.String(), "/")
-		c.tr = itr
+	itr, err := newGithubAppTransport(http.DefaultTransport, c.AppID, installationID, c.Key, c.getAPIURL(), c.TokenRefreshLeadTime)
+	if err != nil {
+		return nil, err
 	}
-	return itr, err
+	c.tr = itr
+	return itr, nil
 }
 
 func (c *GithubAppCredentials) getAPIURL() *url.URL {
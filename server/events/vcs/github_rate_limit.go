@@ -0,0 +1,123 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package vcs
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// githubSecondaryRateLimitWait is the first wait after a secondary rate limit
+// response without a Retry-After header, it doubles with every retry. See
+// https://docs.github.com/en/rest/using-the-rest-api/best-practices-for-using-the-rest-api#handle-rate-limit-errors-appropriately
+const githubSecondaryRateLimitWait = time.Minute
+
+// githubRateLimitMaxAttempts is the most times a request is sent, so a rate
+// limit that keeps failing requests isn't retried forever.
+const githubRateLimitMaxAttempts = 5
+
+// githubRateLimitMinWait is the least time waited before a retry, so a
+// Retry-After of 0 or a rate limit reset in the past don't retry the request
+// right away.
+var githubRateLimitMinWait = time.Second
+
+// githubRateLimitTransport retries requests that are rejected by GitHub's
+// primary or secondary rate limits once the limit resets. Requests aren't
+// retried if the accumulated wait would exceed maxWait or after
+// githubRateLimitMaxAttempts attempts, the rate limit response is returned
+// instead. Other responses and errors aren't retried.
+type githubRateLimitTransport struct {
+	base    http.RoundTripper
+	maxWait time.Duration
+	logger  logging.SimpleLogging
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *githubRateLimitTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var waited time.Duration
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return resp, err
+		}
+		wait, ok := githubRateLimitWait(resp, attempt)
+		if !ok || t.maxWait == 0 {
+			return resp, nil
+		}
+		wait = max(wait, githubRateLimitMinWait)
+		if attempt == githubRateLimitMaxAttempts {
+			t.logger.Warn("GitHub rate limit exceeded for %s %s, not retrying after %d attempts", req.Method, req.URL.Path, attempt)
+			return resp, nil
+		}
+		if waited+wait > t.maxWait {
+			t.logger.Warn("GitHub rate limit exceeded for %s %s, not retrying since waiting %s would exceed the max wait of %s", req.Method, req.URL.Path, wait, t.maxWait)
+			return resp, nil
+		}
+
+		// The request body was consumed so it must be recreated to retry.
+		if req.Body != nil && req.Body != http.NoBody {
+			if req.GetBody == nil {
+				return resp, nil
+			}
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+		io.Copy(io.Discard, resp.Body) // nolint: errcheck
+		resp.Body.Close()              // nolint: errcheck
+
+		t.logger.Warn("GitHub rate limit exceeded for %s %s, retrying in %s", req.Method, req.URL.Path, wait)
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+		waited += wait
+	}
+}
+
+// githubRateLimitWait returns how long to wait before retrying the request
+// of resp, which is the attempt-th response, and whether resp is a rate
+// limit response at all.
+func githubRateLimitWait(resp *http.Response, attempt int) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+
+	if retryAfter, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && retryAfter >= 0 {
+		return time.Duration(retryAfter) * time.Second, true
+	}
+	// A 403 is only a rate limit if no requests remain or its message says
+	// so, otherwise it's ex. missing permissions.
+	if resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") != "0" && !isGithubSecondaryRateLimitBody(resp) {
+		return 0, false
+	}
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		return max(time.Until(time.Unix(reset, 0)), 0), true
+	}
+	return githubSecondaryRateLimitWait << (attempt - 1), true
+}
+
+// isGithubSecondaryRateLimitBody returns true if the body of resp is a
+// secondary rate limit error. The body is restored so it can still be read.
+func isGithubSecondaryRateLimitBody(resp *http.Response) bool {
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close() // nolint: errcheck
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(body)), "secondary rate limit")
+}
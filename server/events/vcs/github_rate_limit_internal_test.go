@@ -0,0 +1,155 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package vcs
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestGithubRateLimitTransport_RoundTrip(t *testing.T) {
+	minWait := githubRateLimitMinWait
+	githubRateLimitMinWait = time.Millisecond
+	defer func() { githubRateLimitMinWait = minWait }()
+
+	cases := []struct {
+		description string
+		header      http.Header
+		status      int
+		body        string
+		maxWait     time.Duration
+		// limited is true if every request is rate limited, not just the
+		// first.
+		limited     bool
+		expRequests int
+		expStatus   int
+	}{
+		{
+			description: "retry after",
+			header:      http.Header{"Retry-After": {"0"}},
+			status:      http.StatusForbidden,
+			maxWait:     time.Minute,
+			expRequests: 2,
+			expStatus:   http.StatusOK,
+		},
+		{
+			description: "retry after 0 keeps failing",
+			header:      http.Header{"Retry-After": {"0"}},
+			status:      http.StatusTooManyRequests,
+			maxWait:     time.Minute,
+			limited:     true,
+			expRequests: githubRateLimitMaxAttempts,
+			expStatus:   http.StatusTooManyRequests,
+		},
+		{
+			description: "no max wait",
+			header:      http.Header{"Retry-After": {"0"}},
+			status:      http.StatusTooManyRequests,
+			expRequests: 1,
+			expStatus:   http.StatusTooManyRequests,
+		},
+		{
+			description: "primary rate limit reset",
+			header:      http.Header{"X-Ratelimit-Remaining": {"0"}, "X-Ratelimit-Reset": {strconv.FormatInt(time.Now().Add(-time.Second).Unix(), 10)}},
+			status:      http.StatusForbidden,
+			maxWait:     time.Minute,
+			expRequests: 2,
+			expStatus:   http.StatusOK,
+		},
+		{
+			description: "too many requests above max wait",
+			status:      http.StatusTooManyRequests,
+			maxWait:     30 * time.Second,
+			expRequests: 1,
+			expStatus:   http.StatusTooManyRequests,
+		},
+		{
+			description: "secondary rate limit above max wait",
+			status:      http.StatusForbidden,
+			body:        `{"message": "You have exceeded a secondary rate limit."}`,
+			maxWait:     30 * time.Second,
+			expRequests: 1,
+			expStatus:   http.StatusForbidden,
+		},
+		{
+			description: "retry after above max wait",
+			header:      http.Header{"Retry-After": {"1"}},
+			status:      http.StatusForbidden,
+			expRequests: 1,
+			expStatus:   http.StatusForbidden,
+		},
+		{
+			description: "forbidden",
+			status:      http.StatusForbidden,
+			body:        `{"message": "Resource not accessible by integration"}`,
+			maxWait:     time.Minute,
+			expRequests: 1,
+			expStatus:   http.StatusForbidden,
+		},
+		{
+			description: "server error",
+			header:      http.Header{"Retry-After": {"0"}},
+			status:      http.StatusInternalServerError,
+			maxWait:     time.Minute,
+			expRequests: 1,
+			expStatus:   http.StatusInternalServerError,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				body, err := io.ReadAll(r.Body)
+				Ok(t, err)
+				Equals(t, "comment", string(body))
+				if requests > 1 && !c.limited {
+					w.WriteHeader(http.StatusOK)
+					return
+				}
+				for k, v := range c.header {
+					w.Header()[k] = v
+				}
+				w.WriteHeader(c.status)
+				w.Write([]byte(c.body)) // nolint: errcheck
+			}))
+			defer server.Close()
+
+			client := &http.Client{Transport: &githubRateLimitTransport{
+				base:    http.DefaultTransport,
+				maxWait: c.maxWait,
+				logger:  logging.NewNoopLogger(t),
+			}}
+			resp, err := client.Post(server.URL, "text/plain", strings.NewReader("comment"))
+			Ok(t, err)
+			defer resp.Body.Close()
+			body, err := io.ReadAll(resp.Body)
+			Ok(t, err)
+			Equals(t, c.expStatus, resp.StatusCode)
+			Equals(t, c.expRequests, requests)
+			if c.expStatus != http.StatusOK {
+				Equals(t, c.body, string(body))
+			}
+		})
+	}
+}
+
+func TestGithubRateLimitWait_SecondaryRateLimitBackoff(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusTooManyRequests, Header: http.Header{}}
+	wait, ok := githubRateLimitWait(resp, 1)
+	Assert(t, ok, "exp rate limit")
+	Equals(t, time.Minute, wait)
+
+	wait, ok = githubRateLimitWait(resp, 3)
+	Assert(t, ok, "exp rate limit")
+	Equals(t, 4*time.Minute, wait)
+}
@@ -19,6 +19,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"slices"
 	"strings"
 	"time"
 
@@ -49,6 +50,9 @@ type GitlabClient struct {
 	PollingTimeout time.Duration
 	// StatusRetryEnabled enables enhanced retry logic for pipeline status updates.
 	StatusRetryEnabled bool
+	// ExternalStatusChecks is true if statuses are also reported to the
+	// merge request's external status checks with the same names.
+	ExternalStatusChecks bool
 }
 
 // commonMarkSupported is a version constraint that is true when this version of
@@ -176,23 +180,36 @@ func (g *GitlabClient) GetModifiedFiles(logger logging.SimpleLogging, repo model
 }
 
 // CreateComment creates a comment on the merge request.
-func (g *GitlabClient) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, _ string) error {
+func (g *GitlabClient) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, _ string) (string, error) {
 	logger.Debug("Creating comment on GitLab merge request %d", pullNum)
 	sepEnd := "\n```\n</details>" +
 		"\n<br>\n\n**Warning**: Output length greater than max comment size. Continued in next comment."
 	sepStart := "Continued from previous comment.\n<details><summary>Show Output</summary>\n\n" +
 		"```diff\n"
+	var url string
 	comments := common.SplitComment(comment, gitlabMaxCommentLength, sepEnd, sepStart, 0, "")
-	for _, c := range comments {
-		_, resp, err := g.Client.Notes.CreateMergeRequestNote(repo.FullName, pullNum, &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.Ptr(c)})
+	for i, c := range comments {
+		note, resp, err := g.Client.Notes.CreateMergeRequestNote(repo.FullName, pullNum, &gitlab.CreateMergeRequestNoteOptions{Body: gitlab.Ptr(c)})
 		if resp != nil {
 			logger.Debug("POST /projects/%s/merge_requests/%d/notes returned: %d", repo.FullName, pullNum, resp.StatusCode)
 		}
 		if err != nil {
-			return err
+			return "", err
+		}
+		if i == 0 {
+			url = g.noteURL(repo, pullNum, note.ID)
 		}
 	}
-	return nil
+	return url, nil
+}
+
+// noteURL returns the web URL of the note noteID on the merge request. The
+// notes API doesn't return it so it's built from the API's base URL.
+func (g *GitlabClient) noteURL(repo models.Repo, pullNum int, noteID int) string {
+	u := *g.Client.BaseURL()
+	u.Path = fmt.Sprintf("%s%s/-/merge_requests/%d", strings.TrimSuffix(u.Path, "api/v4/"), repo.FullName, pullNum)
+	u.Fragment = fmt.Sprintf("note_%d", noteID)
+	return u.String()
 }
 
 // ReactToComment adds a reaction to a comment.
@@ -365,6 +382,11 @@ func (g *GitlabClient) PullIsMergeable(logger logging.SimpleLogging, repo models
 	}
 
 	res := gitlabIsMergeable(mr, project, supportsDetailedMergeStatus)
+	if res.IsMergeable {
+		// The merge status doesn't always reflect approval rules, ex. when
+		// it's ci_must_pass, so they're checked separately.
+		res = g.approvalRulesStatus(logger, mr.ProjectID, pull.Num)
+	}
 	if res.IsMergeable {
 		logger.Debug("Merge request is mergeable")
 	} else {
@@ -373,6 +395,41 @@ func (g *GitlabClient) PullIsMergeable(logger logging.SimpleLogging, repo models
 	return res, nil
 }
 
+// approvalRulesStatus returns whether all the approval rules of the merge
+// request are satisfied. Approval rules are only available on some GitLab
+// tiers, so if the API isn't available or fails the rules are ignored and
+// only the merge request's approvals are checked.
+func (g *GitlabClient) approvalRulesStatus(logger logging.SimpleLogging, projectID int, mrID int) models.MergeableStatus {
+	state, resp, err := g.Client.MergeRequestApprovals.GetApprovalState(projectID, mrID)
+	if resp != nil {
+		logger.Debug("GET /projects/%d/merge_requests/%d/approval_state returned: %d", projectID, mrID, resp.StatusCode)
+	}
+	if err != nil {
+		if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound) {
+			logger.Debug("Approval rules aren't available, ignoring them: %s", err)
+		} else {
+			logger.Warn("unable to get approval state, ignoring approval rules: %s", err)
+		}
+		return models.MergeableStatus{IsMergeable: true}
+	}
+
+	var unapproved []string
+	for _, rule := range state.Rules {
+		if !rule.Approved {
+			unapproved = append(unapproved, rule.Name)
+		}
+	}
+	if len(unapproved) > 0 {
+		return models.MergeableStatus{
+			IsMergeable: false,
+			Reason:      fmt.Sprintf("Approval rules not satisfied: %s", strings.Join(unapproved, ", ")),
+		}
+	}
+	return models.MergeableStatus{
+		IsMergeable: true,
+	}
+}
+
 // gitlabIsMergeable a pure function that encapsulates the tricky logic behind determining whether a gitlab MR is mergeable
 // It doesn't make any external calls and cannot error, so is much easier to test
 func gitlabIsMergeable(mr *gitlab.MergeRequest, project *gitlab.Project, supportsDetailedMergeStatus bool) models.MergeableStatus {
@@ -446,6 +503,55 @@ func (g *GitlabClient) SupportsDetailedMergeStatus(logger logging.SimpleLogging)
 	return cons.Check(v), nil
 }
 
+// updateExternalStatusCheck sets the status of the merge request's external
+// status check named src so merge checks can depend on it, ex. a check named
+// "atlantis/apply: project1". It does nothing if there's no check with that
+// name.
+func (g *GitlabClient) updateExternalStatusCheck(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, state models.CommitStatus, src string) error {
+	var check *gitlab.MergeStatusCheck
+	opts := &gitlab.ListOptions{PerPage: 100}
+	for {
+		checks, resp, err := g.Client.ExternalStatusChecks.ListMergeStatusChecks(repo.FullName, pull.Num, opts)
+		if resp != nil {
+			logger.Debug("GET /projects/%s/merge_requests/%d/status_checks returned: %d", repo.FullName, pull.Num, resp.StatusCode)
+		}
+		if err != nil {
+			return errors.Wrap(err, "listing external status checks")
+		}
+		if i := slices.IndexFunc(checks, func(check *gitlab.MergeStatusCheck) bool { return check.Name == src }); i >= 0 {
+			check = checks[i]
+			break
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	if check == nil {
+		logger.Debug("no external status check named '%s'", src)
+		return nil
+	}
+
+	status := "pending"
+	switch state {
+	case models.SuccessCommitStatus:
+		status = "passed"
+	case models.FailedCommitStatus:
+		status = "failed"
+	}
+	logger.Info("Updating GitLab external status check '%s' to '%s'", src, status)
+
+	resp, err := g.Client.ExternalStatusChecks.SetExternalStatusCheckStatus(repo.FullName, pull.Num, &gitlab.SetExternalStatusCheckStatusOptions{
+		SHA:                   gitlab.Ptr(pull.HeadCommit),
+		ExternalStatusCheckID: gitlab.Ptr(check.ID),
+		Status:                gitlab.Ptr(status),
+	})
+	if resp != nil {
+		logger.Debug("POST /projects/%s/merge_requests/%d/status_check_responses returned: %d", repo.FullName, pull.Num, resp.StatusCode)
+	}
+	return errors.Wrap(err, "setting external status check")
+}
+
 // UpdateStatus updates the build status of a commit.
 func (g *GitlabClient) UpdateStatus(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, state models.CommitStatus, src string, description string, url string) error {
 	gitlabState := gitlab.Pending
@@ -458,6 +564,14 @@ func (g *GitlabClient) UpdateStatus(logger logging.SimpleLogging, repo models.Re
 		gitlabState = gitlab.Success
 	}
 
+	// The commit status is still set if the external status check can't be
+	// so the status is shown on the merge request either way.
+	if g.ExternalStatusChecks {
+		if err := g.updateExternalStatusCheck(logger, repo, pull, state, src); err != nil {
+			logger.Err("unable to update GitLab external status check '%s': %s", src, err)
+		}
+	}
+
 	logger.Info("Updating GitLab commit status for '%s' to '%s'", src, gitlabState)
 
 	setCommitStatusOptions := &gitlab.SetCommitStatusOptions{
@@ -625,6 +739,11 @@ func (g *GitlabClient) MergePull(logger logging.SimpleLogging, pull models.PullR
 		return errors.Wrap(err, "unable to merge merge request, it was not possible to check the project requirements")
 	}
 
+	squash, err := gitlabSquash(project, pullOptions.MergeMethod)
+	if err != nil {
+		return err
+	}
+
 	if project != nil && project.OnlyAllowMergeIfPipelineSucceeds {
 		g.WaitForSuccessPipeline(logger, context.Background(), pull)
 	}
@@ -635,6 +754,7 @@ func (g *GitlabClient) MergePull(logger logging.SimpleLogging, pull models.PullR
 		&gitlab.AcceptMergeRequestOptions{
 			MergeCommitMessage:       &commitMsg,
 			ShouldRemoveSourceBranch: &pullOptions.DeleteSourceBranchOnMerge,
+			Squash:                   squash,
 		})
 	if resp != nil {
 		logger.Debug("PUT /projects/%s/merge_requests/%d/merge returned: %d", pull.BaseRepo.FullName, pull.Num, resp.StatusCode)
@@ -645,6 +765,33 @@ func (g *GitlabClient) MergePull(logger logging.SimpleLogging, pull models.PullR
 	return nil
 }
 
+// gitlabSquash returns whether a merge request should be squashed when it's
+// merged with method. GitLab only supports merging with or without squashing,
+// the merge method (ex. fast-forward) is a project setting. A nil result
+// leaves it to the project's squash commits setting.
+func gitlabSquash(project *gitlab.Project, method string) (*bool, error) {
+	var squashOption gitlab.SquashOptionValue
+	if project != nil {
+		squashOption = project.SquashOption
+	}
+	switch method {
+	case "":
+		return nil, nil
+	case "merge":
+		if squashOption == gitlab.SquashOptionAlways {
+			return nil, fmt.Errorf("merge method '%s' is not allowed by the project, it always squashes commits", method)
+		}
+		return gitlab.Ptr(false), nil
+	case "squash":
+		if squashOption == gitlab.SquashOptionNever {
+			return nil, fmt.Errorf("merge method '%s' is not allowed by the project, it never squashes commits", method)
+		}
+		return gitlab.Ptr(true), nil
+	default:
+		return nil, fmt.Errorf("merge method '%s' is not supported by GitLab. Specify one of the valid values: 'merge, squash'", method)
+	}
+}
+
 // MarkdownPullLink specifies the string used in a pull request comment to reference another pull request.
 func (g *GitlabClient) MarkdownPullLink(pull models.PullRequest) (string, error) {
 	return fmt.Sprintf("!%d", pull.Num), nil
@@ -794,3 +941,92 @@ func (g *GitlabClient) GetPullLabels(logger logging.SimpleLogging, repo models.R
 
 	return mr.Labels, nil
 }
+
+// GetPipelineStatus returns the status of the merge request's latest
+// pipeline, which is the pipeline GitLab checks before merging. Atlantis's
+// own commit statuses, the ones named vcsStatusName/..., are added to that
+// pipeline so if it hasn't succeeded its status is computed from its other
+// jobs and statuses, the same way PullIsMergeable ignores the apply status.
+func (g *GitlabClient) GetPipelineStatus(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, vcsStatusName string) (models.PipelineStatus, error) {
+	logger.Debug("Getting GitLab pipeline status for merge request %d", pull.Num)
+	mr, resp, err := g.Client.MergeRequests.GetMergeRequest(repo.FullName, pull.Num, nil)
+	if resp != nil {
+		logger.Debug("GET /projects/%s/merge_requests/%d returned: %d", repo.FullName, pull.Num, resp.StatusCode)
+	}
+	if err != nil {
+		return models.PipelineStatus{}, err
+	}
+	if mr.HeadPipeline == nil {
+		return models.PipelineStatus{}, nil
+	}
+	status := models.PipelineStatus{
+		Status: mr.HeadPipeline.Status,
+		URL:    mr.HeadPipeline.WebURL,
+	}
+	if status.Status == "success" {
+		return status, nil
+	}
+
+	status.Status = ""
+	opts := &gitlab.GetCommitStatusesOptions{ListOptions: gitlab.ListOptions{PerPage: 100}}
+	for {
+		statuses, resp, err := g.Client.Commits.GetCommitStatuses(repo.FullName, mr.HeadPipeline.SHA, opts)
+		if resp != nil {
+			logger.Debug("GET /projects/%s/repository/commits/%s/statuses returned: %d", repo.FullName, mr.HeadPipeline.SHA, resp.StatusCode)
+		}
+		if err != nil {
+			return models.PipelineStatus{}, err
+		}
+		for _, s := range statuses {
+			if s.PipelineId != mr.HeadPipeline.ID || s.AllowFailure || strings.HasPrefix(s.Name, vcsStatusName+"/") {
+				continue
+			}
+			if s.Status != "success" {
+				status.Status = s.Status
+				return status, nil
+			}
+			status.Status = "success"
+		}
+		if resp == nil || resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	// A pipeline with only Atlantis's statuses is the one GitLab creates for
+	// them if the commit has no pipeline.
+	if status.Status == "" {
+		return models.PipelineStatus{}, nil
+	}
+	return status, nil
+}
+
+// GetPullDescription returns the description of the merge request.
+func (g *GitlabClient) GetPullDescription(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest) (string, error) {
+	logger.Debug("Getting GitLab description for merge request %d", pull.Num)
+	mr, resp, err := g.Client.MergeRequests.GetMergeRequest(repo.FullName, pull.Num, nil)
+	if resp != nil {
+		logger.Debug("GET /projects/%s/merge_requests/%d returned: %d", repo.FullName, pull.Num, resp.StatusCode)
+	}
+	if err != nil {
+		return "", err
+	}
+	return mr.Description, nil
+}
+
+// UpdatePullDescription replaces the description of the merge request.
+func (g *GitlabClient) UpdatePullDescription(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, description string) error {
+	logger.Debug("Updating GitLab description for merge request %d", pull.Num)
+	_, resp, err := g.Client.MergeRequests.UpdateMergeRequest(repo.FullName, pull.Num, &gitlab.UpdateMergeRequestOptions{Description: gitlab.Ptr(description)})
+	if resp != nil {
+		logger.Debug("PUT /projects/%s/merge_requests/%d returned: %d", repo.FullName, pull.Num, resp.StatusCode)
+	}
+	return err
+}
+
+func (g *GitlabClient) SupportsReviewComments(_ models.Repo) bool {
+	return false
+}
+
+func (g *GitlabClient) CreateReviewComment(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest, _ string, _ string, _ string) error {
+	return fmt.Errorf("not yet implemented")
+}
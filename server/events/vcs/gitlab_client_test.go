@@ -6,6 +6,7 @@ package vcs
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
@@ -59,7 +60,6 @@ type EmptyStruct struct{}
 // Test that the base url gets set properly.
 func TestNewGitlabClient_BaseURL(t *testing.T) {
 	gitlabClientUnderTest = true
-	defer func() { gitlabClientUnderTest = false }()
 
 	cases := []struct {
 		Hostname   string
@@ -303,6 +303,116 @@ func TestGitlabClient_MergePull(t *testing.T) {
 	}
 }
 
+func TestGitlabClient_MergePull_MergeMethod(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	mergeSuccess, err := os.ReadFile("testdata/github-pull-request.json")
+	Ok(t, err)
+
+	pipelineSuccess, err := os.ReadFile("testdata/gitlab-pipeline-success.json")
+	Ok(t, err)
+
+	cases := []struct {
+		description  string
+		mergeMethod  string
+		squashOption string
+		expSquash    *bool
+		expErr       string
+	}{
+		{
+			description:  "default",
+			squashOption: "default_off",
+		},
+		{
+			description:  "squash",
+			mergeMethod:  "squash",
+			squashOption: "default_off",
+			expSquash:    gitlab.Ptr(true),
+		},
+		{
+			description:  "merge",
+			mergeMethod:  "merge",
+			squashOption: "default_on",
+			expSquash:    gitlab.Ptr(false),
+		},
+		{
+			description:  "squash never allowed",
+			mergeMethod:  "squash",
+			squashOption: "never",
+			expErr:       "merge method 'squash' is not allowed by the project, it never squashes commits",
+		},
+		{
+			description:  "merge always squashed",
+			mergeMethod:  "merge",
+			squashOption: "always",
+			expErr:       "merge method 'merge' is not allowed by the project, it always squashes commits",
+		},
+		{
+			description:  "rebase unsupported",
+			mergeMethod:  "rebase",
+			squashOption: "default_off",
+			expErr:       "merge method 'rebase' is not supported by GitLab. Specify one of the valid values: 'merge, squash'",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			merged := false
+			testServer := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.URL.Path {
+					case "/api/v4/projects/runatlantis/atlantis/merge_requests/1/merge":
+						merged = true
+						var body gitlab.AcceptMergeRequestOptions
+						Ok(t, json.NewDecoder(r.Body).Decode(&body))
+						Equals(t, c.expSquash, body.Squash)
+						w.WriteHeader(http.StatusOK)
+						w.Write(mergeSuccess) // nolint: errcheck
+					case "/api/v4/projects/runatlantis/atlantis/merge_requests/1":
+						w.WriteHeader(http.StatusOK)
+						w.Write(pipelineSuccess) // nolint: errcheck
+					case "/api/v4/projects/4580910":
+						w.WriteHeader(http.StatusOK)
+						fmt.Fprintf(w, `{"id": 4580910, "squash_option": %q}`, c.squashOption) // nolint: errcheck
+					case "/api/v4/":
+						// Rate limiter requests.
+						w.WriteHeader(http.StatusOK)
+					default:
+						t.Errorf("got unexpected request at %q", r.RequestURI)
+						http.Error(w, "not found", http.StatusNotFound)
+					}
+				}))
+			defer testServer.Close()
+
+			internalClient, err := gitlab.NewClient("token", gitlab.WithBaseURL(testServer.URL))
+			Ok(t, err)
+			client := &GitlabClient{
+				Client:  internalClient,
+				Version: nil,
+			}
+
+			err = client.MergePull(
+				logger,
+				models.PullRequest{
+					Num: 1,
+					BaseRepo: models.Repo{
+						FullName: "runatlantis/atlantis",
+						Owner:    "runatlantis",
+						Name:     "atlantis",
+					},
+				}, models.PullRequestOptions{
+					MergeMethod: c.mergeMethod,
+				})
+			if c.expErr == "" {
+				Ok(t, err)
+				Assert(t, merged, "exp merge request to be merged")
+			} else {
+				ErrEquals(t, c.expErr, err)
+				Assert(t, !merged, "exp merge request not to be merged")
+			}
+		})
+	}
+}
+
 func TestGitlabClient_UpdateStatus(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
 
@@ -406,6 +516,142 @@ func TestGitlabClient_UpdateStatus(t *testing.T) {
 	}
 }
 
+func TestGitlabClient_UpdateStatus_ExternalStatusChecks(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+
+	cases := []struct {
+		src       string
+		status    models.CommitStatus
+		expStatus string
+	}{
+		{"atlantis/plan: project1", models.PendingCommitStatus, "pending"},
+		{"atlantis/plan: project1", models.SuccessCommitStatus, "passed"},
+		{"atlantis/plan: project1", models.FailedCommitStatus, "failed"},
+		{"atlantis/plan: project2", models.SuccessCommitStatus, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.src+" "+c.status.String(), func(t *testing.T) {
+			var gotResponse string
+			testServer := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.RequestURI {
+					case "/api/v4/projects/runatlantis%2Fatlantis/merge_requests/1/status_checks?per_page=100":
+						w.Write([]byte(`[{"id": 7, "name": "atlantis/plan: project1", "status": "pending"}]`)) // nolint: errcheck
+					case "/api/v4/projects/runatlantis%2Fatlantis/merge_requests/1/status_check_responses":
+						body, err := io.ReadAll(r.Body)
+						Ok(t, err)
+						gotResponse = string(body)
+						w.Write([]byte(`{}`)) // nolint: errcheck
+					case "/api/v4/projects/runatlantis%2Fatlantis/statuses/sha":
+						w.Write([]byte(`{}`)) // nolint: errcheck
+					case "/api/v4/projects/runatlantis%2Fatlantis/repository/commits/sha":
+						w.Write([]byte(fmt.Sprintf(`{"last_pipeline": {"id": %d}}`, gitlabPipelineSuccessMrID))) // nolint: errcheck
+					case "/api/v4/":
+						// Rate limiter requests.
+						w.WriteHeader(http.StatusOK)
+					default:
+						t.Errorf("got unexpected request at %q", r.RequestURI)
+						http.Error(w, "not found", http.StatusNotFound)
+					}
+				}))
+
+			internalClient, err := gitlab.NewClient("token", gitlab.WithBaseURL(testServer.URL))
+			Ok(t, err)
+			client := &GitlabClient{
+				Client:               internalClient,
+				ExternalStatusChecks: true,
+			}
+
+			repo := models.Repo{
+				FullName: "runatlantis/atlantis",
+				Owner:    "runatlantis",
+				Name:     "atlantis",
+			}
+			err = client.UpdateStatus(logger, repo, models.PullRequest{Num: 1, BaseRepo: repo, HeadCommit: "sha"}, c.status, c.src, updateStatusDescription, updateStatusTargetUrl)
+			Ok(t, err)
+			if c.expStatus == "" {
+				Equals(t, "", gotResponse)
+				return
+			}
+			Equals(t, fmt.Sprintf(`{"sha":"sha","external_status_check_id":7,"status":"%s"}`, c.expStatus), gotResponse)
+		})
+	}
+}
+
+// Test that external status checks are found on later pages and that the
+// commit status is still set if they can't be listed.
+func TestGitlabClient_UpdateStatus_ExternalStatusChecksPages(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+
+	cases := []struct {
+		description string
+		listFails   bool
+		expResponse string
+	}{
+		{
+			description: "check on second page",
+			expResponse: `{"sha":"sha","external_status_check_id":8,"status":"passed"}`,
+		},
+		{
+			description: "listing checks fails",
+			listFails:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			var gotResponse string
+			statusSet := false
+			testServer := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.RequestURI {
+					case "/api/v4/projects/runatlantis%2Fatlantis/merge_requests/1/status_checks?per_page=100":
+						if c.listFails {
+							http.Error(w, "server error", http.StatusInternalServerError)
+							return
+						}
+						w.Header().Set("X-Next-Page", "2")
+						w.Write([]byte(`[{"id": 7, "name": "atlantis/plan: project2", "status": "pending"}]`)) // nolint: errcheck
+					case "/api/v4/projects/runatlantis%2Fatlantis/merge_requests/1/status_checks?page=2&per_page=100":
+						w.Write([]byte(`[{"id": 8, "name": "atlantis/plan: project1", "status": "pending"}]`)) // nolint: errcheck
+					case "/api/v4/projects/runatlantis%2Fatlantis/merge_requests/1/status_check_responses":
+						body, err := io.ReadAll(r.Body)
+						Ok(t, err)
+						gotResponse = string(body)
+						w.Write([]byte(`{}`)) // nolint: errcheck
+					case "/api/v4/projects/runatlantis%2Fatlantis/statuses/sha":
+						statusSet = true
+						w.Write([]byte(`{}`)) // nolint: errcheck
+					case "/api/v4/projects/runatlantis%2Fatlantis/repository/commits/sha":
+						w.Write([]byte(fmt.Sprintf(`{"last_pipeline": {"id": %d}}`, gitlabPipelineSuccessMrID))) // nolint: errcheck
+					case "/api/v4/":
+						// Rate limiter requests.
+						w.WriteHeader(http.StatusOK)
+					default:
+						t.Errorf("got unexpected request at %q", r.RequestURI)
+						http.Error(w, "not found", http.StatusNotFound)
+					}
+				}))
+
+			internalClient, err := gitlab.NewClient("token", gitlab.WithBaseURL(testServer.URL), gitlab.WithCustomRetryMax(0))
+			Ok(t, err)
+			client := &GitlabClient{
+				Client:               internalClient,
+				ExternalStatusChecks: true,
+			}
+
+			repo := models.Repo{
+				FullName: "runatlantis/atlantis",
+				Owner:    "runatlantis",
+				Name:     "atlantis",
+			}
+			err = client.UpdateStatus(logger, repo, models.PullRequest{Num: 1, BaseRepo: repo, HeadCommit: "sha"}, models.SuccessCommitStatus, "atlantis/plan: project1", updateStatusDescription, updateStatusTargetUrl)
+			Ok(t, err)
+			Equals(t, c.expResponse, gotResponse)
+			Assert(t, statusSet, "exp commit status to be set")
+		})
+	}
+}
+
 func TestGitlabClient_UpdateStatusGetCommitRetryable(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
 
@@ -1056,6 +1302,9 @@ func TestGitlabClient_PullIsMergeable(t *testing.T) {
 							w.WriteHeader(http.StatusOK)
 							response := fmt.Sprintf(`[{"id":133702594,"sha":"67cb91d3f6198189f433c045154a885784ba6977","ref":"patch-1","status":"%s","name":"%s","target_url":null,"description":"ApplySuccess","created_at":"2018-12-12T18:31:57.957Z","started_at":null,"finished_at":"2018-12-12T18:31:58.480Z","allow_failure":false,"coverage":null,"author":{"id":1755902,"username":"lkysow","name":"LukeKysow","state":"active","avatar_url":"https://secure.gravatar.com/avatar/25fd57e71590fe28736624ff24d41c5f?s=80&d=identicon","web_url":"https://gitlab.com/lkysow"}}]`, c.status, c.statusName)
 							w.Write([]byte(response)) // nolint: errcheck
+						case r.RequestURI == fmt.Sprintf("/api/v4/projects/%v/merge_requests/%d/approval_state", projectID, c.mrID):
+							w.WriteHeader(http.StatusOK)
+							w.Write([]byte(`{"approval_rules_overwritten":false,"rules":[{"id":1,"name":"All Members","approved":true}]}`)) // nolint: errcheck
 						case r.RequestURI == "/api/v4/version":
 							w.WriteHeader(http.StatusOK)
 							w.Header().Set("Content-Type", "application/json")
@@ -1104,6 +1353,110 @@ func TestGitlabClient_PullIsMergeable(t *testing.T) {
 	}
 }
 
+func TestGitlabClient_PullIsMergeable_ApprovalRules(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	gitlabClientUnderTest = true
+	mr := mustReadFile(t, "testdata/gitlab-pipeline-success.json")
+	project := mustReadFile(t, "testdata/gitlab-project-success.json")
+
+	cases := []struct {
+		description string
+		status      int
+		response    string
+		expState    models.MergeableStatus
+		expErr      string
+	}{
+		{
+			description: "all rules approved",
+			status:      http.StatusOK,
+			response:    `{"rules":[{"name":"All Members","approved":true},{"name":"Security","approved":true}]}`,
+			expState:    models.MergeableStatus{IsMergeable: true},
+		},
+		{
+			description: "rules not approved",
+			status:      http.StatusOK,
+			response:    `{"rules":[{"name":"All Members","approved":true},{"name":"Security","approved":false},{"name":"DBA","approved":false}]}`,
+			expState: models.MergeableStatus{
+				IsMergeable: false,
+				Reason:      "Approval rules not satisfied: Security, DBA",
+			},
+		},
+		{
+			description: "approval rules not licensed",
+			status:      http.StatusForbidden,
+			response:    `{"message":"403 Forbidden"}`,
+			expState:    models.MergeableStatus{IsMergeable: true},
+		},
+		{
+			description: "approval state endpoint not found",
+			status:      http.StatusNotFound,
+			response:    `{"message":"404 Not found"}`,
+			expState:    models.MergeableStatus{IsMergeable: true},
+		},
+		{
+			description: "approval state endpoint unauthorized",
+			status:      http.StatusUnauthorized,
+			response:    `{"message":"401 Unauthorized"}`,
+			expState:    models.MergeableStatus{IsMergeable: true},
+		},
+		{
+			description: "approval state endpoint fails",
+			status:      http.StatusInternalServerError,
+			response:    `{"message":"500 Internal Server Error"}`,
+			expState:    models.MergeableStatus{IsMergeable: true},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			testServer := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.RequestURI {
+					case "/api/v4/":
+						// Rate limiter requests.
+						w.WriteHeader(http.StatusOK)
+					case "/api/v4/projects/runatlantis%2Fatlantis/merge_requests/1":
+						w.Write(mr) // nolint: errcheck
+					case fmt.Sprintf("/api/v4/projects/%v", projectID):
+						w.Write(project) // nolint: errcheck
+					case fmt.Sprintf("/api/v4/projects/%v/repository/commits/67cb91d3f6198189f433c045154a885784ba6977/statuses", projectID):
+						w.Write([]byte("[]")) // nolint: errcheck
+					case fmt.Sprintf("/api/v4/projects/%v/merge_requests/1/approval_state", projectID):
+						w.WriteHeader(c.status)
+						w.Write([]byte(c.response)) // nolint: errcheck
+					case "/api/v4/version":
+						w.Header().Set("Content-Type", "application/json")
+						w.Write([]byte(`{"version":"15.8.3-ee"}`)) // nolint: errcheck
+					default:
+						t.Errorf("got unexpected request at %q", r.RequestURI)
+						http.Error(w, "not found", http.StatusNotFound)
+					}
+				}))
+			defer testServer.Close()
+
+			internalClient, err := gitlab.NewClient("token", gitlab.WithBaseURL(testServer.URL), gitlab.WithCustomRetryMax(0))
+			Ok(t, err)
+			client := &GitlabClient{Client: internalClient}
+			repo := models.Repo{
+				FullName: "runatlantis/atlantis",
+				Owner:    "runatlantis",
+				Name:     "atlantis",
+			}
+
+			mergeable, err := client.PullIsMergeable(logger, repo, models.PullRequest{
+				Num:        1,
+				BaseRepo:   repo,
+				HeadCommit: "67cb91d3f6198189f433c045154a885784ba6977",
+			}, "atlantis-test", []string{})
+			if c.expErr != "" {
+				ErrEquals(t, fmt.Sprintf(c.expErr, testServer.URL), err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, c.expState, mergeable)
+		})
+	}
+}
+
 func TestGitlabClient_gitlabIsMergeable(t *testing.T) {
 	// Test the helper gitlabIsMergeable directly
 
@@ -1260,7 +1613,6 @@ func TestGitlabClient_gitlabIsMergeable(t *testing.T) {
 func TestGitlabClient_MarkdownPullLink(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
 	gitlabClientUnderTest = true
-	defer func() { gitlabClientUnderTest = false }()
 	client, err := NewGitlabClient("gitlab.com", "token", []string{}, logger)
 	Ok(t, err)
 	pull := models.PullRequest{Num: 1}
@@ -1413,6 +1765,32 @@ func TestGitlabClient_HideOldComments(t *testing.T) {
 	}
 }
 
+// Test that CreateComment returns the web URL of the note.
+func TestGitlabClient_CreateComment(t *testing.T) {
+	logger := logging.NewNoopLogger(t)
+	testServer := httptest.NewServer(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method + " " + r.RequestURI {
+			case "POST /api/v4/projects/runatlantis%2Fatlantis/merge_requests/1/notes":
+				w.Write([]byte(`{"id": 301}`)) // nolint: errcheck
+			default:
+				t.Errorf("got unexpected request at %q", r.RequestURI)
+				http.Error(w, "not found", http.StatusNotFound)
+			}
+		}))
+
+	internalClient, err := gitlab.NewClient("token", gitlab.WithBaseURL(testServer.URL))
+	Ok(t, err)
+	client := &GitlabClient{
+		Client:  internalClient,
+		Version: nil,
+	}
+
+	url, err := client.CreateComment(logger, models.Repo{FullName: "runatlantis/atlantis"}, 1, "comment", "")
+	Ok(t, err)
+	Equals(t, testServer.URL+"/runatlantis/atlantis/-/merge_requests/1#note_301", url)
+}
+
 func TestGitlabClient_GetPullLabels(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
 	mergeSuccessWithLabel, err := os.ReadFile("testdata/gitlab-merge-success-with-label.json")
@@ -1485,6 +1863,96 @@ func TestGitlabClient_GetPullLabels_EmptyResponse(t *testing.T) {
 	Equals(t, 0, len(labels))
 }
 
+func TestGitlabClient_GetPipelineStatus(t *testing.T) {
+	mrWithPipeline := func(status string) []byte {
+		return []byte(fmt.Sprintf(`{"head_pipeline": {"id": 488598, "sha": "sha", "status": %q, "web_url": "https://gitlab.com/lkysow/atlantis-example/-/pipelines/488598"}}`, status))
+	}
+	cases := []struct {
+		description string
+		mr          []byte
+		statuses    string
+		exp         models.PipelineStatus
+	}{
+		{
+			description: "head pipeline",
+			mr:          mustReadFile(t, "testdata/gitlab-pipeline-success.json"),
+			exp: models.PipelineStatus{
+				Status: "success",
+				URL:    "https://gitlab.com/lkysow/atlantis-example/-/pipelines/488598",
+			},
+		},
+		{
+			description: "no head pipeline",
+			mr:          mustReadFile(t, "testdata/gitlab-head-pipeline-not-available.json"),
+		},
+		{
+			description: "pending atlantis status",
+			mr:          mrWithPipeline("running"),
+			statuses: `[
+				{"name": "build", "status": "success", "pipeline_id": 488598},
+				{"name": "atlantis/apply", "status": "running", "pipeline_id": 488598},
+				{"name": "atlantis/apply: project1", "status": "running", "pipeline_id": 488598},
+				{"name": "lint", "status": "failed", "allow_failure": true, "pipeline_id": 488598},
+				{"name": "test", "status": "failed", "pipeline_id": 1}
+			]`,
+			exp: models.PipelineStatus{
+				Status: "success",
+				URL:    "https://gitlab.com/lkysow/atlantis-example/-/pipelines/488598",
+			},
+		},
+		{
+			description: "running job",
+			mr:          mrWithPipeline("running"),
+			statuses: `[
+				{"name": "build", "status": "success", "pipeline_id": 488598},
+				{"name": "test", "status": "running", "pipeline_id": 488598},
+				{"name": "atlantis/apply", "status": "running", "pipeline_id": 488598}
+			]`,
+			exp: models.PipelineStatus{
+				Status: "running",
+				URL:    "https://gitlab.com/lkysow/atlantis-example/-/pipelines/488598",
+			},
+		},
+		{
+			description: "only atlantis statuses",
+			mr:          mrWithPipeline("running"),
+			statuses:    `[{"name": "atlantis/apply", "status": "running", "pipeline_id": 488598}]`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.description, func(t *testing.T) {
+			testServer := httptest.NewServer(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					switch r.RequestURI {
+					case "/api/v4/projects/runatlantis%2Fatlantis/merge_requests/1":
+						w.WriteHeader(http.StatusOK)
+						w.Write(c.mr) // nolint: errcheck
+					case "/api/v4/projects/runatlantis%2Fatlantis/repository/commits/sha/statuses?per_page=100":
+						w.WriteHeader(http.StatusOK)
+						w.Write([]byte(c.statuses)) // nolint: errcheck
+					default:
+						t.Errorf("got unexpected request at %q", r.RequestURI)
+						http.Error(w, "not found", http.StatusNotFound)
+					}
+				}))
+			defer testServer.Close()
+
+			internalClient, err := gitlab.NewClient("token", gitlab.WithBaseURL(testServer.URL))
+			Ok(t, err)
+			client := &GitlabClient{Client: internalClient}
+
+			status, err := client.GetPipelineStatus(
+				logging.NewNoopLogger(t),
+				models.Repo{FullName: "runatlantis/atlantis"},
+				models.PullRequest{Num: 1},
+				"atlantis",
+			)
+			Ok(t, err)
+			Equals(t, c.exp, status)
+		})
+	}
+}
+
 // GetTeamNamesForUser returns the names of the GitLab groups that the user belongs to.
 func TestGitlabClient_GetTeamNamesForUser(t *testing.T) {
 	logger := logging.NewNoopLogger(t)
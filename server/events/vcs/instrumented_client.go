@@ -104,7 +104,7 @@ func (c *InstrumentedClient) GetModifiedFiles(logger logging.SimpleLogging, repo
 	return files, err
 }
 
-func (c *InstrumentedClient) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) error {
+func (c *InstrumentedClient) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) (string, error) {
 	scope := c.StatsScope.SubScope("create_comment")
 	scope = SetGitScopeTags(scope, repo.FullName, pullNum)
 
@@ -114,14 +114,15 @@ func (c *InstrumentedClient) CreateComment(logger logging.SimpleLogging, repo mo
 	executionSuccess := scope.Counter(metrics.ExecutionSuccessMetric)
 	executionError := scope.Counter(metrics.ExecutionErrorMetric)
 
-	if err := c.Client.CreateComment(logger, repo, pullNum, comment, command); err != nil {
+	url, err := c.Client.CreateComment(logger, repo, pullNum, comment, command)
+	if err != nil {
 		executionError.Inc(1)
 		logger.Err("Unable to create comment for command %s, error: %s", command, err.Error())
-		return err
+		return "", err
 	}
 
 	executionSuccess.Inc(1)
-	return nil
+	return url, nil
 }
 
 func (c *InstrumentedClient) ReactToComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, commentID int64, reaction string) error {
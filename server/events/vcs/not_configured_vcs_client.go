@@ -30,8 +30,8 @@ type NotConfiguredVCSClient struct {
 func (a *NotConfiguredVCSClient) GetModifiedFiles(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest) ([]string, error) {
 	return nil, a.err()
 }
-func (a *NotConfiguredVCSClient) CreateComment(_ logging.SimpleLogging, _ models.Repo, _ int, _ string, _ string) error {
-	return a.err()
+func (a *NotConfiguredVCSClient) CreateComment(_ logging.SimpleLogging, _ models.Repo, _ int, _ string, _ string) (string, error) {
+	return "", a.err()
 }
 func (a *NotConfiguredVCSClient) HidePrevCommandComments(_ logging.SimpleLogging, _ models.Repo, _ int, _ string, _ string) error {
 	return nil
@@ -78,3 +78,19 @@ func (a *NotConfiguredVCSClient) GetCloneURL(_ logging.SimpleLogging, _ models.V
 func (a *NotConfiguredVCSClient) GetPullLabels(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest) ([]string, error) {
 	return nil, a.err()
 }
+
+func (a *NotConfiguredVCSClient) GetPullDescription(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest) (string, error) {
+	return "", a.err()
+}
+
+func (a *NotConfiguredVCSClient) UpdatePullDescription(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest, _ string) error {
+	return a.err()
+}
+
+func (a *NotConfiguredVCSClient) SupportsReviewComments(_ models.Repo) bool {
+	return false
+}
+
+func (a *NotConfiguredVCSClient) CreateReviewComment(_ logging.SimpleLogging, _ models.Repo, _ models.PullRequest, _ string, _ string, _ string) error {
+	return a.err()
+}
@@ -61,7 +61,7 @@ func (d *ClientProxy) GetModifiedFiles(logger logging.SimpleLogging, repo models
 	return d.clients[repo.VCSHost.Type].GetModifiedFiles(logger, repo, pull)
 }
 
-func (d *ClientProxy) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) error {
+func (d *ClientProxy) CreateComment(logger logging.SimpleLogging, repo models.Repo, pullNum int, comment string, command string) (string, error) {
 	return d.clients[repo.VCSHost.Type].CreateComment(logger, repo, pullNum, comment, command)
 }
 
@@ -116,3 +116,19 @@ func (d *ClientProxy) GetCloneURL(logger logging.SimpleLogging, VCSHostType mode
 func (d *ClientProxy) GetPullLabels(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest) ([]string, error) {
 	return d.clients[repo.VCSHost.Type].GetPullLabels(logger, repo, pull)
 }
+
+func (d *ClientProxy) GetPullDescription(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest) (string, error) {
+	return d.clients[repo.VCSHost.Type].GetPullDescription(logger, repo, pull)
+}
+
+func (d *ClientProxy) UpdatePullDescription(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, description string) error {
+	return d.clients[repo.VCSHost.Type].UpdatePullDescription(logger, repo, pull, description)
+}
+
+func (d *ClientProxy) SupportsReviewComments(repo models.Repo) bool {
+	return d.clients[repo.VCSHost.Type].SupportsReviewComments(repo)
+}
+
+func (d *ClientProxy) CreateReviewComment(logger logging.SimpleLogging, repo models.Repo, pull models.PullRequest, path string, comment string, command string) error {
+	return d.clients[repo.VCSHost.Type].CreateReviewComment(logger, repo, pull, path, comment, command)
+}
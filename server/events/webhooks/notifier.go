@@ -0,0 +1,61 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+// NotifyTimeout is the timeout of the requests sent by notifiers so a slow
+// destination doesn't keep them running.
+const NotifyTimeout = 10 * time.Second
+
+//go:generate pegomock generate --package mocks -o mocks/mock_command_notifier.go CommandNotifier
+
+// CommandNotifier sends a summary of the result of a command.
+type CommandNotifier interface {
+	// Notify sends the summary of result.
+	Notify(log logging.SimpleLogging, result CommandResult) error
+}
+
+// CommandResult is the summary of a plan or apply command sent by notifiers.
+type CommandResult struct {
+	// Command is the name of the command, ex. plan.
+	Command string
+	Repo    models.Repo
+	Pull    models.PullRequest
+	User    models.User
+	// Success is whether the command and all its projects succeeded.
+	Success  bool
+	Projects []ProjectCommandResult
+}
+
+// ProjectCommandResult is the result of the command for one project.
+type ProjectCommandResult struct {
+	ProjectName string
+	Directory   string
+	Workspace   string
+	Success     bool
+}
+
+// MultiCommandNotifier sends command results with each of its Notifiers.
+type MultiCommandNotifier struct {
+	Notifiers []CommandNotifier
+}
+
+// Notify sends result with each notifier in the background so a slow or
+// unavailable destination doesn't delay the command. Errors are logged.
+func (m *MultiCommandNotifier) Notify(log logging.SimpleLogging, result CommandResult) error {
+	for _, n := range m.Notifiers {
+		go func(n CommandNotifier) {
+			if err := n.Notify(log, result); err != nil {
+				log.Warn("error sending %s notification: %s", result.Command, err)
+			}
+		}(n)
+	}
+	return nil
+}
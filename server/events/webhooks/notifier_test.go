@@ -0,0 +1,49 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+// blockingNotifier sends the results it's notified of to results once
+// release is closed.
+type blockingNotifier struct {
+	release chan struct{}
+	results chan webhooks.CommandResult
+	err     error
+}
+
+func (b *blockingNotifier) Notify(_ logging.SimpleLogging, result webhooks.CommandResult) error {
+	<-b.release
+	b.results <- result
+	return b.err
+}
+
+func TestMultiCommandNotifier_Notify(t *testing.T) {
+	failing := &blockingNotifier{release: make(chan struct{}), results: make(chan webhooks.CommandResult, 1), err: errors.New("slack is down")}
+	working := &blockingNotifier{release: make(chan struct{}), results: make(chan webhooks.CommandResult, 1)}
+	notifier := webhooks.MultiCommandNotifier{Notifiers: []webhooks.CommandNotifier{failing, working}}
+
+	result := webhooks.CommandResult{Command: "plan", Success: true}
+	// Notify doesn't wait for the notifiers.
+	Ok(t, notifier.Notify(logging.NewNoopLogger(t), result))
+
+	close(failing.release)
+	close(working.release)
+	for _, n := range []*blockingNotifier{failing, working} {
+		select {
+		case got := <-n.results:
+			Equals(t, result, got)
+		case <-time.After(5 * time.Second):
+			t.Fatal("notifier wasn't called")
+		}
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
+	"github.com/slack-go/slack"
+)
+
+// SlackNotifier sends command results to a Slack incoming webhook.
+type SlackNotifier struct {
+	Client *http.Client
+	URL    string
+}
+
+// Notify posts a message with the summary of result to the webhook.
+func (s *SlackNotifier) Notify(_ logging.SimpleLogging, result CommandResult) error {
+	if err := slack.PostWebhookCustomHTTP(s.URL, s.Client, s.createMessage(result)); err != nil {
+		return errors.Wrap(err, "posting to slack webhook")
+	}
+	return nil
+}
+
+func (s *SlackNotifier) createMessage(result CommandResult) *slack.WebhookMessage {
+	colour := slackSuccessColour
+	successWord := "succeeded"
+	if !result.Success {
+		colour = slackFailureColour
+		successWord = "failed"
+	}
+
+	var projects []string
+	for _, p := range result.Projects {
+		status := "succeeded"
+		if !p.Success {
+			status = "failed"
+		}
+		projects = append(projects, fmt.Sprintf("%s (%s)", projectDisplayName(p), status))
+	}
+
+	text := fmt.Sprintf("%s %s for <%s|%s#%d>", commandTitle(result.Command), successWord, result.Pull.URL, result.Repo.FullName, result.Pull.Num)
+	fields := []slack.AttachmentField{
+		{
+			Title: "Branch",
+			Value: result.Pull.BaseBranch,
+			Short: true,
+		},
+		{
+			Title: "User",
+			Value: result.User.Username,
+			Short: true,
+		},
+	}
+	if len(projects) > 0 {
+		fields = append(fields, slack.AttachmentField{
+			Title: "Projects",
+			Value: strings.Join(projects, "\n"),
+		})
+	}
+	return &slack.WebhookMessage{
+		Text: text,
+		Attachments: []slack.Attachment{{
+			Color:  colour,
+			Fields: fields,
+		}},
+	}
+}
+
+// commandTitle returns the name of a command in title case, ex. Plan.
+func commandTitle(command string) string {
+	if command == "" {
+		return command
+	}
+	return strings.ToUpper(command[:1]) + command[1:]
+}
+
+// projectDisplayName returns the name of a project if it has one, otherwise
+// its directory and workspace.
+func projectDisplayName(p ProjectCommandResult) string {
+	if p.ProjectName != "" {
+		return p.ProjectName
+	}
+	directory := p.Directory
+	// Since "." looks weird, replace it with "/" to make it clear this is the root.
+	if directory == "." {
+		directory = "/"
+	}
+	return fmt.Sprintf("dir: %s workspace: %s", directory, p.Workspace)
+}
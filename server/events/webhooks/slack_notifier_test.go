@@ -0,0 +1,66 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/models"
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+	"github.com/slack-go/slack"
+)
+
+var notifyResult = webhooks.CommandResult{
+	Command: "plan",
+	Repo:    models.Repo{FullName: "runatlantis/atlantis"},
+	Pull: models.PullRequest{
+		Num:        1,
+		URL:        "https://github.com/runatlantis/atlantis/pull/1",
+		BaseBranch: "main",
+	},
+	User:    models.User{Username: "lkysow"},
+	Success: false,
+	Projects: []webhooks.ProjectCommandResult{
+		{Directory: ".", Workspace: "default", Success: true},
+		{ProjectName: "staging", Directory: "staging", Workspace: "default", Success: false},
+	},
+}
+
+func TestSlackNotifier_Notify(t *testing.T) {
+	var msg slack.WebhookMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Equals(t, "application/json", r.Header.Get("Content-Type"))
+		Ok(t, json.NewDecoder(r.Body).Decode(&msg))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notifier := webhooks.SlackNotifier{Client: http.DefaultClient, URL: server.URL}
+	Ok(t, notifier.Notify(logging.NewNoopLogger(t), notifyResult))
+
+	Equals(t, "Plan failed for <https://github.com/runatlantis/atlantis/pull/1|runatlantis/atlantis#1>", msg.Text)
+	Equals(t, 1, len(msg.Attachments))
+	Equals(t, "danger", msg.Attachments[0].Color)
+	Equals(t, []slack.AttachmentField{
+		{Title: "Branch", Value: "main", Short: true},
+		{Title: "User", Value: "lkysow", Short: true},
+		{Title: "Projects", Value: "dir: / workspace: default (succeeded)\nstaging (failed)"},
+	}, msg.Attachments[0].Fields)
+}
+
+func TestSlackNotifier_NotifyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	notifier := webhooks.SlackNotifier{Client: http.DefaultClient, URL: server.URL}
+	err := notifier.Notify(logging.NewNoopLogger(t), notifyResult)
+	ErrContains(t, "posting to slack webhook", err)
+}
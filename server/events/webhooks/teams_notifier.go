@@ -0,0 +1,113 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/runatlantis/atlantis/server/logging"
+)
+
+const adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+
+// TeamsNotifier sends command results to a Microsoft Teams webhook as an
+// adaptive card.
+type TeamsNotifier struct {
+	Client *http.Client
+	URL    string
+}
+
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+type teamsAttachment struct {
+	ContentType string       `json:"contentType"`
+	Content     adaptiveCard `json:"content"`
+}
+
+type adaptiveCard struct {
+	Schema  string `json:"$schema"`
+	Type    string `json:"type"`
+	Version string `json:"version"`
+	// FallbackText is shown by clients that can't render the card.
+	FallbackText string           `json:"fallbackText"`
+	Body         []map[string]any `json:"body"`
+	Actions      []map[string]any `json:"actions,omitempty"`
+}
+
+// Notify posts an adaptive card with the summary of result to the webhook.
+func (t *TeamsNotifier) Notify(_ logging.SimpleLogging, result CommandResult) error {
+	body, err := json.Marshal(t.createMessage(result))
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", t.URL, bytes.NewBuffer(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return errors.Wrap(err, "posting to teams webhook")
+	}
+	defer resp.Body.Close()
+	// Workflows webhooks return 202 and connectors 200.
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("posting to teams webhook returned status code %d with response %q", resp.StatusCode, respBody)
+	}
+	return nil
+}
+
+func (t *TeamsNotifier) createMessage(result CommandResult) teamsMessage {
+	colour := "good"
+	successWord := "succeeded"
+	if !result.Success {
+		colour = "attention"
+		successWord = "failed"
+	}
+	title := fmt.Sprintf("%s %s for %s#%d", commandTitle(result.Command), successWord, result.Repo.FullName, result.Pull.Num)
+
+	facts := []map[string]any{
+		{"title": "Branch", "value": result.Pull.BaseBranch},
+		{"title": "User", "value": result.User.Username},
+	}
+	for _, p := range result.Projects {
+		status := "succeeded"
+		if !p.Success {
+			status = "failed"
+		}
+		facts = append(facts, map[string]any{"title": projectDisplayName(p), "value": status})
+	}
+
+	card := adaptiveCard{
+		Schema:       "http://adaptivecards.io/schemas/adaptive-card.json",
+		Type:         "AdaptiveCard",
+		Version:      "1.4",
+		FallbackText: title,
+		Body: []map[string]any{
+			{"type": "TextBlock", "text": title, "weight": "bolder", "color": colour, "wrap": true},
+			{"type": "FactSet", "facts": facts},
+		},
+	}
+	if result.Pull.URL != "" {
+		card.Actions = []map[string]any{
+			{"type": "Action.OpenUrl", "title": "View pull request", "url": result.Pull.URL},
+		}
+	}
+	return teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: adaptiveCardContentType,
+			Content:     card,
+		}},
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 The Atlantis Authors
+// SPDX-License-Identifier: Apache-2.0
+
+package webhooks_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/runatlantis/atlantis/server/events/webhooks"
+	"github.com/runatlantis/atlantis/server/logging"
+	. "github.com/runatlantis/atlantis/testing"
+)
+
+func TestTeamsNotifier_Notify(t *testing.T) {
+	var msg map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Equals(t, "application/json", r.Header.Get("Content-Type"))
+		Ok(t, json.NewDecoder(r.Body).Decode(&msg))
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	notifier := webhooks.TeamsNotifier{Client: http.DefaultClient, URL: server.URL}
+	Ok(t, notifier.Notify(logging.NewNoopLogger(t), notifyResult))
+
+	Equals(t, "message", msg["type"])
+	attachment := msg["attachments"].([]any)[0].(map[string]any)
+	Equals(t, "application/vnd.microsoft.card.adaptive", attachment["contentType"])
+	card := attachment["content"].(map[string]any)
+	Equals(t, "AdaptiveCard", card["type"])
+	Equals(t, "Plan failed for runatlantis/atlantis#1", card["fallbackText"])
+	body := card["body"].([]any)
+	Equals(t, "Plan failed for runatlantis/atlantis#1", body[0].(map[string]any)["text"])
+	Equals(t, "attention", body[0].(map[string]any)["color"])
+	Equals(t, []any{
+		map[string]any{"title": "Branch", "value": "main"},
+		map[string]any{"title": "User", "value": "lkysow"},
+		map[string]any{"title": "dir: / workspace: default", "value": "succeeded"},
+		map[string]any{"title": "staging", "value": "failed"},
+	}, body[1].(map[string]any)["facts"])
+	Equals(t, []any{
+		map[string]any{"type": "Action.OpenUrl", "title": "View pull request", "url": "https://github.com/runatlantis/atlantis/pull/1"},
+	}, card["actions"])
+}
+
+func TestTeamsNotifier_NotifyNoPullURL(t *testing.T) {
+	var msg map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		Ok(t, json.NewDecoder(r.Body).Decode(&msg))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := notifyResult
+	result.Pull.URL = ""
+	notifier := webhooks.TeamsNotifier{Client: http.DefaultClient, URL: server.URL}
+	Ok(t, notifier.Notify(logging.NewNoopLogger(t), result))
+
+	card := msg["attachments"].([]any)[0].(map[string]any)["content"].(map[string]any)
+	_, hasActions := card["actions"]
+	Assert(t, !hasActions, "expected no actions without a pull request URL")
+}
+
+func TestTeamsNotifier_NotifyError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("invalid card")) // nolint: errcheck
+	}))
+	defer server.Close()
+
+	notifier := webhooks.TeamsNotifier{Client: http.DefaultClient, URL: server.URL}
+	err := notifier.Notify(logging.NewNoopLogger(t), notifyResult)
+	ErrEquals(t, `posting to teams webhook returned status code 400 with response "invalid card"`, err)
+}
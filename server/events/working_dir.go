@@ -55,6 +55,9 @@ type WorkingDir interface {
 	DeleteForWorkspace(logger logging.SimpleLogging, r models.Repo, p models.PullRequest, workspace string) error
 	// DeletePlan deletes the plan for this repo, pull, workspace path and project name
 	DeletePlan(logger logging.SimpleLogging, r models.Repo, p models.PullRequest, workspace string, path string, projectName string) error
+	// GetGitChangedFilesSince returns the files changed in the working dir
+	// since the merge base of HEAD and ref.
+	GetGitChangedFilesSince(logger logging.SimpleLogging, r models.Repo, p models.PullRequest, workspace string, ref string) ([]string, error)
 	// GetGitUntrackedFiles returns a list of Git untracked files in the working dir.
 	GetGitUntrackedFiles(logger logging.SimpleLogging, r models.Repo, p models.PullRequest, workspace string) ([]string, error)
 }
@@ -495,9 +498,87 @@ func (w *FileWorkspace) SetCheckForUpstreamChanges() {
 func (w *FileWorkspace) DeletePlan(logger logging.SimpleLogging, r models.Repo, p models.PullRequest, workspace string, projectPath string, projectName string) error {
 	planPath := filepath.Join(w.cloneDir(r, p, workspace), projectPath, runtime.GetPlanFilename(workspace, projectName))
 	logger.Info("Deleting plan: " + planPath)
+	// If the project sets plan_file_path, planPath links to its plan file.
+	if target, _ := linkedPlanFile(planPath); target != "" {
+		if err := utils.RemoveIgnoreNonExistent(target); err != nil {
+			return err
+		}
+	}
+	if err := os.RemoveAll(runtime.StepArtifactsDir(planPath)); err != nil {
+		return err
+	}
 	return utils.RemoveIgnoreNonExistent(planPath)
 }
 
+// GetGitChangedFilesSince returns the files changed in the working dir since
+// the merge base of HEAD and ref, relative to the root of the repo. Missing
+// origin branches and history are fetched first. It errors if ref isn't a
+// commit in the clone.
+func (w *FileWorkspace) GetGitChangedFilesSince(logger logging.SimpleLogging, r models.Repo, p models.PullRequest, workspace string, ref string) ([]string, error) {
+	workingDir, err := w.GetWorkingDir(r, p, workspace)
+	if err != nil {
+		return nil, err
+	}
+
+	c := wrappedGitContext{workingDir, r, p}
+
+	// The clone can be shallow and only have the pull request's branch, ex.
+	// with the branch checkout strategy, so fetch origin's branches when
+	// they're missing.
+	if !gitRefExists(workingDir, ref) {
+		if branch, ok := strings.CutPrefix(ref, "origin/"); ok {
+			// If this fails the ref doesn't exist, which is reported below.
+			if err := w.wrappedGit(logger, c, "fetch", "origin", fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", branch, branch)); err != nil {
+				logger.Debug("fetching %q: %s", ref, err)
+			}
+		}
+	}
+	if !gitRefExists(workingDir, ref) {
+		return nil, fmt.Errorf("the ref %q doesn't exist in the clone of this pull request", ref)
+	}
+
+	if err := w.wrappedGit(logger, c, "merge-base", ref, "HEAD"); err != nil && isShallowRepo(workingDir) {
+		// git merge-base returning error means that we did not receive enough
+		// commits in the shallow clone. Fall back to retrieving full history.
+		if err := w.wrappedGit(logger, c, "fetch", "--unshallow", "origin"); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Debug("Checking for files changed since '%s' in directory: '%s'", ref, workingDir)
+	cmd := exec.Command("git", "diff", "--name-only", ref+"...HEAD", "--")
+	cmd.Dir = workingDir
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("running git diff --name-only %s...HEAD: %s: %s", ref, strings.TrimSpace(string(output)), err)
+	}
+
+	var changedFiles []string
+	for _, f := range strings.Split(string(output), "\n") {
+		if f != "" {
+			changedFiles = append(changedFiles, f)
+		}
+	}
+	logger.Debug("Files changed since '%s': '%s'", ref, strings.Join(changedFiles, ","))
+	return changedFiles, nil
+}
+
+// gitRefExists returns true if ref resolves to a commit in the repo at dir.
+func gitRefExists(dir string, ref string) bool {
+	cmd := exec.Command("git", "rev-parse", "--verify", "--quiet", "--end-of-options", ref+"^{commit}")
+	cmd.Dir = dir
+	return cmd.Run() == nil
+}
+
+// isShallowRepo returns true if the repo at dir is a shallow clone.
+func isShallowRepo(dir string) bool {
+	cmd := exec.Command("git", "rev-parse", "--is-shallow-repository")
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	return err == nil && strings.TrimSpace(string(output)) == "true"
+}
+
 // getGitUntrackedFiles returns a list of Git untracked files in the working dir.
 func (w *FileWorkspace) GetGitUntrackedFiles(logger logging.SimpleLogging, r models.Repo, p models.PullRequest, workspace string) ([]string, error) {
 	workingDir, err := w.GetWorkingDir(r, p, workspace)
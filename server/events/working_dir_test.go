@@ -726,6 +726,75 @@ func TestHasDiverged_MasterHasDiverged(t *testing.T) {
 	Equals(t, hasDiverged, false)
 }
 
+func TestGetGitChangedFilesSince(t *testing.T) {
+	repoDir := initRepo(t)
+	dataDir := t.TempDir()
+
+	runCmd(t, dataDir, "mkdir", "-p", "repos/0/")
+	runCmd(t, dataDir, "mv", repoDir, "repos/0/default")
+	cloneDir := filepath.Join(dataDir, "repos/0/default")
+	runCmd(t, cloneDir, "mkdir", "a", "b")
+	runCmd(t, cloneDir, "touch", "a/main.tf")
+	runCmd(t, cloneDir, "git", "add", "a/main.tf")
+	runCmd(t, cloneDir, "git", "commit", "-m", "add a")
+	runCmd(t, cloneDir, "git", "tag", "since")
+	runCmd(t, cloneDir, "touch", "b/main.tf", "b/variables.tf")
+	runCmd(t, cloneDir, "git", "add", "b")
+	runCmd(t, cloneDir, "git", "commit", "-m", "add b")
+
+	logger := logging.NewNoopLogger(t)
+	wd := &events.FileWorkspace{
+		DataDir:             dataDir,
+		GpgNoSigningEnabled: true,
+	}
+
+	files, err := wd.GetGitChangedFilesSince(logger, models.Repo{}, models.PullRequest{}, "default", "since")
+	Ok(t, err)
+	Equals(t, []string{"b/main.tf", "b/variables.tf"}, files)
+
+	files, err = wd.GetGitChangedFilesSince(logger, models.Repo{}, models.PullRequest{}, "default", "HEAD")
+	Ok(t, err)
+	Equals(t, []string(nil), files)
+
+	_, err = wd.GetGitChangedFilesSince(logger, models.Repo{}, models.PullRequest{}, "default", "origin/missing")
+	ErrEquals(t, `the ref "origin/missing" doesn't exist in the clone of this pull request`, err)
+}
+
+// Test that the branch checkout strategy's shallow, single branch clone
+// fetches the ref and its merge base.
+func TestGetGitChangedFilesSince_ShallowClone(t *testing.T) {
+	repoDir := initRepo(t)
+	runCmd(t, repoDir, "mkdir", "a", "b", "c")
+	runCmd(t, repoDir, "touch", "a/main.tf")
+	runCmd(t, repoDir, "git", "add", "a/main.tf")
+	runCmd(t, repoDir, "git", "commit", "-m", "add a")
+	runCmd(t, repoDir, "git", "checkout", "-b", "feature")
+	runCmd(t, repoDir, "touch", "b/main.tf")
+	runCmd(t, repoDir, "git", "add", "b/main.tf")
+	runCmd(t, repoDir, "git", "commit", "-m", "add b")
+	runCmd(t, repoDir, "touch", "b/variables.tf")
+	runCmd(t, repoDir, "git", "add", "b/variables.tf")
+	runCmd(t, repoDir, "git", "commit", "-m", "add b variables")
+	runCmd(t, repoDir, "git", "checkout", "main")
+	runCmd(t, repoDir, "touch", "c/main.tf")
+	runCmd(t, repoDir, "git", "add", "c/main.tf")
+	runCmd(t, repoDir, "git", "commit", "-m", "add c")
+
+	dataDir := t.TempDir()
+	runCmd(t, dataDir, "mkdir", "-p", "repos/0/")
+	runCmd(t, dataDir, "git", "clone", "--depth=1", "--branch", "feature", "--single-branch", "file://"+repoDir, "repos/0/default")
+
+	logger := logging.NewNoopLogger(t)
+	wd := &events.FileWorkspace{
+		DataDir:             dataDir,
+		GpgNoSigningEnabled: true,
+	}
+
+	files, err := wd.GetGitChangedFilesSince(logger, models.Repo{}, models.PullRequest{}, "default", "origin/main")
+	Ok(t, err)
+	Equals(t, []string{"b/main.tf", "b/variables.tf"}, files)
+}
+
 func initRepo(t *testing.T) string {
 	repoDir := t.TempDir()
 	runCmd(t, repoDir, "git", "init", "--initial-branch=main")
@@ -14,6 +14,8 @@
 package logging_test
 
 import (
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/runatlantis/atlantis/server/logging"
@@ -32,3 +34,38 @@ func TestStructuredLoggerSavesHistory(t *testing.T) {
 
 	assert.Equal(t, expectedStr, historyLogger.GetHistory())
 }
+
+func TestStructuredLoggerWithSharesHistory(t *testing.T) {
+	historyLogger := logging.NewNoopLogger(t).WithHistory()
+
+	historyLogger.Info("foo bar")
+	historyLogger.With("project", "prod").Warn("Hello World")
+
+	assert.Equal(t, "[INFO] foo bar\n[WARN] Hello World\n", historyLogger.GetHistory())
+}
+
+func TestStructuredLoggerWithSharesHistory_Concurrent(t *testing.T) {
+	historyLogger := logging.NewNoopLogger(t).WithHistory()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			historyLogger.With("project", i).Info("planning")
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, strings.Repeat("[INFO] planning\n", 10), historyLogger.GetHistory())
+}
+
+func TestNewStructuredLoggerFromLevelAndFormat(t *testing.T) {
+	for _, format := range []string{"", logging.DefaultFormat, logging.JSONFormat} {
+		_, err := logging.NewStructuredLoggerFromLevelAndFormat(logging.Info, format)
+		assert.NoError(t, err)
+	}
+
+	_, err := logging.NewStructuredLoggerFromLevelAndFormat(logging.Info, "text")
+	assert.EqualError(t, err, `invalid log format "text": must be one of [default json]`)
+}
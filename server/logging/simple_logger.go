@@ -19,6 +19,7 @@ package logging
 import (
 	"bytes"
 	"fmt"
+	"sync"
 
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
@@ -69,35 +70,82 @@ type StructuredLogger struct {
 	// This doesn't really make sense to keep given that structured logging
 	// gives us the ability to query our logs across multiple dimensions
 	// I don't believe we should mix this in with atlantis commands and expose this to the user
-	history bytes.Buffer
+	// It is shared with the loggers created by With so their entries are kept too.
+	history *logHistory
 }
 
+// logHistory is the history of a logger. It's locked since the loggers
+// created by With can be used concurrently, ex. when planning projects in
+// parallel.
+type logHistory struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (h *logHistory) WriteString(s string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.buf.WriteString(s)
+}
+
+func (h *logHistory) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.buf.String()
+}
+
+const (
+	// DefaultFormat writes entries as JSON with the fields added by With
+	// nested under "json".
+	DefaultFormat = "default"
+	// JSONFormat writes entries as JSON with the fields added by With next
+	// to level and msg so they can be queried directly.
+	JSONFormat = "json"
+)
+
+// ValidFormats are the formats accepted by NewStructuredLoggerFromLevelAndFormat.
+var ValidFormats = []string{DefaultFormat, JSONFormat}
+
 func NewStructuredLoggerFromLevel(lvl LogLevel) (SimpleLogging, error) {
+	return NewStructuredLoggerFromLevelAndFormat(lvl, DefaultFormat)
+}
+
+// NewStructuredLoggerFromLevelAndFormat returns a logger at lvl writing
+// entries in format, one of ValidFormats. An empty format is DefaultFormat.
+func NewStructuredLoggerFromLevelAndFormat(lvl LogLevel, format string) (SimpleLogging, error) {
 	cfg := zap.NewProductionConfig()
 
 	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
 	cfg.Level = zap.NewAtomicLevelAt(lvl.zLevel)
-	return newStructuredLogger(cfg)
+	switch format {
+	case "", DefaultFormat:
+		return newStructuredLogger(cfg, true)
+	case JSONFormat:
+		return newStructuredLogger(cfg, false)
+	default:
+		return nil, fmt.Errorf("invalid log format %q: must be one of %v", format, ValidFormats)
+	}
 }
 
 func NewStructuredLogger() (SimpleLogging, error) {
 	cfg := zap.NewProductionConfig()
 	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	return newStructuredLogger(cfg)
+	return newStructuredLogger(cfg, true)
 }
 
-func newStructuredLogger(cfg zap.Config) (*StructuredLogger, error) {
+func newStructuredLogger(cfg zap.Config, nestFields bool) (*StructuredLogger, error) {
 	baseLogger, err := cfg.Build()
+	if err != nil {
+		return nil, errors.Wrap(err, " initializing structured logger")
+	}
 
 	baseLogger = baseLogger.
 		// ensures that the caller doesn't just say logging/simple_logger each time
 		WithOptions(zap.AddCallerSkip(1)).
-		WithOptions(zap.AddStacktrace(zapcore.WarnLevel)).
+		WithOptions(zap.AddStacktrace(zapcore.WarnLevel))
+	if nestFields {
 		// creates isolated context for all future kv pairs, name can be flexible as needed
-		With(zap.Namespace("json"))
-
-	if err != nil {
-		return nil, errors.Wrap(err, " initializing structured logger")
+		baseLogger = baseLogger.With(zap.Namespace("json"))
 	}
 
 	return &StructuredLogger{
@@ -108,8 +156,10 @@ func newStructuredLogger(cfg zap.Config) (*StructuredLogger, error) {
 
 func (l *StructuredLogger) With(a ...interface{}) SimpleLogging {
 	return &StructuredLogger{
-		z:     l.z.With(a...),
-		level: l.level,
+		z:           l.z.With(a...),
+		level:       l.level,
+		keepHistory: l.keepHistory,
+		history:     l.history,
 	}
 }
 
@@ -121,12 +171,18 @@ func (l *StructuredLogger) WithHistory(a ...interface{}) SimpleLogging {
 
 	// ensure that the history is kept across loggers.
 	logger.keepHistory = true
-	logger.history = l.history
+	logger.history = &logHistory{}
+	if l.history != nil {
+		logger.history.WriteString(l.history.String())
+	}
 
 	return logger
 }
 
 func (l *StructuredLogger) GetHistory() string {
+	if l.history == nil {
+		return ""
+	}
 	return l.history.String()
 }
 
@@ -3,6 +3,16 @@
 
 package metrics
 
+import (
+	"time"
+
+	tally "github.com/uber-go/tally/v4"
+)
+
+// DurationBuckets are the buckets of the histograms of long running
+// operations, from 1s to about 68m.
+var DurationBuckets = tally.MustMakeExponentialDurationBuckets(time.Second, 2, 13)
+
 const (
 	ExecutionTimeMetric    = "execution_time"
 	ExecutionSuccessMetric = "execution_success"
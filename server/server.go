@@ -36,6 +36,7 @@ import (
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	version "github.com/hashicorp/go-version"
 	"github.com/mitchellh/go-homedir"
 	tally "github.com/uber-go/tally/v4"
 	prometheus "github.com/uber-go/tally/v4/prometheus"
@@ -46,6 +47,7 @@ import (
 	"github.com/runatlantis/atlantis/server/core/config/valid"
 	"github.com/runatlantis/atlantis/server/core/db"
 	"github.com/runatlantis/atlantis/server/core/redis"
+	runtime_models "github.com/runatlantis/atlantis/server/core/runtime/models"
 	"github.com/runatlantis/atlantis/server/core/terraform/tfclient"
 	"github.com/runatlantis/atlantis/server/jobs"
 	"github.com/runatlantis/atlantis/server/metrics"
@@ -89,6 +91,9 @@ const (
 	// terraformPluginCacheDir is the name of the dir inside our data dir
 	// where we tell terraform to cache plugins and modules.
 	TerraformPluginCacheDirName = "plugin-cache"
+	// PolicyCheckCacheDirName is the name of the dir inside our data dir
+	// where we cache policy check results.
+	PolicyCheckCacheDirName = "policy-check-cache"
 )
 
 // Server runs the Atlantis web server.
@@ -173,7 +178,7 @@ var staticAssets embed.FS
 // for the server CLI command because it injects all the dependencies.
 func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	logging.SuppressDefaultLogging()
-	logger, err := logging.NewStructuredLoggerFromLevel(userConfig.ToLogLevel())
+	logger, err := logging.NewStructuredLoggerFromLevelAndFormat(userConfig.ToLogLevel(), userConfig.LogFormat)
 
 	if err != nil {
 		return nil, err
@@ -208,7 +213,13 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		}
 	}
 
-	parserValidator := &cfg.ParserValidator{}
+	repoConfigEnvAllowlist, err := userConfig.ToRepoConfigEnvAllowlist()
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing --repo-config-env-allowlist")
+	}
+	parserValidator := &cfg.ParserValidator{
+		AllowedEnvVars: repoConfigEnvAllowlist,
+	}
 
 	globalCfg := valid.NewGlobalCfgFromArgs(
 		valid.GlobalCfgArgs{
@@ -233,12 +244,21 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	}
 
 	if userConfig.GithubUser != "" || userConfig.GithubAppID != 0 {
-		if userConfig.GithubAllowMergeableBypassApply {
-			githubConfig = vcs.GithubConfig{
-				AllowMergeableBypassApply: true,
-			}
+		githubConfig = vcs.GithubConfig{
+			AllowMergeableBypassApply: userConfig.GithubAllowMergeableBypassApply,
+			PlanReviewComments:        userConfig.PlanReviewComments,
+			SplitLargeComments:        userConfig.SplitLargeComments,
+			CheckRuns:                 userConfig.GithubCheckRuns,
 		}
 		supportedVCSHosts = append(supportedVCSHosts, models.Github)
+		githubAppTokenRefreshLeadTime, err := userConfig.ToGithubAppTokenRefreshLeadTime()
+		if err != nil {
+			return nil, err
+		}
+		githubConfig.RateLimitMaxWait, err = userConfig.ToGithubRateLimitMaxWait()
+		if err != nil {
+			return nil, err
+		}
 		if userConfig.GithubUser != "" {
 			githubCredentials = &vcs.GithubUserCredentials{
 				User:      userConfig.GithubUser,
@@ -251,25 +271,26 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 				return nil, err
 			}
 			githubCredentials = &vcs.GithubAppCredentials{
-				AppID:          userConfig.GithubAppID,
-				InstallationID: userConfig.GithubAppInstallationID,
-				Key:            privateKey,
-				Hostname:       userConfig.GithubHostname,
-				AppSlug:        userConfig.GithubAppSlug,
+				AppID:                userConfig.GithubAppID,
+				InstallationID:       userConfig.GithubAppInstallationID,
+				Key:                  privateKey,
+				Hostname:             userConfig.GithubHostname,
+				AppSlug:              userConfig.GithubAppSlug,
+				TokenRefreshLeadTime: githubAppTokenRefreshLeadTime,
 			}
 			githubAppEnabled = true
 		} else if userConfig.GithubAppID != 0 && userConfig.GithubAppKey != "" {
 			githubCredentials = &vcs.GithubAppCredentials{
-				AppID:          userConfig.GithubAppID,
-				InstallationID: userConfig.GithubAppInstallationID,
-				Key:            []byte(userConfig.GithubAppKey),
-				Hostname:       userConfig.GithubHostname,
-				AppSlug:        userConfig.GithubAppSlug,
+				AppID:                userConfig.GithubAppID,
+				InstallationID:       userConfig.GithubAppInstallationID,
+				Key:                  []byte(userConfig.GithubAppKey),
+				Hostname:             userConfig.GithubHostname,
+				AppSlug:              userConfig.GithubAppSlug,
+				TokenRefreshLeadTime: githubAppTokenRefreshLeadTime,
 			}
 			githubAppEnabled = true
 		}
 
-		var err error
 		rawGithubClient, err := vcs.NewGithubClient(userConfig.GithubHostname, githubCredentials, githubConfig, userConfig.MaxCommentsPerCommand, logger)
 		if err != nil {
 			return nil, err
@@ -293,6 +314,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			return nil, err
 		}
 		gitlabClient.StatusRetryEnabled = userConfig.GitlabStatusRetryEnabled
+		gitlabClient.ExternalStatusChecks = userConfig.GitlabExternalStatusChecks
 	}
 	if userConfig.BitbucketUser != "" {
 		if userConfig.BitbucketBaseURL == bitbucketcloud.BaseURL {
@@ -325,6 +347,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		if err != nil {
 			return nil, err
 		}
+		azuredevopsClient.ThreadComments = userConfig.AzureDevopsThreadComments
 	}
 	if userConfig.GiteaToken != "" {
 		supportedVCSHosts = append(supportedVCSHosts, models.Gitea)
@@ -416,8 +439,25 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	if err != nil {
 		return nil, errors.Wrap(err, "initializing webhooks")
 	}
+	var commandNotifiers []webhooks.CommandNotifier
+	if userConfig.SlackWebhookURL != "" {
+		commandNotifiers = append(commandNotifiers, &webhooks.SlackNotifier{
+			Client: &http.Client{Timeout: webhooks.NotifyTimeout},
+			URL:    userConfig.SlackWebhookURL,
+		})
+	}
+	if userConfig.TeamsWebhookURL != "" {
+		commandNotifiers = append(commandNotifiers, &webhooks.TeamsNotifier{
+			Client: &http.Client{Timeout: webhooks.NotifyTimeout},
+			URL:    userConfig.TeamsWebhookURL,
+		})
+	}
 	vcsClient := vcs.NewClientProxy(githubClient, gitlabClient, bitbucketCloudClient, bitbucketServerClient, azuredevopsClient, giteaClient)
-	commitStatusUpdater := &events.DefaultCommitStatusUpdater{Client: vcsClient, StatusName: userConfig.VCSStatusName}
+	commitStatusUpdater := &events.DefaultCommitStatusUpdater{
+		Client:                 vcsClient,
+		StatusName:             userConfig.VCSStatusName,
+		DisableProjectStatuses: userConfig.DisableProjectStatuses,
+	}
 
 	binDir, err := mkSubDir(userConfig.DataDir, BinDirName)
 
@@ -431,6 +471,14 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		return nil, err
 	}
 
+	var policyCheckCacheDir string
+	if !userConfig.DisablePolicyCheckCache {
+		policyCheckCacheDir, err = mkSubDir(userConfig.DataDir, PolicyCheckCacheDirName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	parsedURL, err := ParseAtlantisURL(userConfig.AtlantisURL)
 	if err != nil {
 		return nil, errors.Wrapf(err, "parsing --%s flag %q", config.AtlantisURLFlag, userConfig.AtlantisURL)
@@ -460,6 +508,20 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 
 	distribution := terraform.NewDistribution(userConfig.DefaultTFDistribution)
 
+	defaultTFVersion := userConfig.DefaultTFVersion
+	if userConfig.DefaultTFVersionConstraint != "" {
+		constraint, err := version.NewConstraint(userConfig.DefaultTFVersionConstraint)
+		if err != nil {
+			return nil, errors.Wrap(err, "parsing default tf version constraint")
+		}
+		installed, err := tfclient.FindInstalledVersion(distribution, binDir, constraint)
+		if err != nil {
+			return nil, errors.Wrap(err, "picking default tf version")
+		}
+		logger.Info("using %s %s as the default version since it's the newest installed version satisfying %q", distribution.BinName(), installed, userConfig.DefaultTFVersionConstraint)
+		defaultTFVersion = installed.String()
+	}
+
 	terraformClient, err := tfclient.NewClient(
 		logger,
 		distribution,
@@ -467,7 +529,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		cacheDir,
 		userConfig.TFEToken,
 		userConfig.TFEHostname,
-		userConfig.DefaultTFVersion,
+		defaultTFVersion,
 		config.DefaultTFVersionFlag,
 		userConfig.TFDownloadURL,
 		userConfig.TFDownload,
@@ -479,6 +541,11 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	if err != nil && flag.Lookup("test.v") == nil {
 		return nil, errors.Wrap(err, fmt.Sprintf("initializing %s", userConfig.DefaultTFDistribution))
 	}
+	// Errors in the template overrides are reported now instead of when
+	// comments are rendered.
+	if _, err := events.ParseMarkdownTemplates(userConfig.MarkdownTemplateOverridesDir); err != nil {
+		return nil, err
+	}
 	markdownRenderer := events.NewMarkdownRenderer(
 		gitlabClient.SupportsCommonMark(),
 		userConfig.DisableApplyAll,
@@ -490,6 +557,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		userConfig.ExecutableName,
 		userConfig.HideUnchangedPlanComments,
 		userConfig.QuietPolicyChecks,
+		userConfig.MaxCommentOutputSize,
 	)
 
 	var lockingClient locking.Locker
@@ -516,7 +584,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		logger.Info("Repo Locking is disabled")
 		lockingClient = noOpLocker
 	} else {
-		lockingClient = locking.NewClient(database)
+		lockingClient = locking.NewClientWithGranularity(database, userConfig.LockGranularity)
 	}
 	disableGlobalApplyLock := userConfig.DisableGlobalApplyLock
 
@@ -567,6 +635,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		Locker:     lockingClient,
 		NoOpLocker: noOpLocker,
 		VCSClient:  vcsClient,
+		Scope:      statsScope.SubScope("project_lock"),
 	}
 	deleteLockCommand := &events.DefaultDeleteLockCommand{
 		Locker:           lockingClient,
@@ -575,6 +644,27 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		Database:         database,
 	}
 
+	lockTTL, err := userConfig.ToLockTTL()
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing lock TTL")
+	}
+	if lockTTL > 0 {
+		scheduledExecutorService.AddJob(scheduled.JobDefinition{
+			Job: &events.LockExpirySweeper{
+				TTL:               lockTTL,
+				Locker:            lockingClient,
+				DeleteLockCommand: deleteLockCommand,
+				WorkingDirLocker:  workingDirLocker,
+				Database:          database,
+				VCSClient:         vcsClient,
+				Logger:            logger,
+			},
+			// Sweeping every minute is precise enough for TTLs measured in
+			// hours while not listing locks too often.
+			Period: min(lockTTL, time.Minute),
+		})
+	}
+
 	pullClosedExecutor := events.NewInstrumentedPullClosedExecutor(
 		statsScope,
 		logger,
@@ -585,23 +675,27 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			PullClosedTemplate:       &events.PullClosedEventTemplate{},
 			LogStreamResourceCleaner: projectCmdOutputHandler,
 			VCSClient:                vcsClient,
+			TerraformClient:          terraformClient,
+			TerraformDistribution:    terraformClient.DefaultDistribution(),
 		},
 	)
 
 	eventParser := &events.EventParser{
-		GithubUser:         userConfig.GithubUser,
-		GithubToken:        userConfig.GithubToken,
-		GithubTokenFile:    userConfig.GithubTokenFile,
-		GitlabUser:         userConfig.GitlabUser,
-		GitlabToken:        userConfig.GitlabToken,
-		GiteaUser:          userConfig.GiteaUser,
-		GiteaToken:         userConfig.GiteaToken,
-		AllowDraftPRs:      userConfig.PlanDrafts,
-		BitbucketUser:      userConfig.BitbucketUser,
-		BitbucketToken:     userConfig.BitbucketToken,
-		BitbucketServerURL: userConfig.BitbucketBaseURL,
-		AzureDevopsUser:    userConfig.AzureDevopsUser,
-		AzureDevopsToken:   userConfig.AzureDevopsToken,
+		GithubUser:                        userConfig.GithubUser,
+		GithubToken:                       userConfig.GithubToken,
+		GithubTokenFile:                   userConfig.GithubTokenFile,
+		GitlabUser:                        userConfig.GitlabUser,
+		GitlabToken:                       userConfig.GitlabToken,
+		GiteaUser:                         userConfig.GiteaUser,
+		GiteaToken:                        userConfig.GiteaToken,
+		AllowDraftPRs:                     userConfig.PlanDrafts,
+		BitbucketUser:                     userConfig.BitbucketUser,
+		BitbucketToken:                    userConfig.BitbucketToken,
+		BitbucketServerURL:                userConfig.BitbucketBaseURL,
+		BitbucketServerDraftDetection:     userConfig.BitbucketDraftDetection,
+		BitbucketServerDraftTitlePrefixes: strings.Split(userConfig.BitbucketDraftTitlePrefixes, ","),
+		AzureDevopsUser:                   userConfig.AzureDevopsUser,
+		AzureDevopsToken:                  userConfig.AzureDevopsToken,
 	}
 	commentParser := events.NewCommentParser(
 		userConfig.GithubUser,
@@ -672,6 +766,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		userConfig.SilenceNoProjects,
 		userConfig.IncludeGitUntrackedFiles,
 		userConfig.AutoDiscoverModeFlag,
+		userConfig.ProjectOrder,
 		statsScope,
 		terraformClient,
 	)
@@ -685,7 +780,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	policyCheckStepRunner, err := runtime.NewPolicyCheckStepRunner(
 		defaultTfDistribution,
 		defaultTfVersion,
-		policy.NewConfTestExecutorWorkflow(logger, binDir, &policy.ConfTestGoGetterVersionDownloader{}),
+		policy.NewConfTestExecutorWorkflow(logger, binDir, &policy.ConfTestGoGetterVersionDownloader{}, policyCheckCacheDir),
 	)
 
 	if err != nil {
@@ -693,8 +788,17 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	}
 
 	applyRequirementHandler := &events.DefaultCommandRequirementHandler{
-		WorkingDir: workingDir,
+		WorkingDir:              workingDir,
+		CustomRequirementRunner: runStepRunner,
+		VCSStatusName:           userConfig.VCSStatusName,
 	}
+	if gitlabClient != nil {
+		applyRequirementHandler.GitlabPipelineStatusGetter = gitlabClient
+	}
+
+	// approvals is shared by await_approval steps and the API endpoint that
+	// receives their callbacks.
+	approvals := runtime.NewApprovalRegistry()
 
 	projectCommandRunner := &events.DefaultProjectCommandRunner{
 		VcsClient:        vcsClient,
@@ -705,8 +809,9 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 			TerraformExecutor:     terraformClient,
 			DefaultTFDistribution: defaultTfDistribution,
 			DefaultTFVersion:      defaultTfVersion,
+			Exec:                  runtime_models.LocalExec{},
 		},
-		PlanStepRunner:        runtime.NewPlanStepRunner(terraformClient, defaultTfDistribution, defaultTfVersion, commitStatusUpdater, terraformClient),
+		PlanStepRunner:        runtime.NewPlanStepRunner(terraformClient, defaultTfDistribution, defaultTfVersion, commitStatusUpdater, terraformClient, userConfig.PlanJSONStats),
 		ShowStepRunner:        showStepRunner,
 		PolicyCheckStepRunner: policyCheckStepRunner,
 		ApplyStepRunner: &runtime.ApplyStepRunner{
@@ -720,20 +825,54 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		EnvStepRunner: &runtime.EnvStepRunner{
 			RunStepRunner: runStepRunner,
 		},
+		VaultSecretReader: &runtime.VaultSecretReader{
+			Exec: runtime_models.LocalExec{},
+		},
 		MultiEnvStepRunner: &runtime.MultiEnvStepRunner{
 			RunStepRunner: runStepRunner,
 		},
+		ArchiveStepRunner: &runtime.ArchiveStepRunner{
+			Exec: runtime_models.LocalExec{},
+		},
+		ModulePinCheckStepRunner: &runtime.ModulePinCheckStepRunner{},
+		CostEstimateStepRunner: &runtime.CostEstimateStepRunner{
+			Exec: runtime_models.LocalExec{},
+		},
+		AwaitApprovalStepRunner: &runtime.AwaitApprovalStepRunner{
+			Registry:    approvals,
+			Exec:        runtime_models.LocalExec{},
+			ApprovalURL: parsedURL.String() + "/api/approvals",
+		},
+		LockProvidersStepRunner: &runtime.LockProvidersStepRunner{
+			TerraformExecutor:     terraformClient,
+			DefaultTFDistribution: defaultTfDistribution,
+			DefaultTFVersion:      defaultTfVersion,
+			Exec:                  runtime_models.LocalExec{},
+		},
 		VersionStepRunner: &runtime.VersionStepRunner{
 			TerraformExecutor:     terraformClient,
 			DefaultTFDistribution: defaultTfDistribution,
 			DefaultTFVersion:      defaultTfVersion,
 		},
+		ValidateStepRunner: &runtime.ValidateStepRunner{
+			TerraformExecutor:     terraformClient,
+			DefaultTFDistribution: defaultTfDistribution,
+			DefaultTFVersion:      defaultTfVersion,
+		},
+		FmtCheckStepRunner: &runtime.FmtCheckStepRunner{
+			TerraformExecutor:     terraformClient,
+			DefaultTFDistribution: defaultTfDistribution,
+			DefaultTFVersion:      defaultTfVersion,
+		},
 		ImportStepRunner:          runtime.NewImportStepRunner(terraformClient, defaultTfDistribution, defaultTfVersion),
 		StateRmStepRunner:         runtime.NewStateRmStepRunner(terraformClient, defaultTfDistribution, defaultTfVersion),
+		DriftStepRunner:           runtime.NewDriftStepRunner(terraformClient, defaultTfDistribution, defaultTfVersion),
 		WorkingDir:                workingDir,
 		Webhooks:                  webhooksManager,
 		WorkingDirLocker:          workingDirLocker,
 		CommandRequirementHandler: applyRequirementHandler,
+		NoOpApply:                 userConfig.NoOpApply,
+		ShowPlanStepRunner:        runtime.NewShowPlanStepRunner(terraformClient, defaultTfDistribution, defaultTfVersion),
 	}
 
 	dbUpdater := &events.DBUpdater{
@@ -741,9 +880,14 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 	}
 
 	pullUpdater := &events.PullUpdater{
-		HidePrevPlanComments: userConfig.HidePrevPlanComments,
-		VCSClient:            vcsClient,
-		MarkdownRenderer:     markdownRenderer,
+		HidePrevPlanComments:     userConfig.HidePrevPlanComments,
+		PlanReviewComments:       userConfig.PlanReviewComments,
+		VCSClient:                vcsClient,
+		MarkdownRenderer:         markdownRenderer,
+		PullDescriptionPlanLinks: userConfig.PullDescriptionPlanLinks,
+		PlanSummaryComment:       userConfig.PlanSummaryComment,
+		JobURLGenerator:          router,
+		Notifier:                 &webhooks.MultiCommandNotifier{Notifiers: commandNotifiers},
 	}
 
 	autoMerger := &events.AutoMerger{
@@ -755,6 +899,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		JobMessageSender:     projectCmdOutputHandler,
 		ProjectCommandRunner: projectCommandRunner,
 		JobURLSetter:         jobs.NewJobURLSetter(router, commitStatusUpdater),
+		JobURLGenerator:      router,
 	}
 	instrumentedProjectCmdRunner := events.NewInstrumentedProjectCommandRunner(
 		statsScope,
@@ -792,6 +937,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		userConfig.DiscardApprovalOnPlanFlag,
 		pullReqStatusFetcher,
 		userConfig.PendingApplyStatus,
+		userConfig.ParallelPlanLimit,
 	)
 
 	applyCommandRunner := events.NewApplyCommandRunner(
@@ -809,6 +955,8 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		userConfig.SilenceNoProjects,
 		userConfig.SilenceVCSStatusNoProjects,
 		pullReqStatusFetcher,
+		userConfig.AsyncApply,
+		drainer,
 	)
 
 	approvePoliciesCommandRunner := events.NewApprovePoliciesCommandRunner(
@@ -851,6 +999,20 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		instrumentedProjectCmdRunner,
 	)
 
+	driftCommandRunner := events.NewDriftCommandRunner(
+		pullUpdater,
+		projectCommandBuilder,
+		instrumentedProjectCmdRunner,
+		userConfig.ParallelPoolSize,
+		userConfig.SilenceNoProjects,
+	)
+
+	runningCommands := &events.RunningCommands{}
+	cancelCommandRunner := events.NewCancelCommandRunner(
+		vcsClient,
+		runningCommands,
+	)
+
 	commentCommandRunnerByCmd := map[command.Name]events.CommentCommandRunner{
 		command.Plan:            planCommandRunner,
 		command.Apply:           applyCommandRunner,
@@ -859,6 +1021,8 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		command.Version:         versionCommandRunner,
 		command.Import:          importCommandRunner,
 		command.State:           stateCommandRunner,
+		command.Drift:           driftCommandRunner,
+		command.Cancel:          cancelCommandRunner,
 	}
 
 	var teamAllowlistChecker command.TeamAllowlistChecker
@@ -911,6 +1075,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		TeamAllowlistChecker:           teamAllowlistChecker,
 		VarFileAllowlistChecker:        varFileAllowlistChecker,
 		CommitStatusUpdater:            commitStatusUpdater,
+		RunningCommands:                runningCommands,
 	}
 	repoAllowlist, err := events.NewRepoAllowlistChecker(userConfig.RepoAllowlist)
 	if err != nil {
@@ -957,6 +1122,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		ProjectCommandBuilder:          projectCommandBuilder,
 		ProjectPlanCommandRunner:       instrumentedProjectCmdRunner,
 		ProjectApplyCommandRunner:      instrumentedProjectCmdRunner,
+		ProjectDriftCommandRunner:      instrumentedProjectCmdRunner,
 		FailOnPreWorkflowHookError:     userConfig.FailOnPreWorkflowHookError,
 		PreWorkflowHooksCommandRunner:  preWorkflowHooksCommandRunner,
 		PostWorkflowHooksCommandRunner: postWorkflowHooksCommandRunner,
@@ -967,6 +1133,8 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		WorkingDirLocker:               workingDirLocker,
 		CommitStatusUpdater:            commitStatusUpdater,
 		SilenceVCSStatusNoProjects:     userConfig.SilenceVCSStatusNoProjects,
+		Approvals:                      approvals,
+		Database:                       database,
 	}
 
 	eventsController := &events_controllers.VCSEventsController{
@@ -987,6 +1155,7 @@ func NewServer(userConfig UserConfig, config Config) (*Server, error) {
 		ExecutableName:                  userConfig.ExecutableName,
 		SupportedVCSHosts:               supportedVCSHosts,
 		VCSClient:                       vcsClient,
+		CommitStatusUpdater:             commitStatusUpdater,
 		BitbucketWebhookSecret:          []byte(userConfig.BitbucketWebhookSecret),
 		AzureDevopsWebhookBasicUser:     []byte(userConfig.AzureDevopsWebhookUser),
 		AzureDevopsWebhookBasicPassword: []byte(userConfig.AzureDevopsWebhookPassword),
@@ -1059,7 +1228,9 @@ func (s *Server) Start() error {
 	s.Router.HandleFunc("/events", s.VCSEventsController.Post).Methods("POST")
 	s.Router.HandleFunc("/api/plan", s.APIController.Plan).Methods("POST")
 	s.Router.HandleFunc("/api/apply", s.APIController.Apply).Methods("POST")
+	s.Router.HandleFunc("/api/drift", s.APIController.Drift).Methods("POST")
 	s.Router.HandleFunc("/api/locks", s.APIController.ListLocks).Methods("GET")
+	s.Router.HandleFunc("/api/approvals", s.APIController.Approve).Methods("POST")
 	s.Router.HandleFunc("/github-app/exchange-code", s.GithubAppController.ExchangeCode).Methods("GET")
 	s.Router.HandleFunc("/github-app/setup", s.GithubAppController.New).Methods("GET")
 	s.Router.HandleFunc("/locks", s.LocksController.DeleteLock).Methods("DELETE").Queries("id", "{id:.*}")
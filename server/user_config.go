@@ -6,9 +6,11 @@ package server
 import (
 	"encoding/json"
 	"strings"
+	"time"
 
 	"github.com/pkg/errors"
 
+	cfg "github.com/runatlantis/atlantis/server/core/config"
 	"github.com/runatlantis/atlantis/server/events/command"
 	"github.com/runatlantis/atlantis/server/logging"
 )
@@ -19,6 +21,7 @@ import (
 type UserConfig struct {
 	AllowForkPRs                bool   `mapstructure:"allow-fork-prs"`
 	AllowCommands               string `mapstructure:"allow-commands"`
+	AsyncApply                  bool   `mapstructure:"async-apply"`
 	AtlantisURL                 string `mapstructure:"atlantis-url"`
 	AutoDiscoverModeFlag        string `mapstructure:"autodiscover-mode"`
 	Automerge                   bool   `mapstructure:"automerge"`
@@ -30,8 +33,11 @@ type UserConfig struct {
 	AzureDevopsWebhookPassword  string `mapstructure:"azuredevops-webhook-password"`
 	AzureDevopsWebhookUser      string `mapstructure:"azuredevops-webhook-user"`
 	AzureDevOpsHostname         string `mapstructure:"azuredevops-hostname"`
+	AzureDevopsThreadComments   bool   `mapstructure:"azuredevops-thread-comments"`
 	BitbucketApiUser            string `mapstructure:"bitbucket-api-user"`
 	BitbucketBaseURL            string `mapstructure:"bitbucket-base-url"`
+	BitbucketDraftDetection     string `mapstructure:"bitbucket-draft-detection"`
+	BitbucketDraftTitlePrefixes string `mapstructure:"bitbucket-draft-title-prefixes"`
 	BitbucketToken              string `mapstructure:"bitbucket-token"`
 	BitbucketUser               string `mapstructure:"bitbucket-user"`
 	BitbucketWebhookSecret      string `mapstructure:"bitbucket-webhook-secret"`
@@ -42,6 +48,8 @@ type UserConfig struct {
 	DisableAutoplan             bool   `mapstructure:"disable-autoplan"`
 	DisableAutoplanLabel        string `mapstructure:"disable-autoplan-label"`
 	DisableMarkdownFolding      bool   `mapstructure:"disable-markdown-folding"`
+	DisablePolicyCheckCache     bool   `mapstructure:"disable-policy-check-cache"`
+	DisableProjectStatuses      bool   `mapstructure:"disable-project-statuses"`
 	DisableRepoLocking          bool   `mapstructure:"disable-repo-locking"`
 	DisableGlobalApplyLock      bool   `mapstructure:"disable-global-apply-lock"`
 	DisableUnlockLabel          string `mapstructure:"disable-unlock-label"`
@@ -56,6 +64,7 @@ type UserConfig struct {
 	FailOnPreWorkflowHookError      bool   `mapstructure:"fail-on-pre-workflow-hook-error"`
 	HideUnchangedPlanComments       bool   `mapstructure:"hide-unchanged-plan-comments"`
 	GithubAllowMergeableBypassApply bool   `mapstructure:"gh-allow-mergeable-bypass-apply"`
+	GithubCheckRuns                 bool   `mapstructure:"gh-check-runs"`
 	GithubHostname                  string `mapstructure:"gh-hostname"`
 	GithubToken                     string `mapstructure:"gh-token"`
 	GithubTokenFile                 string `mapstructure:"gh-token-file"`
@@ -66,7 +75,9 @@ type UserConfig struct {
 	GithubAppKey                    string `mapstructure:"gh-app-key"`
 	GithubAppKeyFile                string `mapstructure:"gh-app-key-file"`
 	GithubAppSlug                   string `mapstructure:"gh-app-slug"`
+	GithubAppTokenRefreshLeadTime   string `mapstructure:"gh-app-token-refresh-lead-time"`
 	GithubAppInstallationID         int64  `mapstructure:"gh-app-installation-id"`
+	GithubRateLimitMaxWait          string `mapstructure:"gh-rate-limit-max-wait"`
 	GithubTeamAllowlist             string `mapstructure:"gh-team-allowlist"`
 	GiteaBaseURL                    string `mapstructure:"gitea-base-url"`
 	GiteaToken                      string `mapstructure:"gitea-token"`
@@ -79,18 +90,30 @@ type UserConfig struct {
 	GitlabUser                      string `mapstructure:"gitlab-user"`
 	GitlabWebhookSecret             string `mapstructure:"gitlab-webhook-secret"`
 	GitlabStatusRetryEnabled        bool   `mapstructure:"gitlab-status-retry-enabled"`
+	GitlabExternalStatusChecks      bool   `mapstructure:"gitlab-external-status-checks"`
 	IncludeGitUntrackedFiles        bool   `mapstructure:"include-git-untracked-files"`
 	APISecret                       string `mapstructure:"api-secret"`
 	HidePrevPlanComments            bool   `mapstructure:"hide-prev-plan-comments"`
+	LockGranularity                 string `mapstructure:"lock-granularity"`
 	LockingDBType                   string `mapstructure:"locking-db-type"`
+	LockTTL                         string `mapstructure:"lock-ttl"`
+	LogFormat                       string `mapstructure:"log-format"`
 	LogLevel                        string `mapstructure:"log-level"`
 	MarkdownTemplateOverridesDir    string `mapstructure:"markdown-template-overrides-dir"`
+	MaxCommentOutputSize            int    `mapstructure:"max-comment-output-size"`
 	MaxCommentsPerCommand           int    `mapstructure:"max-comments-per-command"`
+	NoOpApply                       string `mapstructure:"no-op-apply"`
 	IgnoreVCSStatusNames            string `mapstructure:"ignore-vcs-status-names"`
 	ParallelPoolSize                int    `mapstructure:"parallel-pool-size"`
+	ParallelPlanLimit               int    `mapstructure:"parallel-plan-limit"`
 	ParallelPlan                    bool   `mapstructure:"parallel-plan"`
 	ParallelApply                   bool   `mapstructure:"parallel-apply"`
 	PendingApplyStatus              bool   `mapstructure:"pending-apply-status"`
+	PlanJSONStats                   bool   `mapstructure:"plan-json-stats"`
+	PlanReviewComments              bool   `mapstructure:"plan-review-comments"`
+	PlanSummaryComment              bool   `mapstructure:"plan-summary-comment"`
+	ProjectOrder                    string `mapstructure:"project-order"`
+	PullDescriptionPlanLinks        bool   `mapstructure:"pull-description-plan-links"`
 	StatsNamespace                  string `mapstructure:"stats-namespace"`
 	PlanDrafts                      bool   `mapstructure:"allow-draft-prs"`
 	Port                            int    `mapstructure:"port"`
@@ -103,6 +126,7 @@ type UserConfig struct {
 	RedisInsecureSkipVerify         bool   `mapstructure:"redis-insecure-skip-verify"`
 	RepoConfig                      string `mapstructure:"repo-config"`
 	RepoConfigJSON                  string `mapstructure:"repo-config-json"`
+	RepoConfigEnvAllowlist          string `mapstructure:"repo-config-env-allowlist"`
 	RepoAllowlist                   string `mapstructure:"repo-allowlist"`
 
 	// SilenceNoProjects is whether Atlantis should respond to a PR if no projects are found.
@@ -116,10 +140,13 @@ type UserConfig struct {
 	SilenceVCSStatusNoProjects bool            `mapstructure:"silence-vcs-status-no-projects"`
 	SilenceAllowlistErrors     bool            `mapstructure:"silence-allowlist-errors"`
 	SkipCloneNoChanges         bool            `mapstructure:"skip-clone-no-changes"`
+	SplitLargeComments         bool            `mapstructure:"split-large-comments"`
 	SlackToken                 string          `mapstructure:"slack-token"`
+	SlackWebhookURL            string          `mapstructure:"slack-webhook-url"`
 	SSLCertFile                string          `mapstructure:"ssl-cert-file"`
 	SSLKeyFile                 string          `mapstructure:"ssl-key-file"`
 	RestrictFileList           bool            `mapstructure:"restrict-file-list"`
+	TeamsWebhookURL            string          `mapstructure:"teams-webhook-url"`
 	TFDistribution             string          `mapstructure:"tf-distribution"` // deprecated in favor of DefaultTFDistribution
 	TFDownload                 bool            `mapstructure:"tf-download"`
 	TFDownloadURL              string          `mapstructure:"tf-download-url"`
@@ -130,6 +157,7 @@ type UserConfig struct {
 	VCSStatusName              string          `mapstructure:"vcs-status-name"`
 	DefaultTFDistribution      string          `mapstructure:"default-tf-distribution"`
 	DefaultTFVersion           string          `mapstructure:"default-tf-version"`
+	DefaultTFVersionConstraint string          `mapstructure:"default-tf-version-constraint"`
 	Webhooks                   []WebhookConfig `mapstructure:"webhooks" flag:"false"`
 	WebhookHttpHeaders         string          `mapstructure:"webhook-http-headers"`
 	WebBasicAuth               bool            `mapstructure:"web-basic-auth"`
@@ -195,6 +223,67 @@ func (u UserConfig) ToWebhookHttpHeaders() (map[string][]string, error) {
 	return headers, nil
 }
 
+// githubAppTokenValidity is how long GitHub App installation tokens are
+// valid for.
+const githubAppTokenValidity = time.Hour
+
+// ToGithubAppTokenRefreshLeadTime parses GithubAppTokenRefreshLeadTime. A
+// lead time of 0 means the default lead time is used.
+func (u UserConfig) ToGithubAppTokenRefreshLeadTime() (time.Duration, error) {
+	leadTime, err := parseNonNegativeDuration(u.GithubAppTokenRefreshLeadTime)
+	if err != nil {
+		return 0, err
+	}
+	if leadTime >= githubAppTokenValidity {
+		return 0, errors.Errorf("must be less than %s", githubAppTokenValidity)
+	}
+	return leadTime, nil
+}
+
+// ToGithubRateLimitMaxWait parses GithubRateLimitMaxWait. A max wait of 0
+// means rate limited requests aren't retried.
+func (u UserConfig) ToGithubRateLimitMaxWait() (time.Duration, error) {
+	return parseNonNegativeDuration(u.GithubRateLimitMaxWait)
+}
+
+// ToRepoConfigEnvAllowlist parses RepoConfigEnvAllowlist into the names of
+// the environment variables that repo configs can reference.
+func (u UserConfig) ToRepoConfigEnvAllowlist() ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(u.RepoConfigEnvAllowlist, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !cfg.ValidEnvVarName(name) {
+			return nil, errors.Errorf("%q is not a valid environment variable name", name)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+// ToLockTTL parses LockTTL. A TTL of 0 means locks never expire.
+func (u UserConfig) ToLockTTL() (time.Duration, error) {
+	return parseNonNegativeDuration(u.LockTTL)
+}
+
+// parseNonNegativeDuration parses the duration flag value s. An empty value
+// is 0.
+func parseNonNegativeDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, errors.New("must not be negative")
+	}
+	return d, nil
+}
+
 // ToLogLevel returns the LogLevel object corresponding to the user-passed
 // log level.
 func (u UserConfig) ToLogLevel() logging.LogLevel {
@@ -5,6 +5,7 @@ package server_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 
@@ -74,6 +75,32 @@ func TestUserConfig_ToAllowCommandNames(t *testing.T) {
 	}
 }
 
+func TestUserConfig_ToLockTTL(t *testing.T) {
+	cases := []struct {
+		lockTTL string
+		exp     time.Duration
+		expErr  string
+	}{
+		{lockTTL: "", exp: 0},
+		{lockTTL: "0", exp: 0},
+		{lockTTL: "24h", exp: 24 * time.Hour},
+		{lockTTL: "-1h", expErr: "must not be negative"},
+		{lockTTL: "1day", expErr: `time: unknown unit "day" in duration "1day"`},
+	}
+	for _, c := range cases {
+		t.Run(c.lockTTL, func(t *testing.T) {
+			u := server.UserConfig{LockTTL: c.lockTTL}
+			got, err := u.ToLockTTL()
+			if c.expErr != "" {
+				ErrEquals(t, c.expErr, err)
+				return
+			}
+			Ok(t, err)
+			Equals(t, c.exp, got)
+		})
+	}
+}
+
 func TestUserConfig_ToWebhookHttpHeaders(t *testing.T) {
 	tcs := []struct {
 		name  string